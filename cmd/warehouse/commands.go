@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stpnv0/WarehouseControl/internal/app"
+	"github.com/stpnv0/WarehouseControl/internal/config"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// Коды выхода служебных команд
+const (
+	exitOK     = 0
+	exitFailed = 1 // проверка прошла, результат отрицательный
+	exitError  = 2 // проверку не удалось выполнить
+)
+
+// runCommand выполняет служебную команду вместо запуска сервера и возвращает код выхода
+func runCommand(args []string, cfg *config.Config, log logger.Logger) int {
+	switch args[0] {
+	case "audit-verify":
+		return auditVerify(args[1:], cfg, log)
+	case "mock-oidc":
+		return mockOIDC(cfg, log)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (available: audit-verify, mock-oidc)\n", args[0])
+		return exitError
+	}
+}
+
+// auditVerify - `warehouse audit-verify [-anchor <id>:<hash>]`: проверка цепочки хешей audit_log.
+// Печатает звено для следующей проверки: его нужно сохранить вне БД.
+func auditVerify(args []string, cfg *config.Config, log logger.Logger) int {
+	fs := flag.NewFlagSet("audit-verify", flag.ContinueOnError)
+	anchorFlag := fs.String("anchor", "", "chain link <id>:<hash> printed by a previous run")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	var anchor *domain.AuditChainAnchor
+	if *anchorFlag != "" {
+		a, err := domain.ParseAuditChainAnchor(*anchorFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "audit-verify: invalid -anchor: %v\n", err)
+			return exitError
+		}
+		anchor = a
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := app.VerifyAudit(ctx, cfg, log, anchor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit-verify: %v\n", err)
+		return exitError
+	}
+
+	if report.Valid {
+		fmt.Printf("audit chain OK: %d entries verified, last id %d\n", report.Checked, report.LastID)
+		if report.Archived > 0 {
+			fmt.Printf("  %d archived entries bridged by stored segment hashes\n", report.Archived)
+		}
+		switch {
+		case report.AnchorVerified:
+			fmt.Printf("  anchor %d matched\n", anchor.EntryID)
+		case anchor != nil:
+			fmt.Printf("  anchor %d is inside an archived segment, check it against the archive file\n", anchor.EntryID)
+		}
+		head := domain.AuditChainAnchor{EntryID: report.LastID, Hash: report.LastHash}
+		if head.EntryID > 0 {
+			fmt.Printf("anchor for the next run (store it outside the database): %s\n", head)
+		}
+		return exitOK
+	}
+
+	b := report.Broken
+	fmt.Printf("audit chain BROKEN at entry %d: %s\n", b.EntryID, b.Reason)
+	fmt.Printf("  expected: %s\n", b.Expected)
+	fmt.Printf("  actual:   %s\n", b.Actual)
+	fmt.Printf("entries verified before the break: %d (last good id %d)\n", report.Checked, report.LastID)
+	return exitFailed
+}
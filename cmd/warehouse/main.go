@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/stpnv0/WarehouseControl/internal/app"
 	"github.com/stpnv0/WarehouseControl/internal/config"
@@ -22,6 +23,11 @@ func main() {
 		log.Fatalf("init logger: %v", err)
 	}
 
+	// `warehouse <command>` - служебная команда вместо запуска сервера
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:], cfg, logger))
+	}
+
 	application, err := app.New(cfg, logger)
 	if err != nil {
 		log.Fatalf("init app: %v", err)
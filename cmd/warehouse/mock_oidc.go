@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/config"
+	"github.com/stpnv0/WarehouseControl/internal/oidc/oidctest"
+	"github.com/wb-go/wbf/logger"
+)
+
+const defaultMockIssuer = "http://localhost:9090"
+
+// mockOIDCUsers - пользователи локального провайдера; группы совпадают с role_mapping из config.yaml.example
+var mockOIDCUsers = []oidctest.User{
+	{Subject: "mock-alice", Username: "alice", Email: "alice@example.com", Name: "Alice", Groups: []string{"wh-admins"}},
+	{Subject: "mock-bob", Username: "bob", Email: "bob@example.com", Name: "Bob", Groups: []string{"wh-managers"}},
+	{Subject: "mock-carol", Username: "carol", Email: "carol@example.com", Name: "Carol", Groups: []string{"wh-viewers"}},
+	{Subject: "mock-dave", Username: "dave", Email: "dave@example.com", Name: "Dave"},
+}
+
+// mockOIDC - `warehouse mock-oidc`: провайдер OpenID Connect для локальной разработки.
+// Клиент и адрес берутся из auth.oidc (пустой issuer - http://localhost:9090); пароли не спрашиваются.
+func mockOIDC(cfg *config.Config, log logger.Logger) int {
+	oidcCfg := cfg.Auth.OIDC
+	issuer := oidcCfg.Issuer
+	if issuer == "" {
+		issuer = defaultMockIssuer
+	}
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		fmt.Fprintf(os.Stderr, "mock-oidc: invalid issuer %q\n", issuer)
+		return exitError
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+	}
+
+	clientID := oidcCfg.ClientID
+	if clientID == "" {
+		clientID = "warehouse"
+	}
+	provider, err := oidctest.NewProvider(oidctest.Options{
+		Issuer:       issuer,
+		ClientID:     clientID,
+		ClientSecret: oidcCfg.ClientSecret,
+		RedirectURL:  oidcCfg.RedirectURL,
+		Users:        mockOIDCUsers,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mock-oidc: %v\n", err)
+		return exitError
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              net.JoinHostPort("", port),
+		Handler:           provider,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("mock oidc provider started",
+		"issuer", issuer,
+		"client_id", clientID,
+		"addr", srv.Addr,
+	)
+	if err = srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "mock-oidc: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
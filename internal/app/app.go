@@ -6,19 +6,24 @@ import (
 	"fmt"
 	"net/http"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/pressly/goose/v3"
 	"github.com/stpnv0/WarehouseControl/internal/auth"
 	"github.com/stpnv0/WarehouseControl/internal/config"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/stpnv0/WarehouseControl/internal/handler"
 	"github.com/stpnv0/WarehouseControl/internal/middleware"
+	"github.com/stpnv0/WarehouseControl/internal/publisher"
 	"github.com/stpnv0/WarehouseControl/internal/repository"
 	"github.com/stpnv0/WarehouseControl/internal/router"
 	"github.com/stpnv0/WarehouseControl/internal/service"
+	"github.com/stpnv0/WarehouseControl/internal/webhook"
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/logger"
 	"github.com/wb-go/wbf/retry"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const migrationsDir = "migrations"
@@ -28,6 +33,19 @@ type App struct {
 	log        logger.Logger
 	db         *dbpg.DB
 	httpServer *http.Server
+	exportJobs *service.ExportJobService
+	auditArch  *service.AuditArchiveService
+	events     *service.EventService
+	presence   *service.PresenceService
+	outbox     *service.OutboxRelay
+	publisher  eventPublisher
+	webhooks   *service.WebhookService
+	denylist   *auth.Denylist
+	keyring    *auth.Keyring // nil при подписи HS256
+
+	// фоновые задачи останавливаются после HTTP-сервера, но до закрытия БД
+	bgCancel context.CancelFunc
+	bg       sync.WaitGroup
 }
 
 func New(cfg *config.Config, log logger.Logger) (*App, error) {
@@ -75,35 +93,150 @@ func (a *App) initDB() error {
 	return nil
 }
 
-func (a *App) initServices() error {
-	strategy := retry.Strategy{
+func (a *App) retryStrategy() retry.Strategy {
+	return retry.Strategy{
 		Attempts: a.cfg.Retry.Attempts,
 		Delay:    a.cfg.Retry.Delay,
 		Backoff:  a.cfg.Retry.Backoff,
 	}
+}
 
-	tokenManager := auth.NewManager(a.cfg.Auth.JWTSecret, a.cfg.Auth.TokenTTL)
+func (a *App) initServices() error {
+	strategy := a.retryStrategy()
+
+	tokenRepo := repository.NewTokenRepository(a.db, strategy)
+	a.denylist = auth.NewDenylist(tokenRepo, auth.DenylistOptions{
+		SyncInterval: a.cfg.Auth.DenylistSync,
+	}, a.log)
+	// до полной загрузки отозванные токены приняли бы как действующие
+	if err := a.denylist.Sync(context.Background()); err != nil {
+		return fmt.Errorf("token denylist: %w", err)
+	}
+	signingKeys, err := a.initSigningKeys(strategy)
+	if err != nil {
+		return fmt.Errorf("signing keys: %w", err)
+	}
+	tokenManager := auth.NewManager(signingKeys, a.cfg.Auth.TokenTTL, a.denylist)
 
 	auditRepo := repository.NewAuditRepository(a.db, strategy)
-	userRepo := repository.NewUserRepository(a.db, strategy)
-	itemRepo := repository.NewItemRepository(a.db, strategy)
+	auditRecorder, err := repository.NewAuditRecorder(domain.AuditMode(a.cfg.Audit.Mode))
+	if err != nil {
+		return fmt.Errorf("audit recorder: %w", err)
+	}
+	userRepo := repository.NewUserRepository(a.db, strategy, auditRecorder)
+
+	itemRepo := repository.NewItemRepository(a.db, strategy, auditRecorder, a.cfg.Outbox.LowStockThreshold)
+	exportJobRepo := repository.NewExportJobRepository(a.db, strategy)
+	auditArchiveRepo := repository.NewAuditArchiveRepository(a.db, strategy)
 
 	auditService := service.NewAuditService(auditRepo, a.log)
-	authService := service.NewAuthService(userRepo, tokenManager, a.log)
+	if cost := a.cfg.Auth.BcryptCost; cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return fmt.Errorf("auth.bcrypt_cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, cost)
+	}
+	authService := service.NewAuthService(userRepo, tokenRepo, tokenManager, a.denylist, service.AuthOptions{
+		BcryptCost: a.cfg.Auth.BcryptCost,
+		RefreshTTL: a.cfg.Auth.RefreshTTL,
+	}, a.log)
+	oidcService, err := a.initOIDC(strategy, auditRecorder, userRepo, authService)
+	if err != nil {
+		return fmt.Errorf("oidc: %w", err)
+	}
+	userService := service.NewUserService(userRepo, authService, a.cfg.Auth.BcryptCost, a.log)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(a.db, strategy), userRepo, a.log)
 	itemService := service.NewItemService(itemRepo, a.log)
+	a.exportJobs = service.NewExportJobService(exportJobRepo, itemRepo, auditRepo, service.ExportJobOptions{
+		Dir:             a.cfg.Exports.Dir,
+		Workers:         a.cfg.Exports.Workers,
+		TTL:             a.cfg.Exports.TTL,
+		PollInterval:    a.cfg.Exports.PollInterval,
+		CleanupInterval: a.cfg.Exports.CleanupInterval,
+	}, a.log)
+	a.auditArch = service.NewAuditArchiveService(auditArchiveRepo, service.AuditArchiveOptions{
+		Dir:             a.cfg.Audit.Retention.ArchiveDir,
+		RetentionMonths: a.cfg.Audit.Retention.Months,
+		Interval:        a.cfg.Audit.Retention.Interval,
+		ReattachHold:    a.cfg.Audit.Retention.ReattachHold,
+	}, a.log)
+	a.events = service.NewEventService(auditRepo, repository.NewAuditListener(a.cfg.Postgres.DSN(), a.log),
+		service.EventOptions{
+			Buffer:       a.cfg.Events.Buffer,
+			ReplayLimit:  a.cfg.Events.ReplayLimit,
+			PollInterval: a.cfg.Events.PollInterval,
+		}, a.log)
+	allowedNetworks, err := webhook.ParseAllowedNetworks(a.cfg.Webhooks.AllowedNetworks)
+	if err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+	a.webhooks = service.NewWebhookService(repository.NewWebhookRepository(a.db, strategy),
+		webhook.NewSender(a.cfg.Webhooks.Timeout, allowedNetworks),
+		service.WebhookOptions{
+			Workers:         a.cfg.Webhooks.Workers,
+			BatchSize:       a.cfg.Webhooks.BatchSize,
+			PollInterval:    a.cfg.Webhooks.PollInterval,
+			Lease:           a.cfg.Webhooks.Lease,
+			MaxAttempts:     a.cfg.Webhooks.MaxAttempts,
+			RetryDelay:      a.cfg.Webhooks.RetryDelay,
+			MaxBackoff:      a.cfg.Webhooks.MaxBackoff,
+			Retention:       a.cfg.Webhooks.Retention,
+			CleanupInterval: a.cfg.Webhooks.CleanupInterval,
+		}, a.log)
+	external, err := newEventPublisher(a.cfg.Outbox, a.log)
+	if err != nil {
+		return fmt.Errorf("outbox publisher: %w", err)
+	}
+	// раскладка по подпискам webhook - запись в ту же БД, поэтому идёт раньше внешнего получателя
+	a.publisher = publisher.NewMulti(a.webhooks, external)
+	a.outbox = service.NewOutboxRelay(repository.NewOutboxRepository(a.db, strategy), a.publisher,
+		service.OutboxOptions{
+			BatchSize:       a.cfg.Outbox.BatchSize,
+			PollInterval:    a.cfg.Outbox.PollInterval,
+			Lease:           a.cfg.Outbox.Lease,
+			MaxBackoff:      a.cfg.Outbox.MaxBackoff,
+			Retention:       a.cfg.Outbox.Retention,
+			CleanupInterval: a.cfg.Outbox.CleanupInterval,
+			Retry: retry.Strategy{
+				Attempts: a.cfg.Outbox.RetryAttempts,
+				Delay:    a.cfg.Outbox.RetryDelay,
+				Backoff:  a.cfg.Outbox.RetryBackoff,
+			},
+		}, a.log)
+	a.presence = service.NewPresenceService(a.events, service.PresenceOptions{
+		Buffer:   a.cfg.Presence.Buffer,
+		MaxItems: a.cfg.Presence.MaxItems,
+	}, a.log)
 
 	auditHandler := handler.NewAuditHandler(auditService, a.log)
 	authHandler := handler.NewAuthHandler(authService, a.log)
+	oidcHandler := handler.NewOIDCHandler(oidcService, a.cfg.Auth.OIDC.StateTTL, a.log)
 	itemHandler := handler.NewItemHandler(itemService, a.log)
+	exportJobHandler := handler.NewExportJobHandler(a.exportJobs, a.log)
+	auditArchiveHandler := handler.NewAuditArchiveHandler(a.auditArch, a.log)
+	eventHandler := handler.NewEventHandler(a.events, a.denylist, a.cfg.Events.Heartbeat, a.log)
+	presenceHandler := handler.NewPresenceHandler(a.presence, a.denylist, a.cfg.Presence.Heartbeat, a.log)
+	webhookHandler := handler.NewWebhookHandler(a.webhooks, a.log)
+	userHandler := handler.NewUserHandler(userService, a.log)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, a.log)
+	jwksHandler := handler.NewJWKSHandler(signingKeys)
 
 	r := router.InitRouter(
 		a.cfg.Gin.Mode,
 		authHandler,
+		oidcHandler,
 		auditHandler,
 		itemHandler,
+		exportJobHandler,
+		auditArchiveHandler,
+		eventHandler,
+		presenceHandler,
+		webhookHandler,
+		userHandler,
+		apiKeyHandler,
+		jwksHandler,
 		tokenManager,
+		apiKeyService,
 		middleware.CORS(),
 		middleware.RequestID(),
+		middleware.AuditRequest(),
 		middleware.RequestLogger(a.log),
 	)
 
@@ -114,6 +247,9 @@ func (a *App) initServices() error {
 		WriteTimeout: a.cfg.Server.WriteTimeout,
 		IdleTimeout:  a.cfg.Server.IdleTimeout,
 	}
+	// Shutdown ждёт завершения запросов, а потоки /api/events и /api/ws/presence сами не заканчиваются
+	a.httpServer.RegisterOnShutdown(a.events.Close)
+	a.httpServer.RegisterOnShutdown(a.presence.Close)
 
 	return nil
 }
@@ -122,6 +258,8 @@ func (a *App) Run() error {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	a.startBackground()
+
 	errCh := make(chan error, 1)
 	go func() {
 		a.log.LogAttrs(ctx, logger.InfoLevel, "HTTP server starting",
@@ -136,6 +274,7 @@ func (a *App) Run() error {
 	case <-ctx.Done():
 		a.log.LogAttrs(context.Background(), logger.InfoLevel, "shutdown signal received")
 	case err := <-errCh:
+		a.stopBackground()
 		return err
 	}
 
@@ -156,6 +295,15 @@ func (a *App) shutdown() error {
 	}
 	a.log.LogAttrs(context.Background(), logger.InfoLevel, "HTTP server stopped")
 
+	a.stopBackground()
+	a.log.LogAttrs(context.Background(), logger.InfoLevel, "background workers stopped")
+
+	if err := a.publisher.Close(); err != nil {
+		a.log.LogAttrs(context.Background(), logger.ErrorLevel, "failed to close outbox publisher",
+			logger.String("error", err.Error()),
+		)
+	}
+
 	if err := a.db.Master.Close(); err != nil {
 		return fmt.Errorf("close db: %w", err)
 	}
@@ -166,6 +314,90 @@ func (a *App) shutdown() error {
 	return nil
 }
 
+func (a *App) startBackground() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.bgCancel = cancel
+
+	a.bg.Add(1)
+	go func() {
+		defer a.bg.Done()
+		if err := a.exportJobs.Run(ctx); err != nil {
+			a.log.LogAttrs(ctx, logger.ErrorLevel, "export workers stopped",
+				logger.String("error", err.Error()),
+			)
+		}
+	}()
+
+	a.bg.Add(1)
+	go func() {
+		defer a.bg.Done()
+		if err := a.auditArch.Run(ctx); err != nil {
+			a.log.LogAttrs(ctx, logger.ErrorLevel, "audit archiver stopped",
+				logger.String("error", err.Error()),
+			)
+		}
+	}()
+
+	a.bg.Add(1)
+	go func() {
+		defer a.bg.Done()
+		if err := a.events.Run(ctx); err != nil {
+			a.log.LogAttrs(ctx, logger.ErrorLevel, "event feed stopped",
+				logger.String("error", err.Error()),
+			)
+		}
+	}()
+
+	a.bg.Add(1)
+	go func() {
+		defer a.bg.Done()
+		if err := a.outbox.Run(ctx); err != nil {
+			a.log.LogAttrs(ctx, logger.ErrorLevel, "outbox relay stopped",
+				logger.String("error", err.Error()),
+			)
+		}
+	}()
+
+	a.bg.Add(1)
+	go func() {
+		defer a.bg.Done()
+		if err := a.webhooks.Run(ctx); err != nil {
+			a.log.LogAttrs(ctx, logger.ErrorLevel, "webhook delivery stopped",
+				logger.String("error", err.Error()),
+			)
+		}
+	}()
+
+	a.bg.Add(1)
+	go func() {
+		defer a.bg.Done()
+		if err := a.denylist.Run(ctx); err != nil {
+			a.log.LogAttrs(ctx, logger.ErrorLevel, "token denylist sync stopped",
+				logger.String("error", err.Error()),
+			)
+		}
+	}()
+
+	if a.keyring != nil {
+		a.bg.Add(1)
+		go func() {
+			defer a.bg.Done()
+			if err := a.keyring.Run(ctx); err != nil {
+				a.log.LogAttrs(ctx, logger.ErrorLevel, "signing key rotation stopped",
+					logger.String("error", err.Error()),
+				)
+			}
+		}()
+	}
+}
+
+func (a *App) stopBackground() {
+	if a.bgCancel != nil {
+		a.bgCancel()
+	}
+	a.bg.Wait()
+}
+
 func (a *App) runMigrations() error {
 	db, err := sql.Open("postgres", a.cfg.Postgres.DSN())
 	if err != nil {
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stpnv0/WarehouseControl/internal/auditchain"
+	"github.com/stpnv0/WarehouseControl/internal/config"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/repository"
+	"github.com/wb-go/wbf/logger"
+)
+
+// VerifyAudit проверяет цепочку хешей аудита без миграций и HTTP-сервера
+// (команда `warehouse audit-verify`); anchor - звено из прошлой проверки или nil
+func VerifyAudit(
+	ctx context.Context,
+	cfg *config.Config,
+	log logger.Logger,
+	anchor *domain.AuditChainAnchor,
+) (*domain.AuditChainReport, error) {
+	app := &App{cfg: cfg, log: log}
+
+	if err := app.initDB(); err != nil {
+		return nil, fmt.Errorf("init db: %w", err)
+	}
+	defer app.db.Master.Close()
+
+	return auditchain.Verify(ctx, repository.NewAuditRepository(app.db, app.retryStrategy()), anchor)
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/oidc"
+	"github.com/stpnv0/WarehouseControl/internal/repository"
+	"github.com/stpnv0/WarehouseControl/internal/service"
+	"github.com/wb-go/wbf/retry"
+)
+
+// initOIDC - единый вход через провайдера из auth.oidc; без issuer сервис отвечает, что вход не настроен
+func (a *App) initOIDC(
+	strategy retry.Strategy,
+	recorder repository.AuditRecorder,
+	users *repository.UserRepository,
+	sessions *service.AuthService,
+) (*service.OIDCService, error) {
+	cfg := a.cfg.Auth.OIDC
+	repo := repository.NewOIDCRepository(a.db, strategy, recorder)
+	opts := service.OIDCOptions{
+		Name:           cfg.Name,
+		LinkByUsername: cfg.LinkByUsername,
+		StateTTL:       cfg.StateTTL,
+	}
+
+	if !cfg.Enabled() {
+		return service.NewOIDCService(nil, repo, users, sessions, opts, a.log), nil
+	}
+
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("auth.oidc.client_id and auth.oidc.redirect_url are required")
+	}
+	opts.RoleMapping = make(map[string]domain.Role, len(cfg.RoleMapping))
+	for group, role := range cfg.RoleMapping {
+		r, err := domain.ParseRole(role)
+		if err != nil {
+			return nil, fmt.Errorf("auth.oidc.role_mapping[%s]: %w", group, err)
+		}
+		opts.RoleMapping[group] = r
+	}
+	if cfg.DefaultRole != "" {
+		r, err := domain.ParseRole(cfg.DefaultRole)
+		if err != nil {
+			return nil, fmt.Errorf("auth.oidc.default_role: %w", err)
+		}
+		opts.DefaultRole = r
+	}
+
+	client := oidc.NewClient(oidc.Options{
+		Issuer:        cfg.Issuer,
+		ClientID:      cfg.ClientID,
+		ClientSecret:  cfg.ClientSecret,
+		RedirectURL:   cfg.RedirectURL,
+		Scopes:        cfg.Scopes,
+		UsernameClaim: cfg.UsernameClaim,
+		GroupsClaim:   cfg.GroupsClaim,
+		Timeout:       cfg.Timeout,
+	})
+	return service.NewOIDCService(client, repo, users, sessions, opts, a.log), nil
+}
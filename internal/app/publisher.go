@@ -0,0 +1,41 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stpnv0/WarehouseControl/internal/config"
+	"github.com/stpnv0/WarehouseControl/internal/publisher"
+	"github.com/stpnv0/WarehouseControl/internal/service"
+	"github.com/wb-go/wbf/logger"
+)
+
+// eventPublisher - получатель событий outbox; закрывается после остановки релея
+type eventPublisher interface {
+	service.EventPublisher
+	Close() error
+}
+
+func newEventPublisher(cfg config.OutboxConfig, log logger.Logger) (eventPublisher, error) {
+	switch cfg.Publisher {
+	case "", "log":
+		return publisher.NewLog(log), nil
+	case "file":
+		p, err := publisher.NewFile(cfg.File.Path)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case "http":
+		if cfg.HTTP.URL == "" {
+			return nil, errors.New("outbox.http.url is required")
+		}
+		return publisher.NewHTTP(cfg.HTTP.URL, cfg.HTTP.Timeout), nil
+	case "kafka":
+		if len(cfg.Kafka.Brokers) == 0 {
+			return nil, errors.New("outbox.kafka.brokers is required")
+		}
+		return publisher.NewKafka(cfg.Kafka.Brokers, cfg.Kafka.Topic), nil
+	}
+	return nil, fmt.Errorf("unknown outbox publisher: %q", cfg.Publisher)
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stpnv0/WarehouseControl/internal/auth"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/repository"
+	"github.com/wb-go/wbf/retry"
+)
+
+// signingKeys - ключи auth.Manager и их открытая часть для /.well-known/jwks.json
+type signingKeys interface {
+	auth.KeySet
+	PublicKeys() []*domain.PublicKey
+}
+
+func (a *App) initSigningKeys(strategy retry.Strategy) (signingKeys, error) {
+	cfg := a.cfg.Auth
+	switch cfg.Signing.Algorithm {
+	case "", auth.AlgHS256:
+		return auth.NewHMACKeySet(cfg.JWTSecret), nil
+	case auth.AlgRS256, auth.AlgEdDSA:
+	default:
+		return nil, fmt.Errorf("unknown auth.signing.algorithm: %q", cfg.Signing.Algorithm)
+	}
+
+	cipher, err := auth.NewKeyCipher(cfg.Signing.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("auth.signing.encryption_key: %w", err)
+	}
+
+	opts := auth.KeyringOptions{
+		Algorithm:        cfg.Signing.Algorithm,
+		RotationInterval: cfg.Signing.RotationInterval,
+		PublishAhead:     cfg.Signing.PublishAhead,
+		SyncInterval:     cfg.Signing.SyncInterval,
+		TokenTTL:         cfg.TokenTTL,
+		Cipher:           cipher,
+	}
+	// общий секрет подделывает токены - после перехода он принимается только по явному разрешению
+	if cfg.Signing.AcceptLegacy {
+		opts.LegacySecret = cfg.JWTSecret
+	}
+	keyring, err := auth.NewKeyring(repository.NewSigningKeyRepository(a.db, strategy), opts, a.log)
+	if err != nil {
+		return nil, err
+	}
+	// без загруженных ключей подписывать токены нечем
+	if err = keyring.Sync(context.Background()); err != nil {
+		return nil, err
+	}
+
+	a.keyring = keyring
+	return keyring, nil
+}
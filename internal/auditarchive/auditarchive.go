@@ -0,0 +1,161 @@
+// Package auditarchive - формат файлов с выгруженными секциями audit_log:
+// JSON Lines в gzip, строка на запись со всеми колонками, включая prev_hash и hash.
+// JSONB-поля хранятся строками в текстовом виде PostgreSQL, поэтому хеши цепочки
+// пересчитываются прямо по файлу.
+package auditarchive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Extension - расширение файла архива
+const Extension = ".jsonl.gz"
+
+type line struct {
+	ID         int64     `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	Action     string    `json:"action"`
+	ChangedBy  uuid.UUID `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+	OldData    *string   `json:"old_data"`
+	NewData    *string   `json:"new_data"`
+	Diff       *string   `json:"diff"`
+	Reason     *string   `json:"reason"`
+	Reference  *string   `json:"reference"`
+	RequestID  *string   `json:"request_id"`
+	ClientIP   *string   `json:"client_ip"`
+	UserAgent  *string   `json:"user_agent"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// Writer пишет записи в архив. Close обязателен: он дописывает gzip-поток.
+type Writer struct {
+	bw   *bufio.Writer
+	gz   *gzip.Writer
+	enc  *json.Encoder
+	rows int64
+}
+
+func NewWriter(w io.Writer) *Writer {
+	gz := gzip.NewWriter(w)
+	bw := bufio.NewWriter(gz)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	return &Writer{bw: bw, gz: gz, enc: enc}
+}
+
+func (w *Writer) Write(l *domain.AuditChainLink) error {
+	err := w.enc.Encode(line{
+		ID:         l.ID,
+		EntityType: string(l.EntityType),
+		EntityID:   l.EntityID,
+		Action:     string(l.Action),
+		ChangedBy:  l.ChangedBy,
+		ChangedAt:  l.ChangedAt,
+		OldData:    jsonbText(l.OldData),
+		NewData:    jsonbText(l.NewData),
+		Diff:       jsonbText(l.Diff),
+		Reason:     l.Reason,
+		Reference:  l.Reference,
+		RequestID:  l.RequestID,
+		ClientIP:   l.ClientIP,
+		UserAgent:  l.UserAgent,
+		PrevHash:   l.PrevHash,
+		Hash:       l.Hash,
+	})
+	if err != nil {
+		return fmt.Errorf("auditarchive: write entry %d: %w", l.ID, err)
+	}
+	w.rows++
+	return nil
+}
+
+// Rows - сколько записей записано
+func (w *Writer) Rows() int64 {
+	return w.rows
+}
+
+func (w *Writer) Close() error {
+	if err := w.bw.Flush(); err != nil {
+		return fmt.Errorf("auditarchive: flush: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("auditarchive: close gzip: %w", err)
+	}
+	return nil
+}
+
+// Reader читает записи архива по одной
+type Reader struct {
+	gz  *gzip.Reader
+	dec *json.Decoder
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("auditarchive: open gzip: %w", err)
+	}
+	return &Reader{gz: gz, dec: json.NewDecoder(gz)}, nil
+}
+
+// Next возвращает следующую запись; после последней - io.EOF
+func (r *Reader) Next() (*domain.AuditChainLink, error) {
+	var ln line
+	if err := r.dec.Decode(&ln); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("auditarchive: read entry: %w", err)
+	}
+
+	return &domain.AuditChainLink{
+		AuditEntry: domain.AuditEntry{
+			ID:         ln.ID,
+			EntityType: domain.AuditEntity(ln.EntityType),
+			EntityID:   ln.EntityID,
+			Action:     domain.AuditAction(ln.Action),
+			ChangedBy:  ln.ChangedBy,
+			ChangedAt:  ln.ChangedAt,
+			OldData:    jsonbRaw(ln.OldData),
+			NewData:    jsonbRaw(ln.NewData),
+			Diff:       jsonbRaw(ln.Diff),
+			Reason:     ln.Reason,
+			Reference:  ln.Reference,
+			RequestID:  ln.RequestID,
+			ClientIP:   ln.ClientIP,
+			UserAgent:  ln.UserAgent,
+		},
+		PrevHash: ln.PrevHash,
+		Hash:     ln.Hash,
+	}, nil
+}
+
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
+
+func jsonbText(raw json.RawMessage) *string {
+	if raw == nil {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
+
+func jsonbRaw(s *string) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
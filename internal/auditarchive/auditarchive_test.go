@@ -0,0 +1,66 @@
+package auditarchive
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/auditchain"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	reason := "инвентаризация"
+	e := domain.AuditEntry{
+		ID:         7,
+		EntityType: domain.AuditEntityItem,
+		EntityID:   uuid.New(),
+		Action:     domain.AuditUpdate,
+		ChangedBy:  uuid.New(),
+		ChangedAt:  time.Date(2025, 9, 30, 23, 59, 59, 999999000, time.UTC),
+		// текст JSONB в том виде, в каком его отдаёт PostgreSQL
+		OldData: json.RawMessage(`{"name": "Кабель <3x1.5>", "price": 100.50}`),
+		NewData: json.RawMessage(`{"name": "Кабель <3x1.5>", "price": 120.50}`),
+		Diff:    json.RawMessage(`{"price": {"new": 120.50, "old": 100.50}}`),
+		Reason:  &reason,
+	}
+	first := &domain.AuditChainLink{AuditEntry: e, PrevHash: domain.AuditChainGenesis}
+	first.Hash = auditchain.Hash(first.PrevHash, &first.AuditEntry)
+
+	e.ID, e.Action, e.OldData, e.NewData, e.Diff, e.Reason = 8, domain.AuditDelete, e.NewData, nil, nil, nil
+	second := &domain.AuditChainLink{AuditEntry: e, PrevHash: first.Hash}
+	second.Hash = auditchain.Hash(second.PrevHash, &second.AuditEntry)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.Write(first))
+	require.NoError(t, w.Write(second))
+	require.NoError(t, w.Close())
+	assert.Equal(t, int64(2), w.Rows())
+
+	r, err := NewReader(&buf)
+	require.NoError(t, err)
+	defer r.Close()
+
+	for _, want := range []*domain.AuditChainLink{first, second} {
+		got, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		// хеш сходится по данным из файла
+		assert.Equal(t, want.Hash, auditchain.Hash(got.PrevHash, &got.AuditEntry))
+	}
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNewReader_NotGzip(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte(`{"id": 1}`)))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+// Package auditchain проверяет цепочку хешей audit_log.
+// Хеши считает триггер fn_audit_chain() в БД, здесь они пересчитываются независимо.
+package auditchain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Source - записи цепочки в порядке id и голова цепочки из того же снимка БД
+type Source interface {
+	StreamChain(ctx context.Context, fn func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error)
+}
+
+// errBroken останавливает чтение на первом нарушенном звене
+var errBroken = errors.New("audit chain broken")
+
+// Hash - хеш записи аудита, тот же, что считает fn_audit_hash() в БД:
+// sha256 от полей через \n, changed_at в микросекундах Unix, пустой JSONB - пустая строка.
+// Необязательные поля (причина, ссылка, данные запроса) добавляются с префиксом и только если заданы.
+// JSONB сравнивается в текстовом виде, в котором его отдаёт PostgreSQL.
+func Hash(prevHash string, e *domain.AuditEntry) string {
+	fields := []string{
+		prevHash,
+		strconv.FormatInt(e.ID, 10),
+		string(e.EntityType),
+		e.EntityID.String(),
+		string(e.Action),
+		e.ChangedBy.String(),
+		strconv.FormatInt(e.ChangedAt.UnixMicro(), 10),
+		string(e.OldData),
+		string(e.NewData),
+		string(e.Diff),
+	}
+	if e.Reason != nil {
+		fields = append(fields, "reason:"+*e.Reason)
+	}
+	if e.Reference != nil {
+		fields = append(fields, "reference:"+*e.Reference)
+	}
+	if e.RequestID != nil {
+		fields = append(fields, "request_id:"+*e.RequestID)
+	}
+	if e.ClientIP != nil {
+		fields = append(fields, "client_ip:"+*e.ClientIP)
+	}
+	if e.UserAgent != nil {
+		fields = append(fields, "user_agent:"+*e.UserAgent)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify проходит цепочку от первой записи и возвращает отчёт с первым нарушенным звеном.
+// anchor - звено, сохранённое вне БД прошлой проверкой (nil - без него): без него не видно
+// цепочку, пересчитанную после правки, и хвост, удалённый вместе с головой.
+// Ошибка возвращается только если цепочку не удалось прочитать.
+func Verify(ctx context.Context, src Source, anchor *domain.AuditChainAnchor) (*domain.AuditChainReport, error) {
+	report := &domain.AuditChainReport{}
+	prev := domain.AuditChainGenesis
+	anchorPending := anchor != nil
+
+	head, err := src.StreamChain(ctx, func(l *domain.AuditChainLink) error {
+		if l.PrevHash != prev {
+			report.Broken = &domain.AuditChainBreak{
+				EntryID:  l.ID,
+				Reason:   domain.AuditChainPrevHashMismatch,
+				Expected: prev,
+				Actual:   l.PrevHash,
+			}
+			return errBroken
+		}
+
+		// отрезок из архива: содержимое записей проверено при выгрузке и проверяется при возврате в БД
+		if l.Archived > 0 {
+			prev = l.Hash
+			report.Archived += l.Archived
+			report.LastID = l.ID
+		} else {
+			if b := checkHash(l); b != nil {
+				report.Broken = b
+				return errBroken
+			}
+			prev = l.Hash
+			report.Checked++
+			report.LastID = l.ID
+		}
+
+		if anchorPending && l.ID >= anchor.EntryID {
+			anchorPending = false
+			if b := checkAnchor(anchor, l); b != nil {
+				report.Broken = b
+				return errBroken
+			}
+			report.AnchorVerified = l.ID == anchor.EntryID
+		}
+		return nil
+	})
+	if errors.Is(err, errBroken) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auditchain.Verify: %w", err)
+	}
+
+	if head.LastID != report.LastID || head.LastHash != prev {
+		report.Broken = &domain.AuditChainBreak{
+			EntryID:  head.LastID,
+			Reason:   domain.AuditChainHeadMismatch,
+			Expected: head.LastHash,
+			Actual:   prev,
+		}
+		return report, nil
+	}
+
+	// цепочка кончилась раньше сохранённого звена - хвост удалён вместе с головой
+	if anchorPending {
+		report.Broken = &domain.AuditChainBreak{
+			EntryID:  anchor.EntryID,
+			Reason:   domain.AuditChainAnchorMismatch,
+			Expected: anchor.Hash,
+		}
+		return report, nil
+	}
+
+	report.LastHash = prev
+	report.Valid = true
+	return report, nil
+}
+
+// checkAnchor сверяет первое звено с id не меньше anchor.EntryID с сохранённым.
+// Звено внутри отрезка из архива здесь не сверить - оно пропускается.
+func checkAnchor(anchor *domain.AuditChainAnchor, l *domain.AuditChainLink) *domain.AuditChainBreak {
+	switch {
+	case l.ID == anchor.EntryID && l.Hash == anchor.Hash:
+		return nil
+	case l.ID == anchor.EntryID:
+		return &domain.AuditChainBreak{
+			EntryID:  anchor.EntryID,
+			Reason:   domain.AuditChainAnchorMismatch,
+			Expected: anchor.Hash,
+			Actual:   l.Hash,
+		}
+	case l.Archived > 0:
+		return nil
+	default:
+		// записи с этим id в цепочке нет
+		return &domain.AuditChainBreak{
+			EntryID:  anchor.EntryID,
+			Reason:   domain.AuditChainAnchorMismatch,
+			Expected: anchor.Hash,
+		}
+	}
+}
+
+// checkHash сверяет хеш записи с её содержимым
+func checkHash(l *domain.AuditChainLink) *domain.AuditChainBreak {
+	if h := Hash(l.PrevHash, &l.AuditEntry); h != l.Hash {
+		return &domain.AuditChainBreak{
+			EntryID:  l.ID,
+			Reason:   domain.AuditChainHashMismatch,
+			Expected: h,
+			Actual:   l.Hash,
+		}
+	}
+	return nil
+}
+
+// Segments собирает записи одной секции журнала в отрезки цепочки.
+// Записи секции не обязательно идут в цепочке подряд: транзакция, начатая в конце месяца,
+// получает changed_at этого месяца, а id - после записей следующего.
+type Segments struct {
+	list []domain.AuditChainSegment
+}
+
+// Add добавляет запись (по возрастанию id) и проверяет её хеш.
+// Запись, prev_hash которой совпадает с хешем предыдущей, продолжает текущий отрезок.
+func (s *Segments) Add(l *domain.AuditChainLink) *domain.AuditChainBreak {
+	if b := checkHash(l); b != nil {
+		return b
+	}
+
+	if n := len(s.list); n > 0 && s.list[n-1].Hash == l.PrevHash {
+		last := &s.list[n-1]
+		last.LastID = l.ID
+		last.Rows++
+		last.Hash = l.Hash
+		return nil
+	}
+
+	s.list = append(s.list, domain.AuditChainSegment{
+		FirstID:  l.ID,
+		LastID:   l.ID,
+		Rows:     1,
+		PrevHash: l.PrevHash,
+		Hash:     l.Hash,
+	})
+	return nil
+}
+
+// List - собранные отрезки по возрастанию id
+func (s *Segments) List() []domain.AuditChainSegment {
+	return s.list
+}
+
+// Rows - сколько записей добавлено
+func (s *Segments) Rows() int64 {
+	var n int64
+	for _, seg := range s.list {
+		n += seg.Rows
+	}
+	return n
+}
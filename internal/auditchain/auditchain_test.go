@@ -0,0 +1,381 @@
+package auditchain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource - цепочка в памяти
+type fakeSource struct {
+	links []*domain.AuditChainLink
+	head  *domain.AuditChainHead
+	err   error
+}
+
+func (f *fakeSource) StreamChain(_ context.Context, fn func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error) {
+	for _, l := range f.links {
+		if err := fn(l); err != nil {
+			return nil, err
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.head, nil
+}
+
+// newChain строит корректную цепочку из n записей так же, как триггер fn_audit_chain()
+func newChain(n int) *fakeSource {
+	src := &fakeSource{}
+	prev := domain.AuditChainGenesis
+	changedAt := time.Date(2026, 3, 10, 9, 0, 0, 123456000, time.UTC)
+
+	for i := 0; i < n; i++ {
+		l := &domain.AuditChainLink{
+			AuditEntry: domain.AuditEntry{
+				ID:         int64(i + 1),
+				EntityType: domain.AuditEntityItem,
+				EntityID:   uuid.New(),
+				Action:     domain.AuditUpdate,
+				ChangedBy:  uuid.New(),
+				OldData:    json.RawMessage(`{"quantity": 10}`),
+				NewData:    json.RawMessage(`{"quantity": 7}`),
+				Diff:       json.RawMessage(`{"quantity": {"new": 7, "old": 10}}`),
+				ChangedAt:  changedAt.Add(time.Duration(i) * time.Second),
+			},
+			PrevHash: prev,
+		}
+		l.Hash = Hash(prev, &l.AuditEntry)
+		prev = l.Hash
+		src.links = append(src.links, l)
+	}
+
+	src.head = &domain.AuditChainHead{LastID: int64(n), LastHash: prev}
+	if n == 0 {
+		src.head.LastHash = domain.AuditChainGenesis
+	}
+	return src
+}
+
+func TestHash_Format(t *testing.T) {
+	itemID := uuid.MustParse("6f1c2a9e-0b7d-4c1e-9a53-2f0e8d4b7c11")
+	userID := uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+	e := &domain.AuditEntry{
+		ID:         42,
+		EntityType: domain.AuditEntityItem,
+		EntityID:   itemID,
+		Action:     domain.AuditInsert,
+		ChangedBy:  userID,
+		NewData:    json.RawMessage(`{"sku": "A-1"}`),
+		ChangedAt:  time.Date(2026, 3, 10, 9, 0, 0, 123456000, time.UTC),
+	}
+
+	// тот же формат собирает fn_audit_hash() в миграции
+	canonical := domain.AuditChainGenesis + "\n42\nitem\n" + itemID.String() + "\nINSERT\n" + userID.String() +
+		"\n1773133200123456\n\n" + `{"sku": "A-1"}` + "\n"
+	sum := sha256.Sum256([]byte(canonical))
+
+	assert.Equal(t, hex.EncodeToString(sum[:]), Hash(domain.AuditChainGenesis, e))
+}
+
+func TestHash_Reason(t *testing.T) {
+	e := newChain(1).links[0].AuditEntry
+	plain := Hash(domain.AuditChainGenesis, &e)
+
+	reason, reference := "пересорт", "INV-42"
+	e.Reason = &reason
+	withReason := Hash(domain.AuditChainGenesis, &e)
+	assert.NotEqual(t, plain, withReason)
+
+	// та же строка в ссылке вместо причины даёт другой хеш
+	e.Reason, e.Reference = nil, &reason
+	assert.NotEqual(t, withReason, Hash(domain.AuditChainGenesis, &e))
+
+	e.Reason, e.Reference = &reason, &reference
+	canonical := domain.AuditChainGenesis + "\n1\nitem\n" + e.EntityID.String() + "\nUPDATE\n" + e.ChangedBy.String() +
+		"\n" + fmt.Sprint(e.ChangedAt.UnixMicro()) + "\n" + string(e.OldData) + "\n" + string(e.NewData) +
+		"\n" + string(e.Diff) + "\nreason:пересорт\nreference:INV-42"
+	sum := sha256.Sum256([]byte(canonical))
+	assert.Equal(t, hex.EncodeToString(sum[:]), Hash(domain.AuditChainGenesis, &e))
+}
+
+func TestHash_RequestMeta(t *testing.T) {
+	e := newChain(1).links[0].AuditEntry
+	plain := Hash(domain.AuditChainGenesis, &e)
+
+	requestID, clientIP, userAgent := "req-42", "10.0.0.7", "curl/8.5.0"
+	e.RequestID, e.ClientIP, e.UserAgent = &requestID, &clientIP, &userAgent
+
+	canonical := domain.AuditChainGenesis + "\n1\nitem\n" + e.EntityID.String() + "\nUPDATE\n" + e.ChangedBy.String() +
+		"\n" + fmt.Sprint(e.ChangedAt.UnixMicro()) + "\n" + string(e.OldData) + "\n" + string(e.NewData) +
+		"\n" + string(e.Diff) + "\nrequest_id:req-42\nclient_ip:10.0.0.7\nuser_agent:curl/8.5.0"
+	sum := sha256.Sum256([]byte(canonical))
+
+	got := Hash(domain.AuditChainGenesis, &e)
+	assert.NotEqual(t, plain, got)
+	assert.Equal(t, hex.EncodeToString(sum[:]), got)
+}
+
+func TestHash_DependsOnPrevHash(t *testing.T) {
+	e := &newChain(1).links[0].AuditEntry
+
+	assert.NotEqual(t, Hash(domain.AuditChainGenesis, e), Hash("ff", e))
+}
+
+func TestVerify_ValidChain(t *testing.T) {
+	src := newChain(5)
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, int64(5), report.Checked)
+	assert.Equal(t, int64(5), report.LastID)
+	assert.Equal(t, src.links[4].Hash, report.LastHash)
+	assert.Nil(t, report.Broken)
+}
+
+// rehash пересчитывает хеши и голову после правки - как сделал бы пользователь с правом записи в БД
+func (f *fakeSource) rehash() {
+	prev := domain.AuditChainGenesis
+	for _, l := range f.links {
+		l.PrevHash = prev
+		l.Hash = Hash(prev, &l.AuditEntry)
+		prev = l.Hash
+	}
+	f.head = &domain.AuditChainHead{LastID: f.links[len(f.links)-1].ID, LastHash: prev}
+}
+
+func TestVerify_Anchor(t *testing.T) {
+	src := newChain(5)
+	anchor := &domain.AuditChainAnchor{EntryID: 3, Hash: src.links[2].Hash}
+
+	report, err := Verify(context.Background(), src, anchor)
+
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.True(t, report.AnchorVerified)
+}
+
+func TestVerify_RehashedChainCaughtByAnchor(t *testing.T) {
+	src := newChain(5)
+	anchor := &domain.AuditChainAnchor{EntryID: 4, Hash: src.links[3].Hash}
+
+	src.links[1].NewData = json.RawMessage(`{"quantity": 700}`)
+	src.rehash()
+
+	// без звена вне БД пересчитанная цепочка неотличима от настоящей
+	report, err := Verify(context.Background(), src, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+
+	report, err = Verify(context.Background(), src, anchor)
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(4), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainAnchorMismatch, report.Broken.Reason)
+	assert.Equal(t, anchor.Hash, report.Broken.Expected)
+	assert.Equal(t, src.links[3].Hash, report.Broken.Actual)
+}
+
+func TestVerify_TruncatedTailWithHeadCaughtByAnchor(t *testing.T) {
+	src := newChain(5)
+	anchor := &domain.AuditChainAnchor{EntryID: 5, Hash: src.links[4].Hash}
+
+	// хвост удалён, голова подвинута на последнюю оставшуюся запись
+	src.links = src.links[:3]
+	src.head = &domain.AuditChainHead{LastID: 3, LastHash: src.links[2].Hash}
+
+	report, err := Verify(context.Background(), src, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+
+	report, err = Verify(context.Background(), src, anchor)
+	require.NoError(t, err)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(5), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainAnchorMismatch, report.Broken.Reason)
+	assert.Empty(t, report.Broken.Actual)
+}
+
+func TestVerify_AnchorEntryMissing(t *testing.T) {
+	src := newChain(5)
+	anchor := &domain.AuditChainAnchor{EntryID: 3, Hash: src.links[2].Hash}
+
+	// запись 3 удалена, следующие перенумерованы и пересчитаны
+	src.links = append(src.links[:2], src.links[3:]...)
+	src.rehash()
+
+	report, err := Verify(context.Background(), src, anchor)
+	require.NoError(t, err)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, domain.AuditChainAnchorMismatch, report.Broken.Reason)
+	assert.Equal(t, int64(3), report.Broken.EntryID)
+}
+
+func TestVerify_EmptyChain(t *testing.T) {
+	report, err := Verify(context.Background(), newChain(0), nil)
+
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Zero(t, report.Checked)
+}
+
+func TestVerify_EditedEntry(t *testing.T) {
+	src := newChain(5)
+	src.links[2].NewData = json.RawMessage(`{"quantity": 700}`)
+
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(3), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainHashMismatch, report.Broken.Reason)
+	assert.Equal(t, src.links[2].Hash, report.Broken.Actual)
+	assert.Equal(t, int64(2), report.Checked)
+}
+
+func TestVerify_DeletedEntry(t *testing.T) {
+	src := newChain(5)
+	src.links = append(src.links[:1], src.links[2:]...)
+
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(3), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainPrevHashMismatch, report.Broken.Reason)
+	assert.Equal(t, src.links[0].Hash, report.Broken.Expected)
+}
+
+func TestVerify_RehashedEntryBreaksNextLink(t *testing.T) {
+	src := newChain(5)
+	// правка с пересчётом хеша самой записи всё равно видна по следующему звену
+	src.links[1].Diff = nil
+	src.links[1].Hash = Hash(src.links[1].PrevHash, &src.links[1].AuditEntry)
+
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(3), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainPrevHashMismatch, report.Broken.Reason)
+}
+
+func TestVerify_TruncatedTail(t *testing.T) {
+	src := newChain(5)
+	src.links = src.links[:3]
+
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(5), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainHeadMismatch, report.Broken.Reason)
+	assert.Equal(t, int64(3), report.Checked)
+}
+
+func TestVerify_SourceError(t *testing.T) {
+	src := newChain(2)
+	src.err = errors.New("db error")
+
+	_, err := Verify(context.Background(), src, nil)
+
+	assert.Error(t, err)
+}
+
+func TestVerify_ArchivedSegment(t *testing.T) {
+	src := newChain(5)
+	// записи 2-3 выгружены в архив, вместо них - отрезок с границами цепочки
+	archived := &domain.AuditChainLink{
+		AuditEntry: domain.AuditEntry{ID: 3},
+		PrevHash:   src.links[1].PrevHash,
+		Hash:       src.links[2].Hash,
+		Archived:   2,
+	}
+	src.links = append([]*domain.AuditChainLink{src.links[0], archived}, src.links[3:]...)
+
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, int64(3), report.Checked)
+	assert.Equal(t, int64(2), report.Archived)
+
+	// звено на границе отрезка сверяется, внутри - нет: записи только в файле архива
+	report, err = Verify(context.Background(), src, &domain.AuditChainAnchor{EntryID: 3, Hash: archived.Hash})
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.True(t, report.AnchorVerified)
+
+	report, err = Verify(context.Background(), src, &domain.AuditChainAnchor{EntryID: 2, Hash: archived.PrevHash})
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.False(t, report.AnchorVerified)
+}
+
+func TestVerify_ArchivedSegmentDoesNotCoverGap(t *testing.T) {
+	src := newChain(5)
+	// в архиве только запись 2, запись 3 удалена
+	archived := &domain.AuditChainLink{
+		AuditEntry: domain.AuditEntry{ID: 2},
+		PrevHash:   src.links[1].PrevHash,
+		Hash:       src.links[1].Hash,
+		Archived:   1,
+	}
+	src.links = append([]*domain.AuditChainLink{src.links[0], archived}, src.links[3:]...)
+
+	report, err := Verify(context.Background(), src, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, report.Broken)
+	assert.Equal(t, int64(4), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainPrevHashMismatch, report.Broken.Reason)
+}
+
+func TestSegments_SplitByChainOrder(t *testing.T) {
+	src := newChain(6)
+	var segs Segments
+
+	// в секции записи 1-2 и 4-6, запись 3 - в соседней секции
+	for _, i := range []int{0, 1, 3, 4, 5} {
+		require.Nil(t, segs.Add(src.links[i]))
+	}
+
+	list := segs.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, domain.AuditChainSegment{
+		FirstID: 1, LastID: 2, Rows: 2, PrevHash: domain.AuditChainGenesis, Hash: src.links[1].Hash,
+	}, list[0])
+	assert.Equal(t, domain.AuditChainSegment{
+		FirstID: 4, LastID: 6, Rows: 3, PrevHash: src.links[3].PrevHash, Hash: src.links[5].Hash,
+	}, list[1])
+	assert.Equal(t, int64(5), segs.Rows())
+}
+
+func TestSegments_EditedEntry(t *testing.T) {
+	src := newChain(3)
+	src.links[1].Reason = new(string)
+	var segs Segments
+
+	require.Nil(t, segs.Add(src.links[0]))
+	b := segs.Add(src.links[1])
+
+	require.NotNil(t, b)
+	assert.Equal(t, int64(2), b.EntryID)
+	assert.Equal(t, domain.AuditChainHashMismatch, b.Reason)
+}
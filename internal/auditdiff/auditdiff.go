@@ -0,0 +1,205 @@
+// Package auditdiff считает diff между снимками строки по тем же правилам,
+// что и триггер fn_audit_row(), - для записи аудита из приложения (audit.mode: app).
+package auditdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// skipFields - служебные поля, которые триггер не включает в diff
+var skipFields = map[string]struct{}{
+	"id":         {},
+	"updated_at": {},
+	"created_at": {},
+}
+
+// redacted - значение секретного поля в журнале, как в fn_audit_row()
+var redacted = json.RawMessage(`"***"`)
+
+// Compute возвращает {"поле": {"new": ..., "old": ...}} для полей newData, значение которых
+// отличается от oldData (сравнение по правилам jsonb: числа - по значению, объекты - без учёта порядка ключей).
+// Если отличий нет - nil, как и триггер, который в этом случае запись не пишет.
+// Изменение секретного поля из secret попадает в diff со значениями "***".
+// Результат - в текстовом виде jsonb (порядок ключей и разделители как у PostgreSQL),
+// значения полей копируются из снимков как есть.
+func Compute(oldData, newData json.RawMessage, secret ...string) (json.RawMessage, error) {
+	var oldFields, newFields map[string]json.RawMessage
+	if err := json.Unmarshal(oldData, &oldFields); err != nil {
+		return nil, fmt.Errorf("auditdiff: old data: %w", err)
+	}
+	if err := json.Unmarshal(newData, &newFields); err != nil {
+		return nil, fmt.Errorf("auditdiff: new data: %w", err)
+	}
+
+	var changed []string
+	for k, newVal := range newFields {
+		if _, skip := skipFields[k]; skip {
+			continue
+		}
+		oldVal, ok := oldFields[k]
+		if ok {
+			equal, err := jsonbEqual(oldVal, newVal)
+			if err != nil {
+				return nil, fmt.Errorf("auditdiff: field %s: %w", k, err)
+			}
+			if equal {
+				continue
+			}
+		}
+		changed = append(changed, k)
+	}
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	diff := make(map[string]json.RawMessage, len(changed))
+	for _, k := range changed {
+		oldVal, newVal := oldFields[k], newFields[k]
+		if oldVal == nil {
+			oldVal = json.RawMessage("null")
+		}
+		if isSecret(k, secret) {
+			oldVal, newVal = redacted, redacted
+		}
+		diff[k] = marshalObject(map[string]json.RawMessage{"new": newVal, "old": oldVal})
+	}
+
+	return marshalObject(diff), nil
+}
+
+// Redact заменяет в снимке значения секретных полей на "***"
+func Redact(data json.RawMessage, secret ...string) (json.RawMessage, error) {
+	if len(data) == 0 || len(secret) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("auditdiff: redact: %w", err)
+	}
+	for _, k := range secret {
+		if _, ok := fields[k]; ok {
+			fields[k] = redacted
+		}
+	}
+	return marshalObject(fields), nil
+}
+
+func isSecret(field string, secret []string) bool {
+	for _, k := range secret {
+		if k == field {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalObject собирает объект в текстовом виде jsonb, значения пишутся как есть
+func marshalObject(fields map[string]json.RawMessage) json.RawMessage {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeString(&buf, k)
+		buf.WriteString(": ")
+		buf.Write(fields[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// sortKeys - порядок ключей объекта в jsonb: сначала короткие, при равной длине - побайтово
+func sortKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode дописывает \n
+}
+
+// jsonbEqual сравнивает два JSON-значения так же, как оператор = для jsonb
+func jsonbEqual(a, b json.RawMessage) (bool, error) {
+	va, err := decode(a)
+	if err != nil {
+		return false, err
+	}
+	vb, err := decode(b)
+	if err != nil {
+		return false, err
+	}
+	return valueEqual(va, vb), nil
+}
+
+func decode(raw json.RawMessage) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func valueEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		dx, errX := decimal.NewFromString(x.String())
+		dy, errY := decimal.NewFromString(y.String())
+		if errX != nil || errY != nil {
+			return x == y
+		}
+		return dx.Equal(dy)
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !valueEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !valueEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
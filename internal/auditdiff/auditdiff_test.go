@@ -0,0 +1,115 @@
+package auditdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// снимки в том виде, в каком их отдаёт to_jsonb(items)
+const oldItem = `{"id": "6f1c2a9e-0b7d-4c1e-9a53-2f0e8d4b7c11", "sku": "A-1", "name": "Болт", "price": 10.00, ` +
+	`"location": null, "quantity": 10, "created_at": "2026-03-10T09:00:00.123456+00:00", ` +
+	`"updated_at": "2026-03-10T09:00:00.123456+00:00"}`
+
+func TestCompute_SingleField(t *testing.T) {
+	newItem := `{"id": "6f1c2a9e-0b7d-4c1e-9a53-2f0e8d4b7c11", "sku": "A-1", "name": "Болт", "price": 10.00, ` +
+		`"location": null, "quantity": 7, "created_at": "2026-03-10T09:00:00.123456+00:00", ` +
+		`"updated_at": "2026-03-10T09:05:00+00:00"}`
+
+	diff, err := Compute(json.RawMessage(oldItem), json.RawMessage(newItem))
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"quantity": {"new": 7, "old": 10}}`, string(diff))
+}
+
+func TestCompute_KeyOrderMatchesJSONB(t *testing.T) {
+	newItem := `{"id": "6f1c2a9e-0b7d-4c1e-9a53-2f0e8d4b7c11", "sku": "A-2", "name": "Болт \"М6\"", "price": 12.50, ` +
+		`"location": "Склад 1", "quantity": 10, "created_at": "2026-03-10T09:00:00.123456+00:00", ` +
+		`"updated_at": "2026-03-10T09:05:00+00:00"}`
+
+	diff, err := Compute(json.RawMessage(oldItem), json.RawMessage(newItem))
+
+	require.NoError(t, err)
+	// jsonb: короткие ключи раньше длинных, при равной длине - побайтово
+	assert.Equal(t, `{"sku": {"new": "A-2", "old": "A-1"}, "name": {"new": "Болт \"М6\"", "old": "Болт"}, `+
+		`"price": {"new": 12.50, "old": 10.00}, "location": {"new": "Склад 1", "old": null}}`, string(diff))
+}
+
+func TestCompute_NoChanges(t *testing.T) {
+	// другой порядок ключей, другой масштаб числа и новый updated_at - для jsonb это те же данные
+	newItem := `{"quantity": 10, "location": null, "price": 10.0, "name": "Болт", "sku": "A-1", ` +
+		`"id": "6f1c2a9e-0b7d-4c1e-9a53-2f0e8d4b7c11", "created_at": "2026-03-10T09:00:00.123456+00:00", ` +
+		`"updated_at": "2026-03-11T00:00:00+00:00"}`
+
+	diff, err := Compute(json.RawMessage(oldItem), json.RawMessage(newItem))
+
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+}
+
+func TestCompute_MissingOldField(t *testing.T) {
+	diff, err := Compute(json.RawMessage(`{"id": 1}`), json.RawMessage(`{"id": 1, "note": null}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"note": {"new": null, "old": null}}`, string(diff))
+}
+
+func TestCompute_InvalidJSON(t *testing.T) {
+	_, err := Compute(json.RawMessage(`{`), json.RawMessage(oldItem))
+
+	assert.Error(t, err)
+}
+
+func TestJSONBEqual(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{`1`, `1.0`, true},
+		{`1e2`, `100`, true},
+		{`"1"`, `1`, false},
+		{`null`, `null`, true},
+		{`null`, `false`, false},
+		{`{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1}`, true},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			got, err := jsonbEqual(json.RawMessage(tt.a), json.RawMessage(tt.b))
+			require.NoError(t, err)
+			assert.Equal(t, tt.equal, got)
+		})
+	}
+}
+
+const oldUser = `{"id": "a0000000-0000-0000-0000-000000000001", "role": "manager", "username": "ivan", ` +
+	`"created_at": "2026-03-10T09:00:00+00:00", "updated_at": "2026-03-10T09:00:00+00:00", "password_hash": "$2a$10$old"}`
+
+func TestCompute_SecretField(t *testing.T) {
+	newUser := `{"id": "a0000000-0000-0000-0000-000000000001", "role": "admin", "username": "ivan", ` +
+		`"created_at": "2026-03-10T09:00:00+00:00", "updated_at": "2026-03-11T09:00:00+00:00", "password_hash": "$2a$10$new"}`
+
+	diff, err := Compute(json.RawMessage(oldUser), json.RawMessage(newUser), "password_hash")
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"role": {"new": "admin", "old": "manager"}, "password_hash": {"new": "***", "old": "***"}}`, string(diff))
+}
+
+func TestRedact(t *testing.T) {
+	data, err := Redact(json.RawMessage(oldUser), "password_hash")
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"id": "a0000000-0000-0000-0000-000000000001", "role": "manager", "username": "ivan", `+
+		`"created_at": "2026-03-10T09:00:00+00:00", "updated_at": "2026-03-10T09:00:00+00:00", "password_hash": "***"}`, string(data))
+}
+
+func TestRedact_NoSecrets(t *testing.T) {
+	data, err := Redact(json.RawMessage(oldItem))
+
+	require.NoError(t, err)
+	assert.Equal(t, oldItem, string(data))
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// syncOverlap - насколько раньше последней синхронизации запрашиваются отзывы: revoked_at
+// ставится при вставке, а видна запись после коммита, который может случиться позже
+const syncOverlap = time.Minute
+
+type denylistStore interface {
+	ListRevoked(ctx context.Context, since time.Time) ([]*domain.RevokedToken, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DenylistOptions - кэш отозванных токенов
+type DenylistOptions struct {
+	SyncInterval    time.Duration // как часто подтягивать отзывы других экземпляров
+	CleanupInterval time.Duration // как часто удалять из БД истёкшие записи
+}
+
+// Denylist - отозванные токены доступа в памяти. Validate проверяет jti без запроса к БД:
+// отзывы этого экземпляра попадают в кэш сразу (Add), других - при синхронизации, не позже SyncInterval.
+type Denylist struct {
+	store denylistStore
+	opts  DenylistOptions
+	log   logger.Logger
+	now   func() time.Time
+
+	mu      sync.RWMutex
+	entries map[uuid.UUID]time.Time // jti -> истечение токена
+	synced  time.Time
+}
+
+func NewDenylist(store denylistStore, opts DenylistOptions, log logger.Logger) *Denylist {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = 5 * time.Second
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Hour
+	}
+
+	return &Denylist{
+		store:   store,
+		opts:    opts,
+		log:     log.With("component", "Denylist"),
+		now:     time.Now,
+		entries: make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (d *Denylist) IsRevoked(jti uuid.UUID) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, ok := d.entries[jti]
+	return ok
+}
+
+// Add заносит в кэш токены, отозванные этим экземпляром (в БД они уже записаны)
+func (d *Denylist) Add(tokens ...*domain.RevokedToken) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, t := range tokens {
+		d.entries[t.ID] = t.ExpiresAt
+	}
+}
+
+// Sync подтягивает отзывы из БД и выбрасывает из кэша истёкшие токены.
+// Первый вызов загружает весь список - до него сервер не должен принимать запросы.
+func (d *Denylist) Sync(ctx context.Context) error {
+	d.mu.RLock()
+	since := d.synced
+	d.mu.RUnlock()
+
+	start := d.now()
+	if !since.IsZero() {
+		since = since.Add(-syncOverlap)
+	}
+	tokens, err := d.store.ListRevoked(ctx, since)
+	if err != nil {
+		return fmt.Errorf("Denylist.Sync: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, t := range tokens {
+		d.entries[t.ID] = t.ExpiresAt
+	}
+	for jti, exp := range d.entries {
+		if !exp.After(start) {
+			delete(d.entries, jti)
+		}
+	}
+	d.synced = start
+	return nil
+}
+
+// Run синхронизирует кэш и удаляет из БД истёкшие отзывы и refresh-токены до отмены ctx
+func (d *Denylist) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.cleanupLoop(ctx)
+	}()
+
+	ticker := time.NewTicker(d.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := d.Sync(ctx); err != nil && ctx.Err() == nil {
+			d.log.Ctx(ctx).Error("failed to sync token denylist",
+				"error", err,
+			)
+		}
+	}
+}
+
+func (d *Denylist) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := d.store.DeleteExpired(ctx, d.now())
+		if err != nil {
+			if ctx.Err() == nil {
+				d.log.Ctx(ctx).Error("failed to delete expired tokens",
+					"error", err,
+				)
+			}
+			continue
+		}
+		if n > 0 {
+			d.log.Ctx(ctx).Info("expired tokens deleted",
+				"count", n,
+			)
+		}
+	}
+}
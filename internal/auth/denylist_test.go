@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDenylistStore struct {
+	tokens []*domain.RevokedToken
+	since  []time.Time
+}
+
+func (s *fakeDenylistStore) ListRevoked(_ context.Context, since time.Time) ([]*domain.RevokedToken, error) {
+	s.since = append(s.since, since)
+	return s.tokens, nil
+}
+
+func (s *fakeDenylistStore) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestDenylist(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeDenylistStore{}
+	d := NewDenylist(store, DenylistOptions{}, newTestLogger())
+	d.now = func() time.Time { return now }
+
+	local := &domain.RevokedToken{ID: uuid.New(), ExpiresAt: now.Add(10 * time.Minute)}
+	remote := &domain.RevokedToken{ID: uuid.New(), ExpiresAt: now.Add(5 * time.Minute)}
+
+	// первая синхронизация загружает весь список
+	store.tokens = []*domain.RevokedToken{remote}
+	require.NoError(t, d.Sync(context.Background()))
+	assert.True(t, store.since[0].IsZero())
+	assert.True(t, d.IsRevoked(remote.ID))
+
+	// отзыв этого экземпляра виден сразу, без синхронизации
+	assert.False(t, d.IsRevoked(local.ID))
+	d.Add(local)
+	assert.True(t, d.IsRevoked(local.ID))
+
+	// следующие синхронизации запрашивают только новые отзывы - с запасом на поздний коммит
+	now = now.Add(6 * time.Minute)
+	store.tokens = nil
+	require.NoError(t, d.Sync(context.Background()))
+	assert.Equal(t, now.Add(-6*time.Minute-syncOverlap), store.since[1])
+
+	// истёкший токен и так не пройдёт проверку - из кэша он выбрасывается
+	assert.False(t, d.IsRevoked(remote.ID))
+	assert.True(t, d.IsRevoked(local.ID))
+	assert.False(t, d.IsRevoked(uuid.New()))
+}
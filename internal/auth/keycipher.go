@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// keyCipherSize - AES-256
+const keyCipherSize = 32
+
+var errKeyCiphertext = errors.New("signing key ciphertext is too short")
+
+// KeyCipher шифрует закрытые ключи подписи в БД (AES-256-GCM) ключом auth.signing.encryption_key.
+// kid - дополнительные данные шифра: ключ, переписанный в чужую строку, не расшифруется.
+type KeyCipher struct {
+	aead cipher.AEAD
+}
+
+// NewKeyCipher - encoded: 32 байта в base64 (openssl rand -base64 32)
+func NewKeyCipher(encoded string) (*KeyCipher, error) {
+	if encoded == "" {
+		return nil, errors.New("encryption key is required")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption key: %w", err)
+	}
+	if len(key) != keyCipherSize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", keyCipherSize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyCipher{aead: aead}, nil
+}
+
+// Seal возвращает nonce и шифртекст одним срезом
+func (c *KeyCipher) Seal(kid string, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, []byte(kid)), nil
+}
+
+// Open расшифровывает результат Seal; ошибка - другой ключ шифрования, другой kid или изменённые данные
+func (c *KeyCipher) Open(kid string, sealed []byte) ([]byte, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, errKeyCiphertext
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt key %s: %w", kid, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCipher(t *testing.T) {
+	c := newTestKeyCipher(t)
+	plaintext := []byte("pkcs8")
+
+	sealed, err := c.Seal("kid-1", plaintext)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), string(plaintext))
+
+	opened, err := c.Open("kid-1", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	// шифртекст привязан к kid
+	_, err = c.Open("kid-2", sealed)
+	assert.Error(t, err)
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = c.Open("kid-1", tampered)
+	assert.Error(t, err)
+
+	_, err = c.Open("kid-1", sealed[:4])
+	assert.ErrorIs(t, err, errKeyCiphertext)
+}
+
+func TestNewKeyCipher_Invalid(t *testing.T) {
+	for name, encoded := range map[string]string{
+		"empty":      "",
+		"not base64": "not base64!",
+		"short":      base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewKeyCipher(encoded)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// clockSkew - запас на расхождение часов экземпляров при удалении заменённых ключей
+const clockSkew = time.Minute
+
+type keyStore interface {
+	ListKeys(ctx context.Context) ([]*domain.SigningKey, error)
+	CreateKeyIfDue(ctx context.Context, key *domain.SigningKey, dueBefore time.Time) (bool, error)
+	EncryptKey(ctx context.Context, kid string, sealed []byte) error
+	DeleteSuperseded(ctx context.Context, before time.Time) (int64, error)
+}
+
+// KeyringOptions - ключи RS256/EdDSA с ротацией
+type KeyringOptions struct {
+	Algorithm        string        // RS256 или EdDSA
+	RotationInterval time.Duration // как часто начинать подписывать новым ключом; 0 - без ротации
+	PublishAhead     time.Duration // за сколько до активации новый ключ появляется в JWKS
+	SyncInterval     time.Duration // как часто перечитывать ключи и проверять, не пора ли ротировать
+	TokenTTL         time.Duration // срок жизни токена: столько заменённый ключ ещё нужен для проверки
+	Cipher           *KeyCipher    // шифрует закрытые ключи в БД
+	// LegacySecret - секрет HS256 для токенов без kid, выпущенных до перехода; пустой - такие не принимаются.
+	// Принимаются они только TokenTTL после активации первого ключа: позже действующих среди них нет,
+	// а секретом можно подписать любой токен.
+	LegacySecret string
+}
+
+// Keyring - ключи подписи в БД, общие для всех экземпляров. Новый ключ создаётся за PublishAhead
+// до активации: к этому времени его загрузят остальные экземпляры и потребители JWKS.
+// Заменённый ключ остаётся для проверки, пока не истекут подписанные им токены.
+type Keyring struct {
+	store keyStore
+	opts  KeyringOptions
+	log   logger.Logger
+	now   func() time.Time
+
+	mu     sync.RWMutex
+	keys   []*Key // по возрастанию ActivatesAt
+	byID   map[string]*Key
+	legacy *Key
+}
+
+func NewKeyring(store keyStore, opts KeyringOptions, log logger.Logger) (*Keyring, error) {
+	if opts.Algorithm != AlgRS256 && opts.Algorithm != AlgEdDSA {
+		return nil, fmt.Errorf("keyring: unsupported algorithm %q", opts.Algorithm)
+	}
+	if opts.Cipher == nil {
+		return nil, errors.New("keyring: key cipher is required")
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Minute
+	}
+	if opts.RotationInterval > 0 {
+		if opts.PublishAhead < opts.SyncInterval {
+			return nil, fmt.Errorf("keyring: publish_ahead (%s) must be at least sync_interval (%s)",
+				opts.PublishAhead, opts.SyncInterval)
+		}
+		if opts.PublishAhead >= opts.RotationInterval {
+			return nil, fmt.Errorf("keyring: publish_ahead (%s) must be less than rotation_interval (%s)",
+				opts.PublishAhead, opts.RotationInterval)
+		}
+	}
+
+	k := &Keyring{
+		store: store,
+		opts:  opts,
+		log:   log.With("component", "Keyring"),
+		now:   time.Now,
+		byID:  make(map[string]*Key),
+	}
+	if opts.LegacySecret != "" {
+		k.legacy = newHMACKey(opts.LegacySecret)
+	}
+	return k, nil
+}
+
+// SigningKey - последний активированный ключ настроенного алгоритма
+func (k *Keyring) SigningKey() (*Key, error) {
+	now := k.now()
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		key := k.keys[i]
+		if key.Method.Alg() == k.opts.Algorithm && !key.ActivatesAt.After(now) {
+			return key, nil
+		}
+	}
+	return nil, errNoSigningKey
+}
+
+func (k *Keyring) VerificationKey(kid string) (*Key, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" {
+		return k.legacy, k.legacyAccepted()
+	}
+
+	key, ok := k.byID[kid]
+	return key, ok
+}
+
+// legacyAccepted - принимаются ли ещё токены без kid. Окно отсчитывается от самого раннего
+// из загруженных ключей: заменённые удаляются лишь через TokenTTL после активации следующего,
+// так что после удаления первого ключа окно остаётся закрытым. Вызывается под k.mu.
+func (k *Keyring) legacyAccepted() bool {
+	if k.legacy == nil || len(k.keys) == 0 {
+		return false
+	}
+	return k.now().Before(k.keys[0].ActivatesAt.Add(k.opts.TokenTTL + clockSkew))
+}
+
+// PublicKeys - открытые ключи для JWKS, включая ещё не активированный следующий
+func (k *Keyring) PublicKeys() []*domain.PublicKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	res := make([]*domain.PublicKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		res = append(res, &domain.PublicKey{
+			ID:        key.ID,
+			Algorithm: key.Method.Alg(),
+			Key:       key.Public,
+		})
+	}
+	return res
+}
+
+// Sync перечитывает ключи, создаёт следующий, если подошёл срок ротации,
+// и удаляет заменённые любым следующим ключом, подписанные которыми токены уже истекли.
+// Первый вызов должен пройти до приёма запросов: до него подписывать нечем.
+func (k *Keyring) Sync(ctx context.Context) error {
+	const op = "Keyring.Sync"
+
+	if err := k.load(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	created, err := k.rotate(ctx)
+	if err != nil {
+		return fmt.Errorf("%s - rotate: %w", op, err)
+	}
+
+	n, err := k.store.DeleteSuperseded(ctx, k.now().Add(-k.opts.TokenTTL-clockSkew))
+	if err != nil {
+		return fmt.Errorf("%s - delete superseded keys: %w", op, err)
+	}
+
+	if created || n > 0 {
+		return k.load(ctx)
+	}
+	return nil
+}
+
+// Run синхронизирует ключи раз в SyncInterval до отмены ctx
+func (k *Keyring) Run(ctx context.Context) error {
+	ticker := time.NewTicker(k.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := k.Sync(ctx); err != nil && ctx.Err() == nil {
+			k.log.Ctx(ctx).Error("failed to sync signing keys",
+				"error", err,
+			)
+		}
+	}
+}
+
+func (k *Keyring) load(ctx context.Context) error {
+	stored, err := k.store.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*Key, 0, len(stored))
+	byID := make(map[string]*Key, len(stored))
+	for _, s := range stored {
+		key, err := k.open(ctx, s)
+		if err != nil {
+			// испорченный ключ не должен мешать проверке токенов остальными
+			k.log.Ctx(ctx).Error("skipping invalid signing key",
+				"error", err,
+				"kid", s.ID,
+			)
+			continue
+		}
+		keys = append(keys, key)
+		byID[key.ID] = key
+	}
+	slices.SortFunc(keys, func(a, b *Key) int { return a.ActivatesAt.Compare(b.ActivatesAt) })
+
+	k.mu.Lock()
+	k.keys, k.byID = keys, byID
+	k.mu.Unlock()
+	return nil
+}
+
+// open расшифровывает и разбирает ключ из БД. Незашифрованный ключ, созданный до шифрования,
+// тут же шифруется в БД; не удалось - он всё равно используется, попытка повторится при следующей загрузке.
+func (k *Keyring) open(ctx context.Context, stored *domain.SigningKey) (*Key, error) {
+	if stored.Encrypted {
+		der, err := k.opts.Cipher.Open(stored.ID, stored.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		plain := *stored
+		plain.PrivateKey = der
+		return parseKey(&plain)
+	}
+
+	key, err := parseKey(stored)
+	if err != nil {
+		return nil, err
+	}
+	if err = k.encrypt(ctx, stored); err != nil {
+		k.log.Ctx(ctx).Error("failed to encrypt signing key",
+			"error", err,
+			"kid", stored.ID,
+		)
+	}
+	return key, nil
+}
+
+func (k *Keyring) encrypt(ctx context.Context, stored *domain.SigningKey) error {
+	sealed, err := k.opts.Cipher.Seal(stored.ID, stored.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if err = k.store.EncryptKey(ctx, stored.ID, sealed); err != nil {
+		return err
+	}
+	k.log.Ctx(ctx).Info("signing key encrypted",
+		"kid", stored.ID,
+	)
+	return nil
+}
+
+// rotate создаёт следующий ключ, если последний ключ алгоритма активирован больше
+// RotationInterval - PublishAhead назад. Самый первый ключ активируется сразу.
+func (k *Keyring) rotate(ctx context.Context) (bool, error) {
+	now := k.now()
+
+	var latest *Key
+	k.mu.RLock()
+	for _, key := range k.keys {
+		if key.Method.Alg() == k.opts.Algorithm {
+			latest = key
+		}
+	}
+	k.mu.RUnlock()
+
+	activatesAt := now
+	if latest != nil {
+		if k.opts.RotationInterval <= 0 {
+			return false, nil
+		}
+		if latest.ActivatesAt.Add(k.opts.RotationInterval - k.opts.PublishAhead).After(now) {
+			return false, nil
+		}
+		activatesAt = now.Add(k.opts.PublishAhead)
+	}
+
+	private, err := generatePrivateKey(k.opts.Algorithm)
+	if err != nil {
+		return false, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return false, fmt.Errorf("marshal key: %w", err)
+	}
+
+	id := uuid.NewString()
+	sealed, err := k.opts.Cipher.Seal(id, der)
+	if err != nil {
+		return false, fmt.Errorf("encrypt key: %w", err)
+	}
+
+	next := &domain.SigningKey{
+		ID:          id,
+		Algorithm:   k.opts.Algorithm,
+		PrivateKey:  sealed,
+		Encrypted:   true,
+		ActivatesAt: activatesAt,
+	}
+	// другой экземпляр мог уже создать ключ - тогда этот отбрасывается
+	created, err := k.store.CreateKeyIfDue(ctx, next, now.Add(k.opts.PublishAhead-k.opts.RotationInterval))
+	if err != nil {
+		return false, err
+	}
+	if created {
+		k.log.Ctx(ctx).Info("signing key created",
+			"kid", next.ID,
+			"algorithm", next.Algorithm,
+			"activates_at", next.ActivatesAt,
+		)
+	}
+	return created, nil
+}
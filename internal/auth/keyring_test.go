@@ -0,0 +1,308 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeyStore повторяет семантику SigningKeyRepository в памяти
+type fakeKeyStore struct {
+	keys []*domain.SigningKey
+}
+
+func (s *fakeKeyStore) ListKeys(context.Context) ([]*domain.SigningKey, error) {
+	return s.keys, nil
+}
+
+func (s *fakeKeyStore) CreateKeyIfDue(_ context.Context, key *domain.SigningKey, dueBefore time.Time) (bool, error) {
+	for _, k := range s.keys {
+		if k.Algorithm == key.Algorithm && k.ActivatesAt.After(dueBefore) {
+			return false, nil
+		}
+	}
+	s.keys = append(s.keys, key)
+	return true, nil
+}
+
+func (s *fakeKeyStore) EncryptKey(_ context.Context, kid string, sealed []byte) error {
+	for _, k := range s.keys {
+		if k.ID == kid && !k.Encrypted {
+			k.PrivateKey, k.Encrypted = sealed, true
+		}
+	}
+	return nil
+}
+
+func (s *fakeKeyStore) DeleteSuperseded(_ context.Context, before time.Time) (int64, error) {
+	var kept []*domain.SigningKey
+	for _, k := range s.keys {
+		superseded := false
+		for _, next := range s.keys {
+			if next.ActivatesAt.After(k.ActivatesAt) && !next.ActivatesAt.After(before) {
+				superseded = true
+			}
+		}
+		if !superseded {
+			kept = append(kept, k)
+		}
+	}
+	n := int64(len(s.keys) - len(kept))
+	s.keys = kept
+	return n, nil
+}
+
+func newTestKeyCipher(t *testing.T) *KeyCipher {
+	t.Helper()
+	c, err := NewKeyCipher(base64.StdEncoding.EncodeToString(make([]byte, keyCipherSize)))
+	require.NoError(t, err)
+	return c
+}
+
+func newTestKeyring(t *testing.T, store keyStore, opts KeyringOptions, now *time.Time) *Keyring {
+	t.Helper()
+	if opts.Cipher == nil {
+		opts.Cipher = newTestKeyCipher(t)
+	}
+	k, err := NewKeyring(store, opts, newTestLogger())
+	require.NoError(t, err)
+	k.now = func() time.Time { return *now }
+	return k
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)
+			store := &fakeKeyStore{}
+			k := newTestKeyring(t, store, KeyringOptions{
+				Algorithm:        alg,
+				RotationInterval: 24 * time.Hour,
+				PublishAhead:     time.Hour,
+				SyncInterval:     time.Minute,
+				TokenTTL:         15 * time.Minute,
+			}, &now)
+
+			// до первой синхронизации подписывать нечем
+			_, err := k.SigningKey()
+			require.ErrorIs(t, err, errNoSigningKey)
+
+			// первый ключ активируется сразу
+			require.NoError(t, k.Sync(ctx))
+			first, err := k.SigningKey()
+			require.NoError(t, err)
+			assert.Equal(t, alg, first.Method.Alg())
+			require.Len(t, k.PublicKeys(), 1)
+
+			m := NewManager(k, 15*time.Minute, nil)
+			user := &domain.User{ID: uuid.New(), Username: "admin", Role: domain.RoleAdmin}
+			oldToken, err := m.GenerateJWT(user)
+			require.NoError(t, err)
+
+			// до срока ротации новый ключ не создаётся
+			now = now.Add(22 * time.Hour)
+			require.NoError(t, k.Sync(ctx))
+			require.Len(t, k.PublicKeys(), 1)
+
+			// следующий ключ публикуется заранее, но подписывает пока старый
+			now = now.Add(time.Hour)
+			require.NoError(t, k.Sync(ctx))
+			require.Len(t, k.PublicKeys(), 2)
+			current, err := k.SigningKey()
+			require.NoError(t, err)
+			assert.Equal(t, first.ID, current.ID)
+
+			// после активации подписывает новый, а токены старого ещё проверяются
+			now = now.Add(time.Hour)
+			require.NoError(t, k.Sync(ctx))
+			current, err = k.SigningKey()
+			require.NoError(t, err)
+			assert.NotEqual(t, first.ID, current.ID)
+
+			newToken, err := m.GenerateJWT(user)
+			require.NoError(t, err)
+			_, err = m.Validate(newToken.Token)
+			require.NoError(t, err)
+
+			// заменённый ключ удаляется, когда истекли все подписанные им токены
+			now = now.Add(15*time.Minute + clockSkew + time.Second)
+			require.NoError(t, k.Sync(ctx))
+			require.Len(t, k.PublicKeys(), 1)
+			_, ok := k.VerificationKey(first.ID)
+			assert.False(t, ok)
+			_, err = m.Validate(oldToken.Token)
+			assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+		})
+	}
+}
+
+func TestKeyring_EncryptsKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := &fakeKeyStore{}
+	opts := KeyringOptions{Algorithm: AlgEdDSA, TokenTTL: time.Hour}
+
+	k := newTestKeyring(t, store, opts, &now)
+	require.NoError(t, k.Sync(ctx))
+	first, err := k.SigningKey()
+	require.NoError(t, err)
+
+	// в БД попадает только шифртекст
+	require.Len(t, store.keys, 1)
+	assert.True(t, store.keys[0].Encrypted)
+	_, err = x509.ParsePKCS8PrivateKey(store.keys[0].PrivateKey)
+	assert.Error(t, err)
+
+	// другой экземпляр с тем же ключом шифрования подписывает тем же ключом
+	k = newTestKeyring(t, store, opts, &now)
+	require.NoError(t, k.Sync(ctx))
+	current, err := k.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, current.ID)
+
+	// с другим ключом шифрования сохранённый ключ не расшифровать - создаётся новый
+	other, err := NewKeyCipher(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, keyCipherSize)))
+	require.NoError(t, err)
+	opts.Cipher = other
+	k = newTestKeyring(t, store, opts, &now)
+	require.NoError(t, k.Sync(ctx))
+	current, err = k.SigningKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, current.ID)
+	_, ok := k.VerificationKey(first.ID)
+	assert.False(t, ok)
+}
+
+// ключ, сохранённый до шифрования, продолжает подписывать и шифруется при загрузке
+func TestKeyring_EncryptsPlaintextKey(t *testing.T) {
+	now := time.Now()
+	private, err := generatePrivateKey(AlgRS256)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	stored := &domain.SigningKey{ID: uuid.NewString(), Algorithm: AlgRS256, PrivateKey: der, ActivatesAt: now.Add(-time.Hour)}
+	store := &fakeKeyStore{keys: []*domain.SigningKey{stored}}
+
+	k := newTestKeyring(t, store, KeyringOptions{Algorithm: AlgRS256, TokenTTL: time.Hour}, &now)
+	require.NoError(t, k.Sync(context.Background()))
+
+	current, err := k.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, stored.ID, current.ID)
+	require.Len(t, store.keys, 1)
+	assert.True(t, store.keys[0].Encrypted)
+	assert.NotEqual(t, der, store.keys[0].PrivateKey)
+}
+
+// после смены алгоритма ключи прежнего удаляются, когда истекли подписанные ими токены
+func TestKeyring_PrunesAfterAlgorithmChange(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)
+	store := &fakeKeyStore{}
+
+	rs := newTestKeyring(t, store, KeyringOptions{Algorithm: AlgRS256, TokenTTL: 15 * time.Minute}, &now)
+	require.NoError(t, rs.Sync(ctx))
+	old, err := rs.SigningKey()
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	ed := newTestKeyring(t, store, KeyringOptions{Algorithm: AlgEdDSA, TokenTTL: 15 * time.Minute}, &now)
+	require.NoError(t, ed.Sync(ctx))
+	require.Len(t, ed.PublicKeys(), 2)
+
+	now = now.Add(15*time.Minute + clockSkew + time.Second)
+	require.NoError(t, ed.Sync(ctx))
+	require.Len(t, ed.PublicKeys(), 1)
+	_, ok := ed.VerificationKey(old.ID)
+	assert.False(t, ok)
+}
+
+func TestKeyring_LegacySecret(t *testing.T) {
+	now := time.Now()
+	legacy := NewManager(NewHMACKeySet("old-secret"), time.Hour, nil)
+	user := &domain.User{ID: uuid.New(), Username: "admin", Role: domain.RoleAdmin}
+	legacyToken, err := legacy.GenerateJWT(user)
+	require.NoError(t, err)
+
+	opts := KeyringOptions{Algorithm: AlgEdDSA, TokenTTL: time.Hour}
+
+	// без LegacySecret токены HS256 без kid не принимаются
+	k := newTestKeyring(t, &fakeKeyStore{}, opts, &now)
+	require.NoError(t, k.Sync(context.Background()))
+	_, err = NewManager(k, time.Hour, nil).Validate(legacyToken.Token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	opts.LegacySecret = "old-secret"
+	k = newTestKeyring(t, &fakeKeyStore{}, opts, &now)
+	require.NoError(t, k.Sync(context.Background()))
+	_, err = NewManager(k, time.Hour, nil).Validate(legacyToken.Token)
+	assert.NoError(t, err)
+
+	// через TokenTTL после активации первого ключа действующих токенов HS256 не осталось:
+	// секретом теперь можно только подделать токен
+	now = now.Add(opts.TokenTTL + clockSkew)
+	_, err = NewManager(k, time.Hour, nil).Validate(legacyToken.Token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
+
+// токен HS256, подписанный открытым ключом RS256 как секретом, не должен приниматься
+func TestManager_ValidateAlgorithmConfusion(t *testing.T) {
+	now := time.Now()
+	k := newTestKeyring(t, &fakeKeyStore{}, KeyringOptions{Algorithm: AlgRS256, TokenTTL: time.Hour}, &now)
+	require.NoError(t, k.Sync(context.Background()))
+	key, err := k.SigningKey()
+	require.NoError(t, err)
+
+	claims := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		UserID:   uuid.NewString(),
+		Username: "admin",
+		Role:     string(domain.RoleAdmin),
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = key.ID
+	token, err := forged.SignedString([]byte("any-secret"))
+	require.NoError(t, err)
+
+	_, err = NewManager(k, time.Hour, nil).Validate(token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
+
+func TestNewKeyring_InvalidOptions(t *testing.T) {
+	cipher := newTestKeyCipher(t)
+	tests := []struct {
+		name string
+		opts KeyringOptions
+	}{
+		{"hmac", KeyringOptions{Algorithm: AlgHS256, Cipher: cipher}},
+		{"no cipher", KeyringOptions{Algorithm: AlgRS256}},
+		{"publish ahead shorter than sync", KeyringOptions{
+			Algorithm: AlgRS256, RotationInterval: time.Hour, PublishAhead: time.Second, SyncInterval: time.Minute,
+			Cipher: cipher,
+		}},
+		{"publish ahead longer than rotation", KeyringOptions{
+			Algorithm: AlgRS256, RotationInterval: time.Hour, PublishAhead: 2 * time.Hour, SyncInterval: time.Minute,
+			Cipher: cipher,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(&fakeKeyStore{}, tt.opts, newTestLogger())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Алгоритмы подписи токенов доступа (auth.signing.algorithm)
+const (
+	AlgHS256 = "HS256" // общий секрет auth.secret; проверить токен может только тот, кто знает секрет
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const rsaKeyBits = 2048
+
+var errNoSigningKey = errors.New("no active signing key")
+
+// Key - ключ подписи или проверки токенов. ID попадает в заголовок kid;
+// у ключа HS256 его нет, Private и Public - один и тот же секрет.
+type Key struct {
+	ID          string
+	Method      jwt.SigningMethod
+	Private     any
+	Public      any
+	ActivatesAt time.Time
+}
+
+// KeySet - ключи Manager: текущий для выпуска токенов и все действующие для проверки
+type KeySet interface {
+	SigningKey() (*Key, error)
+	VerificationKey(kid string) (*Key, bool)
+}
+
+// HMACKeySet - единственный ключ HS256 из auth.secret
+type HMACKeySet struct {
+	key *Key
+}
+
+func NewHMACKeySet(secret string) *HMACKeySet {
+	return &HMACKeySet{key: newHMACKey(secret)}
+}
+
+func (s *HMACKeySet) SigningKey() (*Key, error) { return s.key, nil }
+
+func (s *HMACKeySet) VerificationKey(kid string) (*Key, bool) {
+	return s.key, kid == ""
+}
+
+// PublicKeys - секрет HS256 не публикуется
+func (s *HMACKeySet) PublicKeys() []*domain.PublicKey { return nil }
+
+func newHMACKey(secret string) *Key {
+	return &Key{
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}
+}
+
+// generatePrivateKey создаёт ключ RS256 или EdDSA
+func generatePrivateKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", alg)
+	}
+}
+
+// parseKey восстанавливает ключ из записи в БД и проверяет, что тип ключа соответствует алгоритму
+func parseKey(stored *domain.SigningKey) (*Key, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(stored.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", stored.ID, err)
+	}
+
+	key := &Key{ID: stored.ID, ActivatesAt: stored.ActivatesAt}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if stored.Algorithm != AlgRS256 {
+			return nil, fmt.Errorf("key %s: RSA key for %s", stored.ID, stored.Algorithm)
+		}
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, private, &private.PublicKey
+	case ed25519.PrivateKey:
+		if stored.Algorithm != AlgEdDSA {
+			return nil, fmt.Errorf("key %s: Ed25519 key for %s", stored.ID, stored.Algorithm)
+		}
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, private, private.Public()
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", stored.ID, parsed)
+	}
+	return key, nil
+}
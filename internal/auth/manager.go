@@ -9,15 +9,23 @@ import (
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 )
 
+// RevocationList - отозванные токены доступа по jti (см. Denylist)
+type RevocationList interface {
+	IsRevoked(jti uuid.UUID) bool
+}
+
 type Manager struct {
-	secret   []byte
+	keys     KeySet
 	tokenTTL time.Duration
+	revoked  RevocationList
 }
 
-func NewManager(secret string, tokenTTL time.Duration) *Manager {
+// NewManager - revoked может быть nil: тогда токены не отзываются и действуют до истечения
+func NewManager(keys KeySet, tokenTTL time.Duration, revoked RevocationList) *Manager {
 	return &Manager{
-		secret:   []byte(secret),
+		keys:     keys,
 		tokenTTL: tokenTTL,
+		revoked:  revoked,
 	}
 }
 
@@ -28,12 +36,21 @@ type jwtClaims struct {
 	Role     string `json:"role"`
 }
 
-func (m *Manager) GenerateJWT(user *domain.User) (string, error) {
+// GenerateJWT выпускает токен доступа с новым jti, подписанный текущим ключом
+func (m *Manager) GenerateJWT(user *domain.User) (*domain.AccessToken, error) {
+	key, err := m.keys.SigningKey()
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
+	id := uuid.New()
+	expiresAt := now.Add(m.tokenTTL)
 	claims := jwtClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.String(),
 			Subject:   user.ID.String(),
-			ExpiresAt: jwt.NewNumericDate(now.Add(m.tokenTTL)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 		UserID:   user.ID.String(),
@@ -41,20 +58,30 @@ func (m *Manager) GenerateJWT(user *domain.User) (string, error) {
 		Role:     string(user.Role),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secret)
+	unsigned := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		unsigned.Header["kid"] = key.ID
+	}
+	token, err := unsigned.SignedString(key.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	// в токене время хранится с точностью до секунды - так же возвращаем и здесь
+	return &domain.AccessToken{
+		Token:     token,
+		ID:        id,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
 }
 
 func (m *Manager) Validate(tokenStr string) (*domain.AuthClaims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenStr,
 		&jwtClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return m.secret, nil
-		},
+		m.verificationKey,
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}),
+		jwt.WithExpirationRequired(),
 	)
 
 	if err != nil {
@@ -66,6 +93,15 @@ func (m *Manager) Validate(tokenStr string) (*domain.AuthClaims, error) {
 		return nil, domain.ErrTokenInvalid
 	}
 
+	// без jti токен нельзя отозвать - такие (выпущенные до появления отзыва) не принимаются
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+	if m.revoked != nil && m.revoked.IsRevoked(tokenID) {
+		return nil, domain.ErrTokenInvalid
+	}
+
 	role, err := domain.ParseRole(claims.Role)
 	if err != nil {
 		return nil, domain.ErrTokenInvalid
@@ -77,8 +113,24 @@ func (m *Manager) Validate(tokenStr string) (*domain.AuthClaims, error) {
 	}
 
 	return &domain.AuthClaims{
-		UserID:   userID,
-		Username: claims.Username,
-		Role:     role,
+		UserID:    userID,
+		Username:  claims.Username,
+		Role:      role,
+		TokenID:   tokenID,
+		ExpiresAt: claims.ExpiresAt.Time,
 	}, nil
 }
+
+// verificationKey выбирает ключ по kid. Алгоритм токена должен совпадать с алгоритмом ключа,
+// иначе открытый ключ RS256 можно было бы выдать за секрет HS256.
+func (m *Manager) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys.VerificationKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
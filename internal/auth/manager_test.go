@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -11,7 +12,7 @@ import (
 )
 
 func TestManager_GenerateAndValidate(t *testing.T) {
-	m := NewManager("test-secret", time.Hour)
+	m := NewManager(NewHMACKeySet("test-secret"), time.Hour, nil)
 
 	user := &domain.User{
 		ID:       uuid.New(),
@@ -21,17 +22,60 @@ func TestManager_GenerateAndValidate(t *testing.T) {
 
 	token, err := m.GenerateJWT(user)
 	require.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, token.Token)
+	assert.NotEqual(t, uuid.Nil, token.ID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, 2*time.Second)
 
-	claims, err := m.Validate(token)
+	claims, err := m.Validate(token.Token)
 	require.NoError(t, err)
 	assert.Equal(t, user.ID, claims.UserID)
 	assert.Equal(t, "admin", claims.Username)
 	assert.Equal(t, domain.RoleAdmin, claims.Role)
+	assert.Equal(t, token.ID, claims.TokenID)
+	assert.Equal(t, token.ExpiresAt, claims.ExpiresAt)
+}
+
+type revokedSet map[uuid.UUID]bool
+
+func (s revokedSet) IsRevoked(jti uuid.UUID) bool { return s[jti] }
+
+func TestManager_ValidateRevokedToken(t *testing.T) {
+	revoked := revokedSet{}
+	m := NewManager(NewHMACKeySet("test-secret"), time.Hour, revoked)
+	user := &domain.User{ID: uuid.New(), Username: "admin", Role: domain.RoleAdmin}
+
+	token, err := m.GenerateJWT(user)
+	require.NoError(t, err)
+	other, err := m.GenerateJWT(user)
+	require.NoError(t, err)
+
+	revoked[token.ID] = true
+
+	_, err = m.Validate(token.Token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	_, err = m.Validate(other.Token)
+	assert.NoError(t, err)
+}
+
+// токены без jti выпускались до появления отзыва и отозвать их нельзя
+func TestManager_ValidateTokenWithoutID(t *testing.T) {
+	claims := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		UserID:   uuid.NewString(),
+		Username: "admin",
+		Role:     string(domain.RoleAdmin),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	_, err = NewManager(NewHMACKeySet("test-secret"), time.Hour, nil).Validate(token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
 }
 
 func TestManager_ValidateExpiredToken(t *testing.T) {
-	m := NewManager("test-secret", -time.Hour) // expired immediately
+	m := NewManager(NewHMACKeySet("test-secret"), -time.Hour, nil) // expired immediately
 
 	user := &domain.User{
 		ID:       uuid.New(),
@@ -42,20 +86,20 @@ func TestManager_ValidateExpiredToken(t *testing.T) {
 	token, err := m.GenerateJWT(user)
 	require.NoError(t, err)
 
-	_, err = m.Validate(token)
+	_, err = m.Validate(token.Token)
 	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
 }
 
 func TestManager_ValidateInvalidToken(t *testing.T) {
-	m := NewManager("test-secret", time.Hour)
+	m := NewManager(NewHMACKeySet("test-secret"), time.Hour, nil)
 
 	_, err := m.Validate("garbage-token")
 	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
 }
 
 func TestManager_ValidateWrongSecret(t *testing.T) {
-	m1 := NewManager("secret-1", time.Hour)
-	m2 := NewManager("secret-2", time.Hour)
+	m1 := NewManager(NewHMACKeySet("secret-1"), time.Hour, nil)
+	m2 := NewManager(NewHMACKeySet("secret-2"), time.Hour, nil)
 
 	user := &domain.User{
 		ID:       uuid.New(),
@@ -66,12 +110,12 @@ func TestManager_ValidateWrongSecret(t *testing.T) {
 	token, err := m1.GenerateJWT(user)
 	require.NoError(t, err)
 
-	_, err = m2.Validate(token)
+	_, err = m2.Validate(token.Token)
 	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
 }
 
 func TestManager_AllRoles(t *testing.T) {
-	m := NewManager("test-secret", time.Hour)
+	m := NewManager(NewHMACKeySet("test-secret"), time.Hour, nil)
 
 	roles := []domain.Role{domain.RoleAdmin, domain.RoleManager, domain.RoleViewer}
 	for _, role := range roles {
@@ -80,7 +124,7 @@ func TestManager_AllRoles(t *testing.T) {
 			token, err := m.GenerateJWT(user)
 			require.NoError(t, err)
 
-			claims, err := m.Validate(token)
+			claims, err := m.Validate(token.Token)
 			require.NoError(t, err)
 			assert.Equal(t, role, claims.Role)
 		})
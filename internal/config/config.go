@@ -15,6 +15,12 @@ type Config struct {
 	Gin      GinConfig      `yaml:"gin"`
 	Retry    RetryConfig    `yaml:"retry"`
 	Auth     AuthConfig     `yaml:"auth"`
+	Exports  ExportsConfig  `yaml:"exports"`
+	Audit    AuditConfig    `yaml:"audit"`
+	Events   EventsConfig   `yaml:"events"`
+	Presence PresenceConfig `yaml:"presence"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Webhooks WebhooksConfig `yaml:"webhooks"`
 }
 
 type ServerConfig struct {
@@ -77,8 +83,150 @@ func (p *PostgresConfig) DSN() string {
 }
 
 type AuthConfig struct {
-	JWTSecret string        `yaml:"secret" env:"AUTH_SECRET"`
-	TokenTTL  time.Duration `yaml:"ttl" env:"AUTH_TTL"`
+	JWTSecret string `yaml:"secret" env:"AUTH_SECRET"`
+	// TokenTTL - срок жизни токена доступа; отозванный токен отклоняется и раньше
+	TokenTTL   time.Duration `yaml:"ttl"         env:"AUTH_TTL"         env-default:"15m"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"AUTH_REFRESH_TTL" env-default:"720h"`
+	// DenylistSync - как часто подтягивать отзывы токенов, сделанные другими экземплярами
+	DenylistSync time.Duration `yaml:"denylist_sync" env:"AUTH_DENYLIST_SYNC" env-default:"5s"`
+	// BcryptCost - стоимость bcrypt для новых хешей паролей (4..31); уже сохранённые хеши проверяются со своей
+	BcryptCost int `yaml:"bcrypt_cost" env:"AUTH_BCRYPT_COST" env-default:"10"`
+
+	Signing AuthSigningConfig `yaml:"signing"`
+	OIDC    AuthOIDCConfig    `yaml:"oidc"`
+}
+
+// AuthSigningConfig - подпись токенов доступа. algorithm: HS256 (секрет auth.secret), RS256 или EdDSA.
+// Для RS256/EdDSA ключи хранятся в БД и ротируются раз в rotation_interval (0 - без ротации);
+// следующий ключ публикуется в /.well-known/jwks.json за publish_ahead до активации.
+// accept_legacy - при переходе с HS256 принимать выпущенные до него токены без kid, проверяя их секретом
+// auth.secret, - только в течение auth.ttl после активации первого ключа; по умолчанию не принимаются.
+// encryption_key - 32 байта в base64, которыми закрытые ключи шифруются в БД; обязателен для RS256/EdDSA.
+// С другим encryption_key сохранённые ключи не расшифровать: создаётся новый, выданные токены отклоняются
+type AuthSigningConfig struct {
+	Algorithm        string        `yaml:"algorithm"         env:"AUTH_SIGNING_ALGORITHM"         env-default:"HS256"`
+	RotationInterval time.Duration `yaml:"rotation_interval" env:"AUTH_SIGNING_ROTATION_INTERVAL" env-default:"720h"`
+	PublishAhead     time.Duration `yaml:"publish_ahead"     env:"AUTH_SIGNING_PUBLISH_AHEAD"     env-default:"1h"`
+	SyncInterval     time.Duration `yaml:"sync_interval"     env:"AUTH_SIGNING_SYNC_INTERVAL"     env-default:"1m"`
+	AcceptLegacy     bool          `yaml:"accept_legacy"     env:"AUTH_SIGNING_ACCEPT_LEGACY"`
+	EncryptionKey    string        `yaml:"encryption_key"    env:"AUTH_SIGNING_ENCRYPTION_KEY"`
+}
+
+// AuthOIDCConfig - единый вход через провайдера OpenID Connect; пустой issuer - вход только по паролю.
+// redirect_url - адрес веб-интерфейса, зарегистрированный у провайдера: туда он возвращает пользователя.
+// Роль пользователя - наибольшая из role_mapping по его группам (утверждение groups_claim ID token),
+// без сопоставленных групп - default_role; пустая default_role - такой пользователь не войдёт.
+// link_by_username - при первом входе привязать существующего пользователя с тем же логином
+type AuthOIDCConfig struct {
+	Name           string            `yaml:"name"             env:"AUTH_OIDC_NAME"             env-default:"SSO"`
+	Issuer         string            `yaml:"issuer"           env:"AUTH_OIDC_ISSUER"`
+	ClientID       string            `yaml:"client_id"        env:"AUTH_OIDC_CLIENT_ID"`
+	ClientSecret   string            `yaml:"client_secret"    env:"AUTH_OIDC_CLIENT_SECRET"`
+	RedirectURL    string            `yaml:"redirect_url"     env:"AUTH_OIDC_REDIRECT_URL"`
+	Scopes         []string          `yaml:"scopes"           env:"AUTH_OIDC_SCOPES"           env-separator:"," env-default:"openid,profile,email"`
+	UsernameClaim  string            `yaml:"username_claim"   env:"AUTH_OIDC_USERNAME_CLAIM"   env-default:"preferred_username"`
+	GroupsClaim    string            `yaml:"groups_claim"     env:"AUTH_OIDC_GROUPS_CLAIM"     env-default:"groups"`
+	RoleMapping    map[string]string `yaml:"role_mapping"     env:"AUTH_OIDC_ROLE_MAPPING"     env-separator:","`
+	DefaultRole    string            `yaml:"default_role"     env:"AUTH_OIDC_DEFAULT_ROLE"`
+	LinkByUsername bool              `yaml:"link_by_username" env:"AUTH_OIDC_LINK_BY_USERNAME"`
+	StateTTL       time.Duration     `yaml:"state_ttl"        env:"AUTH_OIDC_STATE_TTL"        env-default:"10m"`
+	Timeout        time.Duration     `yaml:"timeout"          env:"AUTH_OIDC_TIMEOUT"          env-default:"10s"`
+}
+
+// Enabled - единый вход настроен
+func (c AuthOIDCConfig) Enabled() bool { return c.Issuer != "" }
+
+// ExportsConfig - фоновые выгрузки (POST /api/exports)
+type ExportsConfig struct {
+	Dir             string        `yaml:"dir"              env:"EXPORTS_DIR"              env-default:"data/exports"`
+	Workers         int           `yaml:"workers"          env:"EXPORTS_WORKERS"          env-default:"2"`
+	TTL             time.Duration `yaml:"ttl"              env:"EXPORTS_TTL"              env-default:"24h"`
+	PollInterval    time.Duration `yaml:"poll_interval"    env:"EXPORTS_POLL_INTERVAL"    env-default:"2s"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"EXPORTS_CLEANUP_INTERVAL" env-default:"10m"`
+}
+
+// EventsConfig - лента изменений (GET /api/events)
+type EventsConfig struct {
+	Heartbeat    time.Duration `yaml:"heartbeat"     env:"EVENTS_HEARTBEAT"     env-default:"25s"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"EVENTS_POLL_INTERVAL" env-default:"5s"`
+	Buffer       int           `yaml:"buffer"        env:"EVENTS_BUFFER"        env-default:"256"`
+	ReplayLimit  int           `yaml:"replay_limit"  env:"EVENTS_REPLAY_LIMIT"  env-default:"1000"`
+}
+
+// PresenceConfig - канал присутствия (WebSocket /api/ws/presence)
+type PresenceConfig struct {
+	Heartbeat time.Duration `yaml:"heartbeat" env:"PRESENCE_HEARTBEAT" env-default:"30s"`
+	Buffer    int           `yaml:"buffer"    env:"PRESENCE_BUFFER"    env-default:"64"`
+	MaxItems  int           `yaml:"max_items" env:"PRESENCE_MAX_ITEMS" env-default:"50"`
+}
+
+// OutboxConfig - доставка событий об изменениях товаров во внешние системы (таблица outbox).
+// publisher: log, file, http или kafka
+type OutboxConfig struct {
+	Publisher       string        `yaml:"publisher"        env:"OUTBOX_PUBLISHER"        env-default:"log"`
+	BatchSize       int           `yaml:"batch_size"       env:"OUTBOX_BATCH_SIZE"       env-default:"100"`
+	PollInterval    time.Duration `yaml:"poll_interval"    env:"OUTBOX_POLL_INTERVAL"    env-default:"1s"`
+	Lease           time.Duration `yaml:"lease"            env:"OUTBOX_LEASE"            env-default:"1m"`
+	MaxBackoff      time.Duration `yaml:"max_backoff"      env:"OUTBOX_MAX_BACKOFF"      env-default:"10m"`
+	Retention       time.Duration `yaml:"retention"        env:"OUTBOX_RETENTION"        env-default:"168h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"OUTBOX_CLEANUP_INTERVAL" env-default:"1h"`
+	// повторы отправки одного события, прежде чем отложить его до следующей попытки
+	RetryAttempts int           `yaml:"retry_attempts" env:"OUTBOX_RETRY_ATTEMPTS" env-default:"3"`
+	RetryDelay    time.Duration `yaml:"retry_delay"    env:"OUTBOX_RETRY_DELAY"    env-default:"200ms"`
+	RetryBackoff  float64       `yaml:"retry_backoff"  env:"OUTBOX_RETRY_BACKOFF"  env-default:"2"`
+	// остаток, при падении до которого пишется событие item.low_stock; -1 - не писать
+	LowStockThreshold int `yaml:"low_stock_threshold" env:"OUTBOX_LOW_STOCK_THRESHOLD" env-default:"10"`
+
+	File  OutboxFileConfig  `yaml:"file"`
+	HTTP  OutboxHTTPConfig  `yaml:"http"`
+	Kafka OutboxKafkaConfig `yaml:"kafka"`
+}
+
+type OutboxFileConfig struct {
+	Path string `yaml:"path" env:"OUTBOX_FILE_PATH" env-default:"data/outbox/events.jsonl"`
+}
+
+type OutboxHTTPConfig struct {
+	URL     string        `yaml:"url"     env:"OUTBOX_HTTP_URL"`
+	Timeout time.Duration `yaml:"timeout" env:"OUTBOX_HTTP_TIMEOUT" env-default:"10s"`
+}
+
+type OutboxKafkaConfig struct {
+	Brokers []string `yaml:"brokers" env:"OUTBOX_KAFKA_BROKERS" env-separator:","`
+	Topic   string   `yaml:"topic"   env:"OUTBOX_KAFKA_TOPIC"   env-default:"warehouse.items"`
+}
+
+// WebhooksConfig - доставка исходящих webhook (подписки - /api/webhooks).
+// lease должен покрывать batch_size таймаутов запроса, иначе пачку начнёт отправлять второй экземпляр
+type WebhooksConfig struct {
+	Workers         int           `yaml:"workers"          env:"WEBHOOKS_WORKERS"          env-default:"4"`
+	BatchSize       int           `yaml:"batch_size"       env:"WEBHOOKS_BATCH_SIZE"       env-default:"10"`
+	PollInterval    time.Duration `yaml:"poll_interval"    env:"WEBHOOKS_POLL_INTERVAL"    env-default:"1s"`
+	Lease           time.Duration `yaml:"lease"            env:"WEBHOOKS_LEASE"            env-default:"5m"`
+	Timeout         time.Duration `yaml:"timeout"          env:"WEBHOOKS_TIMEOUT"          env-default:"10s"`
+	MaxAttempts     int           `yaml:"max_attempts"     env:"WEBHOOKS_MAX_ATTEMPTS"     env-default:"8"`
+	RetryDelay      time.Duration `yaml:"retry_delay"      env:"WEBHOOKS_RETRY_DELAY"      env-default:"30s"`
+	MaxBackoff      time.Duration `yaml:"max_backoff"      env:"WEBHOOKS_MAX_BACKOFF"      env-default:"1h"`
+	Retention       time.Duration `yaml:"retention"        env:"WEBHOOKS_RETENTION"        env-default:"720h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"WEBHOOKS_CLEANUP_INTERVAL" env-default:"1h"`
+	// AllowedNetworks - внутренние сети и адреса (CIDR или IP), куда разрешена доставка;
+	// остальные loopback, link-local и частные адреса запрещены
+	AllowedNetworks []string `yaml:"allowed_networks" env:"WEBHOOKS_ALLOWED_NETWORKS" env-separator:","`
+}
+
+// AuditConfig - кто пишет журнал аудита: trigger (fn_audit_row) или app (приложение), и сколько он хранится в БД
+type AuditConfig struct {
+	Mode      string               `yaml:"mode" env:"AUDIT_MODE" env-default:"trigger"`
+	Retention AuditRetentionConfig `yaml:"retention"`
+}
+
+// AuditRetentionConfig - месячные секции audit_log старше months выгружаются в archive_dir и удаляются из БД.
+// months: 0 - журнал хранится в БД целиком
+type AuditRetentionConfig struct {
+	Months       int           `yaml:"months"        env:"AUDIT_RETENTION_MONTHS"        env-default:"0"`
+	ArchiveDir   string        `yaml:"archive_dir"   env:"AUDIT_ARCHIVE_DIR"             env-default:"data/audit-archive"`
+	Interval     time.Duration `yaml:"interval"      env:"AUDIT_RETENTION_INTERVAL"      env-default:"1h"`
+	ReattachHold time.Duration `yaml:"reattach_hold" env:"AUDIT_RETENTION_REATTACH_HOLD" env-default:"168h"`
 }
 
 func MustLoad() *Config {
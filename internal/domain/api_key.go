@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey - ключ интеграции (заголовок X-API-Key). Действует от имени владельца UserID
+// с ролью Role, но не больше текущей роли владельца. Сам ключ не хранится - только KeyHash;
+// Prefix - открытое начало ключа для опознания в списке.
+type APIKey struct {
+	ID         uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	UserID     uuid.UUID
+	Role       Role
+	ReadOnly   bool
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// Key - сам ключ; заполняется только при создании
+	Key string
+	// Owner - владелец; заполняется только при проверке ключа
+	Owner *User
+}
+
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(now)
+}
+
+// CreateAPIKeyInput - новый ключ. UserID nil - владелец сам администратор,
+// пустая Role - роль владельца; ExpiresAt nil - бессрочный
+type CreateAPIKeyInput struct {
+	Name      string
+	UserID    *uuid.UUID
+	Role      Role
+	ReadOnly  bool
+	ExpiresAt *time.Time
+}
+
+// UpdateAPIKeyInput - частичное изменение ключа; nil - поле не меняется
+type UpdateAPIKeyInput struct {
+	Name      *string
+	Role      *Role
+	ReadOnly  *bool
+	ExpiresAt *time.Time
+}
@@ -1,10 +1,12 @@
 package domain
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type AuditAction string
@@ -23,16 +25,110 @@ func (a AuditAction) IsValid() bool {
 	return false
 }
 
-// AuditEntry - одна запись из item_audit_log
+// AuditEntity - тип сущности, к которой относится запись аудита
+type AuditEntity string
+
+const (
+	AuditEntityItem AuditEntity = "item"
+	AuditEntityUser AuditEntity = "user"
+)
+
+func (e AuditEntity) IsValid() bool {
+	switch e {
+	case AuditEntityItem, AuditEntityUser:
+		return true
+	}
+	return false
+}
+
+// AuditMode - кто пишет audit_log
+type AuditMode string
+
+const (
+	// AuditModeTrigger - триггер fn_audit_row() в PostgreSQL
+	AuditModeTrigger AuditMode = "trigger"
+	// AuditModeApp - приложение, в той же транзакции, что и изменение
+	AuditModeApp AuditMode = "app"
+)
+
+func (m AuditMode) IsValid() bool {
+	switch m {
+	case AuditModeTrigger, AuditModeApp:
+		return true
+	}
+	return false
+}
+
+// AuditReason - зачем сделано изменение: свободный текст и ссылка на документ (номер накладной и т.п.).
+// Оба поля необязательные, попадают во все записи аудита одного запроса.
+type AuditReason struct {
+	Reason    string
+	Reference string
+}
+
+type auditReasonKey struct{}
+
+// WithAuditReason кладёт причину изменения в контекст запроса
+func WithAuditReason(ctx context.Context, r AuditReason) context.Context {
+	return context.WithValue(ctx, auditReasonKey{}, r)
+}
+
+// AuditReasonFromContext - причина изменения из контекста; если её нет - пустая
+func AuditReasonFromContext(ctx context.Context) AuditReason {
+	r, _ := ctx.Value(auditReasonKey{}).(AuditReason)
+	return r
+}
+
+// AuditRequest - HTTP-запрос, из которого сделано изменение: по request ID
+// запись аудита находится по строке лога и наоборот
+type AuditRequest struct {
+	RequestID string
+	ClientIP  string
+	UserAgent string
+}
+
+type auditRequestKey struct{}
+
+// WithAuditRequest кладёт данные запроса в контекст
+func WithAuditRequest(ctx context.Context, r AuditRequest) context.Context {
+	return context.WithValue(ctx, auditRequestKey{}, r)
+}
+
+// AuditRequestFromContext - данные запроса из контекста; вне HTTP-запроса (CLI, воркеры) пустые
+func AuditRequestFromContext(ctx context.Context) AuditRequest {
+	r, _ := ctx.Value(auditRequestKey{}).(AuditRequest)
+	return r
+}
+
+// AuditChange - изменение сущности для записи в журнал: снимки строки до и после
+// в том виде, в каком их даёт to_jsonb(). Для INSERT OldData пуст, для DELETE - NewData.
+type AuditChange struct {
+	EntityType AuditEntity
+	EntityID   uuid.UUID
+	Action     AuditAction
+	ChangedBy  uuid.UUID
+	OldData    json.RawMessage
+	NewData    json.RawMessage
+	Reason     AuditReason
+	Request    AuditRequest
+}
+
+// AuditEntry - одна запись из audit_log
 type AuditEntry struct {
-	ID        int64           `json:"id"         db:"id"`
-	ItemID    uuid.UUID       `json:"item_id"    db:"item_id"`
-	Action    AuditAction     `json:"action"     db:"action"`
-	ChangedBy uuid.UUID       `json:"changed_by" db:"changed_by"`
-	OldData   json.RawMessage `json:"old_data"   db:"old_data"`
-	NewData   json.RawMessage `json:"new_data"   db:"new_data"`
-	Diff      json.RawMessage `json:"diff"       db:"diff"`
-	ChangedAt time.Time       `json:"changed_at" db:"changed_at"`
+	ID         int64           `json:"id"          db:"id"`
+	EntityType AuditEntity     `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"   db:"entity_id"`
+	Action     AuditAction     `json:"action"      db:"action"`
+	ChangedBy  uuid.UUID       `json:"changed_by"  db:"changed_by"`
+	OldData    json.RawMessage `json:"old_data"    db:"old_data"`
+	NewData    json.RawMessage `json:"new_data"    db:"new_data"`
+	Diff       json.RawMessage `json:"diff"        db:"diff"`
+	Reason     *string         `json:"reason"      db:"reason"`
+	Reference  *string         `json:"reference"   db:"reference"`
+	RequestID  *string         `json:"request_id"  db:"request_id"`
+	ClientIP   *string         `json:"client_ip"   db:"client_ip"`
+	UserAgent  *string         `json:"user_agent"  db:"user_agent"`
+	ChangedAt  time.Time       `json:"changed_at"  db:"changed_at"`
 }
 
 // AuditEntryWithUser - запись аудита с именем пользователя (для отображения)
@@ -74,13 +170,126 @@ func (a *AuditEntry) ParseDiff() ([]FieldChange, error) {
 	return changes, nil
 }
 
-// AuditFilter - фильтрация истории изменений
+// AuditValueSide - какое значение поля из diff сравнивается: до, после или их разность
+type AuditValueSide string
+
+const (
+	AuditValueOld   AuditValueSide = "old"
+	AuditValueNew   AuditValueSide = "new"
+	AuditValueDelta AuditValueSide = "delta"
+)
+
+func (s AuditValueSide) IsValid() bool {
+	switch s {
+	case AuditValueOld, AuditValueNew, AuditValueDelta:
+		return true
+	}
+	return false
+}
+
+// AuditCompareOp - оператор сравнения в условии на значение поля
+type AuditCompareOp string
+
+const (
+	AuditOpEq  AuditCompareOp = "eq"
+	AuditOpNe  AuditCompareOp = "ne"
+	AuditOpGt  AuditCompareOp = "gt"
+	AuditOpGte AuditCompareOp = "gte"
+	AuditOpLt  AuditCompareOp = "lt"
+	AuditOpLte AuditCompareOp = "lte"
+)
+
+func (o AuditCompareOp) IsValid() bool {
+	switch o {
+	case AuditOpEq, AuditOpNe, AuditOpGt, AuditOpGte, AuditOpLt, AuditOpLte:
+		return true
+	}
+	return false
+}
+
+// IsOrdering - оператор сравнивает по порядку, а не на равенство
+func (o AuditCompareOp) IsOrdering() bool {
+	return o != AuditOpEq && o != AuditOpNe
+}
+
+// AuditValuePredicate - условие на изменение поля в diff, например quantity.delta.lt.-10:
+// остаток уменьшился больше чем на 10. Подходят только записи, где поле изменилось.
+type AuditValuePredicate struct {
+	Field string         `json:"field"`
+	Side  AuditValueSide `json:"side"`
+	Op    AuditCompareOp `json:"op"`
+	Value string         `json:"value"`
+}
+
+// IsNumeric - значение условия - число; тогда и значение поля сравнивается как число
+func (p *AuditValuePredicate) IsNumeric() bool {
+	_, err := decimal.NewFromString(p.Value)
+	return err == nil
+}
+
+// IsValid - разность и сравнение по порядку имеют смысл только для чисел
+func (p *AuditValuePredicate) IsValid() bool {
+	if !IsAuditFieldName(p.Field) || !p.Side.IsValid() || !p.Op.IsValid() {
+		return false
+	}
+	if (p.Side == AuditValueDelta || p.Op.IsOrdering()) && !p.IsNumeric() {
+		return false
+	}
+	return true
+}
+
+// IsAuditFieldName - имя поля в diff: имя колонки, латиница в нижнем регистре, цифры и _
+func IsAuditFieldName(s string) bool {
+	if s == "" || len(s) > 63 {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AuditFilter - фильтрация истории изменений.
+// ItemID - то же, что EntityType=item и EntityID, оставлен для совместимости.
+// Reason ищется как подстрока без учёта регистра, Reference - точное совпадение.
+// Field - в diff есть это поле, Changes - все условия на значения полей выполняются
 type AuditFilter struct {
-	ItemID   *uuid.UUID   `json:"item_id"`
-	UserID   *uuid.UUID   `json:"user_id"`
-	Action   *AuditAction `json:"action"`
-	DateFrom *time.Time   `json:"date_from"`
-	DateTo   *time.Time   `json:"date_to"`
+	EntityType *AuditEntity          `json:"entity_type,omitempty"`
+	EntityID   *uuid.UUID            `json:"entity_id,omitempty"`
+	ItemID     *uuid.UUID            `json:"item_id"`
+	UserID     *uuid.UUID            `json:"user_id"`
+	Action     *AuditAction          `json:"action"`
+	Reason     *string               `json:"reason,omitempty"`
+	Reference  *string               `json:"reference,omitempty"`
+	RequestID  *string               `json:"request_id,omitempty"`
+	Field      *string               `json:"field,omitempty"`
+	Changes    []AuditValuePredicate `json:"changes,omitempty"`
+	DateFrom   *time.Time            `json:"date_from"`
+	DateTo     *time.Time            `json:"date_to"`
+}
+
+// IsValid проверяет значения перечислений и условий фильтра
+func (f *AuditFilter) IsValid() bool {
+	if f.Action != nil && !f.Action.IsValid() {
+		return false
+	}
+	if f.EntityType != nil && !f.EntityType.IsValid() {
+		return false
+	}
+	if f.Field != nil && !IsAuditFieldName(*f.Field) {
+		return false
+	}
+	for i := range f.Changes {
+		if !f.Changes[i].IsValid() {
+			return false
+		}
+	}
+	return true
 }
 
 // AuditList - результат постраничного запроса аудита.
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditArchiveStatus - где сейчас записи архивной секции
+type AuditArchiveStatus string
+
+const (
+	// секция выгружена в файл и удалена из БД
+	AuditArchiveArchived AuditArchiveStatus = "archived"
+	// секция восстановлена из файла и снова присоединена к audit_log
+	AuditArchiveAttached AuditArchiveStatus = "attached"
+)
+
+// AuditPartition - месячная секция audit_log с записями за [From, To)
+type AuditPartition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// AuditArchive - секция audit_log, выгруженная в файл (JSON Lines + gzip).
+// Segments - отрезки цепочки хешей, которые составляли записи секции: по ним проверка цепочки
+// проходит через записи, которых нет в БД.
+type AuditArchive struct {
+	ID         int64
+	Partition  string
+	RangeFrom  time.Time
+	RangeTo    time.Time
+	FileName   string
+	FileSize   int64
+	FileSHA256 string
+	Rows       int64
+	Segments   []AuditChainSegment
+	Status     AuditArchiveStatus
+	ArchivedAt time.Time
+	AttachedAt *time.Time
+	AttachedBy *uuid.UUID
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AuditChainGenesis - prev_hash первой записи цепочки
+const AuditChainGenesis = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditChainLink - запись аудита вместе с её звеном цепочки хешей.
+// Archived > 0 - вместо записи отрезок цепочки из архива: столько записей с ID по AuditEntry.ID
+// вынесено в файл, PrevHash - prev_hash первой из них, Hash - хеш последней.
+type AuditChainLink struct {
+	AuditEntry
+	PrevHash string
+	Hash     string
+	Archived int64
+}
+
+// AuditChainSegment - записи с FirstID по LastID, идущие в цепочке подряд
+type AuditChainSegment struct {
+	FirstID  int64  `json:"first_id"`
+	LastID   int64  `json:"last_id"`
+	Rows     int64  `json:"rows"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditChainHead - последняя запись цепочки по данным audit_chain_head
+type AuditChainHead struct {
+	LastID   int64
+	LastHash string
+}
+
+// AuditChainAnchor - звено цепочки, сохранённое оператором вне БД: id записи и её хеш.
+// Цепочка и её голова лежат в той же БД, и пользователь с правом записи может пересчитать
+// все хеши после правки или удалить хвост вместе с головой. Такую подмену видно только
+// по звену, которое записано где-то ещё.
+type AuditChainAnchor struct {
+	EntryID int64
+	Hash    string
+}
+
+func (a AuditChainAnchor) String() string {
+	return strconv.FormatInt(a.EntryID, 10) + ":" + a.Hash
+}
+
+// ParseAuditChainAnchor разбирает звено в виде "<id>:<hash>" - так его печатает проверка
+func ParseAuditChainAnchor(s string) (*AuditChainAnchor, error) {
+	id, hash, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("anchor must be <id>:<hash>")
+	}
+	entryID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || entryID <= 0 {
+		return nil, fmt.Errorf("anchor id must be a positive integer")
+	}
+	if len(hash) != len(AuditChainGenesis) || strings.ToLower(hash) != hash {
+		return nil, fmt.Errorf("anchor hash must be %d lowercase hex characters", len(AuditChainGenesis))
+	}
+	if _, err = hex.DecodeString(hash); err != nil {
+		return nil, fmt.Errorf("anchor hash must be %d lowercase hex characters", len(AuditChainGenesis))
+	}
+	return &AuditChainAnchor{EntryID: entryID, Hash: hash}, nil
+}
+
+// AuditChainBreakReason - чем именно нарушена цепочка
+type AuditChainBreakReason string
+
+const (
+	// содержимое записи не соответствует её хешу - запись изменена
+	AuditChainHashMismatch AuditChainBreakReason = "hash_mismatch"
+	// prev_hash не совпадает с хешем предыдущей записи - запись удалена или вставлена
+	AuditChainPrevHashMismatch AuditChainBreakReason = "prev_hash_mismatch"
+	// последняя запись не совпадает с головой цепочки - удалён хвост
+	AuditChainHeadMismatch AuditChainBreakReason = "head_mismatch"
+	// хеш записи не совпадает с сохранённым звеном или записи нет - цепочка пересчитана или обрезана
+	AuditChainAnchorMismatch AuditChainBreakReason = "anchor_mismatch"
+)
+
+// AuditChainBreak - первое нарушенное звено
+type AuditChainBreak struct {
+	EntryID  int64
+	Reason   AuditChainBreakReason
+	Expected string
+	Actual   string
+}
+
+// AuditChainReport - результат проверки цепочки аудита
+// Archived - сколько записей пройдено по отрезкам из архивов, без пересчёта хешей.
+// LastID и LastHash - последнее проверенное звено: его стоит сохранить вне БД и передавать
+// следующим проверкам как AuditChainAnchor.
+// AnchorVerified - переданное звено найдено и совпало; false при валидной цепочке - звено внутри
+// отрезка из архива, и сверить его можно только по файлу архива.
+type AuditChainReport struct {
+	Valid          bool
+	Checked        int64
+	Archived       int64
+	LastID         int64
+	LastHash       string
+	AnchorVerified bool
+	Broken         *AuditChainBreak
+}
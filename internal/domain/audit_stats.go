@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// AuditStatsDefaultDays - период статистики, если даты не заданы
+	AuditStatsDefaultDays = 30
+	// AuditStatsMaxDays - ограничение периода: по дням строится разбивка
+	AuditStatsMaxDays = 366
+
+	AuditStatsDefaultLimit = 10
+	AuditStatsMaxLimit     = 100
+)
+
+// AuditStatsFilter - период [DateFrom, DateTo) и длина топов
+type AuditStatsFilter struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	Limit    int
+}
+
+// AuditStats - агрегаты журнала аудита за период.
+// Daily и TopUsers считаются по всем сущностям, остальное - по товарам.
+type AuditStats struct {
+	DateFrom        time.Time
+	DateTo          time.Time
+	Daily           []*AuditDailyStat
+	TopUsers        []*AuditUserStat
+	TopItems        []*AuditItemStat
+	TopFields       []*AuditFieldStat
+	QuantityChanges []*AuditQuantityStat
+}
+
+// AuditDailyStat - число изменений за день (UTC) по действиям
+type AuditDailyStat struct {
+	Day     time.Time
+	Inserts int64
+	Updates int64
+	Deletes int64
+}
+
+// AuditUserStat - сколько изменений сделал пользователь
+type AuditUserStat struct {
+	UserID   uuid.UUID
+	Username string
+	Changes  int64
+}
+
+// AuditItemStat - сколько раз менялся товар. Name - текущее имя, у удалённого товара - последнее из журнала
+type AuditItemStat struct {
+	ItemID  uuid.UUID
+	Name    string
+	Changes int64
+}
+
+// AuditFieldStat - сколько раз менялось поле товара
+type AuditFieldStat struct {
+	Field   string
+	Changes int64
+}
+
+// AuditQuantityStat - суммарное изменение остатка товара за период:
+// создание с начальным остатком, правки quantity и удаление с остатком на момент удаления
+type AuditQuantityStat struct {
+	ItemID uuid.UUID
+	Name   string
+	Net    int64
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,6 +18,56 @@ func TestAuditAction_IsValid(t *testing.T) {
 	assert.False(t, AuditAction("").IsValid())
 }
 
+func TestAuditReasonContext(t *testing.T) {
+	assert.Equal(t, AuditReason{}, AuditReasonFromContext(context.Background()))
+
+	r := AuditReason{Reason: "пересорт", Reference: "INV-1"}
+	ctx := WithAuditReason(context.Background(), r)
+	assert.Equal(t, r, AuditReasonFromContext(ctx))
+}
+
+func TestAuditValuePredicate_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		p    AuditValuePredicate
+		want bool
+	}{
+		{"delta number", AuditValuePredicate{"quantity", AuditValueDelta, AuditOpLt, "-10"}, true},
+		{"new decimal", AuditValuePredicate{"price", AuditValueNew, AuditOpGte, "1000.50"}, true},
+		{"old text eq", AuditValuePredicate{"location", AuditValueOld, AuditOpEq, "Склад 1"}, true},
+		{"text ordering", AuditValuePredicate{"name", AuditValueNew, AuditOpGt, "abc"}, false},
+		{"text delta", AuditValuePredicate{"name", AuditValueDelta, AuditOpEq, "abc"}, false},
+		{"bad side", AuditValuePredicate{"quantity", AuditValueSide("mid"), AuditOpEq, "1"}, false},
+		{"bad op", AuditValuePredicate{"quantity", AuditValueNew, AuditCompareOp("like"), "1"}, false},
+		{"bad field", AuditValuePredicate{"quantity'--", AuditValueNew, AuditOpEq, "1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.p.IsValid())
+		})
+	}
+}
+
+func TestIsAuditFieldName(t *testing.T) {
+	assert.True(t, IsAuditFieldName("quantity"))
+	assert.True(t, IsAuditFieldName("password_hash"))
+	assert.True(t, IsAuditFieldName("field2"))
+	assert.False(t, IsAuditFieldName(""))
+	assert.False(t, IsAuditFieldName("2field"))
+	assert.False(t, IsAuditFieldName("Price"))
+	assert.False(t, IsAuditFieldName("a.b"))
+}
+
+func TestAuditFilter_IsValid(t *testing.T) {
+	field := "price"
+	badAction := AuditAction("UPSERT")
+
+	assert.True(t, (&AuditFilter{}).IsValid())
+	assert.True(t, (&AuditFilter{Field: &field}).IsValid())
+	assert.False(t, (&AuditFilter{Action: &badAction}).IsValid())
+	assert.False(t, (&AuditFilter{Changes: []AuditValuePredicate{{"name", AuditValueDelta, AuditOpLt, "x"}}}).IsValid())
+}
+
 func TestAuditEntry_ParseDiff_Success(t *testing.T) {
 	diff := json.RawMessage(`{
 		"name": {"old": "Laptop", "new": "Gaming Laptop"},
@@ -59,3 +111,26 @@ func TestAuditEntry_ParseDiff_InvalidJSON(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestParseAuditChainAnchor(t *testing.T) {
+	hash := strings.Repeat("0f", 32)
+
+	a, err := ParseAuditChainAnchor("42:" + hash)
+	require.NoError(t, err)
+	assert.Equal(t, &AuditChainAnchor{EntryID: 42, Hash: hash}, a)
+	assert.Equal(t, "42:"+hash, a.String())
+
+	for _, s := range []string{
+		"",
+		hash,
+		"0:" + hash,
+		"-1:" + hash,
+		"x:" + hash,
+		"42:" + strings.ToUpper(hash),
+		"42:" + hash[:62],
+		"42:" + strings.Repeat("zz", 32),
+	} {
+		_, err = ParseAuditChainAnchor(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+func (o BatchOp) IsValid() bool {
+	switch o {
+	case BatchCreate, BatchUpdate, BatchDelete:
+		return true
+	}
+	return false
+}
+
+// BatchMode - режим применения пачки операций
+type BatchMode string
+
+const (
+	// BatchAtomic - всё или ничего: первая ошибка откатывает всю пачку
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort - ошибочные строки пропускаются, остальные применяются
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+func (m BatchMode) IsValid() bool {
+	switch m {
+	case BatchAtomic, BatchBestEffort:
+		return true
+	}
+	return false
+}
+
+// BatchOperation - одна операция из POST /api/items/batch.
+// Create заполняется для create, ID - для update/delete, Update - для update.
+// Invalid - ошибка проверки полей строки при разборе запроса: такая операция не выполняется,
+// ошибка становится ошибкой строки (в атомарном режиме - с её индексом).
+type BatchOperation struct {
+	Op      BatchOp
+	ID      *uuid.UUID
+	Create  *CreateItemInput
+	Update  *UpdateItemInput
+	Invalid error
+}
+
+// Validate - проверка, что для операции переданы нужные поля
+func (o *BatchOperation) Validate() error {
+	if o.Invalid != nil {
+		return o.Invalid
+	}
+
+	switch o.Op {
+	case BatchCreate:
+		if o.Create == nil {
+			return &ValidationError{Field: "item", Reason: "is required"}
+		}
+	case BatchUpdate:
+		if o.ID == nil {
+			return &ValidationError{Field: "id", Reason: "is required"}
+		}
+		if o.Update == nil {
+			return &ValidationError{Field: "changes", Reason: "is required"}
+		}
+		if !o.Update.HasChanges() {
+			return ErrNoChanges
+		}
+	case BatchDelete:
+		if o.ID == nil {
+			return &ValidationError{Field: "id", Reason: "is required"}
+		}
+	default:
+		return ErrValidation
+	}
+	return nil
+}
+
+// BatchItemResult - результат одной операции пачки
+type BatchItemResult struct {
+	Index int
+	Op    BatchOp
+	ID    *uuid.UUID
+	Item  *Item
+	Err   error
+}
+
+// BatchResult - итог применения пачки
+type BatchResult struct {
+	Mode      BatchMode
+	Results   []*BatchItemResult
+	Succeeded int
+	Failed    int
+}
+
+// BatchOpError - ошибка конкретной операции, оборвавшая атомарную пачку
+type BatchOpError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchOpError) Error() string {
+	return fmt.Sprintf("batch operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchOpError) Unwrap() error {
+	return e.Err
+}
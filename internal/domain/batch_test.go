@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchOp_IsValid(t *testing.T) {
+	assert.True(t, BatchCreate.IsValid())
+	assert.True(t, BatchUpdate.IsValid())
+	assert.True(t, BatchDelete.IsValid())
+	assert.False(t, BatchOp("upsert").IsValid())
+}
+
+func TestBatchMode_IsValid(t *testing.T) {
+	assert.True(t, BatchAtomic.IsValid())
+	assert.True(t, BatchBestEffort.IsValid())
+	assert.False(t, BatchMode("partial").IsValid())
+}
+
+func TestBatchOperation_Validate(t *testing.T) {
+	id := uuid.New()
+	name := "New Name"
+
+	tests := []struct {
+		name    string
+		op      BatchOperation
+		wantErr error
+	}{
+		{"create ok", BatchOperation{Op: BatchCreate, Create: &CreateItemInput{Name: "x", SKU: "y"}}, nil},
+		{"create without item", BatchOperation{Op: BatchCreate}, ErrValidation},
+		{"update ok", BatchOperation{Op: BatchUpdate, ID: &id, Update: &UpdateItemInput{Name: &name}}, nil},
+		{"update without id", BatchOperation{Op: BatchUpdate, Update: &UpdateItemInput{Name: &name}}, ErrValidation},
+		{"update without changes", BatchOperation{Op: BatchUpdate, ID: &id, Update: &UpdateItemInput{}}, ErrNoChanges},
+		{"delete ok", BatchOperation{Op: BatchDelete, ID: &id}, nil},
+		{"delete without id", BatchOperation{Op: BatchDelete}, ErrValidation},
+		{"unknown op", BatchOperation{Op: "upsert"}, ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.op.Validate()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBatchOpError_Unwrap(t *testing.T) {
+	err := &BatchOpError{Index: 3, Err: ErrDuplicateSKU}
+
+	assert.ErrorIs(t, err, ErrDuplicateSKU)
+	assert.Contains(t, err.Error(), "batch operation 3")
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// Общие
@@ -11,6 +14,14 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrTokenExpired       = errors.New("token expired")
 	ErrTokenInvalid       = errors.New("invalid token")
+	ErrUserDisabled       = errors.New("user is disabled")
+	// refresh-токен уже обменян - его предъявляют повторно
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// провайдер единого входа (OIDC) недоступен или ответил некорректно
+	ErrIdentityProvider = errors.New("identity provider error")
+
+	// Управление пользователями
+	ErrOwnAccount = errors.New("operation is not allowed on own account")
 
 	// Права доступа
 	ErrForbidden = errors.New("forbidden: insufficient permissions")
@@ -19,4 +30,31 @@ var (
 	ErrValidation   = errors.New("validation error")
 	ErrNoChanges    = errors.New("no changes provided")
 	ErrDuplicateSKU = errors.New("item with this SKU already exists")
+
+	// Остатки
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	// Фоновые выгрузки
+	ErrExportNotReady = errors.New("export is not ready")
+
+	// Архивы аудита
+	ErrAuditArchiveAttached  = errors.New("audit archive is already attached")
+	ErrAuditArchiveCorrupted = errors.New("audit archive file is missing or corrupted")
+
+	// Лента изменений
+	ErrEventsUnavailable = errors.New("event feed is unavailable")
 )
+
+// ValidationError - ошибка валидации конкретного поля с пояснением для клиента
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,43 @@
+package domain
+
+// ChangeEvent - событие ленты изменений: новая запись аудита.
+// Detailed - подписчику доступен журнал аудита (автор, diff, причина);
+// иначе ему отдаётся только новое состояние товара.
+type ChangeEvent struct {
+	Entry    *AuditEntryWithUser
+	Detailed bool
+}
+
+// ChangeEventFor - событие записи e для роли r или nil, если роль его не видит.
+// Записи по пользователям видны только тем, кому доступен журнал аудита.
+func ChangeEventFor(r Role, e *AuditEntryWithUser) *ChangeEvent {
+	if r.CanViewAudit() {
+		return &ChangeEvent{Entry: e, Detailed: true}
+	}
+	if e.EntityType == AuditEntityItem && r.CanView() {
+		return &ChangeEvent{Entry: e}
+	}
+	return nil
+}
+
+// EventSubscription - подписка на ленту изменений. Сначала отдаются Replay, затем события из Events;
+// Events закрывается при остановке ленты или если подписчик не успевает их забирать.
+type EventSubscription struct {
+	// Replay - события после Last-Event-ID, пропущенные клиентом
+	Replay []*ChangeEvent
+	// Reset - пропущенных событий слишком много или Last-Event-ID неизвестен:
+	// клиенту нужно перечитать данные целиком
+	Reset  bool
+	Events <-chan *ChangeEvent
+
+	close func()
+}
+
+func NewEventSubscription(events <-chan *ChangeEvent, close func()) *EventSubscription {
+	return &EventSubscription{Events: events, close: close}
+}
+
+// Close отписывается от ленты
+func (s *EventSubscription) Close() {
+	s.close()
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeEventFor(t *testing.T) {
+	item := &AuditEntryWithUser{AuditEntry: AuditEntry{ID: 1, EntityType: AuditEntityItem}}
+	user := &AuditEntryWithUser{AuditEntry: AuditEntry{ID: 2, EntityType: AuditEntityUser}}
+
+	tests := []struct {
+		role         Role
+		entry        *AuditEntryWithUser
+		wantVisible  bool
+		wantDetailed bool
+	}{
+		{RoleAdmin, item, true, true},
+		{RoleAdmin, user, true, true},
+		{RoleManager, item, true, true},
+		{RoleManager, user, true, true},
+		{RoleViewer, item, true, false},
+		{RoleViewer, user, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.entry.EntityType), func(t *testing.T) {
+			ev := ChangeEventFor(tt.role, tt.entry)
+			if !tt.wantVisible {
+				assert.Nil(t, ev)
+				return
+			}
+			if assert.NotNil(t, ev) {
+				assert.Same(t, tt.entry, ev.Entry)
+				assert.Equal(t, tt.wantDetailed, ev.Detailed)
+			}
+		})
+	}
+}
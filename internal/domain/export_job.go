@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportJobType - что выгружается фоновой задачей
+type ExportJobType string
+
+const (
+	ExportJobAudit ExportJobType = "audit"
+	ExportJobItems ExportJobType = "items"
+)
+
+func (t ExportJobType) IsValid() bool {
+	switch t {
+	case ExportJobAudit, ExportJobItems:
+		return true
+	}
+	return false
+}
+
+type ExportJobStatus string
+
+const (
+	ExportJobPending ExportJobStatus = "pending"
+	ExportJobRunning ExportJobStatus = "running"
+	ExportJobDone    ExportJobStatus = "done"
+	ExportJobFailed  ExportJobStatus = "failed"
+)
+
+// ExportJob - фоновая выгрузка в файл (POST /api/exports).
+// Фильтр хранится в том виде, в каком его принимают синхронные выгрузки.
+type ExportJob struct {
+	ID          uuid.UUID
+	Type        ExportJobType
+	Format      string
+	AuditFilter *AuditFilter
+	ItemFilter  *ItemFilter
+	Columns     []string
+	Status      ExportJobStatus
+	TotalRows   int64
+	Processed   int64
+	FileName    string
+	FileSize    int64
+	Error       string
+	CreatedBy   uuid.UUID
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	ExpiresAt   *time.Time
+}
+
+// CreateExportJobInput - параметры новой выгрузки; для items используется ItemFilter и Columns, для audit - AuditFilter
+type CreateExportJobInput struct {
+	Type        ExportJobType
+	Format      string
+	AuditFilter *AuditFilter
+	ItemFilter  *ItemFilter
+	Columns     []string
+}
@@ -45,10 +45,22 @@ func (u *UpdateItemInput) HasChanges() bool {
 		u.Location != nil
 }
 
+// AdjustQuantityInput - относительное изменение остатка (+5, -3)
+type AdjustQuantityInput struct {
+	Delta int `json:"delta" validate:"required"`
+}
+
 // ItemFilter - фильтрация и пагинация для GET /items
 type ItemFilter struct {
 	Search *string `json:"search"`
 }
+
+// ItemCursor - позиция keyset-пагинации по (created_at, id)
+type ItemCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
 type ItemList struct {
 	Items      []*Item
 	Total      int64
@@ -0,0 +1,41 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ImportAction - что импорт сделал (или сделает при dry run) со строкой файла
+type ImportAction string
+
+const (
+	ImportCreate    ImportAction = "create"
+	ImportUpdate    ImportAction = "update"
+	ImportUnchanged ImportAction = "unchanged"
+	ImportError     ImportAction = "error"
+)
+
+// ImportRow - строка файла импорта после разбора и валидации.
+// Если Err != nil, строка не прошла валидацию и Input может быть nil.
+type ImportRow struct {
+	Line  int
+	SKU   string
+	Input *CreateItemInput
+	Err   error
+}
+
+// ImportRowResult - итог по одной строке файла
+type ImportRowResult struct {
+	Line   int
+	SKU    string
+	Action ImportAction
+	ItemID *uuid.UUID
+	Err    error
+}
+
+// ImportReport - построчный отчёт импорта
+type ImportReport struct {
+	DryRun    bool
+	Rows      []*ImportRowResult
+	Created   int
+	Updated   int
+	Unchanged int
+	Failed    int
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCIdentity - пользователь провайдера единого входа из проверенного ID token.
+// Issuer и Subject однозначно определяют его у провайдера; логин может меняться.
+type OIDCIdentity struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Email    string
+	Name     string
+	Groups   []string
+}
+
+// UserIdentity - привязка пользователя к учётной записи у провайдера единого входа
+type UserIdentity struct {
+	Issuer      string
+	Subject     string
+	UserID      uuid.UUID
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
+
+// OIDCLoginState - начатый вход через провайдера. Хранится до возврата пользователя:
+// по state находятся nonce для проверки ID token и verifier PKCE для обмена code.
+// Сам state не хранится, только StateHash.
+type OIDCLoginState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxAggregateItem - события outbox по товарам
+const OutboxAggregateItem = "item"
+
+// OutboxEventType - тип события для внешних систем
+type OutboxEventType string
+
+const (
+	OutboxItemCreated OutboxEventType = "item.created"
+	OutboxItemUpdated OutboxEventType = "item.updated"
+	OutboxItemDeleted OutboxEventType = "item.deleted"
+	// остаток опустился до порога outbox.low_stock_threshold
+	OutboxItemLowStock OutboxEventType = "item.low_stock"
+)
+
+func (t OutboxEventType) IsValid() bool {
+	switch t {
+	case OutboxItemCreated, OutboxItemUpdated, OutboxItemDeleted, OutboxItemLowStock:
+		return true
+	}
+	return false
+}
+
+// OutboxEvent - событие для внешних систем (ERP, BI) из таблицы outbox.
+// Пишется в транзакции изменения и доставляется не менее одного раза:
+// получатель отбрасывает повторы по EventID. События одного агрегата уходят в порядке ID.
+type OutboxEvent struct {
+	ID            int64
+	EventID       uuid.UUID
+	AggregateType string
+	AggregateID   uuid.UUID
+	Type          OutboxEventType
+	// Payload - для товаров: снимок строки после изменения (для item.deleted - до удаления),
+	// автор и причина изменения
+	Payload   json.RawMessage
+	CreatedAt time.Time
+	// Attempts - сколько раз отправка уже не удалась
+	Attempts int
+}
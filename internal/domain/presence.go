@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PresenceUser - пользователь, у которого открыт товар. Editing - открыто окно редактирования
+// хотя бы в одной его вкладке, Since - когда он открыл товар первым соединением
+type PresenceUser struct {
+	UserID   uuid.UUID
+	Username string
+	Role     Role
+	Editing  bool
+	Since    time.Time
+}
+
+// PresenceUpdate - кто сейчас открыл товар
+type PresenceUpdate struct {
+	ItemID uuid.UUID
+	Users  []PresenceUser
+}
+
+// PresenceMessage - сообщение в сессию присутствия: либо новый состав открывших товар,
+// либо изменение товара, на который подписана сессия
+type PresenceMessage struct {
+	Presence *PresenceUpdate
+	Change   *ChangeEvent
+}
+
+// PresenceSession - соединение клиента с каналом присутствия
+type PresenceSession interface {
+	// Messages закрывается, когда сессия завершена (Close, остановка сервера, медленный клиент)
+	Messages() <-chan *PresenceMessage
+	Subscribe(itemID uuid.UUID) error
+	Unsubscribe(itemID uuid.UUID)
+	// SetEditing отмечает, что окно редактирования товара открыто или закрыто; подписывает на товар
+	SetEditing(itemID uuid.UUID, editing bool) error
+	Close()
+}
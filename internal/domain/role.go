@@ -26,6 +26,30 @@ func ParseRole(s string) (Role, error) {
 	return r, nil
 }
 
+// права ролей вложены: у admin есть все права manager, у manager - все права viewer
+var roleRank = map[Role]int{RoleViewer: 1, RoleManager: 2, RoleAdmin: 3}
+
+// Includes - у роли r есть все права роли other
+func (r Role) Includes(other Role) bool {
+	return other.IsValid() && roleRank[r] >= roleRank[other]
+}
+
+// Min - роль с меньшими правами
+func (r Role) Min(other Role) Role {
+	if r.Includes(other) {
+		return other
+	}
+	return r
+}
+
+// Max - роль с большими правами
+func (r Role) Max(other Role) Role {
+	if r.Includes(other) {
+		return r
+	}
+	return other
+}
+
 func (r Role) CanCreate() bool { return r == RoleAdmin || r == RoleManager }
 func (r Role) CanUpdate() bool { return r == RoleAdmin || r == RoleManager }
 func (r Role) CanDelete() bool { return r == RoleAdmin }
@@ -34,5 +58,23 @@ func (r Role) CanView() bool   { return true }
 // CanViewAudit - просмотр истории изменений
 func (r Role) CanViewAudit() bool { return r == RoleAdmin || r == RoleManager }
 
-// CanExport - экспорт в CSV
+// CanExport - выгрузка аудита и каталога в файл
 func (r Role) CanExport() bool { return r == RoleAdmin || r == RoleManager }
+
+// CanVerifyAudit - проверка целостности цепочки хешей аудита
+func (r Role) CanVerifyAudit() bool { return r == RoleAdmin }
+
+// CanManageAuditArchive - просмотр архивов аудита и возврат их в БД
+func (r Role) CanManageAuditArchive() bool { return r == RoleAdmin }
+
+// CanListUsers - список пользователей (логин и роль)
+func (r Role) CanListUsers() bool { return r == RoleAdmin || r == RoleManager }
+
+// CanManageUsers - создание, смена роли и пароля, отключение и удаление пользователей
+func (r Role) CanManageUsers() bool { return r == RoleAdmin }
+
+// CanManageWebhooks - подписки на исходящие webhook и журнал их доставок
+func (r Role) CanManageWebhooks() bool { return r == RoleAdmin }
+
+// CanManageAPIKeys - ключи интеграций (X-API-Key)
+func (r Role) CanManageAPIKeys() bool { return r == RoleAdmin }
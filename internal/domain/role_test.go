@@ -50,10 +50,15 @@ func TestRole_Permissions(t *testing.T) {
 		canView      bool
 		canViewAudit bool
 		canExport    bool
+		canVerify    bool
+		canArchive   bool
+		canWebhooks  bool
+		canUsers     bool
+		canList      bool
 	}{
-		{RoleAdmin, true, true, true, true, true, true},
-		{RoleManager, true, true, false, true, true, true},
-		{RoleViewer, false, false, false, true, false, false},
+		{RoleAdmin, true, true, true, true, true, true, true, true, true, true, true},
+		{RoleManager, true, true, false, true, true, true, false, false, false, false, true},
+		{RoleViewer, false, false, false, true, false, false, false, false, false, false, false},
 	}
 
 	for _, tt := range tests {
@@ -64,6 +69,25 @@ func TestRole_Permissions(t *testing.T) {
 			assert.Equal(t, tt.canView, tt.role.CanView())
 			assert.Equal(t, tt.canViewAudit, tt.role.CanViewAudit())
 			assert.Equal(t, tt.canExport, tt.role.CanExport())
+			assert.Equal(t, tt.canVerify, tt.role.CanVerifyAudit())
+			assert.Equal(t, tt.canArchive, tt.role.CanManageAuditArchive())
+			assert.Equal(t, tt.canWebhooks, tt.role.CanManageWebhooks())
+			assert.Equal(t, tt.canUsers, tt.role.CanManageUsers())
+			assert.Equal(t, tt.canList, tt.role.CanListUsers())
 		})
 	}
 }
+
+func TestRole_Includes(t *testing.T) {
+	assert.True(t, RoleAdmin.Includes(RoleManager))
+	assert.True(t, RoleManager.Includes(RoleManager))
+	assert.True(t, RoleManager.Includes(RoleViewer))
+	assert.False(t, RoleViewer.Includes(RoleManager))
+	assert.False(t, RoleManager.Includes(RoleAdmin))
+	assert.False(t, RoleAdmin.Includes(Role("unknown")))
+
+	assert.Equal(t, RoleViewer, RoleAdmin.Min(RoleViewer))
+	assert.Equal(t, RoleManager, RoleManager.Min(RoleAdmin))
+	assert.Equal(t, RoleAdmin, RoleAdmin.Max(RoleViewer))
+	assert.Equal(t, RoleAdmin, RoleManager.Max(RoleAdmin))
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"crypto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessToken - выпущенный JWT доступа; ID - его jti
+type AccessToken struct {
+	Token     string
+	ID        uuid.UUID
+	ExpiresAt time.Time
+}
+
+// TokenPair - токен доступа и refresh-токен, выдаваемые при входе и обновлении
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// RefreshToken - запись о refresh-токене. Сам токен не хранится, только TokenHash.
+// Токены одного входа делят FamilyID; RotatedAt - токен уже обменян на следующий.
+type RefreshToken struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	FamilyID        uuid.UUID
+	TokenHash       string
+	AccessTokenID   uuid.UUID
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	RotatedAt       *time.Time
+	RevokedAt       *time.Time
+}
+
+// RevokedToken - отозванный токен доступа; хранится, пока он не истёк
+type RevokedToken struct {
+	ID        uuid.UUID
+	ExpiresAt time.Time
+}
+
+// SigningKey - ключ подписи токенов доступа в БД. PrivateKey - PKCS #8 (DER), зашифрованный,
+// если Encrypted; незашифрованными остаются только ключи, созданные до шифрования.
+// Ключ подписывает токены начиная с ActivatesAt и до активации следующего.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	PrivateKey  []byte
+	Encrypted   bool
+	ActivatesAt time.Time
+	CreatedAt   time.Time
+}
+
+// PublicKey - открытый ключ проверки токенов, публикуемый в JWKS
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
@@ -7,12 +7,24 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id"         db:"id"`
-	Username     string    `json:"username"    db:"username"`
-	PasswordHash string    `json:"-"           db:"password_hash"`
-	Role         Role      `json:"role"        db:"role"`
-	CreatedAt    time.Time `json:"created_at"  db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"  db:"updated_at"`
+	ID                uuid.UUID  `json:"id"                  db:"id"`
+	Username          string     `json:"username"            db:"username"`
+	PasswordHash      string     `json:"-"                   db:"password_hash"`
+	Role              Role       `json:"role"                db:"role"`
+	DisabledAt        *time.Time `json:"disabled_at"         db:"disabled_at"`
+	PasswordChangedAt *time.Time `json:"password_changed_at" db:"password_changed_at"`
+	CreatedAt         time.Time  `json:"created_at"          db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"          db:"updated_at"`
+}
+
+// Disabled - учётная запись отключена администратором: вход запрещён
+func (u *User) Disabled() bool { return u.DisabledAt != nil }
+
+// CreateUserInput - данные для создания пользователя администратором
+type CreateUserInput struct {
+	Username string
+	Password string
+	Role     Role
 }
 
 // LoginInput - входные данные для авторизации
@@ -21,9 +33,23 @@ type LoginInput struct {
 	Password string `json:"password" validate:"required"`
 }
 
-// AuthClaims - данные, зашиваемые в JWT
+// ChangePasswordInput - смена собственного пароля: текущий пароль обязателен
+type ChangePasswordInput struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// AuthClaims - данные, зашиваемые в JWT. TokenID (jti) и ExpiresAt нужны для отзыва токена.
+// При входе по API-ключу TokenID пуст, APIKeyID - ключ, ReadOnly - ключ только для чтения.
 type AuthClaims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
-	Role     Role      `json:"role"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
+	TokenID   uuid.UUID `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+	APIKeyID  uuid.UUID `json:"-"`
+	ReadOnly  bool      `json:"-"`
 }
+
+// ViaAPIKey - запрос выполнен по API-ключу, а не токену входа
+func (c *AuthClaims) ViaAPIKey() bool { return c.APIKeyID != uuid.Nil }
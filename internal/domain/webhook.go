@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook - подписка внешней системы на события outbox.
+// Каждая доставка подписывается HMAC-SHA256 с ключом Secret.
+type Webhook struct {
+	ID         uuid.UUID
+	URL        string
+	EventTypes []OutboxEventType
+	Secret     string
+	Active     bool
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (w *Webhook) Subscribed(t OutboxEventType) bool {
+	return slices.Contains(w.EventTypes, t)
+}
+
+// CreateWebhookInput - новая подписка; пустой Secret - ключ сгенерирует сервер
+type CreateWebhookInput struct {
+	URL        string
+	EventTypes []OutboxEventType
+	Secret     string
+	Active     *bool
+}
+
+// UpdateWebhookInput - частичное изменение подписки; nil - поле не меняется
+type UpdateWebhookInput struct {
+	URL        *string
+	EventTypes []OutboxEventType
+	Secret     *string
+	Active     *bool
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	// доставка ждёт первой или повторной попытки
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// получатель ответил 2xx
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// попытки исчерпаны; доставку можно повторить вручную
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery - отправка одного события одной подписке: запись журнала доставок.
+// Поля Response* и Error - результат последней попытки.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	EventID        uuid.UUID
+	EventType      OutboxEventType
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time
+	ResponseStatus int
+	ResponseBody   string
+	Error          string
+	Duration       time.Duration
+	// RedeliveryOf - исходная доставка, если эта создана повторной отправкой вручную
+	RedeliveryOf *uuid.UUID
+	CreatedAt    time.Time
+	DeliveredAt  *time.Time
+
+	// Webhook - адрес и ключ подписки; заполняется только при выдаче доставки на отправку
+	Webhook *Webhook
+}
+
+// WebhookAttempt - результат одной попытки доставки
+type WebhookAttempt struct {
+	StatusCode int    // 0 - ответа не было
+	Body       string // начало тела ответа
+	Error      string
+	Duration   time.Duration
+}
+
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// WebhookDeliveryFilter - журнал доставок подписки; пустой Status - все
+type WebhookDeliveryFilter struct {
+	WebhookID uuid.UUID
+	Status    WebhookDeliveryStatus
+}
+
+type WebhookDeliveryList struct {
+	Deliveries []*WebhookDelivery
+	Total      int64
+	Page       int
+	PageSize   int
+	TotalPages int
+}
@@ -0,0 +1,236 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// AuditWriter - экспортёр аудита в конкретный формат.
+// Записи передаются по одной; Close дописывает хвост формата и обязателен.
+type AuditWriter interface {
+	Write(e *domain.AuditEntryWithUser) error
+	Close() error
+}
+
+func NewAuditWriter(w io.Writer, format Format) (AuditWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewAuditCSVWriter(w)
+	case FormatJSONL:
+		return newAuditJSONLWriter(w), nil
+	case FormatXLSX:
+		return newAuditXLSXWriter(w)
+	case FormatPDF:
+		return newAuditPDFWriter(w)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// auditJSONLWriter - одна запись на строку, old_data/new_data/diff без изменений
+type auditJSONLWriter struct {
+	bw  *bufio.Writer
+	enc *json.Encoder
+}
+
+type auditJSONLine struct {
+	ID         int64           `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	Action     string          `json:"action"`
+	ChangedBy  uuid.UUID       `json:"changed_by"`
+	Username   string          `json:"username"`
+	ChangedAt  time.Time       `json:"changed_at"`
+	OldData    json.RawMessage `json:"old_data"`
+	NewData    json.RawMessage `json:"new_data"`
+	Diff       json.RawMessage `json:"diff"`
+	Reason     *string         `json:"reason"`
+	Reference  *string         `json:"reference"`
+	RequestID  *string         `json:"request_id"`
+	ClientIP   *string         `json:"client_ip"`
+	UserAgent  *string         `json:"user_agent"`
+}
+
+func newAuditJSONLWriter(w io.Writer) *auditJSONLWriter {
+	bw := bufio.NewWriter(w)
+	return &auditJSONLWriter{bw: bw, enc: json.NewEncoder(bw)}
+}
+
+func (w *auditJSONLWriter) Write(e *domain.AuditEntryWithUser) error {
+	line := auditJSONLine{
+		ID:         e.ID,
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID,
+		Action:     string(e.Action),
+		ChangedBy:  e.ChangedBy,
+		Username:   e.Username,
+		ChangedAt:  e.ChangedAt,
+		OldData:    rawOrNull(e.OldData),
+		NewData:    rawOrNull(e.NewData),
+		Diff:       rawOrNull(e.Diff),
+		Reason:     e.Reason,
+		Reference:  e.Reference,
+		RequestID:  e.RequestID,
+		ClientIP:   e.ClientIP,
+		UserAgent:  e.UserAgent,
+	}
+	if err := w.enc.Encode(line); err != nil {
+		return fmt.Errorf("write row %d: %w", e.ID, err)
+	}
+	return nil
+}
+
+func (w *auditJSONLWriter) Close() error {
+	return w.bw.Flush()
+}
+
+var auditXLSXHeader = []interface{}{
+	"ID",
+	"Entity",
+	"Entity ID",
+	"Action",
+	"Changed By (ID)",
+	"Changed By (Username)",
+	"Changed At",
+	"Field",
+	"Old Value",
+	"New Value",
+	"Reason",
+	"Reference",
+	"Request ID",
+	"Client IP",
+	"User Agent",
+}
+
+// auditXLSXWriter - одна строка листа на каждое изменённое поле записи
+type auditXLSXWriter struct {
+	w   io.Writer
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	row int
+}
+
+func newAuditXLSXWriter(w io.Writer) (*auditXLSXWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter(f.GetSheetName(0))
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("create sheet: %w", err)
+	}
+	if err = sw.SetRow("A1", auditXLSXHeader); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+	return &auditXLSXWriter{w: w, f: f, sw: sw, row: 1}, nil
+}
+
+func (w *auditXLSXWriter) Write(e *domain.AuditEntryWithUser) error {
+	changes := auditFieldChanges(e)
+	if len(changes) == 0 {
+		changes = []domain.FieldChange{{}}
+	}
+
+	for _, ch := range changes {
+		w.row++
+		cell, err := excelize.CoordinatesToCellName(1, w.row)
+		if err != nil {
+			return err
+		}
+		err = w.sw.SetRow(cell, []interface{}{
+			e.ID,
+			string(e.EntityType),
+			e.EntityID.String(),
+			string(e.Action),
+			e.ChangedBy.String(),
+			e.Username,
+			e.ChangedAt.Format(time.RFC3339),
+			ch.Field,
+			formatAuditValue(ch.OldValue),
+			formatAuditValue(ch.NewValue),
+			stringValue(e.Reason),
+			stringValue(e.Reference),
+			stringValue(e.RequestID),
+			stringValue(e.ClientIP),
+			stringValue(e.UserAgent),
+		})
+		if err != nil {
+			return fmt.Errorf("write row %d: %w", e.ID, err)
+		}
+	}
+	return nil
+}
+
+func (w *auditXLSXWriter) Close() error {
+	defer w.f.Close()
+
+	if err := w.sw.Flush(); err != nil {
+		return fmt.Errorf("flush sheet: %w", err)
+	}
+	if err := w.f.Write(w.w); err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	return nil
+}
+
+// auditFieldChanges - изменения по полям, отсортированные по имени поля.
+// Для UPDATE берётся diff, для INSERT - все поля new_data, для DELETE - все поля old_data.
+func auditFieldChanges(e *domain.AuditEntryWithUser) []domain.FieldChange {
+	var changes []domain.FieldChange
+
+	switch e.Action {
+	case domain.AuditUpdate:
+		changes, _ = e.ParseDiff()
+	case domain.AuditInsert:
+		for field, v := range decodeAuditData(e.NewData) {
+			changes = append(changes, domain.FieldChange{Field: field, NewValue: v})
+		}
+	case domain.AuditDelete:
+		for field, v := range decodeAuditData(e.OldData) {
+			changes = append(changes, domain.FieldChange{Field: field, OldValue: v})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func decodeAuditData(raw json.RawMessage) map[string]interface{} {
+	var data map[string]interface{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &data)
+	}
+	return data
+}
+
+func formatAuditValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func rawOrNull(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return raw
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func testAuditEntries() []*domain.AuditEntryWithUser {
+	itemID := uuid.New()
+	userID := uuid.New()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	return []*domain.AuditEntryWithUser{
+		{
+			AuditEntry: domain.AuditEntry{
+				ID:         1,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   itemID,
+				Action:     domain.AuditInsert,
+				ChangedBy:  userID,
+				NewData:    json.RawMessage(`{"name":"Ноутбук","quantity":10}`),
+				ChangedAt:  now,
+			},
+			Username: "admin",
+		},
+		{
+			AuditEntry: domain.AuditEntry{
+				ID:         2,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   itemID,
+				Action:     domain.AuditUpdate,
+				ChangedBy:  userID,
+				OldData:    json.RawMessage(`{"name":"Ноутбук","quantity":10,"price":999.99}`),
+				NewData:    json.RawMessage(`{"name":"Gaming Laptop","quantity":8,"price":999.99}`),
+				Diff:       json.RawMessage(`{"quantity":{"old":10,"new":8},"name":{"old":"Ноутбук","new":"Gaming Laptop"}}`),
+				ChangedAt:  now.Add(time.Minute),
+			},
+			Username: "manager",
+		},
+	}
+}
+
+func writeAudit(t *testing.T, format Format, entries []*domain.AuditEntryWithUser) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	aw, err := NewAuditWriter(&buf, format)
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.NoError(t, aw.Write(e))
+	}
+	require.NoError(t, aw.Close())
+	return buf.Bytes()
+}
+
+func TestAuditWriter_JSONL(t *testing.T) {
+	out := writeAudit(t, FormatJSONL, testAuditEntries())
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 2)
+
+	var first map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.JSONEq(t, `{"name":"Ноутбук","quantity":10}`, string(first["new_data"]))
+	assert.Equal(t, "null", string(first["old_data"]))
+	assert.Equal(t, "null", string(first["diff"]))
+
+	var second map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.JSONEq(t, `{"quantity":{"old":10,"new":8},"name":{"old":"Ноутбук","new":"Gaming Laptop"}}`, string(second["diff"]))
+	assert.JSONEq(t, `"manager"`, string(second["username"]))
+}
+
+func TestAuditWriter_XLSXRowPerField(t *testing.T) {
+	out := writeAudit(t, FormatXLSX, testAuditEntries())
+
+	f, err := excelize.OpenReader(bytes.NewReader(out))
+	require.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	require.NoError(t, err)
+	// заголовок + 2 поля INSERT + 2 поля UPDATE
+	require.Len(t, rows, 5)
+	assert.Equal(t, "Field", rows[0][7])
+
+	assert.Equal(t, []string{"INSERT", "name", "", "Ноутбук"}, []string{rows[1][3], rows[1][7], rows[1][8], rows[1][9]})
+	assert.Equal(t, []string{"UPDATE", "name", "Ноутбук", "Gaming Laptop"}, []string{rows[3][3], rows[3][7], rows[3][8], rows[3][9]})
+	assert.Equal(t, []string{"quantity", "10", "8"}, rows[4][7:10])
+}
+
+func TestAuditWriter_PDF(t *testing.T) {
+	// достаточно записей, чтобы отчёт занял несколько страниц
+	var entries []*domain.AuditEntryWithUser
+	for i := 0; i < 60; i++ {
+		entries = append(entries, testAuditEntries()...)
+	}
+
+	out := writeAudit(t, FormatPDF, entries)
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+	assert.Greater(t, bytes.Count(out, []byte("/Type /Page\n")), 1)
+}
+
+func TestAuditWriter_PDFEmpty(t *testing.T) {
+	out := writeAudit(t, FormatPDF, nil)
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+}
+
+func TestNewAuditWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewAuditWriter(&bytes.Buffer{}, Format("docx"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestParseFormat_Audit(t *testing.T) {
+	f, err := ParseFormat("pdf", AuditFormats)
+	require.NoError(t, err)
+	assert.Equal(t, FormatPDF, f)
+}
@@ -12,39 +12,83 @@ import (
 
 var auditCSVHeader = []string{
 	"ID",
-	"Item ID",
+	"Entity",
+	"Entity ID",
 	"Action",
 	"Changed By (ID)",
 	"Changed By (Username)",
 	"Changed At",
 	"Changes",
+	"Reason",
+	"Reference",
+	"Request ID",
+	"Client IP",
+	"User Agent",
 }
 
 func WriteAuditCSV(w io.Writer, entries []*domain.AuditEntryWithUser) error {
-	cw := csv.NewWriter(w)
-
-	if err := cw.Write(auditCSVHeader); err != nil {
-		return fmt.Errorf("write header: %w", err)
+	aw, err := NewAuditCSVWriter(w)
+	if err != nil {
+		return err
 	}
 
 	for _, e := range entries {
-		row := []string{
-			fmt.Sprintf("%d", e.ID),
-			e.ItemID.String(),
-			string(e.Action),
-			e.ChangedBy.String(),
-			e.Username,
-			e.ChangedAt.Format(time.RFC3339),
-			formatDiff(e.Diff),
+		if err = aw.Write(e); err != nil {
+			return err
 		}
+	}
 
-		if err := cw.Write(row); err != nil {
-			return fmt.Errorf("write row %d: %w", e.ID, err)
-		}
+	return aw.Close()
+}
+
+// AuditCSVWriter пишет аудит построчно - для потоковой выгрузки без сборки файла в памяти.
+// Заголовок пишется при создании, Close сбрасывает буфер.
+type AuditCSVWriter struct {
+	cw *csv.Writer
+}
+
+func NewAuditCSVWriter(w io.Writer) (*AuditCSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(auditCSVHeader); err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
 	}
+	return &AuditCSVWriter{cw: cw}, nil
+}
+
+func (w *AuditCSVWriter) Write(e *domain.AuditEntryWithUser) error {
+	row := []string{
+		fmt.Sprintf("%d", e.ID),
+		string(e.EntityType),
+		e.EntityID.String(),
+		string(e.Action),
+		e.ChangedBy.String(),
+		e.Username,
+		e.ChangedAt.Format(time.RFC3339),
+		formatDiff(e.Diff),
+		stringValue(e.Reason),
+		stringValue(e.Reference),
+		stringValue(e.RequestID),
+		stringValue(e.ClientIP),
+		stringValue(e.UserAgent),
+	}
+
+	if err := w.cw.Write(row); err != nil {
+		return fmt.Errorf("write row %d: %w", e.ID, err)
+	}
+	return nil
+}
+
+func (w *AuditCSVWriter) Close() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
 
-	cw.Flush()
-	return cw.Error()
+// stringValue - необязательное текстовое поле записи, пустое - пустая строка
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
 
 func formatDiff(raw json.RawMessage) string {
@@ -18,7 +18,7 @@ func TestWriteAuditCSV_Empty(t *testing.T) {
 	err := WriteAuditCSV(&buf, nil)
 
 	require.NoError(t, err)
-	assert.Contains(t, buf.String(), "ID,Item ID,Action")
+	assert.Contains(t, buf.String(), "ID,Entity,Entity ID,Action")
 	// only header, no data rows
 	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
 	assert.Len(t, lines, 1)
@@ -28,26 +28,32 @@ func TestWriteAuditCSV_WithEntries(t *testing.T) {
 	itemID := uuid.New()
 	userID := uuid.New()
 	now := time.Now()
+	reason := "переименование по накладной"
+	requestID := "req-42"
 
 	entries := []*domain.AuditEntryWithUser{
 		{
 			AuditEntry: domain.AuditEntry{
-				ID:        1,
-				ItemID:    itemID,
-				Action:    domain.AuditInsert,
-				ChangedBy: userID,
-				ChangedAt: now,
+				ID:         1,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   itemID,
+				Action:     domain.AuditInsert,
+				ChangedBy:  userID,
+				ChangedAt:  now,
 			},
 			Username: "admin",
 		},
 		{
 			AuditEntry: domain.AuditEntry{
-				ID:        2,
-				ItemID:    itemID,
-				Action:    domain.AuditUpdate,
-				ChangedBy: userID,
-				Diff:      json.RawMessage(`{"name":{"old":"Laptop","new":"Gaming Laptop"}}`),
-				ChangedAt: now,
+				ID:         2,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   itemID,
+				Action:     domain.AuditUpdate,
+				ChangedBy:  userID,
+				Diff:       json.RawMessage(`{"name":{"old":"Laptop","new":"Gaming Laptop"}}`),
+				Reason:     &reason,
+				RequestID:  &requestID,
+				ChangedAt:  now,
 			},
 			Username: "admin",
 		},
@@ -65,6 +71,7 @@ func TestWriteAuditCSV_WithEntries(t *testing.T) {
 	assert.Contains(t, buf.String(), "UPDATE")
 	assert.Contains(t, buf.String(), "admin")
 	assert.Contains(t, buf.String(), "Laptop")
+	assert.True(t, strings.HasSuffix(lines[2], ","+reason+",,"+requestID+",,"))
 }
 
 func TestFormatDiff_Empty(t *testing.T) {
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"errors"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatXLSX  Format = "xlsx"
+	FormatJSONL Format = "jsonl"
+	FormatPDF   Format = "pdf"
+)
+
+// Форматы, доступные для каждого вида выгрузки
+var (
+	ItemFormats  = []Format{FormatCSV, FormatXLSX, FormatJSONL}
+	AuditFormats = []Format{FormatCSV, FormatJSONL, FormatXLSX, FormatPDF}
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// ParseFormat - формат из query-параметра, пустая строка - CSV.
+// Формат вне allowed - ErrUnsupportedFormat.
+func ParseFormat(s string, allowed []Format) (Format, error) {
+	f := Format(strings.ToLower(s))
+	if f == "" {
+		f = FormatCSV
+	}
+	for _, a := range allowed {
+		if f == a {
+			return f, nil
+		}
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// FormatList - форматы через запятую, для сообщений об ошибке
+func FormatList(formats []Format) string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Extension - расширение файла без точки
+func (f Format) Extension() string {
+	return string(f)
+}
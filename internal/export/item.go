@@ -0,0 +1,257 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// Колонки выгрузки каталога. Названия совпадают с полями импорта,
+// поэтому выгруженный CSV/XLSX можно загрузить обратно через POST /api/items/import.
+const (
+	ItemColumnID        = "id"
+	ItemColumnName      = "name"
+	ItemColumnSKU       = "sku"
+	ItemColumnQuantity  = "quantity"
+	ItemColumnPrice     = "price"
+	ItemColumnLocation  = "location"
+	ItemColumnCreatedAt = "created_at"
+	ItemColumnUpdatedAt = "updated_at"
+)
+
+var ItemColumns = []string{
+	ItemColumnID,
+	ItemColumnName,
+	ItemColumnSKU,
+	ItemColumnQuantity,
+	ItemColumnPrice,
+	ItemColumnLocation,
+	ItemColumnCreatedAt,
+	ItemColumnUpdatedAt,
+}
+
+// ParseItemColumns разбирает список колонок через запятую.
+// Пустая строка - все колонки в порядке ItemColumns.
+func ParseItemColumns(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return ItemColumns, nil
+	}
+
+	var (
+		res  []string
+		seen = make(map[string]bool)
+	)
+	for _, col := range strings.Split(s, ",") {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if col == "" || seen[col] {
+			continue
+		}
+		if !isItemColumn(col) {
+			return nil, fmt.Errorf("unknown column: %q", col)
+		}
+		seen[col] = true
+		res = append(res, col)
+	}
+	if len(res) == 0 {
+		return ItemColumns, nil
+	}
+	return res, nil
+}
+
+// ItemWriter пишет товары построчно, не накапливая их в памяти.
+// Close дописывает хвост формата (для XLSX - весь архив) и обязателен.
+type ItemWriter interface {
+	Write(item *domain.Item) error
+	Close() error
+}
+
+func NewItemWriter(w io.Writer, format Format, columns []string) (ItemWriter, error) {
+	if len(columns) == 0 {
+		columns = ItemColumns
+	}
+
+	switch format {
+	case FormatCSV:
+		return newItemCSVWriter(w, columns)
+	case FormatXLSX:
+		return newItemXLSXWriter(w, columns)
+	case FormatJSONL:
+		return newItemJSONLWriter(w, columns), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type itemCSVWriter struct {
+	cw      *csv.Writer
+	columns []string
+}
+
+func newItemCSVWriter(w io.Writer, columns []string) (*itemCSVWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+	return &itemCSVWriter{cw: cw, columns: columns}, nil
+}
+
+func (w *itemCSVWriter) Write(item *domain.Item) error {
+	row := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		row[i] = formatItemValue(itemValue(item, col))
+	}
+	if err := w.cw.Write(row); err != nil {
+		return fmt.Errorf("write item %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+func (w *itemCSVWriter) Close() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+// itemXLSXWriter пишет через StreamWriter excelize: строки сбрасываются во временный файл,
+// а не держатся в памяти; итоговый архив отдаётся в w при Close.
+type itemXLSXWriter struct {
+	w       io.Writer
+	f       *excelize.File
+	sw      *excelize.StreamWriter
+	columns []string
+	row     int
+}
+
+func newItemXLSXWriter(w io.Writer, columns []string) (*itemXLSXWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter(f.GetSheetName(0))
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("create sheet: %w", err)
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = col
+	}
+	if err = sw.SetRow("A1", header); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+
+	return &itemXLSXWriter{w: w, f: f, sw: sw, columns: columns, row: 1}, nil
+}
+
+func (w *itemXLSXWriter) Write(item *domain.Item) error {
+	w.row++
+
+	// количество и цена - числовые ячейки, чтобы по ним можно было считать в таблице
+	values := make([]interface{}, len(w.columns))
+	for i, col := range w.columns {
+		if col == ItemColumnPrice {
+			values[i] = item.Price.InexactFloat64()
+			continue
+		}
+		values[i] = itemValue(item, col)
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	if err = w.sw.SetRow(cell, values); err != nil {
+		return fmt.Errorf("write item %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+func (w *itemXLSXWriter) Close() error {
+	defer w.f.Close()
+
+	if err := w.sw.Flush(); err != nil {
+		return fmt.Errorf("flush sheet: %w", err)
+	}
+	if err := w.f.Write(w.w); err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+	return nil
+}
+
+type itemJSONLWriter struct {
+	bw      *bufio.Writer
+	enc     *json.Encoder
+	columns []string
+}
+
+func newItemJSONLWriter(w io.Writer, columns []string) *itemJSONLWriter {
+	bw := bufio.NewWriter(w)
+	return &itemJSONLWriter{bw: bw, enc: json.NewEncoder(bw), columns: columns}
+}
+
+func (w *itemJSONLWriter) Write(item *domain.Item) error {
+	obj := make(map[string]interface{}, len(w.columns))
+	for _, col := range w.columns {
+		obj[col] = itemValue(item, col)
+	}
+	if err := w.enc.Encode(obj); err != nil {
+		return fmt.Errorf("write item %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+func (w *itemJSONLWriter) Close() error {
+	return w.bw.Flush()
+}
+
+// itemValue - значение колонки; nil для пустой локации
+func itemValue(item *domain.Item, column string) interface{} {
+	switch column {
+	case ItemColumnID:
+		return item.ID.String()
+	case ItemColumnName:
+		return item.Name
+	case ItemColumnSKU:
+		return item.SKU
+	case ItemColumnQuantity:
+		return item.Quantity
+	case ItemColumnPrice:
+		return item.Price.StringFixed(2)
+	case ItemColumnLocation:
+		if item.Location == nil {
+			return nil
+		}
+		return *item.Location
+	case ItemColumnCreatedAt:
+		return item.CreatedAt.Format(time.RFC3339)
+	case ItemColumnUpdatedAt:
+		return item.UpdatedAt.Format(time.RFC3339)
+	}
+	return nil
+}
+
+func formatItemValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	}
+	return fmt.Sprint(v)
+}
+
+func isItemColumn(col string) bool {
+	for _, c := range ItemColumns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
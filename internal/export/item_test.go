@@ -0,0 +1,119 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func testItems() []*domain.Item {
+	location := "Shelf 1"
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*domain.Item{
+		{ID: uuid.New(), Name: "Laptop", SKU: "LAP-001", Quantity: 10, Price: decimal.RequireFromString("999.9"), Location: &location, CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New(), Name: "Mouse, wireless", SKU: "MOU-001", Quantity: 0, Price: decimal.NewFromInt(25), CreatedAt: now, UpdatedAt: now},
+	}
+}
+
+func writeItems(t *testing.T, format Format, columns []string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	iw, err := NewItemWriter(&buf, format, columns)
+	require.NoError(t, err)
+	for _, item := range testItems() {
+		require.NoError(t, iw.Write(item))
+	}
+	require.NoError(t, iw.Close())
+	return buf.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("", ItemFormats)
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	f, err = ParseFormat("XLSX", ItemFormats)
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, f)
+
+	_, err = ParseFormat("pdf", ItemFormats)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestParseItemColumns(t *testing.T) {
+	cols, err := ParseItemColumns("")
+	require.NoError(t, err)
+	assert.Equal(t, ItemColumns, cols)
+
+	cols, err = ParseItemColumns(" SKU, name,sku ,quantity")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sku", "name", "quantity"}, cols)
+
+	_, err = ParseItemColumns("sku,color")
+	assert.Error(t, err)
+}
+
+func TestItemWriter_CSV(t *testing.T) {
+	out := writeItems(t, FormatCSV, []string{ItemColumnSKU, ItemColumnName, ItemColumnPrice, ItemColumnLocation})
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "sku,name,price,location", lines[0])
+	assert.Equal(t, "LAP-001,Laptop,999.90,Shelf 1", lines[1])
+	assert.Equal(t, `MOU-001,"Mouse, wireless",25.00,`, lines[2])
+}
+
+func TestItemWriter_CSVRoundTripsThroughImporter(t *testing.T) {
+	out := writeItems(t, FormatCSV, nil)
+
+	rows, err := importer.ReadItems(bytes.NewReader(out), importer.FormatCSV, nil, 0)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "MOU-001", rows[1].Fields[importer.FieldSKU])
+	assert.Equal(t, "25.00", rows[1].Fields[importer.FieldPrice])
+}
+
+func TestItemWriter_JSONL(t *testing.T) {
+	out := writeItems(t, FormatJSONL, []string{ItemColumnSKU, ItemColumnQuantity, ItemColumnLocation})
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 2)
+
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &obj))
+	assert.Len(t, obj, 3)
+	assert.Equal(t, "MOU-001", obj["sku"])
+	assert.Equal(t, float64(0), obj["quantity"])
+	assert.Nil(t, obj["location"])
+}
+
+func TestItemWriter_XLSX(t *testing.T) {
+	out := writeItems(t, FormatXLSX, []string{ItemColumnSKU, ItemColumnQuantity, ItemColumnPrice})
+
+	f, err := excelize.OpenReader(bytes.NewReader(out))
+	require.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"sku", "quantity", "price"}, rows[0])
+	assert.Equal(t, []string{"LAP-001", "10", "999.9"}, rows[1])
+}
+
+func TestNewItemWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewItemWriter(&bytes.Buffer{}, Format("pdf"), nil)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
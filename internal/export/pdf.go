@@ -0,0 +1,141 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Разметка PDF-отчёта: A4 альбомная, размеры в мм
+const (
+	pdfFont       = "go"
+	pdfFontSize   = 8
+	pdfLineHeight = 4
+	pdfMargin     = 10
+)
+
+// pdfColumns - ширины колонок таблицы; последняя (изменения) переносится по строкам
+var pdfColumns = []struct {
+	title string
+	width float64
+}{
+	{"Changed At", 36},
+	{"Action", 18},
+	{"User", 30},
+	{"Entity", 14},
+	{"Entity ID", 62},
+	{"Changes", 117},
+}
+
+// auditPDFWriter - постраничный отчёт по аудиту.
+// PDF собирается в памяти и отдаётся в w целиком при Close, поэтому для больших
+// выборок лучше CSV/JSONL или фоновая выгрузка.
+type auditPDFWriter struct {
+	w    io.Writer
+	pdf  *fpdf.Fpdf
+	rows int
+}
+
+func newAuditPDFWriter(w io.Writer) (*auditPDFWriter, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	// встроенные шрифты PDF не содержат кириллицы - берём Go fonts (WGL4)
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.AliasNbPages("{nb}")
+
+	generated := time.Now().UTC().Format(time.RFC3339)
+	pdf.SetHeaderFuncMode(func() {
+		pdf.SetFont(pdfFont, "B", 12)
+		pdf.CellFormat(0, 8, "Audit report", "", 1, "L", false, 0, "")
+		pdf.SetFont(pdfFont, "", pdfFontSize)
+		pdf.CellFormat(0, 5, "Generated at "+generated, "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+
+		pdf.SetFont(pdfFont, "B", pdfFontSize)
+		pdf.SetFillColor(230, 230, 230)
+		for _, col := range pdfColumns {
+			pdf.CellFormat(col.width, 6, col.title, "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(pdfFont, "", pdfFontSize)
+	}, true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin)
+		pdf.SetFont(pdfFont, "", pdfFontSize)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("init pdf: %w", err)
+	}
+	return &auditPDFWriter{w: w, pdf: pdf}, nil
+}
+
+func (w *auditPDFWriter) Write(e *domain.AuditEntryWithUser) error {
+	pdf := w.pdf
+
+	changes := auditFieldChanges(e)
+	lines := make([]string, 0, len(changes))
+	changesWidth := pdfColumns[len(pdfColumns)-1].width
+	for _, ch := range changes {
+		text := fmt.Sprintf("%s: %s -> %s", ch.Field, pdfValue(ch.OldValue), pdfValue(ch.NewValue))
+		lines = append(lines, pdf.SplitText(text, changesWidth-2)...)
+	}
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+
+	height := float64(len(lines)) * pdfLineHeight
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+height > pageHeight-2*pdfMargin {
+		pdf.AddPage()
+	}
+
+	x, y := pdf.GetXY()
+	cells := []string{
+		e.ChangedAt.Format("2006-01-02 15:04:05"),
+		string(e.Action),
+		e.Username,
+		string(e.EntityType),
+		e.EntityID.String(),
+	}
+	for i, text := range cells {
+		pdf.CellFormat(pdfColumns[i].width, height, text, "1", 0, "L", false, 0, "")
+	}
+	pdf.MultiCell(changesWidth, pdfLineHeight, strings.Join(lines, "\n"), "1", "L", false)
+
+	// MultiCell переводит курсор сам; выравниваем по высоте строки таблицы
+	pdf.SetXY(x, y+height)
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("write row %d: %w", e.ID, err)
+	}
+	w.rows++
+	return nil
+}
+
+func (w *auditPDFWriter) Close() error {
+	if w.rows == 0 {
+		w.pdf.CellFormat(0, 8, "No audit entries match the filter", "", 1, "L", false, 0, "")
+	}
+	if err := w.pdf.Output(w.w); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
+	}
+	return nil
+}
+
+func pdfValue(v interface{}) string {
+	if v == nil {
+		return "—"
+	}
+	return formatAuditValue(v)
+}
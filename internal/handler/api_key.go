@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+type apiKeyService interface {
+	Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateAPIKeyInput) (*domain.APIKey, error)
+	List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.APIKey, error)
+	Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.APIKey, error)
+	Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error)
+	Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error
+}
+
+type APIKeyHandler struct {
+	service apiKeyService
+	log     logger.Logger
+}
+
+func NewAPIKeyHandler(service apiKeyService, log logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		log:     log.With("handler", "api_key"),
+	}
+}
+
+// POST /api/api-keys
+func (h *APIKeyHandler) Create(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	key, err := h.service.Create(c.Request.Context(), claims, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/api-keys/%s", key.ID))
+	writeJSON(c, http.StatusCreated, dto.NewCreatedAPIKeyResponse(key))
+}
+
+// GET /api/api-keys
+func (h *APIKeyHandler) List(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	keys, err := h.service.List(c.Request.Context(), claims)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAPIKeyListResponse(keys))
+}
+
+// GET /api/api-keys/:id
+func (h *APIKeyHandler) Get(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.service.Get(c.Request.Context(), claims, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAPIKeyResponse(key))
+}
+
+// PATCH /api/api-keys/:id
+func (h *APIKeyHandler) Update(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	key, err := h.service.Update(c.Request.Context(), claims, id, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAPIKeyResponse(key))
+}
+
+// DELETE /api/api-keys/:id
+// Отзывает ключ: запросы с ним сразу перестают приниматься
+func (h *APIKeyHandler) Delete(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), claims, id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseAPIKeyID(c *ginext.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid api key id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
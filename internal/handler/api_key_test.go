@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testAPIKey() *domain.APIKey {
+	return &domain.APIKey{
+		ID:        uuid.New(),
+		Name:      "ERP sync",
+		Prefix:    "wck_0a1b2c3d",
+		KeyHash:   "hash",
+		UserID:    uuid.New(),
+		Role:      domain.RoleManager,
+		ReadOnly:  true,
+		CreatedBy: testAdminClaims.UserID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	svc := newMockapiKeyService(t)
+	h := NewAPIKeyHandler(svc, newTestLogger())
+
+	key := testAPIKey()
+	key.Key = key.Prefix + "_secret"
+	svc.EXPECT().Create(mock.Anything, testAdminClaims, mock.MatchedBy(func(in *domain.CreateAPIKeyInput) bool {
+		return in.Name == "ERP sync" && in.UserID != nil && *in.UserID == key.UserID &&
+			in.Role == domain.RoleManager && in.ReadOnly && in.ExpiresAt == nil
+	})).Return(key, nil)
+
+	body := `{"name":"ERP sync","user_id":"` + key.UserID.String() + `","role":"manager","read_only":true}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/api-keys", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/api-keys/"+key.ID.String(), w.Header().Get("Location"))
+
+	var resp dto.APIKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, key.Key, resp.Key, "key is shown once on creation")
+	assert.Equal(t, key.Prefix, resp.Prefix)
+	assert.NotContains(t, w.Body.String(), key.KeyHash)
+}
+
+func TestAPIKeyHandler_List(t *testing.T) {
+	svc := newMockapiKeyService(t)
+	h := NewAPIKeyHandler(svc, newTestLogger())
+
+	key := testAPIKey()
+	svc.EXPECT().List(mock.Anything, testAdminClaims).Return([]*domain.APIKey{key}, nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/api-keys", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.NotContains(t, resp[0], "key")
+	assert.Equal(t, "wck_0a1b2c3d", resp[0]["prefix"])
+}
+
+func TestAPIKeyHandler_Delete(t *testing.T) {
+	svc := newMockapiKeyService(t)
+	h := NewAPIKeyHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	svc.EXPECT().Delete(mock.Anything, testAdminClaims, id).Return(nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodDelete, "/api/api-keys/"+id.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Delete(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAPIKeyHandler_Get_Forbidden(t *testing.T) {
+	svc := newMockapiKeyService(t)
+	h := NewAPIKeyHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	svc.EXPECT().Get(mock.Anything, testViewerClaims, id).Return(nil, domain.ErrForbidden)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/api-keys/"+id.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testViewerClaims)
+
+	h.Get(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAPIKeyHandler_Update_InvalidID(t *testing.T) {
+	svc := newMockapiKeyService(t)
+	h := NewAPIKeyHandler(svc, newTestLogger())
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodPatch, "/api/api-keys/bad", bytes.NewReader([]byte(`{"name":"x"}`)))
+	c.Params = gin.Params{{Key: "id", Value: "bad"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Update(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
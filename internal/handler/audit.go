@@ -1,16 +1,17 @@
 package handler
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
 	"github.com/wb-go/wbf/ginext"
 	"github.com/wb-go/wbf/logger"
@@ -19,7 +20,9 @@ import (
 type auditService interface {
 	GetByItemID(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error)
 	List(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page, pageSize int) (*domain.AuditList, error)
-	ExportCSV(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, w io.Writer) error
+	Export(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, format export.Format, w io.Writer) (int64, error)
+	Verify(ctx context.Context, claims *domain.AuthClaims, anchor *domain.AuditChainAnchor) (*domain.AuditChainReport, error)
+	Stats(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditStatsFilter) (*domain.AuditStats, error)
 }
 
 type AuditHandler struct {
@@ -81,8 +84,18 @@ func (h *AuditHandler) List(c *ginext.Context) {
 	writeJSON(c, http.StatusOK, dto.NewAuditListFromDomain(list))
 }
 
-// GET /api/audit/export
-func (h *AuditHandler) ExportCSV(c *ginext.Context) {
+// Трейлеры потоковой выгрузки: статус известен только после последней строки
+const (
+	exportStatusTrailer = "X-Export-Status"
+	exportRowsTrailer   = "X-Export-Rows"
+
+	exportStatusComplete  = "complete"
+	exportStatusTruncated = "truncated"
+)
+
+// GET /api/audit/export?format=csv|jsonl|xlsx|pdf
+// Ответ идёт chunked-потоком; в трейлерах X-Export-Status (complete/truncated) и X-Export-Rows.
+func (h *AuditHandler) Export(c *ginext.Context) {
 	claims := getClaims(c)
 	if claims == nil {
 		return //getClaims уже вызвал abort и записал ответ
@@ -94,15 +107,120 @@ func (h *AuditHandler) ExportCSV(c *ginext.Context) {
 		return
 	}
 
-	var buf bytes.Buffer
-	if err = h.service.ExportCSV(c.Request.Context(), claims, filter, &buf); err != nil {
-		writeError(c, err)
+	format, err := export.ParseFormat(c.Query("format"), export.AuditFormats)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "unsupported format (allowed: " + export.FormatList(export.AuditFormats) + ")"})
 		return
 	}
 
-	filename := fmt.Sprintf("audit_%s.csv", time.Now().Format("2006-01-02"))
+	// большая выгрузка не должна обрываться по WriteTimeout сервера
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	filename := fmt.Sprintf("audit_%s.%s", time.Now().Format("2006-01-02"), format.Extension())
 	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
-	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Trailer", exportStatusTrailer+", "+exportRowsTrailer)
+
+	rows, err := h.service.Export(c.Request.Context(), claims, filter, format, c.Writer)
+	if err != nil && !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Trailer")
+		writeError(c, err)
+		return
+	}
+
+	status := exportStatusComplete
+	if err != nil {
+		status = exportStatusTruncated
+		h.log.Ctx(c.Request.Context()).Warn("audit export truncated",
+			"error", err,
+			"rows", rows,
+			"user_id", claims.UserID,
+		)
+	}
+
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Header().Set(exportStatusTrailer, status)
+	c.Writer.Header().Set(exportRowsTrailer, strconv.FormatInt(rows, 10))
+}
+
+// GET /api/audit/verify?anchor=<id>:<hash>
+// Проходит всю цепочку хешей аудита; 200 и в случае разрыва - он описан в поле broken.
+// anchor - звено (last_id и last_hash) из прошлой проверки, сохранённое вне БД.
+func (h *AuditHandler) Verify(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var anchor *domain.AuditChainAnchor
+	if v := c.Query("anchor"); v != "" {
+		a, err := domain.ParseAuditChainAnchor(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid anchor: " + err.Error()})
+			return
+		}
+		anchor = a
+	}
+
+	// проверка читает весь журнал и может идти дольше WriteTimeout сервера
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	report, err := h.service.Verify(c.Request.Context(), claims, anchor)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAuditVerifyResponse(report))
+}
+
+// GET /api/audit/stats
+// Агрегаты за период: изменения по дням, самые активные пользователи,
+// чаще всего меняемые товары и поля, суммарное изменение остатков
+func (h *AuditHandler) Stats(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	filter := &domain.AuditStatsFilter{}
+
+	if v := c.Query("date_from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid date_from: use RFC3339 format"})
+			return
+		}
+		filter.DateFrom = t
+	}
+
+	if v := c.Query("date_to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid date_to: use RFC3339 format"})
+			return
+		}
+		filter.DateTo = t
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), claims, filter)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAuditStatsResponse(stats))
 }
 
 // parseAuditFilter - парсинг query-параметров
@@ -117,6 +235,22 @@ func (h *AuditHandler) parseAuditFilter(c *ginext.Context) (*domain.AuditFilter,
 		filter.ItemID = &id
 	}
 
+	if v := c.Query("entity_type"); v != "" {
+		entityType := domain.AuditEntity(v)
+		if !entityType.IsValid() {
+			return nil, fmt.Errorf("invalid entity_type: %s (allowed: item, user)", v)
+		}
+		filter.EntityType = &entityType
+	}
+
+	if v := c.Query("entity_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entity_id: %s", v)
+		}
+		filter.EntityID = &id
+	}
+
 	if v := c.Query("user_id"); v != "" {
 		id, err := uuid.Parse(v)
 		if err != nil {
@@ -133,6 +267,33 @@ func (h *AuditHandler) parseAuditFilter(c *ginext.Context) (*domain.AuditFilter,
 		filter.Action = &action
 	}
 
+	if v := c.Query("reason"); v != "" {
+		filter.Reason = &v
+	}
+
+	if v := c.Query("reference"); v != "" {
+		filter.Reference = &v
+	}
+
+	if v := c.Query("request_id"); v != "" {
+		filter.RequestID = &v
+	}
+
+	if v := c.Query("field"); v != "" {
+		if !domain.IsAuditFieldName(v) {
+			return nil, fmt.Errorf("invalid field: %s", v)
+		}
+		filter.Field = &v
+	}
+
+	for _, v := range c.QueryArray("change") {
+		p, err := parseAuditValuePredicate(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Changes = append(filter.Changes, *p)
+	}
+
 	if v := c.Query("date_from"); v != "" {
 		t, err := time.Parse(time.RFC3339, v)
 		if err != nil {
@@ -151,3 +312,24 @@ func (h *AuditHandler) parseAuditFilter(c *ginext.Context) (*domain.AuditFilter,
 
 	return filter, nil
 }
+
+// parseAuditValuePredicate разбирает условие вида поле.old|new|delta.оператор.значение,
+// например quantity.delta.lt.-10 или price.new.gte.1000.50; значение может содержать точки
+func parseAuditValuePredicate(v string) (*domain.AuditValuePredicate, error) {
+	parts := strings.SplitN(v, ".", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid change: %s (use field.old|new|delta.op.value)", v)
+	}
+
+	p := &domain.AuditValuePredicate{
+		Field: parts[0],
+		Side:  domain.AuditValueSide(parts[1]),
+		Op:    domain.AuditCompareOp(parts[2]),
+		Value: parts[3],
+	}
+	if !p.IsValid() {
+		return nil, fmt.Errorf(
+			"invalid change: %s (side: old, new, delta; op: eq, ne, gt, gte, lt, lte; delta and gt/lt need a number)", v)
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+type auditArchiveService interface {
+	List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.AuditArchive, error)
+	Attach(ctx context.Context, claims *domain.AuthClaims, id int64) (*domain.AuditArchive, error)
+}
+
+type AuditArchiveHandler struct {
+	service auditArchiveService
+	log     logger.Logger
+}
+
+func NewAuditArchiveHandler(service auditArchiveService, log logger.Logger) *AuditArchiveHandler {
+	return &AuditArchiveHandler{
+		service: service,
+		log:     log.With("handler", "audit_archive"),
+	}
+}
+
+// GET /api/audit/archives
+func (h *AuditArchiveHandler) List(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	archives, err := h.service.List(c.Request.Context(), claims)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAuditArchiveListResponse(archives))
+}
+
+// POST /api/audit/archives/:id/attach
+// Загружает архив обратно в БД; записи снова видны в /api/audit и проверяются в /api/audit/verify
+func (h *AuditArchiveHandler) Attach(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid archive id"})
+		return
+	}
+
+	// загрузка большого архива может идти дольше WriteTimeout сервера
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	archive, err := h.service.Attach(c.Request.Context(), claims, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewAuditArchiveResponse(archive))
+}
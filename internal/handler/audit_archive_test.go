@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditArchiveHandler_List(t *testing.T) {
+	svc := newMockauditArchiveService(t)
+	h := NewAuditArchiveHandler(svc, newTestLogger())
+
+	svc.EXPECT().List(mock.Anything, testAdminClaims).Return([]*domain.AuditArchive{{
+		ID:         3,
+		Partition:  "audit_log_2026_01",
+		RangeFrom:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		RangeTo:    time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		FileName:   "audit_log_2026_01.jsonl.gz",
+		Rows:       120,
+		Status:     domain.AuditArchiveArchived,
+		ArchivedAt: time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC),
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/archives", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AuditArchiveResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, "audit_log_2026_01", resp[0].Partition)
+	assert.Equal(t, "archived", resp[0].Status)
+	assert.Nil(t, resp[0].AttachedAt)
+}
+
+func TestAuditArchiveHandler_Attach(t *testing.T) {
+	svc := newMockauditArchiveService(t)
+	h := NewAuditArchiveHandler(svc, newTestLogger())
+
+	attachedAt := time.Date(2026, 5, 17, 12, 0, 0, 0, time.UTC)
+	svc.EXPECT().Attach(mock.Anything, testAdminClaims, int64(3)).Return(&domain.AuditArchive{
+		ID:         3,
+		Partition:  "audit_log_2026_01",
+		Status:     domain.AuditArchiveAttached,
+		AttachedAt: &attachedAt,
+		AttachedBy: &testAdminClaims.UserID,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/audit/archives/3/attach", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Attach(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AuditArchiveResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "attached", resp.Status)
+	require.NotNil(t, resp.AttachedBy)
+}
+
+func TestAuditArchiveHandler_Attach_InvalidID(t *testing.T) {
+	svc := newMockauditArchiveService(t)
+	h := NewAuditArchiveHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/audit/archives/abc/attach", nil)
+	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Attach(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuditArchiveHandler_Attach_Corrupted(t *testing.T) {
+	svc := newMockauditArchiveService(t)
+	h := NewAuditArchiveHandler(svc, newTestLogger())
+
+	svc.EXPECT().Attach(mock.Anything, testAdminClaims, int64(3)).Return(nil, domain.ErrAuditArchiveCorrupted)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/audit/archives/3/attach", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Attach(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -1,19 +1,25 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuditHandler_GetByItemID_Success(t *testing.T) {
@@ -24,11 +30,12 @@ func TestAuditHandler_GetByItemID_Success(t *testing.T) {
 	entries := []*domain.AuditEntryWithUser{
 		{
 			AuditEntry: domain.AuditEntry{
-				ID:        1,
-				ItemID:    itemID,
-				Action:    domain.AuditInsert,
-				ChangedBy: testAdminClaims.UserID,
-				ChangedAt: time.Now(),
+				ID:         1,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   itemID,
+				Action:     domain.AuditInsert,
+				ChangedBy:  testAdminClaims.UserID,
+				ChangedAt:  time.Now(),
 			},
 			Username: "admin",
 		},
@@ -50,6 +57,9 @@ func TestAuditHandler_GetByItemID_Success(t *testing.T) {
 	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	assert.Len(t, resp, 1)
 	assert.Equal(t, "INSERT", resp[0].Action)
+	assert.Equal(t, "item", resp[0].EntityType)
+	assert.Equal(t, itemID, resp[0].EntityID)
+	assert.Equal(t, &itemID, resp[0].ItemID)
 }
 
 func TestAuditHandler_GetByItemID_InvalidID(t *testing.T) {
@@ -137,6 +147,172 @@ func TestAuditHandler_List_WithFilters(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestAuditHandler_List_EntityFilter(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	userID := uuid.New()
+	list := &domain.AuditList{
+		Entries: []*domain.AuditEntryWithUser{
+			{
+				AuditEntry: domain.AuditEntry{
+					ID:         7,
+					EntityType: domain.AuditEntityUser,
+					EntityID:   userID,
+					Action:     domain.AuditUpdate,
+					ChangedBy:  testAdminClaims.UserID,
+					ChangedAt:  time.Now(),
+				},
+				Username: "admin",
+			},
+		},
+		Total:      1,
+		Page:       1,
+		PageSize:   20,
+		TotalPages: 1,
+	}
+
+	svc.EXPECT().List(mock.Anything, testAdminClaims, mock.MatchedBy(func(f *domain.AuditFilter) bool {
+		return f.EntityType != nil && *f.EntityType == domain.AuditEntityUser &&
+			f.EntityID != nil && *f.EntityID == userID
+	}), 0, 0).Return(list, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit?entity_type=user&entity_id="+userID.String(), nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AuditListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Entries, 1)
+	assert.Equal(t, "user", resp.Entries[0].EntityType)
+	assert.Nil(t, resp.Entries[0].ItemID)
+}
+
+func TestAuditHandler_List_ReasonFilter(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().List(mock.Anything, testAdminClaims, mock.MatchedBy(func(f *domain.AuditFilter) bool {
+		return f.Reason != nil && *f.Reason == "брак" &&
+			f.Reference != nil && *f.Reference == "INV-42"
+	}), 0, 0).Return(&domain.AuditList{Entries: []*domain.AuditEntryWithUser{}}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit?reason=%D0%B1%D1%80%D0%B0%D0%BA&reference=INV-42", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuditHandler_List_RequestIDFilter(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	requestID := "7f3c9e2a-req"
+	clientIP := "10.0.0.7"
+	list := &domain.AuditList{
+		Entries: []*domain.AuditEntryWithUser{
+			{
+				AuditEntry: domain.AuditEntry{
+					ID:         3,
+					EntityType: domain.AuditEntityItem,
+					EntityID:   uuid.New(),
+					Action:     domain.AuditUpdate,
+					ChangedBy:  testAdminClaims.UserID,
+					RequestID:  &requestID,
+					ClientIP:   &clientIP,
+					ChangedAt:  time.Now(),
+				},
+				Username: "admin",
+			},
+		},
+		Total: 1, Page: 1, PageSize: 20, TotalPages: 1,
+	}
+
+	svc.EXPECT().List(mock.Anything, testAdminClaims, mock.MatchedBy(func(f *domain.AuditFilter) bool {
+		return f.RequestID != nil && *f.RequestID == requestID
+	}), 0, 0).Return(list, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit?request_id="+requestID, nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AuditListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Entries, 1)
+	assert.Equal(t, &requestID, resp.Entries[0].RequestID)
+	assert.Equal(t, &clientIP, resp.Entries[0].ClientIP)
+	assert.Nil(t, resp.Entries[0].UserAgent)
+}
+
+func TestAuditHandler_List_ChangeFilter(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().List(mock.Anything, testAdminClaims, mock.MatchedBy(func(f *domain.AuditFilter) bool {
+		return f.Field != nil && *f.Field == "price" &&
+			assert.ObjectsAreEqual([]domain.AuditValuePredicate{
+				{Field: "quantity", Side: domain.AuditValueDelta, Op: domain.AuditOpLt, Value: "-10"},
+				{Field: "price", Side: domain.AuditValueNew, Op: domain.AuditOpGte, Value: "1000.50"},
+			}, f.Changes)
+	}), 0, 0).Return(&domain.AuditList{Entries: []*domain.AuditEntryWithUser{}}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/api/audit?field=price&change=quantity.delta.lt.-10&change=price.new.gte.1000.50", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuditHandler_List_InvalidChange(t *testing.T) {
+	for _, q := range []string{"quantity.delta.lt", "name.delta.eq.x", "quantity.mid.eq.1", "name.new.like.x"} {
+		t.Run(q, func(t *testing.T) {
+			svc := newMockauditService(t)
+			h := NewAuditHandler(svc, newTestLogger())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/audit?change="+q, nil)
+			setAuthClaims(c, testAdminClaims)
+
+			h.List(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestAuditHandler_List_InvalidEntityType(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit?entity_type=order", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestAuditHandler_List_InvalidAction(t *testing.T) {
 	svc := newMockauditService(t)
 	h := NewAuditHandler(svc, newTestLogger())
@@ -165,7 +341,7 @@ func TestAuditHandler_List_InvalidDateFrom(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestAuditHandler_ExportCSV_NoClaims(t *testing.T) {
+func TestAuditHandler_Export_NoClaims(t *testing.T) {
 	svc := newMockauditService(t)
 	h := NewAuditHandler(svc, newTestLogger())
 
@@ -173,7 +349,268 @@ func TestAuditHandler_ExportCSV_NoClaims(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/export", nil)
 
-	h.ExportCSV(c)
+	h.Export(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestAuditHandler_Export_CompleteTrailer(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Export(mock.Anything, testAdminClaims, mock.Anything, export.FormatCSV, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuthClaims, _ *domain.AuditFilter, _ export.Format, w io.Writer) (int64, error) {
+			_, err := io.WriteString(w, "ID,Item ID\n1,x\n")
+			return 1, err
+		})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/export", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Export(c)
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, res.Header.Get("Content-Disposition"), "audit_")
+	assert.Equal(t, "complete", res.Trailer.Get("X-Export-Status"))
+	assert.Equal(t, "1", res.Trailer.Get("X-Export-Rows"))
+}
+
+func TestAuditHandler_Export_TruncatedTrailer(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Export(mock.Anything, testAdminClaims, mock.Anything, export.FormatCSV, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuthClaims, _ *domain.AuditFilter, _ export.Format, w io.Writer) (int64, error) {
+			_, _ = io.WriteString(w, "ID,Item ID\n1,x\n2,y\n")
+			return 2, errors.New("connection reset")
+		})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/export", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Export(c)
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "truncated", res.Trailer.Get("X-Export-Status"))
+	assert.Equal(t, "2", res.Trailer.Get("X-Export-Rows"))
+}
+
+func TestAuditHandler_Export_ErrorBeforeWrite(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Export(mock.Anything, testViewerClaims, mock.Anything, export.FormatCSV, mock.Anything).
+		Return(int64(0), domain.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/export", nil)
+	setAuthClaims(c, testViewerClaims)
+
+	h.Export(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+	assert.Empty(t, w.Header().Get("Trailer"))
+}
+
+func TestAuditHandler_Export_PDF(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Export(mock.Anything, testAdminClaims, mock.Anything, export.FormatPDF, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuthClaims, _ *domain.AuditFilter, _ export.Format, w io.Writer) (int64, error) {
+			_, err := io.WriteString(w, "%PDF-1.3")
+			return 0, err
+		})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/export?format=pdf", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".pdf")
+}
+
+func TestAuditHandler_Export_UnsupportedFormat(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/export?format=docx", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Export(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "pdf")
+}
+
+func TestAuditHandler_Verify_Broken(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Verify(mock.Anything, testAdminClaims, (*domain.AuditChainAnchor)(nil)).Return(&domain.AuditChainReport{
+		Checked: 41,
+		LastID:  41,
+		Broken: &domain.AuditChainBreak{
+			EntryID:  42,
+			Reason:   domain.AuditChainHashMismatch,
+			Expected: "aa",
+			Actual:   "bb",
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/verify", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Verify(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AuditVerifyResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Valid)
+	assert.Equal(t, int64(41), resp.Checked)
+	if assert.NotNil(t, resp.Broken) {
+		assert.Equal(t, int64(42), resp.Broken.EntryID)
+		assert.Equal(t, "hash_mismatch", resp.Broken.Reason)
+	}
+}
+
+func TestAuditHandler_Verify_Forbidden(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Verify(mock.Anything, testViewerClaims, (*domain.AuditChainAnchor)(nil)).Return(nil, domain.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/verify", nil)
+	setAuthClaims(c, testViewerClaims)
+
+	h.Verify(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuditHandler_Verify_Anchor(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	hash := strings.Repeat("ab", 32)
+	svc.EXPECT().Verify(mock.Anything, testAdminClaims, &domain.AuditChainAnchor{EntryID: 40, Hash: hash}).
+		Return(&domain.AuditChainReport{Valid: true, Checked: 41, LastID: 41, LastHash: "cd", AnchorVerified: true}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/verify?anchor=40:"+hash, nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Verify(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.AuditVerifyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.AnchorVerified)
+	assert.Equal(t, "cd", resp.LastHash)
+
+	// неверное звено - 400 без проверки
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/verify?anchor=40", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Verify(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuditHandler_Stats_Success(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	itemID := uuid.New()
+
+	svc.EXPECT().Stats(mock.Anything, testAdminClaims, &domain.AuditStatsFilter{DateFrom: from, DateTo: to, Limit: 5}).
+		Return(&domain.AuditStats{
+			DateFrom: from,
+			DateTo:   to,
+			Daily: []*domain.AuditDailyStat{
+				{Day: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Inserts: 1, Updates: 4},
+			},
+			TopItems:        []*domain.AuditItemStat{{ItemID: itemID, Name: "Widget", Changes: 5}},
+			QuantityChanges: []*domain.AuditQuantityStat{{ItemID: itemID, Name: "Widget", Net: -12}},
+		}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/api/audit/stats?date_from=2026-03-01T00:00:00Z&date_to=2026-03-08T00:00:00Z&limit=5", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Stats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AuditStatsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Daily, 1) {
+		assert.Equal(t, "2026-03-02", resp.Daily[0].Day)
+		assert.Equal(t, int64(4), resp.Daily[0].Updates)
+	}
+	assert.NotNil(t, resp.TopUsers)
+	assert.Empty(t, resp.TopUsers)
+	if assert.Len(t, resp.QuantityChanges, 1) {
+		assert.Equal(t, int64(-12), resp.QuantityChanges[0].Net)
+	}
+}
+
+func TestAuditHandler_Stats_InvalidParams(t *testing.T) {
+	for _, query := range []string{"date_from=yesterday", "date_to=2026-03-08", "limit=ten"} {
+		t.Run(query, func(t *testing.T) {
+			svc := newMockauditService(t)
+			h := NewAuditHandler(svc, newTestLogger())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/stats?"+query, nil)
+			setAuthClaims(c, testAdminClaims)
+
+			h.Stats(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestAuditHandler_Stats_ValidationError(t *testing.T) {
+	svc := newMockauditService(t)
+	h := NewAuditHandler(svc, newTestLogger())
+
+	svc.EXPECT().Stats(mock.Anything, testAdminClaims, mock.Anything).Return(nil, domain.ErrValidation)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/audit/stats?limit=1000", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Stats(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -11,8 +11,12 @@ import (
 )
 
 type authService interface {
-	Login(ctx context.Context, input *domain.LoginInput) (string, *domain.User, error)
-	ListUsers(ctx context.Context) ([]*domain.User, error)
+	Login(ctx context.Context, input *domain.LoginInput) (*domain.TokenPair, *domain.User, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, *domain.User, error)
+	Logout(ctx context.Context, claims *domain.AuthClaims) error
+	ListUsers(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error)
+	Me(ctx context.Context, claims *domain.AuthClaims) (*domain.User, error)
+	ChangePassword(ctx context.Context, claims *domain.AuthClaims, input *domain.ChangePasswordInput) error
 }
 
 type AuthHandler struct {
@@ -27,7 +31,7 @@ func NewAuthHandler(service authService, log logger.Logger) *AuthHandler {
 	}
 }
 
-// POST /api/auth/
+// POST /api/auth/login
 func (h *AuthHandler) Login(c *ginext.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -35,18 +39,56 @@ func (h *AuthHandler) Login(c *ginext.Context) {
 		return
 	}
 
-	token, user, err := h.service.Login(c.Request.Context(), req.ToInput())
+	pair, user, err := h.service.Login(c.Request.Context(), req.ToInput())
 	if err != nil {
 		writeError(c, err)
 		return
 	}
 
-	writeJSON(c, http.StatusOK, dto.NewLoginResponse(token, user))
+	writeJSON(c, http.StatusOK, dto.NewLoginResponse(pair, user))
 }
 
-// GET /api/auth/
+// POST /api/auth/refresh
+// Обменивает refresh-токен на новую пару; предъявленный токен больше не действует
+func (h *AuthHandler) Refresh(c *ginext.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	pair, user, err := h.service.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewLoginResponse(pair, user))
+}
+
+// POST /api/auth/logout
+func (h *AuthHandler) Logout(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), claims); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /api/auth/users
 func (h *AuthHandler) ListUsers(c *ginext.Context) {
-	users, err := h.service.ListUsers(c.Request.Context())
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	users, err := h.service.ListUsers(c.Request.Context(), claims)
 	if err != nil {
 		writeError(c, err)
 		return
@@ -54,3 +96,40 @@ func (h *AuthHandler) ListUsers(c *ginext.Context) {
 
 	writeJSON(c, http.StatusOK, dto.NewUserListResponse(users))
 }
+
+// GET /api/auth/me
+func (h *AuthHandler) Me(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	user, err := h.service.Me(c.Request.Context(), claims)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewUserAccountResponse(user))
+}
+
+// PUT /api/auth/me/password
+func (h *AuthHandler) ChangePassword(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), claims, req.ToInput()); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
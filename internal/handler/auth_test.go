@@ -28,7 +28,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	svc.EXPECT().Login(mock.Anything, &domain.LoginInput{
 		Username: "admin",
 		Password: "password",
-	}).Return("jwt-token", user, nil)
+	}).Return(&domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}, user, nil)
 
 	body, _ := json.Marshal(dto.LoginRequest{Username: "admin", Password: "password"})
 	w := httptest.NewRecorder()
@@ -43,6 +43,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 	var resp dto.LoginResponse
 	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	assert.Equal(t, "jwt-token", resp.Token)
+	assert.Equal(t, "refresh-token", resp.RefreshToken)
 	assert.Equal(t, "admin", resp.User.Username)
 }
 
@@ -64,7 +65,7 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	svc := newMockauthService(t)
 	h := NewAuthHandler(svc, newTestLogger())
 
-	svc.EXPECT().Login(mock.Anything, mock.Anything).Return("", nil, domain.ErrInvalidCredentials)
+	svc.EXPECT().Login(mock.Anything, mock.Anything).Return(nil, nil, domain.ErrInvalidCredentials)
 
 	body, _ := json.Marshal(dto.LoginRequest{Username: "admin", Password: "wrong"})
 	w := httptest.NewRecorder()
@@ -86,11 +87,12 @@ func TestAuthHandler_ListUsers_Success(t *testing.T) {
 		{ID: uuid.New(), Username: "viewer", Role: domain.RoleViewer},
 	}
 
-	svc.EXPECT().ListUsers(mock.Anything).Return(users, nil)
+	svc.EXPECT().ListUsers(mock.Anything, testAdminClaims).Return(users, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/users", nil)
+	setAuthClaims(c, testAdminClaims)
 
 	h.ListUsers(c)
 
@@ -100,3 +102,124 @@ func TestAuthHandler_ListUsers_Success(t *testing.T) {
 	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	assert.Len(t, resp, 2)
 }
+
+func TestAuthHandler_ListUsers_Unauthorized(t *testing.T) {
+	svc := newMockauthService(t)
+	h := NewAuthHandler(svc, newTestLogger())
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/users", nil)
+
+	h.ListUsers(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthHandler_Me(t *testing.T) {
+	svc := newMockauthService(t)
+	h := NewAuthHandler(svc, newTestLogger())
+
+	svc.EXPECT().Me(mock.Anything, testViewerClaims).Return(&domain.User{
+		ID:       testViewerClaims.UserID,
+		Username: testViewerClaims.Username,
+		Role:     domain.RoleViewer,
+	}, nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+	setAuthClaims(c, testViewerClaims)
+
+	h.Me(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.UserAccountResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, testViewerClaims.UserID, resp.ID)
+	assert.Equal(t, "viewer", resp.Role)
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	svc := newMockauthService(t)
+	h := NewAuthHandler(svc, newTestLogger())
+
+	svc.EXPECT().ChangePassword(mock.Anything, testViewerClaims, &domain.ChangePasswordInput{
+		CurrentPassword: "password",
+		NewPassword:     "correct-horse",
+	}).Return(nil).Once()
+	svc.EXPECT().ChangePassword(mock.Anything, testViewerClaims, &domain.ChangePasswordInput{
+		CurrentPassword: "guess",
+		NewPassword:     "correct-horse",
+	}).Return(&domain.ValidationError{Field: "current_password", Reason: "is incorrect"}).Once()
+
+	for _, tt := range []struct {
+		body       string
+		wantStatus int
+	}{
+		{`{"current_password":"password","new_password":"correct-horse"}`, http.StatusNoContent},
+		// неверный текущий пароль - 400, а не 401: клиент не должен завершать сессию
+		{`{"current_password":"guess","new_password":"correct-horse"}`, http.StatusBadRequest},
+		{`{"new_password":"correct-horse"}`, http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/auth/me/password", bytes.NewReader([]byte(tt.body)))
+		c.Request.Header.Set("Content-Type", "application/json")
+		setAuthClaims(c, testViewerClaims)
+
+		h.ChangePassword(c)
+		c.Writer.WriteHeaderNow()
+
+		assert.Equal(t, tt.wantStatus, w.Code, tt.body)
+	}
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	svc := newMockauthService(t)
+	h := NewAuthHandler(svc, newTestLogger())
+
+	user := &domain.User{ID: uuid.New(), Username: "viewer", Role: domain.RoleViewer}
+	svc.EXPECT().Refresh(mock.Anything, "refresh-1").
+		Return(&domain.TokenPair{AccessToken: "jwt-2", RefreshToken: "refresh-2"}, user, nil).Once()
+	svc.EXPECT().Refresh(mock.Anything, "refresh-0").Return(nil, nil, domain.ErrTokenInvalid).Once()
+
+	for _, tt := range []struct {
+		body       string
+		wantStatus int
+	}{
+		{`{"refresh_token":"refresh-1"}`, http.StatusOK},
+		{`{"refresh_token":"refresh-0"}`, http.StatusUnauthorized},
+		{`{}`, http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader([]byte(tt.body)))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.Refresh(c)
+
+		assert.Equal(t, tt.wantStatus, w.Code, tt.body)
+		if tt.wantStatus == http.StatusOK {
+			var resp dto.LoginResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "jwt-2", resp.Token)
+			assert.Equal(t, "refresh-2", resp.RefreshToken)
+		}
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	svc := newMockauthService(t)
+	h := NewAuthHandler(svc, newTestLogger())
+
+	svc.EXPECT().Logout(mock.Anything, testViewerClaims).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+	setAuthClaims(c, testViewerClaims)
+
+	h.Logout(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
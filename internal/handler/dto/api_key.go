@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// CreateAPIKeyRequest - тело POST /api/api-keys. user_id - владелец (по умолчанию сам администратор),
+// role - по умолчанию роль владельца, expires_at - без него ключ бессрочный
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"       binding:"required"`
+	UserID    *uuid.UUID `json:"user_id"`
+	Role      string     `json:"role"`
+	ReadOnly  bool       `json:"read_only"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *CreateAPIKeyRequest) ToInput() *domain.CreateAPIKeyInput {
+	return &domain.CreateAPIKeyInput{
+		Name:      r.Name,
+		UserID:    r.UserID,
+		Role:      domain.Role(r.Role),
+		ReadOnly:  r.ReadOnly,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+// UpdateAPIKeyRequest - тело PATCH /api/api-keys/:id; отсутствующие поля не меняются
+type UpdateAPIKeyRequest struct {
+	Name      *string    `json:"name"`
+	Role      *string    `json:"role"`
+	ReadOnly  *bool      `json:"read_only"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *UpdateAPIKeyRequest) ToInput() *domain.UpdateAPIKeyInput {
+	input := &domain.UpdateAPIKeyInput{
+		Name:      r.Name,
+		ReadOnly:  r.ReadOnly,
+		ExpiresAt: r.ExpiresAt,
+	}
+	if r.Role != nil {
+		role := domain.Role(*r.Role)
+		input.Role = &role
+	}
+	return input
+}
+
+// APIKeyResponse - ключ интеграции; сам ключ (key) отдаётся только при создании
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Role       string     `json:"role"`
+	ReadOnly   bool       `json:"read_only"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func NewAPIKeyResponse(key *domain.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		UserID:     key.UserID,
+		Role:       string(key.Role),
+		ReadOnly:   key.ReadOnly,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+}
+
+// NewCreatedAPIKeyResponse - ответ на создание: единственный раз, когда виден сам ключ
+func NewCreatedAPIKeyResponse(key *domain.APIKey) *APIKeyResponse {
+	resp := NewAPIKeyResponse(key)
+	resp.Key = key.Key
+	return resp
+}
+
+func NewAPIKeyListResponse(keys []*domain.APIKey) []*APIKeyResponse {
+	resp := make([]*APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, NewAPIKeyResponse(key))
+	}
+	return resp
+}
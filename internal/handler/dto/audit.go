@@ -10,8 +10,11 @@ import (
 
 // DTO для записи аудита
 type AuditEntryResponse struct {
-	ID        int64            `json:"id"`
-	ItemID    uuid.UUID        `json:"item_id"`
+	ID         int64     `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	// ItemID оставлен для совместимости, заполняется только у записей по товарам
+	ItemID    *uuid.UUID       `json:"item_id,omitempty"`
 	Action    string           `json:"action"`
 	ChangedBy uuid.UUID        `json:"changed_by"`
 	Username  string           `json:"username"`
@@ -19,6 +22,11 @@ type AuditEntryResponse struct {
 	NewData   json.RawMessage  `json:"new_data,omitempty"`
 	Diff      json.RawMessage  `json:"diff,omitempty"`
 	Changes   []FieldChangeDTO `json:"changes,omitempty"`
+	Reason    *string          `json:"reason,omitempty"`
+	Reference *string          `json:"reference,omitempty"`
+	RequestID *string          `json:"request_id,omitempty"`
+	ClientIP  *string          `json:"client_ip,omitempty"`
+	UserAgent *string          `json:"user_agent,omitempty"`
 	ChangedAt time.Time        `json:"changed_at"`
 }
 
@@ -31,15 +39,25 @@ type FieldChangeDTO struct {
 
 func NewAuditEntryResponse(e *domain.AuditEntryWithUser) *AuditEntryResponse {
 	resp := &AuditEntryResponse{
-		ID:        e.ID,
-		ItemID:    e.ItemID,
-		Action:    string(e.Action),
-		ChangedBy: e.ChangedBy,
-		Username:  e.Username,
-		OldData:   e.OldData,
-		NewData:   e.NewData,
-		Diff:      e.Diff,
-		ChangedAt: e.ChangedAt,
+		ID:         e.ID,
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID,
+		Action:     string(e.Action),
+		ChangedBy:  e.ChangedBy,
+		Username:   e.Username,
+		OldData:    e.OldData,
+		NewData:    e.NewData,
+		Diff:       e.Diff,
+		Reason:     e.Reason,
+		Reference:  e.Reference,
+		RequestID:  e.RequestID,
+		ClientIP:   e.ClientIP,
+		UserAgent:  e.UserAgent,
+		ChangedAt:  e.ChangedAt,
+	}
+	if e.EntityType == domain.AuditEntityItem {
+		itemID := e.EntityID
+		resp.ItemID = &itemID
 	}
 
 	// Парсинг diff в формат для фронтенда
@@ -83,3 +101,43 @@ func NewAuditListFromDomain(list *domain.AuditList) *AuditListResponse {
 		TotalPages: list.TotalPages,
 	}
 }
+
+// AuditVerifyResponse - результат проверки цепочки хешей аудита
+// last_id и last_hash стоит сохранить вне БД и передавать следующим проверкам в anchor
+type AuditVerifyResponse struct {
+	Valid          bool                `json:"valid"`
+	Checked        int64               `json:"checked"`
+	Archived       int64               `json:"archived"`
+	LastID         int64               `json:"last_id"`
+	LastHash       string              `json:"last_hash,omitempty"`
+	AnchorVerified bool                `json:"anchor_verified"`
+	Broken         *AuditChainBreakDTO `json:"broken,omitempty"`
+}
+
+// AuditChainBreakDTO - первое нарушенное звено цепочки
+type AuditChainBreakDTO struct {
+	EntryID  int64  `json:"entry_id"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+func NewAuditVerifyResponse(r *domain.AuditChainReport) *AuditVerifyResponse {
+	resp := &AuditVerifyResponse{
+		Valid:          r.Valid,
+		Checked:        r.Checked,
+		Archived:       r.Archived,
+		LastID:         r.LastID,
+		LastHash:       r.LastHash,
+		AnchorVerified: r.AnchorVerified,
+	}
+	if r.Broken != nil {
+		resp.Broken = &AuditChainBreakDTO{
+			EntryID:  r.Broken.EntryID,
+			Reason:   string(r.Broken.Reason),
+			Expected: r.Broken.Expected,
+			Actual:   r.Broken.Actual,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// AuditArchiveResponse - выгруженная в файл секция журнала аудита
+type AuditArchiveResponse struct {
+	ID         int64      `json:"id"`
+	Partition  string     `json:"partition"`
+	RangeFrom  time.Time  `json:"range_from"`
+	RangeTo    time.Time  `json:"range_to"`
+	FileName   string     `json:"file_name"`
+	FileSize   int64      `json:"file_size"`
+	FileSHA256 string     `json:"file_sha256"`
+	Rows       int64      `json:"rows"`
+	Status     string     `json:"status"`
+	ArchivedAt time.Time  `json:"archived_at"`
+	AttachedAt *time.Time `json:"attached_at,omitempty"`
+	AttachedBy *uuid.UUID `json:"attached_by,omitempty"`
+}
+
+func NewAuditArchiveResponse(a *domain.AuditArchive) *AuditArchiveResponse {
+	return &AuditArchiveResponse{
+		ID:         a.ID,
+		Partition:  a.Partition,
+		RangeFrom:  a.RangeFrom,
+		RangeTo:    a.RangeTo,
+		FileName:   a.FileName,
+		FileSize:   a.FileSize,
+		FileSHA256: a.FileSHA256,
+		Rows:       a.Rows,
+		Status:     string(a.Status),
+		ArchivedAt: a.ArchivedAt,
+		AttachedAt: a.AttachedAt,
+		AttachedBy: a.AttachedBy,
+	}
+}
+
+func NewAuditArchiveListResponse(archives []*domain.AuditArchive) []*AuditArchiveResponse {
+	resp := make([]*AuditArchiveResponse, 0, len(archives))
+	for _, a := range archives {
+		resp = append(resp, NewAuditArchiveResponse(a))
+	}
+	return resp
+}
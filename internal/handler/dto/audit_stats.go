@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// AuditStatsResponse - агрегаты аудита за период [date_from, date_to)
+type AuditStatsResponse struct {
+	DateFrom        time.Time              `json:"date_from"`
+	DateTo          time.Time              `json:"date_to"`
+	Daily           []AuditDailyStatDTO    `json:"daily"`
+	TopUsers        []AuditUserStatDTO     `json:"top_users"`
+	TopItems        []AuditItemStatDTO     `json:"top_items"`
+	TopFields       []AuditFieldStatDTO    `json:"top_fields"`
+	QuantityChanges []AuditQuantityStatDTO `json:"quantity_changes"`
+}
+
+// AuditDailyStatDTO - изменения за день, day в формате 2006-01-02 (UTC)
+type AuditDailyStatDTO struct {
+	Day     string `json:"day"`
+	Inserts int64  `json:"inserts"`
+	Updates int64  `json:"updates"`
+	Deletes int64  `json:"deletes"`
+}
+
+type AuditUserStatDTO struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Changes  int64     `json:"changes"`
+}
+
+type AuditItemStatDTO struct {
+	ItemID  uuid.UUID `json:"item_id"`
+	Name    string    `json:"name"`
+	Changes int64     `json:"changes"`
+}
+
+type AuditFieldStatDTO struct {
+	Field   string `json:"field"`
+	Changes int64  `json:"changes"`
+}
+
+type AuditQuantityStatDTO struct {
+	ItemID uuid.UUID `json:"item_id"`
+	Name   string    `json:"name"`
+	Net    int64     `json:"net"`
+}
+
+func NewAuditStatsResponse(s *domain.AuditStats) *AuditStatsResponse {
+	resp := &AuditStatsResponse{
+		DateFrom:        s.DateFrom,
+		DateTo:          s.DateTo,
+		Daily:           make([]AuditDailyStatDTO, 0, len(s.Daily)),
+		TopUsers:        make([]AuditUserStatDTO, 0, len(s.TopUsers)),
+		TopItems:        make([]AuditItemStatDTO, 0, len(s.TopItems)),
+		TopFields:       make([]AuditFieldStatDTO, 0, len(s.TopFields)),
+		QuantityChanges: make([]AuditQuantityStatDTO, 0, len(s.QuantityChanges)),
+	}
+
+	for _, d := range s.Daily {
+		resp.Daily = append(resp.Daily, AuditDailyStatDTO{
+			Day:     d.Day.Format(time.DateOnly),
+			Inserts: d.Inserts,
+			Updates: d.Updates,
+			Deletes: d.Deletes,
+		})
+	}
+	for _, u := range s.TopUsers {
+		resp.TopUsers = append(resp.TopUsers, AuditUserStatDTO{UserID: u.UserID, Username: u.Username, Changes: u.Changes})
+	}
+	for _, it := range s.TopItems {
+		resp.TopItems = append(resp.TopItems, AuditItemStatDTO{ItemID: it.ItemID, Name: it.Name, Changes: it.Changes})
+	}
+	for _, f := range s.TopFields {
+		resp.TopFields = append(resp.TopFields, AuditFieldStatDTO{Field: f.Field, Changes: f.Changes})
+	}
+	for _, q := range s.QuantityChanges {
+		resp.QuantityChanges = append(resp.QuantityChanges, AuditQuantityStatDTO{ItemID: q.ItemID, Name: q.Name, Net: q.Net})
+	}
+
+	return resp
+}
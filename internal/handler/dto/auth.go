@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 )
@@ -18,6 +20,24 @@ func (r *LoginRequest) ToInput() *domain.LoginInput {
 	}
 }
 
+// RefreshRequest - тело POST /api/auth/refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ChangePasswordRequest - тело PUT /api/auth/me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password"     binding:"required"`
+}
+
+func (r *ChangePasswordRequest) ToInput() *domain.ChangePasswordInput {
+	return &domain.ChangePasswordInput{
+		CurrentPassword: r.CurrentPassword,
+		NewPassword:     r.NewPassword,
+	}
+}
+
 // UserResponse - публичное представление пользователя (без пароля)
 type UserResponse struct {
 	ID       uuid.UUID `json:"id"`
@@ -41,15 +61,82 @@ func NewUserListResponse(users []*domain.User) []*UserResponse {
 	return resp
 }
 
-// DTO ответа на авторизацию
+// DTO ответа на авторизацию и обновление токенов. token - токен доступа
 type LoginResponse struct {
-	Token string        `json:"token"`
-	User  *UserResponse `json:"user"`
+	Token            string        `json:"token"`
+	ExpiresAt        time.Time     `json:"expires_at"`
+	RefreshToken     string        `json:"refresh_token"`
+	RefreshExpiresAt time.Time     `json:"refresh_expires_at"`
+	User             *UserResponse `json:"user"`
 }
 
-func NewLoginResponse(token string, user *domain.User) *LoginResponse {
+func NewLoginResponse(pair *domain.TokenPair, user *domain.User) *LoginResponse {
 	return &LoginResponse{
-		Token: token,
-		User:  NewUserResponse(user),
+		Token:            pair.AccessToken,
+		ExpiresAt:        pair.AccessExpiresAt,
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+		User:             NewUserResponse(user),
+	}
+}
+
+// CreateUserRequest - тело POST /api/users
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role"     binding:"required"`
+	AuditReasonRequest
+}
+
+func (r *CreateUserRequest) ToInput() *domain.CreateUserInput {
+	return &domain.CreateUserInput{
+		Username: r.Username,
+		Password: r.Password,
+		Role:     domain.Role(r.Role),
+	}
+}
+
+// UpdateUserRoleRequest - тело PUT /api/users/:id/role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+	AuditReasonRequest
+}
+
+// ResetPasswordRequest - тело PUT /api/users/:id/password
+type ResetPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+	AuditReasonRequest
+}
+
+// UserAccountResponse - учётная запись для администратора: со статусом и временем смены пароля
+type UserAccountResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	Username          string     `json:"username"`
+	Role              string     `json:"role"`
+	Disabled          bool       `json:"disabled"`
+	DisabledAt        *time.Time `json:"disabled_at,omitempty"`
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func NewUserAccountResponse(u *domain.User) *UserAccountResponse {
+	return &UserAccountResponse{
+		ID:                u.ID,
+		Username:          u.Username,
+		Role:              string(u.Role),
+		Disabled:          u.Disabled(),
+		DisabledAt:        u.DisabledAt,
+		PasswordChangedAt: u.PasswordChangedAt,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
+	}
+}
+
+func NewUserAccountListResponse(users []*domain.User) []*UserAccountResponse {
+	resp := make([]*UserAccountResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, NewUserAccountResponse(u))
 	}
+	return resp
 }
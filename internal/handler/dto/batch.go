@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// DTO для POST /api/items/batch.
+// Mode: atomic (по умолчанию, всё или ничего) или best_effort (построчные результаты)
+type BatchRequest struct {
+	Mode       string                   `json:"mode"       binding:"omitempty,oneof=atomic best_effort"`
+	Operations []*BatchOperationRequest `json:"operations" binding:"required,min=1,max=1000,dive,required"`
+	AuditReasonRequest
+}
+
+// BatchOperationRequest - одна операция пачки:
+// create - item, update - id и changes, delete - id.
+// Поля item и changes при разборе запроса не проверяются: ошибка строки не должна
+// отклонять всю пачку, их проверяет handler построчно (Invalid операции)
+type BatchOperationRequest struct {
+	Op      string             `json:"op"      binding:"required,oneof=create update delete"`
+	ID      *uuid.UUID         `json:"id"`
+	Item    *CreateItemRequest `json:"item"    binding:"-"`
+	Changes *UpdateItemRequest `json:"changes" binding:"-"`
+}
+
+func (r *BatchRequest) ToInput() ([]*domain.BatchOperation, domain.BatchMode) {
+	ops := make([]*domain.BatchOperation, 0, len(r.Operations))
+	for _, o := range r.Operations {
+		bop := &domain.BatchOperation{
+			Op: domain.BatchOp(o.Op),
+			ID: o.ID,
+		}
+		if o.Item != nil {
+			bop.Create = o.Item.ToInput()
+		}
+		if o.Changes != nil {
+			bop.Update = o.Changes.ToInput()
+		}
+		ops = append(ops, bop)
+	}
+	return ops, domain.BatchMode(r.Mode)
+}
+
+// BatchItemResultResponse - результат одной операции пачки
+type BatchItemResultResponse struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	ID     *uuid.UUID    `json:"id,omitempty"`
+	Status string        `json:"status"`
+	Item   *ItemResponse `json:"item,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// BatchResponse - DTO ответа на POST /api/items/batch
+type BatchResponse struct {
+	Mode      string                     `json:"mode"`
+	Succeeded int                        `json:"succeeded"`
+	Failed    int                        `json:"failed"`
+	Results   []*BatchItemResultResponse `json:"results"`
+}
+
+// NewBatchResponse собирает ответ; errMessage превращает ошибку строки в публичное сообщение
+func NewBatchResponse(res *domain.BatchResult, errMessage func(error) string) *BatchResponse {
+	resp := &BatchResponse{
+		Mode:      string(res.Mode),
+		Succeeded: res.Succeeded,
+		Failed:    res.Failed,
+		Results:   make([]*BatchItemResultResponse, 0, len(res.Results)),
+	}
+
+	for _, r := range res.Results {
+		item := &BatchItemResultResponse{
+			Index:  r.Index,
+			Op:     string(r.Op),
+			ID:     r.ID,
+			Status: "ok",
+		}
+		if r.Err != nil {
+			item.Status = "error"
+			item.Error = errMessage(r.Err)
+		}
+		if r.Item != nil {
+			item.Item = NewItemResponse(r.Item)
+			item.ID = &r.Item.ID
+		}
+		resp.Results = append(resp.Results, item)
+	}
+
+	return resp
+}
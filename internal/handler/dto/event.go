@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// ChangeEventResponse - событие ленты для роли без доступа к журналу: только новое состояние товара
+type ChangeEventResponse struct {
+	ID         int64           `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	Action     string          `json:"action"`
+	NewData    json.RawMessage `json:"new_data,omitempty"`
+	ChangedAt  time.Time       `json:"changed_at"`
+}
+
+// NewChangeEventResponse - данные SSE-события: полная запись аудита или урезанное событие
+func NewChangeEventResponse(ev *domain.ChangeEvent) interface{} {
+	if ev.Detailed {
+		return NewAuditEntryResponse(ev.Entry)
+	}
+	return &ChangeEventResponse{
+		ID:         ev.Entry.ID,
+		EntityType: string(ev.Entry.EntityType),
+		EntityID:   ev.Entry.EntityID,
+		Action:     string(ev.Entry.Action),
+		NewData:    ev.Entry.NewData,
+		ChangedAt:  ev.Entry.ChangedAt,
+	}
+}
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// CreateExportJobRequest - тело POST /api/exports
+type CreateExportJobRequest struct {
+	Type    string              `json:"type"    binding:"required,oneof=audit items"`
+	Format  string              `json:"format"`
+	Columns []string            `json:"columns"`
+	Filter  ExportFilterRequest `json:"filter"`
+}
+
+// ExportFilterRequest - фильтр выгрузки: search для items, остальные поля для audit
+type ExportFilterRequest struct {
+	Search     *string    `json:"search"`
+	ItemID     *uuid.UUID `json:"item_id"`
+	EntityType *string    `json:"entity_type"`
+	EntityID   *uuid.UUID `json:"entity_id"`
+	UserID     *uuid.UUID `json:"user_id"`
+	Action     *string    `json:"action"`
+	Reason     *string    `json:"reason"`
+	Reference  *string    `json:"reference"`
+	RequestID  *string    `json:"request_id"`
+	Field      *string    `json:"field"`
+	// Changes - условия на значения полей, как ?change= у GET /api/audit, но объектами
+	Changes  []AuditValuePredicateRequest `json:"changes"`
+	DateFrom *time.Time                   `json:"date_from"`
+	DateTo   *time.Time                   `json:"date_to"`
+}
+
+// AuditValuePredicateRequest - условие на значение поля в diff: {"field": "quantity", "side": "delta", "op": "lt", "value": "-10"}
+type AuditValuePredicateRequest struct {
+	Field string `json:"field"`
+	Side  string `json:"side"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+func (r *CreateExportJobRequest) ToInput() *domain.CreateExportJobInput {
+	input := &domain.CreateExportJobInput{
+		Type:    domain.ExportJobType(r.Type),
+		Format:  r.Format,
+		Columns: r.Columns,
+	}
+
+	switch input.Type {
+	case domain.ExportJobItems:
+		input.ItemFilter = &domain.ItemFilter{Search: r.Filter.Search}
+	case domain.ExportJobAudit:
+		input.AuditFilter = &domain.AuditFilter{
+			ItemID:    r.Filter.ItemID,
+			EntityID:  r.Filter.EntityID,
+			UserID:    r.Filter.UserID,
+			Reason:    r.Filter.Reason,
+			Reference: r.Filter.Reference,
+			RequestID: r.Filter.RequestID,
+			Field:     r.Filter.Field,
+			DateFrom:  r.Filter.DateFrom,
+			DateTo:    r.Filter.DateTo,
+		}
+		for _, c := range r.Filter.Changes {
+			input.AuditFilter.Changes = append(input.AuditFilter.Changes, domain.AuditValuePredicate{
+				Field: c.Field,
+				Side:  domain.AuditValueSide(c.Side),
+				Op:    domain.AuditCompareOp(c.Op),
+				Value: c.Value,
+			})
+		}
+		if r.Filter.EntityType != nil {
+			entityType := domain.AuditEntity(*r.Filter.EntityType)
+			input.AuditFilter.EntityType = &entityType
+		}
+		if r.Filter.Action != nil {
+			action := domain.AuditAction(*r.Filter.Action)
+			input.AuditFilter.Action = &action
+		}
+	}
+
+	return input
+}
+
+// ExportJobResponse - состояние фоновой выгрузки
+type ExportJobResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Type        string     `json:"type"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	TotalRows   int64      `json:"total_rows"`
+	Processed   int64      `json:"processed_rows"`
+	Progress    float64    `json:"progress"`
+	FileSize    int64      `json:"file_size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+func NewExportJobResponse(job *domain.ExportJob) *ExportJobResponse {
+	resp := &ExportJobResponse{
+		ID:         job.ID,
+		Type:       string(job.Type),
+		Format:     job.Format,
+		Status:     string(job.Status),
+		TotalRows:  job.TotalRows,
+		Processed:  job.Processed,
+		FileSize:   job.FileSize,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		ExpiresAt:  job.ExpiresAt,
+	}
+
+	// прогресс в процентах, 100 - только для готового файла
+	switch {
+	case job.Status == domain.ExportJobDone:
+		resp.Progress = 100
+		resp.DownloadURL = fmt.Sprintf("/api/exports/%s/download", job.ID)
+	case job.TotalRows > 0:
+		resp.Progress = min(99, float64(job.Processed*100/job.TotalRows))
+	}
+
+	return resp
+}
@@ -8,13 +8,25 @@ import (
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 )
 
+// AuditReasonRequest - необязательные причина изменения и ссылка на документ (номер накладной и т.п.),
+// попадают в журнал аудита
+type AuditReasonRequest struct {
+	Reason    string `json:"reason"    form:"reason"    binding:"max=1000"`
+	Reference string `json:"reference" form:"reference" binding:"max=128"`
+}
+
+func (r *AuditReasonRequest) ToDomain() domain.AuditReason {
+	return domain.AuditReason{Reason: r.Reason, Reference: r.Reference}
+}
+
 // DTO для POST /api/items.
 type CreateItemRequest struct {
 	Name     string          `json:"name"     binding:"required,max=255"`
 	SKU      string          `json:"sku"      binding:"required,max=64"`
-	Quantity int             `json:"quantity"  binding:"gte=0"`
+	Quantity int             `json:"quantity"  binding:"gte=0,lte=2147483647"`
 	Price    decimal.Decimal `json:"price"    binding:"required"`
 	Location *string         `json:"location" binding:"omitempty,max=128"`
+	AuditReasonRequest
 }
 
 func (r *CreateItemRequest) ToInput() *domain.CreateItemInput {
@@ -31,9 +43,10 @@ func (r *CreateItemRequest) ToInput() *domain.CreateItemInput {
 type UpdateItemRequest struct {
 	Name     *string          `json:"name"     binding:"omitempty,max=255"`
 	SKU      *string          `json:"sku"      binding:"omitempty,max=64"`
-	Quantity *int             `json:"quantity"  binding:"omitempty,gte=0"`
+	Quantity *int             `json:"quantity"  binding:"omitempty,gte=0,lte=2147483647"`
 	Price    *decimal.Decimal `json:"price"`
 	Location *string          `json:"location" binding:"omitempty,max=128"`
+	AuditReasonRequest
 }
 
 func (r *UpdateItemRequest) ToInput() *domain.UpdateItemInput {
@@ -46,6 +59,19 @@ func (r *UpdateItemRequest) ToInput() *domain.UpdateItemInput {
 	}
 }
 
+// DTO для POST /api/items/:id/adjust.
+// Delta - относительное изменение остатка: +5 приход, -3 списание; остаток в БД - int4
+type AdjustQuantityRequest struct {
+	Delta int `json:"delta" binding:"required,min=-2147483648,max=2147483647"`
+	AuditReasonRequest
+}
+
+func (r *AdjustQuantityRequest) ToInput() *domain.AdjustQuantityInput {
+	return &domain.AdjustQuantityInput{
+		Delta: r.Delta,
+	}
+}
+
 // ItemResponse - DTO ответа для одного товара
 type ItemResponse struct {
 	ID        uuid.UUID       `json:"id"`
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// NewCreateItemRequestFromRow переводит строку файла импорта (поле -> значение) в CreateItemRequest.
+// Возвращает *domain.ValidationError, если число или цена не разбираются.
+func NewCreateItemRequestFromRow(fields map[string]string) (*CreateItemRequest, error) {
+	req := &CreateItemRequest{
+		Name: fields["name"],
+		SKU:  fields["sku"],
+	}
+
+	if v := fields["quantity"]; v != "" {
+		qty, err := strconv.Atoi(strings.TrimSuffix(v, ".0"))
+		if err != nil {
+			return nil, &domain.ValidationError{Field: "quantity", Reason: fmt.Sprintf("not an integer: %q", v)}
+		}
+		req.Quantity = qty
+	}
+
+	if v := fields["price"]; v != "" {
+		// Excel в русской локали пишет десятичную запятую
+		price, err := decimal.NewFromString(strings.ReplaceAll(strings.ReplaceAll(v, " ", ""), ",", "."))
+		if err != nil {
+			return nil, &domain.ValidationError{Field: "price", Reason: fmt.Sprintf("not a number: %q", v)}
+		}
+		if price.IsNegative() {
+			return nil, &domain.ValidationError{Field: "price", Reason: "must be greater than or equal to 0"}
+		}
+		req.Price = price
+	}
+
+	if v := fields["location"]; v != "" {
+		req.Location = &v
+	}
+
+	return req, nil
+}
+
+// ImportRowResponse - итог по одной строке файла
+type ImportRowResponse struct {
+	Line   int        `json:"line"`
+	SKU    string     `json:"sku,omitempty"`
+	Action string     `json:"action"`
+	ItemID *uuid.UUID `json:"item_id,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// ImportReportResponse - DTO ответа на POST /api/items/import
+type ImportReportResponse struct {
+	DryRun    bool                 `json:"dry_run"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Failed    int                  `json:"failed"`
+	Rows      []*ImportRowResponse `json:"rows"`
+}
+
+// NewImportReportResponse собирает отчёт; errMessage превращает ошибку строки в публичное сообщение
+func NewImportReportResponse(report *domain.ImportReport, errMessage func(error) string) *ImportReportResponse {
+	resp := &ImportReportResponse{
+		DryRun:    report.DryRun,
+		Created:   report.Created,
+		Updated:   report.Updated,
+		Unchanged: report.Unchanged,
+		Failed:    report.Failed,
+		Rows:      make([]*ImportRowResponse, 0, len(report.Rows)),
+	}
+
+	for _, r := range report.Rows {
+		row := &ImportRowResponse{
+			Line:   r.Line,
+			SKU:    r.SKU,
+			Action: string(r.Action),
+			ItemID: r.ItemID,
+		}
+		if r.Err != nil {
+			row.Error = errMessage(r.Err)
+		}
+		resp.Rows = append(resp.Rows, row)
+	}
+
+	return resp
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// JWK - открытый ключ проверки токенов доступа (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 (RFC 8037)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSResponse - GET /.well-known/jwks.json
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWKSResponse - ключи неизвестного типа пропускаются
+func NewJWKSResponse(keys []*domain.PublicKey) *JWKSResponse {
+	res := &JWKSResponse{Keys: make([]JWK, 0, len(keys))}
+	for _, k := range keys {
+		jwk := JWK{Use: "sig", Alg: k.Algorithm, Kid: k.ID}
+		switch pub := k.Key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		res.Keys = append(res.Keys, jwk)
+	}
+	return res
+}
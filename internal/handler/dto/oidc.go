@@ -0,0 +1,17 @@
+package dto
+
+// OIDCProviderResponse - ответ GET /api/auth/oidc: название провайдера для кнопки входа
+type OIDCProviderResponse struct {
+	Name string `json:"name"`
+}
+
+// OIDCLoginResponse - ответ POST /api/auth/oidc/login: страница входа провайдера
+type OIDCLoginResponse struct {
+	AuthURL string `json:"auth_url"`
+}
+
+// OIDCCallbackRequest - тело POST /api/auth/oidc/callback: параметры, с которыми провайдер вернул пользователя
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"  binding:"required"`
+	State string `json:"state" binding:"required"`
+}
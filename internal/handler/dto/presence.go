@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Типы сообщений канала присутствия (/api/ws/presence)
+const (
+	PresenceSubscribe   = "subscribe"
+	PresenceUnsubscribe = "unsubscribe"
+	PresenceEditing     = "editing"
+	PresencePing        = "ping"
+	PresencePong        = "pong"
+
+	PresenceUpdateType  = "presence"
+	PresenceItemChanged = "item_changed"
+	PresenceError       = "error"
+)
+
+// PresenceRequest - сообщение клиента
+type PresenceRequest struct {
+	Type    string `json:"type"`
+	ItemID  string `json:"item_id"`
+	Editing *bool  `json:"editing"`
+}
+
+type PresenceUserDTO struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Editing  bool      `json:"editing"`
+	Since    time.Time `json:"since"`
+}
+
+// PresenceResponse - сообщение сервера: состав открывших товар, изменение товара, ошибка или ping
+type PresenceResponse struct {
+	Type   string            `json:"type"`
+	ItemID *uuid.UUID        `json:"item_id,omitempty"`
+	Users  []PresenceUserDTO `json:"users,omitempty"`
+	// Event - изменение товара в том же виде, что и в /api/events
+	Event interface{} `json:"event,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+func NewPresenceResponse(msg *domain.PresenceMessage) *PresenceResponse {
+	if msg.Change != nil {
+		itemID := msg.Change.Entry.EntityID
+		return &PresenceResponse{
+			Type:   PresenceItemChanged,
+			ItemID: &itemID,
+			Event:  NewChangeEventResponse(msg.Change),
+		}
+	}
+
+	itemID := msg.Presence.ItemID
+	users := make([]PresenceUserDTO, 0, len(msg.Presence.Users))
+	for _, u := range msg.Presence.Users {
+		users = append(users, PresenceUserDTO{
+			UserID:   u.UserID,
+			Username: u.Username,
+			Role:     string(u.Role),
+			Editing:  u.Editing,
+			Since:    u.Since,
+		})
+	}
+	return &PresenceResponse{
+		Type:   PresenceUpdateType,
+		ItemID: &itemID,
+		Users:  users,
+	}
+}
@@ -0,0 +1,159 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// CreateWebhookRequest - тело POST /api/webhooks; без secret ключ подписи генерирует сервер
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"         binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+func (r *CreateWebhookRequest) ToInput() *domain.CreateWebhookInput {
+	return &domain.CreateWebhookInput{
+		URL:        r.URL,
+		EventTypes: toEventTypes(r.EventTypes),
+		Secret:     r.Secret,
+		Active:     r.Active,
+	}
+}
+
+// UpdateWebhookRequest - тело PATCH /api/webhooks/:id; отсутствующие поля не меняются
+type UpdateWebhookRequest struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     *string  `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+func (r *UpdateWebhookRequest) ToInput() *domain.UpdateWebhookInput {
+	return &domain.UpdateWebhookInput{
+		URL:        r.URL,
+		EventTypes: toEventTypes(r.EventTypes),
+		Secret:     r.Secret,
+		Active:     r.Active,
+	}
+}
+
+func toEventTypes(types []string) []domain.OutboxEventType {
+	if types == nil {
+		return nil
+	}
+	res := make([]domain.OutboxEventType, 0, len(types))
+	for _, t := range types {
+		res = append(res, domain.OutboxEventType(t))
+	}
+	return res
+}
+
+// WebhookResponse - подписка; Secret отдаётся только при создании
+type WebhookResponse struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedBy  uuid.UUID `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func NewWebhookResponse(hook *domain.Webhook) *WebhookResponse {
+	eventTypes := make([]string, 0, len(hook.EventTypes))
+	for _, t := range hook.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return &WebhookResponse{
+		ID:         hook.ID,
+		URL:        hook.URL,
+		EventTypes: eventTypes,
+		Active:     hook.Active,
+		CreatedBy:  hook.CreatedBy,
+		CreatedAt:  hook.CreatedAt,
+		UpdatedAt:  hook.UpdatedAt,
+	}
+}
+
+// NewCreatedWebhookResponse - ответ на создание: единственный раз, когда виден ключ подписи
+func NewCreatedWebhookResponse(hook *domain.Webhook) *WebhookResponse {
+	resp := NewWebhookResponse(hook)
+	resp.Secret = hook.Secret
+	return resp
+}
+
+func NewWebhookListResponse(hooks []*domain.Webhook) []*WebhookResponse {
+	resp := make([]*WebhookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		resp = append(resp, NewWebhookResponse(hook))
+	}
+	return resp
+}
+
+// WebhookDeliveryResponse - запись журнала доставок; response_* и error - последняя попытка
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	DurationMS     int64           `json:"duration_ms"`
+	RedeliveryOf   *uuid.UUID      `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+func NewWebhookDeliveryResponse(d *domain.WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		ResponseBody:   d.ResponseBody,
+		Error:          d.Error,
+		DurationMS:     d.Duration.Milliseconds(),
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+// WebhookDeliveryListResponse - журнал доставок с пагинацией
+type WebhookDeliveryListResponse struct {
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	TotalPages int                        `json:"total_pages"`
+}
+
+func NewWebhookDeliveryListResponse(list *domain.WebhookDeliveryList) *WebhookDeliveryListResponse {
+	deliveries := make([]*WebhookDeliveryResponse, 0, len(list.Deliveries))
+	for _, d := range list.Deliveries {
+		deliveries = append(deliveries, NewWebhookDeliveryResponse(d))
+	}
+	return &WebhookDeliveryListResponse{
+		Deliveries: deliveries,
+		Total:      list.Total,
+		Page:       list.Page,
+		PageSize:   list.PageSize,
+		TotalPages: list.TotalPages,
+	}
+}
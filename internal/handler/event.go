@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+// eventReset - событие для клиента, который пропустил слишком много: данные нужно перечитать целиком
+const eventReset = "reset"
+
+type eventService interface {
+	Subscribe(ctx context.Context, claims *domain.AuthClaims, lastEventID int64) (*domain.EventSubscription, error)
+}
+
+// revocationList - отозванные токены доступа по jti (auth.Denylist). Долгоживущие соединения
+// (/api/events, /api/ws/presence) сверяются с ним периодически, а не только при подключении
+type revocationList interface {
+	IsRevoked(jti uuid.UUID) bool
+}
+
+type EventHandler struct {
+	service   eventService
+	revoked   revocationList
+	heartbeat time.Duration
+	log       logger.Logger
+}
+
+// NewEventHandler - heartbeat: как часто слать комментарий в пустой поток, чтобы его не закрыли прокси.
+// С той же частотой токен потока проверяется по revoked: отозванный токен завершает поток.
+func NewEventHandler(service eventService, revoked revocationList, heartbeat time.Duration, log logger.Logger) *EventHandler {
+	if heartbeat <= 0 {
+		heartbeat = 25 * time.Second
+	}
+	return &EventHandler{
+		service:   service,
+		revoked:   revoked,
+		heartbeat: heartbeat,
+		log:       log.With("handler", "event"),
+	}
+}
+
+// GET /api/events
+// Лента изменений (Server-Sent Events): событие на каждую новую запись аудита,
+// id события - id записи в audit_log, тип - сущность (item, user).
+// Переподключение с заголовком Last-Event-ID (или ?last_event_id=) досылает пропущенное.
+// Поток завершается, когда истекает или отзывается токен, с которым он открыт: клиент переподключается с новым.
+func (h *EventHandler) Stream(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	var lastEventID int64
+	if raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 0 {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		lastEventID = id
+	}
+
+	ctx := c.Request.Context()
+	sub, err := h.service.Subscribe(ctx, claims, lastEventID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer sub.Close()
+
+	// поток живёт, пока клиент не отключится, а токен не истечёт и не будет отозван
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+
+	if sub.Reset {
+		if _, err = fmt.Fprintf(c.Writer, "event: %s\ndata: {}\n\n", eventReset); err != nil {
+			return
+		}
+	}
+	for _, ev := range sub.Replay {
+		if err = writeEvent(c.Writer, ev); err != nil {
+			return
+		}
+	}
+	if err = rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	// у API-ключа срока в claims нет
+	var expired <-chan time.Time
+	if !claims.ExpiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(claims.ExpiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			// клиент переподключится с новым токеном и Last-Event-ID
+			return
+		case ev, ok := <-sub.Events:
+			if !ok {
+				// лента закрыта или клиент не успевал читать - он переподключится с Last-Event-ID
+				return
+			}
+			if err = writeEvent(c.Writer, ev); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// выход, смена пароля или роли, отключение пользователя
+			if claims.TokenID != uuid.Nil && h.revoked.IsRevoked(claims.TokenID) {
+				return
+			}
+			if _, err = fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err = rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev *domain.ChangeEvent) error {
+	data, err := json.Marshal(dto.NewChangeEventResponse(ev))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Entry.ID, ev.Entry.EntityType, data)
+	return err
+}
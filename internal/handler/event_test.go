@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testChangeEvent(id int64, detailed bool) *domain.ChangeEvent {
+	return &domain.ChangeEvent{
+		Entry: &domain.AuditEntryWithUser{
+			AuditEntry: domain.AuditEntry{
+				ID:         id,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   uuid.New(),
+				Action:     domain.AuditUpdate,
+				ChangedBy:  testAdminClaims.UserID,
+				NewData:    json.RawMessage(`{"quantity": 7}`),
+				Diff:       json.RawMessage(`{"quantity": {"new": 7, "old": 10}}`),
+				ChangedAt:  time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC),
+			},
+			Username: "admin",
+		},
+		Detailed: detailed,
+	}
+}
+
+func TestEventHandler_Stream(t *testing.T) {
+	svc := newMockeventService(t)
+	h := NewEventHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+
+	events := make(chan *domain.ChangeEvent, 1)
+	closed := false
+	sub := domain.NewEventSubscription(events, func() { closed = true })
+	sub.Reset = true
+	sub.Replay = []*domain.ChangeEvent{testChangeEvent(11, false)}
+
+	svc.EXPECT().Subscribe(mock.Anything, testViewerClaims, int64(10)).Return(sub, nil)
+
+	events <- testChangeEvent(12, false)
+	close(events)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	c.Request.Header.Set("Last-Event-ID", "10")
+	setAuthClaims(c, testViewerClaims)
+
+	h.Stream(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.True(t, closed, "subscription must be closed")
+
+	frames := strings.Split(strings.TrimSpace(w.Body.String()), "\n\n")
+	require.Len(t, frames, 3)
+	assert.Equal(t, "event: reset\ndata: {}", frames[0])
+
+	lines := strings.Split(frames[1], "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "id: 11", lines[0])
+	assert.Equal(t, "event: item", lines[1])
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &data))
+	assert.NotContains(t, data, "changed_by", "viewer must not see the audit details")
+	assert.NotContains(t, data, "diff")
+	assert.Contains(t, data, "new_data")
+
+	assert.True(t, strings.HasPrefix(frames[2], "id: 12\nevent: item\n"))
+}
+
+func TestEventHandler_Stream_Detailed(t *testing.T) {
+	svc := newMockeventService(t)
+	h := NewEventHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+
+	events := make(chan *domain.ChangeEvent, 1)
+	events <- testChangeEvent(5, true)
+	close(events)
+	svc.EXPECT().Subscribe(mock.Anything, testAdminClaims, int64(0)).
+		Return(domain.NewEventSubscription(events, func() {}), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Stream(c)
+
+	body := strings.TrimSpace(w.Body.String())
+	data := body[strings.Index(body, "data: ")+len("data: "):]
+
+	var resp dto.AuditEntryResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &resp))
+	assert.Equal(t, "admin", resp.Username)
+	require.Len(t, resp.Changes, 1)
+	assert.Equal(t, "quantity", resp.Changes[0].Field)
+}
+
+func TestEventHandler_Stream_LastEventIDQuery(t *testing.T) {
+	svc := newMockeventService(t)
+	h := NewEventHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+
+	events := make(chan *domain.ChangeEvent)
+	close(events)
+	svc.EXPECT().Subscribe(mock.Anything, testAdminClaims, int64(42)).
+		Return(domain.NewEventSubscription(events, func() {}), nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events?last_event_id=42", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Stream(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestEventHandler_Stream_InvalidLastEventID(t *testing.T) {
+	svc := newMockeventService(t)
+	h := NewEventHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	c.Request.Header.Set("Last-Event-ID", "abc")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Stream(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEventHandler_Stream_Unavailable(t *testing.T) {
+	svc := newMockeventService(t)
+	h := NewEventHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+
+	svc.EXPECT().Subscribe(mock.Anything, testAdminClaims, int64(0)).Return(nil, domain.ErrEventsUnavailable)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Stream(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestEventHandler_Stream_TokenExpired(t *testing.T) {
+	svc := newMockeventService(t)
+	h := NewEventHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+
+	// лента открыта и молчит: поток завершает только истечение токена
+	events := make(chan *domain.ChangeEvent)
+	closed := false
+	sub := domain.NewEventSubscription(events, func() { closed = true })
+	sub.Replay = []*domain.ChangeEvent{testChangeEvent(3, false)}
+
+	claims := *testViewerClaims
+	claims.ExpiresAt = time.Now().Add(50 * time.Millisecond)
+	svc.EXPECT().Subscribe(mock.Anything, &claims, int64(0)).Return(sub, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	setAuthClaims(c, &claims)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Stream(c)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream must end when the token expires")
+	}
+	assert.True(t, closed, "subscription must be closed")
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id: 3\n"))
+}
+
+func TestEventHandler_Stream_TokenRevoked(t *testing.T) {
+	svc := newMockeventService(t)
+	revoked := newMockrevocationList(t)
+	h := NewEventHandler(svc, revoked, 20*time.Millisecond, newTestLogger())
+
+	events := make(chan *domain.ChangeEvent)
+	closed := false
+	sub := domain.NewEventSubscription(events, func() { closed = true })
+
+	claims := *testViewerClaims
+	claims.TokenID = uuid.New()
+	claims.ExpiresAt = time.Now().Add(time.Hour)
+	svc.EXPECT().Subscribe(mock.Anything, &claims, int64(0)).Return(sub, nil)
+	revoked.EXPECT().IsRevoked(claims.TokenID).Return(false).Once()
+	revoked.EXPECT().IsRevoked(claims.TokenID).Return(true).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	setAuthClaims(c, &claims)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Stream(c)
+	}()
+
+	// токен отозван после подключения - поток завершается на следующей проверке
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream must end when the token is revoked")
+	}
+	assert.True(t, closed, "subscription must be closed")
+	assert.Equal(t, ": ping\n\n", w.Body.String())
+}
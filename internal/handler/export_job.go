@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+type exportJobService interface {
+	Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateExportJobInput) (*domain.ExportJob, error)
+	Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, error)
+	Download(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, string, error)
+}
+
+type ExportJobHandler struct {
+	service exportJobService
+	log     logger.Logger
+}
+
+func NewExportJobHandler(service exportJobService, log logger.Logger) *ExportJobHandler {
+	return &ExportJobHandler{
+		service: service,
+		log:     log.With("handler", "export_job"),
+	}
+}
+
+// POST /api/exports
+func (h *ExportJobHandler) Create(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var req dto.CreateExportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	job, err := h.service.Create(c.Request.Context(), claims, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/exports/%s", job.ID))
+	writeJSON(c, http.StatusAccepted, dto.NewExportJobResponse(job))
+}
+
+// GET /api/exports/:id
+func (h *ExportJobHandler) Get(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid export id"})
+		return
+	}
+
+	job, err := h.service.Get(c.Request.Context(), claims, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewExportJobResponse(job))
+}
+
+// GET /api/exports/:id/download
+func (h *ExportJobHandler) Download(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid export id"})
+		return
+	}
+
+	job, path, err := h.service.Download(c.Request.Context(), claims, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s", job.Type, job.CreatedAt.Format("2006-01-02"), job.Format)
+	c.FileAttachment(path, filename)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJobHandler_Create(t *testing.T) {
+	svc := newMockexportJobService(t)
+	h := NewExportJobHandler(svc, newTestLogger())
+
+	job := &domain.ExportJob{ID: uuid.New(), Type: domain.ExportJobAudit, Format: "csv", Status: domain.ExportJobPending}
+	svc.EXPECT().Create(mock.Anything, testAdminClaims, mock.MatchedBy(func(in *domain.CreateExportJobInput) bool {
+		return in.Type == domain.ExportJobAudit &&
+			in.AuditFilter != nil && in.AuditFilter.Action != nil && *in.AuditFilter.Action == domain.AuditUpdate
+	})).Return(job, nil)
+
+	body := `{"type":"audit","format":"csv","filter":{"action":"UPDATE"}}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/exports", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/exports/"+job.ID.String(), w.Header().Get("Location"))
+
+	var resp dto.ExportJobResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "pending", resp.Status)
+	assert.Empty(t, resp.DownloadURL)
+}
+
+func TestExportJobHandler_Create_InvalidType(t *testing.T) {
+	svc := newMockexportJobService(t)
+	h := NewExportJobHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/exports", bytes.NewReader([]byte(`{"type":"users"}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestExportJobHandler_Get_Progress(t *testing.T) {
+	svc := newMockexportJobService(t)
+	h := NewExportJobHandler(svc, newTestLogger())
+
+	job := &domain.ExportJob{
+		ID:        uuid.New(),
+		Type:      domain.ExportJobItems,
+		Format:    "xlsx",
+		Status:    domain.ExportJobRunning,
+		TotalRows: 400,
+		Processed: 100,
+	}
+	svc.EXPECT().Get(mock.Anything, testAdminClaims, job.ID).Return(job, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/exports/"+job.ID.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: job.ID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Get(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ExportJobResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(25), resp.Progress)
+	assert.Equal(t, int64(100), resp.Processed)
+}
+
+func TestExportJobHandler_Download(t *testing.T) {
+	svc := newMockexportJobService(t)
+	h := NewExportJobHandler(svc, newTestLogger())
+
+	path := filepath.Join(t.TempDir(), "items.csv")
+	require.NoError(t, os.WriteFile(path, []byte("sku\nLAP-001\n"), 0o600))
+
+	job := &domain.ExportJob{
+		ID:        uuid.New(),
+		Type:      domain.ExportJobItems,
+		Format:    "csv",
+		Status:    domain.ExportJobDone,
+		CreatedAt: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+	svc.EXPECT().Download(mock.Anything, testAdminClaims, job.ID).Return(job, path, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/exports/"+job.ID.String()+"/download", nil)
+	c.Params = gin.Params{{Key: "id", Value: job.ID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Download(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "items_2026-03-05.csv")
+	assert.Equal(t, "sku\nLAP-001\n", w.Body.String())
+}
+
+func TestExportJobHandler_Download_NotReady(t *testing.T) {
+	svc := newMockexportJobService(t)
+	h := NewExportJobHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	svc.EXPECT().Download(mock.Anything, testAdminClaims, id).Return(nil, "", domain.ErrExportNotReady)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/exports/"+id.String()+"/download", nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Download(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
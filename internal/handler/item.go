@@ -2,11 +2,14 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
 	"github.com/wb-go/wbf/ginext"
 	"github.com/wb-go/wbf/logger"
@@ -17,7 +20,11 @@ type itemService interface {
 	GetByID(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Item, error)
 	ListItems(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page, pageSize int) (*domain.ItemList, error)
 	Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error)
+	AdjustQuantity(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.AdjustQuantityInput) (*domain.Item, error)
 	Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error
+	Batch(ctx context.Context, claims *domain.AuthClaims, ops []*domain.BatchOperation, mode domain.BatchMode) (*domain.BatchResult, error)
+	Import(ctx context.Context, claims *domain.AuthClaims, rows []*domain.ImportRow, dryRun bool) (*domain.ImportReport, error)
+	Export(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, format export.Format, columns []string, w io.Writer) error
 }
 
 type ItemHandler struct {
@@ -45,6 +52,7 @@ func (h *ItemHandler) Create(c *ginext.Context) {
 		return
 	}
 
+	withAuditReason(c, req.ToDomain())
 	item, err := h.service.CreateItem(c.Request.Context(), claims, req.ToInput())
 	if err != nil {
 		writeError(c, err)
@@ -61,10 +69,7 @@ func (h *ItemHandler) List(c *ginext.Context) {
 		return
 	}
 
-	filter := &domain.ItemFilter{}
-	if search := c.Query("search"); search != "" {
-		filter.Search = &search
-	}
+	filter := parseItemFilter(c)
 
 	page, _ := strconv.Atoi(c.Query("page"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size"))
@@ -119,6 +124,7 @@ func (h *ItemHandler) Update(c *ginext.Context) {
 		return
 	}
 
+	withAuditReason(c, req.ToDomain())
 	item, err := h.service.Update(c.Request.Context(), claims, id, req.ToInput())
 	if err != nil {
 		writeError(c, err)
@@ -128,7 +134,36 @@ func (h *ItemHandler) Update(c *ginext.Context) {
 	writeJSON(c, http.StatusOK, dto.NewItemResponse(item))
 }
 
-// DELETE /api/items/:id
+// POST /api/items/:id/adjust
+func (h *ItemHandler) AdjustQuantity(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid item id"})
+		return
+	}
+
+	var req dto.AdjustQuantityRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
+	item, err := h.service.AdjustQuantity(c.Request.Context(), claims, id, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewItemResponse(item))
+}
+
+// DELETE /api/items/:id?reason=...&reference=...
 func (h *ItemHandler) Delete(c *ginext.Context) {
 	claims := getClaims(c)
 	if claims == nil {
@@ -141,6 +176,14 @@ func (h *ItemHandler) Delete(c *ginext.Context) {
 		return
 	}
 
+	// у DELETE нет тела - причина передаётся в query
+	var req dto.AuditReasonRequest
+	if err = c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid reason or reference"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
 	if err = h.service.Delete(c.Request.Context(), claims, id); err != nil {
 		writeError(c, err)
 		return
@@ -148,3 +191,61 @@ func (h *ItemHandler) Delete(c *ginext.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// POST /api/items/batch
+func (h *ItemHandler) Batch(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var req dto.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	ops, mode := req.ToInput()
+	for i, o := range req.Operations {
+		ops[i].Invalid = validateBatchOperation(o)
+	}
+	withAuditReason(c, req.ToDomain())
+	res, err := h.service.Batch(c.Request.Context(), claims, ops, mode)
+	if err != nil {
+		var opErr *domain.BatchOpError
+		if errors.As(err, &opErr) {
+			status, msg := mapError(opErr.Err)
+			c.JSON(status, ginext.H{"error": msg, "index": opErr.Index})
+			return
+		}
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewBatchResponse(res, errorMessage))
+}
+
+// validateBatchOperation проверяет поля строки пачки теми же правилами, что и POST/PUT /api/items
+func validateBatchOperation(o *dto.BatchOperationRequest) error {
+	switch {
+	case o.Op == string(domain.BatchCreate) && o.Item != nil:
+		return validateRequest(o.Item)
+	case o.Op == string(domain.BatchUpdate) && o.Changes != nil:
+		return validateRequest(o.Changes)
+	}
+	return nil
+}
+
+// withAuditReason передаёт причину изменения из запроса в журнал аудита через контекст
+func withAuditReason(c *ginext.Context, r domain.AuditReason) {
+	c.Request = c.Request.WithContext(domain.WithAuditReason(c.Request.Context(), r))
+}
+
+// parseItemFilter - query-параметры фильтра, общие для списка и выгрузки
+func parseItemFilter(c *ginext.Context) *domain.ItemFilter {
+	filter := &domain.ItemFilter{}
+	if search := c.Query("search"); search != "" {
+		filter.Search = &search
+	}
+	return filter
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/export"
+	"github.com/wb-go/wbf/ginext"
+)
+
+// GET /api/items/export?format=csv|xlsx|jsonl&columns=sku,name,quantity&search=
+// Фильтр тот же, что у GET /api/items; ответ пишется потоком по мере чтения из БД.
+func (h *ItemHandler) Export(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	format, err := export.ParseFormat(c.Query("format"), export.ItemFormats)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "unsupported format (allowed: " + export.FormatList(export.ItemFormats) + ")"})
+		return
+	}
+
+	columns, err := export.ParseItemColumns(c.Query("columns"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("items_%s.%s", time.Now().Format("2006-01-02"), format.Extension())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", format.ContentType())
+
+	err = h.service.Export(c.Request.Context(), claims, parseItemFilter(c), format, columns, c.Writer)
+	if err == nil {
+		return
+	}
+
+	// пока ничего не отправлено, ошибку ещё можно вернуть обычным ответом
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		writeError(c, err)
+		return
+	}
+
+	h.log.Ctx(c.Request.Context()).Error("item export interrupted",
+		"error", err,
+		"user_id", claims.UserID,
+	)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestItemHandler_Export_Streams(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	search := "lap"
+	svc.EXPECT().Export(mock.Anything, testAdminClaims, &domain.ItemFilter{Search: &search}, export.FormatJSONL,
+		[]string{"sku", "quantity"}, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuthClaims, _ *domain.ItemFilter, _ export.Format, _ []string, w io.Writer) error {
+			_, err := io.WriteString(w, `{"quantity":10,"sku":"LAP-001"}`+"\n")
+			return err
+		})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/items/export?format=jsonl&columns=sku,quantity&search=lap", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".jsonl")
+	assert.Contains(t, w.Body.String(), "LAP-001")
+}
+
+func TestItemHandler_Export_Forbidden(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	svc.EXPECT().Export(mock.Anything, testViewerClaims, mock.Anything, export.FormatCSV, export.ItemColumns, mock.Anything).
+		Return(domain.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/items/export", nil)
+	setAuthClaims(c, testViewerClaims)
+
+	h.Export(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+func TestItemHandler_Export_FailsAfterWrite(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	svc.EXPECT().Export(mock.Anything, testAdminClaims, mock.Anything, export.FormatCSV, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuthClaims, _ *domain.ItemFilter, _ export.Format, _ []string, w io.Writer) error {
+			_, _ = io.WriteString(w, "id,name\n")
+			return errors.New("db error")
+		})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/items/export?format=csv", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,name\n", w.Body.String())
+}
+
+func TestItemHandler_Export_BadParams(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	for _, target := range []string{"/api/items/export?format=pdf", "/api/items/export?columns=color"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		setAuthClaims(c, testAdminClaims)
+
+		h.Export(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stpnv0/WarehouseControl/internal/importer"
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	maxImportFileSize = 10 << 20
+	maxImportRows     = 10000
+)
+
+// POST /api/items/import?dry_run=true
+// multipart/form-data: file - CSV или XLSX, mapping - JSON {"поле": "заголовок колонки"}
+func (h *ItemHandler) Import(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "file is required (max 10 MB)"})
+		return
+	}
+
+	format, err := importer.DetectFormat(fh.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "unsupported file format (allowed: csv, xlsx)"})
+		return
+	}
+
+	var mapping map[string]string
+	if v := c.PostForm("mapping"); v != "" {
+		if err = json.Unmarshal([]byte(v), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid mapping: expected JSON object"})
+			return
+		}
+		if err = importer.ValidateMapping(mapping); err != nil {
+			c.JSON(http.StatusBadRequest, ginext.H{"error": err.Error()})
+			return
+		}
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	f, err := fh.Open()
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer f.Close()
+
+	rows, err := importer.ReadItems(f, format, mapping, maxImportRows)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Import(c.Request.Context(), claims, toImportRows(rows), dryRun)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewImportReportResponse(report, errorMessage))
+}
+
+// toImportRows проверяет каждую строку теми же правилами, что и POST /api/items
+func toImportRows(rows []*importer.Row) []*domain.ImportRow {
+	res := make([]*domain.ImportRow, 0, len(rows))
+	for _, row := range rows {
+		ir := &domain.ImportRow{Line: row.Line, SKU: row.Fields[importer.FieldSKU]}
+
+		req, err := dto.NewCreateItemRequestFromRow(row.Fields)
+		if err == nil {
+			err = validateRequest(req)
+		}
+		if err != nil {
+			ir.Err = err
+		} else {
+			ir.Input = req.ToInput()
+		}
+
+		res = append(res, ir)
+	}
+	return res
+}
+
+// validateRequest прогоняет binding-теги DTO и возвращает первую ошибку как *domain.ValidationError
+func validateRequest(obj any) error {
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) && len(verrs) > 0 {
+		return newValidationError(verrs[0])
+	}
+	return domain.ErrValidation
+}
+
+func newValidationError(fe validator.FieldError) *domain.ValidationError {
+	field := strings.ToLower(fe.Field())
+
+	var reason string
+	switch fe.Tag() {
+	case "required":
+		reason = "is required"
+	case "max":
+		reason = fmt.Sprintf("must be at most %s characters", fe.Param())
+	case "gte":
+		reason = fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lte":
+		reason = fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	default:
+		reason = fmt.Sprintf("failed %q check", fe.Tag())
+	}
+
+	return &domain.ValidationError{Field: field, Reason: reason}
+}
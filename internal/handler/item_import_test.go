@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newImportRequest(t *testing.T, target, filename, content string, fields map[string]string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	_, _ = fw.Write([]byte(content))
+	for k, v := range fields {
+		assert.NoError(t, mw.WriteField(k, v))
+	}
+	assert.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, target, &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestItemHandler_Import_DryRun(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	svc.EXPECT().Import(mock.Anything, testAdminClaims, mock.MatchedBy(func(rows []*domain.ImportRow) bool {
+		return len(rows) == 2 &&
+			rows[0].Line == 2 && rows[0].Input != nil &&
+			rows[0].Input.SKU == "LAP-001" && rows[0].Input.Price.Equal(decimal.RequireFromString("999.99")) &&
+			rows[1].Line == 3 && rows[1].Err != nil
+	}), true).Return(&domain.ImportReport{
+		DryRun: true,
+		Rows: []*domain.ImportRowResult{
+			{Line: 2, SKU: "LAP-001", Action: domain.ImportCreate},
+			{Line: 3, SKU: "MOU-001", Action: domain.ImportError, Err: &domain.ValidationError{Field: "price", Reason: "must not be negative"}},
+		},
+		Created: 1,
+		Failed:  1,
+	}, nil)
+
+	content := "Артикул;Наименование;Цена;Количество\nLAP-001;Laptop;999,99;10\nMOU-001;Mouse;-1;5\n"
+	mapping := `{"sku":"Артикул","name":"Наименование","price":"Цена","quantity":"Количество"}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newImportRequest(t, "/api/items/import?dry_run=true", "items.csv", content, map[string]string{"mapping": mapping})
+	setAuthClaims(c, testAdminClaims)
+
+	h.Import(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ImportReportResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 1, resp.Created)
+	assert.Equal(t, "create", resp.Rows[0].Action)
+	assert.Equal(t, "price: must not be negative", resp.Rows[1].Error)
+}
+
+func TestItemHandler_Import_UnsupportedFormat(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newImportRequest(t, "/api/items/import", "items.txt", "name,sku,price\n", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Import(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestItemHandler_Import_MissingColumn(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newImportRequest(t, "/api/items/import", "items.csv", "name,sku\nLaptop,LAP-001\n", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Import(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "price")
+}
+
+func TestItemHandler_Import_InvalidMapping(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newImportRequest(t, "/api/items/import", "items.csv", "name,sku,price\n", map[string]string{"mapping": `{"color":"Цвет"}`})
+	setAuthClaims(c, testAdminClaims)
+
+	h.Import(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
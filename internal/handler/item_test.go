@@ -2,10 +2,13 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +18,7 @@ import (
 	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -247,6 +251,133 @@ func TestItemHandler_Update_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestItemHandler_AdjustQuantity_Success(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	itemID := uuid.New()
+	expected := &domain.Item{ID: itemID, Name: "Laptop", SKU: "LAP-001", Quantity: 7, Price: decimal.NewFromInt(999)}
+
+	svc.EXPECT().AdjustQuantity(mock.Anything, testAdminClaims, itemID, &domain.AdjustQuantityInput{Delta: -3}).Return(expected, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/items/%s/adjust", itemID), bytes.NewReader([]byte(`{"delta":-3}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.AdjustQuantity(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ItemResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 7, resp.Quantity)
+}
+
+func TestItemHandler_AdjustQuantity_Reason(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	itemID := uuid.New()
+	expected := &domain.Item{ID: itemID, Name: "Laptop", SKU: "LAP-001", Quantity: 7, Price: decimal.NewFromInt(999)}
+	want := domain.AuditReason{Reason: "брак при приёмке", Reference: "INV-2026-0042"}
+
+	svc.EXPECT().AdjustQuantity(mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.AuditReasonFromContext(ctx) == want
+	}), testAdminClaims, itemID, &domain.AdjustQuantityInput{Delta: -3}).Return(expected, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"delta":-3,"reason":"брак при приёмке","reference":"INV-2026-0042"}`
+	c.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/items/%s/adjust", itemID), bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.AdjustQuantity(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestItemHandler_AdjustQuantity_ReferenceTooLong(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	itemID := uuid.New()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := fmt.Sprintf(`{"delta":-3,"reference":"%s"}`, strings.Repeat("x", 129))
+	c.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/items/%s/adjust", itemID), bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.AdjustQuantity(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestItemHandler_AdjustQuantity_ZeroDelta(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	itemID := uuid.New()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/items/%s/adjust", itemID), bytes.NewReader([]byte(`{"delta":0}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.AdjustQuantity(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestItemHandler_AdjustQuantity_DeltaOutOfRange(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	// остаток в БД - int4: больший delta отклоняется до обращения к сервису
+	for _, delta := range []string{"2147483648", "-2147483649"} {
+		itemID := uuid.New()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/items/%s/adjust", itemID),
+			bytes.NewReader([]byte(`{"delta":`+delta+`}`)))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+		setAuthClaims(c, testAdminClaims)
+
+		h.AdjustQuantity(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, delta)
+	}
+}
+
+func TestItemHandler_AdjustQuantity_InsufficientStock(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	itemID := uuid.New()
+	svc.EXPECT().AdjustQuantity(mock.Anything, testAdminClaims, itemID, &domain.AdjustQuantityInput{Delta: -500}).
+		Return(nil, domain.ErrInsufficientStock)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/items/%s/adjust", itemID), bytes.NewReader([]byte(`{"delta":-500}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.AdjustQuantity(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "insufficient stock")
+}
+
 func TestItemHandler_Delete_Success(t *testing.T) {
 	svc := newMockitemService(t)
 	h := NewItemHandler(svc, newTestLogger())
@@ -265,6 +396,28 @@ func TestItemHandler_Delete_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
 }
 
+func TestItemHandler_Delete_ReasonFromQuery(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	itemID := uuid.New()
+	want := domain.AuditReason{Reason: "списан", Reference: "ACT-7"}
+	svc.EXPECT().Delete(mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.AuditReasonFromContext(ctx) == want
+	}), testAdminClaims, itemID).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	target := fmt.Sprintf("/api/items/%s?reason=%s&reference=ACT-7", itemID, url.QueryEscape("списан"))
+	c.Request = httptest.NewRequest(http.MethodDelete, target, nil)
+	c.Params = gin.Params{{Key: "id", Value: itemID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Delete(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+}
+
 func TestItemHandler_Delete_InvalidID(t *testing.T) {
 	svc := newMockitemService(t)
 	h := NewItemHandler(svc, newTestLogger())
@@ -297,3 +450,133 @@ func TestItemHandler_Delete_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestItemHandler_Batch_BestEffort(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	deleteID := uuid.New()
+	created := &domain.Item{ID: uuid.New(), Name: "Hub", SKU: "HUB-1", Price: decimal.NewFromInt(10)}
+
+	svc.EXPECT().Batch(mock.Anything, testAdminClaims, mock.Anything, domain.BatchBestEffort).Return(&domain.BatchResult{
+		Mode: domain.BatchBestEffort,
+		Results: []*domain.BatchItemResult{
+			{Index: 0, Op: domain.BatchCreate, Item: created},
+			{Index: 1, Op: domain.BatchDelete, ID: &deleteID, Err: domain.ErrNotFound},
+		},
+		Succeeded: 1,
+		Failed:    1,
+	}, nil)
+
+	body := fmt.Sprintf(`{"mode":"best_effort","operations":[
+		{"op":"create","item":{"name":"Hub","sku":"HUB-1","quantity":1,"price":"10"}},
+		{"op":"delete","id":"%s"}
+	]}`, deleteID)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/items/batch", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Batch(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.BatchResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, "ok", resp.Results[0].Status)
+	assert.Equal(t, "error", resp.Results[1].Status)
+	assert.Equal(t, "not found", resp.Results[1].Error)
+}
+
+func TestItemHandler_Batch_AtomicFailureReportsIndex(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	svc.EXPECT().Batch(mock.Anything, testAdminClaims, mock.Anything, domain.BatchMode("")).
+		Return(nil, &domain.BatchOpError{Index: 1, Err: domain.ErrDuplicateSKU})
+
+	body := `{"operations":[
+		{"op":"create","item":{"name":"Hub","sku":"HUB-1","quantity":1,"price":"10"}},
+		{"op":"create","item":{"name":"Hub","sku":"HUB-1","quantity":1,"price":"10"}}
+	]}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/items/batch", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Batch(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"item with this SKU already exists","index":1}`, w.Body.String())
+}
+
+func TestItemHandler_Batch_InvalidRowsCheckedPerRow(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	// ошибки полей строк не отклоняют запрос: они передаются сервису как ошибки строк
+	var ops []*domain.BatchOperation
+	svc.EXPECT().Batch(mock.Anything, testAdminClaims, mock.Anything, domain.BatchBestEffort).
+		RunAndReturn(func(_ context.Context, _ *domain.AuthClaims, got []*domain.BatchOperation, mode domain.BatchMode) (*domain.BatchResult, error) {
+			ops = got
+			return &domain.BatchResult{Mode: mode}, nil
+		})
+
+	body := `{"mode":"best_effort","operations":[
+		{"op":"create","item":{"name":"Hub","sku":"HUB-1","quantity":1,"price":"10"}},
+		{"op":"create","item":{"sku":"HUB-2","price":"10"}},
+		{"op":"update","id":"` + uuid.NewString() + `","changes":{"quantity":-1}}
+	]}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/items/batch", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Batch(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, ops, 3)
+	assert.NoError(t, ops[0].Invalid)
+
+	var verr *domain.ValidationError
+	require.ErrorAs(t, ops[1].Invalid, &verr)
+	assert.Equal(t, "name", verr.Field)
+	require.ErrorAs(t, ops[2].Invalid, &verr)
+	assert.Equal(t, "quantity", verr.Field)
+}
+
+func TestItemHandler_Batch_InvalidBody(t *testing.T) {
+	svc := newMockitemService(t)
+	h := NewItemHandler(svc, newTestLogger())
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty operations", `{"operations":[]}`},
+		{"unknown op", `{"operations":[{"op":"upsert"}]}`},
+		{"unknown mode", `{"mode":"partial","operations":[{"op":"delete","id":"` + uuid.NewString() + `"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/items/batch", bytes.NewReader([]byte(tt.body)))
+			c.Request.Header.Set("Content-Type", "application/json")
+			setAuthClaims(c, testAdminClaims)
+
+			h.Batch(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+)
+
+// jwksCacheMaxAge - сколько потребители могут кэшировать ключи; новый ключ публикуется
+// за auth.signing.publish_ahead до активации, поэтому этот срок должен быть меньше
+const jwksCacheMaxAge = "public, max-age=300"
+
+type publicKeySource interface {
+	PublicKeys() []*domain.PublicKey
+}
+
+type JWKSHandler struct {
+	keys publicKeySource
+}
+
+func NewJWKSHandler(keys publicKeySource) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GET /.well-known/jwks.json
+// Открытые ключи проверки токенов доступа: действующие и следующий, ещё не активированный.
+// При подписи HS256 список пуст - секрет не публикуется.
+func (h *JWKSHandler) Keys(c *ginext.Context) {
+	c.Header("Cache-Control", jwksCacheMaxAge)
+	writeJSON(c, http.StatusOK, dto.NewJWKSResponse(h.keys.PublicKeys()))
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler_Keys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := newMockpublicKeySource(t)
+	keys.EXPECT().PublicKeys().Return([]*domain.PublicKey{
+		{ID: "rsa-1", Algorithm: "RS256", Key: &rsaKey.PublicKey},
+		{ID: "ed-1", Algorithm: "EdDSA", Key: edPublic},
+	})
+	h := NewJWKSHandler(keys)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+	h.Keys(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+	var resp dto.JWKSResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Keys, 2)
+
+	assert.Equal(t, dto.JWK{
+		Kty: "RSA", Use: "sig", Alg: "RS256", Kid: "rsa-1",
+		N: base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		E: "AQAB",
+	}, resp.Keys[0])
+	assert.Equal(t, dto.JWK{
+		Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "ed-1",
+		Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(edPublic),
+	}, resp.Keys[1])
+}
+
+// при подписи HS256 публиковать нечего - список пуст, но не null
+func TestJWKSHandler_KeysEmpty(t *testing.T) {
+	keys := newMockpublicKeySource(t)
+	keys.EXPECT().PublicKeys().Return(nil)
+	h := NewJWKSHandler(keys)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+	h.Keys(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"keys":[]}`, w.Body.String())
+}
@@ -10,16 +10,17 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// newMockauditService creates a new instance of mockauditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// newMockapiKeyService creates a new instance of mockapiKeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMockauditService(t interface {
+func newMockapiKeyService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mockauditService {
-	mock := &mockauditService{}
+}) *mockapiKeyService {
+	mock := &mockapiKeyService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -27,51 +28,124 @@ func newMockauditService(t interface {
 	return mock
 }
 
-// mockauditService is an autogenerated mock type for the auditService type
-type mockauditService struct {
+// mockapiKeyService is an autogenerated mock type for the apiKeyService type
+type mockapiKeyService struct {
 	mock.Mock
 }
 
-type mockauditService_Expecter struct {
+type mockapiKeyService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mockauditService) EXPECT() *mockauditService_Expecter {
-	return &mockauditService_Expecter{mock: &_m.Mock}
+func (_m *mockapiKeyService) EXPECT() *mockapiKeyService_Expecter {
+	return &mockapiKeyService_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type mockapiKeyService
+func (_mock *mockapiKeyService) Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateAPIKeyInput) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, claims, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateAPIKeyInput) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, claims, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateAPIKeyInput) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, claims, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.CreateAPIKeyInput) error); ok {
+		r1 = returnFunc(ctx, claims, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyService_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockapiKeyService_Create_Call struct {
+	*mock.Call
 }
 
-// ExportCSV provides a mock function for the type mockauditService
-func (_mock *mockauditService) ExportCSV(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, w io.Writer) error {
-	ret := _mock.Called(ctx, claims, filter, w)
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - input *domain.CreateAPIKeyInput
+func (_e *mockapiKeyService_Expecter) Create(ctx interface{}, claims interface{}, input interface{}) *mockapiKeyService_Create_Call {
+	return &mockapiKeyService_Create_Call{Call: _e.mock.On("Create", ctx, claims, input)}
+}
+
+func (_c *mockapiKeyService_Create_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateAPIKeyInput)) *mockapiKeyService_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.CreateAPIKeyInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.CreateAPIKeyInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyService_Create_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyService_Create_Call {
+	_c.Call.Return(apiKey, err)
+	return _c
+}
+
+func (_c *mockapiKeyService_Create_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateAPIKeyInput) (*domain.APIKey, error)) *mockapiKeyService_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type mockapiKeyService
+func (_mock *mockapiKeyService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+	ret := _mock.Called(ctx, claims, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ExportCSV")
+		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, io.Writer) error); ok {
-		r0 = returnFunc(ctx, claims, filter, w)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, claims, id)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// mockauditService_ExportCSV_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportCSV'
-type mockauditService_ExportCSV_Call struct {
+// mockapiKeyService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockapiKeyService_Delete_Call struct {
 	*mock.Call
 }
 
-// ExportCSV is a helper method to define mock.On call
+// Delete is a helper method to define mock.On call
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
-//   - filter *domain.AuditFilter
-//   - w io.Writer
-func (_e *mockauditService_Expecter) ExportCSV(ctx interface{}, claims interface{}, filter interface{}, w interface{}) *mockauditService_ExportCSV_Call {
-	return &mockauditService_ExportCSV_Call{Call: _e.mock.On("ExportCSV", ctx, claims, filter, w)}
+//   - id uuid.UUID
+func (_e *mockapiKeyService_Expecter) Delete(ctx interface{}, claims interface{}, id interface{}) *mockapiKeyService_Delete_Call {
+	return &mockapiKeyService_Delete_Call{Call: _e.mock.On("Delete", ctx, claims, id)}
 }
 
-func (_c *mockauditService_ExportCSV_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, w io.Writer)) *mockauditService_ExportCSV_Call {
+func (_c *mockapiKeyService_Delete_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockapiKeyService_Delete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -81,76 +155,71 @@ func (_c *mockauditService_ExportCSV_Call) Run(run func(ctx context.Context, cla
 		if args[1] != nil {
 			arg1 = args[1].(*domain.AuthClaims)
 		}
-		var arg2 *domain.AuditFilter
+		var arg2 uuid.UUID
 		if args[2] != nil {
-			arg2 = args[2].(*domain.AuditFilter)
-		}
-		var arg3 io.Writer
-		if args[3] != nil {
-			arg3 = args[3].(io.Writer)
+			arg2 = args[2].(uuid.UUID)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockauditService_ExportCSV_Call) Return(err error) *mockauditService_ExportCSV_Call {
+func (_c *mockapiKeyService_Delete_Call) Return(err error) *mockapiKeyService_Delete_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *mockauditService_ExportCSV_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, w io.Writer) error) *mockauditService_ExportCSV_Call {
+func (_c *mockapiKeyService_Delete_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error) *mockapiKeyService_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByItemID provides a mock function for the type mockauditService
-func (_mock *mockauditService) GetByItemID(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error) {
-	ret := _mock.Called(ctx, claims, itemID)
+// Get provides a mock function for the type mockapiKeyService
+func (_mock *mockapiKeyService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, claims, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByItemID")
+		panic("no return value specified for Get")
 	}
 
-	var r0 []*domain.AuditEntryWithUser
+	var r0 *domain.APIKey
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) ([]*domain.AuditEntryWithUser, error)); ok {
-		return returnFunc(ctx, claims, itemID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, claims, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) []*domain.AuditEntryWithUser); ok {
-		r0 = returnFunc(ctx, claims, itemID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, claims, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
+			r0 = ret.Get(0).(*domain.APIKey)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, claims, itemID)
+		r1 = returnFunc(ctx, claims, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockauditService_GetByItemID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByItemID'
-type mockauditService_GetByItemID_Call struct {
+// mockapiKeyService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockapiKeyService_Get_Call struct {
 	*mock.Call
 }
 
-// GetByItemID is a helper method to define mock.On call
+// Get is a helper method to define mock.On call
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
-//   - itemID uuid.UUID
-func (_e *mockauditService_Expecter) GetByItemID(ctx interface{}, claims interface{}, itemID interface{}) *mockauditService_GetByItemID_Call {
-	return &mockauditService_GetByItemID_Call{Call: _e.mock.On("GetByItemID", ctx, claims, itemID)}
+//   - id uuid.UUID
+func (_e *mockapiKeyService_Expecter) Get(ctx interface{}, claims interface{}, id interface{}) *mockapiKeyService_Get_Call {
+	return &mockapiKeyService_Get_Call{Call: _e.mock.On("Get", ctx, claims, id)}
 }
 
-func (_c *mockauditService_GetByItemID_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID)) *mockauditService_GetByItemID_Call {
+func (_c *mockapiKeyService_Get_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockapiKeyService_Get_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -173,60 +242,57 @@ func (_c *mockauditService_GetByItemID_Call) Run(run func(ctx context.Context, c
 	return _c
 }
 
-func (_c *mockauditService_GetByItemID_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, err error) *mockauditService_GetByItemID_Call {
-	_c.Call.Return(auditEntryWithUsers, err)
+func (_c *mockapiKeyService_Get_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyService_Get_Call {
+	_c.Call.Return(apiKey, err)
 	return _c
 }
 
-func (_c *mockauditService_GetByItemID_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error)) *mockauditService_GetByItemID_Call {
+func (_c *mockapiKeyService_Get_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.APIKey, error)) *mockapiKeyService_Get_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function for the type mockauditService
-func (_mock *mockauditService) List(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page int, pageSize int) (*domain.AuditList, error) {
-	ret := _mock.Called(ctx, claims, filter, page, pageSize)
+// List provides a mock function for the type mockapiKeyService
+func (_mock *mockapiKeyService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.APIKey, error) {
+	ret := _mock.Called(ctx, claims)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 *domain.AuditList
+	var r0 []*domain.APIKey
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, int, int) (*domain.AuditList, error)); ok {
-		return returnFunc(ctx, claims, filter, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) ([]*domain.APIKey, error)); ok {
+		return returnFunc(ctx, claims)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, int, int) *domain.AuditList); ok {
-		r0 = returnFunc(ctx, claims, filter, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) []*domain.APIKey); ok {
+		r0 = returnFunc(ctx, claims)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.AuditList)
+			r0 = ret.Get(0).([]*domain.APIKey)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, int, int) error); ok {
-		r1 = returnFunc(ctx, claims, filter, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockauditService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type mockauditService_List_Call struct {
+// mockapiKeyService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockapiKeyService_List_Call struct {
 	*mock.Call
 }
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
-//   - filter *domain.AuditFilter
-//   - page int
-//   - pageSize int
-func (_e *mockauditService_Expecter) List(ctx interface{}, claims interface{}, filter interface{}, page interface{}, pageSize interface{}) *mockauditService_List_Call {
-	return &mockauditService_List_Call{Call: _e.mock.On("List", ctx, claims, filter, page, pageSize)}
+func (_e *mockapiKeyService_Expecter) List(ctx interface{}, claims interface{}) *mockapiKeyService_List_Call {
+	return &mockapiKeyService_List_Call{Call: _e.mock.On("List", ctx, claims)}
 }
 
-func (_c *mockauditService_List_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page int, pageSize int)) *mockauditService_List_Call {
+func (_c *mockapiKeyService_List_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockapiKeyService_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -236,46 +302,111 @@ func (_c *mockauditService_List_Call) Run(run func(ctx context.Context, claims *
 		if args[1] != nil {
 			arg1 = args[1].(*domain.AuthClaims)
 		}
-		var arg2 *domain.AuditFilter
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyService_List_Call) Return(apiKeys []*domain.APIKey, err error) *mockapiKeyService_List_Call {
+	_c.Call.Return(apiKeys, err)
+	return _c
+}
+
+func (_c *mockapiKeyService_List_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) ([]*domain.APIKey, error)) *mockapiKeyService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type mockapiKeyService
+func (_mock *mockapiKeyService) Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, claims, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateAPIKeyInput) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, claims, id, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateAPIKeyInput) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, claims, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateAPIKeyInput) error); ok {
+		r1 = returnFunc(ctx, claims, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyService_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockapiKeyService_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+//   - input *domain.UpdateAPIKeyInput
+func (_e *mockapiKeyService_Expecter) Update(ctx interface{}, claims interface{}, id interface{}, input interface{}) *mockapiKeyService_Update_Call {
+	return &mockapiKeyService_Update_Call{Call: _e.mock.On("Update", ctx, claims, id, input)}
+}
+
+func (_c *mockapiKeyService_Update_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateAPIKeyInput)) *mockapiKeyService_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
 		if args[2] != nil {
-			arg2 = args[2].(*domain.AuditFilter)
+			arg2 = args[2].(uuid.UUID)
 		}
-		var arg3 int
+		var arg3 *domain.UpdateAPIKeyInput
 		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
-		var arg4 int
-		if args[4] != nil {
-			arg4 = args[4].(int)
+			arg3 = args[3].(*domain.UpdateAPIKeyInput)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
-			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *mockauditService_List_Call) Return(auditList *domain.AuditList, err error) *mockauditService_List_Call {
-	_c.Call.Return(auditList, err)
+func (_c *mockapiKeyService_Update_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyService_Update_Call {
+	_c.Call.Return(apiKey, err)
 	return _c
 }
 
-func (_c *mockauditService_List_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page int, pageSize int) (*domain.AuditList, error)) *mockauditService_List_Call {
+func (_c *mockapiKeyService_Update_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error)) *mockapiKeyService_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// newMockauthService creates a new instance of mockauthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// newMockauditService creates a new instance of mockauditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMockauthService(t interface {
+func newMockauditService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mockauthService {
-	mock := &mockauthService{}
+}) *mockauditService {
+	mock := &mockauditService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -283,162 +414,3121 @@ func newMockauthService(t interface {
 	return mock
 }
 
-// mockauthService is an autogenerated mock type for the authService type
-type mockauthService struct {
+// mockauditService is an autogenerated mock type for the auditService type
+type mockauditService struct {
 	mock.Mock
 }
 
-type mockauthService_Expecter struct {
+type mockauditService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mockauthService) EXPECT() *mockauthService_Expecter {
-	return &mockauthService_Expecter{mock: &_m.Mock}
+func (_m *mockauditService) EXPECT() *mockauditService_Expecter {
+	return &mockauditService_Expecter{mock: &_m.Mock}
 }
 
-// ListUsers provides a mock function for the type mockauthService
-func (_mock *mockauthService) ListUsers(ctx context.Context) ([]*domain.User, error) {
-	ret := _mock.Called(ctx)
+// Export provides a mock function for the type mockauditService
+func (_mock *mockauditService) Export(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, format export.Format, w io.Writer) (int64, error) {
+	ret := _mock.Called(ctx, claims, filter, format, w)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListUsers")
+		panic("no return value specified for Export")
 	}
 
-	var r0 []*domain.User
+	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.User, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, export.Format, io.Writer) (int64, error)); ok {
+		return returnFunc(ctx, claims, filter, format, w)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.User); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, export.Format, io.Writer) int64); ok {
+		r0 = returnFunc(ctx, claims, filter, format, w)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.User)
-		}
+		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, export.Format, io.Writer) error); ok {
+		r1 = returnFunc(ctx, claims, filter, format, w)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockauthService_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
-type mockauthService_ListUsers_Call struct {
+// mockauditService_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type mockauditService_Export_Call struct {
 	*mock.Call
 }
 
-// ListUsers is a helper method to define mock.On call
+// Export is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *mockauthService_Expecter) ListUsers(ctx interface{}) *mockauthService_ListUsers_Call {
-	return &mockauthService_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx)}
+//   - claims *domain.AuthClaims
+//   - filter *domain.AuditFilter
+//   - format export.Format
+//   - w io.Writer
+func (_e *mockauditService_Expecter) Export(ctx interface{}, claims interface{}, filter interface{}, format interface{}, w interface{}) *mockauditService_Export_Call {
+	return &mockauditService_Export_Call{Call: _e.mock.On("Export", ctx, claims, filter, format, w)}
+}
+
+func (_c *mockauditService_Export_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, format export.Format, w io.Writer)) *mockauditService_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.AuditFilter
+		if args[2] != nil {
+			arg2 = args[2].(*domain.AuditFilter)
+		}
+		var arg3 export.Format
+		if args[3] != nil {
+			arg3 = args[3].(export.Format)
+		}
+		var arg4 io.Writer
+		if args[4] != nil {
+			arg4 = args[4].(io.Writer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditService_Export_Call) Return(n int64, err error) *mockauditService_Export_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *mockauditService_Export_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, format export.Format, w io.Writer) (int64, error)) *mockauditService_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByItemID provides a mock function for the type mockauditService
+func (_mock *mockauditService) GetByItemID(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error) {
+	ret := _mock.Called(ctx, claims, itemID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByItemID")
+	}
+
+	var r0 []*domain.AuditEntryWithUser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) ([]*domain.AuditEntryWithUser, error)); ok {
+		return returnFunc(ctx, claims, itemID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) []*domain.AuditEntryWithUser); ok {
+		r0 = returnFunc(ctx, claims, itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, claims, itemID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditService_GetByItemID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByItemID'
+type mockauditService_GetByItemID_Call struct {
+	*mock.Call
+}
+
+// GetByItemID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - itemID uuid.UUID
+func (_e *mockauditService_Expecter) GetByItemID(ctx interface{}, claims interface{}, itemID interface{}) *mockauditService_GetByItemID_Call {
+	return &mockauditService_GetByItemID_Call{Call: _e.mock.On("GetByItemID", ctx, claims, itemID)}
+}
+
+func (_c *mockauditService_GetByItemID_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID)) *mockauditService_GetByItemID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditService_GetByItemID_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, err error) *mockauditService_GetByItemID_Call {
+	_c.Call.Return(auditEntryWithUsers, err)
+	return _c
+}
+
+func (_c *mockauditService_GetByItemID_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error)) *mockauditService_GetByItemID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockauditService
+func (_mock *mockauditService) List(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page int, pageSize int) (*domain.AuditList, error) {
+	ret := _mock.Called(ctx, claims, filter, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *domain.AuditList
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, int, int) (*domain.AuditList, error)); ok {
+		return returnFunc(ctx, claims, filter, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, int, int) *domain.AuditList); ok {
+		r0 = returnFunc(ctx, claims, filter, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditList)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.AuditFilter, int, int) error); ok {
+		r1 = returnFunc(ctx, claims, filter, page, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockauditService_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - filter *domain.AuditFilter
+//   - page int
+//   - pageSize int
+func (_e *mockauditService_Expecter) List(ctx interface{}, claims interface{}, filter interface{}, page interface{}, pageSize interface{}) *mockauditService_List_Call {
+	return &mockauditService_List_Call{Call: _e.mock.On("List", ctx, claims, filter, page, pageSize)}
+}
+
+func (_c *mockauditService_List_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page int, pageSize int)) *mockauditService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.AuditFilter
+		if args[2] != nil {
+			arg2 = args[2].(*domain.AuditFilter)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditService_List_Call) Return(auditList *domain.AuditList, err error) *mockauditService_List_Call {
+	_c.Call.Return(auditList, err)
+	return _c
+}
+
+func (_c *mockauditService_List_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditFilter, page int, pageSize int) (*domain.AuditList, error)) *mockauditService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function for the type mockauditService
+func (_mock *mockauditService) Stats(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditStatsFilter) (*domain.AuditStats, error) {
+	ret := _mock.Called(ctx, claims, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *domain.AuditStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditStatsFilter) (*domain.AuditStats, error)); ok {
+		return returnFunc(ctx, claims, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditStatsFilter) *domain.AuditStats); ok {
+		r0 = returnFunc(ctx, claims, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.AuditStatsFilter) error); ok {
+		r1 = returnFunc(ctx, claims, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditService_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type mockauditService_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - filter *domain.AuditStatsFilter
+func (_e *mockauditService_Expecter) Stats(ctx interface{}, claims interface{}, filter interface{}) *mockauditService_Stats_Call {
+	return &mockauditService_Stats_Call{Call: _e.mock.On("Stats", ctx, claims, filter)}
+}
+
+func (_c *mockauditService_Stats_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditStatsFilter)) *mockauditService_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.AuditStatsFilter
+		if args[2] != nil {
+			arg2 = args[2].(*domain.AuditStatsFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditService_Stats_Call) Return(auditStats *domain.AuditStats, err error) *mockauditService_Stats_Call {
+	_c.Call.Return(auditStats, err)
+	return _c
+}
+
+func (_c *mockauditService_Stats_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.AuditStatsFilter) (*domain.AuditStats, error)) *mockauditService_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Verify provides a mock function for the type mockauditService
+func (_mock *mockauditService) Verify(ctx context.Context, claims *domain.AuthClaims, anchor *domain.AuditChainAnchor) (*domain.AuditChainReport, error) {
+	ret := _mock.Called(ctx, claims, anchor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *domain.AuditChainReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditChainAnchor) (*domain.AuditChainReport, error)); ok {
+		return returnFunc(ctx, claims, anchor)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.AuditChainAnchor) *domain.AuditChainReport); ok {
+		r0 = returnFunc(ctx, claims, anchor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditChainReport)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.AuditChainAnchor) error); ok {
+		r1 = returnFunc(ctx, claims, anchor)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditService_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type mockauditService_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - anchor *domain.AuditChainAnchor
+func (_e *mockauditService_Expecter) Verify(ctx interface{}, claims interface{}, anchor interface{}) *mockauditService_Verify_Call {
+	return &mockauditService_Verify_Call{Call: _e.mock.On("Verify", ctx, claims, anchor)}
+}
+
+func (_c *mockauditService_Verify_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, anchor *domain.AuditChainAnchor)) *mockauditService_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.AuditChainAnchor
+		if args[2] != nil {
+			arg2 = args[2].(*domain.AuditChainAnchor)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditService_Verify_Call) Return(auditChainReport *domain.AuditChainReport, err error) *mockauditService_Verify_Call {
+	_c.Call.Return(auditChainReport, err)
+	return _c
+}
+
+func (_c *mockauditService_Verify_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, anchor *domain.AuditChainAnchor) (*domain.AuditChainReport, error)) *mockauditService_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockauditArchiveService creates a new instance of mockauditArchiveService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockauditArchiveService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockauditArchiveService {
+	mock := &mockauditArchiveService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockauditArchiveService is an autogenerated mock type for the auditArchiveService type
+type mockauditArchiveService struct {
+	mock.Mock
+}
+
+type mockauditArchiveService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockauditArchiveService) EXPECT() *mockauditArchiveService_Expecter {
+	return &mockauditArchiveService_Expecter{mock: &_m.Mock}
+}
+
+// Attach provides a mock function for the type mockauditArchiveService
+func (_mock *mockauditArchiveService) Attach(ctx context.Context, claims *domain.AuthClaims, id int64) (*domain.AuditArchive, error) {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Attach")
+	}
+
+	var r0 *domain.AuditArchive
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, int64) (*domain.AuditArchive, error)); ok {
+		return returnFunc(ctx, claims, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, int64) *domain.AuditArchive); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditArchive)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, int64) error); ok {
+		r1 = returnFunc(ctx, claims, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditArchiveService_Attach_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Attach'
+type mockauditArchiveService_Attach_Call struct {
+	*mock.Call
+}
+
+// Attach is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id int64
+func (_e *mockauditArchiveService_Expecter) Attach(ctx interface{}, claims interface{}, id interface{}) *mockauditArchiveService_Attach_Call {
+	return &mockauditArchiveService_Attach_Call{Call: _e.mock.On("Attach", ctx, claims, id)}
+}
+
+func (_c *mockauditArchiveService_Attach_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id int64)) *mockauditArchiveService_Attach_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveService_Attach_Call) Return(auditArchive *domain.AuditArchive, err error) *mockauditArchiveService_Attach_Call {
+	_c.Call.Return(auditArchive, err)
+	return _c
+}
+
+func (_c *mockauditArchiveService_Attach_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id int64) (*domain.AuditArchive, error)) *mockauditArchiveService_Attach_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockauditArchiveService
+func (_mock *mockauditArchiveService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.AuditArchive, error) {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.AuditArchive
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) ([]*domain.AuditArchive, error)); ok {
+		return returnFunc(ctx, claims)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) []*domain.AuditArchive); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditArchive)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditArchiveService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockauditArchiveService_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockauditArchiveService_Expecter) List(ctx interface{}, claims interface{}) *mockauditArchiveService_List_Call {
+	return &mockauditArchiveService_List_Call{Call: _e.mock.On("List", ctx, claims)}
+}
+
+func (_c *mockauditArchiveService_List_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockauditArchiveService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveService_List_Call) Return(auditArchives []*domain.AuditArchive, err error) *mockauditArchiveService_List_Call {
+	_c.Call.Return(auditArchives, err)
+	return _c
+}
+
+func (_c *mockauditArchiveService_List_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) ([]*domain.AuditArchive, error)) *mockauditArchiveService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockauthService creates a new instance of mockauthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockauthService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockauthService {
+	mock := &mockauthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockauthService is an autogenerated mock type for the authService type
+type mockauthService struct {
+	mock.Mock
+}
+
+type mockauthService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockauthService) EXPECT() *mockauthService_Expecter {
+	return &mockauthService_Expecter{mock: &_m.Mock}
+}
+
+// ChangePassword provides a mock function for the type mockauthService
+func (_mock *mockauthService) ChangePassword(ctx context.Context, claims *domain.AuthClaims, input *domain.ChangePasswordInput) error {
+	ret := _mock.Called(ctx, claims, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.ChangePasswordInput) error); ok {
+		r0 = returnFunc(ctx, claims, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauthService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type mockauthService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - input *domain.ChangePasswordInput
+func (_e *mockauthService_Expecter) ChangePassword(ctx interface{}, claims interface{}, input interface{}) *mockauthService_ChangePassword_Call {
+	return &mockauthService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, claims, input)}
+}
+
+func (_c *mockauthService_ChangePassword_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.ChangePasswordInput)) *mockauthService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.ChangePasswordInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.ChangePasswordInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauthService_ChangePassword_Call) Return(err error) *mockauthService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauthService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.ChangePasswordInput) error) *mockauthService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUsers provides a mock function for the type mockauthService
+func (_mock *mockauthService) ListUsers(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error) {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) ([]*domain.User, error)); ok {
+		return returnFunc(ctx, claims)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) []*domain.User); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauthService_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type mockauthService_ListUsers_Call struct {
+	*mock.Call
+}
+
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockauthService_Expecter) ListUsers(ctx interface{}, claims interface{}) *mockauthService_ListUsers_Call {
+	return &mockauthService_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, claims)}
+}
+
+func (_c *mockauthService_ListUsers_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockauthService_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauthService_ListUsers_Call) Return(users []*domain.User, err error) *mockauthService_ListUsers_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *mockauthService_ListUsers_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error)) *mockauthService_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function for the type mockauthService
+func (_mock *mockauthService) Login(ctx context.Context, input *domain.LoginInput) (*domain.TokenPair, *domain.User, error) {
+	ret := _mock.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 *domain.User
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.LoginInput) (*domain.TokenPair, *domain.User, error)); ok {
+		return returnFunc(ctx, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.LoginInput) *domain.TokenPair); ok {
+		r0 = returnFunc(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.LoginInput) *domain.User); ok {
+		r1 = returnFunc(ctx, input)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.LoginInput) error); ok {
+		r2 = returnFunc(ctx, input)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockauthService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type mockauthService_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - ctx context.Context
+//   - input *domain.LoginInput
+func (_e *mockauthService_Expecter) Login(ctx interface{}, input interface{}) *mockauthService_Login_Call {
+	return &mockauthService_Login_Call{Call: _e.mock.On("Login", ctx, input)}
+}
+
+func (_c *mockauthService_Login_Call) Run(run func(ctx context.Context, input *domain.LoginInput)) *mockauthService_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.LoginInput
+		if args[1] != nil {
+			arg1 = args[1].(*domain.LoginInput)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauthService_Login_Call) Return(tokenPair *domain.TokenPair, user *domain.User, err error) *mockauthService_Login_Call {
+	_c.Call.Return(tokenPair, user, err)
+	return _c
+}
+
+func (_c *mockauthService_Login_Call) RunAndReturn(run func(ctx context.Context, input *domain.LoginInput) (*domain.TokenPair, *domain.User, error)) *mockauthService_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function for the type mockauthService
+func (_mock *mockauthService) Logout(ctx context.Context, claims *domain.AuthClaims) error {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) error); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauthService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type mockauthService_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockauthService_Expecter) Logout(ctx interface{}, claims interface{}) *mockauthService_Logout_Call {
+	return &mockauthService_Logout_Call{Call: _e.mock.On("Logout", ctx, claims)}
+}
+
+func (_c *mockauthService_Logout_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockauthService_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauthService_Logout_Call) Return(err error) *mockauthService_Logout_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauthService_Logout_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) error) *mockauthService_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Me provides a mock function for the type mockauthService
+func (_mock *mockauthService) Me(ctx context.Context, claims *domain.AuthClaims) (*domain.User, error) {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Me")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) (*domain.User, error)); ok {
+		return returnFunc(ctx, claims)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) *domain.User); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauthService_Me_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Me'
+type mockauthService_Me_Call struct {
+	*mock.Call
+}
+
+// Me is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockauthService_Expecter) Me(ctx interface{}, claims interface{}) *mockauthService_Me_Call {
+	return &mockauthService_Me_Call{Call: _e.mock.On("Me", ctx, claims)}
+}
+
+func (_c *mockauthService_Me_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockauthService_Me_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauthService_Me_Call) Return(user *domain.User, err error) *mockauthService_Me_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockauthService_Me_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) (*domain.User, error)) *mockauthService_Me_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refresh provides a mock function for the type mockauthService
+func (_mock *mockauthService) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, *domain.User, error) {
+	ret := _mock.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 *domain.User
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.TokenPair, *domain.User, error)); ok {
+		return returnFunc(ctx, refreshToken)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.TokenPair); ok {
+		r0 = returnFunc(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) *domain.User); ok {
+		r1 = returnFunc(ctx, refreshToken)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = returnFunc(ctx, refreshToken)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockauthService_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
+type mockauthService_Refresh_Call struct {
+	*mock.Call
+}
+
+// Refresh is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+func (_e *mockauthService_Expecter) Refresh(ctx interface{}, refreshToken interface{}) *mockauthService_Refresh_Call {
+	return &mockauthService_Refresh_Call{Call: _e.mock.On("Refresh", ctx, refreshToken)}
+}
+
+func (_c *mockauthService_Refresh_Call) Run(run func(ctx context.Context, refreshToken string)) *mockauthService_Refresh_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauthService_Refresh_Call) Return(tokenPair *domain.TokenPair, user *domain.User, err error) *mockauthService_Refresh_Call {
+	_c.Call.Return(tokenPair, user, err)
+	return _c
+}
+
+func (_c *mockauthService_Refresh_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) (*domain.TokenPair, *domain.User, error)) *mockauthService_Refresh_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockeventService creates a new instance of mockeventService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockeventService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockeventService {
+	mock := &mockeventService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockeventService is an autogenerated mock type for the eventService type
+type mockeventService struct {
+	mock.Mock
+}
+
+type mockeventService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockeventService) EXPECT() *mockeventService_Expecter {
+	return &mockeventService_Expecter{mock: &_m.Mock}
+}
+
+// Subscribe provides a mock function for the type mockeventService
+func (_mock *mockeventService) Subscribe(ctx context.Context, claims *domain.AuthClaims, lastEventID int64) (*domain.EventSubscription, error) {
+	ret := _mock.Called(ctx, claims, lastEventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 *domain.EventSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, int64) (*domain.EventSubscription, error)); ok {
+		return returnFunc(ctx, claims, lastEventID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, int64) *domain.EventSubscription); ok {
+		r0 = returnFunc(ctx, claims, lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.EventSubscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, int64) error); ok {
+		r1 = returnFunc(ctx, claims, lastEventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockeventService_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type mockeventService_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - lastEventID int64
+func (_e *mockeventService_Expecter) Subscribe(ctx interface{}, claims interface{}, lastEventID interface{}) *mockeventService_Subscribe_Call {
+	return &mockeventService_Subscribe_Call{Call: _e.mock.On("Subscribe", ctx, claims, lastEventID)}
+}
+
+func (_c *mockeventService_Subscribe_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, lastEventID int64)) *mockeventService_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockeventService_Subscribe_Call) Return(eventSubscription *domain.EventSubscription, err error) *mockeventService_Subscribe_Call {
+	_c.Call.Return(eventSubscription, err)
+	return _c
+}
+
+func (_c *mockeventService_Subscribe_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, lastEventID int64) (*domain.EventSubscription, error)) *mockeventService_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockexportJobService creates a new instance of mockexportJobService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockexportJobService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockexportJobService {
+	mock := &mockexportJobService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockexportJobService is an autogenerated mock type for the exportJobService type
+type mockexportJobService struct {
+	mock.Mock
+}
+
+type mockexportJobService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockexportJobService) EXPECT() *mockexportJobService_Expecter {
+	return &mockexportJobService_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type mockexportJobService
+func (_mock *mockexportJobService) Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateExportJobInput) (*domain.ExportJob, error) {
+	ret := _mock.Called(ctx, claims, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.ExportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateExportJobInput) (*domain.ExportJob, error)); ok {
+		return returnFunc(ctx, claims, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateExportJobInput) *domain.ExportJob); ok {
+		r0 = returnFunc(ctx, claims, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.CreateExportJobInput) error); ok {
+		r1 = returnFunc(ctx, claims, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobService_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockexportJobService_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - input *domain.CreateExportJobInput
+func (_e *mockexportJobService_Expecter) Create(ctx interface{}, claims interface{}, input interface{}) *mockexportJobService_Create_Call {
+	return &mockexportJobService_Create_Call{Call: _e.mock.On("Create", ctx, claims, input)}
+}
+
+func (_c *mockexportJobService_Create_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateExportJobInput)) *mockexportJobService_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.CreateExportJobInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.CreateExportJobInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobService_Create_Call) Return(exportJob *domain.ExportJob, err error) *mockexportJobService_Create_Call {
+	_c.Call.Return(exportJob, err)
+	return _c
+}
+
+func (_c *mockexportJobService_Create_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateExportJobInput) (*domain.ExportJob, error)) *mockexportJobService_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Download provides a mock function for the type mockexportJobService
+func (_mock *mockexportJobService) Download(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, string, error) {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Download")
+	}
+
+	var r0 *domain.ExportJob
+	var r1 string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.ExportJob, string, error)); ok {
+		return returnFunc(ctx, claims, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.ExportJob); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) string); ok {
+		r1 = returnFunc(ctx, claims, id)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r2 = returnFunc(ctx, claims, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockexportJobService_Download_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Download'
+type mockexportJobService_Download_Call struct {
+	*mock.Call
+}
+
+// Download is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+func (_e *mockexportJobService_Expecter) Download(ctx interface{}, claims interface{}, id interface{}) *mockexportJobService_Download_Call {
+	return &mockexportJobService_Download_Call{Call: _e.mock.On("Download", ctx, claims, id)}
+}
+
+func (_c *mockexportJobService_Download_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockexportJobService_Download_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobService_Download_Call) Return(exportJob *domain.ExportJob, s string, err error) *mockexportJobService_Download_Call {
+	_c.Call.Return(exportJob, s, err)
+	return _c
+}
+
+func (_c *mockexportJobService_Download_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, string, error)) *mockexportJobService_Download_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type mockexportJobService
+func (_mock *mockexportJobService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, error) {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.ExportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.ExportJob, error)); ok {
+		return returnFunc(ctx, claims, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.ExportJob); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, claims, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockexportJobService_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+func (_e *mockexportJobService_Expecter) Get(ctx interface{}, claims interface{}, id interface{}) *mockexportJobService_Get_Call {
+	return &mockexportJobService_Get_Call{Call: _e.mock.On("Get", ctx, claims, id)}
+}
+
+func (_c *mockexportJobService_Get_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockexportJobService_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobService_Get_Call) Return(exportJob *domain.ExportJob, err error) *mockexportJobService_Get_Call {
+	_c.Call.Return(exportJob, err)
+	return _c
+}
+
+func (_c *mockexportJobService_Get_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, error)) *mockexportJobService_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockitemService creates a new instance of mockitemService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockitemService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockitemService {
+	mock := &mockitemService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockitemService is an autogenerated mock type for the itemService type
+type mockitemService struct {
+	mock.Mock
+}
+
+type mockitemService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockitemService) EXPECT() *mockitemService_Expecter {
+	return &mockitemService_Expecter{mock: &_m.Mock}
+}
+
+// AdjustQuantity provides a mock function for the type mockitemService
+func (_mock *mockitemService) AdjustQuantity(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.AdjustQuantityInput) (*domain.Item, error) {
+	ret := _mock.Called(ctx, claims, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustQuantity")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.AdjustQuantityInput) (*domain.Item, error)); ok {
+		return returnFunc(ctx, claims, id, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.AdjustQuantityInput) *domain.Item); ok {
+		r0 = returnFunc(ctx, claims, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.AdjustQuantityInput) error); ok {
+		r1 = returnFunc(ctx, claims, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_AdjustQuantity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustQuantity'
+type mockitemService_AdjustQuantity_Call struct {
+	*mock.Call
+}
+
+// AdjustQuantity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+//   - input *domain.AdjustQuantityInput
+func (_e *mockitemService_Expecter) AdjustQuantity(ctx interface{}, claims interface{}, id interface{}, input interface{}) *mockitemService_AdjustQuantity_Call {
+	return &mockitemService_AdjustQuantity_Call{Call: _e.mock.On("AdjustQuantity", ctx, claims, id, input)}
+}
+
+func (_c *mockitemService_AdjustQuantity_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.AdjustQuantityInput)) *mockitemService_AdjustQuantity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 *domain.AdjustQuantityInput
+		if args[3] != nil {
+			arg3 = args[3].(*domain.AdjustQuantityInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_AdjustQuantity_Call) Return(item *domain.Item, err error) *mockitemService_AdjustQuantity_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemService_AdjustQuantity_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.AdjustQuantityInput) (*domain.Item, error)) *mockitemService_AdjustQuantity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Batch provides a mock function for the type mockitemService
+func (_mock *mockitemService) Batch(ctx context.Context, claims *domain.AuthClaims, ops []*domain.BatchOperation, mode domain.BatchMode) (*domain.BatchResult, error) {
+	ret := _mock.Called(ctx, claims, ops, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 *domain.BatchResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, []*domain.BatchOperation, domain.BatchMode) (*domain.BatchResult, error)); ok {
+		return returnFunc(ctx, claims, ops, mode)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, []*domain.BatchOperation, domain.BatchMode) *domain.BatchResult); ok {
+		r0 = returnFunc(ctx, claims, ops, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BatchResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, []*domain.BatchOperation, domain.BatchMode) error); ok {
+		r1 = returnFunc(ctx, claims, ops, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_Batch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Batch'
+type mockitemService_Batch_Call struct {
+	*mock.Call
+}
+
+// Batch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - ops []*domain.BatchOperation
+//   - mode domain.BatchMode
+func (_e *mockitemService_Expecter) Batch(ctx interface{}, claims interface{}, ops interface{}, mode interface{}) *mockitemService_Batch_Call {
+	return &mockitemService_Batch_Call{Call: _e.mock.On("Batch", ctx, claims, ops, mode)}
+}
+
+func (_c *mockitemService_Batch_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, ops []*domain.BatchOperation, mode domain.BatchMode)) *mockitemService_Batch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 []*domain.BatchOperation
+		if args[2] != nil {
+			arg2 = args[2].([]*domain.BatchOperation)
+		}
+		var arg3 domain.BatchMode
+		if args[3] != nil {
+			arg3 = args[3].(domain.BatchMode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_Batch_Call) Return(batchResult *domain.BatchResult, err error) *mockitemService_Batch_Call {
+	_c.Call.Return(batchResult, err)
+	return _c
+}
+
+func (_c *mockitemService_Batch_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, ops []*domain.BatchOperation, mode domain.BatchMode) (*domain.BatchResult, error)) *mockitemService_Batch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateItem provides a mock function for the type mockitemService
+func (_mock *mockitemService) CreateItem(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateItemInput) (*domain.Item, error) {
+	ret := _mock.Called(ctx, claims, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateItem")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateItemInput) (*domain.Item, error)); ok {
+		return returnFunc(ctx, claims, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateItemInput) *domain.Item); ok {
+		r0 = returnFunc(ctx, claims, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.CreateItemInput) error); ok {
+		r1 = returnFunc(ctx, claims, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_CreateItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateItem'
+type mockitemService_CreateItem_Call struct {
+	*mock.Call
+}
+
+// CreateItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - input *domain.CreateItemInput
+func (_e *mockitemService_Expecter) CreateItem(ctx interface{}, claims interface{}, input interface{}) *mockitemService_CreateItem_Call {
+	return &mockitemService_CreateItem_Call{Call: _e.mock.On("CreateItem", ctx, claims, input)}
+}
+
+func (_c *mockitemService_CreateItem_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateItemInput)) *mockitemService_CreateItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.CreateItemInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.CreateItemInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_CreateItem_Call) Return(item *domain.Item, err error) *mockitemService_CreateItem_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemService_CreateItem_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateItemInput) (*domain.Item, error)) *mockitemService_CreateItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type mockitemService
+func (_mock *mockitemService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockitemService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockitemService_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+func (_e *mockitemService_Expecter) Delete(ctx interface{}, claims interface{}, id interface{}) *mockitemService_Delete_Call {
+	return &mockitemService_Delete_Call{Call: _e.mock.On("Delete", ctx, claims, id)}
+}
+
+func (_c *mockitemService_Delete_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockitemService_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_Delete_Call) Return(err error) *mockitemService_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockitemService_Delete_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error) *mockitemService_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Export provides a mock function for the type mockitemService
+func (_mock *mockitemService) Export(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, format export.Format, columns []string, w io.Writer) error {
+	ret := _mock.Called(ctx, claims, filter, format, columns, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, export.Format, []string, io.Writer) error); ok {
+		r0 = returnFunc(ctx, claims, filter, format, columns, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockitemService_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type mockitemService_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - filter *domain.ItemFilter
+//   - format export.Format
+//   - columns []string
+//   - w io.Writer
+func (_e *mockitemService_Expecter) Export(ctx interface{}, claims interface{}, filter interface{}, format interface{}, columns interface{}, w interface{}) *mockitemService_Export_Call {
+	return &mockitemService_Export_Call{Call: _e.mock.On("Export", ctx, claims, filter, format, columns, w)}
+}
+
+func (_c *mockitemService_Export_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, format export.Format, columns []string, w io.Writer)) *mockitemService_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.ItemFilter
+		if args[2] != nil {
+			arg2 = args[2].(*domain.ItemFilter)
+		}
+		var arg3 export.Format
+		if args[3] != nil {
+			arg3 = args[3].(export.Format)
+		}
+		var arg4 []string
+		if args[4] != nil {
+			arg4 = args[4].([]string)
+		}
+		var arg5 io.Writer
+		if args[5] != nil {
+			arg5 = args[5].(io.Writer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_Export_Call) Return(err error) *mockitemService_Export_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockitemService_Export_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, format export.Format, columns []string, w io.Writer) error) *mockitemService_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type mockitemService
+func (_mock *mockitemService) GetByID(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Item, error) {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.Item, error)); ok {
+		return returnFunc(ctx, claims, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.Item); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, claims, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockitemService_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+func (_e *mockitemService_Expecter) GetByID(ctx interface{}, claims interface{}, id interface{}) *mockitemService_GetByID_Call {
+	return &mockitemService_GetByID_Call{Call: _e.mock.On("GetByID", ctx, claims, id)}
+}
+
+func (_c *mockitemService_GetByID_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockitemService_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_GetByID_Call) Return(item *domain.Item, err error) *mockitemService_GetByID_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemService_GetByID_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Item, error)) *mockitemService_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Import provides a mock function for the type mockitemService
+func (_mock *mockitemService) Import(ctx context.Context, claims *domain.AuthClaims, rows []*domain.ImportRow, dryRun bool) (*domain.ImportReport, error) {
+	ret := _mock.Called(ctx, claims, rows, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 *domain.ImportReport
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, []*domain.ImportRow, bool) (*domain.ImportReport, error)); ok {
+		return returnFunc(ctx, claims, rows, dryRun)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, []*domain.ImportRow, bool) *domain.ImportReport); ok {
+		r0 = returnFunc(ctx, claims, rows, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ImportReport)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, []*domain.ImportRow, bool) error); ok {
+		r1 = returnFunc(ctx, claims, rows, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type mockitemService_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - rows []*domain.ImportRow
+//   - dryRun bool
+func (_e *mockitemService_Expecter) Import(ctx interface{}, claims interface{}, rows interface{}, dryRun interface{}) *mockitemService_Import_Call {
+	return &mockitemService_Import_Call{Call: _e.mock.On("Import", ctx, claims, rows, dryRun)}
+}
+
+func (_c *mockitemService_Import_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, rows []*domain.ImportRow, dryRun bool)) *mockitemService_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 []*domain.ImportRow
+		if args[2] != nil {
+			arg2 = args[2].([]*domain.ImportRow)
+		}
+		var arg3 bool
+		if args[3] != nil {
+			arg3 = args[3].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_Import_Call) Return(importReport *domain.ImportReport, err error) *mockitemService_Import_Call {
+	_c.Call.Return(importReport, err)
+	return _c
+}
+
+func (_c *mockitemService_Import_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, rows []*domain.ImportRow, dryRun bool) (*domain.ImportReport, error)) *mockitemService_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListItems provides a mock function for the type mockitemService
+func (_mock *mockitemService) ListItems(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page int, pageSize int) (*domain.ItemList, error) {
+	ret := _mock.Called(ctx, claims, filter, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListItems")
+	}
+
+	var r0 *domain.ItemList
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, int, int) (*domain.ItemList, error)); ok {
+		return returnFunc(ctx, claims, filter, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, int, int) *domain.ItemList); ok {
+		r0 = returnFunc(ctx, claims, filter, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ItemList)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, int, int) error); ok {
+		r1 = returnFunc(ctx, claims, filter, page, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_ListItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListItems'
+type mockitemService_ListItems_Call struct {
+	*mock.Call
+}
+
+// ListItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - filter *domain.ItemFilter
+//   - page int
+//   - pageSize int
+func (_e *mockitemService_Expecter) ListItems(ctx interface{}, claims interface{}, filter interface{}, page interface{}, pageSize interface{}) *mockitemService_ListItems_Call {
+	return &mockitemService_ListItems_Call{Call: _e.mock.On("ListItems", ctx, claims, filter, page, pageSize)}
+}
+
+func (_c *mockitemService_ListItems_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page int, pageSize int)) *mockitemService_ListItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.ItemFilter
+		if args[2] != nil {
+			arg2 = args[2].(*domain.ItemFilter)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_ListItems_Call) Return(itemList *domain.ItemList, err error) *mockitemService_ListItems_Call {
+	_c.Call.Return(itemList, err)
+	return _c
+}
+
+func (_c *mockitemService_ListItems_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page int, pageSize int) (*domain.ItemList, error)) *mockitemService_ListItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type mockitemService
+func (_mock *mockitemService) Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error) {
+	ret := _mock.Called(ctx, claims, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateItemInput) (*domain.Item, error)); ok {
+		return returnFunc(ctx, claims, id, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateItemInput) *domain.Item); ok {
+		r0 = returnFunc(ctx, claims, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateItemInput) error); ok {
+		r1 = returnFunc(ctx, claims, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemService_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockitemService_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+//   - input *domain.UpdateItemInput
+func (_e *mockitemService_Expecter) Update(ctx interface{}, claims interface{}, id interface{}, input interface{}) *mockitemService_Update_Call {
+	return &mockitemService_Update_Call{Call: _e.mock.On("Update", ctx, claims, id, input)}
+}
+
+func (_c *mockitemService_Update_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput)) *mockitemService_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 *domain.UpdateItemInput
+		if args[3] != nil {
+			arg3 = args[3].(*domain.UpdateItemInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemService_Update_Call) Return(item *domain.Item, err error) *mockitemService_Update_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemService_Update_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error)) *mockitemService_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockpublicKeySource creates a new instance of mockpublicKeySource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockpublicKeySource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockpublicKeySource {
+	mock := &mockpublicKeySource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockpublicKeySource is an autogenerated mock type for the publicKeySource type
+type mockpublicKeySource struct {
+	mock.Mock
+}
+
+type mockpublicKeySource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockpublicKeySource) EXPECT() *mockpublicKeySource_Expecter {
+	return &mockpublicKeySource_Expecter{mock: &_m.Mock}
+}
+
+// PublicKeys provides a mock function for the type mockpublicKeySource
+func (_mock *mockpublicKeySource) PublicKeys() []*domain.PublicKey {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 []*domain.PublicKey
+	if returnFunc, ok := ret.Get(0).(func() []*domain.PublicKey); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.PublicKey)
+		}
+	}
+	return r0
+}
+
+// mockpublicKeySource_PublicKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublicKeys'
+type mockpublicKeySource_PublicKeys_Call struct {
+	*mock.Call
+}
+
+// PublicKeys is a helper method to define mock.On call
+func (_e *mockpublicKeySource_Expecter) PublicKeys() *mockpublicKeySource_PublicKeys_Call {
+	return &mockpublicKeySource_PublicKeys_Call{Call: _e.mock.On("PublicKeys")}
+}
+
+func (_c *mockpublicKeySource_PublicKeys_Call) Run(run func()) *mockpublicKeySource_PublicKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *mockpublicKeySource_PublicKeys_Call) Return(publicKeys []*domain.PublicKey) *mockpublicKeySource_PublicKeys_Call {
+	_c.Call.Return(publicKeys)
+	return _c
+}
+
+func (_c *mockpublicKeySource_PublicKeys_Call) RunAndReturn(run func() []*domain.PublicKey) *mockpublicKeySource_PublicKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockoidcService creates a new instance of mockoidcService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockoidcService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockoidcService {
+	mock := &mockoidcService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockoidcService is an autogenerated mock type for the oidcService type
+type mockoidcService struct {
+	mock.Mock
+}
+
+type mockoidcService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockoidcService) EXPECT() *mockoidcService_Expecter {
+	return &mockoidcService_Expecter{mock: &_m.Mock}
+}
+
+// Complete provides a mock function for the type mockoidcService
+func (_mock *mockoidcService) Complete(ctx context.Context, state string, code string) (*domain.TokenPair, *domain.User, error) {
+	ret := _mock.Called(ctx, state, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 *domain.User
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.TokenPair, *domain.User, error)); ok {
+		return returnFunc(ctx, state, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.TokenPair); ok {
+		r0 = returnFunc(ctx, state, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) *domain.User); ok {
+		r1 = returnFunc(ctx, state, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = returnFunc(ctx, state, code)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockoidcService_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type mockoidcService_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - state string
+//   - code string
+func (_e *mockoidcService_Expecter) Complete(ctx interface{}, state interface{}, code interface{}) *mockoidcService_Complete_Call {
+	return &mockoidcService_Complete_Call{Call: _e.mock.On("Complete", ctx, state, code)}
+}
+
+func (_c *mockoidcService_Complete_Call) Run(run func(ctx context.Context, state string, code string)) *mockoidcService_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcService_Complete_Call) Return(tokenPair *domain.TokenPair, user *domain.User, err error) *mockoidcService_Complete_Call {
+	_c.Call.Return(tokenPair, user, err)
+	return _c
+}
+
+func (_c *mockoidcService_Complete_Call) RunAndReturn(run func(ctx context.Context, state string, code string) (*domain.TokenPair, *domain.User, error)) *mockoidcService_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Provider provides a mock function for the type mockoidcService
+func (_mock *mockoidcService) Provider() (string, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Provider")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (string, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcService_Provider_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Provider'
+type mockoidcService_Provider_Call struct {
+	*mock.Call
+}
+
+// Provider is a helper method to define mock.On call
+func (_e *mockoidcService_Expecter) Provider() *mockoidcService_Provider_Call {
+	return &mockoidcService_Provider_Call{Call: _e.mock.On("Provider")}
+}
+
+func (_c *mockoidcService_Provider_Call) Run(run func()) *mockoidcService_Provider_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *mockoidcService_Provider_Call) Return(s string, err error) *mockoidcService_Provider_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockoidcService_Provider_Call) RunAndReturn(run func() (string, error)) *mockoidcService_Provider_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Start provides a mock function for the type mockoidcService
+func (_mock *mockoidcService) Start(ctx context.Context) (string, string, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (string, string, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) string); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = returnFunc(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockoidcService_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type mockoidcService_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockoidcService_Expecter) Start(ctx interface{}) *mockoidcService_Start_Call {
+	return &mockoidcService_Start_Call{Call: _e.mock.On("Start", ctx)}
+}
+
+func (_c *mockoidcService_Start_Call) Run(run func(ctx context.Context)) *mockoidcService_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcService_Start_Call) Return(authURL string, state string, err error) *mockoidcService_Start_Call {
+	_c.Call.Return(authURL, state, err)
+	return _c
+}
+
+func (_c *mockoidcService_Start_Call) RunAndReturn(run func(ctx context.Context) (string, string, error)) *mockoidcService_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockpresenceService creates a new instance of mockpresenceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockpresenceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockpresenceService {
+	mock := &mockpresenceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockpresenceService is an autogenerated mock type for the presenceService type
+type mockpresenceService struct {
+	mock.Mock
+}
+
+type mockpresenceService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockpresenceService) EXPECT() *mockpresenceService_Expecter {
+	return &mockpresenceService_Expecter{mock: &_m.Mock}
+}
+
+// Join provides a mock function for the type mockpresenceService
+func (_mock *mockpresenceService) Join(ctx context.Context, claims *domain.AuthClaims) (domain.PresenceSession, error) {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Join")
+	}
+
+	var r0 domain.PresenceSession
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) (domain.PresenceSession, error)); ok {
+		return returnFunc(ctx, claims)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) domain.PresenceSession); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		r0 = ret.Get(0).(domain.PresenceSession)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockpresenceService_Join_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Join'
+type mockpresenceService_Join_Call struct {
+	*mock.Call
+}
+
+// Join is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockpresenceService_Expecter) Join(ctx interface{}, claims interface{}) *mockpresenceService_Join_Call {
+	return &mockpresenceService_Join_Call{Call: _e.mock.On("Join", ctx, claims)}
+}
+
+func (_c *mockpresenceService_Join_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockpresenceService_Join_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockpresenceService_Join_Call) Return(presenceSession domain.PresenceSession, err error) *mockpresenceService_Join_Call {
+	_c.Call.Return(presenceSession, err)
+	return _c
+}
+
+func (_c *mockpresenceService_Join_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) (domain.PresenceSession, error)) *mockpresenceService_Join_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockrevocationList creates a new instance of mockrevocationList. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockrevocationList(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockrevocationList {
+	mock := &mockrevocationList{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockrevocationList is an autogenerated mock type for the revocationList type
+type mockrevocationList struct {
+	mock.Mock
+}
+
+type mockrevocationList_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockrevocationList) EXPECT() *mockrevocationList_Expecter {
+	return &mockrevocationList_Expecter{mock: &_m.Mock}
+}
+
+// IsRevoked provides a mock function for the type mockrevocationList
+func (_mock *mockrevocationList) IsRevoked(jti uuid.UUID) bool {
+	ret := _mock.Called(jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRevoked")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) bool); ok {
+		r0 = returnFunc(jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// mockrevocationList_IsRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsRevoked'
+type mockrevocationList_IsRevoked_Call struct {
+	*mock.Call
+}
+
+// IsRevoked is a helper method to define mock.On call
+//   - jti uuid.UUID
+func (_e *mockrevocationList_Expecter) IsRevoked(jti interface{}) *mockrevocationList_IsRevoked_Call {
+	return &mockrevocationList_IsRevoked_Call{Call: _e.mock.On("IsRevoked", jti)}
+}
+
+func (_c *mockrevocationList_IsRevoked_Call) Run(run func(jti uuid.UUID)) *mockrevocationList_IsRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 uuid.UUID
+		if args[0] != nil {
+			arg0 = args[0].(uuid.UUID)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrevocationList_IsRevoked_Call) Return(b bool) *mockrevocationList_IsRevoked_Call {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *mockrevocationList_IsRevoked_Call) RunAndReturn(run func(jti uuid.UUID) bool) *mockrevocationList_IsRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockuserService creates a new instance of mockuserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockuserService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockuserService {
+	mock := &mockuserService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockuserService is an autogenerated mock type for the userService type
+type mockuserService struct {
+	mock.Mock
+}
+
+type mockuserService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockuserService) EXPECT() *mockuserService_Expecter {
+	return &mockuserService_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type mockuserService
+func (_mock *mockuserService) Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateUserInput) (*domain.User, error) {
+	ret := _mock.Called(ctx, claims, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateUserInput) (*domain.User, error)); ok {
+		return returnFunc(ctx, claims, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateUserInput) *domain.User); ok {
+		r0 = returnFunc(ctx, claims, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.CreateUserInput) error); ok {
+		r1 = returnFunc(ctx, claims, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserService_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockuserService_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - input *domain.CreateUserInput
+func (_e *mockuserService_Expecter) Create(ctx interface{}, claims interface{}, input interface{}) *mockuserService_Create_Call {
+	return &mockuserService_Create_Call{Call: _e.mock.On("Create", ctx, claims, input)}
+}
+
+func (_c *mockuserService_Create_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateUserInput)) *mockuserService_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 *domain.CreateUserInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.CreateUserInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserService_Create_Call) Return(user *domain.User, err error) *mockuserService_Create_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserService_Create_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateUserInput) (*domain.User, error)) *mockuserService_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type mockuserService
+func (_mock *mockuserService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockuserService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockuserService_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+func (_e *mockuserService_Expecter) Delete(ctx interface{}, claims interface{}, id interface{}) *mockuserService_Delete_Call {
+	return &mockuserService_Delete_Call{Call: _e.mock.On("Delete", ctx, claims, id)}
+}
+
+func (_c *mockuserService_Delete_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockuserService_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserService_Delete_Call) Return(err error) *mockuserService_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockuserService_Delete_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error) *mockuserService_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type mockuserService
+func (_mock *mockuserService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.User, error) {
+	ret := _mock.Called(ctx, claims, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.User, error)); ok {
+		return returnFunc(ctx, claims, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.User); ok {
+		r0 = returnFunc(ctx, claims, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, claims, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockuserService_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+func (_e *mockuserService_Expecter) Get(ctx interface{}, claims interface{}, id interface{}) *mockuserService_Get_Call {
+	return &mockuserService_Get_Call{Call: _e.mock.On("Get", ctx, claims, id)}
+}
+
+func (_c *mockuserService_Get_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockuserService_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserService_Get_Call) Return(user *domain.User, err error) *mockuserService_Get_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserService_Get_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.User, error)) *mockuserService_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockuserService
+func (_mock *mockuserService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error) {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) ([]*domain.User, error)); ok {
+		return returnFunc(ctx, claims)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) []*domain.User); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockuserService_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockuserService_Expecter) List(ctx interface{}, claims interface{}) *mockuserService_List_Call {
+	return &mockuserService_List_Call{Call: _e.mock.On("List", ctx, claims)}
+}
+
+func (_c *mockuserService_List_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockuserService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserService_List_Call) Return(users []*domain.User, err error) *mockuserService_List_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *mockuserService_List_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error)) *mockuserService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type mockuserService
+func (_mock *mockuserService) ResetPassword(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, password string) (*domain.User, error) {
+	ret := _mock.Called(ctx, claims, id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, claims, id, password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, string) *domain.User); ok {
+		r0 = returnFunc(ctx, claims, id, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, string) error); ok {
+		r1 = returnFunc(ctx, claims, id, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type mockuserService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+//   - password string
+func (_e *mockuserService_Expecter) ResetPassword(ctx interface{}, claims interface{}, id interface{}, password interface{}) *mockuserService_ResetPassword_Call {
+	return &mockuserService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, claims, id, password)}
+}
+
+func (_c *mockuserService_ResetPassword_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, password string)) *mockuserService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserService_ResetPassword_Call) Return(user *domain.User, err error) *mockuserService_ResetPassword_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, password string) (*domain.User, error)) *mockuserService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetDisabled provides a mock function for the type mockuserService
+func (_mock *mockuserService) SetDisabled(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, disabled bool) (*domain.User, error) {
+	ret := _mock.Called(ctx, claims, id, disabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetDisabled")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, bool) (*domain.User, error)); ok {
+		return returnFunc(ctx, claims, id, disabled)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, bool) *domain.User); ok {
+		r0 = returnFunc(ctx, claims, id, disabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, bool) error); ok {
+		r1 = returnFunc(ctx, claims, id, disabled)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserService_SetDisabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetDisabled'
+type mockuserService_SetDisabled_Call struct {
+	*mock.Call
+}
+
+// SetDisabled is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+//   - disabled bool
+func (_e *mockuserService_Expecter) SetDisabled(ctx interface{}, claims interface{}, id interface{}, disabled interface{}) *mockuserService_SetDisabled_Call {
+	return &mockuserService_SetDisabled_Call{Call: _e.mock.On("SetDisabled", ctx, claims, id, disabled)}
 }
 
-func (_c *mockauthService_ListUsers_Call) Run(run func(ctx context.Context)) *mockauthService_ListUsers_Call {
+func (_c *mockuserService_SetDisabled_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, disabled bool)) *mockuserService_SetDisabled_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 bool
+		if args[3] != nil {
+			arg3 = args[3].(bool)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockauthService_ListUsers_Call) Return(users []*domain.User, err error) *mockauthService_ListUsers_Call {
-	_c.Call.Return(users, err)
+func (_c *mockuserService_SetDisabled_Call) Return(user *domain.User, err error) *mockuserService_SetDisabled_Call {
+	_c.Call.Return(user, err)
 	return _c
 }
 
-func (_c *mockauthService_ListUsers_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.User, error)) *mockauthService_ListUsers_Call {
+func (_c *mockuserService_SetDisabled_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, disabled bool) (*domain.User, error)) *mockuserService_SetDisabled_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Login provides a mock function for the type mockauthService
-func (_mock *mockauthService) Login(ctx context.Context, input *domain.LoginInput) (string, *domain.User, error) {
-	ret := _mock.Called(ctx, input)
+// UpdateRole provides a mock function for the type mockuserService
+func (_mock *mockuserService) UpdateRole(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, role domain.Role) (*domain.User, error) {
+	ret := _mock.Called(ctx, claims, id, role)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for UpdateRole")
 	}
 
-	var r0 string
-	var r1 *domain.User
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.LoginInput) (string, *domain.User, error)); ok {
-		return returnFunc(ctx, input)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.LoginInput) string); ok {
-		r0 = returnFunc(ctx, input)
-	} else {
-		r0 = ret.Get(0).(string)
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, domain.Role) (*domain.User, error)); ok {
+		return returnFunc(ctx, claims, id, role)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.LoginInput) *domain.User); ok {
-		r1 = returnFunc(ctx, input)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, domain.Role) *domain.User); ok {
+		r0 = returnFunc(ctx, claims, id, role)
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*domain.User)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.LoginInput) error); ok {
-		r2 = returnFunc(ctx, input)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, domain.Role) error); ok {
+		r1 = returnFunc(ctx, claims, id, role)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// mockauthService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
-type mockauthService_Login_Call struct {
+// mockuserService_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type mockuserService_UpdateRole_Call struct {
 	*mock.Call
 }
 
-// Login is a helper method to define mock.On call
+// UpdateRole is a helper method to define mock.On call
 //   - ctx context.Context
-//   - input *domain.LoginInput
-func (_e *mockauthService_Expecter) Login(ctx interface{}, input interface{}) *mockauthService_Login_Call {
-	return &mockauthService_Login_Call{Call: _e.mock.On("Login", ctx, input)}
+//   - claims *domain.AuthClaims
+//   - id uuid.UUID
+//   - role domain.Role
+func (_e *mockuserService_Expecter) UpdateRole(ctx interface{}, claims interface{}, id interface{}, role interface{}) *mockuserService_UpdateRole_Call {
+	return &mockuserService_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, claims, id, role)}
 }
 
-func (_c *mockauthService_Login_Call) Run(run func(ctx context.Context, input *domain.LoginInput)) *mockauthService_Login_Call {
+func (_c *mockuserService_UpdateRole_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, role domain.Role)) *mockuserService_UpdateRole_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *domain.LoginInput
+		var arg1 *domain.AuthClaims
 		if args[1] != nil {
-			arg1 = args[1].(*domain.LoginInput)
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 domain.Role
+		if args[3] != nil {
+			arg3 = args[3].(domain.Role)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockauthService_Login_Call) Return(s string, user *domain.User, err error) *mockauthService_Login_Call {
-	_c.Call.Return(s, user, err)
+func (_c *mockuserService_UpdateRole_Call) Return(user *domain.User, err error) *mockuserService_UpdateRole_Call {
+	_c.Call.Return(user, err)
 	return _c
 }
 
-func (_c *mockauthService_Login_Call) RunAndReturn(run func(ctx context.Context, input *domain.LoginInput) (string, *domain.User, error)) *mockauthService_Login_Call {
+func (_c *mockuserService_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, role domain.Role) (*domain.User, error)) *mockuserService_UpdateRole_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// newMockitemService creates a new instance of mockitemService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// newMockwebhookService creates a new instance of mockwebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMockitemService(t interface {
+func newMockwebhookService(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mockitemService {
-	mock := &mockitemService{}
+}) *mockwebhookService {
+	mock := &mockwebhookService{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -446,40 +3536,40 @@ func newMockitemService(t interface {
 	return mock
 }
 
-// mockitemService is an autogenerated mock type for the itemService type
-type mockitemService struct {
+// mockwebhookService is an autogenerated mock type for the webhookService type
+type mockwebhookService struct {
 	mock.Mock
 }
 
-type mockitemService_Expecter struct {
+type mockwebhookService_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mockitemService) EXPECT() *mockitemService_Expecter {
-	return &mockitemService_Expecter{mock: &_m.Mock}
+func (_m *mockwebhookService) EXPECT() *mockwebhookService_Expecter {
+	return &mockwebhookService_Expecter{mock: &_m.Mock}
 }
 
-// CreateItem provides a mock function for the type mockitemService
-func (_mock *mockitemService) CreateItem(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateItemInput) (*domain.Item, error) {
+// Create provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateWebhookInput) (*domain.Webhook, error) {
 	ret := _mock.Called(ctx, claims, input)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateItem")
+		panic("no return value specified for Create")
 	}
 
-	var r0 *domain.Item
+	var r0 *domain.Webhook
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateItemInput) (*domain.Item, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateWebhookInput) (*domain.Webhook, error)); ok {
 		return returnFunc(ctx, claims, input)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateItemInput) *domain.Item); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.CreateWebhookInput) *domain.Webhook); ok {
 		r0 = returnFunc(ctx, claims, input)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Item)
+			r0 = ret.Get(0).(*domain.Webhook)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.CreateItemInput) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.CreateWebhookInput) error); ok {
 		r1 = returnFunc(ctx, claims, input)
 	} else {
 		r1 = ret.Error(1)
@@ -487,20 +3577,20 @@ func (_mock *mockitemService) CreateItem(ctx context.Context, claims *domain.Aut
 	return r0, r1
 }
 
-// mockitemService_CreateItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateItem'
-type mockitemService_CreateItem_Call struct {
+// mockwebhookService_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockwebhookService_Create_Call struct {
 	*mock.Call
 }
 
-// CreateItem is a helper method to define mock.On call
+// Create is a helper method to define mock.On call
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
-//   - input *domain.CreateItemInput
-func (_e *mockitemService_Expecter) CreateItem(ctx interface{}, claims interface{}, input interface{}) *mockitemService_CreateItem_Call {
-	return &mockitemService_CreateItem_Call{Call: _e.mock.On("CreateItem", ctx, claims, input)}
+//   - input *domain.CreateWebhookInput
+func (_e *mockwebhookService_Expecter) Create(ctx interface{}, claims interface{}, input interface{}) *mockwebhookService_Create_Call {
+	return &mockwebhookService_Create_Call{Call: _e.mock.On("Create", ctx, claims, input)}
 }
 
-func (_c *mockitemService_CreateItem_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateItemInput)) *mockitemService_CreateItem_Call {
+func (_c *mockwebhookService_Create_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateWebhookInput)) *mockwebhookService_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -510,9 +3600,9 @@ func (_c *mockitemService_CreateItem_Call) Run(run func(ctx context.Context, cla
 		if args[1] != nil {
 			arg1 = args[1].(*domain.AuthClaims)
 		}
-		var arg2 *domain.CreateItemInput
+		var arg2 *domain.CreateWebhookInput
 		if args[2] != nil {
-			arg2 = args[2].(*domain.CreateItemInput)
+			arg2 = args[2].(*domain.CreateWebhookInput)
 		}
 		run(
 			arg0,
@@ -523,18 +3613,18 @@ func (_c *mockitemService_CreateItem_Call) Run(run func(ctx context.Context, cla
 	return _c
 }
 
-func (_c *mockitemService_CreateItem_Call) Return(item *domain.Item, err error) *mockitemService_CreateItem_Call {
-	_c.Call.Return(item, err)
+func (_c *mockwebhookService_Create_Call) Return(webhook *domain.Webhook, err error) *mockwebhookService_Create_Call {
+	_c.Call.Return(webhook, err)
 	return _c
 }
 
-func (_c *mockitemService_CreateItem_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateItemInput) (*domain.Item, error)) *mockitemService_CreateItem_Call {
+func (_c *mockwebhookService_Create_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateWebhookInput) (*domain.Webhook, error)) *mockwebhookService_Create_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Delete provides a mock function for the type mockitemService
-func (_mock *mockitemService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+// Delete provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
 	ret := _mock.Called(ctx, claims, id)
 
 	if len(ret) == 0 {
@@ -550,8 +3640,8 @@ func (_mock *mockitemService) Delete(ctx context.Context, claims *domain.AuthCla
 	return r0
 }
 
-// mockitemService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
-type mockitemService_Delete_Call struct {
+// mockwebhookService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockwebhookService_Delete_Call struct {
 	*mock.Call
 }
 
@@ -559,11 +3649,11 @@ type mockitemService_Delete_Call struct {
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
 //   - id uuid.UUID
-func (_e *mockitemService_Expecter) Delete(ctx interface{}, claims interface{}, id interface{}) *mockitemService_Delete_Call {
-	return &mockitemService_Delete_Call{Call: _e.mock.On("Delete", ctx, claims, id)}
+func (_e *mockwebhookService_Expecter) Delete(ctx interface{}, claims interface{}, id interface{}) *mockwebhookService_Delete_Call {
+	return &mockwebhookService_Delete_Call{Call: _e.mock.On("Delete", ctx, claims, id)}
 }
 
-func (_c *mockitemService_Delete_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockitemService_Delete_Call {
+func (_c *mockwebhookService_Delete_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockwebhookService_Delete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -586,34 +3676,34 @@ func (_c *mockitemService_Delete_Call) Run(run func(ctx context.Context, claims
 	return _c
 }
 
-func (_c *mockitemService_Delete_Call) Return(err error) *mockitemService_Delete_Call {
+func (_c *mockwebhookService_Delete_Call) Return(err error) *mockwebhookService_Delete_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *mockitemService_Delete_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error) *mockitemService_Delete_Call {
+func (_c *mockwebhookService_Delete_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error) *mockwebhookService_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByID provides a mock function for the type mockitemService
-func (_mock *mockitemService) GetByID(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Item, error) {
+// Get provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Webhook, error) {
 	ret := _mock.Called(ctx, claims, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByID")
+		panic("no return value specified for Get")
 	}
 
-	var r0 *domain.Item
+	var r0 *domain.Webhook
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.Item, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) (*domain.Webhook, error)); ok {
 		return returnFunc(ctx, claims, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.Item); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID) *domain.Webhook); ok {
 		r0 = returnFunc(ctx, claims, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Item)
+			r0 = ret.Get(0).(*domain.Webhook)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID) error); ok {
@@ -624,20 +3714,20 @@ func (_mock *mockitemService) GetByID(ctx context.Context, claims *domain.AuthCl
 	return r0, r1
 }
 
-// mockitemService_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
-type mockitemService_GetByID_Call struct {
+// mockwebhookService_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockwebhookService_Get_Call struct {
 	*mock.Call
 }
 
-// GetByID is a helper method to define mock.On call
+// Get is a helper method to define mock.On call
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
 //   - id uuid.UUID
-func (_e *mockitemService_Expecter) GetByID(ctx interface{}, claims interface{}, id interface{}) *mockitemService_GetByID_Call {
-	return &mockitemService_GetByID_Call{Call: _e.mock.On("GetByID", ctx, claims, id)}
+func (_e *mockwebhookService_Expecter) Get(ctx interface{}, claims interface{}, id interface{}) *mockwebhookService_Get_Call {
+	return &mockwebhookService_Get_Call{Call: _e.mock.On("Get", ctx, claims, id)}
 }
 
-func (_c *mockitemService_GetByID_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockitemService_GetByID_Call {
+func (_c *mockwebhookService_Get_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID)) *mockwebhookService_Get_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -660,37 +3750,105 @@ func (_c *mockitemService_GetByID_Call) Run(run func(ctx context.Context, claims
 	return _c
 }
 
-func (_c *mockitemService_GetByID_Call) Return(item *domain.Item, err error) *mockitemService_GetByID_Call {
-	_c.Call.Return(item, err)
+func (_c *mockwebhookService_Get_Call) Return(webhook *domain.Webhook, err error) *mockwebhookService_Get_Call {
+	_c.Call.Return(webhook, err)
 	return _c
 }
 
-func (_c *mockitemService_GetByID_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Item, error)) *mockitemService_GetByID_Call {
+func (_c *mockwebhookService_Get_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Webhook, error)) *mockwebhookService_Get_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListItems provides a mock function for the type mockitemService
-func (_mock *mockitemService) ListItems(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page int, pageSize int) (*domain.ItemList, error) {
+// List provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.Webhook, error) {
+	ret := _mock.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.Webhook
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) ([]*domain.Webhook, error)); ok {
+		return returnFunc(ctx, claims)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims) []*domain.Webhook); ok {
+		r0 = returnFunc(ctx, claims)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Webhook)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims) error); ok {
+		r1 = returnFunc(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockwebhookService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockwebhookService_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+func (_e *mockwebhookService_Expecter) List(ctx interface{}, claims interface{}) *mockwebhookService_List_Call {
+	return &mockwebhookService_List_Call{Call: _e.mock.On("List", ctx, claims)}
+}
+
+func (_c *mockwebhookService_List_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims)) *mockwebhookService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockwebhookService_List_Call) Return(webhooks []*domain.Webhook, err error) *mockwebhookService_List_Call {
+	_c.Call.Return(webhooks, err)
+	return _c
+}
+
+func (_c *mockwebhookService_List_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims) ([]*domain.Webhook, error)) *mockwebhookService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeliveries provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) ListDeliveries(ctx context.Context, claims *domain.AuthClaims, filter *domain.WebhookDeliveryFilter, page int, pageSize int) (*domain.WebhookDeliveryList, error) {
 	ret := _mock.Called(ctx, claims, filter, page, pageSize)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListItems")
+		panic("no return value specified for ListDeliveries")
 	}
 
-	var r0 *domain.ItemList
+	var r0 *domain.WebhookDeliveryList
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, int, int) (*domain.ItemList, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.WebhookDeliveryFilter, int, int) (*domain.WebhookDeliveryList, error)); ok {
 		return returnFunc(ctx, claims, filter, page, pageSize)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, int, int) *domain.ItemList); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, *domain.WebhookDeliveryFilter, int, int) *domain.WebhookDeliveryList); ok {
 		r0 = returnFunc(ctx, claims, filter, page, pageSize)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.ItemList)
+			r0 = ret.Get(0).(*domain.WebhookDeliveryList)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.ItemFilter, int, int) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, *domain.WebhookDeliveryFilter, int, int) error); ok {
 		r1 = returnFunc(ctx, claims, filter, page, pageSize)
 	} else {
 		r1 = ret.Error(1)
@@ -698,22 +3856,22 @@ func (_mock *mockitemService) ListItems(ctx context.Context, claims *domain.Auth
 	return r0, r1
 }
 
-// mockitemService_ListItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListItems'
-type mockitemService_ListItems_Call struct {
+// mockwebhookService_ListDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeliveries'
+type mockwebhookService_ListDeliveries_Call struct {
 	*mock.Call
 }
 
-// ListItems is a helper method to define mock.On call
+// ListDeliveries is a helper method to define mock.On call
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
-//   - filter *domain.ItemFilter
+//   - filter *domain.WebhookDeliveryFilter
 //   - page int
 //   - pageSize int
-func (_e *mockitemService_Expecter) ListItems(ctx interface{}, claims interface{}, filter interface{}, page interface{}, pageSize interface{}) *mockitemService_ListItems_Call {
-	return &mockitemService_ListItems_Call{Call: _e.mock.On("ListItems", ctx, claims, filter, page, pageSize)}
+func (_e *mockwebhookService_Expecter) ListDeliveries(ctx interface{}, claims interface{}, filter interface{}, page interface{}, pageSize interface{}) *mockwebhookService_ListDeliveries_Call {
+	return &mockwebhookService_ListDeliveries_Call{Call: _e.mock.On("ListDeliveries", ctx, claims, filter, page, pageSize)}
 }
 
-func (_c *mockitemService_ListItems_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page int, pageSize int)) *mockitemService_ListItems_Call {
+func (_c *mockwebhookService_ListDeliveries_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.WebhookDeliveryFilter, page int, pageSize int)) *mockwebhookService_ListDeliveries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -723,9 +3881,9 @@ func (_c *mockitemService_ListItems_Call) Run(run func(ctx context.Context, clai
 		if args[1] != nil {
 			arg1 = args[1].(*domain.AuthClaims)
 		}
-		var arg2 *domain.ItemFilter
+		var arg2 *domain.WebhookDeliveryFilter
 		if args[2] != nil {
-			arg2 = args[2].(*domain.ItemFilter)
+			arg2 = args[2].(*domain.WebhookDeliveryFilter)
 		}
 		var arg3 int
 		if args[3] != nil {
@@ -746,37 +3904,117 @@ func (_c *mockitemService_ListItems_Call) Run(run func(ctx context.Context, clai
 	return _c
 }
 
-func (_c *mockitemService_ListItems_Call) Return(itemList *domain.ItemList, err error) *mockitemService_ListItems_Call {
-	_c.Call.Return(itemList, err)
+func (_c *mockwebhookService_ListDeliveries_Call) Return(webhookDeliveryList *domain.WebhookDeliveryList, err error) *mockwebhookService_ListDeliveries_Call {
+	_c.Call.Return(webhookDeliveryList, err)
 	return _c
 }
 
-func (_c *mockitemService_ListItems_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.ItemFilter, page int, pageSize int) (*domain.ItemList, error)) *mockitemService_ListItems_Call {
+func (_c *mockwebhookService_ListDeliveries_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, filter *domain.WebhookDeliveryFilter, page int, pageSize int) (*domain.WebhookDeliveryList, error)) *mockwebhookService_ListDeliveries_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Update provides a mock function for the type mockitemService
-func (_mock *mockitemService) Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error) {
+// Redeliver provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) Redeliver(ctx context.Context, claims *domain.AuthClaims, webhookID uuid.UUID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	ret := _mock.Called(ctx, claims, webhookID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeliver")
+	}
+
+	var r0 *domain.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, uuid.UUID) (*domain.WebhookDelivery, error)); ok {
+		return returnFunc(ctx, claims, webhookID, deliveryID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, uuid.UUID) *domain.WebhookDelivery); ok {
+		r0 = returnFunc(ctx, claims, webhookID, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, claims, webhookID, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockwebhookService_Redeliver_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Redeliver'
+type mockwebhookService_Redeliver_Call struct {
+	*mock.Call
+}
+
+// Redeliver is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - webhookID uuid.UUID
+//   - deliveryID uuid.UUID
+func (_e *mockwebhookService_Expecter) Redeliver(ctx interface{}, claims interface{}, webhookID interface{}, deliveryID interface{}) *mockwebhookService_Redeliver_Call {
+	return &mockwebhookService_Redeliver_Call{Call: _e.mock.On("Redeliver", ctx, claims, webhookID, deliveryID)}
+}
+
+func (_c *mockwebhookService_Redeliver_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, webhookID uuid.UUID, deliveryID uuid.UUID)) *mockwebhookService_Redeliver_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 uuid.UUID
+		if args[3] != nil {
+			arg3 = args[3].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockwebhookService_Redeliver_Call) Return(webhookDelivery *domain.WebhookDelivery, err error) *mockwebhookService_Redeliver_Call {
+	_c.Call.Return(webhookDelivery, err)
+	return _c
+}
+
+func (_c *mockwebhookService_Redeliver_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, webhookID uuid.UUID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)) *mockwebhookService_Redeliver_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type mockwebhookService
+func (_mock *mockwebhookService) Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error) {
 	ret := _mock.Called(ctx, claims, id, input)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
-	var r0 *domain.Item
+	var r0 *domain.Webhook
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateItemInput) (*domain.Item, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateWebhookInput) (*domain.Webhook, error)); ok {
 		return returnFunc(ctx, claims, id, input)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateItemInput) *domain.Item); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateWebhookInput) *domain.Webhook); ok {
 		r0 = returnFunc(ctx, claims, id, input)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Item)
+			r0 = ret.Get(0).(*domain.Webhook)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateItemInput) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, uuid.UUID, *domain.UpdateWebhookInput) error); ok {
 		r1 = returnFunc(ctx, claims, id, input)
 	} else {
 		r1 = ret.Error(1)
@@ -784,8 +4022,8 @@ func (_mock *mockitemService) Update(ctx context.Context, claims *domain.AuthCla
 	return r0, r1
 }
 
-// mockitemService_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
-type mockitemService_Update_Call struct {
+// mockwebhookService_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockwebhookService_Update_Call struct {
 	*mock.Call
 }
 
@@ -793,12 +4031,12 @@ type mockitemService_Update_Call struct {
 //   - ctx context.Context
 //   - claims *domain.AuthClaims
 //   - id uuid.UUID
-//   - input *domain.UpdateItemInput
-func (_e *mockitemService_Expecter) Update(ctx interface{}, claims interface{}, id interface{}, input interface{}) *mockitemService_Update_Call {
-	return &mockitemService_Update_Call{Call: _e.mock.On("Update", ctx, claims, id, input)}
+//   - input *domain.UpdateWebhookInput
+func (_e *mockwebhookService_Expecter) Update(ctx interface{}, claims interface{}, id interface{}, input interface{}) *mockwebhookService_Update_Call {
+	return &mockwebhookService_Update_Call{Call: _e.mock.On("Update", ctx, claims, id, input)}
 }
 
-func (_c *mockitemService_Update_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput)) *mockitemService_Update_Call {
+func (_c *mockwebhookService_Update_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateWebhookInput)) *mockwebhookService_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -812,9 +4050,9 @@ func (_c *mockitemService_Update_Call) Run(run func(ctx context.Context, claims
 		if args[2] != nil {
 			arg2 = args[2].(uuid.UUID)
 		}
-		var arg3 *domain.UpdateItemInput
+		var arg3 *domain.UpdateWebhookInput
 		if args[3] != nil {
-			arg3 = args[3].(*domain.UpdateItemInput)
+			arg3 = args[3].(*domain.UpdateWebhookInput)
 		}
 		run(
 			arg0,
@@ -826,12 +4064,12 @@ func (_c *mockitemService_Update_Call) Run(run func(ctx context.Context, claims
 	return _c
 }
 
-func (_c *mockitemService_Update_Call) Return(item *domain.Item, err error) *mockitemService_Update_Call {
-	_c.Call.Return(item, err)
+func (_c *mockwebhookService_Update_Call) Return(webhook *domain.Webhook, err error) *mockwebhookService_Update_Call {
+	_c.Call.Return(webhook, err)
 	return _c
 }
 
-func (_c *mockitemService_Update_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error)) *mockitemService_Update_Call {
+func (_c *mockwebhookService_Update_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error)) *mockwebhookService_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+const (
+	// oidcStateCookie связывает возврат от провайдера с браузером, начавшим вход:
+	// иначе можно подсунуть пользователю ссылку с code от своей учётной записи
+	oidcStateCookie = "oidc_state"
+	oidcCookiePath  = "/api/auth/oidc"
+)
+
+type oidcService interface {
+	Provider() (string, error)
+	Start(ctx context.Context) (authURL, state string, err error)
+	Complete(ctx context.Context, state, code string) (*domain.TokenPair, *domain.User, error)
+}
+
+type OIDCHandler struct {
+	service  oidcService
+	stateTTL time.Duration
+	log      logger.Logger
+}
+
+func NewOIDCHandler(service oidcService, stateTTL time.Duration, log logger.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		service:  service,
+		stateTTL: stateTTL,
+		log:      log.With("handler", "oidc"),
+	}
+}
+
+// GET /api/auth/oidc
+// Провайдер единого входа; 404 - вход только по паролю
+func (h *OIDCHandler) Provider(c *ginext.Context) {
+	name, err := h.service.Provider()
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.OIDCProviderResponse{Name: name})
+}
+
+// POST /api/auth/oidc/login
+// Начинает вход: клиент переходит на auth_url, провайдер возвращает пользователя на redirect_url с code и state
+func (h *OIDCHandler) Login(c *ginext.Context) {
+	authURL, state, err := h.service.Start(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	h.setStateCookie(c, state, int(h.stateTTL.Seconds()))
+	writeJSON(c, http.StatusOK, dto.OIDCLoginResponse{AuthURL: authURL})
+}
+
+// POST /api/auth/oidc/callback
+// Завершает вход по code и state от провайдера; ответ - как у POST /api/auth/login
+func (h *OIDCHandler) Callback(c *ginext.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	cookie, err := c.Cookie(oidcStateCookie)
+	h.setStateCookie(c, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		writeError(c, domain.ErrTokenInvalid)
+		return
+	}
+
+	pair, user, err := h.service.Complete(c.Request.Context(), req.State, req.Code)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewLoginResponse(pair, user))
+}
+
+// setStateCookie - cookie только для /api/auth/oidc и недоступна скриптам; maxAge < 0 - удалить
+func (h *OIDCHandler) setStateCookie(c *ginext.Context, state string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, maxAge, oidcCookiePath, "", c.Request.TLS != nil, true)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOIDCCallbackRequest(t *testing.T, state, cookie string) *http.Request {
+	body, err := json.Marshal(dto.OIDCCallbackRequest{Code: "code", State: state})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/oidc/callback", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: cookie})
+	}
+	return req
+}
+
+func TestOIDCHandler_Provider(t *testing.T) {
+	svc := newMockoidcService(t)
+	h := NewOIDCHandler(svc, 10*time.Minute, newTestLogger())
+	svc.EXPECT().Provider().Return("Keycloak", nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/oidc", nil)
+	h.Provider(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name":"Keycloak"}`, w.Body.String())
+}
+
+func TestOIDCHandler_Provider_Disabled(t *testing.T) {
+	svc := newMockoidcService(t)
+	h := NewOIDCHandler(svc, 10*time.Minute, newTestLogger())
+	svc.EXPECT().Provider().Return("", domain.ErrNotFound)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/auth/oidc", nil)
+	h.Provider(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOIDCHandler_Login(t *testing.T) {
+	svc := newMockoidcService(t)
+	h := NewOIDCHandler(svc, 10*time.Minute, newTestLogger())
+	svc.EXPECT().Start(mock.Anything).Return("https://idp.example.com/authorize?state=s1", "s1", nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/oidc/login", nil)
+	h.Login(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"auth_url":"https://idp.example.com/authorize?state=s1"}`, w.Body.String())
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, oidcStateCookie, cookies[0].Name)
+	assert.Equal(t, "s1", cookies[0].Value)
+	assert.Equal(t, oidcCookiePath, cookies[0].Path)
+	assert.Equal(t, 600, cookies[0].MaxAge)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	svc := newMockoidcService(t)
+	h := NewOIDCHandler(svc, 10*time.Minute, newTestLogger())
+	user := &domain.User{ID: uuid.New(), Username: "alice", Role: domain.RoleManager}
+	svc.EXPECT().Complete(mock.Anything, "s1", "code").
+		Return(&domain.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}, user, nil)
+
+	c, w := setupTestContext()
+	c.Request = newOIDCCallbackRequest(t, "s1", "s1")
+	h.Callback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "jwt-token", resp.Token)
+	assert.Equal(t, "alice", resp.User.Username)
+
+	// cookie одноразовая
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+}
+
+// state из ответа провайдера должен совпасть с cookie браузера, начавшего вход
+func TestOIDCHandler_Callback_StateMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+	}{
+		{"no cookie", ""},
+		{"other state", "s2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newMockoidcService(t)
+			h := NewOIDCHandler(svc, 10*time.Minute, newTestLogger())
+
+			c, w := setupTestContext()
+			c.Request = newOIDCCallbackRequest(t, "s1", tt.cookie)
+			h.Callback(c)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
+
+func TestOIDCHandler_Callback_ProviderUnavailable(t *testing.T) {
+	svc := newMockoidcService(t)
+	h := NewOIDCHandler(svc, 10*time.Minute, newTestLogger())
+	svc.EXPECT().Complete(mock.Anything, "s1", "code").Return(nil, nil, domain.ErrIdentityProvider)
+
+	c, w := setupTestContext()
+	c.Request = newOIDCCallbackRequest(t, "s1", "s1")
+	h.Callback(c)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
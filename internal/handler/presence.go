@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// presenceProtocol - подпротокол, которым браузер передаёт токен (см. middleware.Auth)
+	presenceProtocol     = "bearer"
+	presenceMaxMessage   = 4 << 10
+	presenceWriteTimeout = 10 * time.Second
+)
+
+type presenceService interface {
+	Join(ctx context.Context, claims *domain.AuthClaims) (domain.PresenceSession, error)
+}
+
+type PresenceHandler struct {
+	service   presenceService
+	revoked   revocationList
+	heartbeat time.Duration
+	log       logger.Logger
+}
+
+// NewPresenceHandler - heartbeat: как часто слать ping; клиент, молчащий дольше двух интервалов, отключается.
+// На каждом ping токен соединения проверяется по revoked: отозванный токен закрывает канал.
+func NewPresenceHandler(service presenceService, revoked revocationList, heartbeat time.Duration, log logger.Logger) *PresenceHandler {
+	if heartbeat <= 0 {
+		heartbeat = 30 * time.Second
+	}
+	return &PresenceHandler{
+		service:   service,
+		revoked:   revoked,
+		heartbeat: heartbeat,
+		log:       log.With("handler", "presence"),
+	}
+}
+
+// GET /api/ws/presence
+// WebSocket-канал присутствия: клиент подписывается на товары (subscribe/unsubscribe),
+// сообщает, что открыл форму редактирования (editing), и получает состав открывших товар (presence)
+// и изменения подписанных товаров (item_changed).
+// Канал закрывается, когда истекает или отзывается токен, с которым он открыт:
+// клиент переподключается с новым токеном.
+func (h *PresenceHandler) Connect(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	session, err := h.service.Join(c.Request.Context(), claims)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer session.Close()
+
+	srv := websocket.Server{
+		Handshake: func(cfg *websocket.Config, _ *http.Request) error {
+			// токен не аутентифицирует origin, поэтому проверка Origin не нужна
+			if slices.ContainsFunc(cfg.Protocol, func(p string) bool {
+				return strings.EqualFold(p, presenceProtocol)
+			}) {
+				cfg.Protocol = []string{presenceProtocol}
+			} else {
+				cfg.Protocol = nil
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			h.serve(ws, session, claims)
+		},
+	}
+	srv.ServeHTTP(c.Writer, c.Request)
+}
+
+// serve пишет в соединение сообщения сессии, ответы на запросы клиента и ping; читает - отдельная горутина
+func (h *PresenceHandler) serve(ws *websocket.Conn, session domain.PresenceSession, claims *domain.AuthClaims) {
+	ws.MaxPayloadBytes = presenceMaxMessage
+
+	replies := make(chan *dto.PresenceResponse, 8)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		defer close(done)
+		h.read(ws, session, replies, stop)
+	}()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	// у API-ключа срока в claims нет
+	var expired <-chan time.Time
+	if !claims.ExpiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(claims.ExpiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	for {
+		var (
+			resp *dto.PresenceResponse
+			last bool
+		)
+		select {
+		case <-done:
+			return
+		case msg, ok := <-session.Messages():
+			if !ok {
+				// сессия закрыта сервером или не успевала читать - клиент переподключится
+				return
+			}
+			resp = dto.NewPresenceResponse(msg)
+		case resp = <-replies:
+		case <-expired:
+			resp, last = &dto.PresenceResponse{Type: dto.PresenceError, Error: "token expired"}, true
+		case <-heartbeat.C:
+			if h.isRevoked(claims) {
+				resp, last = &dto.PresenceResponse{Type: dto.PresenceError, Error: "token revoked"}, true
+			} else {
+				resp = &dto.PresenceResponse{Type: dto.PresencePing}
+			}
+		}
+
+		_ = ws.SetWriteDeadline(time.Now().Add(presenceWriteTimeout))
+		if err := websocket.JSON.Send(ws, resp); err != nil || last {
+			return
+		}
+	}
+}
+
+// isRevoked - токен соединения отозван после подключения (выход, смена пароля, отключение пользователя)
+func (h *PresenceHandler) isRevoked(claims *domain.AuthClaims) bool {
+	return claims.TokenID != uuid.Nil && h.revoked.IsRevoked(claims.TokenID)
+}
+
+func (h *PresenceHandler) read(
+	ws *websocket.Conn,
+	session domain.PresenceSession,
+	replies chan<- *dto.PresenceResponse,
+	stop <-chan struct{},
+) {
+	for {
+		_ = ws.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+
+		var data []byte
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			return
+		}
+
+		var resp *dto.PresenceResponse
+		var req dto.PresenceRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			resp = &dto.PresenceResponse{Type: dto.PresenceError, Error: "invalid message"}
+		} else {
+			resp = h.handle(session, &req)
+		}
+		if resp == nil {
+			continue
+		}
+
+		select {
+		case replies <- resp:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// handle выполняет запрос клиента; nil - отвечать не нужно
+func (h *PresenceHandler) handle(session domain.PresenceSession, req *dto.PresenceRequest) *dto.PresenceResponse {
+	switch req.Type {
+	case dto.PresencePing:
+		return &dto.PresenceResponse{Type: dto.PresencePong}
+	case dto.PresencePong:
+		return nil
+	case dto.PresenceSubscribe, dto.PresenceUnsubscribe, dto.PresenceEditing:
+	default:
+		return &dto.PresenceResponse{Type: dto.PresenceError, Error: "unknown message type"}
+	}
+
+	itemID, err := uuid.Parse(req.ItemID)
+	if err != nil {
+		return &dto.PresenceResponse{Type: dto.PresenceError, Error: "invalid item_id"}
+	}
+
+	switch req.Type {
+	case dto.PresenceSubscribe:
+		err = session.Subscribe(itemID)
+	case dto.PresenceUnsubscribe:
+		session.Unsubscribe(itemID)
+	case dto.PresenceEditing:
+		if req.Editing == nil {
+			return &dto.PresenceResponse{Type: dto.PresenceError, ItemID: &itemID, Error: "editing is required"}
+		}
+		err = session.SetEditing(itemID, *req.Editing)
+	}
+	if err != nil {
+		return &dto.PresenceResponse{Type: dto.PresenceError, ItemID: &itemID, Error: errorMessage(err)}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// fakePresenceSession записывает запросы клиента; сообщения сервера отправляются в out
+type fakePresenceSession struct {
+	out chan *domain.PresenceMessage
+
+	mu      sync.Mutex
+	calls   []string
+	editErr error
+	closed  bool
+}
+
+func newFakePresenceSession() *fakePresenceSession {
+	return &fakePresenceSession{out: make(chan *domain.PresenceMessage, 4)}
+}
+
+func (f *fakePresenceSession) Messages() <-chan *domain.PresenceMessage { return f.out }
+
+func (f *fakePresenceSession) Subscribe(itemID uuid.UUID) error {
+	f.record("subscribe " + itemID.String())
+	return nil
+}
+
+func (f *fakePresenceSession) Unsubscribe(itemID uuid.UUID) {
+	f.record("unsubscribe " + itemID.String())
+}
+
+func (f *fakePresenceSession) SetEditing(itemID uuid.UUID, editing bool) error {
+	if editing {
+		f.record("editing " + itemID.String())
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.editErr
+}
+
+func (f *fakePresenceSession) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+func (f *fakePresenceSession) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *fakePresenceSession) recorded() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func (f *fakePresenceSession) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// presenceServer - сервер с каналом присутствия для claims (middleware.Auth здесь не нужен)
+func presenceServer(t *testing.T, h *PresenceHandler, claims *domain.AuthClaims) *httptest.Server {
+	router := gin.New()
+	router.GET("/api/ws/presence", func(c *gin.Context) {
+		setAuthClaims(c, claims)
+		h.Connect(c)
+	})
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dialPresence(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	cfg, err := websocket.NewConfig("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/ws/presence", srv.URL)
+	require.NoError(t, err)
+	cfg.Protocol = []string{"bearer", "token"}
+
+	ws, err := websocket.DialConfig(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ws.Close() })
+	return ws
+}
+
+func receivePresence(t *testing.T, ws *websocket.Conn) *dto.PresenceResponse {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+
+	var resp dto.PresenceResponse
+	require.NoError(t, websocket.JSON.Receive(ws, &resp))
+	return &resp
+}
+
+func TestPresenceHandler_Connect(t *testing.T) {
+	svc := newMockpresenceService(t)
+	h := NewPresenceHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+	session := newFakePresenceSession()
+	svc.EXPECT().Join(mock.Anything, testAdminClaims).Return(session, nil)
+
+	ws := dialPresence(t, presenceServer(t, h, testAdminClaims))
+	assert.Equal(t, []string{"bearer"}, ws.Config().Protocol, "server must accept the bearer subprotocol")
+
+	itemID := uuid.New()
+	require.NoError(t, websocket.JSON.Send(ws, dto.PresenceRequest{Type: dto.PresenceSubscribe, ItemID: itemID.String()}))
+	editing := true
+	require.NoError(t, websocket.JSON.Send(ws, dto.PresenceRequest{
+		Type:    dto.PresenceEditing,
+		ItemID:  itemID.String(),
+		Editing: &editing,
+	}))
+
+	require.NoError(t, websocket.JSON.Send(ws, dto.PresenceRequest{Type: dto.PresencePing}))
+	assert.Equal(t, dto.PresencePong, receivePresence(t, ws).Type)
+	assert.Equal(t, []string{"subscribe " + itemID.String(), "editing " + itemID.String()}, session.recorded())
+
+	session.out <- &domain.PresenceMessage{Presence: &domain.PresenceUpdate{
+		ItemID: itemID,
+		Users: []domain.PresenceUser{{
+			UserID:   testAdminClaims.UserID,
+			Username: "admin",
+			Role:     domain.RoleAdmin,
+			Editing:  true,
+		}},
+	}}
+	resp := receivePresence(t, ws)
+	assert.Equal(t, dto.PresenceUpdateType, resp.Type)
+	require.NotNil(t, resp.ItemID)
+	assert.Equal(t, itemID, *resp.ItemID)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, "admin", resp.Users[0].Username)
+	assert.True(t, resp.Users[0].Editing)
+
+	ev := testChangeEvent(12, true)
+	session.out <- &domain.PresenceMessage{Change: ev}
+	resp = receivePresence(t, ws)
+	assert.Equal(t, dto.PresenceItemChanged, resp.Type)
+	assert.Equal(t, ev.Entry.EntityID, *resp.ItemID)
+	event, ok := resp.Event.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "admin", event["username"], "admin must receive the detailed event")
+
+	// сессия закрыта сервером - соединение закрывается
+	close(session.out)
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+	var data []byte
+	assert.Error(t, websocket.Message.Receive(ws, &data))
+	assert.Eventually(t, session.isClosed, time.Second, 10*time.Millisecond)
+}
+
+func TestPresenceHandler_Connect_InvalidMessages(t *testing.T) {
+	svc := newMockpresenceService(t)
+	h := NewPresenceHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+	session := newFakePresenceSession()
+	session.editErr = domain.ErrForbidden
+	svc.EXPECT().Join(mock.Anything, testViewerClaims).Return(session, nil)
+
+	ws := dialPresence(t, presenceServer(t, h, testViewerClaims))
+	itemID := uuid.New()
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"not json", "{", "invalid message"},
+		{"unknown type", `{"type": "kick"}`, "unknown message type"},
+		{"invalid item", `{"type": "subscribe", "item_id": "abc"}`, "invalid item_id"},
+		{"editing missing", `{"type": "editing", "item_id": "` + itemID.String() + `"}`, "editing is required"},
+		{"forbidden", `{"type": "editing", "item_id": "` + itemID.String() + `", "editing": true}`, "insufficient permissions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, websocket.Message.Send(ws, tt.message))
+
+			resp := receivePresence(t, ws)
+			assert.Equal(t, dto.PresenceError, resp.Type)
+			assert.Equal(t, tt.want, resp.Error)
+		})
+	}
+}
+
+func TestPresenceHandler_Connect_Unavailable(t *testing.T) {
+	svc := newMockpresenceService(t)
+	h := NewPresenceHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+	svc.EXPECT().Join(mock.Anything, testAdminClaims).
+		RunAndReturn(func(context.Context, *domain.AuthClaims) (domain.PresenceSession, error) {
+			return nil, domain.ErrEventsUnavailable
+		})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/ws/presence", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.Connect(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "event feed is unavailable", body["error"])
+}
+
+func TestPresenceHandler_Connect_TokenExpired(t *testing.T) {
+	svc := newMockpresenceService(t)
+	h := NewPresenceHandler(svc, newMockrevocationList(t), time.Hour, newTestLogger())
+	session := newFakePresenceSession()
+	claims := *testAdminClaims
+	claims.TokenID = uuid.New()
+	claims.ExpiresAt = time.Now().Add(100 * time.Millisecond)
+	svc.EXPECT().Join(mock.Anything, &claims).Return(session, nil)
+
+	ws := dialPresence(t, presenceServer(t, h, &claims))
+
+	// по истечении токена сервер сообщает причину и закрывает канал
+	resp := receivePresence(t, ws)
+	assert.Equal(t, dto.PresenceError, resp.Type)
+	assert.Equal(t, "token expired", resp.Error)
+
+	var data []byte
+	assert.Error(t, websocket.Message.Receive(ws, &data))
+	assert.Eventually(t, session.isClosed, time.Second, 10*time.Millisecond)
+}
+
+func TestPresenceHandler_Connect_TokenRevoked(t *testing.T) {
+	svc := newMockpresenceService(t)
+	revoked := newMockrevocationList(t)
+	h := NewPresenceHandler(svc, revoked, 50*time.Millisecond, newTestLogger())
+	session := newFakePresenceSession()
+	claims := *testAdminClaims
+	claims.TokenID = uuid.New()
+	claims.ExpiresAt = time.Now().Add(time.Hour)
+	svc.EXPECT().Join(mock.Anything, &claims).Return(session, nil)
+	revoked.EXPECT().IsRevoked(claims.TokenID).Return(false).Once()
+	revoked.EXPECT().IsRevoked(claims.TokenID).Return(true).Once()
+
+	ws := dialPresence(t, presenceServer(t, h, &claims))
+
+	assert.Equal(t, dto.PresencePing, receivePresence(t, ws).Type)
+	// без ответа сервер отключил бы молчащего клиента раньше второго ping
+	require.NoError(t, websocket.JSON.Send(ws, dto.PresenceRequest{Type: dto.PresencePong}))
+
+	// токен отозван после подключения - канал закрывается на следующем ping
+	resp := receivePresence(t, ws)
+	assert.Equal(t, dto.PresenceError, resp.Type)
+	assert.Equal(t, "token revoked", resp.Error)
+
+	var data []byte
+	assert.Error(t, websocket.Message.Receive(ws, &data))
+	assert.Eventually(t, session.isClosed, time.Second, 10*time.Millisecond)
+}
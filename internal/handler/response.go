@@ -18,7 +18,15 @@ func writeError(c *ginext.Context, err error) {
 	c.JSON(status, ginext.H{"error": msg})
 }
 
+// errorMessage - публичное сообщение об ошибке без HTTP-статуса (для построчных результатов)
+func errorMessage(err error) string {
+	_, msg := mapError(err)
+	return msg
+}
+
 func mapError(err error) (int, string) {
+	var validationErr *domain.ValidationError
+
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
 		return http.StatusNotFound, "not found"
@@ -30,12 +38,30 @@ func mapError(err error) (int, string) {
 		return http.StatusUnauthorized, "invalid token"
 	case errors.Is(err, domain.ErrTokenExpired):
 		return http.StatusUnauthorized, "token expired"
+	case errors.Is(err, domain.ErrUserDisabled):
+		return http.StatusForbidden, "account is disabled"
+	case errors.Is(err, domain.ErrOwnAccount):
+		return http.StatusConflict, "operation is not allowed on own account"
 	case errors.Is(err, domain.ErrDuplicateSKU):
 		return http.StatusConflict, "item with this SKU already exists"
+	case errors.Is(err, domain.ErrInsufficientStock):
+		return http.StatusConflict, "insufficient stock"
+	case errors.Is(err, domain.ErrExportNotReady):
+		return http.StatusConflict, "export is not ready"
+	case errors.Is(err, domain.ErrAuditArchiveAttached):
+		return http.StatusConflict, "audit archive is already attached"
+	case errors.Is(err, domain.ErrAuditArchiveCorrupted):
+		return http.StatusConflict, "audit archive file is missing or corrupted"
+	case errors.Is(err, domain.ErrIdentityProvider):
+		return http.StatusBadGateway, "identity provider is unavailable"
+	case errors.Is(err, domain.ErrEventsUnavailable):
+		return http.StatusServiceUnavailable, "event feed is unavailable"
 	case errors.Is(err, domain.ErrAlreadyExists):
 		return http.StatusConflict, "already exists"
 	case errors.Is(err, domain.ErrNoChanges):
 		return http.StatusBadRequest, "no changes provided"
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, validationErr.Error()
 	case errors.Is(err, domain.ErrValidation):
 		return http.StatusBadRequest, "validation error"
 	default:
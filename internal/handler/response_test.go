@@ -22,8 +22,14 @@ func TestMapError(t *testing.T) {
 		{"invalid token", domain.ErrTokenInvalid, http.StatusUnauthorized, "invalid token"},
 		{"token expired", domain.ErrTokenExpired, http.StatusUnauthorized, "token expired"},
 		{"duplicate SKU", domain.ErrDuplicateSKU, http.StatusConflict, "item with this SKU already exists"},
+		{"insufficient stock", domain.ErrInsufficientStock, http.StatusConflict, "insufficient stock"},
+		{"export not ready", domain.ErrExportNotReady, http.StatusConflict, "export is not ready"},
 		{"already exists", domain.ErrAlreadyExists, http.StatusConflict, "already exists"},
+		{"user disabled", domain.ErrUserDisabled, http.StatusForbidden, "account is disabled"},
+		{"own account", domain.ErrOwnAccount, http.StatusConflict, "operation is not allowed on own account"},
+		{"identity provider", domain.ErrIdentityProvider, http.StatusBadGateway, "identity provider is unavailable"},
 		{"no changes", domain.ErrNoChanges, http.StatusBadRequest, "no changes provided"},
+		{"field validation", &domain.ValidationError{Field: "price", Reason: "is required"}, http.StatusBadRequest, "price: is required"},
 		{"validation", domain.ErrValidation, http.StatusBadRequest, "validation error"},
 		{"unknown", errors.New("something"), http.StatusInternalServerError, "internal server error"},
 	}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+type userService interface {
+	List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error)
+	Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.User, error)
+	Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateUserInput) (*domain.User, error)
+	UpdateRole(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, role domain.Role) (*domain.User, error)
+	ResetPassword(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, password string) (*domain.User, error)
+	SetDisabled(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, disabled bool) (*domain.User, error)
+	Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error
+}
+
+type UserHandler struct {
+	service userService
+	log     logger.Logger
+}
+
+func NewUserHandler(service userService, log logger.Logger) *UserHandler {
+	return &UserHandler{
+		service: service,
+		log:     log.With("handler", "user"),
+	}
+}
+
+// GET /api/users
+func (h *UserHandler) List(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	users, err := h.service.List(c.Request.Context(), claims)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewUserAccountListResponse(users))
+}
+
+// GET /api/users/:id
+func (h *UserHandler) Get(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.Get(c.Request.Context(), claims, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewUserAccountResponse(user))
+}
+
+// POST /api/users
+func (h *UserHandler) Create(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
+	user, err := h.service.Create(c.Request.Context(), claims, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/users/%s", user.ID))
+	writeJSON(c, http.StatusCreated, dto.NewUserAccountResponse(user))
+}
+
+// PUT /api/users/:id/role
+func (h *UserHandler) UpdateRole(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
+	user, err := h.service.UpdateRole(c.Request.Context(), claims, id, domain.Role(req.Role))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewUserAccountResponse(user))
+}
+
+// PUT /api/users/:id/password
+func (h *UserHandler) ResetPassword(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
+	user, err := h.service.ResetPassword(c.Request.Context(), claims, id, req.Password)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewUserAccountResponse(user))
+}
+
+// POST /api/users/:id/disable
+func (h *UserHandler) Disable(c *ginext.Context) {
+	h.setDisabled(c, true)
+}
+
+// POST /api/users/:id/enable
+func (h *UserHandler) Enable(c *ginext.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *UserHandler) setDisabled(c *ginext.Context, disabled bool) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	// тела нет - причина передаётся в query, как у DELETE
+	var req dto.AuditReasonRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid reason or reference"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
+	user, err := h.service.SetDisabled(c.Request.Context(), claims, id, disabled)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewUserAccountResponse(user))
+}
+
+// DELETE /api/users/:id
+func (h *UserHandler) Delete(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AuditReasonRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid reason or reference"})
+		return
+	}
+
+	withAuditReason(c, req.ToDomain())
+	if err := h.service.Delete(c.Request.Context(), claims, id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseUserID(c *ginext.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid user id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
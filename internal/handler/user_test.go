@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHandler_Create(t *testing.T) {
+	svc := newMockuserService(t)
+	h := NewUserHandler(svc, newTestLogger())
+
+	user := &domain.User{ID: uuid.New(), Username: "storekeeper", Role: domain.RoleManager, CreatedAt: time.Now()}
+	svc.EXPECT().Create(mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.AuditReasonFromContext(ctx).Reason == "новый сотрудник склада"
+	}), testAdminClaims, &domain.CreateUserInput{
+		Username: "storekeeper",
+		Password: "s3cret-pass",
+		Role:     domain.RoleManager,
+	}).Return(user, nil)
+
+	body := `{"username":"storekeeper","password":"s3cret-pass","role":"manager","reason":"новый сотрудник склада"}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/users/"+user.ID.String(), w.Header().Get("Location"))
+	assert.NotContains(t, w.Body.String(), "password")
+
+	var resp dto.UserAccountResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "manager", resp.Role)
+	assert.False(t, resp.Disabled)
+}
+
+func TestUserHandler_Create_InvalidBody(t *testing.T) {
+	svc := newMockuserService(t)
+	h := NewUserHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewReader([]byte(`{"username":"bob"}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserHandler_List_Forbidden(t *testing.T) {
+	svc := newMockuserService(t)
+	h := NewUserHandler(svc, newTestLogger())
+
+	svc.EXPECT().List(mock.Anything, testViewerClaims).Return(nil, domain.ErrForbidden)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	setAuthClaims(c, testViewerClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUserHandler_UpdateRole(t *testing.T) {
+	svc := newMockuserService(t)
+	h := NewUserHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	svc.EXPECT().UpdateRole(mock.Anything, testAdminClaims, id, domain.RoleViewer).
+		Return(&domain.User{ID: id, Username: "bob", Role: domain.RoleViewer}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/", bytes.NewReader([]byte(`{"role":"viewer"}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.UpdateRole(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"viewer"`)
+}
+
+func TestUserHandler_Disable(t *testing.T) {
+	svc := newMockuserService(t)
+	h := NewUserHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	disabledAt := time.Now()
+	svc.EXPECT().SetDisabled(mock.MatchedBy(func(ctx context.Context) bool {
+		return domain.AuditReasonFromContext(ctx).Reference == "HR-12"
+	}), testAdminClaims, id, true).Return(&domain.User{ID: id, DisabledAt: &disabledAt}, nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/users/"+id.String()+"/disable?reference=HR-12", nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Disable(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.UserAccountResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Disabled)
+	assert.NotNil(t, resp.DisabledAt)
+}
+
+func TestUserHandler_Delete(t *testing.T) {
+	svc := newMockuserService(t)
+	h := NewUserHandler(svc, newTestLogger())
+
+	self := testAdminClaims.UserID
+	svc.EXPECT().Delete(mock.Anything, testAdminClaims, self).Return(domain.ErrOwnAccount)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
+	c.Params = gin.Params{{Key: "id", Value: self.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Delete(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	c, w = setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
+	c.Params = gin.Params{{Key: "id", Value: "42"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Delete(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid user id")
+}
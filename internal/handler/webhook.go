@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+type webhookService interface {
+	Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateWebhookInput) (*domain.Webhook, error)
+	List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.Webhook, error)
+	Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Webhook, error)
+	Update(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error)
+	Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error
+	ListDeliveries(
+		ctx context.Context,
+		claims *domain.AuthClaims,
+		filter *domain.WebhookDeliveryFilter,
+		page, pageSize int,
+	) (*domain.WebhookDeliveryList, error)
+	Redeliver(ctx context.Context, claims *domain.AuthClaims, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	service webhookService
+	log     logger.Logger
+}
+
+func NewWebhookHandler(service webhookService, log logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		log:     log.With("handler", "webhook"),
+	}
+}
+
+// POST /api/webhooks
+func (h *WebhookHandler) Create(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	hook, err := h.service.Create(c.Request.Context(), claims, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/webhooks/%s", hook.ID))
+	writeJSON(c, http.StatusCreated, dto.NewCreatedWebhookResponse(hook))
+}
+
+// GET /api/webhooks
+func (h *WebhookHandler) List(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	hooks, err := h.service.List(c.Request.Context(), claims)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewWebhookListResponse(hooks))
+}
+
+// GET /api/webhooks/:id
+func (h *WebhookHandler) Get(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	hook, err := h.service.Get(c.Request.Context(), claims, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewWebhookResponse(hook))
+}
+
+// PATCH /api/webhooks/:id
+func (h *WebhookHandler) Update(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid request body"})
+		return
+	}
+
+	hook, err := h.service.Update(c.Request.Context(), claims, id, req.ToInput())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewWebhookResponse(hook))
+}
+
+// DELETE /api/webhooks/:id
+func (h *WebhookHandler) Delete(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), claims, id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /api/webhooks/:id/deliveries?status=failed&page=1&page_size=20
+func (h *WebhookHandler) ListDeliveries(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	filter := &domain.WebhookDeliveryFilter{
+		WebhookID: id,
+		Status:    domain.WebhookDeliveryStatus(c.Query("status")),
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+
+	list, err := h.service.ListDeliveries(c.Request.Context(), claims, filter, page, pageSize)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, dto.NewWebhookDeliveryListResponse(list))
+}
+
+// POST /api/webhooks/:id/deliveries/:delivery_id/redeliver
+// Ставит событие доставки в очередь ещё раз; ответ - новая доставка
+func (h *WebhookHandler) Redeliver(c *ginext.Context) {
+	claims := getClaims(c)
+	if claims == nil {
+		return
+	}
+
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid delivery id"})
+		return
+	}
+
+	d, err := h.service.Redeliver(c.Request.Context(), claims, id, deliveryID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusAccepted, dto.NewWebhookDeliveryResponse(d))
+}
+
+func parseWebhookID(c *ginext.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ginext.H{"error": "invalid webhook id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
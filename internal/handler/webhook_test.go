@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/handler/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhook() *domain.Webhook {
+	return &domain.Webhook{
+		ID:         uuid.New(),
+		URL:        "https://example.com/hook",
+		EventTypes: []domain.OutboxEventType{domain.OutboxItemLowStock},
+		Secret:     "whsec_0123456789abcdef",
+		Active:     true,
+		CreatedBy:  testAdminClaims.UserID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	hook := testWebhook()
+	svc.EXPECT().Create(mock.Anything, testAdminClaims, mock.MatchedBy(func(in *domain.CreateWebhookInput) bool {
+		return in.URL == hook.URL && len(in.EventTypes) == 1 && in.EventTypes[0] == domain.OutboxItemLowStock &&
+			in.Secret == "" && in.Active == nil
+	})).Return(hook, nil)
+
+	body := `{"url":"https://example.com/hook","event_types":["item.low_stock"]}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/webhooks", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/webhooks/"+hook.ID.String(), w.Header().Get("Location"))
+
+	var resp dto.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, hook.Secret, resp.Secret, "secret is shown once on creation")
+	assert.Equal(t, []string{"item.low_stock"}, resp.EventTypes)
+}
+
+func TestWebhookHandler_Create_InvalidBody(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/webhooks", bytes.NewReader([]byte(`{"url":"https://example.com"}`)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	setAuthClaims(c, testAdminClaims)
+
+	h.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookHandler_List_HidesSecret(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	svc.EXPECT().List(mock.Anything, testAdminClaims).Return([]*domain.Webhook{testWebhook()}, nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+	setAuthClaims(c, testAdminClaims)
+
+	h.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "whsec_")
+	assert.NotContains(t, w.Body.String(), `"secret"`)
+}
+
+func TestWebhookHandler_Get_Forbidden(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	svc.EXPECT().Get(mock.Anything, testViewerClaims, id).Return(nil, domain.ErrForbidden)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/webhooks/"+id.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testViewerClaims)
+
+	h.Get(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestWebhookHandler_InvalidID(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodDelete, "/api/webhooks/abc", nil)
+	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Delete(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid webhook id")
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	id := uuid.New()
+	d := &domain.WebhookDelivery{
+		ID:             uuid.New(),
+		WebhookID:      id,
+		EventID:        uuid.New(),
+		EventType:      domain.OutboxItemUpdated,
+		Payload:        []byte(`{"type":"item.updated"}`),
+		Status:         domain.WebhookDeliveryFailed,
+		Attempts:       8,
+		ResponseStatus: http.StatusBadGateway,
+		Error:          "receiver responded 502 Bad Gateway",
+		Duration:       1500 * time.Millisecond,
+	}
+	svc.EXPECT().ListDeliveries(mock.Anything, testAdminClaims,
+		&domain.WebhookDeliveryFilter{WebhookID: id, Status: domain.WebhookDeliveryFailed}, 2, 5).
+		Return(&domain.WebhookDeliveryList{Deliveries: []*domain.WebhookDelivery{d}, Total: 6, Page: 2, PageSize: 5, TotalPages: 2}, nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/webhooks/"+id.String()+"/deliveries?status=failed&page=2&page_size=5", nil)
+	c.Params = gin.Params{{Key: "id", Value: id.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.ListDeliveries(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.WebhookDeliveryListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Deliveries, 1)
+	assert.Equal(t, "failed", resp.Deliveries[0].Status)
+	assert.EqualValues(t, 1500, resp.Deliveries[0].DurationMS)
+	assert.JSONEq(t, `{"type":"item.updated"}`, string(resp.Deliveries[0].Payload))
+	assert.EqualValues(t, 6, resp.Total)
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	svc := newMockwebhookService(t)
+	h := NewWebhookHandler(svc, newTestLogger())
+
+	hookID, deliveryID := uuid.New(), uuid.New()
+	svc.EXPECT().Redeliver(mock.Anything, testAdminClaims, hookID, deliveryID).Return(&domain.WebhookDelivery{
+		ID:           uuid.New(),
+		WebhookID:    hookID,
+		Status:       domain.WebhookDeliveryPending,
+		RedeliveryOf: &deliveryID,
+	}, nil)
+
+	c, w := setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Params = gin.Params{{Key: "id", Value: hookID.String()}, {Key: "delivery_id", Value: deliveryID.String()}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Redeliver(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var resp dto.WebhookDeliveryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, deliveryID, *resp.RedeliveryOf)
+
+	c, w = setupTestContext()
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Params = gin.Params{{Key: "id", Value: hookID.String()}, {Key: "delivery_id", Value: "nope"}}
+	setAuthClaims(c, testAdminClaims)
+
+	h.Redeliver(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid delivery id")
+}
@@ -0,0 +1,228 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// Поля товара, которые можно загрузить из файла
+const (
+	FieldName     = "name"
+	FieldSKU      = "sku"
+	FieldQuantity = "quantity"
+	FieldPrice    = "price"
+	FieldLocation = "location"
+)
+
+var (
+	ItemFields     = []string{FieldName, FieldSKU, FieldQuantity, FieldPrice, FieldLocation}
+	requiredFields = []string{FieldName, FieldSKU, FieldPrice}
+
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+	ErrEmptyFile         = errors.New("file has no header row")
+	ErrTooManyRows       = errors.New("too many rows")
+)
+
+// Row - одна строка файла, значения разложены по полям товара.
+// Line - номер строки в файле (заголовок - строка 1).
+type Row struct {
+	Line   int
+	Fields map[string]string
+}
+
+// DetectFormat определяет формат по расширению имени файла
+func DetectFormat(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ValidateMapping проверяет, что маппинг ссылается только на известные поля.
+// Маппинг: поле товара -> заголовок колонки в файле.
+func ValidateMapping(mapping map[string]string) error {
+	for field := range mapping {
+		if !isItemField(field) {
+			return fmt.Errorf("unknown field in mapping: %q", field)
+		}
+	}
+	return nil
+}
+
+// ReadItems читает строки товаров из CSV или XLSX (первый лист).
+// Колонки сопоставляются по mapping, для незаданных полей - по имени поля без учёта регистра.
+// Пустые строки пропускаются; maxRows ограничивает число строк с данными (0 - без ограничения).
+func ReadItems(r io.Reader, format Format, mapping map[string]string, maxRows int) ([]*Row, error) {
+	var next func() ([]string, int, error)
+
+	switch format {
+	case FormatCSV:
+		cr, err := newCSVReader(r)
+		if err != nil {
+			return nil, err
+		}
+		next = func() ([]string, int, error) {
+			rec, err := cr.Read()
+			if err != nil {
+				return nil, 0, err
+			}
+			line, _ := cr.FieldPos(0)
+			return rec, line, nil
+		}
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("open xlsx: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, ErrEmptyFile
+		}
+		rows, err := f.Rows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("read sheet: %w", err)
+		}
+		defer rows.Close()
+
+		line := 0
+		next = func() ([]string, int, error) {
+			if !rows.Next() {
+				if err := rows.Error(); err != nil {
+					return nil, 0, err
+				}
+				return nil, 0, io.EOF
+			}
+			line++
+			cols, err := rows.Columns()
+			return cols, line, err
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	header, _, err := next()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns, err := resolveColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Row
+	for {
+		rec, line, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read row: %w", err)
+		}
+		if isBlank(rec) {
+			continue
+		}
+		if maxRows > 0 && len(res) >= maxRows {
+			return nil, fmt.Errorf("%w: limit is %d", ErrTooManyRows, maxRows)
+		}
+
+		row := &Row{Line: line, Fields: make(map[string]string, len(columns))}
+		for field, idx := range columns {
+			if idx < len(rec) {
+				row.Fields[field] = strings.TrimSpace(rec[idx])
+			}
+		}
+		res = append(res, row)
+	}
+
+	return res, nil
+}
+
+// newCSVReader снимает UTF-8 BOM и подбирает разделитель (',' или ';') по строке заголовка
+func newCSVReader(r io.Reader) (*csv.Reader, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = br.Discard(3)
+	}
+
+	first, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	if i := strings.IndexByte(string(first), '\n'); i >= 0 {
+		first = first[:i]
+	}
+
+	cr := csv.NewReader(br)
+	if strings.Count(string(first), ";") > strings.Count(string(first), ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	return cr, nil
+}
+
+func resolveColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	columns := make(map[string]int, len(ItemFields))
+	for _, field := range ItemFields {
+		name := field
+		if h, ok := mapping[field]; ok && h != "" {
+			name = h
+		}
+		if idx, ok := index[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[field] = idx
+		}
+	}
+
+	for _, field := range requiredFields {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("missing column for field %q", field)
+		}
+	}
+
+	return columns, nil
+}
+
+func isItemField(field string) bool {
+	for _, f := range ItemFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
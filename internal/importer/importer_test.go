@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDetectFormat(t *testing.T) {
+	f, err := DetectFormat("items.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	f, err = DetectFormat("catalogue.xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, f)
+
+	_, err = DetectFormat("items.xls")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestReadItems_CSV(t *testing.T) {
+	data := "SKU,Name,Price,Quantity,Location\n" +
+		"LAP-001,Laptop,999.99,10,Shelf 1\n" +
+		",,,,\n" +
+		"MOU-001,Mouse,25,,\n"
+
+	rows, err := ReadItems(strings.NewReader(data), FormatCSV, nil, 0)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "LAP-001", rows[0].Fields[FieldSKU])
+	assert.Equal(t, "Shelf 1", rows[0].Fields[FieldLocation])
+	assert.Equal(t, 4, rows[1].Line)
+	assert.Equal(t, "", rows[1].Fields[FieldQuantity])
+}
+
+func TestReadItems_CSVSemicolonWithBOM(t *testing.T) {
+	data := "\xef\xbb\xbfname;sku;price\nLaptop;LAP-001;1299,99\n"
+
+	rows, err := ReadItems(strings.NewReader(data), FormatCSV, nil, 0)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Laptop", rows[0].Fields[FieldName])
+	assert.Equal(t, "1299,99", rows[0].Fields[FieldPrice])
+}
+
+func TestReadItems_Mapping(t *testing.T) {
+	data := "Артикул,Наименование,Цена\nLAP-001,Laptop,999\n"
+	mapping := map[string]string{
+		FieldSKU:   "Артикул",
+		FieldName:  "Наименование",
+		FieldPrice: "Цена",
+	}
+
+	rows, err := ReadItems(strings.NewReader(data), FormatCSV, mapping, 0)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "LAP-001", rows[0].Fields[FieldSKU])
+	assert.Equal(t, "Laptop", rows[0].Fields[FieldName])
+}
+
+func TestReadItems_MissingRequiredColumn(t *testing.T) {
+	data := "name,sku\nLaptop,LAP-001\n"
+
+	_, err := ReadItems(strings.NewReader(data), FormatCSV, nil, 0)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"price"`)
+}
+
+func TestReadItems_Empty(t *testing.T) {
+	_, err := ReadItems(strings.NewReader(""), FormatCSV, nil, 0)
+
+	assert.ErrorIs(t, err, ErrEmptyFile)
+}
+
+func TestReadItems_TooManyRows(t *testing.T) {
+	data := "name,sku,price\na,A,1\nb,B,2\nc,C,3\n"
+
+	_, err := ReadItems(strings.NewReader(data), FormatCSV, nil, 2)
+
+	assert.ErrorIs(t, err, ErrTooManyRows)
+}
+
+func TestReadItems_XLSX(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	require.NoError(t, f.SetSheetRow(sheet, "A1", &[]interface{}{"Name", "SKU", "Quantity", "Price"}))
+	require.NoError(t, f.SetSheetRow(sheet, "A2", &[]interface{}{"Laptop", "LAP-001", 10, 999.99}))
+	require.NoError(t, f.SetSheetRow(sheet, "A3", &[]interface{}{"Mouse", "MOU-001", 200, 25}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	rows, err := ReadItems(&buf, FormatXLSX, nil, 0)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, "MOU-001", rows[1].Fields[FieldSKU])
+	assert.Equal(t, "200", rows[1].Fields[FieldQuantity])
+	assert.Equal(t, "999.99", rows[0].Fields[FieldPrice])
+}
+
+func TestValidateMapping(t *testing.T) {
+	assert.NoError(t, ValidateMapping(map[string]string{FieldSKU: "Артикул"}))
+	assert.Error(t, ValidateMapping(map[string]string{"color": "Цвет"}))
+}
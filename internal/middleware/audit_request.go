@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"unicode/utf8"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/ginext"
+	"github.com/wb-go/wbf/logger"
+)
+
+// ограничения длины - по размерам колонок audit_log
+const (
+	maxAuditRequestIDLen = 128
+	maxAuditUserAgentLen = 512
+)
+
+// AuditRequest передаёт request ID, IP клиента и User-Agent в журнал аудита.
+// Ставится после RequestID.
+func AuditRequest() ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+		ctx := c.Request.Context()
+		ctx = domain.WithAuditRequest(ctx, domain.AuditRequest{
+			RequestID: truncate(logger.GetRequestID(ctx), maxAuditRequestIDLen),
+			ClientIP:  c.ClientIP(),
+			UserAgent: truncate(c.Request.UserAgent(), maxAuditUserAgentLen),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// truncate обрезает s до n байт, не разрывая символ UTF-8
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
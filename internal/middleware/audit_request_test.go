@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRequest_PutsRequestMetaIntoContext(t *testing.T) {
+	var got domain.AuditRequest
+
+	router := gin.New()
+	router.Use(RequestID(), AuditRequest())
+	router.GET("/test", func(c *gin.Context) {
+		got = domain.AuditRequestFromContext(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	req.Header.Set("User-Agent", "curl/8.5.0")
+	req.RemoteAddr = "10.0.0.7:51234"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, domain.AuditRequest{RequestID: "req-42", ClientIP: "10.0.0.7", UserAgent: "curl/8.5.0"}, got)
+}
+
+func TestAuditRequest_TruncatesUserAgent(t *testing.T) {
+	var got domain.AuditRequest
+
+	router := gin.New()
+	router.Use(RequestID(), AuditRequest())
+	router.GET("/test", func(c *gin.Context) {
+		got = domain.AuditRequestFromContext(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("User-Agent", strings.Repeat("ж", maxAuditUserAgentLen))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NotEmpty(t, got.RequestID)
+	assert.LessOrEqual(t, len(got.UserAgent), maxAuditUserAgentLen)
+	assert.Equal(t, strings.Repeat("ж", maxAuditUserAgentLen/2), got.UserAgent)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -10,12 +11,27 @@ import (
 
 const ClaimsKey = "auth_claims"
 
+// APIKeyHeader - заголовок с ключом интеграции
+const APIKeyHeader = "X-API-Key"
+
 type TokenValidator interface {
 	Validate(tokenStr string) (*domain.AuthClaims, error)
 }
 
-func Auth(validator TokenValidator) ginext.HandlerFunc {
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*domain.AuthClaims, error)
+}
+
+// Auth принимает токен доступа (Authorization: Bearer) или ключ интеграции (X-API-Key).
+// Ключ только для чтения допускает лишь GET, HEAD и OPTIONS. keys может быть nil - тогда
+// принимаются только токены.
+func Auth(validator TokenValidator, keys APIKeyValidator) ginext.HandlerFunc {
 	return func(c *ginext.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" && keys != nil {
+			authAPIKey(c, keys, key)
+			return
+		}
+
 		token := extractBearerToken(c)
 		if token == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ginext.H{
@@ -37,9 +53,44 @@ func Auth(validator TokenValidator) ginext.HandlerFunc {
 	}
 }
 
+func authAPIKey(c *ginext.Context, keys APIKeyValidator, key string) {
+	claims, err := keys.ValidateAPIKey(c.Request.Context(), key)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ginext.H{
+			"error": "invalid or expired API key",
+		})
+		return
+	}
+
+	if claims.ReadOnly && !isSafeMethod(c.Request.Method) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ginext.H{
+			"error": "API key is read-only",
+		})
+		return
+	}
+
+	c.Set(ClaimsKey, claims)
+	c.Next()
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// wsBearerProtocol - браузер не умеет задавать заголовки для WebSocket, поэтому токен
+// передаётся подпротоколом: new WebSocket(url, ["bearer", token])
+const wsBearerProtocol = "bearer"
+
 func extractBearerToken(c *ginext.Context) string {
 	header := c.GetHeader("Authorization")
 	if header == "" {
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			return websocketProtocolToken(c.GetHeader("Sec-WebSocket-Protocol"))
+		}
 		return ""
 	}
 
@@ -50,3 +101,13 @@ func extractBearerToken(c *ginext.Context) string {
 
 	return strings.TrimSpace(parts[1])
 }
+
+func websocketProtocolToken(header string) string {
+	protocols := strings.Split(header, ",")
+	for i := 0; i < len(protocols)-1; i++ {
+		if strings.EqualFold(strings.TrimSpace(protocols[i]), wsBearerProtocol) {
+			return strings.TrimSpace(protocols[i+1])
+		}
+	}
+	return ""
+}
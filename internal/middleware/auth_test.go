@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func init() {
@@ -22,7 +23,7 @@ func TestAuth_NoHeader(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
 
-	handler := Auth(validator)
+	handler := Auth(validator, nil)
 	handler(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -37,7 +38,7 @@ func TestAuth_EmptyBearerToken(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer ")
 
-	handler := Auth(validator)
+	handler := Auth(validator, nil)
 	handler(c)
 
 	// extractBearerToken trims spaces; empty token passed to validator
@@ -55,7 +56,7 @@ func TestAuth_InvalidToken(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer bad-token")
 
-	handler := Auth(validator)
+	handler := Auth(validator, nil)
 	handler(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -76,7 +77,7 @@ func TestAuth_ValidToken(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer valid-token")
 
-	handler := Auth(validator)
+	handler := Auth(validator, nil)
 	handler(c)
 
 	assert.False(t, c.IsAborted())
@@ -94,7 +95,7 @@ func TestAuth_NonBearerScheme(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
 	c.Request.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
 
-	handler := Auth(validator)
+	handler := Auth(validator, nil)
 	handler(c)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
@@ -128,3 +129,83 @@ func TestExtractBearerToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuth_APIKey(t *testing.T) {
+	claims := &domain.AuthClaims{
+		UserID:   uuid.New(),
+		Username: "erp-sync",
+		Role:     domain.RoleManager,
+		APIKeyID: uuid.New(),
+	}
+	readOnly := *claims
+	readOnly.ReadOnly = true
+
+	tests := []struct {
+		name     string
+		method   string
+		claims   *domain.AuthClaims
+		err      error
+		wantCode int
+	}{
+		{"valid", http.MethodPost, claims, nil, http.StatusOK},
+		{"read-only get", http.MethodGet, &readOnly, nil, http.StatusOK},
+		{"read-only post", http.MethodPost, &readOnly, nil, http.StatusForbidden},
+		{"read-only delete", http.MethodDelete, &readOnly, nil, http.StatusForbidden},
+		{"invalid", http.MethodGet, nil, domain.ErrTokenInvalid, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// при X-API-Key токен из Authorization не проверяется
+			validator := NewMockTokenValidator(t)
+			keys := NewMockAPIKeyValidator(t)
+			keys.EXPECT().ValidateAPIKey(mock.Anything, "wck_key").Return(tt.claims, tt.err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(tt.method, "/test", nil)
+			c.Request.Header.Set(APIKeyHeader, "wck_key")
+			c.Request.Header.Set("Authorization", "Bearer some-token")
+
+			Auth(validator, keys)(c)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusOK {
+				assert.True(t, c.IsAborted())
+				return
+			}
+			val, exists := c.Get(ClaimsKey)
+			assert.True(t, exists)
+			assert.Equal(t, tt.claims, val)
+		})
+	}
+}
+
+func TestExtractBearerToken_WebSocketProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		upgrade  string
+		protocol string
+		want     string
+	}{
+		{"token after bearer", "websocket", "bearer, token123", "token123"},
+		{"other protocols around", "websocket", "json, Bearer,token123, v2", "token123"},
+		{"bearer without token", "websocket", "bearer", ""},
+		{"no bearer protocol", "websocket", "token123", ""},
+		{"not an upgrade request", "", "bearer, token123", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.upgrade != "" {
+				c.Request.Header.Set("Upgrade", tt.upgrade)
+			}
+			c.Request.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+
+			assert.Equal(t, tt.want, extractBearerToken(c))
+		})
+	}
+}
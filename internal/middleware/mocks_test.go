@@ -5,6 +5,8 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -97,3 +99,98 @@ func (_c *MockTokenValidator_Validate_Call) RunAndReturn(run func(tokenStr strin
 	_c.Call.Return(run)
 	return _c
 }
+
+// NewMockAPIKeyValidator creates a new instance of MockAPIKeyValidator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAPIKeyValidator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAPIKeyValidator {
+	mock := &MockAPIKeyValidator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAPIKeyValidator is an autogenerated mock type for the APIKeyValidator type
+type MockAPIKeyValidator struct {
+	mock.Mock
+}
+
+type MockAPIKeyValidator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAPIKeyValidator) EXPECT() *MockAPIKeyValidator_Expecter {
+	return &MockAPIKeyValidator_Expecter{mock: &_m.Mock}
+}
+
+// ValidateAPIKey provides a mock function for the type MockAPIKeyValidator
+func (_mock *MockAPIKeyValidator) ValidateAPIKey(ctx context.Context, key string) (*domain.AuthClaims, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAPIKey")
+	}
+
+	var r0 *domain.AuthClaims
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.AuthClaims, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.AuthClaims); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthClaims)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAPIKeyValidator_ValidateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateAPIKey'
+type MockAPIKeyValidator_ValidateAPIKey_Call struct {
+	*mock.Call
+}
+
+// ValidateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockAPIKeyValidator_Expecter) ValidateAPIKey(ctx interface{}, key interface{}) *MockAPIKeyValidator_ValidateAPIKey_Call {
+	return &MockAPIKeyValidator_ValidateAPIKey_Call{Call: _e.mock.On("ValidateAPIKey", ctx, key)}
+}
+
+func (_c *MockAPIKeyValidator_ValidateAPIKey_Call) Run(run func(ctx context.Context, key string)) *MockAPIKeyValidator_ValidateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyValidator_ValidateAPIKey_Call) Return(authClaims *domain.AuthClaims, err error) *MockAPIKeyValidator_ValidateAPIKey_Call {
+	_c.Call.Return(authClaims, err)
+	return _c
+}
+
+func (_c *MockAPIKeyValidator_ValidateAPIKey_Call) RunAndReturn(run func(ctx context.Context, key string) (*domain.AuthClaims, error)) *MockAPIKeyValidator_ValidateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,296 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// leeway - допуск на расхождение часов с провайдером при проверке exp и iat
+	leeway = time.Minute
+	// maxResponseSize - предел ответа провайдера; discovery и JWKS - килобайты
+	maxResponseSize = 1 << 20
+)
+
+// signingMethods - алгоритмы подписи ID token, которые принимаются; HS* не принимаются:
+// ими подписывают общим секретом, а ключи провайдера берутся из JWKS
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Options - клиент приложения у провайдера OpenID Connect
+type Options struct {
+	Issuer       string   // URL провайдера; discovery - Issuer + /.well-known/openid-configuration
+	ClientID     string   // client_id приложения
+	ClientSecret string   // пустой - публичный клиент, защищённый только PKCE
+	RedirectURL  string   // куда провайдер возвращает пользователя с code и state
+	Scopes       []string // openid добавляется всегда
+	// UsernameClaim и GroupsClaim - утверждения ID token с логином и группами пользователя
+	UsernameClaim string
+	GroupsClaim   string
+	Timeout       time.Duration // таймаут запроса к провайдеру
+}
+
+// Client - вход через провайдера OpenID Connect по authorization code flow с PKCE (S256).
+// Настройки провайдера (discovery) загружаются при первом обращении и кэшируются:
+// приложение стартует, даже если провайдер недоступен.
+type Client struct {
+	opts Options
+	http *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      *keySet
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewClient(opts Options) *Client {
+	opts.Issuer = strings.TrimSuffix(opts.Issuer, "/")
+	if opts.UsernameClaim == "" {
+		opts.UsernameClaim = "preferred_username"
+	}
+	if opts.GroupsClaim == "" {
+		opts.GroupsClaim = "groups"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	scopes := []string{"openid"}
+	for _, s := range opts.Scopes {
+		if s != "openid" && s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	opts.Scopes = scopes
+
+	return &Client{
+		opts: opts,
+		http: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+// AuthURL - адрес страницы входа провайдера. state и nonce связывают ответ провайдера
+// с этим входом, verifier - секрет PKCE: провайдер получает только его хеш
+func (c *Client) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid authorization_endpoint: %v", domain.ErrIdentityProvider, err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.opts.ClientID)
+	q.Set("redirect_uri", c.opts.RedirectURL)
+	q.Set("scope", strings.Join(c.opts.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange обменивает code на токены провайдера и возвращает пользователя из проверенного ID token:
+// подпись ключом из JWKS, iss, aud, срок действия и nonce этого входа
+func (c *Client) Exchange(ctx context.Context, code, verifier, nonce string) (*domain.OIDCIdentity, error) {
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := c.token(ctx, doc.TokenEndpoint, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return c.verify(ctx, rawIDToken, nonce)
+}
+
+// CodeChallenge - code_challenge метода S256 (RFC 7636)
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (c *Client) discover(ctx context.Context) (*discoveryDocument, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+
+	var doc discoveryDocument
+	if err := c.getJSON(ctx, c.opts.Issuer+discoveryPath, &doc); err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	// иначе ID token от подменённого провайдера прошёл бы проверку iss
+	if strings.TrimSuffix(doc.Issuer, "/") != c.opts.Issuer {
+		return nil, fmt.Errorf("%w: discovery issuer %q does not match %q", domain.ErrIdentityProvider, doc.Issuer, c.opts.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete discovery document", domain.ErrIdentityProvider)
+	}
+
+	c.discovery = &doc
+	c.keys = newKeySet(doc.JWKSURI, c.getJSON)
+	return c.discovery, nil
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (c *Client) token(ctx context.Context, endpoint, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.opts.RedirectURL},
+		"client_id":     {c.opts.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrIdentityProvider, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.opts.ClientSecret != "" {
+		// client_secret_basic: id и секрет кодируются как form-значения (RFC 6749, 2.3.1)
+		req.SetBasicAuth(url.QueryEscape(c.opts.ClientID), url.QueryEscape(c.opts.ClientSecret))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: token request: %v", domain.ErrIdentityProvider, err)
+	}
+	defer resp.Body.Close()
+
+	var res tokenResponse
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&res); err != nil {
+		return "", fmt.Errorf("%w: token response (status %d): %v", domain.ErrIdentityProvider, resp.StatusCode, err)
+	}
+	// отклонённый code (истёк, уже использован, неверный verifier) - ошибка входа, а не провайдера
+	if res.Error == "invalid_grant" {
+		return "", fmt.Errorf("%w: %s", domain.ErrTokenInvalid, res.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || res.Error != "" {
+		return "", fmt.Errorf("%w: token endpoint: status %d: %s %s",
+			domain.ErrIdentityProvider, resp.StatusCode, res.Error, res.ErrorDescription)
+	}
+	if res.IDToken == "" {
+		return "", fmt.Errorf("%w: token response without id_token", domain.ErrIdentityProvider)
+	}
+	return res.IDToken, nil
+}
+
+func (c *Client) verify(ctx context.Context, rawIDToken, nonce string) (*domain.OIDCIdentity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (any, error) { return c.keys.verificationKey(ctx, token) },
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(c.opts.Issuer),
+		jwt.WithAudience(c.opts.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(leeway),
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrIdentityProvider) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: id token: %v", domain.ErrTokenInvalid, err)
+	}
+
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, fmt.Errorf("%w: id token nonce mismatch", domain.ErrTokenInvalid)
+	}
+	// при нескольких получателях токен должен быть выдан именно этому клиенту (OIDC Core, 3.1.3.7)
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != c.opts.ClientID {
+			return nil, fmt.Errorf("%w: id token azp mismatch", domain.ErrTokenInvalid)
+		}
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: id token without sub", domain.ErrTokenInvalid)
+	}
+
+	identity := &domain.OIDCIdentity{
+		Issuer:   c.opts.Issuer,
+		Subject:  subject,
+		Username: stringClaim(claims, c.opts.UsernameClaim),
+		Email:    stringClaim(claims, "email"),
+		Name:     stringClaim(claims, "name"),
+		Groups:   stringsClaim(claims, c.opts.GroupsClaim),
+	}
+	return identity, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, rawURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrIdentityProvider, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrIdentityProvider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: GET %s: status %d", domain.ErrIdentityProvider, rawURL, resp.StatusCode)
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(dst); err != nil {
+		return fmt.Errorf("%w: GET %s: %v", domain.ErrIdentityProvider, rawURL, err)
+	}
+	return nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// stringsClaim - массив строк; одна строка (так некоторые провайдеры отдают единственную группу) - массив из неё
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
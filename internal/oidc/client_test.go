@@ -0,0 +1,195 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRedirectURL = "http://localhost:8080/"
+
+func newTestProvider(t *testing.T) *oidctest.Server {
+	t.Helper()
+	srv, err := oidctest.NewServer(oidctest.Options{
+		ClientID:     "warehouse",
+		ClientSecret: "s3cret",
+		RedirectURL:  testRedirectURL,
+		Users: []oidctest.User{
+			{Subject: "u-1", Username: "alice", Email: "alice@example.com", Groups: []string{"wh-admins", "staff"}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(srv *oidctest.Server, secret string) *Client {
+	return NewClient(Options{
+		Issuer:       srv.Issuer(),
+		ClientID:     "warehouse",
+		ClientSecret: secret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"profile", "email", "groups"},
+	})
+}
+
+// login проходит вход у провайдера и возвращает code
+func login(t *testing.T, srv *oidctest.Server, c *Client, state, nonce, verifier string) string {
+	t.Helper()
+	authURL, err := c.AuthURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err)
+
+	redirect, err := srv.Login(authURL, "alice")
+	require.NoError(t, err)
+	require.Equal(t, state, redirect.Query().Get("state"))
+	require.NotEmpty(t, redirect.Query().Get("code"))
+	return redirect.Query().Get("code")
+}
+
+func TestClient_AuthURL(t *testing.T) {
+	srv := newTestProvider(t)
+	c := newTestClient(srv, "s3cret")
+
+	raw, err := c.AuthURL(context.Background(), "state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, srv.Issuer()+oidctest.AuthorizePath, u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "openid profile email groups", q.Get("scope"))
+	assert.Equal(t, testRedirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, CodeChallenge("verifier"), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+}
+
+func TestClient_Exchange(t *testing.T) {
+	srv := newTestProvider(t)
+	c := newTestClient(srv, "s3cret")
+
+	code := login(t, srv, c, "state", "nonce", "verifier")
+	identity, err := c.Exchange(context.Background(), code, "verifier", "nonce")
+	require.NoError(t, err)
+
+	assert.Equal(t, srv.Issuer(), identity.Issuer)
+	assert.Equal(t, "u-1", identity.Subject)
+	assert.Equal(t, "alice", identity.Username)
+	assert.Equal(t, "alice@example.com", identity.Email)
+	assert.Equal(t, []string{"wh-admins", "staff"}, identity.Groups)
+
+	// code одноразовый
+	_, err = c.Exchange(context.Background(), code, "verifier", "nonce")
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
+
+func TestClient_Exchange_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		verifier string
+		nonce    string
+		modify   func(jwt.MapClaims)
+		wantErr  error
+	}{
+		{name: "wrong pkce verifier", verifier: "other", wantErr: domain.ErrTokenInvalid},
+		{name: "nonce mismatch", nonce: "other", wantErr: domain.ErrTokenInvalid},
+		{name: "wrong client secret", secret: "wrong", wantErr: domain.ErrIdentityProvider},
+		{
+			name:    "foreign audience",
+			modify:  func(c jwt.MapClaims) { c["aud"] = "other-client" },
+			wantErr: domain.ErrTokenInvalid,
+		},
+		{
+			name:    "foreign issuer",
+			modify:  func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			wantErr: domain.ErrTokenInvalid,
+		},
+		{
+			name:    "expired",
+			modify:  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			wantErr: domain.ErrTokenInvalid,
+		},
+		{
+			name:    "several audiences without azp",
+			modify:  func(c jwt.MapClaims) { c["aud"] = []string{"warehouse", "other-client"} },
+			wantErr: domain.ErrTokenInvalid,
+		},
+		{
+			name:    "without subject",
+			modify:  func(c jwt.MapClaims) { delete(c, "sub") },
+			wantErr: domain.ErrTokenInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestProvider(t)
+			srv.SetModifyClaims(tt.modify)
+			secret := "s3cret"
+			if tt.secret != "" {
+				secret = tt.secret
+			}
+			c := newTestClient(srv, secret)
+
+			code := login(t, srv, c, "state", "nonce", "verifier")
+			verifier, nonce := "verifier", "nonce"
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			_, err := c.Exchange(context.Background(), code, verifier, nonce)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// токен, подписанный не ключом провайдера, не принимается, даже если kid совпадает
+func TestClient_Verify_ForeignSignature(t *testing.T) {
+	srv := newTestProvider(t)
+	other := newTestProvider(t)
+	c := newTestClient(srv, "s3cret")
+	_, err := c.AuthURL(context.Background(), "state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	token, err := other.Sign(jwt.MapClaims{
+		"iss":   srv.Issuer(),
+		"aud":   "warehouse",
+		"sub":   "u-1",
+		"nonce": "nonce",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	_, err = c.verify(context.Background(), token, "nonce")
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	// HS256 с открытым ключом в качестве секрета тоже
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": srv.Issuer(), "aud": "warehouse", "sub": "u-1"})
+	forged.Header["kid"] = "oidctest-1"
+	raw, err := forged.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = c.verify(context.Background(), raw, "nonce")
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
+
+func TestClient_DiscoveryIssuerMismatch(t *testing.T) {
+	srv := newTestProvider(t)
+	c := NewClient(Options{Issuer: srv.Issuer() + "/", ClientID: "warehouse"})
+	_, err := c.AuthURL(context.Background(), "state", "nonce", "verifier")
+	require.NoError(t, err, "trailing slash is not a different issuer")
+
+	c = NewClient(Options{Issuer: srv.Issuer() + "/realms/other", ClientID: "warehouse"})
+	_, err = c.AuthURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, domain.ErrIdentityProvider)
+}
@@ -0,0 +1,349 @@
+// Package oidctest - провайдер OpenID Connect для тестов и локального запуска.
+// Поддерживает то, что нужно входу приложения: discovery, authorization code flow
+// с PKCE (S256), ID token, подписанный RS256, и JWKS. Пароли не спрашиваются:
+// на странице входа выбирается один из заданных пользователей.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AuthorizePath = "/authorize"
+	TokenPath     = "/token"
+	JWKSPath      = "/jwks"
+
+	// LoginParam - параметр страницы входа с логином пользователя; без него показывается выбор пользователя
+	LoginParam = "login"
+
+	codeTTL = time.Minute
+	keyID   = "oidctest-1"
+)
+
+// User - пользователь провайдера
+type User struct {
+	Subject  string   `json:"sub"`
+	Username string   `json:"preferred_username"`
+	Email    string   `json:"email,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// Options - настройки провайдера и единственного зарегистрированного в нём клиента
+type Options struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // пустой - публичный клиент без аутентификации
+	RedirectURL  string // пустой - принимается любой redirect_uri
+	TokenTTL     time.Duration
+	Users        []User
+	// ModifyClaims меняет утверждения ID token перед подписью - для проверки отказов клиента
+	ModifyClaims func(claims jwt.MapClaims)
+}
+
+// Provider - http.Handler провайдера
+type Provider struct {
+	opts Options
+	key  *rsa.PrivateKey
+	mux  *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]*authCode
+}
+
+type authCode struct {
+	user        User
+	redirectURI string
+	nonce       string
+	challenge   string
+	expiresAt   time.Time
+}
+
+func NewProvider(opts Options) (*Provider, error) {
+	opts.Issuer = strings.TrimSuffix(opts.Issuer, "/")
+	if opts.Issuer == "" || opts.ClientID == "" {
+		return nil, fmt.Errorf("oidctest: issuer and client id are required")
+	}
+	if opts.TokenTTL <= 0 {
+		opts.TokenTTL = time.Hour
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("oidctest: generate key: %w", err)
+	}
+
+	p := &Provider{
+		opts:  opts,
+		key:   key,
+		mux:   http.NewServeMux(),
+		codes: make(map[string]*authCode),
+	}
+	p.mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	p.mux.HandleFunc("GET "+AuthorizePath, p.authorize)
+	p.mux.HandleFunc("POST "+TokenPath, p.token)
+	p.mux.HandleFunc("GET "+JWKSPath, p.jwks)
+	return p, nil
+}
+
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+// SetModifyClaims заменяет Options.ModifyClaims
+func (p *Provider) SetModifyClaims(fn func(claims jwt.MapClaims)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.opts.ModifyClaims = fn
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.opts.Issuer,
+		"authorization_endpoint":                p.opts.Issuer + AuthorizePath,
+		"token_endpoint":                        p.opts.Issuer + TokenPath,
+		"jwks_uri":                              p.opts.Issuer + JWKSPath,
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"scopes_supported":                      []string{"openid", "profile", "email", "groups"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"grant_types_supported":                 []string{"authorization_code"},
+	})
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Mock OIDC login</title></head>
+<body>
+<h1>Mock OIDC provider</h1>
+<p>Войти как:</p>
+<ul>
+{{range .Users}}<li><a href="{{$.Base}}&amp;login={{.Username}}">{{.Username}}</a>{{if .Groups}} ({{range $i, $g := .Groups}}{{if $i}}, {{end}}{{$g}}{{end}}){{end}}</li>
+{{end}}</ul>
+</body></html>`))
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// ошибки клиента и redirect_uri показываются на странице: перенаправлять на неизвестный адрес нельзя
+	if q.Get("client_id") != p.opts.ClientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI := q.Get("redirect_uri")
+	if redirectURI == "" || (p.opts.RedirectURL != "" && redirectURI != p.opts.RedirectURL) {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	state := q.Get("state")
+	switch {
+	case q.Get("response_type") != "code":
+		redirectError(w, r, redirectURI, state, "unsupported_response_type")
+		return
+	case !slices.Contains(strings.Fields(q.Get("scope")), "openid"):
+		redirectError(w, r, redirectURI, state, "invalid_scope")
+		return
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		redirectError(w, r, redirectURI, state, "invalid_request")
+		return
+	}
+
+	user, ok := p.user(q.Get(LoginParam))
+	if !ok {
+		base := *r.URL
+		params := r.URL.Query()
+		params.Del(LoginParam)
+		base.RawQuery = params.Encode()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = loginPage.Execute(w, map[string]any{"Base": base.String(), "Users": p.opts.Users})
+		return
+	}
+
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = &authCode{
+		user:        user,
+		redirectURI: redirectURI,
+		nonce:       q.Get("nonce"),
+		challenge:   q.Get("code_challenge"),
+		expiresAt:   time.Now().Add(codeTTL),
+	}
+	p.mu.Unlock()
+
+	redirect(w, r, redirectURI, url.Values{"code": {code}, "state": {state}})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if !p.authenticateClient(r) {
+		tokenError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+		return
+	}
+
+	p.mu.Lock()
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code")) // code одноразовый, даже если обмен не удался
+	modify := p.opts.ModifyClaims
+	p.mu.Unlock()
+
+	switch {
+	case !ok || time.Now().After(code.expiresAt):
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "unknown or expired code")
+		return
+	case r.PostForm.Get("redirect_uri") != code.redirectURI:
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "redirect_uri mismatch")
+		return
+	case challenge(r.PostForm.Get("code_verifier")) != code.challenge:
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "code_verifier mismatch")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                p.opts.Issuer,
+		"sub":                code.user.Subject,
+		"aud":                p.opts.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(p.opts.TokenTTL).Unix(),
+		"preferred_username": code.user.Username,
+	}
+	if code.nonce != "" {
+		claims["nonce"] = code.nonce
+	}
+	if code.user.Email != "" {
+		claims["email"] = code.user.Email
+	}
+	if code.user.Name != "" {
+		claims["name"] = code.user.Name
+	}
+	if code.user.Groups != nil {
+		claims["groups"] = code.user.Groups
+	}
+	if modify != nil {
+		modify(claims)
+	}
+
+	idToken, err := p.Sign(claims)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(p.opts.TokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// Sign подписывает claims ключом провайдера - для токенов, которые провайдер сам не выдал бы
+func (p *Provider) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(p.key)
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// authenticateClient - client_secret_basic или client_secret_post; у публичного клиента - только client_id
+func (p *Provider) authenticateClient(r *http.Request) bool {
+	id, secret, basic := r.BasicAuth()
+	if basic {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != p.opts.ClientID {
+		return false
+	}
+	return p.opts.ClientSecret == "" ||
+		subtle.ConstantTimeCompare([]byte(secret), []byte(p.opts.ClientSecret)) == 1
+}
+
+func (p *Provider) user(login string) (User, bool) {
+	for _, u := range p.opts.Users {
+		if login != "" && u.Username == login {
+			return u, true
+		}
+	}
+	return User{}, false
+}
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func redirect(w http.ResponseWriter, r *http.Request, redirectURI string, params url.Values) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, redirectURI, state, code string) {
+	redirect(w, r, redirectURI, url.Values{"error": {code}, "state": {state}})
+}
+
+func tokenError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
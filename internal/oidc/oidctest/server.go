@@ -0,0 +1,59 @@
+package oidctest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// Server - Provider на локальном httptest.Server; Issuer - адрес сервера
+type Server struct {
+	*Provider
+	*httptest.Server
+}
+
+// NewServer запускает провайдер; opts.Issuer задаётся адресом сервера. Остановить - Close.
+func NewServer(opts Options) (*Server, error) {
+	srv := httptest.NewUnstartedServer(nil)
+	srv.Start()
+
+	opts.Issuer = srv.URL
+	p, err := NewProvider(opts)
+	if err != nil {
+		srv.Close()
+		return nil, err
+	}
+	srv.Config.Handler = p
+
+	return &Server{Provider: p, Server: srv}, nil
+}
+
+// Issuer - адрес провайдера для настроек клиента
+func (s *Server) Issuer() string { return s.URL }
+
+// Login проходит страницу входа authURL за браузер пользователя login и возвращает
+// адрес, на который провайдер его перенаправил: redirect_uri с code и state или error
+func (s *Server) Login(authURL, login string) (*url.URL, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set(LoginParam, login)
+	u.RawQuery = q.Encode()
+
+	client := *s.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return nil, fmt.Errorf("oidctest: login as %q: status %d", login, resp.StatusCode)
+	}
+	return resp.Location()
+}
@@ -0,0 +1,34 @@
+// Package publisher - получатели событий outbox: лог, файл JSON Lines, HTTP, Kafka
+// и Memory для тестов; Multi раздаёт событие нескольким получателям.
+// Файл, HTTP и Kafka получают событие в одном формате - Envelope.
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Envelope - событие в том виде, в каком его получают внешние системы.
+// Доставка не менее одного раза: повторы отбрасываются по EventID.
+type Envelope struct {
+	EventID       uuid.UUID       `json:"event_id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func Encode(ev *domain.OutboxEvent) ([]byte, error) {
+	return json.Marshal(Envelope{
+		EventID:       ev.EventID,
+		Type:          string(ev.Type),
+		AggregateType: ev.AggregateType,
+		AggregateID:   ev.AggregateID,
+		OccurredAt:    ev.CreatedAt.UTC(),
+		Data:          ev.Payload,
+	})
+}
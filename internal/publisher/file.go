@@ -0,0 +1,53 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// File дописывает события в файл JSON Lines (по Envelope на строку) и сбрасывает его на диск
+// после каждого события: событие считается доставленным, только когда оно на диске.
+type File struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFile(path string) (*File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("create outbox dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("open outbox file: %w", err)
+	}
+	return &File{f: f}, nil
+}
+
+func (p *File) Publish(_ context.Context, ev *domain.OutboxEvent) error {
+	data, err := Encode(ev)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err = p.f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write outbox file: %w", err)
+	}
+	if err = p.f.Sync(); err != nil {
+		return fmt.Errorf("sync outbox file: %w", err)
+	}
+	return nil
+}
+
+func (p *File) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.f.Close()
+}
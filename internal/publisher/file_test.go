@@ -0,0 +1,70 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(eventType domain.OutboxEventType) *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:            1,
+		EventID:       uuid.New(),
+		AggregateType: domain.OutboxAggregateItem,
+		AggregateID:   uuid.New(),
+		Type:          eventType,
+		Payload:       json.RawMessage(`{"item":{"sku":"WH-001","quantity":7}}`),
+		CreatedAt:     time.Date(2026, 3, 26, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60)),
+	}
+}
+
+func TestFile_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox", "events.jsonl")
+	p, err := NewFile(path)
+	require.NoError(t, err)
+
+	created, deleted := testEvent(domain.OutboxItemCreated), testEvent(domain.OutboxItemDeleted)
+	require.NoError(t, p.Publish(context.Background(), created))
+	require.NoError(t, p.Publish(context.Background(), deleted))
+	require.NoError(t, p.Close())
+
+	// повторное открытие дописывает, а не перезаписывает
+	p, err = NewFile(path)
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(context.Background(), created))
+	require.NoError(t, p.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []Envelope
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var env Envelope
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &env))
+		lines = append(lines, env)
+	}
+	require.NoError(t, sc.Err())
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, created.EventID, lines[0].EventID)
+	assert.Equal(t, "item.created", lines[0].Type)
+	assert.Equal(t, "item", lines[0].AggregateType)
+	assert.Equal(t, created.AggregateID, lines[0].AggregateID)
+	assert.Equal(t, time.UTC, lines[0].OccurredAt.Location())
+	assert.True(t, created.CreatedAt.Equal(lines[0].OccurredAt))
+	assert.JSONEq(t, string(created.Payload), string(lines[0].Data))
+
+	assert.Equal(t, "item.deleted", lines[1].Type)
+	assert.Equal(t, created.EventID, lines[2].EventID)
+}
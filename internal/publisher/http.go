@@ -0,0 +1,62 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// HTTP отправляет каждое событие POST-запросом с Envelope в теле.
+// Доставленным считается событие, на которое получатель ответил 2xx.
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTP{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *HTTP) Publish(ctx context.Context, ev *domain.OutboxEvent) error {
+	data, err := Encode(ev)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", ev.EventID.String())
+	req.Header.Set("X-Event-Type", string(ev.Type))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// тело дочитывается, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return nil
+}
+
+func (p *HTTP) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
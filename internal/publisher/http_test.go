@@ -0,0 +1,61 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Publish(t *testing.T) {
+	ev := testEvent(domain.OutboxItemUpdated)
+
+	var got Envelope
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, ev.EventID.String(), r.Header.Get("X-Event-ID"))
+		assert.Equal(t, "item.updated", r.Header.Get("X-Event-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(srv.URL, time.Second)
+	defer p.Close()
+
+	require.NoError(t, p.Publish(context.Background(), ev))
+	assert.Equal(t, ev.EventID, got.EventID)
+	assert.Equal(t, ev.AggregateID, got.AggregateID)
+}
+
+func TestHTTP_Publish_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(srv.URL, time.Second)
+	defer p.Close()
+
+	err := p.Publish(context.Background(), testEvent(domain.OutboxItemCreated))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestHTTP_Publish_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	p := NewHTTP(url, time.Second)
+
+	assert.Error(t, p.Publish(context.Background(), testEvent(domain.OutboxItemCreated)))
+}
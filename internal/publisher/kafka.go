@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/kafka"
+)
+
+// Kafka пишет события в топик; ключ сообщения - id агрегата, поэтому события
+// одного товара попадают в одну партицию и читаются по порядку
+type Kafka struct {
+	producer *kafka.Producer
+}
+
+func NewKafka(brokers []string, topic string) *Kafka {
+	return &Kafka{producer: kafka.NewProducer(brokers, topic)}
+}
+
+func (p *Kafka) Publish(ctx context.Context, ev *domain.OutboxEvent) error {
+	data, err := Encode(ev)
+	if err != nil {
+		return err
+	}
+	return p.producer.Send(ctx, []byte(ev.AggregateID.String()), data)
+}
+
+func (p *Kafka) Close() error {
+	return p.producer.Close()
+}
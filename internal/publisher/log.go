@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// Log пишет события в лог приложения - получатель по умолчанию, пока внешняя система не подключена
+type Log struct {
+	log logger.Logger
+}
+
+func NewLog(log logger.Logger) *Log {
+	return &Log{log: log.With("component", "OutboxLogPublisher")}
+}
+
+func (p *Log) Publish(ctx context.Context, ev *domain.OutboxEvent) error {
+	p.log.Ctx(ctx).Info("outbox event",
+		"event_id", ev.EventID,
+		"event_type", ev.Type,
+		"aggregate_type", ev.AggregateType,
+		"aggregate_id", ev.AggregateID,
+		"payload", string(ev.Payload),
+	)
+	return nil
+}
+
+func (p *Log) Close() error { return nil }
@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Memory запоминает события в памяти - замена внешней системы в тестах.
+// FailNext заставляет следующие отправки завершиться ошибками.
+type Memory struct {
+	mu     sync.Mutex
+	events []*domain.OutboxEvent
+	fail   []error
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (p *Memory) Publish(_ context.Context, ev *domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.fail) > 0 {
+		err := p.fail[0]
+		p.fail = p.fail[1:]
+		return err
+	}
+	p.events = append(p.events, ev)
+	return nil
+}
+
+// FailNext - следующие len(errs) отправок вернут эти ошибки по порядку
+func (p *Memory) FailNext(errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fail = append(p.fail, errs...)
+}
+
+// Events - доставленные события в порядке доставки
+func (p *Memory) Events() []*domain.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.events)
+}
+
+func (p *Memory) Close() error { return nil }
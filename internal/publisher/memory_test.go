@@ -0,0 +1,25 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_FailNext(t *testing.T) {
+	p := NewMemory()
+	errDown := errors.New("down")
+	p.FailNext(errDown)
+
+	ev := testEvent(domain.OutboxItemCreated)
+	assert.ErrorIs(t, p.Publish(context.Background(), ev), errDown)
+	assert.Empty(t, p.Events(), "failed event must not be recorded")
+
+	require.NoError(t, p.Publish(context.Background(), ev))
+	require.Len(t, p.Events(), 1)
+	assert.Same(t, ev, p.Events()[0])
+}
@@ -0,0 +1,44 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// Publisher - получатель событий outbox
+type Publisher interface {
+	Publish(ctx context.Context, ev *domain.OutboxEvent) error
+}
+
+// Multi отправляет событие всем получателям по очереди и останавливается на первой ошибке.
+// Релей повторит событие целиком, поэтому получатели до упавшего увидят его ещё раз -
+// как и при любом повторе, они отбрасывают его по EventID.
+type Multi struct {
+	publishers []Publisher
+}
+
+func NewMulti(publishers ...Publisher) *Multi {
+	return &Multi{publishers: publishers}
+}
+
+func (p *Multi) Publish(ctx context.Context, ev *domain.OutboxEvent) error {
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close закрывает получателей, у которых есть Close
+func (p *Multi) Close() error {
+	var errs []error
+	for _, pub := range p.publishers {
+		if c, ok := pub.(interface{ Close() error }); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
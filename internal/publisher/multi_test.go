@@ -0,0 +1,27 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMulti_Publish(t *testing.T) {
+	first, second := NewMemory(), NewMemory()
+	p := NewMulti(first, second)
+	ev := testEvent(domain.OutboxItemLowStock)
+
+	errDown := errors.New("down")
+	first.FailNext(errDown)
+	assert.ErrorIs(t, p.Publish(context.Background(), ev), errDown)
+	assert.Empty(t, second.Events(), "publishing must stop at the first error")
+
+	require.NoError(t, p.Publish(context.Background(), ev))
+	assert.Len(t, first.Events(), 1)
+	assert.Len(t, second.Events(), 1)
+	assert.NoError(t, p.Close())
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const apiKeyColumns = `k.id, k.name, k.prefix, k.key_hash, k.user_id, k.role, k.read_only,
+	k.expires_at, k.last_used_at, k.created_by, k.created_at, k.updated_at`
+
+// APIKeyRepository - ключи интеграций
+type APIKeyRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewAPIKeyRepository(db *dbpg.DB, strategy retry.Strategy) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	const op = "APIKeyRepository.Create"
+
+	query := `INSERT INTO api_keys AS k (name, prefix, key_hash, user_id, role, read_only, expires_at, created_by)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING ` + apiKeyColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query,
+		key.Name, key.Prefix, key.KeyHash, key.UserID, string(key.Role), key.ReadOnly, key.ExpiresAt, key.CreatedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := scanAPIKey(row)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("%s - scan api key: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	const op = "APIKeyRepository.GetByID"
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys k WHERE k.id=$1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan api key: %w", op, err)
+	}
+	return key, nil
+}
+
+// GetByHash - ключ вместе с владельцем (Owner: логин, роль и отключение)
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	const op = "APIKeyRepository.GetByHash"
+
+	query := `SELECT ` + apiKeyColumns + `, u.username, u.role, u.disabled_at
+			  FROM api_keys k
+			  JOIN users u ON u.id = k.user_id
+			  WHERE k.key_hash=$1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, keyHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var owner domain.User
+	key, err := scanAPIKey(row, &owner.Username, &owner.Role, &owner.DisabledAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan api key: %w", op, err)
+	}
+	owner.ID = key.UserID
+	key.Owner = &owner
+	return key, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	const op = "APIKeyRepository.List"
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys k ORDER BY k.created_at, k.id`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - scan api key: %w", op, err)
+		}
+		res = append(res, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+// Update меняет заданные поля ключа; nil в input - поле остаётся прежним
+func (r *APIKeyRepository) Update(ctx context.Context, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error) {
+	const op = "APIKeyRepository.Update"
+
+	var role *string
+	if input.Role != nil {
+		s := string(*input.Role)
+		role = &s
+	}
+
+	query := `UPDATE api_keys AS k
+			  SET name = COALESCE($2, name),
+			      role = COALESCE($3, role),
+			      read_only = COALESCE($4, read_only),
+			      expires_at = COALESCE($5, expires_at),
+			      updated_at = now()
+			  WHERE k.id = $1
+			  RETURNING ` + apiKeyColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id, input.Name, role, input.ReadOnly, input.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan api key: %w", op, err)
+	}
+	return key, nil
+}
+
+// Delete отзывает ключ: запросы с ним сразу перестают приниматься
+func (r *APIKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "APIKeyRepository.Delete"
+
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, `DELETE FROM api_keys WHERE id=$1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// TouchLastUsed записывает время последнего использования ключа
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	const op = "APIKeyRepository.TouchLastUsed"
+
+	query := `UPDATE api_keys SET last_used_at = $2
+			  WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2)`
+
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, id, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// scanAPIKey читает apiKeyColumns; extra - столбцы после них
+func scanAPIKey(row rowScanner, extra ...any) (*domain.APIKey, error) {
+	var k domain.APIKey
+	dest := []any{
+		&k.ID, &k.Name, &k.Prefix, &k.KeyHash, &k.UserID, &k.Role, &k.ReadOnly,
+		&k.ExpiresAt, &k.LastUsedAt, &k.CreatedBy, &k.CreatedAt, &k.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	users := NewUserRepository(db, strategy, triggerAuditRecorder{})
+	repo := NewAPIKeyRepository(db, strategy)
+
+	username := "apikeys-" + uuid.NewString()[:8]
+	ownerID, err := users.Create(ctx, uuid.New(), &domain.User{
+		Username:     username,
+		PasswordHash: "$2a$10$initial",
+		Role:         domain.RoleManager,
+	})
+	require.NoError(t, err)
+
+	suffix := uuid.NewString()[:8]
+	created, err := repo.Create(ctx, &domain.APIKey{
+		Name:      "ERP sync",
+		Prefix:    "wck_" + suffix,
+		KeyHash:   uuid.NewString() + uuid.NewString()[:28],
+		UserID:    ownerID,
+		Role:      domain.RoleViewer,
+		ReadOnly:  true,
+		CreatedBy: ownerID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleViewer, created.Role)
+	assert.Nil(t, created.ExpiresAt)
+	assert.Nil(t, created.LastUsedAt)
+
+	// проверка ключа приносит и владельца
+	got, err := repo.GetByHash(ctx, created.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+	require.NotNil(t, got.Owner)
+	assert.Equal(t, username, got.Owner.Username)
+	assert.Equal(t, domain.RoleManager, got.Owner.Role)
+	assert.False(t, got.Owner.Disabled())
+
+	_, err = repo.GetByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	now := time.Now()
+	require.NoError(t, repo.TouchLastUsed(ctx, created.ID, now))
+	// более раннее время не перезаписывает более позднее
+	require.NoError(t, repo.TouchLastUsed(ctx, created.ID, now.Add(-time.Hour)))
+	got, err = repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.WithinDuration(t, now, *got.LastUsedAt, time.Millisecond)
+
+	name := "ERP sync v2"
+	expires := now.Add(24 * time.Hour)
+	updated, err := repo.Update(ctx, created.ID, &domain.UpdateAPIKeyInput{Name: &name, ExpiresAt: &expires})
+	require.NoError(t, err)
+	assert.Equal(t, name, updated.Name)
+	assert.True(t, updated.ReadOnly)
+	require.NotNil(t, updated.ExpiresAt)
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, k := range keys {
+		ids = append(ids, k.ID)
+	}
+	assert.Contains(t, ids, created.ID)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	assert.ErrorIs(t, repo.Delete(ctx, created.ID), domain.ErrNotFound)
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
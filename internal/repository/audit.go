@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/google/uuid"
@@ -12,6 +15,9 @@ import (
 	"github.com/wb-go/wbf/retry"
 )
 
+// auditStreamFetchSize - сколько строк забирать из курсора за один FETCH
+const auditStreamFetchSize = 500
+
 type AuditRepository struct {
 	db       *dbpg.DB
 	strategy retry.Strategy
@@ -29,15 +35,16 @@ func (r *AuditRepository) GetByItemID(ctx context.Context, itemID uuid.UUID) ([]
 
 	query := `
 		SELECT
-			a.id, a.item_id, a.action, a.changed_by,
-			a.old_data, a.new_data, a.diff, a.changed_at,
+			a.id, a.entity_type, a.entity_id, a.action, a.changed_by,
+			a.old_data, a.new_data, a.diff, a.reason, a.reference,
+			a.request_id, a.client_ip, a.user_agent, a.changed_at,
 			COALESCE(u.username, 'unknown') AS username
-		FROM item_audit_log a
+		FROM audit_log a
 		LEFT JOIN users u ON u.id = a.changed_by
-		WHERE item_id=$1
+		WHERE a.entity_type=$1 AND a.entity_id=$2
 		ORDER BY a.changed_at DESC`
 
-	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, itemID)
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, string(domain.AuditEntityItem), itemID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -66,36 +73,8 @@ func (r *AuditRepository) List(
 ) ([]*domain.AuditEntryWithUser, int64, error) {
 	const op = "AuditRepository.List"
 
-	var (
-		conditions []string
-		args       []interface{}
-		argIdx     = 1
-	)
-	if filter.ItemID != nil {
-		conditions = append(conditions, fmt.Sprintf("a.item_id = $%d", argIdx))
-		args = append(args, *filter.ItemID)
-		argIdx++
-	}
-	if filter.UserID != nil {
-		conditions = append(conditions, fmt.Sprintf("a.changed_by = $%d", argIdx))
-		args = append(args, *filter.UserID)
-		argIdx++
-	}
-	if filter.Action != nil {
-		conditions = append(conditions, fmt.Sprintf("a.action = $%d", argIdx))
-		args = append(args, string(*filter.Action))
-		argIdx++
-	}
-	if filter.DateFrom != nil {
-		conditions = append(conditions, fmt.Sprintf("a.changed_at >= $%d", argIdx))
-		args = append(args, *filter.DateFrom)
-		argIdx++
-	}
-	if filter.DateTo != nil {
-		conditions = append(conditions, fmt.Sprintf("a.changed_at <= $%d", argIdx))
-		args = append(args, *filter.DateTo)
-		argIdx++
-	}
+	conditions, args := auditFilterConditions(filter)
+	argIdx := len(args) + 1
 
 	where := ""
 	if len(conditions) > 0 {
@@ -104,11 +83,12 @@ func (r *AuditRepository) List(
 
 	query := fmt.Sprintf(`
 		SELECT 
-			a.id, a.item_id, a.action, a.changed_by,
-			a.old_data, a.new_data, a.diff, a.changed_at,
+			a.id, a.entity_type, a.entity_id, a.action, a.changed_by,
+			a.old_data, a.new_data, a.diff, a.reason, a.reference,
+			a.request_id, a.client_ip, a.user_agent, a.changed_at,
 			COALESCE(u.username, 'unknown') AS username,
 			COUNT(*) OVER() AS total_count
-		FROM item_audit_log a
+		FROM audit_log a
 		LEFT JOIN users u ON u.id = a.changed_by
 		%s
 		ORDER BY a.changed_at DESC
@@ -139,6 +119,379 @@ func (r *AuditRepository) List(
 	return res, totalCount, nil
 }
 
+// ListAfter - до limit записей аудита с id больше afterID по возрастанию id.
+// Id выдаются под блокировкой головы цепочки до конца транзакции, поэтому
+// записи фиксируются в порядке id и новые всегда приходят после уже прочитанных.
+func (r *AuditRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.AuditEntryWithUser, error) {
+	const op = "AuditRepository.ListAfter"
+
+	query := `
+		SELECT
+			a.id, a.entity_type, a.entity_id, a.action, a.changed_by,
+			a.old_data, a.new_data, a.diff, a.reason, a.reference,
+			a.request_id, a.client_ip, a.user_agent, a.changed_at,
+			COALESCE(u.username, 'unknown') AS username
+		FROM audit_log a
+		LEFT JOIN users u ON u.id = a.changed_by
+		WHERE a.id > $1
+		ORDER BY a.id
+		LIMIT $2`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.AuditEntryWithUser
+	for rows.Next() {
+		e, err := scanAuditRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - scan audit: %w", op, err)
+		}
+		res = append(res, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// LastID - id последней зафиксированной записи аудита (голова цепочки)
+func (r *AuditRepository) LastID(ctx context.Context) (int64, error) {
+	const op = "AuditRepository.LastID"
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, `SELECT last_id FROM audit_chain_head`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	if err = row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s - scan: %w", op, err)
+	}
+	return id, nil
+}
+
+// Stream читает записи аудита по фильтру через серверный курсор (DECLARE/FETCH)
+// порциями по auditStreamFetchSize и отдаёт их в fn по одной - без ограничения на объём выборки.
+// Ошибка из fn прерывает чтение и возвращается как есть.
+func (r *AuditRepository) Stream(
+	ctx context.Context,
+	filter *domain.AuditFilter,
+	fn func(e *domain.AuditEntryWithUser) error,
+) error {
+	const op = "AuditRepository.Stream"
+
+	conditions, args := auditFilterConditions(filter)
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		DECLARE audit_export NO SCROLL CURSOR FOR
+		SELECT
+			a.id, a.entity_type, a.entity_id, a.action, a.changed_by,
+			a.old_data, a.new_data, a.diff, a.reason, a.reference,
+			a.request_id, a.client_ip, a.user_agent, a.changed_at,
+			COALESCE(u.username, 'unknown') AS username
+		FROM audit_log a
+		LEFT JOIN users u ON u.id = a.changed_by
+		%s
+		ORDER BY a.changed_at DESC, a.id DESC`, where)
+
+	// курсор живёт только внутри транзакции
+	tx, err := r.db.Master.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("%s - begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s - declare cursor: %w", op, err)
+	}
+
+	err = fetchCursor(ctx, tx, "audit_export", func(rows *sql.Rows) error {
+		e, err := scanAuditRow(rows)
+		if err != nil {
+			return fmt.Errorf("scan audit: %w", err)
+		}
+		return fn(e)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// StreamChain отдаёт в fn все записи аудита по возрастанию id вместе с prev_hash/hash
+// и возвращает голову цепочки. Голова и записи читаются из одного снимка (REPEATABLE READ),
+// иначе параллельная вставка выглядела бы как разрыв цепочки.
+// Записи из архивных секций приходят отрезками (AuditChainLink.Archived) на своём месте по id.
+func (r *AuditRepository) StreamChain(
+	ctx context.Context,
+	fn func(l *domain.AuditChainLink) error,
+) (*domain.AuditChainHead, error) {
+	const op = "AuditRepository.StreamChain"
+
+	tx, err := r.db.Master.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var head domain.AuditChainHead
+	err = tx.QueryRowContext(ctx, `SELECT last_id, last_hash FROM audit_chain_head`).
+		Scan(&head.LastID, &head.LastHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s - get head: %w", op, err)
+	}
+
+	archived, err := archivedSegments(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%s - archived segments: %w", op, err)
+	}
+
+	// отрезки из архивов встают в поток на место своих записей
+	emitArchived := func(beforeID int64) error {
+		for len(archived) > 0 && archived[0].FirstID < beforeID {
+			seg := archived[0]
+			archived = archived[1:]
+			err := fn(&domain.AuditChainLink{
+				AuditEntry: domain.AuditEntry{ID: seg.LastID},
+				PrevHash:   seg.PrevHash,
+				Hash:       seg.Hash,
+				Archived:   seg.Rows,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	query := `
+		DECLARE audit_chain NO SCROLL CURSOR FOR
+		SELECT ` + auditChainColumns + `
+		FROM audit_log
+		ORDER BY id`
+
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("%s - declare cursor: %w", op, err)
+	}
+
+	err = fetchCursor(ctx, tx, "audit_chain", func(rows *sql.Rows) error {
+		l, err := scanChainLink(rows)
+		if err != nil {
+			return err
+		}
+		if err = emitArchived(l.ID); err != nil {
+			return err
+		}
+		return fn(l)
+	})
+	if err == nil {
+		err = emitArchived(math.MaxInt64)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &head, nil
+}
+
+// auditChainColumns - колонки audit_log для scanChainLink
+const auditChainColumns = `
+			id, entity_type, entity_id, action, changed_by,
+			old_data, new_data, diff, reason, reference,
+			request_id, client_ip, user_agent, changed_at,
+			prev_hash, hash`
+
+func scanChainLink(rows *sql.Rows) (*domain.AuditChainLink, error) {
+	var (
+		l       domain.AuditChainLink
+		oldData []byte
+		newData []byte
+		diff    []byte
+	)
+	if err := rows.Scan(
+		&l.ID, &l.EntityType, &l.EntityID, &l.Action, &l.ChangedBy,
+		&oldData, &newData, &diff, &l.Reason, &l.Reference,
+		&l.RequestID, &l.ClientIP, &l.UserAgent, &l.ChangedAt,
+		&l.PrevHash, &l.Hash,
+	); err != nil {
+		return nil, fmt.Errorf("scan audit chain: %w", err)
+	}
+	l.OldData = oldData
+	l.NewData = newData
+	l.Diff = diff
+	return &l, nil
+}
+
+// archivedSegments - отрезки цепочки из архивов, которых сейчас нет в БД, по возрастанию id
+func archivedSegments(ctx context.Context, tx *sql.Tx) ([]domain.AuditChainSegment, error) {
+	var archived []domain.AuditChainSegment
+	err := queryEach(ctx, tx, `SELECT segments FROM audit_archives WHERE status = 'archived'`, nil,
+		func(rows *sql.Rows) error {
+			var (
+				raw  []byte
+				segs []domain.AuditChainSegment
+			)
+			if err := rows.Scan(&raw); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(raw, &segs); err != nil {
+				return err
+			}
+			archived = append(archived, segs...)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(archived, func(i, j int) bool { return archived[i].FirstID < archived[j].FirstID })
+	return archived, nil
+}
+
+// fetchCursor вычитывает открытый курсор порциями по auditStreamFetchSize и отдаёт строки в fn.
+// Ошибка из fn прерывает чтение.
+func fetchCursor(ctx context.Context, tx *sql.Tx, cursor string, fn func(rows *sql.Rows) error) error {
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", auditStreamFetchSize, cursor)
+	for {
+		n, err := fetchBatch(ctx, tx, fetch, fn)
+		if err != nil {
+			return err
+		}
+		if n < auditStreamFetchSize {
+			return nil
+		}
+	}
+}
+
+func fetchBatch(ctx context.Context, tx *sql.Tx, query string, fn func(rows *sql.Rows) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		n++
+		if err = fn(rows); err != nil {
+			return n, err
+		}
+	}
+	return n, rows.Err()
+}
+
+// auditFilterConditions - условия WHERE для List и Stream, плейсхолдеры с $1
+func auditFilterConditions(filter *domain.AuditFilter) ([]string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.EntityType != nil {
+		args = append(args, string(*filter.EntityType))
+		conditions = append(conditions, fmt.Sprintf("a.entity_type = $%d", len(args)))
+	}
+	if filter.EntityID != nil {
+		args = append(args, *filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("a.entity_id = $%d", len(args)))
+	}
+	if filter.ItemID != nil {
+		args = append(args, string(domain.AuditEntityItem), *filter.ItemID)
+		conditions = append(conditions,
+			fmt.Sprintf("a.entity_type = $%d AND a.entity_id = $%d", len(args)-1, len(args)),
+		)
+	}
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("a.changed_by = $%d", len(args)))
+	}
+	if filter.Action != nil {
+		args = append(args, string(*filter.Action))
+		conditions = append(conditions, fmt.Sprintf("a.action = $%d", len(args)))
+	}
+	if filter.Reason != nil && *filter.Reason != "" {
+		args = append(args, "%"+*filter.Reason+"%")
+		conditions = append(conditions, fmt.Sprintf("a.reason ILIKE $%d", len(args)))
+	}
+	if filter.Reference != nil && *filter.Reference != "" {
+		args = append(args, *filter.Reference)
+		conditions = append(conditions, fmt.Sprintf("a.reference = $%d", len(args)))
+	}
+	if filter.RequestID != nil && *filter.RequestID != "" {
+		args = append(args, *filter.RequestID)
+		conditions = append(conditions, fmt.Sprintf("a.request_id = $%d", len(args)))
+	}
+	if filter.Field != nil {
+		args = append(args, *filter.Field)
+		conditions = append(conditions, fmt.Sprintf("a.diff ? $%d", len(args)))
+	}
+	for i := range filter.Changes {
+		var cond string
+		cond, args = auditPredicateCondition(&filter.Changes[i], args)
+		conditions = append(conditions, cond)
+	}
+	if filter.DateFrom != nil {
+		args = append(args, *filter.DateFrom)
+		conditions = append(conditions, fmt.Sprintf("a.changed_at >= $%d", len(args)))
+	}
+	if filter.DateTo != nil {
+		args = append(args, *filter.DateTo)
+		conditions = append(conditions, fmt.Sprintf("a.changed_at <= $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// auditCompareSQL - операторы AuditValuePredicate в SQL
+var auditCompareSQL = map[domain.AuditCompareOp]string{
+	domain.AuditOpEq:  "=",
+	domain.AuditOpNe:  "<>",
+	domain.AuditOpGt:  ">",
+	domain.AuditOpGte: ">=",
+	domain.AuditOpLt:  "<",
+	domain.AuditOpLte: "<=",
+}
+
+// auditPredicateCondition - условие на значение поля в diff. Проверка "diff ? поле" идёт первой,
+// чтобы работал GIN-индекс по diff. Нечисловые значения при числовом сравнении дают NULL
+// (CASE, а не AND: порядок вычисления AND в PostgreSQL не гарантирован), и запись не подходит.
+func auditPredicateCondition(p *domain.AuditValuePredicate, args []interface{}) (string, []interface{}) {
+	args = append(args, p.Field)
+	key := len(args)
+	args = append(args, p.Value)
+	val := len(args)
+
+	number := func(side domain.AuditValueSide) string {
+		return fmt.Sprintf(
+			"CASE WHEN jsonb_typeof(a.diff -> $%d -> '%s') = 'number' THEN (a.diff -> $%d ->> '%s')::numeric END",
+			key, side, key, side,
+		)
+	}
+
+	var expr string
+	switch {
+	case p.Side == domain.AuditValueDelta:
+		expr = fmt.Sprintf("(%s - %s) %s $%d::numeric",
+			number(domain.AuditValueNew), number(domain.AuditValueOld), auditCompareSQL[p.Op], val)
+	case p.IsNumeric():
+		expr = fmt.Sprintf("%s %s $%d::numeric", number(p.Side), auditCompareSQL[p.Op], val)
+	default:
+		expr = fmt.Sprintf("(a.diff -> $%d ->> '%s') %s $%d", key, p.Side, auditCompareSQL[p.Op], val)
+	}
+
+	return fmt.Sprintf("(a.diff ? $%d AND %s)", key, expr), args
+}
+
 func scanAuditRow(rows *sql.Rows) (*domain.AuditEntryWithUser, error) {
 	var (
 		e       domain.AuditEntryWithUser
@@ -148,8 +501,9 @@ func scanAuditRow(rows *sql.Rows) (*domain.AuditEntryWithUser, error) {
 	)
 
 	if err := rows.Scan(
-		&e.ID, &e.ItemID, &e.Action, &e.ChangedBy, &oldData,
-		&newData, &diff, &e.ChangedAt, &e.Username,
+		&e.ID, &e.EntityType, &e.EntityID, &e.Action, &e.ChangedBy, &oldData,
+		&newData, &diff, &e.Reason, &e.Reference,
+		&e.RequestID, &e.ClientIP, &e.UserAgent, &e.ChangedAt, &e.Username,
 	); err != nil {
 		return nil, err
 	}
@@ -170,8 +524,9 @@ func scanAuditRowWithTotal(rows *sql.Rows, totalCount *int64) (*domain.AuditEntr
 	)
 
 	if err := rows.Scan(
-		&e.ID, &e.ItemID, &e.Action, &e.ChangedBy,
-		&oldData, &newData, &diff, &e.ChangedAt,
+		&e.ID, &e.EntityType, &e.EntityID, &e.Action, &e.ChangedBy,
+		&oldData, &newData, &diff, &e.Reason, &e.Reference,
+		&e.RequestID, &e.ClientIP, &e.UserAgent, &e.ChangedAt,
 		&e.Username,
 		totalCount,
 	); err != nil {
@@ -183,3 +538,172 @@ func scanAuditRowWithTotal(rows *sql.Rows, totalCount *int64) (*domain.AuditEntr
 	e.Diff = diff
 	return &e, nil
 }
+
+// auditStatsItemName - имя товара для статистики: текущее, у удалённого - последнее из журнала
+const auditStatsItemName = `COALESCE(i.name, (
+				SELECT COALESCE(l.new_data, l.old_data) ->> 'name'
+				FROM audit_log l
+				WHERE l.entity_type = 'item' AND l.entity_id = s.entity_id
+				ORDER BY l.id DESC
+				LIMIT 1
+			), '')`
+
+// Stats считает агрегаты журнала за [DateFrom, DateTo). Все выборки идут из одного снимка БД,
+// чтобы разбивка по дням и топы сходились между собой.
+func (r *AuditRepository) Stats(ctx context.Context, filter *domain.AuditStatsFilter) (*domain.AuditStats, error) {
+	const op = "AuditRepository.Stats"
+
+	tx, err := r.db.Master.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stats := &domain.AuditStats{DateFrom: filter.DateFrom, DateTo: filter.DateTo}
+	period := []interface{}{filter.DateFrom, filter.DateTo}
+	withLimit := []interface{}{filter.DateFrom, filter.DateTo, filter.Limit}
+
+	dailyQuery := `
+		SELECT
+			(a.changed_at AT TIME ZONE 'UTC')::date AS day,
+			COUNT(*) FILTER (WHERE a.action = 'INSERT'),
+			COUNT(*) FILTER (WHERE a.action = 'UPDATE'),
+			COUNT(*) FILTER (WHERE a.action = 'DELETE')
+		FROM audit_log a
+		WHERE a.changed_at >= $1 AND a.changed_at < $2
+		GROUP BY day
+		ORDER BY day`
+	err = queryEach(ctx, tx, dailyQuery, period, func(rows *sql.Rows) error {
+		var d domain.AuditDailyStat
+		if err := rows.Scan(&d.Day, &d.Inserts, &d.Updates, &d.Deletes); err != nil {
+			return err
+		}
+		stats.Daily = append(stats.Daily, &d)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - daily: %w", op, err)
+	}
+
+	usersQuery := `
+		SELECT a.changed_by, COALESCE(u.username, 'unknown'), COUNT(*) AS n
+		FROM audit_log a
+		LEFT JOIN users u ON u.id = a.changed_by
+		WHERE a.changed_at >= $1 AND a.changed_at < $2
+		GROUP BY a.changed_by, u.username
+		ORDER BY n DESC, a.changed_by
+		LIMIT $3`
+	err = queryEach(ctx, tx, usersQuery, withLimit, func(rows *sql.Rows) error {
+		var u domain.AuditUserStat
+		if err := rows.Scan(&u.UserID, &u.Username, &u.Changes); err != nil {
+			return err
+		}
+		stats.TopUsers = append(stats.TopUsers, &u)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - top users: %w", op, err)
+	}
+
+	itemsQuery := fmt.Sprintf(`
+		WITH s AS (
+			SELECT a.entity_id, COUNT(*) AS n
+			FROM audit_log a
+			WHERE a.entity_type = 'item' AND a.changed_at >= $1 AND a.changed_at < $2
+			GROUP BY a.entity_id
+			ORDER BY n DESC, a.entity_id
+			LIMIT $3
+		)
+		SELECT s.entity_id, %s, s.n
+		FROM s
+		LEFT JOIN items i ON i.id = s.entity_id
+		ORDER BY s.n DESC, s.entity_id`, auditStatsItemName)
+	err = queryEach(ctx, tx, itemsQuery, withLimit, func(rows *sql.Rows) error {
+		var it domain.AuditItemStat
+		if err := rows.Scan(&it.ItemID, &it.Name, &it.Changes); err != nil {
+			return err
+		}
+		stats.TopItems = append(stats.TopItems, &it)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - top items: %w", op, err)
+	}
+
+	fieldsQuery := `
+		SELECT k, COUNT(*) AS n
+		FROM audit_log a
+		CROSS JOIN LATERAL jsonb_object_keys(a.diff) AS k
+		WHERE a.entity_type = 'item' AND a.diff IS NOT NULL
+		  AND a.changed_at >= $1 AND a.changed_at < $2
+		GROUP BY k
+		ORDER BY n DESC, k
+		LIMIT $3`
+	err = queryEach(ctx, tx, fieldsQuery, withLimit, func(rows *sql.Rows) error {
+		var f domain.AuditFieldStat
+		if err := rows.Scan(&f.Field, &f.Changes); err != nil {
+			return err
+		}
+		stats.TopFields = append(stats.TopFields, &f)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - top fields: %w", op, err)
+	}
+
+	quantityQuery := fmt.Sprintf(`
+		WITH s AS (
+			SELECT entity_id, net
+			FROM (
+				SELECT a.entity_id, SUM(CASE a.action
+					WHEN 'INSERT' THEN (a.new_data ->> 'quantity')::BIGINT
+					WHEN 'DELETE' THEN -(a.old_data ->> 'quantity')::BIGINT
+					ELSE (a.diff -> 'quantity' ->> 'new')::BIGINT - (a.diff -> 'quantity' ->> 'old')::BIGINT
+				END) AS net
+				FROM audit_log a
+				WHERE a.entity_type = 'item'
+				  AND a.changed_at >= $1 AND a.changed_at < $2
+				  AND (a.action <> 'UPDATE' OR a.diff ? 'quantity')
+				GROUP BY a.entity_id
+			) t
+			WHERE net <> 0
+			ORDER BY abs(net) DESC, entity_id
+			LIMIT $3
+		)
+		SELECT s.entity_id, %s, s.net
+		FROM s
+		LEFT JOIN items i ON i.id = s.entity_id
+		ORDER BY abs(s.net) DESC, s.entity_id`, auditStatsItemName)
+	err = queryEach(ctx, tx, quantityQuery, withLimit, func(rows *sql.Rows) error {
+		var q domain.AuditQuantityStat
+		if err := rows.Scan(&q.ItemID, &q.Name, &q.Net); err != nil {
+			return err
+		}
+		stats.QuantityChanges = append(stats.QuantityChanges, &q)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s - quantity changes: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// queryEach выполняет запрос в транзакции и отдаёт строки в fn по одной
+func queryEach(ctx context.Context, tx *sql.Tx, query string, args []interface{}, fn func(rows *sql.Rows) error) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
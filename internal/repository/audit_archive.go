@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// auditPartitionPrefix - имена месячных секций: audit_log_YYYY_MM, как в fn_audit_partition()
+const auditPartitionPrefix = "audit_log_"
+
+// auditArchiveLock - advisory lock обслуживания секций: архивацию ведёт один экземпляр приложения,
+// возврат архива в БД ждёт её окончания
+const auditArchiveLock = `hashtext('audit_archive')`
+
+const auditArchiveColumns = `id, partition_name, range_from, range_to, file_name, file_size, file_sha256,
+	row_count, segments, status, archived_at, attached_at, attached_by`
+
+// auditCopyColumns - колонки audit_log в порядке значений из auditCopyValues
+var auditCopyColumns = []string{
+	"id", "entity_type", "entity_id", "action", "changed_by",
+	"old_data", "new_data", "diff", "reason", "reference",
+	"request_id", "client_ip", "user_agent", "changed_at",
+	"prev_hash", "hash",
+}
+
+type AuditArchiveRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewAuditArchiveRepository(db *dbpg.DB, strategy retry.Strategy) *AuditArchiveRepository {
+	return &AuditArchiveRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+// TryLock берёт advisory lock обслуживания секций на отдельном соединении.
+// ok=false - блокировку держит другой экземпляр; иначе её нужно отпустить через unlock.
+func (r *AuditArchiveRepository) TryLock(ctx context.Context) (unlock func(), ok bool, err error) {
+	const op = "AuditArchiveRepository.TryLock"
+
+	conn, err := r.db.Master.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s - get conn: %w", op, err)
+	}
+
+	if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(`+auditArchiveLock+`)`).Scan(&ok); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(`+auditArchiveLock+`)`)
+		_ = conn.Close()
+	}, true, nil
+}
+
+// EnsurePartition создаёт секцию audit_log за месяц month, если её ещё нет.
+// Записи этого месяца из audit_log_default переносятся в новую секцию.
+func (r *AuditArchiveRepository) EnsurePartition(ctx context.Context, month time.Time) error {
+	const op = "AuditArchiveRepository.EnsurePartition"
+
+	_, err := r.db.ExecWithRetry(ctx, r.strategy, `SELECT fn_audit_partition($1::DATE)`, month.Format(time.DateOnly))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DefaultPartitionMonths - месяцы (по UTC), записи за которые лежат в audit_log_default.
+// Так бывает, если секцию за месяц не успели создать заранее.
+func (r *AuditArchiveRepository) DefaultPartitionMonths(ctx context.Context) ([]time.Time, error) {
+	const op = "AuditArchiveRepository.DefaultPartitionMonths"
+
+	query := `
+		SELECT DISTINCT date_trunc('month', changed_at AT TIME ZONE 'UTC')::DATE
+		FROM audit_log_default
+		ORDER BY 1`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var months []time.Time
+	for rows.Next() {
+		var month time.Time
+		if err = rows.Scan(&month); err != nil {
+			return nil, fmt.Errorf("%s - scan: %w", op, err)
+		}
+		months = append(months, time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows: %w", op, err)
+	}
+
+	return months, nil
+}
+
+// ListPartitions - присоединённые месячные секции audit_log по возрастанию месяца.
+// Секция по умолчанию (audit_log_default) в список не входит.
+func (r *AuditArchiveRepository) ListPartitions(ctx context.Context) ([]*domain.AuditPartition, error) {
+	const op = "AuditArchiveRepository.ListPartitions"
+
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'audit_log'::regclass
+		  AND c.relname ~ '^audit_log_[0-9]{4}_[0-9]{2}$'
+		ORDER BY c.relname`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var partitions []*domain.AuditPartition
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%s - scan: %w", op, err)
+		}
+		p, err := parseAuditPartition(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		partitions = append(partitions, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows: %w", op, err)
+	}
+
+	return partitions, nil
+}
+
+// StreamPartition отдаёт в fn записи секции по возрастанию id
+func (r *AuditArchiveRepository) StreamPartition(
+	ctx context.Context,
+	name string,
+	fn func(l *domain.AuditChainLink) error,
+) error {
+	const op = "AuditArchiveRepository.StreamPartition"
+
+	tx, err := r.db.Master.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("%s - begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		DECLARE audit_partition NO SCROLL CURSOR FOR
+		SELECT ` + auditChainColumns + `
+		FROM ` + pq.QuoteIdentifier(name) + `
+		ORDER BY id`
+
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("%s - declare cursor: %w", op, err)
+	}
+
+	err = fetchCursor(ctx, tx, "audit_partition", func(rows *sql.Rows) error {
+		l, err := scanChainLink(rows)
+		if err != nil {
+			return err
+		}
+		return fn(l)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Detach отсоединяет и удаляет выгруженную секцию и сохраняет запись об архиве.
+// Если с момента выгрузки в секции изменилось число записей или последний id - ничего не удаляется.
+// archive == nil - секция пуста и удаляется без архива.
+func (r *AuditArchiveRepository) Detach(ctx context.Context, p *domain.AuditPartition, archive *domain.AuditArchive) error {
+	const op = "AuditArchiveRepository.Detach"
+
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s - begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	table := pq.QuoteIdentifier(p.Name)
+	if _, err = tx.ExecContext(ctx, `ALTER TABLE audit_log DETACH PARTITION `+table); err != nil {
+		return fmt.Errorf("%s - detach: %w", op, err)
+	}
+
+	var (
+		rows, lastID             int64
+		expectedRows, expectedID int64
+	)
+	if err = tx.QueryRowContext(ctx, `SELECT count(*), COALESCE(max(id), 0) FROM `+table).Scan(&rows, &lastID); err != nil {
+		return fmt.Errorf("%s - count rows: %w", op, err)
+	}
+	if archive != nil {
+		expectedRows = archive.Rows
+		if n := len(archive.Segments); n > 0 {
+			expectedID = archive.Segments[n-1].LastID
+		}
+	}
+	if rows != expectedRows || lastID != expectedID {
+		return fmt.Errorf("%s: partition %s changed after export: %d rows up to id %d, archived %d up to id %d",
+			op, p.Name, rows, lastID, expectedRows, expectedID)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DROP TABLE `+table); err != nil {
+		return fmt.Errorf("%s - drop: %w", op, err)
+	}
+
+	if archive != nil {
+		segments, err := json.Marshal(archive.Segments)
+		if err != nil {
+			return fmt.Errorf("%s - marshal segments: %w", op, err)
+		}
+
+		query := `
+			INSERT INTO audit_archives (partition_name, range_from, range_to, file_name, file_size, file_sha256,
+			                            row_count, segments, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'archived')
+			ON CONFLICT (partition_name) DO UPDATE SET
+				range_from  = EXCLUDED.range_from,
+				range_to    = EXCLUDED.range_to,
+				file_name   = EXCLUDED.file_name,
+				file_size   = EXCLUDED.file_size,
+				file_sha256 = EXCLUDED.file_sha256,
+				row_count   = EXCLUDED.row_count,
+				segments    = EXCLUDED.segments,
+				status      = 'archived',
+				archived_at = now(),
+				attached_at = NULL,
+				attached_by = NULL
+			RETURNING id, archived_at`
+
+		err = tx.QueryRowContext(ctx, query,
+			archive.Partition, archive.RangeFrom, archive.RangeTo, archive.FileName, archive.FileSize,
+			archive.FileSHA256, archive.Rows, string(segments),
+		).Scan(&archive.ID, &archive.ArchivedAt)
+		if err != nil {
+			return fmt.Errorf("%s - save archive: %w", op, err)
+		}
+		archive.Status = domain.AuditArchiveArchived
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s - commit: %w", op, err)
+	}
+	return nil
+}
+
+// List - все архивы, новые секции первыми
+func (r *AuditArchiveRepository) List(ctx context.Context) ([]*domain.AuditArchive, error) {
+	const op = "AuditArchiveRepository.List"
+
+	query := `SELECT ` + auditArchiveColumns + ` FROM audit_archives ORDER BY range_from DESC`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var archives []*domain.AuditArchive
+	for rows.Next() {
+		a, err := scanAuditArchive(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - scan: %w", op, err)
+		}
+		archives = append(archives, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s - rows: %w", op, err)
+	}
+
+	return archives, nil
+}
+
+func (r *AuditArchiveRepository) GetByID(ctx context.Context, id int64) (*domain.AuditArchive, error) {
+	const op = "AuditArchiveRepository.GetByID"
+
+	query := `SELECT ` + auditArchiveColumns + ` FROM audit_archives WHERE id = $1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a, err := scanAuditArchive(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan: %w", op, err)
+	}
+	return a, nil
+}
+
+// Attach восстанавливает секцию архива: создаёт таблицу, загружает в неё записи из next
+// (до io.EOF) через COPY и присоединяет к audit_log. Триггеры audit_log на загрузку не срабатывают:
+// id и хеши сохраняются как были. Любая ошибка next откатывает всё.
+func (r *AuditArchiveRepository) Attach(
+	ctx context.Context,
+	archive *domain.AuditArchive,
+	userID uuid.UUID,
+	next func() (*domain.AuditChainLink, error),
+) error {
+	const op = "AuditArchiveRepository.Attach"
+
+	tx, err := r.db.Master.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s - begin tx: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(`+auditArchiveLock+`)`); err != nil {
+		return fmt.Errorf("%s - lock: %w", op, err)
+	}
+
+	var status domain.AuditArchiveStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM audit_archives WHERE id = $1 FOR UPDATE`, archive.ID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("%s - get status: %w", op, err)
+	}
+	if status == domain.AuditArchiveAttached {
+		return fmt.Errorf("%s: %w", op, domain.ErrAuditArchiveAttached)
+	}
+
+	table := pq.QuoteIdentifier(archive.Partition)
+	if _, err = tx.ExecContext(ctx,
+		`CREATE TABLE `+table+` (LIKE audit_log INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`); err != nil {
+		return fmt.Errorf("%s - create table: %w", op, err)
+	}
+
+	if err = copyAuditLinks(ctx, tx, archive.Partition, next); err != nil {
+		return fmt.Errorf("%s - copy: %w", op, err)
+	}
+
+	attach := fmt.Sprintf(`ALTER TABLE audit_log ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)`, table,
+		pq.QuoteLiteral(archive.RangeFrom.UTC().Format(time.RFC3339)),
+		pq.QuoteLiteral(archive.RangeTo.UTC().Format(time.RFC3339)),
+	)
+	if _, err = tx.ExecContext(ctx, attach); err != nil {
+		return fmt.Errorf("%s - attach: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE audit_archives SET status = 'attached', attached_at = now(), attached_by = $2 WHERE id = $1`,
+		archive.ID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s - update archive: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s - commit: %w", op, err)
+	}
+	return nil
+}
+
+func copyAuditLinks(ctx context.Context, tx *sql.Tx, table string, next func() (*domain.AuditChainLink, error)) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, auditCopyColumns...))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for {
+		l, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = stmt.ExecContext(ctx, auditCopyValues(l)...); err != nil {
+			return err
+		}
+	}
+
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
+
+func auditCopyValues(l *domain.AuditChainLink) []interface{} {
+	return []interface{}{
+		l.ID, string(l.EntityType), l.EntityID, string(l.Action), l.ChangedBy,
+		jsonArg(l.OldData), jsonArg(l.NewData), jsonArg(l.Diff), l.Reason, l.Reference,
+		l.RequestID, l.ClientIP, l.UserAgent, l.ChangedAt,
+		l.PrevHash, l.Hash,
+	}
+}
+
+func scanAuditArchive(row rowScanner) (*domain.AuditArchive, error) {
+	var (
+		a        domain.AuditArchive
+		segments []byte
+	)
+	if err := row.Scan(
+		&a.ID, &a.Partition, &a.RangeFrom, &a.RangeTo, &a.FileName, &a.FileSize, &a.FileSHA256,
+		&a.Rows, &segments, &a.Status, &a.ArchivedAt, &a.AttachedAt, &a.AttachedBy,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(segments, &a.Segments); err != nil {
+		return nil, fmt.Errorf("unmarshal segments: %w", err)
+	}
+	return &a, nil
+}
+
+// parseAuditPartition восстанавливает границы месячной секции по её имени
+func parseAuditPartition(name string) (*domain.AuditPartition, error) {
+	from, err := time.Parse("2006_01", strings.TrimPrefix(name, auditPartitionPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected audit partition name %q", name)
+	}
+	return &domain.AuditPartition{
+		Name: name,
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}, nil
+}
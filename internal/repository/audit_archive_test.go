@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stpnv0/WarehouseControl/internal/auditchain"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestAuditArchiveRepository_DetachAttach(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+
+	archives := NewAuditArchiveRepository(db, strategy)
+	audit := NewAuditRepository(db, strategy)
+
+	// месяц из прошлого века, чтобы повторные прогоны не пересекались
+	n := int(time.Now().UnixNano() % 1200)
+	month := time.Date(1900+n/12, time.Month(n%12+1), 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, archives.EnsurePartition(ctx, month))
+
+	for i := 0; i < 3; i++ {
+		_, err := db.Master.ExecContext(ctx, `
+			INSERT INTO audit_log (entity_type, entity_id, action, changed_by, new_data, changed_at)
+			VALUES ('item', $1, 'INSERT', $2, '{"quantity": 1}', $3)`,
+			uuid.New(), uuid.New(), month.Add(time.Duration(i)*time.Hour))
+		require.NoError(t, err)
+	}
+
+	name := fmt.Sprintf("audit_log_%04d_%02d", month.Year(), month.Month())
+	var p *domain.AuditPartition
+	partitions, err := archives.ListPartitions(ctx)
+	require.NoError(t, err)
+	for _, it := range partitions {
+		if it.Name == name {
+			p = it
+		}
+	}
+	require.NotNil(t, p)
+	assert.True(t, p.From.Equal(month))
+
+	var (
+		links []*domain.AuditChainLink
+		segs  auditchain.Segments
+	)
+	require.NoError(t, archives.StreamPartition(ctx, p.Name, func(l *domain.AuditChainLink) error {
+		require.Nil(t, segs.Add(l))
+		links = append(links, l)
+		return nil
+	}))
+	require.Len(t, links, 3)
+
+	archive := &domain.AuditArchive{
+		Partition:  p.Name,
+		RangeFrom:  p.From,
+		RangeTo:    p.To,
+		FileName:   p.Name + ".jsonl.gz",
+		FileSHA256: fmt.Sprintf("%064d", 0),
+		Rows:       segs.Rows(),
+		Segments:   segs.List(),
+	}
+	require.NoError(t, archives.Detach(ctx, p, archive))
+
+	// записей секции в БД больше нет, цепочка проходит через отрезки из audit_archives
+	report, err := auditchain.Verify(ctx, audit, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Valid, "%+v", report.Broken)
+	assert.GreaterOrEqual(t, report.Archived, int64(3))
+
+	i := 0
+	next := func() (*domain.AuditChainLink, error) {
+		if i == len(links) {
+			return nil, io.EOF
+		}
+		i++
+		return links[i-1], nil
+	}
+	adminID := uuid.New()
+	require.NoError(t, archives.Attach(ctx, archive, adminID, next))
+
+	got, err := archives.GetByID(ctx, archive.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuditArchiveAttached, got.Status)
+	require.NotNil(t, got.AttachedBy)
+	assert.Equal(t, adminID, *got.AttachedBy)
+
+	assert.ErrorIs(t, archives.Attach(ctx, archive, adminID, next), domain.ErrAuditArchiveAttached)
+
+	report, err = auditchain.Verify(ctx, audit, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Valid, "%+v", report.Broken)
+}
+
+func TestAuditArchiveRepository_EnsurePartition_MovesDefaultRows(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+
+	archives := NewAuditArchiveRepository(db, strategy)
+	audit := NewAuditRepository(db, strategy)
+
+	// месяц без секции: записи за него уходят в audit_log_default
+	n := int(time.Now().UnixNano() % 1200)
+	month := time.Date(1800+n/12, time.Month(n%12+1), 1, 0, 0, 0, 0, time.UTC)
+	name := fmt.Sprintf("audit_log_%04d_%02d", month.Year(), month.Month())
+
+	var ids []int64
+	for i := 0; i < 2; i++ {
+		var id int64
+		require.NoError(t, db.Master.QueryRowContext(ctx, `
+			INSERT INTO audit_log (entity_type, entity_id, action, changed_by, new_data, changed_at)
+			VALUES ('item', $1, 'INSERT', $2, '{"quantity": 1}', $3)
+			RETURNING id`,
+			uuid.New(), uuid.New(), month.Add(time.Duration(i)*time.Hour)).Scan(&id))
+		ids = append(ids, id)
+	}
+
+	months, err := archives.DefaultPartitionMonths(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, months, month)
+
+	hashes := func(table string) map[int64]string {
+		rows, err := db.Master.QueryContext(ctx, fmt.Sprintf(`SELECT id, hash FROM %s WHERE id = ANY($1)`, table), pq.Array(ids))
+		require.NoError(t, err)
+		defer rows.Close()
+		got := make(map[int64]string)
+		for rows.Next() {
+			var (
+				id   int64
+				hash string
+			)
+			require.NoError(t, rows.Scan(&id, &hash))
+			got[id] = hash
+		}
+		require.NoError(t, rows.Err())
+		return got
+	}
+	before := hashes("audit_log_default")
+	require.Len(t, before, 2)
+
+	// раньше создание секции падало: в секции по умолчанию уже есть строки за этот месяц
+	require.NoError(t, archives.EnsurePartition(ctx, month))
+	require.NoError(t, archives.EnsurePartition(ctx, month))
+
+	assert.Empty(t, hashes("audit_log_default"))
+	assert.Equal(t, before, hashes(name))
+
+	months, err = archives.DefaultPartitionMonths(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, months, month)
+
+	report, err := auditchain.Verify(ctx, audit, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Valid, "%+v", report.Broken)
+}
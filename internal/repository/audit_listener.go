@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/logger"
+)
+
+// auditNotifyChannel - канал NOTIFY триггера trg_audit_notify
+const auditNotifyChannel = "audit_log_changes"
+
+const (
+	auditListenerMinReconnect = time.Second
+	auditListenerMaxReconnect = time.Minute
+	// auditListenerPing - проверка соединения, если уведомлений долго нет
+	auditListenerPing = time.Minute
+)
+
+// AuditListener слушает уведомления о новых записях аудита (LISTEN audit_log_changes)
+// на отдельном соединении, вне пула dbpg
+type AuditListener struct {
+	dsn string
+	log logger.Logger
+}
+
+func NewAuditListener(dsn string, log logger.Logger) *AuditListener {
+	return &AuditListener{
+		dsn: dsn,
+		log: log.With("component", "AuditListener"),
+	}
+}
+
+// Listen сигналит в notify о каждом уведомлении и о каждом переподключении:
+// уведомления, пришедшие во время разрыва, потеряны, и подписчику нужно дочитать журнал.
+// Сигналы не копятся - если предыдущий ещё не забран, новый отбрасывается.
+// Блокируется до отмены ctx.
+func (l *AuditListener) Listen(ctx context.Context, notify chan<- struct{}) error {
+	listener := pq.NewListener(l.dsn, auditListenerMinReconnect, auditListenerMaxReconnect,
+		func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				l.log.Ctx(ctx).Warn("audit listener connection event",
+					"event", int(ev),
+					"error", err,
+				)
+			}
+		})
+	defer listener.Close()
+
+	if err := listener.Listen(auditNotifyChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", auditNotifyChannel, err)
+	}
+
+	ping := time.NewTicker(auditListenerPing)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-listener.Notify:
+			// nil приходит после переподключения - сигналим так же
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		case <-ping.C:
+			_ = listener.Ping()
+		}
+	}
+}
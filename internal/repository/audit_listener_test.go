@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestAuditListener_NotifiesOnCommit(t *testing.T) {
+	db := openTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	strategy := retry.Strategy{Attempts: 1}
+
+	recorder, err := NewAuditRecorder(domain.AuditModeTrigger)
+	require.NoError(t, err)
+	items := NewItemRepository(db, strategy, recorder, -1)
+	audit := NewAuditRepository(db, strategy)
+
+	notify := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() { done <- NewAuditListener(os.Getenv("TEST_DATABASE_DSN"), newTestLogger()).Listen(ctx, notify) }()
+
+	head, err := audit.LastID(ctx)
+	require.NoError(t, err)
+
+	// LISTEN выполняется асинхронно - изменения повторяются, пока не придёт уведомление
+	var created *domain.Item
+	require.Eventually(t, func() bool {
+		created, err = items.Create(ctx, uuid.New(), &domain.CreateItemInput{
+			Name:     "Шуруп",
+			SKU:      "EVT-" + uuid.NewString()[:8],
+			Quantity: 1,
+			Price:    decimal.RequireFromString("1"),
+		})
+		require.NoError(t, err)
+		select {
+		case <-notify:
+			return true
+		case <-time.After(200 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entries, err := audit.ListAfter(ctx, head, 1000)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	last := entries[len(entries)-1]
+	assert.Equal(t, created.ID, last.EntityID)
+	for i := 1; i < len(entries); i++ {
+		assert.Greater(t, entries[i].ID, entries[i-1].ID)
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stpnv0/WarehouseControl/internal/auditdiff"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// auditSecretFields - поля, значения которых не попадают в журнал (аргументы триггера fn_audit_row())
+var auditSecretFields = map[domain.AuditEntity][]string{
+	domain.AuditEntityUser: {"password_hash"},
+}
+
+// AuditRecorder пишет журнал аудита для изменений, сделанных через репозитории.
+// Выбирается конфигурацией (audit.mode), оба режима дают одинаковые old_data/new_data/diff.
+type AuditRecorder interface {
+	// Mode уходит в app.audit_mode транзакции: в режиме app триггер fn_audit_row() ничего не пишет
+	Mode() domain.AuditMode
+	// Record вызывается после каждого изменения в той же транзакции
+	Record(ctx context.Context, tx *sql.Tx, c *domain.AuditChange) error
+}
+
+func NewAuditRecorder(mode domain.AuditMode) (AuditRecorder, error) {
+	switch mode {
+	case domain.AuditModeTrigger:
+		return triggerAuditRecorder{}, nil
+	case domain.AuditModeApp:
+		return appAuditRecorder{}, nil
+	}
+	return nil, fmt.Errorf("unknown audit mode: %q", mode)
+}
+
+// triggerAuditRecorder - журнал пишет триггер fn_audit_row(), приложению делать нечего
+type triggerAuditRecorder struct{}
+
+func (triggerAuditRecorder) Mode() domain.AuditMode { return domain.AuditModeTrigger }
+
+func (triggerAuditRecorder) Record(context.Context, *sql.Tx, *domain.AuditChange) error { return nil }
+
+// appAuditRecorder считает diff в Go и пишет audit_log сам.
+// Снимки строк берутся через to_jsonb(), поэтому совпадают с тем, что видит триггер.
+type appAuditRecorder struct{}
+
+func (appAuditRecorder) Mode() domain.AuditMode { return domain.AuditModeApp }
+
+func (appAuditRecorder) Record(ctx context.Context, tx *sql.Tx, c *domain.AuditChange) error {
+	secret := auditSecretFields[c.EntityType]
+
+	var (
+		diff json.RawMessage
+		err  error
+	)
+	if c.Action == domain.AuditUpdate {
+		if diff, err = auditdiff.Compute(c.OldData, c.NewData, secret...); err != nil {
+			return fmt.Errorf("audit diff: %w", err)
+		}
+		// как и триггер: UPDATE без изменений в журнал не попадает
+		if diff == nil {
+			return nil
+		}
+	}
+
+	oldData, err := auditdiff.Redact(c.OldData, secret...)
+	if err != nil {
+		return err
+	}
+	newData, err := auditdiff.Redact(c.NewData, secret...)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO audit_log (entity_type, entity_id, action, changed_by, old_data, new_data, diff,
+			                       reason, reference, request_id, client_ip, user_agent)
+			  VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb, $7::jsonb,
+			          NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''))`
+
+	_, err = tx.ExecContext(ctx, query,
+		string(c.EntityType), c.EntityID, string(c.Action), c.ChangedBy,
+		jsonArg(oldData), jsonArg(newData), jsonArg(diff),
+		c.Reason.Reason, c.Reason.Reference,
+		c.Request.RequestID, c.Request.ClientIP, c.Request.UserAgent,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit: %w", err)
+	}
+	return nil
+}
+
+// jsonArg - JSONB-параметр запроса: строкой (lib/pq отправил бы []byte как bytea), пустой - NULL
+func jsonArg(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// Сверка режимов аудита идёт на живой PostgreSQL:
+//
+//	TEST_DATABASE_DSN="host=localhost port=5432 user=postgres password=postgres dbname=warehouse_test sslmode=disable" \
+//	go test ./internal/repository/ -run Parity
+func openTestDB(t *testing.T) *dbpg.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	migrationDB, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	defer migrationDB.Close()
+	require.NoError(t, goose.Up(migrationDB, "../../migrations"))
+
+	db, err := dbpg.New(dsn, nil, &dbpg.Options{MaxOpenConns: 2, MaxIdleConns: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Master.Close() })
+
+	return db
+}
+
+// parityRecorder пишет журнал из приложения, не выключая триггер (app.audit_mode не 'app'):
+// каждое изменение попадает в audit_log дважды - сначала от триггера, затем от приложения
+type parityRecorder struct {
+	appAuditRecorder
+}
+
+func (parityRecorder) Mode() domain.AuditMode { return domain.AuditMode("parity") }
+
+type auditRow struct {
+	Action  string
+	OldData sql.NullString
+	NewData sql.NullString
+	Diff    sql.NullString
+	Reason  sql.NullString
+	Ref     sql.NullString
+	ReqID   sql.NullString
+	IP      sql.NullString
+	UA      sql.NullString
+}
+
+func TestAuditRecorder_Parity(t *testing.T) {
+	db := openTestDB(t)
+	ctx := domain.WithAuditRequest(context.Background(), domain.AuditRequest{
+		RequestID: "parity-" + uuid.NewString(),
+		ClientIP:  "10.0.0.7",
+		UserAgent: "parity-test/1.0",
+	})
+	repo := NewItemRepository(db, retry.Strategy{Attempts: 1}, parityRecorder{}, -1)
+	userID := uuid.New()
+
+	strPtr := func(s string) *string { return &s }
+	price := decimal.RequireFromString("12.50")
+
+	item, err := repo.Create(ctx, userID, &domain.CreateItemInput{
+		Name:     "Болт М6",
+		SKU:      "PARITY-" + uuid.NewString()[:8],
+		Quantity: 10,
+		Price:    decimal.RequireFromString("12.5"),
+	})
+	require.NoError(t, err)
+
+	// та же цена в другой записи - изменений нет, обе стороны ничего не пишут
+	_, err = repo.Update(ctx, userID, item.ID, &domain.UpdateItemInput{Price: &price})
+	require.NoError(t, err)
+
+	_, err = repo.Update(ctx, userID, item.ID, &domain.UpdateItemInput{
+		Name:     strPtr("Болт \"М6\"\tоцинк."),
+		Location: strPtr("Склад 1 / A-3"),
+	})
+	require.NoError(t, err)
+
+	reasonCtx := domain.WithAuditReason(ctx, domain.AuditReason{Reason: "брак при приёмке", Reference: "INV-42"})
+	_, err = repo.AdjustQuantity(reasonCtx, userID, item.ID, -3)
+	require.NoError(t, err)
+
+	_, err = repo.Batch(ctx, userID, []*domain.BatchOperation{
+		{Op: domain.BatchUpdate, ID: &item.ID, Update: &domain.UpdateItemInput{Location: strPtr("")}},
+	}, domain.BatchAtomic)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(ctx, userID, item.ID))
+
+	rows, err := db.Master.QueryContext(ctx, `
+		SELECT action, old_data::text, new_data::text, diff::text, reason, reference,
+		       request_id, client_ip, user_agent
+		FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY id`, domain.AuditEntityItem, item.ID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var got []auditRow
+	for rows.Next() {
+		var r auditRow
+		require.NoError(t, rows.Scan(&r.Action, &r.OldData, &r.NewData, &r.Diff, &r.Reason, &r.Ref, &r.ReqID, &r.IP, &r.UA))
+		got = append(got, r)
+	}
+	require.NoError(t, rows.Err())
+
+	// INSERT, два UPDATE, корректировка, UPDATE из пачки, DELETE - по паре записей на каждое
+	require.Len(t, got, 12)
+	assert.Equal(t, "INV-42", got[4].Ref.String)
+	assert.Equal(t, "10.0.0.7", got[0].IP.String)
+	for i := 0; i < len(got); i += 2 {
+		trigger, app := got[i], got[i+1]
+		assert.Equal(t, trigger, app, "entry %d: trigger and app audit differ", i/2)
+	}
+}
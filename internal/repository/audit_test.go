@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestAuditRepository_ChangeFilters(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	recorder, err := NewAuditRecorder(domain.AuditModeTrigger)
+	require.NoError(t, err)
+
+	items := NewItemRepository(db, strategy, recorder, -1)
+	audit := NewAuditRepository(db, strategy)
+	userID := uuid.New()
+
+	item, err := items.Create(ctx, userID, &domain.CreateItemInput{
+		Name:     "Кабель",
+		SKU:      "DIFF-" + uuid.NewString()[:8],
+		Quantity: 50,
+		Price:    decimal.RequireFromString("100"),
+	})
+	require.NoError(t, err)
+
+	_, err = items.AdjustQuantity(ctx, userID, item.ID, -15)
+	require.NoError(t, err)
+	_, err = items.AdjustQuantity(ctx, userID, item.ID, -2)
+	require.NoError(t, err)
+	price := decimal.RequireFromString("120.5")
+	name := "Кабель ВВГ"
+	_, err = items.Update(ctx, userID, item.ID, &domain.UpdateItemInput{Name: &name, Price: &price})
+	require.NoError(t, err)
+
+	list := func(f *domain.AuditFilter) []*domain.AuditEntryWithUser {
+		f.ItemID = &item.ID
+		entries, _, err := audit.List(ctx, f, 100, 0)
+		require.NoError(t, err)
+		return entries
+	}
+	strPtr := func(s string) *string { return &s }
+
+	assert.Len(t, list(&domain.AuditFilter{Field: strPtr("quantity")}), 2)
+	assert.Len(t, list(&domain.AuditFilter{Field: strPtr("price")}), 1)
+
+	dropped := list(&domain.AuditFilter{Changes: []domain.AuditValuePredicate{
+		{Field: "quantity", Side: domain.AuditValueDelta, Op: domain.AuditOpLt, Value: "-10"},
+	}})
+	require.Len(t, dropped, 1)
+	assert.JSONEq(t, `{"quantity": {"old": 50, "new": 35}}`, string(dropped[0].Diff))
+
+	assert.Len(t, list(&domain.AuditFilter{Changes: []domain.AuditValuePredicate{
+		{Field: "price", Side: domain.AuditValueNew, Op: domain.AuditOpGte, Value: "120.50"},
+	}}), 1)
+
+	// нечисловое значение поля при числовом сравнении не подходит и не роняет запрос
+	assert.Empty(t, list(&domain.AuditFilter{Changes: []domain.AuditValuePredicate{
+		{Field: "name", Side: domain.AuditValueNew, Op: domain.AuditOpGt, Value: "0"},
+	}}))
+}
+
+func TestAuditRepository_Stats(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	recorder, err := NewAuditRecorder(domain.AuditModeTrigger)
+	require.NoError(t, err)
+
+	items := NewItemRepository(db, strategy, recorder, -1)
+	audit := NewAuditRepository(db, strategy)
+	userID := uuid.New()
+	from := time.Now().Add(-time.Minute)
+
+	kept, err := items.Create(ctx, userID, &domain.CreateItemInput{
+		Name:     "Саморез",
+		SKU:      "STAT-" + uuid.NewString()[:8],
+		Quantity: 100,
+		Price:    decimal.RequireFromString("2"),
+	})
+	require.NoError(t, err)
+	_, err = items.AdjustQuantity(ctx, userID, kept.ID, -30)
+	require.NoError(t, err)
+
+	deleted, err := items.Create(ctx, userID, &domain.CreateItemInput{
+		Name:     "Дюбель",
+		SKU:      "STAT-" + uuid.NewString()[:8],
+		Quantity: 5,
+		Price:    decimal.RequireFromString("1"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, items.Delete(ctx, userID, deleted.ID))
+
+	stats, err := audit.Stats(ctx, &domain.AuditStatsFilter{
+		DateFrom: from,
+		DateTo:   time.Now().Add(time.Minute),
+		Limit:    domain.AuditStatsMaxLimit,
+	})
+	require.NoError(t, err)
+
+	var user *domain.AuditUserStat
+	for _, u := range stats.TopUsers {
+		if u.UserID == userID {
+			user = u
+		}
+	}
+	require.NotNil(t, user)
+	assert.Equal(t, int64(4), user.Changes)
+
+	net := map[uuid.UUID]*domain.AuditQuantityStat{}
+	for _, q := range stats.QuantityChanges {
+		net[q.ItemID] = q
+	}
+	require.Contains(t, net, kept.ID)
+	assert.Equal(t, int64(70), net[kept.ID].Net)
+	// создание и удаление дюбеля в сумме дают ноль - в выборку он не попадает
+	assert.NotContains(t, net, deleted.ID)
+
+	var names []string
+	for _, it := range stats.TopItems {
+		if it.ItemID == deleted.ID {
+			names = append(names, it.Name)
+		}
+	}
+	assert.Equal(t, []string{"Дюбель"}, names)
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const exportJobColumns = `id, type, format, filter, columns, status, total_rows, processed,
+	COALESCE(file_name, ''), file_size, COALESCE(error, ''), created_by,
+	created_at, started_at, finished_at, expires_at`
+
+type ExportJobRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewExportJobRepository(db *dbpg.DB, strategy retry.Strategy) *ExportJobRepository {
+	return &ExportJobRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+func (r *ExportJobRepository) Create(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	const op = "ExportJobRepository.Create"
+
+	filter, err := marshalExportFilter(job)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO export_jobs (type, format, filter, columns, created_by)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING ` + exportJobColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query,
+		job.Type, job.Format, filter, pq.Array(job.Columns), job.CreatedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := scanExportJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("%s - scan job: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *ExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	const op = "ExportJobRepository.GetByID"
+
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE id=$1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	job, err := scanExportJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan job: %w", op, err)
+	}
+	return job, nil
+}
+
+// ClaimNext забирает самую старую задачу из очереди и переводит её в running.
+// SKIP LOCKED позволяет нескольким воркерам разбирать очередь без блокировок друг друга.
+// Пустая очередь - domain.ErrNotFound.
+func (r *ExportJobRepository) ClaimNext(ctx context.Context) (*domain.ExportJob, error) {
+	const op = "ExportJobRepository.ClaimNext"
+
+	query := `UPDATE export_jobs
+			  SET status = 'running', started_at = now()
+			  WHERE id = (
+			      SELECT id FROM export_jobs
+			      WHERE status = 'pending'
+			      ORDER BY created_at
+			      FOR UPDATE SKIP LOCKED
+			      LIMIT 1
+			  )
+			  RETURNING ` + exportJobColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	job, err := scanExportJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%s - scan job: %w", op, err)
+	}
+	return job, nil
+}
+
+// UpdateProgress - сколько строк записано и сколько ожидается всего
+func (r *ExportJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, processed, total int64) error {
+	const op = "ExportJobRepository.UpdateProgress"
+
+	query := `UPDATE export_jobs SET processed = $1, total_rows = $2 WHERE id = $3`
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, processed, total, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ExportJobRepository) Complete(
+	ctx context.Context,
+	id uuid.UUID,
+	fileName string,
+	fileSize, processed int64,
+	expiresAt time.Time,
+) error {
+	const op = "ExportJobRepository.Complete"
+
+	query := `UPDATE export_jobs
+			  SET status = 'done', file_name = $1, file_size = $2, processed = $3,
+			      finished_at = now(), expires_at = $4
+			  WHERE id = $5`
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, fileName, fileSize, processed, expiresAt, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ExportJobRepository) Fail(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error {
+	const op = "ExportJobRepository.Fail"
+
+	query := `UPDATE export_jobs
+			  SET status = 'failed', error = $1, finished_at = now(), expires_at = $2
+			  WHERE id = $3`
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, reason, expiresAt, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// RequeueRunning возвращает в очередь задачи, оборванные остановкой приложения
+func (r *ExportJobRepository) RequeueRunning(ctx context.Context) (int64, error) {
+	const op = "ExportJobRepository.RequeueRunning"
+
+	query := `UPDATE export_jobs
+			  SET status = 'pending', started_at = NULL, processed = 0
+			  WHERE status = 'running'`
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// DeleteExpired удаляет задачи с истёкшим сроком хранения и возвращает их (для удаления файлов)
+func (r *ExportJobRepository) DeleteExpired(ctx context.Context, now time.Time) ([]*domain.ExportJob, error) {
+	const op = "ExportJobRepository.DeleteExpired"
+
+	query := `DELETE FROM export_jobs WHERE expires_at <= $1 RETURNING ` + exportJobColumns
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.ExportJob
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - scan job: %w", op, err)
+		}
+		res = append(res, job)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExportJob(row rowScanner) (*domain.ExportJob, error) {
+	var (
+		job     domain.ExportJob
+		filter  []byte
+		columns []string
+	)
+	if err := row.Scan(
+		&job.ID, &job.Type, &job.Format, &filter, pq.Array(&columns), &job.Status,
+		&job.TotalRows, &job.Processed, &job.FileName, &job.FileSize, &job.Error,
+		&job.CreatedBy, &job.CreatedAt, &job.StartedAt, &job.FinishedAt, &job.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	job.Columns = columns
+
+	if err := unmarshalExportFilter(&job, filter); err != nil {
+		return nil, fmt.Errorf("decode filter: %w", err)
+	}
+	return &job, nil
+}
+
+func marshalExportFilter(job *domain.ExportJob) ([]byte, error) {
+	var filter any = struct{}{}
+	switch job.Type {
+	case domain.ExportJobAudit:
+		if job.AuditFilter != nil {
+			filter = job.AuditFilter
+		}
+	case domain.ExportJobItems:
+		if job.ItemFilter != nil {
+			filter = job.ItemFilter
+		}
+	}
+	return json.Marshal(filter)
+}
+
+func unmarshalExportFilter(job *domain.ExportJob, raw []byte) error {
+	switch job.Type {
+	case domain.ExportJobAudit:
+		job.AuditFilter = &domain.AuditFilter{}
+		return json.Unmarshal(raw, job.AuditFilter)
+	case domain.ExportJobItems:
+		job.ItemFilter = &domain.ItemFilter{}
+		return json.Unmarshal(raw, job.ItemFilter)
+	}
+	return nil
+}
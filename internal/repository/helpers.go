@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/wb-go/wbf/dbpg"
 )
 
@@ -19,15 +21,113 @@ func isDuplicateKey(err error) bool {
 	return false
 }
 
-// withAuditContext выполняет fn внутри транзакции с установленным app.current_user_id (необходимо для триггера аудита)
-func withAuditContext(ctx context.Context, db *dbpg.DB, userID uuid.UUID, fn func(tx *sql.Tx) error) error {
+// isOutOfRange - значение не помещается в тип колонки (numeric_value_out_of_range), например остаток больше int4
+func isOutOfRange(err error) bool {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "22003"
+	}
+	return false
+}
+
+func isCheckViolation(err error, constraint string) bool {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23514" && pgErr.Constraint == constraint
+	}
+	return false
+}
+
+// auditTx - транзакция с контекстом аудита: кто и зачем меняет данные и кто пишет журнал
+type auditTx struct {
+	*sql.Tx
+	userID   uuid.UUID
+	reason   domain.AuditReason
+	request  domain.AuditRequest
+	recorder AuditRecorder
+}
+
+// withAuditContext выполняет fn внутри транзакции с установленными app.current_user_id
+// и app.audit_mode (необходимы триггеру аудита). Причина изменения из ctx
+// (domain.WithAuditReason) уходит в app.audit_reason и app.audit_reference,
+// данные HTTP-запроса (domain.WithAuditRequest) - в app.request_id, app.client_ip и app.user_agent.
+//
+// Первым делом транзакция блокирует голову цепочки аудита (audit_chain_head). Триггер цепочки
+// всё равно берёт эту блокировку на первой записи журнала и держит до фиксации, но к тому
+// моменту транзакция уже держит блокировки строк товаров и пользователей - пачка или импорт
+// рядом с одиночным изменением взаимно блокировались (40P01). Голова раньше строк - один
+// порядок блокировок во всех транзакциях с аудитом.
+func withAuditContext(
+	ctx context.Context,
+	db *dbpg.DB,
+	recorder AuditRecorder,
+	userID uuid.UUID,
+	fn func(tx *auditTx) error,
+) error {
 	return db.WithTx(ctx, func(tx *sql.Tx) error {
-		queryAudit := `SELECT set_config('app.current_user_id', $1, true)`
-		_, err := tx.ExecContext(ctx, queryAudit, userID.String())
+		reason := domain.AuditReasonFromContext(ctx)
+		request := domain.AuditRequestFromContext(ctx)
+
+		queryAudit := `SELECT set_config('app.current_user_id', $1, true),
+							  set_config('app.audit_mode', $2, true),
+							  set_config('app.audit_reason', $3, true),
+							  set_config('app.audit_reference', $4, true),
+							  set_config('app.request_id', $5, true),
+							  set_config('app.client_ip', $6, true),
+							  set_config('app.user_agent', $7, true)`
+		_, err := tx.ExecContext(ctx, queryAudit,
+			userID.String(), string(recorder.Mode()), reason.Reason, reason.Reference,
+			request.RequestID, request.ClientIP, request.UserAgent,
+		)
 		if err != nil {
 			return fmt.Errorf("set audit context: %w", err)
 		}
 
-		return fn(tx)
+		if _, err = tx.ExecContext(ctx, `SELECT 1 FROM audit_chain_head FOR UPDATE`); err != nil {
+			return fmt.Errorf("lock audit chain head: %w", err)
+		}
+
+		return fn(&auditTx{Tx: tx, userID: userID, reason: reason, request: request, recorder: recorder})
+	})
+}
+
+// snapshot блокирует строку table и возвращает её to_jsonb - состояние до изменения.
+// Нужен только при записи аудита приложением; в режиме trigger OLD видит сам триггер.
+// table - имя таблицы из кода, не из запроса.
+func (t *auditTx) snapshot(ctx context.Context, table string, id uuid.UUID) (json.RawMessage, error) {
+	if t.recorder.Mode() == domain.AuditModeTrigger {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`SELECT to_jsonb(t) FROM %s t WHERE id=$1 FOR UPDATE`, table)
+
+	var data []byte
+	err := t.QueryRowContext(ctx, query, id).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// record передаёт изменение в журнал аудита
+func (t *auditTx) record(
+	ctx context.Context,
+	entity domain.AuditEntity,
+	action domain.AuditAction,
+	entityID uuid.UUID,
+	oldData, newData json.RawMessage,
+) error {
+	return t.recorder.Record(ctx, t.Tx, &domain.AuditChange{
+		EntityType: entity,
+		EntityID:   entityID,
+		Action:     action,
+		ChangedBy:  t.userID,
+		OldData:    oldData,
+		NewData:    newData,
+		Reason:     t.reason,
+		Request:    t.request,
 	})
 }
@@ -8,20 +8,37 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/retry"
 )
 
+// Имена CHECK-ограничений из init_schema
+const (
+	quantityCheckConstraint = "items_quantity_check"
+	priceCheckConstraint    = "items_price_check"
+)
+
+var (
+	errNegativePrice      = &domain.ValidationError{Field: "price", Reason: "must be greater than or equal to 0"}
+	errQuantityOutOfRange = &domain.ValidationError{Field: "delta", Reason: "resulting quantity is out of range"}
+)
+
 type ItemRepository struct {
 	db       *dbpg.DB
 	strategy retry.Strategy
+	recorder AuditRecorder
+	// lowStockThreshold - остаток, при падении до которого в outbox пишется item.low_stock; < 0 - не писать
+	lowStockThreshold int
 }
 
-func NewItemRepository(db *dbpg.DB, strategy retry.Strategy) *ItemRepository {
+func NewItemRepository(db *dbpg.DB, strategy retry.Strategy, recorder AuditRecorder, lowStockThreshold int) *ItemRepository {
 	return &ItemRepository{
-		db:       db,
-		strategy: strategy,
+		db:                db,
+		strategy:          strategy,
+		recorder:          recorder,
+		lowStockThreshold: lowStockThreshold,
 	}
 }
 
@@ -32,30 +49,18 @@ func (r *ItemRepository) Create(
 ) (*domain.Item, error) {
 	const op = "ItemRepository.Create"
 
-	query := `INSERT INTO items (name, sku, quantity, price, location)
-			  VALUES ($1, $2, $3, $4, $5)
-			  RETURNING id, name, sku, quantity, price, location, created_at, updated_at`
-
-	var i domain.Item
-	err := withAuditContext(ctx, r.db, userID, func(tx *sql.Tx) error {
-		return tx.QueryRowContext(
-			ctx, query, input.Name, input.SKU, input.Quantity,
-			input.Price.StringFixed(2), input.Location,
-		).Scan(
-			&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
-			&i.Location, &i.CreatedAt, &i.UpdatedAt,
-		)
+	var item *domain.Item
+	err := withAuditContext(ctx, r.db, r.recorder, userID, func(tx *auditTx) error {
+		var err error
+		item, err = insertItem(ctx, tx, input)
+		return err
 	})
 
 	if err != nil {
-		if isDuplicateKey(err) {
-			return nil, fmt.Errorf("%s: %w", op, domain.ErrDuplicateSKU)
-		}
-
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &i, nil
+	return item, nil
 }
 
 func (r *ItemRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Item, error) {
@@ -84,6 +89,42 @@ func (r *ItemRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Ite
 	return &i, nil
 }
 
+// GetBySKUs возвращает существующие товары с указанными SKU
+func (r *ItemRepository) GetBySKUs(ctx context.Context, skus []string) ([]*domain.Item, error) {
+	const op = "ItemRepository.GetBySKUs"
+
+	if len(skus) == 0 {
+		return []*domain.Item{}, nil
+	}
+
+	query := `SELECT id, name, sku, quantity, price, location, created_at, updated_at
+			  FROM items
+			  WHERE sku = ANY($1)`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, pq.Array(skus))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	res := make([]*domain.Item, 0, len(skus))
+	for rows.Next() {
+		var i domain.Item
+		if err = rows.Scan(
+			&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
+			&i.Location, &i.CreatedAt, &i.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s - scan item: %w", op, err)
+		}
+		res = append(res, &i)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
 func (r *ItemRepository) List(
 	ctx context.Context,
 	filter *domain.ItemFilter,
@@ -91,19 +132,8 @@ func (r *ItemRepository) List(
 ) ([]*domain.Item, int64, error) {
 	const op = "ItemRepository.List"
 
-	var (
-		conditions []string
-		args       []interface{}
-		argIdx     = 1
-	)
-	if filter.Search != nil && *filter.Search != "" {
-		search := "%" + *filter.Search + "%"
-		conditions = append(conditions,
-			fmt.Sprintf("(name ILIKE $%d OR sku ILIKE $%d)", argIdx, argIdx),
-		)
-		args = append(args, search)
-		argIdx++
-	}
+	conditions, args := itemFilterConditions(filter)
+	argIdx := len(args) + 1
 
 	where := ""
 	if len(conditions) > 0 {
@@ -151,6 +181,78 @@ func (r *ItemRepository) List(
 	return res, totalCount, nil
 }
 
+// ListAfter - keyset-пагинация для выгрузки: следующие limit товаров после курсора
+// в порядке GET /api/items (created_at DESC, id DESC). Без курсора - с начала.
+func (r *ItemRepository) ListAfter(
+	ctx context.Context,
+	filter *domain.ItemFilter,
+	after *domain.ItemCursor,
+	limit int,
+) ([]*domain.Item, error) {
+	const op = "ItemRepository.ListAfter"
+
+	conditions, args := itemFilterConditions(filter)
+	argIdx := len(args) + 1
+
+	if after != nil {
+		conditions = append(conditions,
+			fmt.Sprintf("(created_at, id) < ($%d, $%d)", argIdx, argIdx+1),
+		)
+		args = append(args, after.CreatedAt, after.ID)
+		argIdx += 2
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, name, sku, quantity, price, location, created_at, updated_at
+		FROM items %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d
+	`, where, argIdx)
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	res := make([]*domain.Item, 0, limit)
+	for rows.Next() {
+		var i domain.Item
+		if err = rows.Scan(
+			&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
+			&i.Location, &i.CreatedAt, &i.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s - scan item: %w", op, err)
+		}
+		res = append(res, &i)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// itemFilterConditions - общие условия WHERE для List и ListAfter, плейсхолдеры с $1
+func itemFilterConditions(filter *domain.ItemFilter) ([]string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.Search != nil && *filter.Search != "" {
+		args = append(args, "%"+*filter.Search+"%")
+		conditions = append(conditions,
+			fmt.Sprintf("(name ILIKE $%d OR sku ILIKE $%d)", len(args), len(args)),
+		)
+	}
+	return conditions, args
+}
+
 func (r *ItemRepository) Update(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -159,6 +261,205 @@ func (r *ItemRepository) Update(
 ) (*domain.Item, error) {
 	const op = "ItemRepository.Update"
 
+	var item *domain.Item
+	err := withAuditContext(ctx, r.db, r.recorder, userID, func(tx *auditTx) error {
+		var err error
+		item, err = updateItem(ctx, tx, id, input, r.lowStockThreshold)
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
+// AdjustQuantity атомарно изменяет остаток на delta (quantity = quantity + delta),
+// поэтому параллельные корректировки не затирают друг друга
+func (r *ItemRepository) AdjustQuantity(
+	ctx context.Context,
+	userID uuid.UUID,
+	id uuid.UUID,
+	delta int,
+) (*domain.Item, error) {
+	const op = "ItemRepository.AdjustQuantity"
+
+	query := `UPDATE items
+			  SET quantity = quantity + $1
+			  WHERE id=$2
+			  RETURNING id, name, sku, quantity, price, location, created_at, updated_at, to_jsonb(items)`
+
+	var i domain.Item
+	err := withAuditContext(ctx, r.db, r.recorder, userID, func(tx *auditTx) error {
+		oldData, err := tx.snapshot(ctx, "items", id)
+		if err != nil {
+			return err
+		}
+
+		var newData []byte
+		if err = tx.QueryRowContext(ctx, query, delta, id).Scan(
+			&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
+			&i.Location, &i.CreatedAt, &i.UpdatedAt, &newData,
+		); err != nil {
+			return err
+		}
+
+		if err = tx.record(ctx, domain.AuditEntityItem, domain.AuditUpdate, i.ID, oldData, newData); err != nil {
+			return err
+		}
+		if err = tx.enqueueItemEvent(ctx, domain.OutboxItemUpdated, i.ID, newData); err != nil {
+			return err
+		}
+		return tx.enqueueLowStock(ctx, i.ID, newData, i.Quantity-delta, i.Quantity, r.lowStockThreshold)
+	})
+
+	if err != nil {
+		if isCheckViolation(err, quantityCheckConstraint) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrInsufficientStock)
+		}
+		if isOutOfRange(err) {
+			return nil, fmt.Errorf("%s: %w", op, errQuantityOutOfRange)
+		}
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &i, nil
+}
+
+func (r *ItemRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	const op = "ItemRepository.Delete"
+
+	err := withAuditContext(ctx, r.db, r.recorder, userID, func(tx *auditTx) error {
+		return deleteItem(ctx, tx, id)
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Batch применяет набор операций в одной транзакции.
+// В атомарном режиме первая ошибка откатывает всю пачку и возвращается как *domain.BatchOpError.
+// В режиме best effort каждая операция изолирована SAVEPOINT'ом: ошибка откатывает только её
+// и попадает в результат строки. results[i] соответствует ops[i].
+func (r *ItemRepository) Batch(
+	ctx context.Context,
+	userID uuid.UUID,
+	ops []*domain.BatchOperation,
+	mode domain.BatchMode,
+) ([]*domain.BatchItemResult, error) {
+	const op = "ItemRepository.Batch"
+
+	var results []*domain.BatchItemResult
+	err := withAuditContext(ctx, r.db, r.recorder, userID, func(tx *auditTx) error {
+		results = make([]*domain.BatchItemResult, 0, len(ops))
+
+		for idx, bop := range ops {
+			if mode == domain.BatchBestEffort {
+				if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_op`); err != nil {
+					return fmt.Errorf("savepoint: %w", err)
+				}
+			}
+
+			item, err := applyBatchOp(ctx, tx, bop, r.lowStockThreshold)
+			if err != nil {
+				if mode != domain.BatchBestEffort {
+					return &domain.BatchOpError{Index: idx, Err: err}
+				}
+				if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT batch_op`); rbErr != nil {
+					return fmt.Errorf("rollback to savepoint: %w", rbErr)
+				}
+				results = append(results, &domain.BatchItemResult{Op: bop.Op, ID: bop.ID, Err: err})
+				continue
+			}
+
+			if mode == domain.BatchBestEffort {
+				if _, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT batch_op`); err != nil {
+					return fmt.Errorf("release savepoint: %w", err)
+				}
+			}
+			results = append(results, &domain.BatchItemResult{Op: bop.Op, ID: bop.ID, Item: item})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+func applyBatchOp(
+	ctx context.Context,
+	tx *auditTx,
+	bop *domain.BatchOperation,
+	lowStockThreshold int,
+) (*domain.Item, error) {
+	switch bop.Op {
+	case domain.BatchCreate:
+		return insertItem(ctx, tx, bop.Create)
+	case domain.BatchUpdate:
+		return updateItem(ctx, tx, *bop.ID, bop.Update, lowStockThreshold)
+	case domain.BatchDelete:
+		return nil, deleteItem(ctx, tx, *bop.ID)
+	}
+	return nil, domain.ErrValidation
+}
+
+// insertItem, updateItem и deleteItem выполняются внутри транзакции с уже установленным
+// контекстом аудита, передают изменение в журнал и outbox и возвращают доменные ошибки без обёртки op.
+func insertItem(ctx context.Context, tx *auditTx, input *domain.CreateItemInput) (*domain.Item, error) {
+	query := `INSERT INTO items (name, sku, quantity, price, location)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id, name, sku, quantity, price, location, created_at, updated_at, to_jsonb(items)`
+
+	var (
+		i       domain.Item
+		newData []byte
+	)
+	err := tx.QueryRowContext(
+		ctx, query, input.Name, input.SKU, input.Quantity,
+		input.Price.StringFixed(2), input.Location,
+	).Scan(
+		&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
+		&i.Location, &i.CreatedAt, &i.UpdatedAt, &newData,
+	)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, domain.ErrDuplicateSKU
+		}
+		if isCheckViolation(err, priceCheckConstraint) {
+			return nil, errNegativePrice
+		}
+		return nil, err
+	}
+
+	if err = tx.record(ctx, domain.AuditEntityItem, domain.AuditInsert, i.ID, nil, newData); err != nil {
+		return nil, err
+	}
+	if err = tx.enqueueItemEvent(ctx, domain.OutboxItemCreated, i.ID, newData); err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+func updateItem(
+	ctx context.Context,
+	tx *auditTx,
+	id uuid.UUID,
+	input *domain.UpdateItemInput,
+	lowStockThreshold int,
+) (*domain.Item, error) {
 	var (
 		setClauses []string
 		args       []interface{}
@@ -192,7 +493,7 @@ func (r *ItemRepository) Update(
 	}
 
 	if len(setClauses) == 0 {
-		return nil, fmt.Errorf("%s: %w", op, domain.ErrNoChanges)
+		return nil, domain.ErrNoChanges
 	}
 
 	args = append(args, id)
@@ -201,56 +502,75 @@ func (r *ItemRepository) Update(
 		UPDATE items
 		SET %s
 		WHERE id=$%d
-		RETURNING id, name, sku, quantity, price, location, created_at, updated_at
+		RETURNING id, name, sku, quantity, price, location, created_at, updated_at, to_jsonb(items)
 		`, strings.Join(setClauses, ", "), argIdx)
 
-	var i domain.Item
-	err := withAuditContext(ctx, r.db, userID, func(tx *sql.Tx) error {
-		return tx.QueryRowContext(ctx, query, args...).Scan(
-			&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
-			&i.Location, &i.CreatedAt, &i.UpdatedAt,
-		)
-	})
+	oldData, err := tx.snapshot(ctx, "items", id)
+	if err != nil {
+		return nil, err
+	}
 
+	// прежний остаток - чтобы заметить падение ниже порога item.low_stock
+	var prevQuantity int
+	if input.Quantity != nil {
+		err = tx.QueryRowContext(ctx, `SELECT quantity FROM items WHERE id=$1 FOR UPDATE`, id).Scan(&prevQuantity)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, domain.ErrNotFound
+			}
+			return nil, err
+		}
+	}
+
+	var (
+		i       domain.Item
+		newData []byte
+	)
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&i.ID, &i.Name, &i.SKU, &i.Quantity, &i.Price,
+		&i.Location, &i.CreatedAt, &i.UpdatedAt, &newData,
+	)
 	if err != nil {
 		if isDuplicateKey(err) {
-			return nil, fmt.Errorf("%s: %w", op, domain.ErrDuplicateSKU)
+			return nil, domain.ErrDuplicateSKU
+		}
+		if isCheckViolation(err, priceCheckConstraint) {
+			return nil, errNegativePrice
 		}
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+			return nil, domain.ErrNotFound
 		}
+		return nil, err
+	}
 
-		return nil, fmt.Errorf("%s: %w", op, err)
+	if err = tx.record(ctx, domain.AuditEntityItem, domain.AuditUpdate, i.ID, oldData, newData); err != nil {
+		return nil, err
+	}
+	if err = tx.enqueueItemEvent(ctx, domain.OutboxItemUpdated, i.ID, newData); err != nil {
+		return nil, err
+	}
+	if input.Quantity != nil {
+		if err = tx.enqueueLowStock(ctx, i.ID, newData, prevQuantity, i.Quantity, lowStockThreshold); err != nil {
+			return nil, err
+		}
 	}
 
 	return &i, nil
 }
 
-func (r *ItemRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
-	const op = "ItemRepository.Delete"
+func deleteItem(ctx context.Context, tx *auditTx, id uuid.UUID) error {
+	query := `DELETE FROM items WHERE id=$1 RETURNING to_jsonb(items)`
 
-	query := `DELETE FROM items WHERE id=$1`
-
-	err := withAuditContext(ctx, r.db, userID, func(tx *sql.Tx) error {
-		res, err := tx.ExecContext(ctx, query, id)
-		if err != nil {
-			return err
-		}
-
-		rows, err := res.RowsAffected()
-		if err != nil {
-			return err
-		}
-		if rows == 0 {
+	var oldData []byte
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&oldData); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return domain.ErrNotFound
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return err
 	}
 
-	return nil
+	if err := tx.record(ctx, domain.AuditEntityItem, domain.AuditDelete, id, oldData, nil); err != nil {
+		return err
+	}
+	return tx.enqueueItemEvent(ctx, domain.OutboxItemDeleted, id, oldData)
 }
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+// Пачка блокирует строки товаров по очереди, одиночное изменение - одну строку; обе пишут журнал.
+// Голова цепочки аудита берётся до строк, поэтому они не блокируют друг друга взаимно (40P01).
+func TestItemRepository_BatchConcurrentWithUpdate(t *testing.T) {
+	db := openTestDB(t)
+
+	for _, recorder := range []AuditRecorder{triggerAuditRecorder{}, appAuditRecorder{}} {
+		t.Run(string(recorder.Mode()), func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewItemRepository(db, retry.Strategy{Attempts: 1}, recorder, -1)
+			userID := uuid.New()
+
+			ids := make([]uuid.UUID, 3)
+			for i := range ids {
+				item, err := repo.Create(ctx, userID, &domain.CreateItemInput{
+					Name:     "Concurrent",
+					SKU:      "CONC-" + uuid.NewString()[:8],
+					Quantity: 1000,
+					Price:    decimal.NewFromInt(1),
+				})
+				require.NoError(t, err)
+				ids[i] = item.ID
+			}
+
+			const rounds = 30
+			var wg sync.WaitGroup
+			errs := make(chan error, 2*rounds)
+
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < rounds; i++ {
+					ops := make([]*domain.BatchOperation, 0, len(ids))
+					for j := range ids {
+						qty := i + j
+						ops = append(ops, &domain.BatchOperation{
+							Op: domain.BatchUpdate, ID: &ids[j], Update: &domain.UpdateItemInput{Quantity: &qty},
+						})
+					}
+					_, err := repo.Batch(ctx, userID, ops, domain.BatchAtomic)
+					errs <- err
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 0; i < rounds; i++ {
+					// строка, которую пачка, возможно, заблокирует позже
+					_, err := repo.AdjustQuantity(ctx, userID, ids[len(ids)-1-i%len(ids)], 1)
+					errs <- err
+				}
+			}()
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const identityUserColumns = `u.id, u.username, u.password_hash, u.role, u.disabled_at,
+	u.password_changed_at, u.created_at, u.updated_at`
+
+const insertIdentityQuery = `INSERT INTO user_identities (issuer, subject, user_id) VALUES ($1, $2, $3)`
+
+// OIDCRepository - привязки пользователей к провайдеру единого входа и начатые через него входы
+type OIDCRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+	recorder AuditRecorder
+}
+
+func NewOIDCRepository(db *dbpg.DB, strategy retry.Strategy, recorder AuditRecorder) *OIDCRepository {
+	return &OIDCRepository{
+		db:       db,
+		strategy: strategy,
+		recorder: recorder,
+	}
+}
+
+// CreateLoginState сохраняет начатый вход; заодно удаляются истёкшие, от которых не вернулись
+func (r *OIDCRepository) CreateLoginState(ctx context.Context, st *domain.OIDCLoginState) error {
+	const op = "OIDCRepository.CreateLoginState"
+
+	query := `WITH expired AS (
+				  DELETE FROM oidc_login_states WHERE expires_at < now()
+			  )
+			  INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at)
+			  VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.ExecWithRetry(ctx, r.strategy, query, st.StateHash, st.Nonce, st.CodeVerifier, st.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ConsumeLoginState возвращает и удаляет начатый вход: с одним state можно вернуться только раз.
+// Срок действия проверяет вызывающий.
+func (r *OIDCRepository) ConsumeLoginState(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	const op = "OIDCRepository.ConsumeLoginState"
+
+	query := `DELETE FROM oidc_login_states
+			  WHERE state_hash = $1
+			  RETURNING state_hash, nonce, code_verifier, expires_at`
+
+	// без повторов: удаление уже выполнено, повтор вернул бы ErrNotFound
+	row, err := r.db.QueryRowWithRetry(ctx, retry.Strategy{Attempts: 1}, query, stateHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var st domain.OIDCLoginState
+	if err = row.Scan(&st.StateHash, &st.Nonce, &st.CodeVerifier, &st.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan login state: %w", op, err)
+	}
+	return &st, nil
+}
+
+// RecordIdentityLogin отмечает вход по привязке (issuer, subject) и возвращает её пользователя
+func (r *OIDCRepository) RecordIdentityLogin(ctx context.Context, issuer, subject string) (*domain.User, error) {
+	const op = "OIDCRepository.RecordIdentityLogin"
+
+	query := `UPDATE user_identities i
+			  SET last_login_at = now()
+			  FROM users u
+			  WHERE u.id = i.user_id AND i.issuer = $1 AND i.subject = $2
+			  RETURNING ` + identityUserColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, issuer, subject)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	u, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan user: %w", op, err)
+	}
+	return u, nil
+}
+
+// CreateUserWithIdentity создаёт пользователя, впервые вошедшего через провайдера, вместе с привязкой.
+// Пароля у него нет. В журнал аудита создание пишется от имени самого пользователя - его id
+// выбирается заранее. Занятый логин или уже существующая привязка - domain.ErrAlreadyExists.
+func (r *OIDCRepository) CreateUserWithIdentity(
+	ctx context.Context,
+	user *domain.User,
+	identity *domain.OIDCIdentity,
+) (*domain.User, error) {
+	const op = "OIDCRepository.CreateUserWithIdentity"
+
+	id := uuid.New()
+	query := `INSERT INTO users (id, username, password_hash, role)
+			  VALUES ($1, $2, '', $3)
+			  RETURNING ` + userColumns + `, to_jsonb(users)`
+
+	var created *domain.User
+	err := withAuditContext(ctx, r.db, r.recorder, id, func(tx *auditTx) error {
+		var (
+			newData []byte
+			err     error
+		)
+		created, err = scanUser(tx.QueryRowContext(ctx, query, id, user.Username, user.Role), &newData)
+		if err != nil {
+			return err
+		}
+		if err = insertIdentity(ctx, tx.Tx, id, identity); err != nil {
+			return err
+		}
+		return tx.record(ctx, domain.AuditEntityUser, domain.AuditInsert, id, nil, newData)
+	})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+// LinkIdentity привязывает учётную запись у провайдера к существующему пользователю
+func (r *OIDCRepository) LinkIdentity(ctx context.Context, userID uuid.UUID, identity *domain.OIDCIdentity) error {
+	const op = "OIDCRepository.LinkIdentity"
+
+	_, err := r.db.ExecWithRetry(ctx, r.strategy, insertIdentityQuery, identity.Issuer, identity.Subject, userID)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return fmt.Errorf("%s: %w", op, domain.ErrAlreadyExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func insertIdentity(ctx context.Context, tx *sql.Tx, userID uuid.UUID, identity *domain.OIDCIdentity) error {
+	_, err := tx.ExecContext(ctx, insertIdentityQuery, identity.Issuer, identity.Subject, userID)
+	return err
+}
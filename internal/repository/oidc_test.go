@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestOIDCRepository_LoginState(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewOIDCRepository(db, retry.Strategy{Attempts: 1}, triggerAuditRecorder{})
+
+	st := &domain.OIDCLoginState{
+		StateHash:    uuid.NewString()[:32] + uuid.NewString()[:32],
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().Add(time.Minute).Truncate(time.Microsecond),
+	}
+	require.NoError(t, repo.CreateLoginState(ctx, st))
+
+	got, err := repo.ConsumeLoginState(ctx, st.StateHash)
+	require.NoError(t, err)
+	assert.Equal(t, "nonce", got.Nonce)
+	assert.Equal(t, "verifier", got.CodeVerifier)
+	assert.True(t, st.ExpiresAt.Equal(got.ExpiresAt))
+
+	// state одноразовый
+	_, err = repo.ConsumeLoginState(ctx, st.StateHash)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestOIDCRepository_Identity(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	users := NewUserRepository(db, strategy, triggerAuditRecorder{})
+	repo := NewOIDCRepository(db, strategy, triggerAuditRecorder{})
+
+	issuer := "https://idp-" + uuid.NewString()[:8] + ".example.com"
+	identity := &domain.OIDCIdentity{Issuer: issuer, Subject: "alice"}
+
+	_, err := repo.RecordIdentityLogin(ctx, issuer, "alice")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	username := "sso-" + uuid.NewString()[:8]
+	created, err := repo.CreateUserWithIdentity(ctx, &domain.User{Username: username, Role: domain.RoleManager}, identity)
+	require.NoError(t, err)
+	assert.Equal(t, username, created.Username)
+	assert.Equal(t, domain.RoleManager, created.Role)
+	assert.Empty(t, created.PasswordHash)
+
+	got, err := repo.RecordIdentityLogin(ctx, issuer, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, got.ID)
+
+	// та же привязка или занятый логин
+	_, err = repo.CreateUserWithIdentity(ctx, &domain.User{Username: "other-" + uuid.NewString()[:8], Role: domain.RoleViewer}, identity)
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	_, err = repo.CreateUserWithIdentity(ctx, &domain.User{Username: username, Role: domain.RoleViewer},
+		&domain.OIDCIdentity{Issuer: issuer, Subject: "bob"})
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+	// привязка к существующему пользователю
+	userID, err := users.Create(ctx, uuid.New(), &domain.User{
+		Username:     "linked-" + uuid.NewString()[:8],
+		PasswordHash: "$2a$10$initial",
+		Role:         domain.RoleViewer,
+	})
+	require.NoError(t, err)
+	carol := &domain.OIDCIdentity{Issuer: issuer, Subject: "carol"}
+	require.NoError(t, repo.LinkIdentity(ctx, userID, carol))
+	assert.ErrorIs(t, repo.LinkIdentity(ctx, userID, carol), domain.ErrAlreadyExists)
+
+	got, err = repo.RecordIdentityLogin(ctx, issuer, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.ID)
+}
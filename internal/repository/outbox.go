@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// outboxLock - advisory lock выборки из outbox: пачки выдаются по одной, иначе события
+// одного товара могли бы уйти через разные экземпляры приложения не по порядку
+const outboxLock = `hashtext('outbox')`
+
+const outboxColumns = `id, event_id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts`
+
+// itemEventPayload - payload событий товара в outbox
+type itemEventPayload struct {
+	Item      json.RawMessage `json:"item"`
+	ChangedBy uuid.UUID       `json:"changed_by"`
+	Reason    string          `json:"reason,omitempty"`
+	Reference string          `json:"reference,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	// только для item.low_stock
+	PreviousQuantity *int `json:"previous_quantity,omitempty"`
+	Threshold        *int `json:"threshold,omitempty"`
+}
+
+// enqueueItemEvent пишет событие товара в outbox той же транзакцией, что и изменение.
+// item - to_jsonb(items) после изменения, для удаления - до него.
+func (t *auditTx) enqueueItemEvent(
+	ctx context.Context,
+	eventType domain.OutboxEventType,
+	itemID uuid.UUID,
+	item json.RawMessage,
+) error {
+	return t.enqueueItem(ctx, eventType, itemID, &itemEventPayload{Item: item})
+}
+
+// enqueueLowStock пишет item.low_stock, если остаток опустился с prev до quantity через порог threshold.
+// Отрицательный threshold - событие выключено.
+func (t *auditTx) enqueueLowStock(
+	ctx context.Context,
+	itemID uuid.UUID,
+	item json.RawMessage,
+	prev, quantity, threshold int,
+) error {
+	if threshold < 0 || prev <= threshold || quantity > threshold {
+		return nil
+	}
+	return t.enqueueItem(ctx, domain.OutboxItemLowStock, itemID, &itemEventPayload{
+		Item:             item,
+		PreviousQuantity: &prev,
+		Threshold:        &threshold,
+	})
+}
+
+func (t *auditTx) enqueueItem(
+	ctx context.Context,
+	eventType domain.OutboxEventType,
+	itemID uuid.UUID,
+	p *itemEventPayload,
+) error {
+	p.ChangedBy = t.userID
+	p.Reason = t.reason.Reason
+	p.Reference = t.reason.Reference
+	p.RequestID = t.request.RequestID
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("outbox payload: %w", err)
+	}
+
+	query := `INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload)
+			  VALUES ($1, $2, $3, $4::jsonb)`
+	if _, err = t.ExecContext(ctx, query, domain.OutboxAggregateItem, itemID, string(eventType), string(payload)); err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+	return nil
+}
+
+type OutboxRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewOutboxRepository(db *dbpg.DB, strategy retry.Strategy) *OutboxRepository {
+	return &OutboxRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+// Claim забирает до limit готовых к отправке событий в порядке id и откладывает их на lease:
+// пока релей их отправляет, повторно они не выдаются, а если он упадёт - выдадутся снова.
+// Событие не выдаётся, пока не отправлено предыдущее событие того же агрегата.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	const op = "OutboxRepository.Claim"
+
+	query := `UPDATE outbox
+			  SET next_attempt_at = now() + $2 * interval '1 millisecond'
+			  WHERE id IN (
+			      SELECT c.id FROM outbox c
+			      WHERE c.published_at IS NULL
+			        AND c.next_attempt_at <= now()
+			        AND NOT EXISTS (
+			            SELECT 1 FROM outbox p
+			            WHERE p.aggregate_id = c.aggregate_id
+			              AND p.published_at IS NULL
+			              AND p.id < c.id
+			              AND p.next_attempt_at > now()
+			        )
+			      ORDER BY c.id
+			      LIMIT $1
+			  )
+			  RETURNING ` + outboxColumns
+
+	var events []*domain.OutboxEvent
+	err := r.db.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(`+outboxLock+`)`); err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, query, limit, lease.Milliseconds())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				ev        domain.OutboxEvent
+				eventType string
+				payload   []byte
+			)
+			if err = rows.Scan(
+				&ev.ID, &ev.EventID, &ev.AggregateType, &ev.AggregateID,
+				&eventType, &payload, &ev.CreatedAt, &ev.Attempts,
+			); err != nil {
+				return fmt.Errorf("scan event: %w", err)
+			}
+			ev.Type = domain.OutboxEventType(eventType)
+			ev.Payload = payload
+			events = append(events, &ev)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	slices.SortFunc(events, func(a, b *domain.OutboxEvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return events, nil
+}
+
+// MarkPublished отмечает события доставленными
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	const op = "OutboxRepository.MarkPublished"
+
+	query := `UPDATE outbox SET published_at = now(), last_error = NULL WHERE id = ANY($1)`
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MarkFailed откладывает событие на delay после неудачной отправки
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, delay time.Duration, reason string) error {
+	const op = "OutboxRepository.MarkFailed"
+
+	query := `UPDATE outbox
+			  SET attempts = attempts + 1,
+			      next_attempt_at = now() + $2 * interval '1 millisecond',
+			      last_error = $3
+			  WHERE id = $1`
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, id, delay.Milliseconds(), reason); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Release возвращает в очередь выданные, но не отправленные события
+func (r *OutboxRepository) Release(ctx context.Context, ids []int64) error {
+	const op = "OutboxRepository.Release"
+
+	query := `UPDATE outbox SET next_attempt_at = now() WHERE id = ANY($1) AND published_at IS NULL`
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeletePublished удаляет события, доставленные раньше before
+func (r *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	const op = "OutboxRepository.DeletePublished"
+
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, `DELETE FROM outbox WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func claimedIDs(events []*domain.OutboxEvent) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestOutboxRepository(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	items := NewItemRepository(db, strategy, appAuditRecorder{}, 5)
+	outbox := NewOutboxRepository(db, strategy)
+	userID := uuid.New()
+
+	// очередь общая для всей тестовой базы - оставшееся от прошлых запусков считается доставленным
+	_, err := db.Master.ExecContext(ctx, `UPDATE outbox SET published_at = now() WHERE published_at IS NULL`)
+	require.NoError(t, err)
+
+	item, err := items.Create(ctx, userID, &domain.CreateItemInput{
+		Name:     "Шайба М6",
+		SKU:      "OUTBOX-" + uuid.NewString()[:8],
+		Quantity: 10,
+		Price:    decimal.RequireFromString("1.5"),
+	})
+	require.NoError(t, err)
+
+	reasonCtx := domain.WithAuditReason(ctx, domain.AuditReason{Reason: "инвентаризация", Reference: "INV-7"})
+	_, err = items.AdjustQuantity(reasonCtx, userID, item.ID, -4)
+	require.NoError(t, err)
+
+	events, err := outbox.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	created, updated := events[0], events[1]
+
+	assert.Equal(t, domain.OutboxItemCreated, created.Type)
+	assert.Equal(t, domain.OutboxItemUpdated, updated.Type)
+	for _, ev := range events {
+		assert.Equal(t, domain.OutboxAggregateItem, ev.AggregateType)
+		assert.Equal(t, item.ID, ev.AggregateID)
+	}
+
+	var payload struct {
+		Item struct {
+			Quantity int `json:"quantity"`
+		} `json:"item"`
+		ChangedBy uuid.UUID `json:"changed_by"`
+		Reason    string    `json:"reason"`
+		Reference string    `json:"reference"`
+	}
+	require.NoError(t, json.Unmarshal(updated.Payload, &payload))
+	assert.Equal(t, 6, payload.Item.Quantity)
+	assert.Equal(t, userID, payload.ChangedBy)
+	assert.Equal(t, "инвентаризация", payload.Reason)
+	assert.Equal(t, "INV-7", payload.Reference)
+
+	// выданные события закреплены за релеем
+	again, err := outbox.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	// первое событие отложено - второе того же товара не выдаётся, пока первое не уйдёт
+	require.NoError(t, outbox.MarkFailed(ctx, created.ID, time.Hour, "receiver is down"))
+	require.NoError(t, outbox.Release(ctx, []int64{updated.ID}))
+	again, err = outbox.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	require.NoError(t, outbox.Release(ctx, []int64{created.ID, updated.ID}))
+	again, err = outbox.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{created.ID, updated.ID}, claimedIDs(again))
+	assert.Equal(t, 1, again[0].Attempts)
+
+	require.NoError(t, outbox.MarkPublished(ctx, []int64{created.ID, updated.ID}))
+	require.NoError(t, outbox.Release(ctx, []int64{created.ID, updated.ID}), "release must skip published events")
+	again, err = outbox.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	n, err := outbox.DeletePublished(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, int64(2))
+}
+
+func TestOutboxRepository_LowStock(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	items := NewItemRepository(db, strategy, triggerAuditRecorder{}, 5)
+	outbox := NewOutboxRepository(db, strategy)
+	userID := uuid.New()
+
+	_, err := db.Master.ExecContext(ctx, `UPDATE outbox SET published_at = now() WHERE published_at IS NULL`)
+	require.NoError(t, err)
+
+	item, err := items.Create(ctx, userID, &domain.CreateItemInput{
+		Name:     "Гайка М6",
+		SKU:      "LOWSTOCK-" + uuid.NewString()[:8],
+		Quantity: 8,
+		Price:    decimal.RequireFromString("0.7"),
+	})
+	require.NoError(t, err)
+
+	three, two := 3, 2
+	// 8 -> 3: порог 5 пройден
+	_, err = items.Update(ctx, userID, item.ID, &domain.UpdateItemInput{Quantity: &three})
+	require.NoError(t, err)
+	// 3 -> 2: уже ниже порога, повторно не сообщается
+	_, err = items.Update(ctx, userID, item.ID, &domain.UpdateItemInput{Quantity: &two})
+	require.NoError(t, err)
+	// 2 -> 12 -> 5: снова дошёл до порога
+	_, err = items.AdjustQuantity(ctx, userID, item.ID, 10)
+	require.NoError(t, err)
+	_, err = items.AdjustQuantity(ctx, userID, item.ID, -7)
+	require.NoError(t, err)
+
+	events, err := outbox.Claim(ctx, 20, time.Minute)
+	require.NoError(t, err)
+
+	var types []domain.OutboxEventType
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+	assert.Equal(t, []domain.OutboxEventType{
+		domain.OutboxItemCreated,
+		domain.OutboxItemUpdated, domain.OutboxItemLowStock,
+		domain.OutboxItemUpdated,
+		domain.OutboxItemUpdated,
+		domain.OutboxItemUpdated, domain.OutboxItemLowStock,
+	}, types)
+
+	var payload struct {
+		PreviousQuantity int `json:"previous_quantity"`
+		Threshold        int `json:"threshold"`
+		Item             struct {
+			Quantity int `json:"quantity"`
+		} `json:"item"`
+	}
+	require.NoError(t, json.Unmarshal(events[2].Payload, &payload))
+	assert.Equal(t, 8, payload.PreviousQuantity)
+	assert.Equal(t, 5, payload.Threshold)
+	assert.Equal(t, 3, payload.Item.Quantity)
+
+	require.NoError(t, outbox.MarkPublished(ctx, claimedIDs(events)))
+}
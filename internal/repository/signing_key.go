@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+// SigningKeyRepository - ключи подписи токенов доступа
+type SigningKeyRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewSigningKeyRepository(db *dbpg.DB, strategy retry.Strategy) *SigningKeyRepository {
+	return &SigningKeyRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+func (r *SigningKeyRepository) ListKeys(ctx context.Context) ([]*domain.SigningKey, error) {
+	const op = "SigningKeyRepository.ListKeys"
+
+	query := `SELECT kid, algorithm, private_key, encrypted, activates_at, created_at
+			  FROM signing_keys
+			  ORDER BY activates_at`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.SigningKey
+	for rows.Next() {
+		var k domain.SigningKey
+		if err = rows.Scan(&k.ID, &k.Algorithm, &k.PrivateKey, &k.Encrypted, &k.ActivatesAt, &k.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s - scan signing key: %w", op, err)
+		}
+		res = append(res, &k)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+// CreateKeyIfDue сохраняет key, если у его алгоритма нет ключа, активируемого после dueBefore.
+// Экземпляры ротируют одновременно - блокировка гарантирует, что следующий ключ создаст только один.
+func (r *SigningKeyRepository) CreateKeyIfDue(ctx context.Context, key *domain.SigningKey, dueBefore time.Time) (bool, error) {
+	const op = "SigningKeyRepository.CreateKeyIfDue"
+
+	var created bool
+	err := r.db.WithTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('signing_keys'))`); err != nil {
+			return err
+		}
+
+		var fresh bool
+		err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM signing_keys WHERE algorithm = $1 AND activates_at > $2)`,
+			key.Algorithm, dueBefore,
+		).Scan(&fresh)
+		if err != nil || fresh {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO signing_keys (kid, algorithm, private_key, encrypted, activates_at) VALUES ($1, $2, $3, $4, $5)`,
+			key.ID, key.Algorithm, key.PrivateKey, key.Encrypted, key.ActivatesAt,
+		)
+		created = err == nil
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+// EncryptKey заменяет незашифрованный ключ зашифрованным; уже зашифрованный не трогает
+func (r *SigningKeyRepository) EncryptKey(ctx context.Context, kid string, sealed []byte) error {
+	const op = "SigningKeyRepository.EncryptKey"
+
+	query := `UPDATE signing_keys
+			  SET private_key = $2, encrypted = true
+			  WHERE kid = $1 AND NOT encrypted`
+
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, query, kid, sealed); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteSuperseded удаляет ключи, после которых до before активирован любой другой ключ.
+// Алгоритм следующего ключа не важен: после смены auth.signing.algorithm ключи прежнего
+// алгоритма больше не подписывают и удаляются, когда истекут подписанные ими токены.
+func (r *SigningKeyRepository) DeleteSuperseded(ctx context.Context, before time.Time) (int64, error) {
+	const op = "SigningKeyRepository.DeleteSuperseded"
+
+	query := `DELETE FROM signing_keys k
+			  WHERE EXISTS (
+				  SELECT 1 FROM signing_keys next
+				  WHERE next.activates_at > k.activates_at
+				    AND next.activates_at <= $1
+			  )`
+
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
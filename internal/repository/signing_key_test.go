@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestSigningKeyRepository(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewSigningKeyRepository(db, retry.Strategy{Attempts: 1})
+
+	_, err := db.Master.ExecContext(ctx, `DELETE FROM signing_keys`)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Microsecond)
+	newKey := func(activatesAt time.Time) *domain.SigningKey {
+		return &domain.SigningKey{
+			ID:          uuid.NewString(),
+			Algorithm:   "EdDSA",
+			PrivateKey:  []byte("der"),
+			ActivatesAt: activatesAt,
+		}
+	}
+
+	first := newKey(now.Add(-48 * time.Hour))
+	created, err := repo.CreateKeyIfDue(ctx, first, now.Add(-72*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// ключ, активированный после dueBefore, уже есть - второй не создаётся
+	created, err = repo.CreateKeyIfDue(ctx, newKey(now), now.Add(-72*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	second := newKey(now.Add(-time.Hour))
+	created, err = repo.CreateKeyIfDue(ctx, second, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	keys, err := repo.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, first.ID, keys[0].ID)
+	assert.Equal(t, second.ID, keys[1].ID)
+	assert.Equal(t, []byte("der"), keys[1].PrivateKey)
+	assert.True(t, second.ActivatesAt.Equal(keys[1].ActivatesAt))
+
+	// первый заменён вторым, но подписанные им токены ещё могут действовать
+	n, err := repo.DeleteSuperseded(ctx, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = repo.DeleteSuperseded(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	keys, err = repo.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, second.ID, keys[0].ID)
+	assert.False(t, keys[0].Encrypted)
+
+	require.NoError(t, repo.EncryptKey(ctx, second.ID, []byte("sealed")))
+	// уже зашифрованный ключ повторно не переписывается
+	require.NoError(t, repo.EncryptKey(ctx, second.ID, []byte("other")))
+	keys, err = repo.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, keys[0].Encrypted)
+	assert.Equal(t, []byte("sealed"), keys[0].PrivateKey)
+
+	// ключ другого алгоритма тоже заменяет прежний
+	third := newKey(now.Add(time.Hour))
+	third.Algorithm = "RS256"
+	third.Encrypted = true
+	created, err = repo.CreateKeyIfDue(ctx, third, now)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	n, err = repo.DeleteSuperseded(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	keys, err = repo.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, third.ID, keys[0].ID)
+	assert.True(t, keys[0].Encrypted)
+}
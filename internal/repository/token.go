@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, access_jti, access_expires_at,
+	expires_at, created_at, rotated_at, revoked_at`
+
+// TokenRepository - refresh-токены и отозванные токены доступа
+type TokenRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewTokenRepository(db *dbpg.DB, strategy retry.Strategy) *TokenRepository {
+	return &TokenRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+// CreateRefreshToken сохраняет refresh-токен нового входа
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error {
+	const op = "TokenRepository.CreateRefreshToken"
+
+	if _, err := r.db.ExecWithRetry(ctx, r.strategy, insertRefreshToken, refreshTokenArgs(t)...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *TokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	const op = "TokenRepository.GetRefreshToken"
+
+	query := `SELECT ` + refreshTokenColumns + `
+			  FROM refresh_tokens
+			  WHERE token_hash = $1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var t domain.RefreshToken
+	err = row.Scan(
+		&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.AccessTokenID, &t.AccessExpiresAt,
+		&t.ExpiresAt, &t.CreatedAt, &t.RotatedAt, &t.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan refresh token: %w", op, err)
+	}
+
+	return &t, nil
+}
+
+// RotateRefreshToken помечает токен id обменянным и сохраняет следующий токен семейства.
+// Если id уже обменян или отозван (в том числе параллельным запросом) - domain.ErrRefreshTokenReused.
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error {
+	const op = "TokenRepository.RotateRefreshToken"
+
+	query := `UPDATE refresh_tokens
+			  SET rotated_at = now()
+			  WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`
+
+	err := r.db.WithTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+
+		_, err = tx.ExecContext(ctx, insertRefreshToken, refreshTokenArgs(next)...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// RevokeFamily отзывает все refresh-токены семейства и выданные с ними токены доступа.
+// Возвращает отозванные токены доступа, которые ещё не истекли.
+func (r *TokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) ([]*domain.RevokedToken, error) {
+	const op = "TokenRepository.RevokeFamily"
+
+	query := `WITH family AS (
+				  UPDATE refresh_tokens SET revoked_at = now()
+				  WHERE family_id = $1 AND revoked_at IS NULL
+				  RETURNING access_jti, access_expires_at
+			  ), denied AS (
+				  SELECT access_jti AS jti, access_expires_at AS expires_at
+				  FROM family
+				  WHERE access_expires_at > now()
+			  ), ins AS (
+				  INSERT INTO revoked_tokens (jti, expires_at)
+				  SELECT jti, expires_at FROM denied
+				  ON CONFLICT (jti) DO NOTHING
+			  )
+			  SELECT jti, expires_at FROM denied`
+
+	tokens, err := r.queryRevoked(ctx, query, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tokens, nil
+}
+
+// RevokeSession отзывает токен доступа jti и сессию, в которой он выдан:
+// всё семейство refresh-токенов и остальные его токены доступа
+func (r *TokenRepository) RevokeSession(ctx context.Context, jti uuid.UUID, expiresAt time.Time) ([]*domain.RevokedToken, error) {
+	const op = "TokenRepository.RevokeSession"
+
+	query := `WITH family AS (
+				  UPDATE refresh_tokens SET revoked_at = now()
+				  WHERE revoked_at IS NULL
+				    AND family_id = (SELECT family_id FROM refresh_tokens WHERE access_jti = $1)
+				  RETURNING access_jti, access_expires_at
+			  ), denied AS (
+				  SELECT access_jti AS jti, access_expires_at AS expires_at FROM family
+				  UNION ALL
+				  SELECT $1::uuid, $2::timestamptz
+				  WHERE NOT EXISTS (SELECT 1 FROM family WHERE access_jti = $1)
+			  ), ins AS (
+				  INSERT INTO revoked_tokens (jti, expires_at)
+				  SELECT jti, expires_at FROM denied
+				  WHERE expires_at > now()
+				  ON CONFLICT (jti) DO NOTHING
+			  )
+			  SELECT jti, expires_at FROM denied WHERE expires_at > now()`
+
+	tokens, err := r.queryRevoked(ctx, query, jti, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tokens, nil
+}
+
+// RevokeUserSessions отзывает все сессии пользователя: семейства refresh-токенов и выданные
+// с ними токены доступа. Возвращает отозванные токены доступа, которые ещё не истекли.
+func (r *TokenRepository) RevokeUserSessions(ctx context.Context, userID uuid.UUID) ([]*domain.RevokedToken, error) {
+	const op = "TokenRepository.RevokeUserSessions"
+
+	query := `WITH sessions AS (
+				  UPDATE refresh_tokens SET revoked_at = now()
+				  WHERE user_id = $1 AND revoked_at IS NULL
+				  RETURNING access_jti, access_expires_at
+			  ), denied AS (
+				  SELECT access_jti AS jti, access_expires_at AS expires_at
+				  FROM sessions
+				  WHERE access_expires_at > now()
+			  ), ins AS (
+				  INSERT INTO revoked_tokens (jti, expires_at)
+				  SELECT jti, expires_at FROM denied
+				  ON CONFLICT (jti) DO NOTHING
+			  )
+			  SELECT jti, expires_at FROM denied`
+
+	tokens, err := r.queryRevoked(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tokens, nil
+}
+
+// ListRevoked - действующие отозванные токены доступа, отозванные начиная с since
+func (r *TokenRepository) ListRevoked(ctx context.Context, since time.Time) ([]*domain.RevokedToken, error) {
+	const op = "TokenRepository.ListRevoked"
+
+	query := `SELECT jti, expires_at
+			  FROM revoked_tokens
+			  WHERE revoked_at >= $1 AND expires_at > now()`
+
+	tokens, err := r.queryRevoked(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tokens, nil
+}
+
+// DeleteExpired удаляет refresh-токены и записи denylist, истёкшие раньше before
+func (r *TokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	const op = "TokenRepository.DeleteExpired"
+
+	var total int64
+	for _, query := range []string{
+		`DELETE FROM refresh_tokens WHERE expires_at < $1`,
+		`DELETE FROM revoked_tokens WHERE expires_at < $1`,
+	} {
+		res, err := r.db.ExecWithRetry(ctx, r.strategy, query, before)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
+
+func (r *TokenRepository) queryRevoked(ctx context.Context, query string, args ...any) ([]*domain.RevokedToken, error) {
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*domain.RevokedToken
+	for rows.Next() {
+		var t domain.RevokedToken
+		if err = rows.Scan(&t.ID, &t.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("scan revoked token: %w", err)
+		}
+		res = append(res, &t)
+	}
+	return res, rows.Err()
+}
+
+const insertRefreshToken = `INSERT INTO refresh_tokens
+	(user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)`
+
+func refreshTokenArgs(t *domain.RefreshToken) []any {
+	return []any{t.UserID, t.FamilyID, t.TokenHash, t.AccessTokenID, t.AccessExpiresAt, t.ExpiresAt}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func revokedIDs(tokens []*domain.RevokedToken) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(tokens))
+	for _, t := range tokens {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestTokenRepository(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	strategy := retry.Strategy{Attempts: 1}
+	users := NewUserRepository(db, strategy, triggerAuditRecorder{})
+	repo := NewTokenRepository(db, strategy)
+
+	userID, err := users.Create(ctx, uuid.New(), &domain.User{
+		Username:     "tokens-" + uuid.NewString()[:8],
+		PasswordHash: "$2a$10$initial",
+		Role:         domain.RoleViewer,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	newToken := func(familyID uuid.UUID) *domain.RefreshToken {
+		return &domain.RefreshToken{
+			UserID:          userID,
+			FamilyID:        familyID,
+			TokenHash:       uuid.NewString(),
+			AccessTokenID:   uuid.New(),
+			AccessExpiresAt: now.Add(15 * time.Minute),
+			ExpiresAt:       now.Add(time.Hour),
+		}
+	}
+
+	family := uuid.New()
+	first := newToken(family)
+	require.NoError(t, repo.CreateRefreshToken(ctx, first))
+
+	got, err := repo.GetRefreshToken(ctx, first.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, family, got.FamilyID)
+	assert.Equal(t, first.AccessTokenID, got.AccessTokenID)
+	assert.Nil(t, got.RotatedAt)
+
+	_, err = repo.GetRefreshToken(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// обмен: старый токен помечен, второй раз его не обменять
+	second := newToken(family)
+	require.NoError(t, repo.RotateRefreshToken(ctx, got.ID, second))
+	assert.ErrorIs(t, repo.RotateRefreshToken(ctx, got.ID, newToken(family)), domain.ErrRefreshTokenReused)
+
+	got, err = repo.GetRefreshToken(ctx, first.TokenHash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RotatedAt)
+
+	// отзыв семейства запрещает токены доступа всех его refresh-токенов
+	revoked, err := repo.RevokeFamily(ctx, family)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{first.AccessTokenID, second.AccessTokenID}, revokedIDs(revoked))
+
+	got, err = repo.GetRefreshToken(ctx, second.TokenHash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+
+	// выход: сессия находится по токену доступа
+	other := newToken(uuid.New())
+	require.NoError(t, repo.CreateRefreshToken(ctx, other))
+	revoked, err = repo.RevokeSession(ctx, other.AccessTokenID, other.AccessExpiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{other.AccessTokenID}, revokedIDs(revoked))
+
+	got, err = repo.GetRefreshToken(ctx, other.TokenHash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+
+	// смена пароля или отключение: отзываются все ещё действующие сессии пользователя
+	sessionA, sessionB := newToken(uuid.New()), newToken(uuid.New())
+	require.NoError(t, repo.CreateRefreshToken(ctx, sessionA))
+	require.NoError(t, repo.CreateRefreshToken(ctx, sessionB))
+	revoked, err = repo.RevokeUserSessions(ctx, userID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{sessionA.AccessTokenID, sessionB.AccessTokenID}, revokedIDs(revoked))
+
+	got, err = repo.GetRefreshToken(ctx, sessionB.TokenHash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+
+	listed, err := repo.ListRevoked(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Subset(t, revokedIDs(listed), []uuid.UUID{
+		first.AccessTokenID, second.AccessTokenID, other.AccessTokenID, sessionA.AccessTokenID,
+	})
+
+	// после истечения записи удаляются
+	_, err = repo.DeleteExpired(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	_, err = repo.GetRefreshToken(ctx, first.TokenHash)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	listed, err = repo.ListRevoked(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.NotContains(t, revokedIDs(listed), other.AccessTokenID)
+}
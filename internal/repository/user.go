@@ -12,25 +12,39 @@ import (
 	"github.com/wb-go/wbf/retry"
 )
 
+const userColumns = `id, username, password_hash, role, disabled_at, password_changed_at, created_at, updated_at`
+
 type UserRepository struct {
 	db       *dbpg.DB
 	strategy retry.Strategy
+	recorder AuditRecorder
 }
 
-func NewUserRepository(db *dbpg.DB, strategy retry.Strategy) *UserRepository {
+func NewUserRepository(db *dbpg.DB, strategy retry.Strategy, recorder AuditRecorder) *UserRepository {
 	return &UserRepository{
 		db:       db,
 		strategy: strategy,
+		recorder: recorder,
 	}
 }
 
-func (r *UserRepository) Create(ctx context.Context, user *domain.User) (uuid.UUID, error) {
+// Create добавляет пользователя от имени actorID - изменение попадает в журнал аудита
+func (r *UserRepository) Create(ctx context.Context, actorID uuid.UUID, user *domain.User) (uuid.UUID, error) {
 	const op = "UserRepository.Create"
 
-	query := `INSERT INTO users (username, password_hash, role) 
-			  VALUES ($1, $2, $3) 
-			  RETURNING id`
-	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, user.Username, user.PasswordHash, user.Role)
+	query := `INSERT INTO users (username, password_hash, role)
+			  VALUES ($1, $2, $3)
+			  RETURNING id, to_jsonb(users)`
+
+	var id uuid.UUID
+	err := withAuditContext(ctx, r.db, r.recorder, actorID, func(tx *auditTx) error {
+		var newData []byte
+		err := tx.QueryRowContext(ctx, query, user.Username, user.PasswordHash, user.Role).Scan(&id, &newData)
+		if err != nil {
+			return err
+		}
+		return tx.record(ctx, domain.AuditEntityUser, domain.AuditInsert, id, nil, newData)
+	})
 	if err != nil {
 		if isDuplicateKey(err) {
 			return uuid.Nil, fmt.Errorf("%s: %w", op, domain.ErrAlreadyExists)
@@ -38,40 +52,36 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) (uuid.UU
 		return uuid.Nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var id uuid.UUID
-	if err = row.Scan(&id); err != nil {
-		return uuid.Nil, fmt.Errorf("%s - scan id: %w", op, err)
-	}
 	return id, nil
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	const op = "UserRepository.GetByID"
 
-	query := `SELECT id, username, password_hash, role, created_at, updated_at
+	query := `SELECT ` + userColumns + `
 			  FROM users
 			  WHERE id=$1`
 
-	var u domain.User
 	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err = row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	u, err := scanUser(row)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
 		}
 		return nil, fmt.Errorf("%s - scan user: %w", op, err)
 	}
 
-	return &u, nil
+	return u, nil
 }
 
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	const op = "UserRepository.GetByUsername"
 
-	query := `SELECT id, username, password_hash, role, created_at, updated_at
+	query := `SELECT ` + userColumns + `
 			  FROM users
 			  WHERE username=$1`
 
@@ -80,21 +90,21 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var u domain.User
-	if err = row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	u, err := scanUser(row)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
 		}
 		return nil, fmt.Errorf("%s - scan user: %w", op, err)
 	}
 
-	return &u, nil
+	return u, nil
 }
 
 func (r *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
 	const op = "UserRepository.List"
 
-	query := `SELECT id, username, password_hash, role, created_at, updated_at
+	query := `SELECT ` + userColumns + `
 			  FROM users
 			  ORDER BY username`
 
@@ -106,15 +116,12 @@ func (r *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
 
 	var res []*domain.User
 	for rows.Next() {
-		var u domain.User
-		if err = rows.Scan(
-			&u.ID, &u.Username, &u.PasswordHash,
-			&u.Role, &u.CreatedAt, &u.UpdatedAt,
-		); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s - scan user: %w", op, err)
 		}
 
-		res = append(res, &u)
+		res = append(res, u)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -123,3 +130,106 @@ func (r *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
 
 	return res, nil
 }
+
+// UpdateRole меняет роль пользователя от имени actorID
+func (r *UserRepository) UpdateRole(ctx context.Context, actorID, id uuid.UUID, role domain.Role) (*domain.User, error) {
+	const op = "UserRepository.UpdateRole"
+
+	u, err := r.update(ctx, actorID, id, `role = $2`, role)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return u, nil
+}
+
+// UpdatePassword заменяет хеш пароля и отмечает время смены
+func (r *UserRepository) UpdatePassword(ctx context.Context, actorID, id uuid.UUID, passwordHash string) (*domain.User, error) {
+	const op = "UserRepository.UpdatePassword"
+
+	u, err := r.update(ctx, actorID, id, `password_hash = $2, password_changed_at = now()`, passwordHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return u, nil
+}
+
+// SetDisabled отключает или включает учётную запись. Повторное отключение
+// сохраняет время первого, чтобы в журнале не появлялись пустые изменения.
+func (r *UserRepository) SetDisabled(ctx context.Context, actorID, id uuid.UUID, disabled bool) (*domain.User, error) {
+	const op = "UserRepository.SetDisabled"
+
+	u, err := r.update(ctx, actorID, id,
+		`disabled_at = CASE WHEN $2::boolean THEN COALESCE(disabled_at, now()) END`, disabled)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return u, nil
+}
+
+// Delete удаляет пользователя от имени actorID. Записи журнала аудита, сделанные
+// пользователем, остаются - changed_by хранит его id.
+func (r *UserRepository) Delete(ctx context.Context, actorID, id uuid.UUID) error {
+	const op = "UserRepository.Delete"
+
+	query := `DELETE FROM users WHERE id=$1 RETURNING to_jsonb(users)`
+
+	err := withAuditContext(ctx, r.db, r.recorder, actorID, func(tx *auditTx) error {
+		var oldData []byte
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&oldData); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+		return tx.record(ctx, domain.AuditEntityUser, domain.AuditDelete, id, oldData, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// update применяет set к строке пользователя ($1 - id, $2 - value) и пишет изменение в журнал
+func (r *UserRepository) update(ctx context.Context, actorID, id uuid.UUID, set string, value any) (*domain.User, error) {
+	query := `UPDATE users SET ` + set + `
+			  WHERE id=$1
+			  RETURNING ` + userColumns + `, to_jsonb(users)`
+
+	var u *domain.User
+	err := withAuditContext(ctx, r.db, r.recorder, actorID, func(tx *auditTx) error {
+		oldData, err := tx.snapshot(ctx, "users", id)
+		if err != nil {
+			return err
+		}
+
+		var newData []byte
+		u, err = scanUser(tx.QueryRowContext(ctx, query, id, value), &newData)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+
+		return tx.record(ctx, domain.AuditEntityUser, domain.AuditUpdate, id, oldData, newData)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// scanUser читает колонки userColumns; extra - дополнительные колонки после них
+func scanUser(row rowScanner, extra ...any) (*domain.User, error) {
+	var u domain.User
+	dest := []any{
+		&u.ID, &u.Username, &u.PasswordHash, &u.Role,
+		&u.DisabledAt, &u.PasswordChangedAt, &u.CreatedAt, &u.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestUserRepository_AuditedChanges(t *testing.T) {
+	db := openTestDB(t)
+
+	for _, recorder := range []AuditRecorder{triggerAuditRecorder{}, appAuditRecorder{}} {
+		t.Run(string(recorder.Mode()), func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewUserRepository(db, retry.Strategy{Attempts: 1}, recorder)
+			adminID := uuid.New()
+
+			id, err := repo.Create(ctx, adminID, &domain.User{
+				Username:     "user-" + uuid.NewString()[:8],
+				PasswordHash: "$2a$10$initial",
+				Role:         domain.RoleViewer,
+			})
+			require.NoError(t, err)
+
+			u, err := repo.UpdateRole(ctx, adminID, id, domain.RoleManager)
+			require.NoError(t, err)
+			assert.Equal(t, domain.RoleManager, u.Role)
+
+			u, err = repo.UpdatePassword(ctx, adminID, id, "$2a$10$changed")
+			require.NoError(t, err)
+			assert.Equal(t, "$2a$10$changed", u.PasswordHash)
+			require.NotNil(t, u.PasswordChangedAt)
+
+			u, err = repo.SetDisabled(ctx, adminID, id, true)
+			require.NoError(t, err)
+			require.NotNil(t, u.DisabledAt)
+			disabledAt := *u.DisabledAt
+
+			// повторное отключение не меняет строку и не пишет журнал
+			u, err = repo.SetDisabled(ctx, adminID, id, true)
+			require.NoError(t, err)
+			assert.True(t, disabledAt.Equal(*u.DisabledAt))
+
+			u, err = repo.SetDisabled(ctx, adminID, id, false)
+			require.NoError(t, err)
+			assert.False(t, u.Disabled())
+
+			require.NoError(t, repo.Delete(ctx, adminID, id))
+			assert.ErrorIs(t, repo.Delete(ctx, adminID, id), domain.ErrNotFound)
+			_, err = repo.SetDisabled(ctx, adminID, id, true)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			rows, err := db.Master.QueryContext(ctx, `
+				SELECT action, changed_by, COALESCE(diff, '{}'::jsonb)::text
+				FROM audit_log
+				WHERE entity_type = $1 AND entity_id = $2
+				ORDER BY id`, domain.AuditEntityUser, id)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			var (
+				actions       []string
+				changedFields [][]string
+			)
+			for rows.Next() {
+				var (
+					action    string
+					changedBy uuid.UUID
+					diff      string
+				)
+				require.NoError(t, rows.Scan(&action, &changedBy, &diff))
+				assert.Equal(t, adminID, changedBy)
+				assert.NotContains(t, diff, "$2a$", "password hash must not reach the audit log")
+
+				var fields map[string]json.RawMessage
+				require.NoError(t, json.Unmarshal([]byte(diff), &fields))
+				var keys []string
+				for k := range fields {
+					if k != "updated_at" {
+						keys = append(keys, k)
+					}
+				}
+				actions = append(actions, action)
+				changedFields = append(changedFields, keys)
+			}
+			require.NoError(t, rows.Err())
+
+			assert.Equal(t, []string{"INSERT", "UPDATE", "UPDATE", "UPDATE", "UPDATE", "DELETE"}, actions)
+			assert.Equal(t, []string{"role"}, changedFields[1])
+			assert.ElementsMatch(t, []string{"password_hash", "password_changed_at"}, changedFields[2])
+			assert.Equal(t, []string{"disabled_at"}, changedFields[3])
+			assert.Equal(t, []string{"disabled_at"}, changedFields[4])
+		})
+	}
+}
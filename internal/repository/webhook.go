@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const webhookColumns = `id, url, event_types, secret, active, created_by, created_at, updated_at`
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.next_attempt_at, COALESCE(d.response_status, 0), COALESCE(d.response_body, ''), COALESCE(d.error, ''),
+	COALESCE(d.duration_ms, 0), d.redelivery_of, d.created_at, d.delivered_at`
+
+type WebhookRepository struct {
+	db       *dbpg.DB
+	strategy retry.Strategy
+}
+
+func NewWebhookRepository(db *dbpg.DB, strategy retry.Strategy) *WebhookRepository {
+	return &WebhookRepository{
+		db:       db,
+		strategy: strategy,
+	}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error) {
+	const op = "WebhookRepository.Create"
+
+	query := `INSERT INTO webhooks (url, event_types, secret, active, created_by)
+			  VALUES ($1, $2, $3, $4, $5)
+			  RETURNING ` + webhookColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query,
+		hook.URL, pq.Array(eventTypeStrings(hook.EventTypes)), hook.Secret, hook.Active, hook.CreatedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := scanWebhook(row)
+	if err != nil {
+		return nil, fmt.Errorf("%s - scan webhook: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	const op = "WebhookRepository.GetByID"
+
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id=$1`
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hook, err := scanWebhook(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan webhook: %w", op, err)
+	}
+	return hook, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	const op = "WebhookRepository.List"
+
+	query := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY created_at, id`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Webhook
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s - scan webhook: %w", op, err)
+		}
+		res = append(res, hook)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+// Update меняет заданные поля подписки; nil в input - поле остаётся прежним
+func (r *WebhookRepository) Update(ctx context.Context, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error) {
+	const op = "WebhookRepository.Update"
+
+	query := `UPDATE webhooks
+			  SET url = COALESCE($2, url),
+			      event_types = COALESCE($3, event_types),
+			      secret = COALESCE($4, secret),
+			      active = COALESCE($5, active),
+			      updated_at = now()
+			  WHERE id = $1
+			  RETURNING ` + webhookColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query,
+		id, input.URL, pq.Array(eventTypeStrings(input.EventTypes)), input.Secret, input.Active,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hook, err := scanWebhook(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan webhook: %w", op, err)
+	}
+	return hook, nil
+}
+
+// Delete удаляет подписку вместе с журналом её доставок
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "WebhookRepository.Delete"
+
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, `DELETE FROM webhooks WHERE id=$1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// Enqueue создаёт доставку события для каждой активной подписки на его тип и возвращает их число.
+// Повторно отправленное релеем событие новых доставок не создаёт.
+func (r *WebhookRepository) Enqueue(ctx context.Context, ev *domain.OutboxEvent, payload []byte) (int64, error) {
+	const op = "WebhookRepository.Enqueue"
+
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+			  SELECT id, $1, $2::text, $3::jsonb FROM webhooks
+			  WHERE active AND $2::text = ANY(event_types)
+			  ON CONFLICT (webhook_id, event_id) WHERE redelivery_of IS NULL DO NOTHING`
+
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, query, ev.EventID, string(ev.Type), string(payload))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// Claim забирает до limit доставок, которым пора уходить, и откладывает их на lease:
+// пока воркер их отправляет, повторно они не выдаются, а если он упадёт - выдадутся снова.
+// Доставки выключенных подписок ждут включения.
+func (r *WebhookRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	const op = "WebhookRepository.Claim"
+
+	query := `WITH claimed AS (
+			      UPDATE webhook_deliveries d
+			      SET next_attempt_at = now() + $2 * interval '1 millisecond'
+			      WHERE d.id IN (
+			          SELECT c.id FROM webhook_deliveries c
+			          JOIN webhooks w ON w.id = c.webhook_id
+			          WHERE c.status = 'pending' AND c.next_attempt_at <= now() AND w.active
+			          ORDER BY c.next_attempt_at
+			          FOR UPDATE OF c SKIP LOCKED
+			          LIMIT $1
+			      )
+			      RETURNING d.*
+			  )
+			  SELECT ` + webhookDeliveryColumns + `, w.url, w.secret
+			  FROM claimed d
+			  JOIN webhooks w ON w.id = d.webhook_id
+			  ORDER BY d.created_at`
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.WebhookDelivery
+	for rows.Next() {
+		hook := &domain.Webhook{}
+		d, err := scanWebhookDelivery(rows, &hook.URL, &hook.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("%s - scan delivery: %w", op, err)
+		}
+		hook.ID = d.WebhookID
+		d.Webhook = hook
+		res = append(res, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+// SaveAttempt записывает результат попытки и переводит доставку в status.
+// Для pending следующая попытка - через retryIn.
+func (r *WebhookRepository) SaveAttempt(
+	ctx context.Context,
+	id uuid.UUID,
+	attempt *domain.WebhookAttempt,
+	status domain.WebhookDeliveryStatus,
+	retryIn time.Duration,
+) error {
+	const op = "WebhookRepository.SaveAttempt"
+
+	query := `UPDATE webhook_deliveries
+			  SET attempts = attempts + 1,
+			      status = $2::text,
+			      next_attempt_at = CASE WHEN $2::text = 'pending' THEN now() + $3 * interval '1 millisecond' END,
+			      response_status = NULLIF($4, 0),
+			      response_body = NULLIF($5, ''),
+			      error = NULLIF($6, ''),
+			      duration_ms = $7,
+			      delivered_at = CASE WHEN $2::text = 'succeeded' THEN now() END
+			  WHERE id = $1`
+	_, err := r.db.ExecWithRetry(ctx, r.strategy, query,
+		id, string(status), retryIn.Milliseconds(),
+		attempt.StatusCode, attempt.Body, attempt.Error, attempt.Duration.Milliseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(
+	ctx context.Context,
+	filter *domain.WebhookDeliveryFilter,
+	limit, offset int,
+) ([]*domain.WebhookDelivery, int64, error) {
+	const op = "WebhookRepository.ListDeliveries"
+
+	conditions := []string{"d.webhook_id = $1"}
+	args := []interface{}{filter.WebhookID}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("d.status = $%d", len(args)))
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, `SELECT COUNT(*) FROM webhook_deliveries d`+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s - count: %w", op, err)
+	}
+	if err = row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s - scan count: %w", op, err)
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries d%s
+			  ORDER BY d.created_at DESC, d.id
+			  LIMIT $%d OFFSET $%d`, webhookDeliveryColumns, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryWithRetry(ctx, r.strategy, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []*domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s - scan delivery: %w", op, err)
+		}
+		res = append(res, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, total, nil
+}
+
+// Redeliver ставит в очередь новую доставку с тем же событием; исходная остаётся в журнале как есть
+func (r *WebhookRepository) Redeliver(ctx context.Context, webhookID, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	const op = "WebhookRepository.Redeliver"
+
+	query := `INSERT INTO webhook_deliveries AS d (webhook_id, event_id, event_type, payload, redelivery_of)
+			  SELECT webhook_id, event_id, event_type, payload, id FROM webhook_deliveries
+			  WHERE id = $1 AND webhook_id = $2
+			  RETURNING ` + webhookDeliveryColumns
+
+	row, err := r.db.QueryRowWithRetry(ctx, r.strategy, query, id, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	d, err := scanWebhookDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s - scan delivery: %w", op, err)
+	}
+	return d, nil
+}
+
+// DeleteFinished удаляет завершённые доставки, созданные раньше before
+func (r *WebhookRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	const op = "WebhookRepository.DeleteFinished"
+
+	query := `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`
+	res, err := r.db.ExecWithRetry(ctx, r.strategy, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	var (
+		hook       domain.Webhook
+		eventTypes []string
+	)
+	if err := row.Scan(
+		&hook.ID, &hook.URL, pq.Array(&eventTypes), &hook.Secret, &hook.Active,
+		&hook.CreatedBy, &hook.CreatedAt, &hook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	hook.EventTypes = make([]domain.OutboxEventType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		hook.EventTypes = append(hook.EventTypes, domain.OutboxEventType(t))
+	}
+	return &hook, nil
+}
+
+// scanWebhookDelivery читает webhookDeliveryColumns; extra - столбцы после них
+func scanWebhookDelivery(row rowScanner, extra ...any) (*domain.WebhookDelivery, error) {
+	var (
+		d          domain.WebhookDelivery
+		eventType  string
+		status     string
+		payload    []byte
+		durationMS int64
+		nextAt     sql.NullTime
+		deliveryAt sql.NullTime
+	)
+	dest := []any{
+		&d.ID, &d.WebhookID, &d.EventID, &eventType, &payload, &status, &d.Attempts,
+		&nextAt, &d.ResponseStatus, &d.ResponseBody, &d.Error,
+		&durationMS, &d.RedeliveryOf, &d.CreatedAt, &deliveryAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	d.EventType = domain.OutboxEventType(eventType)
+	d.Status = domain.WebhookDeliveryStatus(status)
+	d.Payload = payload
+	d.Duration = time.Duration(durationMS) * time.Millisecond
+	if nextAt.Valid {
+		d.NextAttemptAt = &nextAt.Time
+	}
+	if deliveryAt.Valid {
+		d.DeliveredAt = &deliveryAt.Time
+	}
+	return &d, nil
+}
+
+// eventTypeStrings - типы событий для pq.Array; nil остаётся nil (NULL в запросе)
+func eventTypeStrings(types []domain.OutboxEventType) []string {
+	if types == nil {
+		return nil
+	}
+	res := make([]string, 0, len(types))
+	for _, t := range types {
+		res = append(res, string(t))
+	}
+	return res
+}
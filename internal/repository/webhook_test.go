@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewWebhookRepository(db, retry.Strategy{Attempts: 1})
+
+	// очередь доставок общая для тестовой базы - подписки прошлых запусков удаляются вместе с доставками
+	_, err := db.Master.ExecContext(ctx, `DELETE FROM webhooks`)
+	require.NoError(t, err)
+
+	hook, err := repo.Create(ctx, &domain.Webhook{
+		URL:        "https://example.com/hook",
+		EventTypes: []domain.OutboxEventType{domain.OutboxItemLowStock},
+		Secret:     "receiver-signing-key",
+		Active:     true,
+		CreatedBy:  uuid.New(),
+	})
+	require.NoError(t, err)
+	other, err := repo.Create(ctx, &domain.Webhook{
+		URL:        "https://example.com/other",
+		EventTypes: []domain.OutboxEventType{domain.OutboxItemCreated},
+		Secret:     "receiver-signing-key",
+		Active:     true,
+		CreatedBy:  uuid.New(),
+	})
+	require.NoError(t, err)
+
+	ev := &domain.OutboxEvent{
+		EventID:       uuid.New(),
+		AggregateType: domain.OutboxAggregateItem,
+		AggregateID:   uuid.New(),
+		Type:          domain.OutboxItemLowStock,
+	}
+	n, err := repo.Enqueue(ctx, ev, []byte(`{"type":"item.low_stock"}`))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n, "only the subscribed webhook gets a delivery")
+
+	// повторная публикация того же события релеем не задваивает доставку
+	n, err = repo.Enqueue(ctx, ev, []byte(`{"type":"item.low_stock"}`))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	claimed, err := repo.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	d := claimed[0]
+	assert.Equal(t, hook.ID, d.WebhookID)
+	assert.Equal(t, hook.URL, d.Webhook.URL)
+	assert.Equal(t, hook.Secret, d.Webhook.Secret)
+
+	// выданная доставка закреплена за отправителем на время lease
+	claimed, err = repo.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.NoError(t, repo.SaveAttempt(ctx, d.ID, &domain.WebhookAttempt{
+		StatusCode: 503,
+		Body:       "maintenance",
+		Error:      "receiver responded 503 Service Unavailable",
+		Duration:   120 * time.Millisecond,
+	}, domain.WebhookDeliveryFailed, 0))
+
+	list, total, err := repo.ListDeliveries(ctx, &domain.WebhookDeliveryFilter{
+		WebhookID: hook.ID,
+		Status:    domain.WebhookDeliveryFailed,
+	}, 10, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	assert.Equal(t, 1, list[0].Attempts)
+	assert.Equal(t, 503, list[0].ResponseStatus)
+	assert.Equal(t, "maintenance", list[0].ResponseBody)
+	assert.Equal(t, 120*time.Millisecond, list[0].Duration)
+	assert.Nil(t, list[0].DeliveredAt)
+
+	redelivery, err := repo.Redeliver(ctx, hook.ID, d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryPending, redelivery.Status)
+	assert.Equal(t, d.ID, *redelivery.RedeliveryOf)
+	assert.Equal(t, ev.EventID, redelivery.EventID)
+
+	_, err = repo.Redeliver(ctx, other.ID, d.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	inactive := false
+	_, err = repo.Update(ctx, hook.ID, &domain.UpdateWebhookInput{Active: &inactive})
+	require.NoError(t, err)
+	claimed, err = repo.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "deliveries of a disabled webhook wait until it is enabled")
+
+	deleted, err := repo.DeleteFinished(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted, "pending redelivery is kept")
+
+	require.NoError(t, repo.Delete(ctx, hook.ID))
+	assert.ErrorIs(t, repo.Delete(ctx, hook.ID), domain.ErrNotFound)
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/stpnv0/WarehouseControl/internal/domain"
@@ -10,53 +11,150 @@ import (
 
 type AuthHandler interface {
 	Login(c *ginext.Context)
+	Refresh(c *ginext.Context)
+	Logout(c *ginext.Context)
 	ListUsers(c *ginext.Context)
+	Me(c *ginext.Context)
+	ChangePassword(c *ginext.Context)
+}
+
+type OIDCHandler interface {
+	Provider(c *ginext.Context)
+	Login(c *ginext.Context)
+	Callback(c *ginext.Context)
 }
 
 type AuditHandler interface {
 	GetByItemID(c *ginext.Context)
 	List(c *ginext.Context)
-	ExportCSV(c *ginext.Context)
+	Export(c *ginext.Context)
+	Verify(c *ginext.Context)
+	Stats(c *ginext.Context)
 }
 type ItemHandler interface {
 	Create(c *ginext.Context)
 	List(c *ginext.Context)
 	GetByID(c *ginext.Context)
 	Update(c *ginext.Context)
+	AdjustQuantity(c *ginext.Context)
 	Delete(c *ginext.Context)
+	Batch(c *ginext.Context)
+	Import(c *ginext.Context)
+	Export(c *ginext.Context)
+}
+
+type ExportJobHandler interface {
+	Create(c *ginext.Context)
+	Get(c *ginext.Context)
+	Download(c *ginext.Context)
+}
+
+type AuditArchiveHandler interface {
+	List(c *ginext.Context)
+	Attach(c *ginext.Context)
+}
+
+type EventHandler interface {
+	Stream(c *ginext.Context)
+}
+
+type PresenceHandler interface {
+	Connect(c *ginext.Context)
+}
+
+type WebhookHandler interface {
+	Create(c *ginext.Context)
+	List(c *ginext.Context)
+	Get(c *ginext.Context)
+	Update(c *ginext.Context)
+	Delete(c *ginext.Context)
+	ListDeliveries(c *ginext.Context)
+	Redeliver(c *ginext.Context)
+}
+
+type UserHandler interface {
+	List(c *ginext.Context)
+	Get(c *ginext.Context)
+	Create(c *ginext.Context)
+	UpdateRole(c *ginext.Context)
+	ResetPassword(c *ginext.Context)
+	Disable(c *ginext.Context)
+	Enable(c *ginext.Context)
+	Delete(c *ginext.Context)
+}
+
+type APIKeyHandler interface {
+	Create(c *ginext.Context)
+	List(c *ginext.Context)
+	Get(c *ginext.Context)
+	Update(c *ginext.Context)
+	Delete(c *ginext.Context)
+}
+
+type JWKSHandler interface {
+	Keys(c *ginext.Context)
 }
 
 type TokenValidator interface {
 	Validate(tokenStr string) (*domain.AuthClaims, error)
 }
 
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*domain.AuthClaims, error)
+}
+
 func InitRouter(
 	mode string,
 	authHandler AuthHandler,
+	oidcHandler OIDCHandler,
 	auditHandler AuditHandler,
 	itemHandler ItemHandler,
+	exportJobHandler ExportJobHandler,
+	auditArchiveHandler AuditArchiveHandler,
+	eventHandler EventHandler,
+	presenceHandler PresenceHandler,
+	webhookHandler WebhookHandler,
+	userHandler UserHandler,
+	apiKeyHandler APIKeyHandler,
+	jwksHandler JWKSHandler,
 	tokenValidator TokenValidator,
+	apiKeyValidator APIKeyValidator,
 	mw ...ginext.HandlerFunc,
 ) *ginext.Engine {
 	router := ginext.New(mode)
 	router.Use(ginext.Recovery())
 	router.Use(mw...)
 
+	router.GET("/.well-known/jwks.json", jwksHandler.Keys)
+
 	auth := router.Group("/api/auth")
 	{
 		auth.POST("/login", authHandler.Login)
-		auth.GET("/users", authHandler.ListUsers)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.GET("/oidc", oidcHandler.Provider)
+		auth.POST("/oidc/login", oidcHandler.Login)
+		auth.POST("/oidc/callback", oidcHandler.Callback)
+
+		session := auth.Group("", middleware.Auth(tokenValidator, apiKeyValidator))
+		session.POST("/logout", authHandler.Logout)
+		session.GET("/users", authHandler.ListUsers)
+		session.GET("/me", authHandler.Me)
+		session.PUT("/me/password", authHandler.ChangePassword)
 	}
 
 	api := router.Group("/api")
-	api.Use(middleware.Auth(tokenValidator))
+	api.Use(middleware.Auth(tokenValidator, apiKeyValidator))
 	{
 		items := api.Group("/items")
 		{
 			items.GET("", itemHandler.List)
 			items.POST("", itemHandler.Create)
+			items.POST("/batch", itemHandler.Batch)
+			items.POST("/import", itemHandler.Import)
+			items.GET("/export", itemHandler.Export)
 			items.GET("/:id", itemHandler.GetByID)
 			items.PUT("/:id", itemHandler.Update)
+			items.POST("/:id/adjust", itemHandler.AdjustQuantity)
 			items.DELETE("/:id", itemHandler.Delete)
 
 			items.GET("/:id/audit", auditHandler.GetByItemID)
@@ -65,8 +163,54 @@ func InitRouter(
 		audit := api.Group("/audit")
 		{
 			audit.GET("", auditHandler.List)
-			audit.GET("/export", auditHandler.ExportCSV)
+			audit.GET("/export", auditHandler.Export)
+			audit.GET("/verify", auditHandler.Verify)
+			audit.GET("/stats", auditHandler.Stats)
+			audit.GET("/archives", auditArchiveHandler.List)
+			audit.POST("/archives/:id/attach", auditArchiveHandler.Attach)
+		}
+
+		exports := api.Group("/exports")
+		{
+			exports.POST("", exportJobHandler.Create)
+			exports.GET("/:id", exportJobHandler.Get)
+			exports.GET("/:id/download", exportJobHandler.Download)
+		}
+
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.GET("", webhookHandler.List)
+			webhooks.POST("", webhookHandler.Create)
+			webhooks.GET("/:id", webhookHandler.Get)
+			webhooks.PATCH("/:id", webhookHandler.Update)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+		}
+
+		users := api.Group("/users")
+		{
+			users.GET("", userHandler.List)
+			users.POST("", userHandler.Create)
+			users.GET("/:id", userHandler.Get)
+			users.PUT("/:id/role", userHandler.UpdateRole)
+			users.PUT("/:id/password", userHandler.ResetPassword)
+			users.POST("/:id/disable", userHandler.Disable)
+			users.POST("/:id/enable", userHandler.Enable)
+			users.DELETE("/:id", userHandler.Delete)
+		}
+
+		apiKeys := api.Group("/api-keys")
+		{
+			apiKeys.GET("", apiKeyHandler.List)
+			apiKeys.POST("", apiKeyHandler.Create)
+			apiKeys.GET("/:id", apiKeyHandler.Get)
+			apiKeys.PATCH("/:id", apiKeyHandler.Update)
+			apiKeys.DELETE("/:id", apiKeyHandler.Delete)
 		}
+
+		api.GET("/events", eventHandler.Stream)
+		api.GET("/ws/presence", presenceHandler.Connect)
 	}
 
 	router.GET("/health", func(c *ginext.Context) {
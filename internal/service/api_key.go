@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+const (
+	// ключ: wck_<prefix>_<secret>; wck_<prefix> показывается в списке ключей
+	apiKeyScheme      = "wck_"
+	apiKeyPrefixBytes = 4
+	apiKeySecretBytes = 32
+	apiKeyNameMaxLen  = 128
+	// last_used_at обновляется не чаще раза в минуту, а не на каждый запрос
+	apiKeyLastUsedResolution = time.Minute
+)
+
+type apiKeyOwnerRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+}
+
+type apiKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	List(ctx context.Context) ([]*domain.APIKey, error)
+	Update(ctx context.Context, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// APIKeyService - ключи интеграций: управление администратором и проверка ключа из X-API-Key.
+// Ключ действует от имени владельца - его изменения пишутся в журнал аудита как изменения владельца.
+// Права ключа не больше текущей роли владельца; отключённый владелец - ключ не принимается.
+// Ключами нельзя управлять по ключу: только после входа администратора.
+type APIKeyService struct {
+	repo  apiKeyRepository
+	users apiKeyOwnerRepository
+	log   logger.Logger
+	now   func() time.Time
+}
+
+func NewAPIKeyService(repo apiKeyRepository, users apiKeyOwnerRepository, log logger.Logger) *APIKeyService {
+	return &APIKeyService{
+		repo:  repo,
+		users: users,
+		log:   log.With("component", "APIKeyService"),
+		now:   time.Now,
+	}
+}
+
+// Create выпускает ключ; сам ключ (Key) возвращается только здесь
+func (s *APIKeyService) Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateAPIKeyInput) (*domain.APIKey, error) {
+	const op = "APIKeyService.Create"
+
+	if err := s.checkAccess(claims); err != nil {
+		return nil, err
+	}
+	if err := validateAPIKeyName(input.Name); err != nil {
+		return nil, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(s.now()) {
+		return nil, &domain.ValidationError{Field: "expires_at", Reason: "must be in the future"}
+	}
+
+	ownerID := claims.UserID
+	if input.UserID != nil {
+		ownerID = *input.UserID
+	}
+	owner, err := s.users.GetByID(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ValidationError{Field: "user_id", Reason: "user not found"}
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if owner.Disabled() {
+		return nil, &domain.ValidationError{Field: "user_id", Reason: "user is disabled"}
+	}
+
+	role := input.Role
+	if role == "" {
+		role = owner.Role
+	}
+	if err = validateAPIKeyRole(role, owner.Role); err != nil {
+		return nil, err
+	}
+
+	prefix, secret, err := newAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := s.repo.Create(ctx, &domain.APIKey{
+		Name:      input.Name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(secret),
+		UserID:    owner.ID,
+		Role:      role,
+		ReadOnly:  input.ReadOnly,
+		ExpiresAt: input.ExpiresAt,
+		CreatedBy: claims.UserID,
+	})
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to create api key",
+			"error", err,
+			"user_id", claims.UserID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("api key created",
+		"user_id", claims.UserID,
+		"api_key_id", key.ID,
+		"owner_id", key.UserID,
+		"role", key.Role,
+		"read_only", key.ReadOnly,
+	)
+	key.Key = secret
+	return key, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.APIKey, error) {
+	const op = "APIKeyService.List"
+
+	if err := s.checkAccess(claims); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to list api keys",
+			"error", err,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.APIKey, error) {
+	const op = "APIKeyService.Get"
+
+	if err := s.checkAccess(claims); err != nil {
+		return nil, err
+	}
+
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to get api key",
+			"error", err,
+			"api_key_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) Update(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	id uuid.UUID,
+	input *domain.UpdateAPIKeyInput,
+) (*domain.APIKey, error) {
+	const op = "APIKeyService.Update"
+
+	if err := s.checkAccess(claims); err != nil {
+		return nil, err
+	}
+	if input.Name == nil && input.Role == nil && input.ReadOnly == nil && input.ExpiresAt == nil {
+		return nil, domain.ErrNoChanges
+	}
+	if input.Name != nil {
+		if err := validateAPIKeyName(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(s.now()) {
+		return nil, &domain.ValidationError{Field: "expires_at", Reason: "must be in the future"}
+	}
+	if input.Role != nil {
+		key, err := s.Get(ctx, claims, id)
+		if err != nil {
+			return nil, err
+		}
+		owner, err := s.users.GetByID(ctx, key.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err = validateAPIKeyRole(*input.Role, owner.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := s.repo.Update(ctx, id, input)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to update api key",
+			"error", err,
+			"api_key_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return key, nil
+}
+
+// Delete отзывает ключ
+func (s *APIKeyService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+	const op = "APIKeyService.Delete"
+
+	if err := s.checkAccess(claims); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to delete api key",
+			"error", err,
+			"api_key_id", id,
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("api key revoked",
+		"user_id", claims.UserID,
+		"api_key_id", id,
+	)
+	return nil
+}
+
+// ValidateAPIKey проверяет ключ из X-API-Key и возвращает права запроса:
+// владелец, меньшая из ролей ключа и владельца, признак только чтения
+func (s *APIKeyService) ValidateAPIKey(ctx context.Context, secret string) (*domain.AuthClaims, error) {
+	if !strings.HasPrefix(secret, apiKeyScheme) {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	key, err := s.repo.GetByHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.log.Ctx(ctx).Error("failed to get api key",
+				"error", err,
+			)
+		}
+		return nil, domain.ErrTokenInvalid
+	}
+
+	now := s.now()
+	if key.Expired(now) || key.Owner.Disabled() {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedResolution {
+		// неудача записи не должна отклонять запрос
+		if err = s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			s.log.Ctx(ctx).Warn("failed to update api key last use",
+				"error", err,
+				"api_key_id", key.ID,
+			)
+		}
+	}
+
+	return &domain.AuthClaims{
+		UserID:   key.UserID,
+		Username: key.Owner.Username,
+		Role:     key.Role.Min(key.Owner.Role),
+		APIKeyID: key.ID,
+		ReadOnly: key.ReadOnly,
+	}, nil
+}
+
+func (s *APIKeyService) checkAccess(claims *domain.AuthClaims) error {
+	if !claims.Role.CanManageAPIKeys() || claims.ViaAPIKey() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func validateAPIKeyName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return &domain.ValidationError{Field: "name", Reason: "is required"}
+	}
+	if utf8.RuneCountInString(name) > apiKeyNameMaxLen {
+		return &domain.ValidationError{Field: "name", Reason: fmt.Sprintf("must be at most %d characters", apiKeyNameMaxLen)}
+	}
+	return nil
+}
+
+// validateAPIKeyRole - ключ не может давать больше прав, чем у владельца
+func validateAPIKeyRole(role, ownerRole domain.Role) error {
+	if !role.IsValid() {
+		return &domain.ValidationError{Field: "role", Reason: "must be admin, manager or viewer"}
+	}
+	if !ownerRole.Includes(role) {
+		return &domain.ValidationError{Field: "role", Reason: "must not exceed the owner's role"}
+	}
+	return nil
+}
+
+// newAPIKey - открытый префикс и полный ключ, который начинается с него
+func newAPIKey() (prefix, key string, err error) {
+	p := make([]byte, apiKeyPrefixBytes)
+	if _, err = rand.Read(p); err != nil {
+		return "", "", fmt.Errorf("generate api key: %w", err)
+	}
+	b := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate api key: %w", err)
+	}
+	prefix = apiKeyScheme + hex.EncodeToString(p)
+	return prefix, prefix + "_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashAPIKey - у ключа 256 бит случайности, перебор невозможен: медленный хеш не нужен
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPIKeyService(t *testing.T) (*APIKeyService, *mockapiKeyRepository, *mockapiKeyOwnerRepository) {
+	repo := newMockapiKeyRepository(t)
+	users := newMockapiKeyOwnerRepository(t)
+	return NewAPIKeyService(repo, users, newTestLogger()), repo, users
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	svc, repo, users := newAPIKeyService(t)
+	owner := &domain.User{ID: uuid.New(), Username: "erp-sync", Role: domain.RoleManager}
+
+	users.EXPECT().GetByID(mock.Anything, owner.ID).Return(owner, nil)
+
+	var stored *domain.APIKey
+	repo.EXPECT().Create(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, k *domain.APIKey) (*domain.APIKey, error) {
+			stored = k
+			res := *k
+			res.ID = uuid.New()
+			return &res, nil
+		})
+
+	key, err := svc.Create(context.Background(), adminClaims, &domain.CreateAPIKeyInput{
+		Name:     "ERP sync",
+		UserID:   &owner.ID,
+		ReadOnly: true,
+	})
+
+	require.NoError(t, err)
+	// без role ключ получает роль владельца; создатель - администратор
+	assert.Equal(t, domain.RoleManager, stored.Role)
+	assert.Equal(t, owner.ID, stored.UserID)
+	assert.Equal(t, adminClaims.UserID, stored.CreatedBy)
+	assert.True(t, stored.ReadOnly)
+
+	// в БД - только хеш, сам ключ отдаётся один раз и начинается с префикса
+	assert.Contains(t, key.Key, stored.Prefix+"_")
+	assert.Equal(t, hashAPIKey(key.Key), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, key.Key)
+}
+
+func TestAPIKeyService_Create_Validation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	viewer := &domain.User{ID: uuid.New(), Role: domain.RoleViewer}
+	disabledAt := time.Now()
+	disabled := &domain.User{ID: uuid.New(), Role: domain.RoleManager, DisabledAt: &disabledAt}
+
+	tests := []struct {
+		name    string
+		claims  *domain.AuthClaims
+		input   *domain.CreateAPIKeyInput
+		owner   *domain.User
+		field   string
+		wantErr error
+	}{
+		{
+			name:    "manager forbidden",
+			claims:  managerClaims,
+			input:   &domain.CreateAPIKeyInput{Name: "key"},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name:    "via api key forbidden",
+			claims:  &domain.AuthClaims{UserID: uuid.New(), Role: domain.RoleAdmin, APIKeyID: uuid.New()},
+			input:   &domain.CreateAPIKeyInput{Name: "key"},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name:   "empty name",
+			claims: adminClaims,
+			input:  &domain.CreateAPIKeyInput{Name: "  "},
+			field:  "name",
+		},
+		{
+			name:   "expired",
+			claims: adminClaims,
+			input:  &domain.CreateAPIKeyInput{Name: "key", ExpiresAt: &past},
+			field:  "expires_at",
+		},
+		{
+			name:   "role above owner",
+			claims: adminClaims,
+			input:  &domain.CreateAPIKeyInput{Name: "key", UserID: &viewer.ID, Role: domain.RoleManager},
+			owner:  viewer,
+			field:  "role",
+		},
+		{
+			name:   "disabled owner",
+			claims: adminClaims,
+			input:  &domain.CreateAPIKeyInput{Name: "key", UserID: &disabled.ID},
+			owner:  disabled,
+			field:  "user_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, users := newAPIKeyService(t)
+			if tt.owner != nil {
+				users.EXPECT().GetByID(mock.Anything, tt.owner.ID).Return(tt.owner, nil)
+			}
+
+			_, err := svc.Create(context.Background(), tt.claims, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			assert.Equal(t, tt.field, vErr.Field)
+		})
+	}
+}
+
+func TestAPIKeyService_ValidateAPIKey(t *testing.T) {
+	now := time.Date(2026, 4, 3, 12, 0, 0, 0, time.UTC)
+	_, secret, err := newAPIKey()
+	require.NoError(t, err)
+
+	newKey := func() *domain.APIKey {
+		return &domain.APIKey{
+			ID:       uuid.New(),
+			UserID:   uuid.New(),
+			Role:     domain.RoleAdmin,
+			ReadOnly: true,
+			Owner:    &domain.User{Username: "erp-sync", Role: domain.RoleManager},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		svc, repo, _ := newAPIKeyService(t)
+		svc.now = func() time.Time { return now }
+		key := newKey()
+
+		repo.EXPECT().GetByHash(mock.Anything, hashAPIKey(secret)).Return(key, nil)
+		repo.EXPECT().TouchLastUsed(mock.Anything, key.ID, now).Return(nil)
+
+		claims, err := svc.ValidateAPIKey(context.Background(), secret)
+
+		require.NoError(t, err)
+		assert.Equal(t, key.UserID, claims.UserID)
+		assert.Equal(t, "erp-sync", claims.Username)
+		// владельца понизили после выпуска ключа - права ключа тоже меньше
+		assert.Equal(t, domain.RoleManager, claims.Role)
+		assert.Equal(t, key.ID, claims.APIKeyID)
+		assert.True(t, claims.ReadOnly)
+		assert.True(t, claims.ViaAPIKey())
+	})
+
+	t.Run("recently used key is not touched", func(t *testing.T) {
+		svc, repo, _ := newAPIKeyService(t)
+		svc.now = func() time.Time { return now }
+		key := newKey()
+		lastUsed := now.Add(-apiKeyLastUsedResolution / 2)
+		key.LastUsedAt = &lastUsed
+
+		repo.EXPECT().GetByHash(mock.Anything, hashAPIKey(secret)).Return(key, nil)
+
+		_, err := svc.ValidateAPIKey(context.Background(), secret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		svc, repo, _ := newAPIKeyService(t)
+		svc.now = func() time.Time { return now }
+		key := newKey()
+		key.ExpiresAt = &now
+
+		repo.EXPECT().GetByHash(mock.Anything, hashAPIKey(secret)).Return(key, nil)
+
+		_, err := svc.ValidateAPIKey(context.Background(), secret)
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("disabled owner", func(t *testing.T) {
+		svc, repo, _ := newAPIKeyService(t)
+		key := newKey()
+		key.Owner.DisabledAt = &now
+
+		repo.EXPECT().GetByHash(mock.Anything, hashAPIKey(secret)).Return(key, nil)
+
+		_, err := svc.ValidateAPIKey(context.Background(), secret)
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		svc, repo, _ := newAPIKeyService(t)
+		repo.EXPECT().GetByHash(mock.Anything, mock.Anything).Return(nil, domain.ErrNotFound)
+
+		_, err := svc.ValidateAPIKey(context.Background(), secret)
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		svc, _, _ := newAPIKeyService(t)
+
+		_, err := svc.ValidateAPIKey(context.Background(), "not-a-key")
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+}
+
+func TestAPIKeyService_Update_RoleAboveOwner(t *testing.T) {
+	svc, repo, users := newAPIKeyService(t)
+	key := &domain.APIKey{ID: uuid.New(), UserID: uuid.New(), Role: domain.RoleViewer}
+	role := domain.RoleAdmin
+
+	repo.EXPECT().GetByID(mock.Anything, key.ID).Return(key, nil)
+	users.EXPECT().GetByID(mock.Anything, key.UserID).Return(&domain.User{ID: key.UserID, Role: domain.RoleManager}, nil)
+
+	_, err := svc.Update(context.Background(), adminClaims, key.ID, &domain.UpdateAPIKeyInput{Role: &role})
+
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, "role", vErr.Field)
+}
@@ -4,28 +4,34 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/auditchain"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/wb-go/wbf/logger"
 )
 
-const maxExportRows = 10000
-
 type auditRepository interface {
 	GetByItemID(ctx context.Context, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error)
 	List(ctx context.Context, filter *domain.AuditFilter, limit, offset int) ([]*domain.AuditEntryWithUser, int64, error)
+	Stream(ctx context.Context, filter *domain.AuditFilter, fn func(e *domain.AuditEntryWithUser) error) error
+	StreamChain(ctx context.Context, fn func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error)
+	Stats(ctx context.Context, filter *domain.AuditStatsFilter) (*domain.AuditStats, error)
 }
+
 type AuditService struct {
 	auditRepo auditRepository
 	log       logger.Logger
+	now       func() time.Time
 }
 
 func NewAuditService(auditRepo auditRepository, log logger.Logger) *AuditService {
 	return &AuditService{
 		auditRepo: auditRepo,
 		log:       log.With("component", "AuditService"),
+		now:       time.Now,
 	}
 }
 
@@ -68,7 +74,7 @@ func (s *AuditService) List(
 		return nil, domain.ErrForbidden
 	}
 
-	if filter.Action != nil && !filter.Action.IsValid() {
+	if !filter.IsValid() {
 		return nil, domain.ErrValidation
 	}
 
@@ -96,25 +102,129 @@ func (s *AuditService) List(
 	}, nil
 }
 
-func (s *AuditService) ExportCSV(
+// Export пишет аудит в w в выбранном формате потоком из курсора БД, без ограничения на число строк.
+// Возвращает число выгруженных записей; при ошибке - сколько успело уйти до неё.
+// Если ни одна запись не выгружена, в w ничего не пишется - вызывающий может ответить ошибкой.
+func (s *AuditService) Export(
 	ctx context.Context,
 	claims *domain.AuthClaims,
 	filter *domain.AuditFilter,
+	format export.Format,
 	w io.Writer,
-) error {
-	const op = "AuditService.ExportCSV"
+) (int64, error) {
+	const op = "AuditService.Export"
 
 	if !claims.Role.CanExport() {
-		return domain.ErrForbidden
+		return 0, domain.ErrForbidden
+	}
+
+	if !filter.IsValid() {
+		return 0, domain.ErrValidation
+	}
+
+	aw, err := export.NewAuditWriter(w, format)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var rows int64
+	err = s.auditRepo.Stream(ctx, filter, func(e *domain.AuditEntryWithUser) error {
+		if err := aw.Write(e); err != nil {
+			return err
+		}
+		rows++
+		return nil
+	})
+	if err != nil {
+		s.log.Ctx(ctx).Error("audit export interrupted",
+			"error", err,
+			"format", format,
+			"rows", rows,
+		)
+		if rows > 0 {
+			_ = aw.Close() // отдаём клиенту то, что успели прочитать
+		}
+		return rows, fmt.Errorf("%s: %w", op, err)
 	}
 
-	entries, _, err := s.auditRepo.List(ctx, filter, maxExportRows, 0)
+	if err = aw.Close(); err != nil {
+		return rows, fmt.Errorf("%s: %w", op, err)
+	}
+	return rows, nil
+}
+
+// Verify проходит цепочку хешей аудита целиком и сообщает первое нарушенное звено.
+// anchor - звено, сохранённое вне БД после прошлой проверки, nil - без сверки с ним.
+// Нарушение цепочки - не ошибка: оно возвращается в отчёте.
+func (s *AuditService) Verify(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	anchor *domain.AuditChainAnchor,
+) (*domain.AuditChainReport, error) {
+	const op = "AuditService.Verify"
+
+	if !claims.Role.CanVerifyAudit() {
+		return nil, domain.ErrForbidden
+	}
+
+	report, err := auditchain.Verify(ctx, s.auditRepo, anchor)
 	if err != nil {
-		s.log.Ctx(ctx).Error("failed to fetch audit for CSV export",
+		s.log.Ctx(ctx).Error("failed to verify audit chain",
 			"error", err,
 		)
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !report.Valid {
+		s.log.Ctx(ctx).Warn("audit chain broken",
+			"entry_id", report.Broken.EntryID,
+			"reason", report.Broken.Reason,
+			"checked", report.Checked,
+		)
+	}
+
+	return report, nil
+}
+
+// Stats возвращает агрегаты аудита за период. Без дат берутся последние AuditStatsDefaultDays дней,
+// без DateFrom - AuditStatsDefaultDays дней до DateTo. Период длиннее AuditStatsMaxDays не принимается.
+func (s *AuditService) Stats(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	filter *domain.AuditStatsFilter,
+) (*domain.AuditStats, error) {
+	const op = "AuditService.Stats"
+
+	if !claims.Role.CanViewAudit() {
+		return nil, domain.ErrForbidden
+	}
+
+	f := *filter
+	if f.DateTo.IsZero() {
+		f.DateTo = s.now()
+	}
+	if f.DateFrom.IsZero() {
+		f.DateFrom = f.DateTo.AddDate(0, 0, -domain.AuditStatsDefaultDays)
+	}
+	if f.Limit == 0 {
+		f.Limit = domain.AuditStatsDefaultLimit
+	}
+
+	if !f.DateFrom.Before(f.DateTo) ||
+		f.DateTo.Sub(f.DateFrom) > domain.AuditStatsMaxDays*24*time.Hour ||
+		f.Limit < 1 || f.Limit > domain.AuditStatsMaxLimit {
+		return nil, domain.ErrValidation
+	}
+
+	stats, err := s.auditRepo.Stats(ctx, &f)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to get audit stats",
+			"error", err,
+			"date_from", f.DateFrom,
+			"date_to", f.DateTo,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return export.WriteAuditCSV(w, entries)
+	return stats, nil
 }
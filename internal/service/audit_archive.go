@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/auditarchive"
+	"github.com/stpnv0/WarehouseControl/internal/auditchain"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// auditPartitionsAhead - на сколько месяцев вперёд (включая текущий) заранее создаются секции audit_log
+const auditPartitionsAhead = 2
+
+type auditArchiveRepository interface {
+	TryLock(ctx context.Context) (unlock func(), ok bool, err error)
+	EnsurePartition(ctx context.Context, month time.Time) error
+	DefaultPartitionMonths(ctx context.Context) ([]time.Time, error)
+	ListPartitions(ctx context.Context) ([]*domain.AuditPartition, error)
+	StreamPartition(ctx context.Context, name string, fn func(l *domain.AuditChainLink) error) error
+	Detach(ctx context.Context, p *domain.AuditPartition, archive *domain.AuditArchive) error
+	List(ctx context.Context) ([]*domain.AuditArchive, error)
+	GetByID(ctx context.Context, id int64) (*domain.AuditArchive, error)
+	Attach(
+		ctx context.Context,
+		archive *domain.AuditArchive,
+		userID uuid.UUID,
+		next func() (*domain.AuditChainLink, error),
+	) error
+}
+
+// AuditArchiveOptions - хранение журнала аудита в БД
+type AuditArchiveOptions struct {
+	Dir             string        // каталог файлов архива
+	RetentionMonths int           // сколько полных месяцев журнала держать в БД; 0 - не архивировать
+	Interval        time.Duration // как часто проверять секции
+	ReattachHold    time.Duration // сколько возвращённая из архива секция не архивируется повторно
+}
+
+// AuditArchiveService обслуживает месячные секции audit_log: заранее создаёт новые,
+// выгружает старше срока хранения в файлы и отсоединяет, возвращает архивы в БД по запросу.
+type AuditArchiveService struct {
+	repo auditArchiveRepository
+	opts AuditArchiveOptions
+	log  logger.Logger
+	now  func() time.Time
+}
+
+func NewAuditArchiveService(repo auditArchiveRepository, opts AuditArchiveOptions, log logger.Logger) *AuditArchiveService {
+	return &AuditArchiveService{
+		repo: repo,
+		opts: opts,
+		log:  log.With("component", "AuditArchiveService"),
+		now:  time.Now,
+	}
+}
+
+// Run обслуживает секции раз в opts.Interval; блокируется до отмены ctx
+func (s *AuditArchiveService) Run(ctx context.Context) error {
+	if err := os.MkdirAll(s.opts.Dir, 0o750); err != nil {
+		return fmt.Errorf("create audit archive dir: %w", err)
+	}
+
+	interval := s.opts.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.maintain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// maintain - один проход: секции на будущие месяцы, перенос записей из audit_log_default и архивация старых.
+// Проход идёт под advisory lock, при нескольких экземплярах приложения его выполняет один.
+func (s *AuditArchiveService) maintain(ctx context.Context) {
+	log := s.log.Ctx(ctx)
+
+	unlock, ok, err := s.repo.TryLock(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to lock audit partitions",
+				"error", err,
+			)
+		}
+		return
+	}
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	month := startOfMonth(s.now())
+	for i := 0; i < auditPartitionsAhead; i++ {
+		if err = s.repo.EnsurePartition(ctx, month.AddDate(0, i, 0)); err != nil {
+			log.Error("failed to create audit partition",
+				"error", err,
+				"month", month.AddDate(0, i, 0).Format("2006-01"),
+			)
+		}
+	}
+	s.repartitionDefault(ctx)
+
+	if s.opts.RetentionMonths <= 0 {
+		return
+	}
+	cutoff := month.AddDate(0, -s.opts.RetentionMonths, 0)
+
+	partitions, err := s.repo.ListPartitions(ctx)
+	if err != nil {
+		log.Error("failed to list audit partitions",
+			"error", err,
+		)
+		return
+	}
+	archives, err := s.repo.List(ctx)
+	if err != nil {
+		log.Error("failed to list audit archives",
+			"error", err,
+		)
+		return
+	}
+	byPartition := make(map[string]*domain.AuditArchive, len(archives))
+	for _, a := range archives {
+		byPartition[a.Partition] = a
+	}
+
+	for _, p := range partitions {
+		if ctx.Err() != nil {
+			return
+		}
+		if p.To.After(cutoff) {
+			continue
+		}
+		if a := byPartition[p.Name]; a != nil && a.Status == domain.AuditArchiveAttached &&
+			a.AttachedAt != nil && s.now().Before(a.AttachedAt.Add(s.opts.ReattachHold)) {
+			continue
+		}
+
+		if err = s.archive(ctx, p); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Error("failed to archive audit partition",
+				"error", err,
+				"partition", p.Name,
+			)
+		}
+	}
+}
+
+// repartitionDefault создаёт секции за месяцы, записи которых попали в audit_log_default:
+// EnsurePartition переносит их туда, и дальше они архивируются вместе с секцией
+func (s *AuditArchiveService) repartitionDefault(ctx context.Context) {
+	log := s.log.Ctx(ctx)
+
+	months, err := s.repo.DefaultPartitionMonths(ctx)
+	if err != nil {
+		log.Error("failed to list audit default partition months",
+			"error", err,
+		)
+		return
+	}
+	for _, month := range months {
+		if err = s.repo.EnsurePartition(ctx, month); err != nil {
+			log.Error("failed to move audit records out of default partition",
+				"error", err,
+				"month", month.Format("2006-01"),
+			)
+			continue
+		}
+		log.Warn("audit records moved out of default partition",
+			"month", month.Format("2006-01"),
+		)
+	}
+}
+
+// archive выгружает секцию в файл и отсоединяет её. Файл пишется целиком и сбрасывается на диск
+// до удаления секции; записи с неверным хешем секцию не выпускают из БД.
+func (s *AuditArchiveService) archive(ctx context.Context, p *domain.AuditPartition) error {
+	fileName := p.Name + auditarchive.Extension
+	path := filepath.Join(s.opts.Dir, fileName)
+	tmp := path + ".part"
+
+	segs, sum, err := s.writeArchive(ctx, p, tmp)
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if segs.Rows() == 0 {
+		_ = os.Remove(path)
+		if err = s.repo.Detach(ctx, p, nil); err != nil {
+			return err
+		}
+		s.log.Ctx(ctx).Info("empty audit partition dropped",
+			"partition", p.Name,
+		)
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat archive: %w", err)
+	}
+
+	archive := &domain.AuditArchive{
+		Partition:  p.Name,
+		RangeFrom:  p.From,
+		RangeTo:    p.To,
+		FileName:   fileName,
+		FileSize:   info.Size(),
+		FileSHA256: sum,
+		Rows:       segs.Rows(),
+		Segments:   segs.List(),
+	}
+	if err = s.repo.Detach(ctx, p, archive); err != nil {
+		return err
+	}
+
+	s.log.Ctx(ctx).Info("audit partition archived",
+		"partition", p.Name,
+		"rows", archive.Rows,
+		"file", fileName,
+		"size", archive.FileSize,
+	)
+	return nil
+}
+
+func (s *AuditArchiveService) writeArchive(
+	ctx context.Context,
+	p *domain.AuditPartition,
+	path string,
+) (*auditchain.Segments, string, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("create file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	aw := auditarchive.NewWriter(io.MultiWriter(f, h))
+	segs := &auditchain.Segments{}
+
+	err = s.repo.StreamPartition(ctx, p.Name, func(l *domain.AuditChainLink) error {
+		if b := segs.Add(l); b != nil {
+			return fmt.Errorf("audit entry %d: %s", b.EntryID, b.Reason)
+		}
+		return aw.Write(l)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = aw.Close(); err != nil {
+		return nil, "", err
+	}
+	if err = f.Sync(); err != nil {
+		return nil, "", fmt.Errorf("sync file: %w", err)
+	}
+	return segs, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// List - архивы секций аудита
+func (s *AuditArchiveService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.AuditArchive, error) {
+	const op = "AuditArchiveService.List"
+
+	if !claims.Role.CanManageAuditArchive() {
+		return nil, domain.ErrForbidden
+	}
+
+	archives, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to list audit archives",
+			"error", err,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if archives == nil {
+		archives = []*domain.AuditArchive{}
+	}
+	return archives, nil
+}
+
+// Attach возвращает архив в БД отдельной секцией audit_log. Файл сверяется с контрольной суммой,
+// а его записи - с хешами и отрезками цепочки, сохранёнными при архивации.
+// Возвращённая секция не архивируется повторно в течение opts.ReattachHold.
+func (s *AuditArchiveService) Attach(ctx context.Context, claims *domain.AuthClaims, id int64) (*domain.AuditArchive, error) {
+	const op = "AuditArchiveService.Attach"
+
+	if !claims.Role.CanManageAuditArchive() {
+		return nil, domain.ErrForbidden
+	}
+
+	archive, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if archive.Status == domain.AuditArchiveAttached {
+		return nil, domain.ErrAuditArchiveAttached
+	}
+
+	path := filepath.Join(s.opts.Dir, archive.FileName)
+	if err = checkFileSHA256(path, archive.FileSHA256); err != nil {
+		s.log.Ctx(ctx).Error("audit archive file check failed",
+			"error", err,
+			"archive_id", id,
+			"file", archive.FileName,
+		)
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrAuditArchiveCorrupted)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrAuditArchiveCorrupted)
+	}
+	defer f.Close()
+
+	ar, err := auditarchive.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrAuditArchiveCorrupted)
+	}
+	defer ar.Close()
+
+	segs := &auditchain.Segments{}
+	next := func() (*domain.AuditChainLink, error) {
+		l, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			if !slices.Equal(segs.List(), archive.Segments) {
+				return nil, fmt.Errorf("chain segments differ from archive record: %w", domain.ErrAuditArchiveCorrupted)
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrAuditArchiveCorrupted, err)
+		}
+		if b := segs.Add(l); b != nil {
+			return nil, fmt.Errorf("audit entry %d: %s: %w", b.EntryID, b.Reason, domain.ErrAuditArchiveCorrupted)
+		}
+		return l, nil
+	}
+
+	if err = s.repo.Attach(ctx, archive, claims.UserID, next); err != nil {
+		s.log.Ctx(ctx).Error("failed to attach audit archive",
+			"error", err,
+			"archive_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("audit archive attached",
+		"archive_id", id,
+		"partition", archive.Partition,
+		"rows", archive.Rows,
+	)
+
+	attached, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return attached, nil
+}
+
+func checkFileSHA256(path, want string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("sha256 %s, expected %s", got, want)
+	}
+	return nil
+}
+
+// startOfMonth - начало месяца t по UTC: границы секций audit_log считаются в UTC
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
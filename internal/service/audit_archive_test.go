@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/auditarchive"
+	"github.com/stpnv0/WarehouseControl/internal/auditchain"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuditArchiveService(t *testing.T, retention int) (*AuditArchiveService, *mockauditArchiveRepository) {
+	repo := newMockauditArchiveRepository(t)
+	svc := NewAuditArchiveService(repo, AuditArchiveOptions{
+		Dir:             t.TempDir(),
+		RetentionMonths: retention,
+		ReattachHold:    24 * time.Hour,
+	}, newTestLogger())
+	svc.now = func() time.Time { return time.Date(2026, 5, 17, 12, 0, 0, 0, time.UTC) }
+	return svc, repo
+}
+
+// auditLinks - корректная цепочка из n записей, как её строит fn_audit_chain()
+func auditLinks(n int) []*domain.AuditChainLink {
+	links := make([]*domain.AuditChainLink, 0, n)
+	prev := domain.AuditChainGenesis
+	for i := 0; i < n; i++ {
+		l := &domain.AuditChainLink{
+			AuditEntry: domain.AuditEntry{
+				ID:         int64(i + 1),
+				EntityType: domain.AuditEntityItem,
+				EntityID:   uuid.New(),
+				Action:     domain.AuditInsert,
+				ChangedBy:  adminClaims.UserID,
+				NewData:    json.RawMessage(`{"quantity": 5}`),
+				ChangedAt:  time.Date(2026, 1, 10, 9, 0, i, 0, time.UTC),
+			},
+			PrevHash: prev,
+		}
+		l.Hash = auditchain.Hash(prev, &l.AuditEntry)
+		prev = l.Hash
+		links = append(links, l)
+	}
+	return links
+}
+
+func expectAuditMaintenance(repo *mockauditArchiveRepository) {
+	repo.EXPECT().TryLock(mock.Anything).Return(func() {}, true, nil)
+	repo.EXPECT().EnsurePartition(mock.Anything, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)).Return(nil)
+	repo.EXPECT().EnsurePartition(mock.Anything, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)).Return(nil)
+	repo.EXPECT().DefaultPartitionMonths(mock.Anything).Return(nil, nil)
+}
+
+func TestAuditArchiveService_Maintain_NoRetention(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 0)
+	expectAuditMaintenance(repo)
+
+	svc.maintain(context.Background())
+}
+
+func TestAuditArchiveService_Maintain_RepartitionsDefault(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 0)
+	repo.EXPECT().TryLock(mock.Anything).Return(func() {}, true, nil)
+	repo.EXPECT().EnsurePartition(mock.Anything, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)).Return(nil)
+	repo.EXPECT().EnsurePartition(mock.Anything, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)).Return(nil)
+
+	// записи за март попали в секцию по умолчанию; ошибка по одному месяцу не мешает остальным
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().DefaultPartitionMonths(mock.Anything).Return([]time.Time{march, april}, nil)
+	repo.EXPECT().EnsurePartition(mock.Anything, march).Return(errors.New("lock timeout"))
+	repo.EXPECT().EnsurePartition(mock.Anything, april).Return(nil)
+
+	svc.maintain(context.Background())
+}
+
+func TestAuditArchiveService_Maintain_Locked(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 3)
+	repo.EXPECT().TryLock(mock.Anything).Return(nil, false, nil)
+
+	svc.maintain(context.Background())
+}
+
+func TestAuditArchiveService_Maintain_ArchivesOldPartitions(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 3)
+	expectAuditMaintenance(repo)
+
+	jan := &domain.AuditPartition{
+		Name: "audit_log_2026_01",
+		From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	feb := &domain.AuditPartition{
+		Name: "audit_log_2026_02",
+		From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	mar := &domain.AuditPartition{
+		Name: "audit_log_2026_03",
+		From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	repo.EXPECT().ListPartitions(mock.Anything).Return([]*domain.AuditPartition{jan, feb, mar}, nil)
+
+	// февраль недавно возвращён из архива и ещё удерживается в БД
+	attachedAt := svc.now().Add(-time.Hour)
+	repo.EXPECT().List(mock.Anything).Return([]*domain.AuditArchive{
+		{Partition: feb.Name, Status: domain.AuditArchiveAttached, AttachedAt: &attachedAt},
+	}, nil)
+
+	// записи 1-2 и 4 в январе, запись 3 - в соседней секции
+	links := auditLinks(4)
+	repo.EXPECT().StreamPartition(mock.Anything, jan.Name, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ string, fn func(l *domain.AuditChainLink) error) error {
+			for _, l := range []*domain.AuditChainLink{links[0], links[1], links[3]} {
+				if err := fn(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	var detached *domain.AuditArchive
+	repo.EXPECT().Detach(mock.Anything, jan, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuditPartition, a *domain.AuditArchive) error {
+			detached = a
+			return nil
+		})
+
+	svc.maintain(context.Background())
+
+	require.NotNil(t, detached)
+	assert.Equal(t, "audit_log_2026_01.jsonl.gz", detached.FileName)
+	assert.Equal(t, int64(3), detached.Rows)
+	assert.Len(t, detached.FileSHA256, 64)
+	require.Len(t, detached.Segments, 2)
+	assert.Equal(t, links[1].Hash, detached.Segments[0].Hash)
+	assert.Equal(t, int64(4), detached.Segments[1].FirstID)
+
+	path := filepath.Join(svc.opts.Dir, detached.FileName)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), detached.FileSize)
+	require.NoError(t, checkFileSHA256(path, detached.FileSHA256))
+}
+
+func TestAuditArchiveService_Maintain_EmptyPartition(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 1)
+	expectAuditMaintenance(repo)
+
+	p := &domain.AuditPartition{
+		Name: "audit_log_2026_03",
+		From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	repo.EXPECT().ListPartitions(mock.Anything).Return([]*domain.AuditPartition{p}, nil)
+	repo.EXPECT().List(mock.Anything).Return(nil, nil)
+	repo.EXPECT().StreamPartition(mock.Anything, p.Name, mock.Anything).Return(nil)
+	repo.EXPECT().Detach(mock.Anything, p, (*domain.AuditArchive)(nil)).Return(nil)
+
+	svc.maintain(context.Background())
+
+	entries, err := os.ReadDir(svc.opts.Dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no file for an empty partition")
+}
+
+func TestAuditArchiveService_Maintain_BrokenChainKeepsPartition(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 1)
+	expectAuditMaintenance(repo)
+
+	p := &domain.AuditPartition{
+		Name: "audit_log_2026_03",
+		From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	repo.EXPECT().ListPartitions(mock.Anything).Return([]*domain.AuditPartition{p}, nil)
+	repo.EXPECT().List(mock.Anything).Return(nil, nil)
+
+	links := auditLinks(2)
+	links[1].NewData = json.RawMessage(`{"quantity": 500}`)
+	repo.EXPECT().StreamPartition(mock.Anything, p.Name, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ string, fn func(l *domain.AuditChainLink) error) error {
+			for _, l := range links {
+				if err := fn(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	svc.maintain(context.Background())
+
+	entries, err := os.ReadDir(svc.opts.Dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAuditArchiveService_List_Forbidden(t *testing.T) {
+	svc, _ := newAuditArchiveService(t, 0)
+
+	_, err := svc.List(context.Background(), managerClaims)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+// writeTestArchive пишет файл архива и возвращает запись о нём
+func writeTestArchive(t *testing.T, svc *AuditArchiveService, links []*domain.AuditChainLink) *domain.AuditArchive {
+	t.Helper()
+
+	p := &domain.AuditPartition{Name: "audit_log_2026_01"}
+	repo := newMockauditArchiveRepository(t)
+	repo.EXPECT().StreamPartition(mock.Anything, p.Name, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ string, fn func(l *domain.AuditChainLink) error) error {
+			for _, l := range links {
+				if err := fn(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	writer := &AuditArchiveService{repo: repo, opts: svc.opts}
+	fileName := p.Name + auditarchive.Extension
+	segs, sum, err := writer.writeArchive(context.Background(), p, filepath.Join(svc.opts.Dir, fileName))
+	require.NoError(t, err)
+
+	return &domain.AuditArchive{
+		ID:         7,
+		Partition:  p.Name,
+		FileName:   fileName,
+		FileSHA256: sum,
+		Rows:       segs.Rows(),
+		Segments:   segs.List(),
+		Status:     domain.AuditArchiveArchived,
+	}
+}
+
+func TestAuditArchiveService_Attach_Success(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 0)
+	links := auditLinks(3)
+	archive := writeTestArchive(t, svc, links)
+
+	repo.EXPECT().GetByID(mock.Anything, int64(7)).Return(archive, nil).Once()
+	repo.EXPECT().Attach(mock.Anything, archive, adminClaims.UserID, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuditArchive, _ uuid.UUID, next func() (*domain.AuditChainLink, error)) error {
+			for i := 0; ; i++ {
+				l, err := next()
+				if err != nil {
+					assert.Equal(t, len(links), i)
+					return ignoreEOF(err)
+				}
+				assert.Equal(t, links[i].Hash, l.Hash)
+			}
+		})
+	attached := *archive
+	attached.Status = domain.AuditArchiveAttached
+	repo.EXPECT().GetByID(mock.Anything, int64(7)).Return(&attached, nil).Once()
+
+	got, err := svc.Attach(context.Background(), adminClaims, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuditArchiveAttached, got.Status)
+}
+
+func TestAuditArchiveService_Attach_Forbidden(t *testing.T) {
+	svc, _ := newAuditArchiveService(t, 0)
+
+	_, err := svc.Attach(context.Background(), managerClaims, 7)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestAuditArchiveService_Attach_AlreadyAttached(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 0)
+	repo.EXPECT().GetByID(mock.Anything, int64(7)).Return(&domain.AuditArchive{
+		ID:     7,
+		Status: domain.AuditArchiveAttached,
+	}, nil)
+
+	_, err := svc.Attach(context.Background(), adminClaims, 7)
+
+	assert.ErrorIs(t, err, domain.ErrAuditArchiveAttached)
+}
+
+func TestAuditArchiveService_Attach_ChecksumMismatch(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 0)
+	archive := writeTestArchive(t, svc, auditLinks(2))
+	archive.FileSHA256 = "00" + archive.FileSHA256[2:]
+	repo.EXPECT().GetByID(mock.Anything, int64(7)).Return(archive, nil)
+
+	_, err := svc.Attach(context.Background(), adminClaims, 7)
+
+	assert.ErrorIs(t, err, domain.ErrAuditArchiveCorrupted)
+}
+
+func TestAuditArchiveService_Attach_SegmentsMismatch(t *testing.T) {
+	svc, repo := newAuditArchiveService(t, 0)
+	archive := writeTestArchive(t, svc, auditLinks(2))
+	archive.Segments[0].Rows = 5
+	repo.EXPECT().GetByID(mock.Anything, int64(7)).Return(archive, nil)
+	repo.EXPECT().Attach(mock.Anything, archive, adminClaims.UserID, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ *domain.AuditArchive, _ uuid.UUID, next func() (*domain.AuditChainLink, error)) error {
+			for {
+				if _, err := next(); err != nil {
+					return ignoreEOF(err)
+				}
+			}
+		})
+
+	_, err := svc.Attach(context.Background(), adminClaims, 7)
+
+	assert.ErrorIs(t, err, domain.ErrAuditArchiveCorrupted)
+}
+
+// ignoreEOF - конец файла для Attach не ошибка, как и в репозитории
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -26,11 +28,12 @@ func TestAuditService_GetByItemID_Success(t *testing.T) {
 	entries := []*domain.AuditEntryWithUser{
 		{
 			AuditEntry: domain.AuditEntry{
-				ID:        1,
-				ItemID:    itemID,
-				Action:    domain.AuditInsert,
-				ChangedBy: adminClaims.UserID,
-				ChangedAt: time.Now(),
+				ID:         1,
+				EntityType: domain.AuditEntityItem,
+				EntityID:   itemID,
+				Action:     domain.AuditInsert,
+				ChangedBy:  adminClaims.UserID,
+				ChangedAt:  time.Now(),
 			},
 			Username: "admin",
 		},
@@ -121,6 +124,29 @@ func TestAuditService_List_InvalidAction(t *testing.T) {
 	assert.ErrorIs(t, err, domain.ErrValidation)
 }
 
+func TestAuditService_List_InvalidEntityType(t *testing.T) {
+	svc, _ := newAuditService(t)
+
+	badEntity := domain.AuditEntity("order")
+	filter := &domain.AuditFilter{EntityType: &badEntity}
+
+	_, err := svc.List(context.Background(), adminClaims, filter, 1, 20)
+
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestAuditService_List_InvalidChange(t *testing.T) {
+	svc, _ := newAuditService(t)
+
+	filter := &domain.AuditFilter{Changes: []domain.AuditValuePredicate{
+		{Field: "name", Side: domain.AuditValueNew, Op: domain.AuditOpGt, Value: "abc"},
+	}}
+
+	_, err := svc.List(context.Background(), adminClaims, filter, 1, 20)
+
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
 func TestAuditService_List_RepoError(t *testing.T) {
 	svc, repo := newAuditService(t)
 
@@ -145,50 +171,227 @@ func TestAuditService_List_NilResultReturnsEmptySlice(t *testing.T) {
 	assert.Empty(t, result.Entries)
 }
 
-func TestAuditService_ExportCSV_Success(t *testing.T) {
-	svc, repo := newAuditService(t)
-
-	entries := []*domain.AuditEntryWithUser{
-		{
+func auditEntries(n int) []*domain.AuditEntryWithUser {
+	res := make([]*domain.AuditEntryWithUser, n)
+	for i := range res {
+		res[i] = &domain.AuditEntryWithUser{
 			AuditEntry: domain.AuditEntry{
-				ID:        1,
-				ItemID:    uuid.New(),
-				Action:    domain.AuditInsert,
-				ChangedBy: adminClaims.UserID,
-				ChangedAt: time.Now(),
+				ID:         int64(i + 1),
+				EntityType: domain.AuditEntityItem,
+				EntityID:   uuid.New(),
+				Action:     domain.AuditInsert,
+				ChangedBy:  adminClaims.UserID,
+				ChangedAt:  time.Now(),
 			},
 			Username: "admin",
-		},
+		}
 	}
+	return res
+}
+
+// streamEntries - поведение Stream: отдать записи по одной, затем вернуть err
+func streamEntries(entries []*domain.AuditEntryWithUser, err error) func(context.Context, *domain.AuditFilter, func(*domain.AuditEntryWithUser) error) error {
+	return func(_ context.Context, _ *domain.AuditFilter, fn func(*domain.AuditEntryWithUser) error) error {
+		for _, e := range entries {
+			if fnErr := fn(e); fnErr != nil {
+				return fnErr
+			}
+		}
+		return err
+	}
+}
+
+func TestAuditService_Export_Success(t *testing.T) {
+	svc, repo := newAuditService(t)
 
 	filter := &domain.AuditFilter{}
-	repo.EXPECT().List(mock.Anything, filter, maxExportRows, 0).Return(entries, int64(1), nil)
+	repo.EXPECT().Stream(mock.Anything, filter, mock.Anything).RunAndReturn(streamEntries(auditEntries(1), nil))
 
 	var buf bytes.Buffer
-	err := svc.ExportCSV(context.Background(), adminClaims, filter, &buf)
+	rows, err := svc.Export(context.Background(), adminClaims, filter, export.FormatCSV, &buf)
 
 	assert.NoError(t, err)
-	assert.Contains(t, buf.String(), "ID,Item ID,Action")
+	assert.Equal(t, int64(1), rows)
+	assert.Contains(t, buf.String(), "ID,Entity,Entity ID,Action")
 	assert.Contains(t, buf.String(), "admin")
 }
 
-func TestAuditService_ExportCSV_ViewerForbidden(t *testing.T) {
+func TestAuditService_Export_NoRowCap(t *testing.T) {
+	svc, repo := newAuditService(t)
+
+	filter := &domain.AuditFilter{}
+	repo.EXPECT().Stream(mock.Anything, filter, mock.Anything).RunAndReturn(streamEntries(auditEntries(12000), nil))
+
+	var buf bytes.Buffer
+	rows, err := svc.Export(context.Background(), adminClaims, filter, export.FormatCSV, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12000), rows)
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 12001)
+}
+
+func TestAuditService_Export_ViewerForbidden(t *testing.T) {
 	svc, _ := newAuditService(t)
 
 	var buf bytes.Buffer
-	err := svc.ExportCSV(context.Background(), viewerClaims, &domain.AuditFilter{}, &buf)
+	_, err := svc.Export(context.Background(), viewerClaims, &domain.AuditFilter{}, export.FormatCSV, &buf)
 
 	assert.ErrorIs(t, err, domain.ErrForbidden)
 }
 
-func TestAuditService_ExportCSV_RepoError(t *testing.T) {
+func TestAuditService_Export_RepoError(t *testing.T) {
+	svc, repo := newAuditService(t)
+
+	filter := &domain.AuditFilter{}
+	repo.EXPECT().Stream(mock.Anything, filter, mock.Anything).Return(errors.New("db error"))
+
+	var buf bytes.Buffer
+	rows, err := svc.Export(context.Background(), adminClaims, filter, export.FormatCSV, &buf)
+
+	assert.Error(t, err)
+	assert.Zero(t, rows)
+	assert.Zero(t, buf.Len(), "nothing must be written before the first row")
+}
+
+func TestAuditService_Export_InterruptedFlushesWrittenRows(t *testing.T) {
 	svc, repo := newAuditService(t)
 
 	filter := &domain.AuditFilter{}
-	repo.EXPECT().List(mock.Anything, filter, maxExportRows, 0).Return(nil, int64(0), errors.New("db error"))
+	repo.EXPECT().Stream(mock.Anything, filter, mock.Anything).
+		RunAndReturn(streamEntries(auditEntries(3), errors.New("connection reset")))
 
 	var buf bytes.Buffer
-	err := svc.ExportCSV(context.Background(), adminClaims, filter, &buf)
+	rows, err := svc.Export(context.Background(), adminClaims, filter, export.FormatCSV, &buf)
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(3), rows)
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 4)
+}
+
+func TestAuditService_Verify_Valid(t *testing.T) {
+	svc, repo := newAuditService(t)
+
+	repo.EXPECT().StreamChain(mock.Anything, mock.Anything).
+		Return(&domain.AuditChainHead{LastHash: domain.AuditChainGenesis}, nil)
+
+	report, err := svc.Verify(context.Background(), adminClaims, nil)
+
+	assert.NoError(t, err)
+	assert.True(t, report.Valid)
+}
+
+func TestAuditService_Verify_Broken(t *testing.T) {
+	svc, repo := newAuditService(t)
+
+	repo.EXPECT().StreamChain(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error) {
+			if err := fn(&domain.AuditChainLink{
+				AuditEntry: domain.AuditEntry{ID: 1},
+				PrevHash:   domain.AuditChainGenesis,
+				Hash:       "tampered",
+			}); err != nil {
+				return nil, err
+			}
+			return &domain.AuditChainHead{LastID: 1, LastHash: "tampered"}, nil
+		})
+
+	report, err := svc.Verify(context.Background(), adminClaims, nil)
+
+	assert.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, int64(1), report.Broken.EntryID)
+	assert.Equal(t, domain.AuditChainHashMismatch, report.Broken.Reason)
+}
+
+func TestAuditService_Verify_ManagerForbidden(t *testing.T) {
+	svc, _ := newAuditService(t)
+
+	_, err := svc.Verify(context.Background(), managerClaims, nil)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestAuditService_Verify_RepoError(t *testing.T) {
+	svc, repo := newAuditService(t)
+
+	repo.EXPECT().StreamChain(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := svc.Verify(context.Background(), adminClaims, nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "AuditService.Verify")
+}
+
+func TestAuditService_Stats_Defaults(t *testing.T) {
+	svc, repo := newAuditService(t)
+	now := time.Date(2026, 3, 21, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	repo.EXPECT().Stats(mock.Anything, &domain.AuditStatsFilter{
+		DateFrom: now.AddDate(0, 0, -domain.AuditStatsDefaultDays),
+		DateTo:   now,
+		Limit:    domain.AuditStatsDefaultLimit,
+	}).Return(&domain.AuditStats{}, nil)
+
+	_, err := svc.Stats(context.Background(), adminClaims, &domain.AuditStatsFilter{})
+
+	assert.NoError(t, err)
+}
+
+func TestAuditService_Stats_ViewerForbidden(t *testing.T) {
+	svc, _ := newAuditService(t)
+
+	_, err := svc.Stats(context.Background(), viewerClaims, &domain.AuditStatsFilter{})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestAuditService_Stats_DateFromOnlyEndsNow(t *testing.T) {
+	svc, repo := newAuditService(t)
+	now := time.Date(2026, 3, 21, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	from := now.AddDate(0, 0, -7)
+
+	repo.EXPECT().Stats(mock.Anything, mock.MatchedBy(func(f *domain.AuditStatsFilter) bool {
+		return f.DateFrom.Equal(from) && f.DateTo.Equal(now)
+	})).Return(&domain.AuditStats{}, nil)
+
+	_, err := svc.Stats(context.Background(), adminClaims, &domain.AuditStatsFilter{DateFrom: from})
+
+	assert.NoError(t, err)
+}
+
+func TestAuditService_Stats_Invalid(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter domain.AuditStatsFilter
+	}{
+		{"from after to", domain.AuditStatsFilter{DateFrom: from, DateTo: from.AddDate(0, 0, -1)}},
+		{"empty period", domain.AuditStatsFilter{DateFrom: from, DateTo: from}},
+		{"period too long", domain.AuditStatsFilter{DateFrom: from, DateTo: from.AddDate(0, 0, domain.AuditStatsMaxDays+1)}},
+		{"negative limit", domain.AuditStatsFilter{DateFrom: from, DateTo: from.AddDate(0, 0, 1), Limit: -1}},
+		{"limit too big", domain.AuditStatsFilter{DateFrom: from, DateTo: from.AddDate(0, 0, 1), Limit: domain.AuditStatsMaxLimit + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newAuditService(t)
+
+			_, err := svc.Stats(context.Background(), adminClaims, &tt.filter)
+
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		})
+	}
+}
+
+func TestAuditService_Stats_RepoError(t *testing.T) {
+	svc, repo := newAuditService(t)
+
+	repo.EXPECT().Stats(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := svc.Stats(context.Background(), adminClaims, &domain.AuditStatsFilter{})
 
 	assert.Error(t, err)
 }
@@ -2,71 +2,322 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	"github.com/wb-go/wbf/logger"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 	List(ctx context.Context) ([]*domain.User, error)
+	UpdatePassword(ctx context.Context, actorID, id uuid.UUID, passwordHash string) (*domain.User, error)
+}
+
+// refreshTokenBytes - случайная часть refresh-токена
+const refreshTokenBytes = 32
+
+type refreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) ([]*domain.RevokedToken, error)
+	RevokeSession(ctx context.Context, jti uuid.UUID, expiresAt time.Time) ([]*domain.RevokedToken, error)
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) ([]*domain.RevokedToken, error)
+}
+
+// tokenDenylist - кэш отозванных токенов, по которому их отклоняет TokenManager.Validate
+type tokenDenylist interface {
+	Add(tokens ...*domain.RevokedToken)
 }
 
 type TokenManager interface {
-	GenerateJWT(user *domain.User) (string, error)
+	GenerateJWT(user *domain.User) (*domain.AccessToken, error)
 	Validate(tokenStr string) (*domain.AuthClaims, error)
 }
 
+// AuthOptions - параметры входа
+type AuthOptions struct {
+	BcryptCost int           // стоимость bcrypt для новых паролей
+	RefreshTTL time.Duration // срок жизни refresh-токена; при обновлении выдаётся новый на тот же срок
+}
+
 type AuthService struct {
-	userRepo userRepository
-	manager  TokenManager
-	log      logger.Logger
+	userRepo  userRepository
+	tokenRepo refreshTokenRepository
+	manager   TokenManager
+	denylist  tokenDenylist
+	opts      AuthOptions
+	log       logger.Logger
+	now       func() time.Time
 }
 
-func NewAuthService(userRepo userRepository, manager TokenManager, log logger.Logger) *AuthService {
+func NewAuthService(
+	userRepo userRepository,
+	tokenRepo refreshTokenRepository,
+	manager TokenManager,
+	denylist tokenDenylist,
+	opts AuthOptions,
+	log logger.Logger,
+) *AuthService {
+	if opts.BcryptCost <= 0 {
+		opts.BcryptCost = bcrypt.DefaultCost
+	}
+	if opts.RefreshTTL <= 0 {
+		opts.RefreshTTL = 30 * 24 * time.Hour
+	}
+
 	return &AuthService{
-		userRepo: userRepo,
-		manager:  manager,
-		log:      log.With("component", "AuthService"),
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		manager:   manager,
+		denylist:  denylist,
+		opts:      opts,
+		log:       log.With("component", "AuthService"),
+		now:       time.Now,
 	}
 }
 
-func (s *AuthService) Login(ctx context.Context, input *domain.LoginInput) (string, *domain.User, error) {
+// Login проверяет пароль и открывает сессию: новое семейство refresh-токенов
+func (s *AuthService) Login(ctx context.Context, input *domain.LoginInput) (*domain.TokenPair, *domain.User, error) {
 	const op = "AuthService.Login"
 	user, err := s.userRepo.GetByUsername(ctx, input.Username)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			return "", nil, domain.ErrInvalidCredentials
+			return nil, nil, domain.ErrInvalidCredentials
 		}
 		s.log.Ctx(ctx).Error("failed to get user",
 			"error", err,
 			"username", input.Username,
 		)
-		return "", nil, fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
-		return "", nil, domain.ErrInvalidCredentials
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+	// об отключении сообщаем только после верного пароля - иначе по ответу можно перебирать учётные записи
+	if user.Disabled() {
+		return nil, nil, domain.ErrUserDisabled
 	}
 
-	token, err := s.manager.GenerateJWT(user)
+	pair, err := s.StartSession(ctx, user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return pair, user, nil
+}
+
+// StartSession открывает сессию уже опознанного пользователя: новое семейство refresh-токенов.
+// Проверка пароля или другого способа входа - на вызывающем.
+func (s *AuthService) StartSession(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	const op = "AuthService.StartSession"
+
+	pair, next, err := s.issue(user, uuid.New())
 	if err != nil {
 		s.log.Ctx(ctx).Error("failed to generate token",
 			"error", err,
 			"user_id", user.ID,
 		)
-		return "", nil, fmt.Errorf("%s - generate token: %w", op, err)
+		return nil, fmt.Errorf("%s - generate token: %w", op, err)
 	}
 
-	return token, user, nil
+	if err = s.tokenRepo.CreateRefreshToken(ctx, next); err != nil {
+		s.log.Ctx(ctx).Error("failed to save refresh token",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return pair, nil
 }
 
-func (s *AuthService) ListUsers(ctx context.Context) ([]*domain.User, error) {
+// Refresh обменивает refresh-токен на новую пару. Каждый refresh-токен действует один раз:
+// повторное предъявление обменянного токена означает, что его украли, - тогда отзывается
+// вся сессия, и войти заново придётся и владельцу, и похитителю.
+// Роль и статус пользователя читаются из БД, так что их изменения вступают в силу при обновлении.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, *domain.User, error) {
+	const op = "AuthService.Refresh"
+
+	current, err := s.tokenRepo.GetRefreshToken(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, domain.ErrTokenInvalid
+		}
+		s.log.Ctx(ctx).Error("failed to get refresh token",
+			"error", err,
+		)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if current.RevokedAt != nil || !current.ExpiresAt.After(s.now()) {
+		return nil, nil, domain.ErrTokenInvalid
+	}
+	if current.RotatedAt != nil {
+		return nil, nil, s.reused(ctx, op, current)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, domain.ErrTokenInvalid
+		}
+		s.log.Ctx(ctx).Error("failed to get user",
+			"error", err,
+			"user_id", current.UserID,
+		)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Disabled() {
+		if err = s.revokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, nil, domain.ErrUserDisabled
+	}
+
+	pair, next, err := s.issue(user, current.FamilyID)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to generate token",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return nil, nil, fmt.Errorf("%s - generate token: %w", op, err)
+	}
+
+	if err = s.tokenRepo.RotateRefreshToken(ctx, current.ID, next); err != nil {
+		// токен успели обменять между чтением и обменом
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			return nil, nil, s.reused(ctx, op, current)
+		}
+		s.log.Ctx(ctx).Error("failed to rotate refresh token",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pair, user, nil
+}
+
+// Logout отзывает токен доступа из claims и всю сессию, в которой он выдан
+func (s *AuthService) Logout(ctx context.Context, claims *domain.AuthClaims) error {
+	const op = "AuthService.Logout"
+
+	// у запроса по API-ключу нет сессии; ключ отзывает администратор
+	if claims.ViaAPIKey() {
+		return domain.ErrForbidden
+	}
+
+	revoked, err := s.tokenRepo.RevokeSession(ctx, claims.TokenID, claims.ExpiresAt)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to revoke session",
+			"error", err,
+			"user_id", claims.UserID,
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.denylist.Add(revoked...)
+
+	s.log.Ctx(ctx).Info("user logged out",
+		"user_id", claims.UserID,
+	)
+	return nil
+}
+
+// RevokeUserSessions завершает все сессии пользователя: refresh-токены больше не обмениваются,
+// выданные с ними токены доступа отклоняются сразу, не дожидаясь истечения
+func (s *AuthService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	const op = "AuthService.RevokeUserSessions"
+
+	revoked, err := s.tokenRepo.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to revoke user sessions",
+			"error", err,
+			"target_user_id", userID,
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.denylist.Add(revoked...)
+
+	s.log.Ctx(ctx).Info("user sessions revoked",
+		"target_user_id", userID,
+		"access_tokens", len(revoked),
+	)
+	return nil
+}
+
+// reused отзывает семейство повторно предъявленного refresh-токена
+func (s *AuthService) reused(ctx context.Context, op string, t *domain.RefreshToken) error {
+	s.log.Ctx(ctx).Warn("refresh token reuse detected, revoking session",
+		"user_id", t.UserID,
+		"family_id", t.FamilyID,
+	)
+	if err := s.revokeFamily(ctx, t.FamilyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return domain.ErrTokenInvalid
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	revoked, err := s.tokenRepo.RevokeFamily(ctx, familyID)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to revoke refresh token family",
+			"error", err,
+			"family_id", familyID,
+		)
+		return err
+	}
+	s.denylist.Add(revoked...)
+	return nil
+}
+
+// issue выпускает пару токенов семейства familyID; refresh-токен ещё нужно сохранить
+func (s *AuthService) issue(user *domain.User, familyID uuid.UUID) (*domain.TokenPair, *domain.RefreshToken, error) {
+	access, err := s.manager.GenerateJWT(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	expiresAt := s.now().Add(s.opts.RefreshTTL)
+
+	pair := &domain.TokenPair{
+		AccessToken:      access.Token,
+		AccessExpiresAt:  access.ExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: expiresAt,
+	}
+	next := &domain.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashRefreshToken(refresh),
+		AccessTokenID:   access.ID,
+		AccessExpiresAt: access.ExpiresAt,
+		ExpiresAt:       expiresAt,
+	}
+	return pair, next, nil
+}
+
+// ListUsers - логины и роли пользователей, которые могут войти; отключённые не показываются
+func (s *AuthService) ListUsers(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error) {
 	const op = "AuthService.ListUsers"
 
+	if !claims.Role.CanListUsers() {
+		return nil, domain.ErrForbidden
+	}
+
 	users, err := s.userRepo.List(ctx)
 	if err != nil {
 		s.log.Ctx(ctx).Error("failed to list users",
@@ -75,7 +326,100 @@ func (s *AuthService) ListUsers(ctx context.Context) ([]*domain.User, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return users, nil
+	active := make([]*domain.User, 0, len(users))
+	for _, u := range users {
+		if !u.Disabled() {
+			active = append(active, u)
+		}
+	}
+	return active, nil
+}
+
+// Me - учётная запись владельца токена
+func (s *AuthService) Me(ctx context.Context, claims *domain.AuthClaims) (*domain.User, error) {
+	const op = "AuthService.Me"
+
+	user, err := s.currentUser(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+// ChangePassword меняет пароль владельца токена; текущий пароль обязателен.
+// Все сессии пользователя, включая текущую, завершаются - с новым паролем нужно войти заново.
+func (s *AuthService) ChangePassword(ctx context.Context, claims *domain.AuthClaims, input *domain.ChangePasswordInput) error {
+	const op = "AuthService.ChangePassword"
+
+	user, err := s.currentUser(ctx, claims)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// неверный текущий пароль - ошибка поля, а не 401: сессия при этом остаётся действительной
+	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.CurrentPassword)); err != nil {
+		return &domain.ValidationError{Field: "current_password", Reason: "is incorrect"}
+	}
+	if err = validatePassword("new_password", input.NewPassword); err != nil {
+		return err
+	}
+	if input.NewPassword == input.CurrentPassword {
+		return &domain.ValidationError{Field: "new_password", Reason: "must differ from the current password"}
+	}
+
+	hash, err := generatePasswordHash(input.NewPassword, s.opts.BcryptCost)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err = s.userRepo.UpdatePassword(ctx, user.ID, user.ID, hash); err != nil {
+		s.log.Ctx(ctx).Error("failed to change password",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// пароль меняют и после утечки: украденный refresh-токен не должен пережить смену
+	if err = s.RevokeUserSessions(ctx, user.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("password changed",
+		"user_id", user.ID,
+	)
+	return nil
 }
 
-//TODO: добавить методы Register, Logout, однако это по заданию не требуется
+// currentUser - пользователь из токена в его текущем состоянии: удалённый - недействительный токен,
+// отключённый - ErrUserDisabled
+func (s *AuthService) currentUser(ctx context.Context, claims *domain.AuthClaims) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrTokenInvalid
+		}
+		s.log.Ctx(ctx).Error("failed to get user",
+			"error", err,
+			"user_id", claims.UserID,
+		)
+		return nil, err
+	}
+	if user.Disabled() {
+		return nil, domain.ErrUserDisabled
+	}
+	return user, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken - в БД хранится SHA-256: токен случайный, медленный хеш ему не нужен
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
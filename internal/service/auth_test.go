@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
@@ -21,10 +23,26 @@ func hashPassword(t *testing.T, password string) string {
 	return string(hash)
 }
 
+// newTestAuthService - AuthService с моками хранилища refresh-токенов и кэша отозванных токенов
+func newTestAuthService(
+	t *testing.T,
+	userRepo userRepository,
+	tokenMgr TokenManager,
+	bcryptCost int,
+) (*AuthService, *mockrefreshTokenRepository, *mocktokenDenylist) {
+	tokenRepo := newMockrefreshTokenRepository(t)
+	denylist := newMocktokenDenylist(t)
+	svc := NewAuthService(userRepo, tokenRepo, tokenMgr, denylist, AuthOptions{
+		BcryptCost: bcryptCost,
+		RefreshTTL: time.Hour,
+	}, newTestLogger())
+	return svc, tokenRepo, denylist
+}
+
 func TestAuthService_Login_Success(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, tokenRepo, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
 	user := &domain.User{
 		ID:           uuid.New(),
@@ -32,24 +50,40 @@ func TestAuthService_Login_Success(t *testing.T) {
 		PasswordHash: hashPassword(t, "password"),
 		Role:         domain.RoleAdmin,
 	}
+	access := &domain.AccessToken{Token: "jwt-token", ID: uuid.New(), ExpiresAt: time.Now().Add(15 * time.Minute)}
 
 	userRepo.EXPECT().GetByUsername(mock.Anything, "admin").Return(user, nil)
-	tokenMgr.EXPECT().GenerateJWT(user).Return("jwt-token", nil)
+	tokenMgr.EXPECT().GenerateJWT(user).Return(access, nil)
+	var saved *domain.RefreshToken
+	tokenRepo.EXPECT().CreateRefreshToken(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, t *domain.RefreshToken) { saved = t }).
+		Return(nil)
 
-	token, result, err := svc.Login(context.Background(), &domain.LoginInput{
+	pair, result, err := svc.Login(context.Background(), &domain.LoginInput{
 		Username: "admin",
 		Password: "password",
 	})
 
 	assert.NoError(t, err)
-	assert.Equal(t, "jwt-token", token)
+	assert.Equal(t, "jwt-token", pair.AccessToken)
+	assert.Equal(t, access.ExpiresAt, pair.AccessExpiresAt)
 	assert.Equal(t, user.ID, result.ID)
+
+	// в БД - хеш токена и сессия, в которой выдан токен доступа
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, hashRefreshToken(pair.RefreshToken), saved.TokenHash)
+	assert.NotEqual(t, pair.RefreshToken, saved.TokenHash)
+	assert.Equal(t, user.ID, saved.UserID)
+	assert.NotEqual(t, uuid.Nil, saved.FamilyID)
+	assert.Equal(t, access.ID, saved.AccessTokenID)
+	assert.Equal(t, pair.RefreshExpiresAt, saved.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), pair.RefreshExpiresAt, time.Minute)
 }
 
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
 	userRepo.EXPECT().GetByUsername(mock.Anything, "unknown").Return(nil, domain.ErrNotFound)
 
@@ -64,7 +98,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 func TestAuthService_Login_WrongPassword(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
 	user := &domain.User{
 		ID:           uuid.New(),
@@ -83,10 +117,40 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
 }
 
+func TestAuthService_Login_Disabled(t *testing.T) {
+	userRepo := newMockuserRepository(t)
+	tokenMgr := NewMockTokenManager(t)
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
+
+	disabledAt := time.Now()
+	user := &domain.User{
+		ID:           uuid.New(),
+		Username:     "manager",
+		PasswordHash: hashPassword(t, "password"),
+		Role:         domain.RoleManager,
+		DisabledAt:   &disabledAt,
+	}
+
+	userRepo.EXPECT().GetByUsername(mock.Anything, "manager").Return(user, nil).Twice()
+
+	_, _, err := svc.Login(context.Background(), &domain.LoginInput{
+		Username: "manager",
+		Password: "password",
+	})
+	assert.ErrorIs(t, err, domain.ErrUserDisabled)
+
+	// с неверным паролем отключённая учётная запись неотличима от прочих
+	_, _, err = svc.Login(context.Background(), &domain.LoginInput{
+		Username: "manager",
+		Password: "wrong",
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+}
+
 func TestAuthService_Login_RepoError(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
 	userRepo.EXPECT().GetByUsername(mock.Anything, "admin").Return(nil, errors.New("db error"))
 
@@ -102,7 +166,7 @@ func TestAuthService_Login_RepoError(t *testing.T) {
 func TestAuthService_Login_TokenGenerationError(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
 	user := &domain.User{
 		ID:           uuid.New(),
@@ -112,7 +176,7 @@ func TestAuthService_Login_TokenGenerationError(t *testing.T) {
 	}
 
 	userRepo.EXPECT().GetByUsername(mock.Anything, "admin").Return(user, nil)
-	tokenMgr.EXPECT().GenerateJWT(user).Return("", errors.New("signing error"))
+	tokenMgr.EXPECT().GenerateJWT(user).Return(nil, errors.New("signing error"))
 
 	_, _, err := svc.Login(context.Background(), &domain.LoginInput{
 		Username: "admin",
@@ -126,29 +190,279 @@ func TestAuthService_Login_TokenGenerationError(t *testing.T) {
 func TestAuthService_ListUsers_Success(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
+	disabledAt := time.Now()
 	users := []*domain.User{
 		{ID: uuid.New(), Username: "admin", Role: domain.RoleAdmin},
+		{ID: uuid.New(), Username: "former", Role: domain.RoleManager, DisabledAt: &disabledAt},
 		{ID: uuid.New(), Username: "viewer", Role: domain.RoleViewer},
 	}
 
 	userRepo.EXPECT().List(mock.Anything).Return(users, nil)
 
-	result, err := svc.ListUsers(context.Background())
+	result, err := svc.ListUsers(context.Background(), adminClaims)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
+	assert.Equal(t, []*domain.User{users[0], users[2]}, result)
 }
 
 func TestAuthService_ListUsers_Error(t *testing.T) {
 	userRepo := newMockuserRepository(t)
 	tokenMgr := NewMockTokenManager(t)
-	svc := NewAuthService(userRepo, tokenMgr, newTestLogger())
+	svc, _, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
 
 	userRepo.EXPECT().List(mock.Anything).Return(nil, errors.New("db error"))
 
-	_, err := svc.ListUsers(context.Background())
+	_, err := svc.ListUsers(context.Background(), adminClaims)
 
 	assert.Error(t, err)
 }
+
+func TestAuthService_ListUsers_Forbidden(t *testing.T) {
+	svc, _, _ := newTestAuthService(t, newMockuserRepository(t), NewMockTokenManager(t), bcrypt.MinCost)
+
+	_, err := svc.ListUsers(context.Background(), viewerClaims)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestAuthService_Me(t *testing.T) {
+	userRepo := newMockuserRepository(t)
+	svc, _, _ := newTestAuthService(t, userRepo, NewMockTokenManager(t), bcrypt.MinCost)
+	ctx := context.Background()
+
+	user := &domain.User{ID: viewerClaims.UserID, Username: "viewer", Role: domain.RoleViewer}
+	userRepo.EXPECT().GetByID(mock.Anything, viewerClaims.UserID).Return(user, nil).Once()
+	got, err := svc.Me(ctx, viewerClaims)
+	assert.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	// пользователя удалили, а токен ещё действует
+	userRepo.EXPECT().GetByID(mock.Anything, viewerClaims.UserID).Return(nil, domain.ErrNotFound).Once()
+	_, err = svc.Me(ctx, viewerClaims)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	disabledAt := time.Now()
+	userRepo.EXPECT().GetByID(mock.Anything, viewerClaims.UserID).
+		Return(&domain.User{ID: viewerClaims.UserID, DisabledAt: &disabledAt}, nil).Once()
+	_, err = svc.Me(ctx, viewerClaims)
+	assert.ErrorIs(t, err, domain.ErrUserDisabled)
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	userRepo := newMockuserRepository(t)
+	svc, tokenRepo, denylist := newTestAuthService(t, userRepo, NewMockTokenManager(t), 5)
+
+	user := &domain.User{ID: managerClaims.UserID, Username: "manager", PasswordHash: hashPassword(t, "password")}
+	userRepo.EXPECT().GetByID(mock.Anything, managerClaims.UserID).Return(user, nil)
+	userRepo.EXPECT().UpdatePassword(mock.Anything, user.ID, user.ID, mock.MatchedBy(func(hash string) bool {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err == nil && cost == 5 && bcrypt.CompareHashAndPassword([]byte(hash), []byte("correct-horse")) == nil
+	})).Return(user, nil).Once()
+
+	// все сессии, в том числе другие устройства, завершаются; их токены доступа - сразу в denylist
+	revoked := []*domain.RevokedToken{
+		{ID: uuid.New(), ExpiresAt: time.Now().Add(10 * time.Minute)},
+		{ID: uuid.New(), ExpiresAt: time.Now().Add(5 * time.Minute)},
+	}
+	tokenRepo.EXPECT().RevokeUserSessions(mock.Anything, user.ID).Return(revoked, nil).Once()
+	denylist.EXPECT().Add(revoked[0], revoked[1]).Return().Once()
+
+	err := svc.ChangePassword(context.Background(), managerClaims, &domain.ChangePasswordInput{
+		CurrentPassword: "password",
+		NewPassword:     "correct-horse",
+	})
+	assert.NoError(t, err)
+}
+
+func TestAuthService_ChangePassword_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		field   string
+	}{
+		{"wrong current", "guess", "correct-horse", "current_password"},
+		{"too short", "password", "short", "new_password"},
+		{"unchanged", "password", "password", "new_password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := newMockuserRepository(t)
+			svc, _, _ := newTestAuthService(t, userRepo, NewMockTokenManager(t), bcrypt.MinCost)
+			userRepo.EXPECT().GetByID(mock.Anything, managerClaims.UserID).Return(&domain.User{
+				ID:           managerClaims.UserID,
+				PasswordHash: hashPassword(t, "password"),
+			}, nil)
+
+			err := svc.ChangePassword(context.Background(), managerClaims, &domain.ChangePasswordInput{
+				CurrentPassword: tt.current,
+				NewPassword:     tt.next,
+			})
+
+			var verr *domain.ValidationError
+			assert.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.field, verr.Field)
+		})
+	}
+}
+
+func TestAuthService_Refresh_Rotates(t *testing.T) {
+	userRepo := newMockuserRepository(t)
+	tokenMgr := NewMockTokenManager(t)
+	svc, tokenRepo, _ := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
+
+	user := &domain.User{ID: uuid.New(), Username: "manager", Role: domain.RoleManager}
+	current := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	access := &domain.AccessToken{Token: "jwt-2", ID: uuid.New(), ExpiresAt: time.Now().Add(15 * time.Minute)}
+
+	tokenRepo.EXPECT().GetRefreshToken(mock.Anything, hashRefreshToken("refresh-1")).Return(current, nil)
+	userRepo.EXPECT().GetByID(mock.Anything, user.ID).Return(user, nil)
+	tokenMgr.EXPECT().GenerateJWT(user).Return(access, nil)
+	var next *domain.RefreshToken
+	tokenRepo.EXPECT().RotateRefreshToken(mock.Anything, current.ID, mock.Anything).
+		Run(func(_ context.Context, _ uuid.UUID, t *domain.RefreshToken) { next = t }).
+		Return(nil)
+
+	pair, got, err := svc.Refresh(context.Background(), "refresh-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, user, got)
+	assert.Equal(t, "jwt-2", pair.AccessToken)
+	assert.NotEqual(t, "refresh-1", pair.RefreshToken)
+	// следующий токен - в том же семействе
+	assert.Equal(t, current.FamilyID, next.FamilyID)
+	assert.Equal(t, hashRefreshToken(pair.RefreshToken), next.TokenHash)
+	assert.Equal(t, access.ID, next.AccessTokenID)
+}
+
+func TestAuthService_Refresh_Invalid(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name  string
+		token *domain.RefreshToken
+		err   error
+	}{
+		{"unknown", nil, domain.ErrNotFound},
+		{"expired", &domain.RefreshToken{ExpiresAt: past}, nil},
+		{"revoked", &domain.RefreshToken{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &past}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, tokenRepo, _ := newTestAuthService(t, newMockuserRepository(t), NewMockTokenManager(t), bcrypt.MinCost)
+			tokenRepo.EXPECT().GetRefreshToken(mock.Anything, mock.Anything).Return(tt.token, tt.err)
+
+			_, _, err := svc.Refresh(context.Background(), "refresh-1")
+
+			assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+		})
+	}
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	rotatedAt := time.Now().Add(-time.Minute)
+	familyID := uuid.New()
+	revoked := []*domain.RevokedToken{
+		{ID: uuid.New(), ExpiresAt: time.Now().Add(10 * time.Minute)},
+		{ID: uuid.New(), ExpiresAt: time.Now().Add(14 * time.Minute)},
+	}
+
+	t.Run("already rotated", func(t *testing.T) {
+		svc, tokenRepo, denylist := newTestAuthService(t, newMockuserRepository(t), NewMockTokenManager(t), bcrypt.MinCost)
+		tokenRepo.EXPECT().GetRefreshToken(mock.Anything, mock.Anything).Return(&domain.RefreshToken{
+			ID:        uuid.New(),
+			FamilyID:  familyID,
+			ExpiresAt: time.Now().Add(time.Hour),
+			RotatedAt: &rotatedAt,
+		}, nil)
+		tokenRepo.EXPECT().RevokeFamily(mock.Anything, familyID).Return(revoked, nil)
+		denylist.EXPECT().Add(revoked[0], revoked[1]).Return()
+
+		_, _, err := svc.Refresh(context.Background(), "refresh-1")
+
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	// два запроса с одним токеном: второй проигрывает при обмене
+	t.Run("concurrent rotation", func(t *testing.T) {
+		userRepo := newMockuserRepository(t)
+		tokenMgr := NewMockTokenManager(t)
+		svc, tokenRepo, denylist := newTestAuthService(t, userRepo, tokenMgr, bcrypt.MinCost)
+		user := &domain.User{ID: uuid.New(), Role: domain.RoleViewer}
+		current := &domain.RefreshToken{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			FamilyID:  familyID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		tokenRepo.EXPECT().GetRefreshToken(mock.Anything, mock.Anything).Return(current, nil)
+		userRepo.EXPECT().GetByID(mock.Anything, user.ID).Return(user, nil)
+		tokenMgr.EXPECT().GenerateJWT(user).Return(&domain.AccessToken{Token: "jwt", ID: uuid.New()}, nil)
+		tokenRepo.EXPECT().RotateRefreshToken(mock.Anything, current.ID, mock.Anything).
+			Return(fmt.Errorf("rotate: %w", domain.ErrRefreshTokenReused))
+		tokenRepo.EXPECT().RevokeFamily(mock.Anything, familyID).Return(revoked, nil)
+		denylist.EXPECT().Add(revoked[0], revoked[1]).Return()
+
+		_, _, err := svc.Refresh(context.Background(), "refresh-1")
+
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+}
+
+func TestAuthService_Refresh_DisabledUser(t *testing.T) {
+	userRepo := newMockuserRepository(t)
+	svc, tokenRepo, denylist := newTestAuthService(t, userRepo, NewMockTokenManager(t), bcrypt.MinCost)
+
+	disabledAt := time.Now()
+	user := &domain.User{ID: uuid.New(), DisabledAt: &disabledAt}
+	current := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tokenRepo.EXPECT().GetRefreshToken(mock.Anything, mock.Anything).Return(current, nil)
+	userRepo.EXPECT().GetByID(mock.Anything, user.ID).Return(user, nil)
+	tokenRepo.EXPECT().RevokeFamily(mock.Anything, current.FamilyID).Return(nil, nil)
+	denylist.EXPECT().Add().Return()
+
+	_, _, err := svc.Refresh(context.Background(), "refresh-1")
+
+	assert.ErrorIs(t, err, domain.ErrUserDisabled)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	svc, tokenRepo, denylist := newTestAuthService(t, newMockuserRepository(t), NewMockTokenManager(t), bcrypt.MinCost)
+
+	claims := &domain.AuthClaims{
+		UserID:    managerClaims.UserID,
+		Role:      domain.RoleManager,
+		TokenID:   uuid.New(),
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}
+	revoked := []*domain.RevokedToken{{ID: claims.TokenID, ExpiresAt: claims.ExpiresAt}}
+
+	tokenRepo.EXPECT().RevokeSession(mock.Anything, claims.TokenID, claims.ExpiresAt).Return(revoked, nil)
+	denylist.EXPECT().Add(revoked[0]).Return()
+
+	assert.NoError(t, svc.Logout(context.Background(), claims))
+}
+
+// у запроса по API-ключу нет сессии, которую можно завершить
+func TestAuthService_Logout_APIKey(t *testing.T) {
+	svc, _, _ := newTestAuthService(t, newMockuserRepository(t), NewMockTokenManager(t), bcrypt.MinCost)
+
+	claims := &domain.AuthClaims{UserID: managerClaims.UserID, Role: domain.RoleManager, APIKeyID: uuid.New()}
+
+	assert.ErrorIs(t, svc.Logout(context.Background(), claims), domain.ErrForbidden)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// eventFetchSize - сколько новых записей аудита читать за один запрос
+const eventFetchSize = 200
+
+type auditFeedRepository interface {
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.AuditEntryWithUser, error)
+	LastID(ctx context.Context) (int64, error)
+}
+
+type auditNotifier interface {
+	Listen(ctx context.Context, notify chan<- struct{}) error
+}
+
+// EventOptions - лента изменений
+type EventOptions struct {
+	Buffer       int           // очередь событий подписчика; переполнилась - подписка закрывается
+	ReplayLimit  int           // сколько пропущенных событий можно дослать по Last-Event-ID
+	PollInterval time.Duration // дочитывание журнала без уведомлений - страховка от потерянных NOTIFY
+}
+
+type eventSubscriber struct {
+	role domain.Role
+	ch   chan *domain.ChangeEvent
+}
+
+// EventService раздаёт новые записи аудита подписчикам /api/events.
+// Записи дочитываются из audit_log по id после уведомления PostgreSQL (LISTEN/NOTIFY)
+// или раз в PollInterval; каждому подписчику уходят только события, видимые его роли.
+type EventService struct {
+	repo     auditFeedRepository
+	notifier auditNotifier
+	opts     EventOptions
+	log      logger.Logger
+
+	mu     sync.Mutex
+	subs   map[*eventSubscriber]struct{}
+	lastID int64
+	ready  bool // lastID прочитан из БД
+	closed bool
+}
+
+func NewEventService(repo auditFeedRepository, notifier auditNotifier, opts EventOptions, log logger.Logger) *EventService {
+	if opts.Buffer <= 0 {
+		opts.Buffer = 256
+	}
+	if opts.ReplayLimit <= 0 {
+		opts.ReplayLimit = 1000
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+
+	return &EventService{
+		repo:     repo,
+		notifier: notifier,
+		opts:     opts,
+		log:      log.With("component", "EventService"),
+		subs:     make(map[*eventSubscriber]struct{}),
+	}
+}
+
+// Run слушает уведомления и раздаёт новые записи; блокируется до отмены ctx, после чего закрывает ленту
+func (s *EventService) Run(ctx context.Context) error {
+	defer s.Close()
+
+	notify := make(chan struct{}, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.listen(ctx, notify)
+	}()
+	defer wg.Wait()
+
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// listen держит LISTEN; при обрыве переподключается, пока не отменён ctx.
+// Без уведомлений лента продолжает работать на опросе раз в PollInterval.
+func (s *EventService) listen(ctx context.Context, notify chan<- struct{}) {
+	for {
+		err := s.notifier.Listen(ctx, notify)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.log.Ctx(ctx).Error("audit listener failed",
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.opts.PollInterval):
+		}
+	}
+}
+
+// poll раздаёт все записи после lastID. До первого успешного чтения головы цепочки
+// лента не принимает подписчиков: без неё нельзя понять, что клиент пропустил.
+func (s *EventService) poll(ctx context.Context) {
+	log := s.log.Ctx(ctx)
+
+	s.mu.Lock()
+	ready, lastID := s.ready, s.lastID
+	s.mu.Unlock()
+
+	if !ready {
+		id, err := s.repo.LastID(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Error("failed to read audit head",
+					"error", err,
+				)
+			}
+			return
+		}
+
+		s.mu.Lock()
+		s.lastID, s.ready = id, true
+		s.mu.Unlock()
+		return
+	}
+
+	for {
+		entries, err := s.repo.ListAfter(ctx, lastID, eventFetchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Error("failed to read new audit entries",
+					"error", err,
+					"after_id", lastID,
+				)
+			}
+			return
+		}
+		if len(entries) == 0 {
+			return
+		}
+
+		s.broadcast(entries)
+		lastID = entries[len(entries)-1].ID
+
+		if len(entries) < eventFetchSize {
+			return
+		}
+	}
+}
+
+func (s *EventService) broadcast(entries []*domain.AuditEntryWithUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range entries {
+		for sub := range s.subs {
+			ev := domain.ChangeEventFor(sub.role, e)
+			if ev == nil {
+				continue
+			}
+			select {
+			case sub.ch <- ev:
+			default:
+				// медленный клиент переподключится с Last-Event-ID и дочитает пропущенное
+				s.drop(sub)
+			}
+		}
+		s.lastID = e.ID
+	}
+}
+
+// drop закрывает подписку; вызывается под s.mu
+func (s *EventService) drop(sub *eventSubscriber) {
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Subscribe подписывает на ленту. lastEventID - id последней полученной клиентом записи (Last-Event-ID),
+// 0 - только новые события.
+func (s *EventService) Subscribe(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	lastEventID int64,
+) (*domain.EventSubscription, error) {
+	const op = "EventService.Subscribe"
+
+	if !claims.Role.CanView() {
+		return nil, domain.ErrForbidden
+	}
+	if lastEventID < 0 {
+		return nil, &domain.ValidationError{Field: "Last-Event-ID", Reason: "must be non-negative"}
+	}
+
+	sub := &eventSubscriber{
+		role: claims.Role,
+		ch:   make(chan *domain.ChangeEvent, s.opts.Buffer),
+	}
+
+	// подписчик регистрируется до чтения пропущенного: всё после head придёт в канал
+	s.mu.Lock()
+	if !s.ready || s.closed {
+		s.mu.Unlock()
+		return nil, domain.ErrEventsUnavailable
+	}
+	s.subs[sub] = struct{}{}
+	head := s.lastID
+	s.mu.Unlock()
+
+	subscription := domain.NewEventSubscription(sub.ch, func() {
+		s.mu.Lock()
+		s.drop(sub)
+		s.mu.Unlock()
+	})
+
+	switch {
+	case lastEventID == 0 || lastEventID == head:
+	case lastEventID > head:
+		subscription.Reset = true
+	default:
+		replay, reset, err := s.replay(ctx, claims.Role, lastEventID, head)
+		if err != nil {
+			subscription.Close()
+			s.log.Ctx(ctx).Error("failed to replay audit events",
+				"error", err,
+				"last_event_id", lastEventID,
+			)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		subscription.Replay, subscription.Reset = replay, reset
+	}
+
+	return subscription, nil
+}
+
+// replay - события (afterID, head], видимые роли; reset, если записей больше ReplayLimit
+func (s *EventService) replay(
+	ctx context.Context,
+	role domain.Role,
+	afterID, head int64,
+) ([]*domain.ChangeEvent, bool, error) {
+	entries, err := s.repo.ListAfter(ctx, afterID, s.opts.ReplayLimit+1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var events []*domain.ChangeEvent
+	for i, e := range entries {
+		if e.ID > head {
+			break
+		}
+		if i == s.opts.ReplayLimit {
+			return nil, true, nil
+		}
+		if ev := domain.ChangeEventFor(role, e); ev != nil {
+			events = append(events, ev)
+		}
+	}
+	return events, false, nil
+}
+
+// Close закрывает ленту: подписки завершаются, новые не принимаются.
+// Вызывается при остановке HTTP-сервера, чтобы открытые потоки не задерживали shutdown.
+func (s *EventService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subs {
+		s.drop(sub)
+	}
+}
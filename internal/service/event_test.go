@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventService(t *testing.T, opts EventOptions) (*EventService, *mockauditFeedRepository) {
+	repo := newMockauditFeedRepository(t)
+	svc := NewEventService(repo, newMockauditNotifier(t), opts, newTestLogger())
+	return svc, repo
+}
+
+// readyEventService - лента, уже прочитавшая голову цепочки head
+func readyEventService(t *testing.T, head int64, opts EventOptions) (*EventService, *mockauditFeedRepository) {
+	svc, repo := newEventService(t, opts)
+	repo.EXPECT().LastID(mock.Anything).Return(head, nil).Once()
+	svc.poll(context.Background())
+	return svc, repo
+}
+
+func auditEvent(id int64, entity domain.AuditEntity) *domain.AuditEntryWithUser {
+	return &domain.AuditEntryWithUser{
+		AuditEntry: domain.AuditEntry{
+			ID:         id,
+			EntityType: entity,
+			EntityID:   uuid.New(),
+			Action:     domain.AuditUpdate,
+			ChangedAt:  time.Now(),
+		},
+		Username: "manager",
+	}
+}
+
+func TestEventService_Subscribe_NotReady(t *testing.T) {
+	svc, _ := newEventService(t, EventOptions{})
+
+	_, err := svc.Subscribe(context.Background(), viewerClaims, 0)
+
+	assert.ErrorIs(t, err, domain.ErrEventsUnavailable)
+}
+
+func TestEventService_Broadcast_RoleFilter(t *testing.T) {
+	svc, repo := readyEventService(t, 10, EventOptions{})
+
+	viewer, err := svc.Subscribe(context.Background(), viewerClaims, 0)
+	require.NoError(t, err)
+	defer viewer.Close()
+	manager, err := svc.Subscribe(context.Background(), managerClaims, 0)
+	require.NoError(t, err)
+	defer manager.Close()
+
+	repo.EXPECT().ListAfter(mock.Anything, int64(10), eventFetchSize).Return([]*domain.AuditEntryWithUser{
+		auditEvent(11, domain.AuditEntityItem),
+		auditEvent(12, domain.AuditEntityUser),
+	}, nil).Once()
+
+	svc.poll(context.Background())
+
+	ev := <-viewer.Events
+	assert.Equal(t, int64(11), ev.Entry.ID)
+	assert.False(t, ev.Detailed)
+	assert.Empty(t, viewer.Events, "user events are not visible to viewers")
+
+	require.Len(t, manager.Events, 2)
+	ev = <-manager.Events
+	assert.True(t, ev.Detailed)
+	ev = <-manager.Events
+	assert.Equal(t, domain.AuditEntityUser, ev.Entry.EntityType)
+
+	// следующий опрос продолжает с последнего id
+	repo.EXPECT().ListAfter(mock.Anything, int64(12), eventFetchSize).Return(nil, nil).Once()
+	svc.poll(context.Background())
+}
+
+func TestEventService_Subscribe_Replay(t *testing.T) {
+	svc, repo := readyEventService(t, 12, EventOptions{})
+
+	repo.EXPECT().ListAfter(mock.Anything, int64(9), 1001).Return([]*domain.AuditEntryWithUser{
+		auditEvent(10, domain.AuditEntityItem),
+		auditEvent(11, domain.AuditEntityUser),
+		auditEvent(12, domain.AuditEntityItem),
+		// запись после головы придёт через канал, в replay её нет
+		auditEvent(13, domain.AuditEntityItem),
+	}, nil)
+
+	sub, err := svc.Subscribe(context.Background(), viewerClaims, 9)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	assert.False(t, sub.Reset)
+	require.Len(t, sub.Replay, 2)
+	assert.Equal(t, int64(10), sub.Replay[0].Entry.ID)
+	assert.Equal(t, int64(12), sub.Replay[1].Entry.ID)
+}
+
+func TestEventService_Subscribe_ReplayLimit(t *testing.T) {
+	svc, repo := readyEventService(t, 20, EventOptions{ReplayLimit: 2})
+
+	repo.EXPECT().ListAfter(mock.Anything, int64(5), 3).Return([]*domain.AuditEntryWithUser{
+		auditEvent(6, domain.AuditEntityItem),
+		auditEvent(7, domain.AuditEntityItem),
+		auditEvent(8, domain.AuditEntityItem),
+	}, nil)
+
+	sub, err := svc.Subscribe(context.Background(), managerClaims, 5)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	assert.True(t, sub.Reset)
+	assert.Empty(t, sub.Replay)
+}
+
+func TestEventService_Subscribe_UnknownLastEventID(t *testing.T) {
+	svc, _ := readyEventService(t, 20, EventOptions{})
+
+	sub, err := svc.Subscribe(context.Background(), managerClaims, 25)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	assert.True(t, sub.Reset)
+}
+
+func TestEventService_Subscribe_ReplayError(t *testing.T) {
+	svc, repo := readyEventService(t, 20, EventOptions{})
+	repo.EXPECT().ListAfter(mock.Anything, int64(5), 1001).Return(nil, errors.New("db error"))
+
+	_, err := svc.Subscribe(context.Background(), managerClaims, 5)
+
+	require.Error(t, err)
+	assert.Empty(t, svc.subs, "failed subscription must be removed")
+}
+
+func TestEventService_SlowSubscriberDropped(t *testing.T) {
+	svc, repo := readyEventService(t, 0, EventOptions{Buffer: 1})
+
+	sub, err := svc.Subscribe(context.Background(), managerClaims, 0)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	repo.EXPECT().ListAfter(mock.Anything, int64(0), eventFetchSize).Return([]*domain.AuditEntryWithUser{
+		auditEvent(1, domain.AuditEntityItem),
+		auditEvent(2, domain.AuditEntityItem),
+	}, nil)
+
+	svc.poll(context.Background())
+
+	ev, ok := <-sub.Events
+	require.True(t, ok)
+	assert.Equal(t, int64(1), ev.Entry.ID)
+	_, ok = <-sub.Events
+	assert.False(t, ok, "subscription must be closed on overflow")
+}
+
+func TestEventService_Close(t *testing.T) {
+	svc, _ := readyEventService(t, 0, EventOptions{})
+
+	sub, err := svc.Subscribe(context.Background(), viewerClaims, 0)
+	require.NoError(t, err)
+
+	svc.Close()
+	sub.Close()
+
+	_, ok := <-sub.Events
+	assert.False(t, ok)
+	_, err = svc.Subscribe(context.Background(), viewerClaims, 0)
+	assert.ErrorIs(t, err, domain.ErrEventsUnavailable)
+}
+
+func TestEventService_Run_Notify(t *testing.T) {
+	repo := newMockauditFeedRepository(t)
+	notifier := newMockauditNotifier(t)
+	svc := NewEventService(repo, notifier, EventOptions{PollInterval: time.Hour}, newTestLogger())
+
+	listening := make(chan chan<- struct{}, 1)
+	notifier.EXPECT().Listen(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, notify chan<- struct{}) error {
+			listening <- notify
+			<-ctx.Done()
+			return nil
+		})
+	repo.EXPECT().LastID(mock.Anything).Return(3, nil)
+	repo.EXPECT().ListAfter(mock.Anything, int64(3), eventFetchSize).
+		Return([]*domain.AuditEntryWithUser{auditEvent(4, domain.AuditEntityItem)}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	notify := <-listening
+	var sub *domain.EventSubscription
+	require.Eventually(t, func() bool {
+		var err error
+		sub, err = svc.Subscribe(context.Background(), viewerClaims, 0)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	notify <- struct{}{}
+
+	select {
+	case ev := <-sub.Events:
+		assert.Equal(t, int64(4), ev.Entry.ID)
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+	_, ok := <-sub.Events
+	assert.False(t, ok, "feed must be closed when Run returns")
+}
@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
+	"github.com/wb-go/wbf/logger"
+)
+
+// exportProgressEvery - как часто (в строках) сохранять прогресс задачи
+const exportProgressEvery = 5000
+
+type exportJobRepository interface {
+	Create(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)
+	ClaimNext(ctx context.Context) (*domain.ExportJob, error)
+	UpdateProgress(ctx context.Context, id uuid.UUID, processed, total int64) error
+	Complete(ctx context.Context, id uuid.UUID, fileName string, fileSize, processed int64, expiresAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error
+	RequeueRunning(ctx context.Context) (int64, error)
+	DeleteExpired(ctx context.Context, now time.Time) ([]*domain.ExportJob, error)
+}
+
+// ExportJobOptions - параметры пула фоновых выгрузок
+type ExportJobOptions struct {
+	Dir             string        // каталог для готовых файлов
+	Workers         int           // число параллельных выгрузок
+	TTL             time.Duration // сколько хранится результат
+	PollInterval    time.Duration // как часто воркер проверяет очередь без сигнала
+	CleanupInterval time.Duration // как часто удаляются просроченные задачи
+}
+
+// ExportJobService - очередь фоновых выгрузок аудита и каталога.
+// Задачи лежат в export_jobs, воркеры забирают их через ClaimNext и пишут файл в opts.Dir.
+type ExportJobService struct {
+	jobRepo   exportJobRepository
+	itemRepo  itemRepository
+	auditRepo auditRepository
+	opts      ExportJobOptions
+	log       logger.Logger
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+func NewExportJobService(
+	jobRepo exportJobRepository,
+	itemRepo itemRepository,
+	auditRepo auditRepository,
+	opts ExportJobOptions,
+	log logger.Logger,
+) *ExportJobService {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	return &ExportJobService{
+		jobRepo:   jobRepo,
+		itemRepo:  itemRepo,
+		auditRepo: auditRepo,
+		opts:      opts,
+		log:       log.With("component", "ExportJobService"),
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+	}
+}
+
+func (s *ExportJobService) Create(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	input *domain.CreateExportJobInput,
+) (*domain.ExportJob, error) {
+	const op = "ExportJobService.Create"
+
+	if !claims.Role.CanExport() {
+		return nil, domain.ErrForbidden
+	}
+
+	job, err := newExportJob(claims, input)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err = s.jobRepo.Create(ctx, job)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to create export job",
+			"error", err,
+			"user_id", claims.UserID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.notify()
+	return job, nil
+}
+
+// Get - задача видна своему автору и администратору; чужая выглядит как несуществующая
+func (s *ExportJobService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.ExportJob, error) {
+	const op = "ExportJobService.Get"
+
+	if !claims.Role.CanExport() {
+		return nil, domain.ErrForbidden
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to get export job",
+			"error", err,
+			"job_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if job.CreatedBy != claims.UserID && claims.Role != domain.RoleAdmin {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+// Download возвращает задачу и путь к готовому файлу
+func (s *ExportJobService) Download(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	id uuid.UUID,
+) (*domain.ExportJob, string, error) {
+	job, err := s.Get(ctx, claims, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if job.Status != domain.ExportJobDone {
+		return nil, "", domain.ErrExportNotReady
+	}
+
+	path := filepath.Join(s.opts.Dir, job.FileName)
+	if _, err = os.Stat(path); err != nil {
+		s.log.Ctx(ctx).Error("export file is missing",
+			"error", err,
+			"job_id", id,
+		)
+		return nil, "", domain.ErrNotFound
+	}
+	return job, path, nil
+}
+
+// Run запускает воркеры и чистку просроченных файлов; блокируется до отмены ctx
+// и возвращается, когда все воркеры остановлены.
+func (s *ExportJobService) Run(ctx context.Context) error {
+	if err := os.MkdirAll(s.opts.Dir, 0o750); err != nil {
+		return fmt.Errorf("create export dir: %w", err)
+	}
+
+	if n, err := s.jobRepo.RequeueRunning(ctx); err != nil {
+		s.log.Ctx(ctx).Error("failed to requeue interrupted export jobs",
+			"error", err,
+		)
+	} else if n > 0 {
+		s.log.Ctx(ctx).Info("interrupted export jobs requeued",
+			"count", n,
+		)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.worker(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.cleanupLoop(ctx)
+	}()
+
+	wg.Wait()
+	return nil
+}
+
+func (s *ExportJobService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *ExportJobService) worker(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		// разбираем очередь, пока в ней есть задачи
+		for ctx.Err() == nil {
+			job, err := s.jobRepo.ClaimNext(ctx)
+			if errors.Is(err, domain.ErrNotFound) {
+				break
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					s.log.Ctx(ctx).Error("failed to claim export job",
+						"error", err,
+					)
+				}
+				break
+			}
+			s.process(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ExportJobService) process(ctx context.Context, job *domain.ExportJob) {
+	log := s.log.Ctx(ctx).With("job_id", job.ID, "type", job.Type)
+
+	fileName := fmt.Sprintf("%s_%s.%s", job.Type, job.ID, export.Format(job.Format).Extension())
+	path := filepath.Join(s.opts.Dir, fileName)
+	tmp := path + ".part"
+
+	rows, err := s.writeFile(ctx, job, tmp)
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		if ctx.Err() != nil {
+			// остановка приложения: задача останется running и вернётся в очередь при следующем запуске
+			log.Info("export job interrupted by shutdown")
+			return
+		}
+
+		log.Error("export job failed",
+			"error", err,
+		)
+		if err = s.jobRepo.Fail(ctx, job.ID, "export failed", s.now().Add(s.opts.TTL)); err != nil {
+			log.Error("failed to mark export job failed",
+				"error", err,
+			)
+		}
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Error("failed to stat export file",
+			"error", err,
+		)
+		return
+	}
+
+	if err = s.jobRepo.Complete(ctx, job.ID, fileName, info.Size(), rows, s.now().Add(s.opts.TTL)); err != nil {
+		log.Error("failed to mark export job done",
+			"error", err,
+		)
+		return
+	}
+	log.Info("export job done",
+		"rows", rows,
+		"size", info.Size(),
+	)
+}
+
+func (s *ExportJobService) writeFile(ctx context.Context, job *domain.ExportJob, path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("create file: %w", err)
+	}
+	defer f.Close()
+
+	var rows int64
+	switch job.Type {
+	case domain.ExportJobItems:
+		rows, err = s.writeItems(ctx, job, f)
+	case domain.ExportJobAudit:
+		rows, err = s.writeAudit(ctx, job, f)
+	default:
+		err = fmt.Errorf("unknown export type %q", job.Type)
+	}
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, f.Close()
+}
+
+func (s *ExportJobService) writeItems(ctx context.Context, job *domain.ExportJob, w io.Writer) (int64, error) {
+	_, total, err := s.itemRepo.List(ctx, job.ItemFilter, 1, 0)
+	if err != nil {
+		return 0, fmt.Errorf("count items: %w", err)
+	}
+
+	iw, err := export.NewItemWriter(w, export.Format(job.Format), job.Columns)
+	if err != nil {
+		return 0, err
+	}
+
+	progress := s.newProgress(ctx, job.ID, total)
+	err = streamItems(ctx, s.itemRepo, job.ItemFilter, func(item *domain.Item) error {
+		if err := iw.Write(item); err != nil {
+			return err
+		}
+		progress.inc()
+		return nil
+	})
+	if err != nil {
+		return progress.rows, err
+	}
+	return progress.rows, iw.Close()
+}
+
+func (s *ExportJobService) writeAudit(ctx context.Context, job *domain.ExportJob, w io.Writer) (int64, error) {
+	_, total, err := s.auditRepo.List(ctx, job.AuditFilter, 1, 0)
+	if err != nil {
+		return 0, fmt.Errorf("count audit: %w", err)
+	}
+
+	aw, err := export.NewAuditWriter(w, export.Format(job.Format))
+	if err != nil {
+		return 0, err
+	}
+
+	progress := s.newProgress(ctx, job.ID, total)
+	err = s.auditRepo.Stream(ctx, job.AuditFilter, func(e *domain.AuditEntryWithUser) error {
+		if err := aw.Write(e); err != nil {
+			return err
+		}
+		progress.inc()
+		return nil
+	})
+	if err != nil {
+		return progress.rows, err
+	}
+	return progress.rows, aw.Close()
+}
+
+func (s *ExportJobService) cleanupLoop(ctx context.Context) {
+	interval := s.opts.CleanupInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup удаляет просроченные задачи вместе с файлами
+func (s *ExportJobService) cleanup(ctx context.Context) {
+	jobs, err := s.jobRepo.DeleteExpired(ctx, s.now())
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Ctx(ctx).Error("failed to delete expired export jobs",
+				"error", err,
+			)
+		}
+		return
+	}
+
+	for _, job := range jobs {
+		if job.FileName == "" {
+			continue
+		}
+		err = os.Remove(filepath.Join(s.opts.Dir, job.FileName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.log.Ctx(ctx).Error("failed to remove expired export file",
+				"error", err,
+				"job_id", job.ID,
+			)
+		}
+	}
+}
+
+func (s *ExportJobService) pollInterval() time.Duration {
+	if s.opts.PollInterval <= 0 {
+		return 2 * time.Second
+	}
+	return s.opts.PollInterval
+}
+
+// exportProgress копит счётчик строк и раз в exportProgressEvery строк сохраняет его в задачу
+type exportProgress struct {
+	ctx   context.Context
+	s     *ExportJobService
+	id    uuid.UUID
+	total int64
+	rows  int64
+}
+
+func (s *ExportJobService) newProgress(ctx context.Context, id uuid.UUID, total int64) *exportProgress {
+	p := &exportProgress{ctx: ctx, s: s, id: id, total: total}
+	p.save()
+	return p
+}
+
+func (p *exportProgress) inc() {
+	p.rows++
+	if p.rows%exportProgressEvery == 0 {
+		p.save()
+	}
+}
+
+func (p *exportProgress) save() {
+	// total - оценка на момент старта: строки могли добавиться во время выгрузки
+	total := max(p.total, p.rows)
+	if err := p.s.jobRepo.UpdateProgress(p.ctx, p.id, p.rows, total); err != nil && p.ctx.Err() == nil {
+		p.s.log.Ctx(p.ctx).Warn("failed to save export progress",
+			"error", err,
+			"job_id", p.id,
+		)
+	}
+}
+
+// newExportJob проверяет параметры выгрузки и приводит формат и колонки к каноническому виду
+func newExportJob(claims *domain.AuthClaims, input *domain.CreateExportJobInput) (*domain.ExportJob, error) {
+	if !input.Type.IsValid() {
+		return nil, &domain.ValidationError{Field: "type", Reason: "must be one of: audit, items"}
+	}
+
+	formats := export.ItemFormats
+	if input.Type == domain.ExportJobAudit {
+		formats = export.AuditFormats
+	}
+	format, err := export.ParseFormat(input.Format, formats)
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "format", Reason: "must be one of: " + export.FormatList(formats)}
+	}
+
+	job := &domain.ExportJob{
+		Type:      input.Type,
+		Format:    string(format),
+		CreatedBy: claims.UserID,
+	}
+
+	switch input.Type {
+	case domain.ExportJobItems:
+		columns, err := export.ParseItemColumns(strings.Join(input.Columns, ","))
+		if err != nil {
+			return nil, &domain.ValidationError{Field: "columns", Reason: err.Error()}
+		}
+		job.Columns = columns
+		job.ItemFilter = input.ItemFilter
+		if job.ItemFilter == nil {
+			job.ItemFilter = &domain.ItemFilter{}
+		}
+	case domain.ExportJobAudit:
+		if len(input.Columns) > 0 {
+			return nil, &domain.ValidationError{Field: "columns", Reason: "not supported for audit export"}
+		}
+		job.AuditFilter = input.AuditFilter
+		if job.AuditFilter == nil {
+			job.AuditFilter = &domain.AuditFilter{}
+		}
+		if job.AuditFilter.Action != nil && !job.AuditFilter.Action.IsValid() {
+			return nil, &domain.ValidationError{Field: "action", Reason: "must be one of: INSERT, UPDATE, DELETE"}
+		}
+		if job.AuditFilter.EntityType != nil && !job.AuditFilter.EntityType.IsValid() {
+			return nil, &domain.ValidationError{Field: "entity_type", Reason: "must be one of: item, user"}
+		}
+		if !job.AuditFilter.IsValid() {
+			return nil, &domain.ValidationError{Field: "filter", Reason: "invalid field or change condition"}
+		}
+	}
+
+	return job, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type exportJobMocks struct {
+	jobs  *mockexportJobRepository
+	items *mockitemRepository
+	audit *mockauditRepository
+}
+
+func newExportJobService(t *testing.T) (*ExportJobService, *exportJobMocks) {
+	m := &exportJobMocks{
+		jobs:  newMockexportJobRepository(t),
+		items: newMockitemRepository(t),
+		audit: newMockauditRepository(t),
+	}
+	svc := NewExportJobService(m.jobs, m.items, m.audit, ExportJobOptions{
+		Dir:     t.TempDir(),
+		Workers: 1,
+		TTL:     time.Hour,
+	}, newTestLogger())
+	return svc, m
+}
+
+func TestExportJobService_Create_Items(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	search := "lap"
+	m.jobs.EXPECT().Create(mock.Anything, mock.MatchedBy(func(job *domain.ExportJob) bool {
+		return job.Type == domain.ExportJobItems &&
+			job.Format == "xlsx" &&
+			*job.ItemFilter.Search == "lap" &&
+			strings.Join(job.Columns, ",") == "sku,quantity" &&
+			job.CreatedBy == managerClaims.UserID
+	})).RunAndReturn(func(_ context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+		job.ID = uuid.New()
+		job.Status = domain.ExportJobPending
+		return job, nil
+	})
+
+	job, err := svc.Create(context.Background(), managerClaims, &domain.CreateExportJobInput{
+		Type:       domain.ExportJobItems,
+		Format:     "XLSX",
+		ItemFilter: &domain.ItemFilter{Search: &search},
+		Columns:    []string{"SKU", "quantity"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExportJobPending, job.Status)
+	assert.Len(t, svc.wake, 1, "workers must be woken up")
+}
+
+func TestExportJobService_Create_Validation(t *testing.T) {
+	svc, _ := newExportJobService(t)
+
+	tests := []struct {
+		name  string
+		input *domain.CreateExportJobInput
+		field string
+	}{
+		{"unknown type", &domain.CreateExportJobInput{Type: "users"}, "type"},
+		{"items pdf", &domain.CreateExportJobInput{Type: domain.ExportJobItems, Format: "pdf"}, "format"},
+		{"unknown column", &domain.CreateExportJobInput{Type: domain.ExportJobItems, Columns: []string{"color"}}, "columns"},
+		{"audit unknown format", &domain.CreateExportJobInput{Type: domain.ExportJobAudit, Format: "docx"}, "format"},
+		{"audit columns", &domain.CreateExportJobInput{Type: domain.ExportJobAudit, Columns: []string{"sku"}}, "columns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), adminClaims, tt.input)
+
+			var verr *domain.ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.field, verr.Field)
+		})
+	}
+}
+
+func TestExportJobService_Create_ViewerForbidden(t *testing.T) {
+	svc, _ := newExportJobService(t)
+
+	_, err := svc.Create(context.Background(), viewerClaims, &domain.CreateExportJobInput{Type: domain.ExportJobAudit})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestExportJobService_Get_OnlyOwnerOrAdmin(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	job := &domain.ExportJob{ID: uuid.New(), CreatedBy: uuid.New(), Status: domain.ExportJobRunning}
+	m.jobs.EXPECT().GetByID(mock.Anything, job.ID).Return(job, nil)
+
+	_, err := svc.Get(context.Background(), managerClaims, job.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	got, err := svc.Get(context.Background(), adminClaims, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, job, got)
+}
+
+func TestExportJobService_Download_NotReady(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	job := &domain.ExportJob{ID: uuid.New(), CreatedBy: managerClaims.UserID, Status: domain.ExportJobRunning}
+	m.jobs.EXPECT().GetByID(mock.Anything, job.ID).Return(job, nil)
+
+	_, _, err := svc.Download(context.Background(), managerClaims, job.ID)
+
+	assert.ErrorIs(t, err, domain.ErrExportNotReady)
+}
+
+func TestExportJobService_ProcessItems(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	job := &domain.ExportJob{
+		ID:         uuid.New(),
+		Type:       domain.ExportJobItems,
+		Format:     "csv",
+		ItemFilter: &domain.ItemFilter{},
+		Columns:    []string{"sku", "price"},
+		CreatedBy:  managerClaims.UserID,
+		Status:     domain.ExportJobRunning,
+	}
+	items := []*domain.Item{
+		{ID: uuid.New(), SKU: "LAP-001", Price: decimal.NewFromInt(999)},
+		{ID: uuid.New(), SKU: "MOU-001", Price: decimal.NewFromInt(25)},
+	}
+	fileName := "items_" + job.ID.String() + ".csv"
+
+	m.items.EXPECT().List(mock.Anything, job.ItemFilter, 1, 0).Return(items[:1], int64(2), nil)
+	m.items.EXPECT().ListAfter(mock.Anything, job.ItemFilter, (*domain.ItemCursor)(nil), exportPageSize).Return(items, nil)
+	m.jobs.EXPECT().UpdateProgress(mock.Anything, job.ID, int64(0), int64(2)).Return(nil)
+	m.jobs.EXPECT().Complete(mock.Anything, job.ID, fileName, mock.AnythingOfType("int64"), int64(2), mock.Anything).Return(nil)
+
+	svc.process(context.Background(), job)
+
+	data, err := os.ReadFile(filepath.Join(svc.opts.Dir, fileName))
+	require.NoError(t, err)
+	assert.Equal(t, "sku,price\nLAP-001,999.00\nMOU-001,25.00\n", string(data))
+
+	_, err = os.Stat(filepath.Join(svc.opts.Dir, fileName+".part"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExportJobService_ProcessAuditFails(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	job := &domain.ExportJob{
+		ID:          uuid.New(),
+		Type:        domain.ExportJobAudit,
+		Format:      "csv",
+		AuditFilter: &domain.AuditFilter{},
+		Status:      domain.ExportJobRunning,
+	}
+
+	m.audit.EXPECT().List(mock.Anything, job.AuditFilter, 1, 0).Return(nil, int64(10), nil)
+	m.jobs.EXPECT().UpdateProgress(mock.Anything, job.ID, int64(0), int64(10)).Return(nil)
+	m.audit.EXPECT().Stream(mock.Anything, job.AuditFilter, mock.Anything).Return(errors.New("db error"))
+	m.jobs.EXPECT().Fail(mock.Anything, job.ID, "export failed", mock.Anything).Return(nil)
+
+	svc.process(context.Background(), job)
+
+	entries, err := os.ReadDir(svc.opts.Dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "partial file must be removed")
+}
+
+func TestExportJobService_Cleanup(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	expired := &domain.ExportJob{ID: uuid.New(), FileName: "items_old.csv"}
+	path := filepath.Join(svc.opts.Dir, expired.FileName)
+	require.NoError(t, os.WriteFile(path, []byte("sku\n"), 0o600))
+
+	m.jobs.EXPECT().DeleteExpired(mock.Anything, mock.Anything).
+		Return([]*domain.ExportJob{expired, {ID: uuid.New()}}, nil)
+
+	svc.cleanup(context.Background())
+
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExportJobService_Run_StopsOnCancel(t *testing.T) {
+	svc, m := newExportJobService(t)
+
+	m.jobs.EXPECT().RequeueRunning(mock.Anything).Return(int64(0), nil)
+	m.jobs.EXPECT().ClaimNext(mock.Anything).Return(nil, domain.ErrNotFound).Maybe()
+	m.jobs.EXPECT().DeleteExpired(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = svc.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after cancel")
+	}
+}
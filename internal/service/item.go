@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/wb-go/wbf/logger"
 )
 
@@ -14,6 +16,10 @@ const (
 	defaultPage     = 1
 	defaultPageSize = 20
 	maxPageSize     = 100
+
+	maxBatchSize = 1000
+
+	exportPageSize = 1000
 )
 
 type itemRepository interface {
@@ -21,7 +27,11 @@ type itemRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Item, error)
 	List(ctx context.Context, filter *domain.ItemFilter, limit, offset int) ([]*domain.Item, int64, error)
 	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error)
+	AdjustQuantity(ctx context.Context, userID uuid.UUID, id uuid.UUID, delta int) (*domain.Item, error)
 	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	Batch(ctx context.Context, userID uuid.UUID, ops []*domain.BatchOperation, mode domain.BatchMode) ([]*domain.BatchItemResult, error)
+	GetBySKUs(ctx context.Context, skus []string) ([]*domain.Item, error)
+	ListAfter(ctx context.Context, filter *domain.ItemFilter, after *domain.ItemCursor, limit int) ([]*domain.Item, error)
 }
 type ItemService struct {
 	itemRepo itemRepository
@@ -116,6 +126,56 @@ func (s *ItemService) ListItems(
 	}, nil
 }
 
+// Export выгружает каталог с тем же фильтром, что и ListItems.
+// Товары читаются страницами по exportPageSize и сразу пишутся в w.
+func (s *ItemService) Export(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	filter *domain.ItemFilter,
+	format export.Format,
+	columns []string,
+	w io.Writer,
+) error {
+	const op = "ItemService.Export"
+
+	if !claims.Role.CanExport() {
+		return domain.ErrForbidden
+	}
+
+	// writer создаётся на первом товаре: пока в w ничего не записано,
+	// ошибку БД ещё можно вернуть клиенту статусом
+	var iw export.ItemWriter
+	newWriter := func() (err error) {
+		iw, err = export.NewItemWriter(w, format, columns)
+		return err
+	}
+
+	err := streamItems(ctx, s.itemRepo, filter, func(item *domain.Item) error {
+		if iw == nil {
+			if err := newWriter(); err != nil {
+				return err
+			}
+		}
+		return iw.Write(item)
+	})
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to export items",
+			"error", err,
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if iw == nil {
+		if err = newWriter(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err = iw.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
 func (s *ItemService) Update(
 	ctx context.Context,
 	claims *domain.AuthClaims,
@@ -151,6 +211,45 @@ func (s *ItemService) Update(
 	return item, nil
 }
 
+func (s *ItemService) AdjustQuantity(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	id uuid.UUID,
+	input *domain.AdjustQuantityInput,
+) (*domain.Item, error) {
+	const op = "ItemService.AdjustQuantity"
+
+	if !claims.Role.CanUpdate() {
+		return nil, domain.ErrForbidden
+	}
+
+	if input.Delta == 0 {
+		return nil, domain.ErrNoChanges
+	}
+
+	item, err := s.itemRepo.AdjustQuantity(ctx, claims.UserID, id, input.Delta)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			return nil, domain.ErrInsufficientStock
+		}
+		if errors.Is(err, domain.ErrValidation) {
+			return nil, err
+		}
+		s.log.Ctx(ctx).Error("failed to adjust item quantity",
+			"error", err,
+			"item_id", id,
+			"user_id", claims.UserID,
+			"delta", input.Delta,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
 func (s *ItemService) Delete(
 	ctx context.Context,
 	claims *domain.AuthClaims,
@@ -178,6 +277,251 @@ func (s *ItemService) Delete(
 	return nil
 }
 
+// Batch применяет пачку create/update/delete операций.
+// Права и обязательные поля проверяются до обращения к БД: в атомарном режиме первая
+// же ошибка отменяет всю пачку (*domain.BatchOpError), в best effort она попадает в результат строки.
+func (s *ItemService) Batch(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	ops []*domain.BatchOperation,
+	mode domain.BatchMode,
+) (*domain.BatchResult, error) {
+	const op = "ItemService.Batch"
+
+	if mode == "" {
+		mode = domain.BatchAtomic
+	}
+	if !mode.IsValid() || len(ops) == 0 || len(ops) > maxBatchSize {
+		return nil, domain.ErrValidation
+	}
+
+	results := make([]*domain.BatchItemResult, len(ops))
+	pending := make([]*domain.BatchOperation, 0, len(ops))
+	pendingIdx := make([]int, 0, len(ops))
+
+	for idx, bop := range ops {
+		if err := checkBatchOp(claims.Role, bop); err != nil {
+			if mode == domain.BatchAtomic {
+				return nil, &domain.BatchOpError{Index: idx, Err: err}
+			}
+			results[idx] = &domain.BatchItemResult{Index: idx, Op: bop.Op, ID: bop.ID, Err: err}
+			continue
+		}
+		pending = append(pending, bop)
+		pendingIdx = append(pendingIdx, idx)
+	}
+
+	if len(pending) > 0 {
+		repoResults, err := s.itemRepo.Batch(ctx, claims.UserID, pending, mode)
+		if err != nil {
+			var opErr *domain.BatchOpError
+			if errors.As(err, &opErr) && isItemDomainError(opErr.Err) {
+				return nil, &domain.BatchOpError{Index: pendingIdx[opErr.Index], Err: opErr.Err}
+			}
+			s.log.Ctx(ctx).Error("failed to apply item batch",
+				"error", err,
+				"user_id", claims.UserID,
+				"mode", mode,
+				"size", len(ops),
+			)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for j, res := range repoResults {
+			res.Index = pendingIdx[j]
+			results[res.Index] = res
+			if res.Err != nil && !isItemDomainError(res.Err) {
+				s.log.Ctx(ctx).Error("failed to apply batch operation",
+					"error", res.Err,
+					"user_id", claims.UserID,
+					"index", res.Index,
+					"op", res.Op,
+				)
+			}
+		}
+	}
+
+	batch := &domain.BatchResult{Mode: mode, Results: results}
+	for _, res := range results {
+		if res.Err != nil {
+			batch.Failed++
+		} else {
+			batch.Succeeded++
+		}
+	}
+
+	return batch, nil
+}
+
+// Import выполняет upsert товаров по SKU из разобранного файла.
+// Строки с ошибками валидации пропускаются и попадают в отчёт; при dryRun БД не изменяется,
+// а отчёт показывает, какие строки были бы созданы, обновлены или остались без изменений.
+func (s *ItemService) Import(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	rows []*domain.ImportRow,
+	dryRun bool,
+) (*domain.ImportReport, error) {
+	const op = "ItemService.Import"
+
+	if !claims.Role.CanCreate() || !claims.Role.CanUpdate() {
+		return nil, domain.ErrForbidden
+	}
+
+	report := &domain.ImportReport{
+		DryRun: dryRun,
+		Rows:   make([]*domain.ImportRowResult, len(rows)),
+	}
+
+	seen := make(map[string]int, len(rows))
+	skus := make([]string, 0, len(rows))
+	for idx, row := range rows {
+		res := &domain.ImportRowResult{Line: row.Line, SKU: row.SKU}
+		report.Rows[idx] = res
+
+		if row.Err != nil {
+			res.Action, res.Err = domain.ImportError, row.Err
+			continue
+		}
+		if line, dup := seen[row.Input.SKU]; dup {
+			res.Action = domain.ImportError
+			res.Err = &domain.ValidationError{Field: "sku", Reason: fmt.Sprintf("duplicates row %d", line)}
+			continue
+		}
+		seen[row.Input.SKU] = row.Line
+		skus = append(skus, row.Input.SKU)
+	}
+
+	items, err := s.itemRepo.GetBySKUs(ctx, skus)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to look up items for import",
+			"error", err,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	existing := make(map[string]*domain.Item, len(items))
+	for _, item := range items {
+		existing[item.SKU] = item
+	}
+
+	var (
+		ops    []*domain.BatchOperation
+		opRows []*domain.ImportRowResult
+	)
+	for idx, row := range rows {
+		res := report.Rows[idx]
+		if res.Action == domain.ImportError {
+			continue
+		}
+
+		item, ok := existing[row.Input.SKU]
+		if !ok {
+			res.Action = domain.ImportCreate
+			ops = append(ops, &domain.BatchOperation{Op: domain.BatchCreate, Create: row.Input})
+			opRows = append(opRows, res)
+			continue
+		}
+
+		res.ItemID = &item.ID
+		changes := importChanges(item, row.Input)
+		if !changes.HasChanges() {
+			res.Action = domain.ImportUnchanged
+			continue
+		}
+		res.Action = domain.ImportUpdate
+		ops = append(ops, &domain.BatchOperation{Op: domain.BatchUpdate, ID: &item.ID, Update: changes})
+		opRows = append(opRows, res)
+	}
+
+	if !dryRun {
+		for start := 0; start < len(ops); start += maxBatchSize {
+			end := min(start+maxBatchSize, len(ops))
+
+			results, err := s.itemRepo.Batch(ctx, claims.UserID, ops[start:end], domain.BatchBestEffort)
+			if err != nil {
+				s.log.Ctx(ctx).Error("failed to import items",
+					"error", err,
+					"user_id", claims.UserID,
+				)
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			for j, r := range results {
+				res := opRows[start+j]
+				if r.Err != nil {
+					res.Action, res.Err = domain.ImportError, r.Err
+					continue
+				}
+				if r.Item != nil {
+					res.ItemID = &r.Item.ID
+				}
+			}
+		}
+	}
+
+	for _, res := range report.Rows {
+		switch res.Action {
+		case domain.ImportCreate:
+			report.Created++
+		case domain.ImportUpdate:
+			report.Updated++
+		case domain.ImportUnchanged:
+			report.Unchanged++
+		case domain.ImportError:
+			report.Failed++
+		}
+	}
+
+	return report, nil
+}
+
+// importChanges - partial update только по отличающимся полям.
+// Пустая локация в файле не затирает существующую.
+func importChanges(item *domain.Item, input *domain.CreateItemInput) *domain.UpdateItemInput {
+	changes := &domain.UpdateItemInput{}
+	if item.Name != input.Name {
+		changes.Name = &input.Name
+	}
+	if item.Quantity != input.Quantity {
+		changes.Quantity = &input.Quantity
+	}
+	if !item.Price.Equal(input.Price) {
+		changes.Price = &input.Price
+	}
+	if input.Location != nil && (item.Location == nil || *item.Location != *input.Location) {
+		changes.Location = input.Location
+	}
+	return changes
+}
+
+func checkBatchOp(role domain.Role, bop *domain.BatchOperation) error {
+	switch bop.Op {
+	case domain.BatchCreate:
+		if !role.CanCreate() {
+			return domain.ErrForbidden
+		}
+	case domain.BatchUpdate:
+		if !role.CanUpdate() {
+			return domain.ErrForbidden
+		}
+	case domain.BatchDelete:
+		if !role.CanDelete() {
+			return domain.ErrForbidden
+		}
+	}
+	return bop.Validate()
+}
+
+// isItemDomainError - ожидаемые ошибки операций над товаром, которые можно отдать клиенту как есть
+func isItemDomainError(err error) bool {
+	return errors.Is(err, domain.ErrNotFound) ||
+		errors.Is(err, domain.ErrDuplicateSKU) ||
+		errors.Is(err, domain.ErrInsufficientStock) ||
+		errors.Is(err, domain.ErrNoChanges) ||
+		errors.Is(err, domain.ErrValidation) ||
+		errors.Is(err, domain.ErrForbidden)
+}
+
 func normalizePagination(page, pageSize int) (int, int) {
 	if page < 1 {
 		page = defaultPage
@@ -198,3 +542,30 @@ func calcTotalPages(total int64, pageSize int) int {
 	}
 	return pages
 }
+
+// streamItems обходит каталог по фильтру keyset-страницами и передаёт товары в fn по одному
+func streamItems(
+	ctx context.Context,
+	repo itemRepository,
+	filter *domain.ItemFilter,
+	fn func(item *domain.Item) error,
+) error {
+	var after *domain.ItemCursor
+	for {
+		items, err := repo.ListAfter(ctx, filter, after, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+		if len(items) < exportPageSize {
+			return nil
+		}
+
+		last := items[len(items)-1]
+		after = &domain.ItemCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
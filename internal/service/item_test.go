@@ -1,15 +1,22 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/export"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -249,6 +256,87 @@ func TestItemService_Update_DuplicateSKU(t *testing.T) {
 	assert.ErrorIs(t, err, domain.ErrDuplicateSKU)
 }
 
+func TestItemService_AdjustQuantity_Success(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	itemID := uuid.New()
+	expected := &domain.Item{ID: itemID, Name: "Laptop", SKU: "LAP-001", Quantity: 15}
+
+	repo.EXPECT().AdjustQuantity(mock.Anything, managerClaims.UserID, itemID, 5).Return(expected, nil)
+
+	result, err := svc.AdjustQuantity(context.Background(), managerClaims, itemID, &domain.AdjustQuantityInput{Delta: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 15, result.Quantity)
+}
+
+func TestItemService_AdjustQuantity_ViewerForbidden(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	_, err := svc.AdjustQuantity(context.Background(), viewerClaims, uuid.New(), &domain.AdjustQuantityInput{Delta: 5})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestItemService_AdjustQuantity_ZeroDelta(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	_, err := svc.AdjustQuantity(context.Background(), adminClaims, uuid.New(), &domain.AdjustQuantityInput{Delta: 0})
+
+	assert.ErrorIs(t, err, domain.ErrNoChanges)
+}
+
+func TestItemService_AdjustQuantity_InsufficientStock(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	itemID := uuid.New()
+	repo.EXPECT().AdjustQuantity(mock.Anything, adminClaims.UserID, itemID, -100).
+		Return(nil, fmt.Errorf("ItemRepository.AdjustQuantity: %w", domain.ErrInsufficientStock))
+
+	_, err := svc.AdjustQuantity(context.Background(), adminClaims, itemID, &domain.AdjustQuantityInput{Delta: -100})
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+}
+
+func TestItemService_AdjustQuantity_OutOfRange(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	// delta в пределах int4, но остаток после изменения - нет (SQLSTATE 22003 в репозитории)
+	itemID := uuid.New()
+	outOfRange := &domain.ValidationError{Field: "delta", Reason: "resulting quantity is out of range"}
+	repo.EXPECT().AdjustQuantity(mock.Anything, adminClaims.UserID, itemID, math.MaxInt32).
+		Return(nil, fmt.Errorf("ItemRepository.AdjustQuantity: %w", outOfRange))
+
+	_, err := svc.AdjustQuantity(context.Background(), adminClaims, itemID, &domain.AdjustQuantityInput{Delta: math.MaxInt32})
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "delta", validationErr.Field)
+}
+
+func TestItemService_AdjustQuantity_NotFound(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	itemID := uuid.New()
+	repo.EXPECT().AdjustQuantity(mock.Anything, adminClaims.UserID, itemID, -3).Return(nil, domain.ErrNotFound)
+
+	_, err := svc.AdjustQuantity(context.Background(), adminClaims, itemID, &domain.AdjustQuantityInput{Delta: -3})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestItemService_AdjustQuantity_RepoError(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	itemID := uuid.New()
+	repo.EXPECT().AdjustQuantity(mock.Anything, adminClaims.UserID, itemID, 1).Return(nil, errors.New("db error"))
+
+	_, err := svc.AdjustQuantity(context.Background(), adminClaims, itemID, &domain.AdjustQuantityInput{Delta: 1})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ItemService.AdjustQuantity")
+}
+
 func TestItemService_Delete_AdminSuccess(t *testing.T) {
 	svc, repo := newItemService(t)
 
@@ -287,6 +375,294 @@ func TestItemService_Delete_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, domain.ErrNotFound)
 }
 
+func TestItemService_Batch_AtomicSuccess(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	itemID := uuid.New()
+	name := "Renamed"
+	ops := []*domain.BatchOperation{
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Mouse", SKU: "MOU-001", Price: decimal.NewFromInt(25)}},
+		{Op: domain.BatchUpdate, ID: &itemID, Update: &domain.UpdateItemInput{Name: &name}},
+	}
+
+	repo.EXPECT().Batch(mock.Anything, managerClaims.UserID, ops, domain.BatchAtomic).Return([]*domain.BatchItemResult{
+		{Op: domain.BatchCreate, Item: &domain.Item{ID: uuid.New(), Name: "Mouse"}},
+		{Op: domain.BatchUpdate, ID: &itemID, Item: &domain.Item{ID: itemID, Name: "Renamed"}},
+	}, nil)
+
+	result, err := svc.Batch(context.Background(), managerClaims, ops, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BatchAtomic, result.Mode)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, 1, result.Results[1].Index)
+}
+
+func TestItemService_Batch_AtomicForbiddenRow(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	itemID := uuid.New()
+	ops := []*domain.BatchOperation{
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Mouse", SKU: "MOU-001"}},
+		{Op: domain.BatchDelete, ID: &itemID},
+	}
+
+	_, err := svc.Batch(context.Background(), managerClaims, ops, domain.BatchAtomic)
+
+	var opErr *domain.BatchOpError
+	assert.ErrorAs(t, err, &opErr)
+	assert.Equal(t, 1, opErr.Index)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestItemService_Batch_AtomicRepoRowError(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	ops := []*domain.BatchOperation{
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Mouse", SKU: "MOU-001"}},
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Mouse", SKU: "MOU-001"}},
+	}
+
+	repo.EXPECT().Batch(mock.Anything, adminClaims.UserID, ops, domain.BatchAtomic).
+		Return(nil, fmt.Errorf("ItemRepository.Batch: %w", &domain.BatchOpError{Index: 1, Err: domain.ErrDuplicateSKU}))
+
+	_, err := svc.Batch(context.Background(), adminClaims, ops, domain.BatchAtomic)
+
+	var opErr *domain.BatchOpError
+	assert.ErrorAs(t, err, &opErr)
+	assert.Equal(t, 1, opErr.Index)
+	assert.ErrorIs(t, err, domain.ErrDuplicateSKU)
+}
+
+func TestItemService_Batch_BestEffortMergesResults(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	missingID := uuid.New()
+	deleteID := uuid.New()
+	ops := []*domain.BatchOperation{
+		{Op: domain.BatchUpdate, ID: &missingID},                                             // без changes - отсеется до БД
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-1"}}, // уйдёт в репозиторий
+		{Op: domain.BatchDelete, ID: &deleteID},                                              // уйдёт в репозиторий
+	}
+
+	repo.EXPECT().Batch(mock.Anything, adminClaims.UserID, ops[1:], domain.BatchBestEffort).Return([]*domain.BatchItemResult{
+		{Op: domain.BatchCreate, Item: &domain.Item{ID: uuid.New(), Name: "Hub"}},
+		{Op: domain.BatchDelete, ID: &deleteID, Err: domain.ErrNotFound},
+	}, nil)
+
+	result, err := svc.Batch(context.Background(), adminClaims, ops, domain.BatchBestEffort)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.ErrorIs(t, result.Results[0].Err, domain.ErrValidation)
+	assert.NoError(t, result.Results[1].Err)
+	assert.Equal(t, 2, result.Results[2].Index)
+	assert.ErrorIs(t, result.Results[2].Err, domain.ErrNotFound)
+}
+
+func TestItemService_Batch_InvalidRow(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	invalid := &domain.ValidationError{Field: "name", Reason: "is required"}
+	ops := []*domain.BatchOperation{
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-1"}},
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{SKU: "HUB-2"}, Invalid: invalid},
+	}
+
+	// атомарный режим: пачка отклоняется с индексом неверной строки
+	_, err := svc.Batch(context.Background(), adminClaims, ops, domain.BatchAtomic)
+	var opErr *domain.BatchOpError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, 1, opErr.Index)
+	assert.ErrorIs(t, err, invalid)
+
+	// best effort: остальные строки применяются, у неверной - своя ошибка
+	repo.EXPECT().Batch(mock.Anything, adminClaims.UserID, ops[:1], domain.BatchBestEffort).Return([]*domain.BatchItemResult{
+		{Op: domain.BatchCreate, Item: &domain.Item{ID: uuid.New(), Name: "Hub"}},
+	}, nil)
+
+	result, err := svc.Batch(context.Background(), adminClaims, ops, domain.BatchBestEffort)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.ErrorIs(t, result.Results[1].Err, invalid)
+}
+
+func TestItemService_Batch_BestEffortAllRejected(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	ops := []*domain.BatchOperation{
+		{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-1"}},
+	}
+
+	result, err := svc.Batch(context.Background(), viewerClaims, ops, domain.BatchBestEffort)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	assert.ErrorIs(t, result.Results[0].Err, domain.ErrForbidden)
+}
+
+func TestItemService_Batch_InvalidInput(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	_, err := svc.Batch(context.Background(), adminClaims, nil, domain.BatchAtomic)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	ops := []*domain.BatchOperation{{Op: domain.BatchCreate, Create: &domain.CreateItemInput{}}}
+	_, err = svc.Batch(context.Background(), adminClaims, ops, "partial")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestItemService_Batch_RepoError(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	ops := []*domain.BatchOperation{{Op: domain.BatchCreate, Create: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-1"}}}
+	repo.EXPECT().Batch(mock.Anything, adminClaims.UserID, ops, domain.BatchAtomic).Return(nil, errors.New("db error"))
+
+	_, err := svc.Batch(context.Background(), adminClaims, ops, domain.BatchAtomic)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ItemService.Batch")
+}
+
+func TestItemService_Import_DryRun(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	existing := &domain.Item{ID: uuid.New(), Name: "Laptop", SKU: "LAP-001", Quantity: 10, Price: decimal.NewFromInt(999)}
+	same := &domain.Item{ID: uuid.New(), Name: "Mouse", SKU: "MOU-001", Quantity: 5, Price: decimal.NewFromInt(25)}
+	rows := []*domain.ImportRow{
+		{Line: 2, SKU: "LAP-001", Input: &domain.CreateItemInput{Name: "Laptop", SKU: "LAP-001", Quantity: 12, Price: decimal.NewFromInt(999)}},
+		{Line: 3, SKU: "MOU-001", Input: &domain.CreateItemInput{Name: "Mouse", SKU: "MOU-001", Quantity: 5, Price: decimal.NewFromInt(25)}},
+		{Line: 4, SKU: "HUB-001", Input: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-001", Price: decimal.NewFromInt(40)}},
+		{Line: 5, SKU: "HUB-001", Input: &domain.CreateItemInput{Name: "Hub 2", SKU: "HUB-001", Price: decimal.NewFromInt(41)}},
+		{Line: 6, Err: &domain.ValidationError{Field: "price", Reason: "is required"}},
+	}
+
+	repo.EXPECT().GetBySKUs(mock.Anything, []string{"LAP-001", "MOU-001", "HUB-001"}).
+		Return([]*domain.Item{existing, same}, nil)
+
+	report, err := svc.Import(context.Background(), adminClaims, rows, true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, domain.ImportUpdate, report.Rows[0].Action)
+	assert.Equal(t, existing.ID, *report.Rows[0].ItemID)
+	assert.Equal(t, domain.ImportUnchanged, report.Rows[1].Action)
+	assert.Equal(t, domain.ImportCreate, report.Rows[2].Action)
+	assert.ErrorIs(t, report.Rows[3].Err, domain.ErrValidation)
+	assert.Contains(t, report.Rows[3].Err.Error(), "duplicates row 4")
+}
+
+func TestItemService_Import_Apply(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	existing := &domain.Item{ID: uuid.New(), Name: "Laptop", SKU: "LAP-001", Quantity: 10, Price: decimal.NewFromInt(999)}
+	created := &domain.Item{ID: uuid.New(), Name: "Hub", SKU: "HUB-001"}
+	rows := []*domain.ImportRow{
+		{Line: 2, SKU: "LAP-001", Input: &domain.CreateItemInput{Name: "Laptop", SKU: "LAP-001", Quantity: 10, Price: decimal.NewFromInt(899)}},
+		{Line: 3, SKU: "HUB-001", Input: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-001", Price: decimal.NewFromInt(40)}},
+		{Line: 4, SKU: "CAB-001", Input: &domain.CreateItemInput{Name: "Cable", SKU: "CAB-001", Price: decimal.NewFromInt(5)}},
+	}
+
+	repo.EXPECT().GetBySKUs(mock.Anything, []string{"LAP-001", "HUB-001", "CAB-001"}).
+		Return([]*domain.Item{existing}, nil)
+	repo.EXPECT().Batch(mock.Anything, adminClaims.UserID, mock.MatchedBy(func(ops []*domain.BatchOperation) bool {
+		return len(ops) == 3 &&
+			ops[0].Op == domain.BatchUpdate && *ops[0].ID == existing.ID &&
+			ops[0].Update.Price != nil && ops[0].Update.Name == nil && ops[0].Update.Quantity == nil &&
+			ops[1].Op == domain.BatchCreate && ops[2].Op == domain.BatchCreate
+	}), domain.BatchBestEffort).Return([]*domain.BatchItemResult{
+		{Op: domain.BatchUpdate, ID: &existing.ID, Item: existing},
+		{Op: domain.BatchCreate, Item: created},
+		{Op: domain.BatchCreate, Err: domain.ErrDuplicateSKU},
+	}, nil)
+
+	report, err := svc.Import(context.Background(), adminClaims, rows, false)
+
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, created.ID, *report.Rows[1].ItemID)
+	assert.ErrorIs(t, report.Rows[2].Err, domain.ErrDuplicateSKU)
+}
+
+func TestItemService_Import_Forbidden(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	_, err := svc.Import(context.Background(), viewerClaims, nil, true)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestItemService_Import_RepoError(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	rows := []*domain.ImportRow{
+		{Line: 2, SKU: "HUB-001", Input: &domain.CreateItemInput{Name: "Hub", SKU: "HUB-001", Price: decimal.NewFromInt(40)}},
+	}
+	repo.EXPECT().GetBySKUs(mock.Anything, []string{"HUB-001"}).Return(nil, errors.New("db error"))
+
+	_, err := svc.Import(context.Background(), adminClaims, rows, false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ItemService.Import")
+}
+
+func TestItemService_Export_Paginates(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	now := time.Now()
+	firstPage := make([]*domain.Item, exportPageSize)
+	for i := range firstPage {
+		firstPage[i] = &domain.Item{ID: uuid.New(), SKU: fmt.Sprintf("SKU-%d", i), CreatedAt: now.Add(-time.Duration(i) * time.Second)}
+	}
+	last := firstPage[len(firstPage)-1]
+	secondPage := []*domain.Item{{ID: uuid.New(), SKU: "SKU-LAST", CreatedAt: now.Add(-time.Hour)}}
+
+	filter := &domain.ItemFilter{}
+	repo.EXPECT().ListAfter(mock.Anything, filter, (*domain.ItemCursor)(nil), exportPageSize).Return(firstPage, nil)
+	repo.EXPECT().ListAfter(mock.Anything, filter, &domain.ItemCursor{CreatedAt: last.CreatedAt, ID: last.ID}, exportPageSize).
+		Return(secondPage, nil)
+
+	var buf bytes.Buffer
+	err := svc.Export(context.Background(), managerClaims, filter, export.FormatCSV, []string{export.ItemColumnSKU}, &buf)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, exportPageSize+2)
+	assert.Equal(t, "SKU-LAST", lines[len(lines)-1])
+}
+
+func TestItemService_Export_Forbidden(t *testing.T) {
+	svc, _ := newItemService(t)
+
+	err := svc.Export(context.Background(), viewerClaims, &domain.ItemFilter{}, export.FormatCSV, nil, &bytes.Buffer{})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestItemService_Export_RepoErrorBeforeWrite(t *testing.T) {
+	svc, repo := newItemService(t)
+
+	repo.EXPECT().ListAfter(mock.Anything, mock.Anything, (*domain.ItemCursor)(nil), exportPageSize).
+		Return(nil, errors.New("db error"))
+
+	var buf bytes.Buffer
+	err := svc.Export(context.Background(), adminClaims, &domain.ItemFilter{}, export.FormatJSONL, nil, &buf)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ItemService.Export")
+	assert.Zero(t, buf.Len())
+}
+
 func TestNormalizePagination(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -6,19 +6,5863 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stpnv0/WarehouseControl/internal/domain"
 	mock "github.com/stretchr/testify/mock"
 )
 
+// newMockapiKeyOwnerRepository creates a new instance of mockapiKeyOwnerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockapiKeyOwnerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockapiKeyOwnerRepository {
+	mock := &mockapiKeyOwnerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockapiKeyOwnerRepository is an autogenerated mock type for the apiKeyOwnerRepository type
+type mockapiKeyOwnerRepository struct {
+	mock.Mock
+}
+
+type mockapiKeyOwnerRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockapiKeyOwnerRepository) EXPECT() *mockapiKeyOwnerRepository_Expecter {
+	return &mockapiKeyOwnerRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetByID provides a mock function for the type mockapiKeyOwnerRepository
+func (_mock *mockapiKeyOwnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyOwnerRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockapiKeyOwnerRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockapiKeyOwnerRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockapiKeyOwnerRepository_GetByID_Call {
+	return &mockapiKeyOwnerRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockapiKeyOwnerRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockapiKeyOwnerRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyOwnerRepository_GetByID_Call) Return(user *domain.User, err error) *mockapiKeyOwnerRepository_GetByID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockapiKeyOwnerRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.User, error)) *mockapiKeyOwnerRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockapiKeyRepository creates a new instance of mockapiKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockapiKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockapiKeyRepository {
+	mock := &mockapiKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockapiKeyRepository is an autogenerated mock type for the apiKeyRepository type
+type mockapiKeyRepository struct {
+	mock.Mock
+}
+
+type mockapiKeyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockapiKeyRepository) EXPECT() *mockapiKeyRepository_Expecter {
+	return &mockapiKeyRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.APIKey) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.APIKey) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.APIKey) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockapiKeyRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key *domain.APIKey
+func (_e *mockapiKeyRepository_Expecter) Create(ctx interface{}, key interface{}) *mockapiKeyRepository_Create_Call {
+	return &mockapiKeyRepository_Create_Call{Call: _e.mock.On("Create", ctx, key)}
+}
+
+func (_c *mockapiKeyRepository_Create_Call) Run(run func(ctx context.Context, key *domain.APIKey)) *mockapiKeyRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.APIKey
+		if args[1] != nil {
+			arg1 = args[1].(*domain.APIKey)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_Create_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyRepository_Create_Call {
+	_c.Call.Return(apiKey, err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_Create_Call) RunAndReturn(run func(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)) *mockapiKeyRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockapiKeyRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockapiKeyRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockapiKeyRepository_Expecter) Delete(ctx interface{}, id interface{}) *mockapiKeyRepository_Delete_Call {
+	return &mockapiKeyRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *mockapiKeyRepository_Delete_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockapiKeyRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_Delete_Call) Return(err error) *mockapiKeyRepository_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *mockapiKeyRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByHash provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByHash")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, keyHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyRepository_GetByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByHash'
+type mockapiKeyRepository_GetByHash_Call struct {
+	*mock.Call
+}
+
+// GetByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyHash string
+func (_e *mockapiKeyRepository_Expecter) GetByHash(ctx interface{}, keyHash interface{}) *mockapiKeyRepository_GetByHash_Call {
+	return &mockapiKeyRepository_GetByHash_Call{Call: _e.mock.On("GetByHash", ctx, keyHash)}
+}
+
+func (_c *mockapiKeyRepository_GetByHash_Call) Run(run func(ctx context.Context, keyHash string)) *mockapiKeyRepository_GetByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_GetByHash_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyRepository_GetByHash_Call {
+	_c.Call.Return(apiKey, err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_GetByHash_Call) RunAndReturn(run func(ctx context.Context, keyHash string) (*domain.APIKey, error)) *mockapiKeyRepository_GetByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockapiKeyRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockapiKeyRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockapiKeyRepository_GetByID_Call {
+	return &mockapiKeyRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockapiKeyRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockapiKeyRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_GetByID_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyRepository_GetByID_Call {
+	_c.Call.Return(apiKey, err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.APIKey, error)) *mockapiKeyRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.APIKey, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.APIKey); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockapiKeyRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockapiKeyRepository_Expecter) List(ctx interface{}) *mockapiKeyRepository_List_Call {
+	return &mockapiKeyRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *mockapiKeyRepository_List_Call) Run(run func(ctx context.Context)) *mockapiKeyRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_List_Call) Return(apiKeys []*domain.APIKey, err error) *mockapiKeyRepository_List_Call {
+	_c.Call.Return(apiKeys, err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.APIKey, error)) *mockapiKeyRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchLastUsed provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ret := _mock.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchLastUsed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockapiKeyRepository_TouchLastUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchLastUsed'
+type mockapiKeyRepository_TouchLastUsed_Call struct {
+	*mock.Call
+}
+
+// TouchLastUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - at time.Time
+func (_e *mockapiKeyRepository_Expecter) TouchLastUsed(ctx interface{}, id interface{}, at interface{}) *mockapiKeyRepository_TouchLastUsed_Call {
+	return &mockapiKeyRepository_TouchLastUsed_Call{Call: _e.mock.On("TouchLastUsed", ctx, id, at)}
+}
+
+func (_c *mockapiKeyRepository_TouchLastUsed_Call) Run(run func(ctx context.Context, id uuid.UUID, at time.Time)) *mockapiKeyRepository_TouchLastUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_TouchLastUsed_Call) Return(err error) *mockapiKeyRepository_TouchLastUsed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_TouchLastUsed_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, at time.Time) error) *mockapiKeyRepository_TouchLastUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type mockapiKeyRepository
+func (_mock *mockapiKeyRepository) Update(ctx context.Context, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.UpdateAPIKeyInput) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, id, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.UpdateAPIKeyInput) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.UpdateAPIKeyInput) error); ok {
+		r1 = returnFunc(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockapiKeyRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockapiKeyRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - input *domain.UpdateAPIKeyInput
+func (_e *mockapiKeyRepository_Expecter) Update(ctx interface{}, id interface{}, input interface{}) *mockapiKeyRepository_Update_Call {
+	return &mockapiKeyRepository_Update_Call{Call: _e.mock.On("Update", ctx, id, input)}
+}
+
+func (_c *mockapiKeyRepository_Update_Call) Run(run func(ctx context.Context, id uuid.UUID, input *domain.UpdateAPIKeyInput)) *mockapiKeyRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.UpdateAPIKeyInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.UpdateAPIKeyInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockapiKeyRepository_Update_Call) Return(apiKey *domain.APIKey, err error) *mockapiKeyRepository_Update_Call {
+	_c.Call.Return(apiKey, err)
+	return _c
+}
+
+func (_c *mockapiKeyRepository_Update_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, input *domain.UpdateAPIKeyInput) (*domain.APIKey, error)) *mockapiKeyRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockauditRepository creates a new instance of mockauditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func newMockauditRepository(t interface {
+func newMockauditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockauditRepository {
+	mock := &mockauditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockauditRepository is an autogenerated mock type for the auditRepository type
+type mockauditRepository struct {
+	mock.Mock
+}
+
+type mockauditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockauditRepository) EXPECT() *mockauditRepository_Expecter {
+	return &mockauditRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetByItemID provides a mock function for the type mockauditRepository
+func (_mock *mockauditRepository) GetByItemID(ctx context.Context, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error) {
+	ret := _mock.Called(ctx, itemID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByItemID")
+	}
+
+	var r0 []*domain.AuditEntryWithUser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.AuditEntryWithUser, error)); ok {
+		return returnFunc(ctx, itemID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.AuditEntryWithUser); ok {
+		r0 = returnFunc(ctx, itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, itemID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditRepository_GetByItemID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByItemID'
+type mockauditRepository_GetByItemID_Call struct {
+	*mock.Call
+}
+
+// GetByItemID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - itemID uuid.UUID
+func (_e *mockauditRepository_Expecter) GetByItemID(ctx interface{}, itemID interface{}) *mockauditRepository_GetByItemID_Call {
+	return &mockauditRepository_GetByItemID_Call{Call: _e.mock.On("GetByItemID", ctx, itemID)}
+}
+
+func (_c *mockauditRepository_GetByItemID_Call) Run(run func(ctx context.Context, itemID uuid.UUID)) *mockauditRepository_GetByItemID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditRepository_GetByItemID_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, err error) *mockauditRepository_GetByItemID_Call {
+	_c.Call.Return(auditEntryWithUsers, err)
+	return _c
+}
+
+func (_c *mockauditRepository_GetByItemID_Call) RunAndReturn(run func(ctx context.Context, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error)) *mockauditRepository_GetByItemID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockauditRepository
+func (_mock *mockauditRepository) List(ctx context.Context, filter *domain.AuditFilter, limit int, offset int) ([]*domain.AuditEntryWithUser, int64, error) {
+	ret := _mock.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.AuditEntryWithUser
+	var r1 int64
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter, int, int) ([]*domain.AuditEntryWithUser, int64, error)); ok {
+		return returnFunc(ctx, filter, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter, int, int) []*domain.AuditEntryWithUser); ok {
+		r0 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuditFilter, int, int) int64); ok {
+		r1 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.AuditFilter, int, int) error); ok {
+		r2 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockauditRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockauditRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.AuditFilter
+//   - limit int
+//   - offset int
+func (_e *mockauditRepository_Expecter) List(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *mockauditRepository_List_Call {
+	return &mockauditRepository_List_Call{Call: _e.mock.On("List", ctx, filter, limit, offset)}
+}
+
+func (_c *mockauditRepository_List_Call) Run(run func(ctx context.Context, filter *domain.AuditFilter, limit int, offset int)) *mockauditRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuditFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuditFilter)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditRepository_List_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, n int64, err error) *mockauditRepository_List_Call {
+	_c.Call.Return(auditEntryWithUsers, n, err)
+	return _c
+}
+
+func (_c *mockauditRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter *domain.AuditFilter, limit int, offset int) ([]*domain.AuditEntryWithUser, int64, error)) *mockauditRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function for the type mockauditRepository
+func (_mock *mockauditRepository) Stats(ctx context.Context, filter *domain.AuditStatsFilter) (*domain.AuditStats, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *domain.AuditStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditStatsFilter) (*domain.AuditStats, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditStatsFilter) *domain.AuditStats); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuditStatsFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditRepository_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type mockauditRepository_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.AuditStatsFilter
+func (_e *mockauditRepository_Expecter) Stats(ctx interface{}, filter interface{}) *mockauditRepository_Stats_Call {
+	return &mockauditRepository_Stats_Call{Call: _e.mock.On("Stats", ctx, filter)}
+}
+
+func (_c *mockauditRepository_Stats_Call) Run(run func(ctx context.Context, filter *domain.AuditStatsFilter)) *mockauditRepository_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuditStatsFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuditStatsFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditRepository_Stats_Call) Return(auditStats *domain.AuditStats, err error) *mockauditRepository_Stats_Call {
+	_c.Call.Return(auditStats, err)
+	return _c
+}
+
+func (_c *mockauditRepository_Stats_Call) RunAndReturn(run func(ctx context.Context, filter *domain.AuditStatsFilter) (*domain.AuditStats, error)) *mockauditRepository_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stream provides a mock function for the type mockauditRepository
+func (_mock *mockauditRepository) Stream(ctx context.Context, filter *domain.AuditFilter, fn func(e *domain.AuditEntryWithUser) error) error {
+	ret := _mock.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stream")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter, func(e *domain.AuditEntryWithUser) error) error); ok {
+		r0 = returnFunc(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauditRepository_Stream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stream'
+type mockauditRepository_Stream_Call struct {
+	*mock.Call
+}
+
+// Stream is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.AuditFilter
+//   - fn func(e *domain.AuditEntryWithUser) error
+func (_e *mockauditRepository_Expecter) Stream(ctx interface{}, filter interface{}, fn interface{}) *mockauditRepository_Stream_Call {
+	return &mockauditRepository_Stream_Call{Call: _e.mock.On("Stream", ctx, filter, fn)}
+}
+
+func (_c *mockauditRepository_Stream_Call) Run(run func(ctx context.Context, filter *domain.AuditFilter, fn func(e *domain.AuditEntryWithUser) error)) *mockauditRepository_Stream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuditFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuditFilter)
+		}
+		var arg2 func(e *domain.AuditEntryWithUser) error
+		if args[2] != nil {
+			arg2 = args[2].(func(e *domain.AuditEntryWithUser) error)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditRepository_Stream_Call) Return(err error) *mockauditRepository_Stream_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauditRepository_Stream_Call) RunAndReturn(run func(ctx context.Context, filter *domain.AuditFilter, fn func(e *domain.AuditEntryWithUser) error) error) *mockauditRepository_Stream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamChain provides a mock function for the type mockauditRepository
+func (_mock *mockauditRepository) StreamChain(ctx context.Context, fn func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error) {
+	ret := _mock.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamChain")
+	}
+
+	var r0 *domain.AuditChainHead
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error)); ok {
+		return returnFunc(ctx, fn)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(l *domain.AuditChainLink) error) *domain.AuditChainHead); ok {
+		r0 = returnFunc(ctx, fn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditChainHead)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, func(l *domain.AuditChainLink) error) error); ok {
+		r1 = returnFunc(ctx, fn)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditRepository_StreamChain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamChain'
+type mockauditRepository_StreamChain_Call struct {
+	*mock.Call
+}
+
+// StreamChain is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(l *domain.AuditChainLink) error
+func (_e *mockauditRepository_Expecter) StreamChain(ctx interface{}, fn interface{}) *mockauditRepository_StreamChain_Call {
+	return &mockauditRepository_StreamChain_Call{Call: _e.mock.On("StreamChain", ctx, fn)}
+}
+
+func (_c *mockauditRepository_StreamChain_Call) Run(run func(ctx context.Context, fn func(l *domain.AuditChainLink) error)) *mockauditRepository_StreamChain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 func(l *domain.AuditChainLink) error
+		if args[1] != nil {
+			arg1 = args[1].(func(l *domain.AuditChainLink) error)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditRepository_StreamChain_Call) Return(auditChainHead *domain.AuditChainHead, err error) *mockauditRepository_StreamChain_Call {
+	_c.Call.Return(auditChainHead, err)
+	return _c
+}
+
+func (_c *mockauditRepository_StreamChain_Call) RunAndReturn(run func(ctx context.Context, fn func(l *domain.AuditChainLink) error) (*domain.AuditChainHead, error)) *mockauditRepository_StreamChain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockauditArchiveRepository creates a new instance of mockauditArchiveRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockauditArchiveRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockauditArchiveRepository {
+	mock := &mockauditArchiveRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockauditArchiveRepository is an autogenerated mock type for the auditArchiveRepository type
+type mockauditArchiveRepository struct {
+	mock.Mock
+}
+
+type mockauditArchiveRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockauditArchiveRepository) EXPECT() *mockauditArchiveRepository_Expecter {
+	return &mockauditArchiveRepository_Expecter{mock: &_m.Mock}
+}
+
+// Attach provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) Attach(ctx context.Context, archive *domain.AuditArchive, userID uuid.UUID, next func() (*domain.AuditChainLink, error)) error {
+	ret := _mock.Called(ctx, archive, userID, next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Attach")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditArchive, uuid.UUID, func() (*domain.AuditChainLink, error)) error); ok {
+		r0 = returnFunc(ctx, archive, userID, next)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauditArchiveRepository_Attach_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Attach'
+type mockauditArchiveRepository_Attach_Call struct {
+	*mock.Call
+}
+
+// Attach is a helper method to define mock.On call
+//   - ctx context.Context
+//   - archive *domain.AuditArchive
+//   - userID uuid.UUID
+//   - next func() (*domain.AuditChainLink, error)
+func (_e *mockauditArchiveRepository_Expecter) Attach(ctx interface{}, archive interface{}, userID interface{}, next interface{}) *mockauditArchiveRepository_Attach_Call {
+	return &mockauditArchiveRepository_Attach_Call{Call: _e.mock.On("Attach", ctx, archive, userID, next)}
+}
+
+func (_c *mockauditArchiveRepository_Attach_Call) Run(run func(ctx context.Context, archive *domain.AuditArchive, userID uuid.UUID, next func() (*domain.AuditChainLink, error))) *mockauditArchiveRepository_Attach_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuditArchive
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuditArchive)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 func() (*domain.AuditChainLink, error)
+		if args[3] != nil {
+			arg3 = args[3].(func() (*domain.AuditChainLink, error))
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_Attach_Call) Return(err error) *mockauditArchiveRepository_Attach_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_Attach_Call) RunAndReturn(run func(ctx context.Context, archive *domain.AuditArchive, userID uuid.UUID, next func() (*domain.AuditChainLink, error)) error) *mockauditArchiveRepository_Attach_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DefaultPartitionMonths provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) DefaultPartitionMonths(ctx context.Context) ([]time.Time, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DefaultPartitionMonths")
+	}
+
+	var r0 []time.Time
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]time.Time, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []time.Time); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditArchiveRepository_DefaultPartitionMonths_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DefaultPartitionMonths'
+type mockauditArchiveRepository_DefaultPartitionMonths_Call struct {
+	*mock.Call
+}
+
+// DefaultPartitionMonths is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockauditArchiveRepository_Expecter) DefaultPartitionMonths(ctx interface{}) *mockauditArchiveRepository_DefaultPartitionMonths_Call {
+	return &mockauditArchiveRepository_DefaultPartitionMonths_Call{Call: _e.mock.On("DefaultPartitionMonths", ctx)}
+}
+
+func (_c *mockauditArchiveRepository_DefaultPartitionMonths_Call) Run(run func(ctx context.Context)) *mockauditArchiveRepository_DefaultPartitionMonths_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_DefaultPartitionMonths_Call) Return(times []time.Time, err error) *mockauditArchiveRepository_DefaultPartitionMonths_Call {
+	_c.Call.Return(times, err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_DefaultPartitionMonths_Call) RunAndReturn(run func(ctx context.Context) ([]time.Time, error)) *mockauditArchiveRepository_DefaultPartitionMonths_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Detach provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) Detach(ctx context.Context, p *domain.AuditPartition, archive *domain.AuditArchive) error {
+	ret := _mock.Called(ctx, p, archive)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Detach")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditPartition, *domain.AuditArchive) error); ok {
+		r0 = returnFunc(ctx, p, archive)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauditArchiveRepository_Detach_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Detach'
+type mockauditArchiveRepository_Detach_Call struct {
+	*mock.Call
+}
+
+// Detach is a helper method to define mock.On call
+//   - ctx context.Context
+//   - p *domain.AuditPartition
+//   - archive *domain.AuditArchive
+func (_e *mockauditArchiveRepository_Expecter) Detach(ctx interface{}, p interface{}, archive interface{}) *mockauditArchiveRepository_Detach_Call {
+	return &mockauditArchiveRepository_Detach_Call{Call: _e.mock.On("Detach", ctx, p, archive)}
+}
+
+func (_c *mockauditArchiveRepository_Detach_Call) Run(run func(ctx context.Context, p *domain.AuditPartition, archive *domain.AuditArchive)) *mockauditArchiveRepository_Detach_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuditPartition
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuditPartition)
+		}
+		var arg2 *domain.AuditArchive
+		if args[2] != nil {
+			arg2 = args[2].(*domain.AuditArchive)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_Detach_Call) Return(err error) *mockauditArchiveRepository_Detach_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_Detach_Call) RunAndReturn(run func(ctx context.Context, p *domain.AuditPartition, archive *domain.AuditArchive) error) *mockauditArchiveRepository_Detach_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnsurePartition provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) EnsurePartition(ctx context.Context, month time.Time) error {
+	ret := _mock.Called(ctx, month)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnsurePartition")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = returnFunc(ctx, month)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauditArchiveRepository_EnsurePartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnsurePartition'
+type mockauditArchiveRepository_EnsurePartition_Call struct {
+	*mock.Call
+}
+
+// EnsurePartition is a helper method to define mock.On call
+//   - ctx context.Context
+//   - month time.Time
+func (_e *mockauditArchiveRepository_Expecter) EnsurePartition(ctx interface{}, month interface{}) *mockauditArchiveRepository_EnsurePartition_Call {
+	return &mockauditArchiveRepository_EnsurePartition_Call{Call: _e.mock.On("EnsurePartition", ctx, month)}
+}
+
+func (_c *mockauditArchiveRepository_EnsurePartition_Call) Run(run func(ctx context.Context, month time.Time)) *mockauditArchiveRepository_EnsurePartition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_EnsurePartition_Call) Return(err error) *mockauditArchiveRepository_EnsurePartition_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_EnsurePartition_Call) RunAndReturn(run func(ctx context.Context, month time.Time) error) *mockauditArchiveRepository_EnsurePartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) GetByID(ctx context.Context, id int64) (*domain.AuditArchive, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.AuditArchive
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) (*domain.AuditArchive, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) *domain.AuditArchive); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditArchive)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditArchiveRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockauditArchiveRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *mockauditArchiveRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockauditArchiveRepository_GetByID_Call {
+	return &mockauditArchiveRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockauditArchiveRepository_GetByID_Call) Run(run func(ctx context.Context, id int64)) *mockauditArchiveRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_GetByID_Call) Return(auditArchive *domain.AuditArchive, err error) *mockauditArchiveRepository_GetByID_Call {
+	_c.Call.Return(auditArchive, err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id int64) (*domain.AuditArchive, error)) *mockauditArchiveRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) List(ctx context.Context) ([]*domain.AuditArchive, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.AuditArchive
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.AuditArchive, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.AuditArchive); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditArchive)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditArchiveRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockauditArchiveRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockauditArchiveRepository_Expecter) List(ctx interface{}) *mockauditArchiveRepository_List_Call {
+	return &mockauditArchiveRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *mockauditArchiveRepository_List_Call) Run(run func(ctx context.Context)) *mockauditArchiveRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_List_Call) Return(auditArchives []*domain.AuditArchive, err error) *mockauditArchiveRepository_List_Call {
+	_c.Call.Return(auditArchives, err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.AuditArchive, error)) *mockauditArchiveRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPartitions provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) ListPartitions(ctx context.Context) ([]*domain.AuditPartition, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPartitions")
+	}
+
+	var r0 []*domain.AuditPartition
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.AuditPartition, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.AuditPartition); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditPartition)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditArchiveRepository_ListPartitions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPartitions'
+type mockauditArchiveRepository_ListPartitions_Call struct {
+	*mock.Call
+}
+
+// ListPartitions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockauditArchiveRepository_Expecter) ListPartitions(ctx interface{}) *mockauditArchiveRepository_ListPartitions_Call {
+	return &mockauditArchiveRepository_ListPartitions_Call{Call: _e.mock.On("ListPartitions", ctx)}
+}
+
+func (_c *mockauditArchiveRepository_ListPartitions_Call) Run(run func(ctx context.Context)) *mockauditArchiveRepository_ListPartitions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_ListPartitions_Call) Return(auditPartitions []*domain.AuditPartition, err error) *mockauditArchiveRepository_ListPartitions_Call {
+	_c.Call.Return(auditPartitions, err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_ListPartitions_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.AuditPartition, error)) *mockauditArchiveRepository_ListPartitions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StreamPartition provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) StreamPartition(ctx context.Context, name string, fn func(l *domain.AuditChainLink) error) error {
+	ret := _mock.Called(ctx, name, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamPartition")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(l *domain.AuditChainLink) error) error); ok {
+		r0 = returnFunc(ctx, name, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauditArchiveRepository_StreamPartition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamPartition'
+type mockauditArchiveRepository_StreamPartition_Call struct {
+	*mock.Call
+}
+
+// StreamPartition is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - fn func(l *domain.AuditChainLink) error
+func (_e *mockauditArchiveRepository_Expecter) StreamPartition(ctx interface{}, name interface{}, fn interface{}) *mockauditArchiveRepository_StreamPartition_Call {
+	return &mockauditArchiveRepository_StreamPartition_Call{Call: _e.mock.On("StreamPartition", ctx, name, fn)}
+}
+
+func (_c *mockauditArchiveRepository_StreamPartition_Call) Run(run func(ctx context.Context, name string, fn func(l *domain.AuditChainLink) error)) *mockauditArchiveRepository_StreamPartition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 func(l *domain.AuditChainLink) error
+		if args[2] != nil {
+			arg2 = args[2].(func(l *domain.AuditChainLink) error)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_StreamPartition_Call) Return(err error) *mockauditArchiveRepository_StreamPartition_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_StreamPartition_Call) RunAndReturn(run func(ctx context.Context, name string, fn func(l *domain.AuditChainLink) error) error) *mockauditArchiveRepository_StreamPartition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TryLock provides a mock function for the type mockauditArchiveRepository
+func (_mock *mockauditArchiveRepository) TryLock(ctx context.Context) (func(), bool, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TryLock")
+	}
+
+	var r0 func()
+	var r1 bool
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (func(), bool, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) func()); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func())
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) bool); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = returnFunc(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockauditArchiveRepository_TryLock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TryLock'
+type mockauditArchiveRepository_TryLock_Call struct {
+	*mock.Call
+}
+
+// TryLock is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockauditArchiveRepository_Expecter) TryLock(ctx interface{}) *mockauditArchiveRepository_TryLock_Call {
+	return &mockauditArchiveRepository_TryLock_Call{Call: _e.mock.On("TryLock", ctx)}
+}
+
+func (_c *mockauditArchiveRepository_TryLock_Call) Run(run func(ctx context.Context)) *mockauditArchiveRepository_TryLock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_TryLock_Call) Return(unlock func(), ok bool, err error) *mockauditArchiveRepository_TryLock_Call {
+	_c.Call.Return(unlock, ok, err)
+	return _c
+}
+
+func (_c *mockauditArchiveRepository_TryLock_Call) RunAndReturn(run func(ctx context.Context) (func(), bool, error)) *mockauditArchiveRepository_TryLock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockuserRepository creates a new instance of mockuserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockuserRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockuserRepository {
+	mock := &mockuserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockuserRepository is an autogenerated mock type for the userRepository type
+type mockuserRepository struct {
+	mock.Mock
+}
+
+type mockuserRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockuserRepository) EXPECT() *mockuserRepository_Expecter {
+	return &mockuserRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetByID provides a mock function for the type mockuserRepository
+func (_mock *mockuserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockuserRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockuserRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockuserRepository_GetByID_Call {
+	return &mockuserRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockuserRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockuserRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserRepository_GetByID_Call) Return(user *domain.User, err error) *mockuserRepository_GetByID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.User, error)) *mockuserRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByUsername provides a mock function for the type mockuserRepository
+func (_mock *mockuserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUsername")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, username)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserRepository_GetByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByUsername'
+type mockuserRepository_GetByUsername_Call struct {
+	*mock.Call
+}
+
+// GetByUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *mockuserRepository_Expecter) GetByUsername(ctx interface{}, username interface{}) *mockuserRepository_GetByUsername_Call {
+	return &mockuserRepository_GetByUsername_Call{Call: _e.mock.On("GetByUsername", ctx, username)}
+}
+
+func (_c *mockuserRepository_GetByUsername_Call) Run(run func(ctx context.Context, username string)) *mockuserRepository_GetByUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserRepository_GetByUsername_Call) Return(user *domain.User, err error) *mockuserRepository_GetByUsername_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserRepository_GetByUsername_Call) RunAndReturn(run func(ctx context.Context, username string) (*domain.User, error)) *mockuserRepository_GetByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockuserRepository
+func (_mock *mockuserRepository) List(ctx context.Context) ([]*domain.User, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.User, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.User); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockuserRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockuserRepository_Expecter) List(ctx interface{}) *mockuserRepository_List_Call {
+	return &mockuserRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *mockuserRepository_List_Call) Run(run func(ctx context.Context)) *mockuserRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserRepository_List_Call) Return(users []*domain.User, err error) *mockuserRepository_List_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *mockuserRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.User, error)) *mockuserRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePassword provides a mock function for the type mockuserRepository
+func (_mock *mockuserRepository) UpdatePassword(ctx context.Context, actorID uuid.UUID, id uuid.UUID, passwordHash string) (*domain.User, error) {
+	ret := _mock.Called(ctx, actorID, id, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, actorID, id, passwordHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) *domain.User); ok {
+		r0 = returnFunc(ctx, actorID, id, passwordHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = returnFunc(ctx, actorID, id, passwordHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserRepository_UpdatePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePassword'
+type mockuserRepository_UpdatePassword_Call struct {
+	*mock.Call
+}
+
+// UpdatePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - id uuid.UUID
+//   - passwordHash string
+func (_e *mockuserRepository_Expecter) UpdatePassword(ctx interface{}, actorID interface{}, id interface{}, passwordHash interface{}) *mockuserRepository_UpdatePassword_Call {
+	return &mockuserRepository_UpdatePassword_Call{Call: _e.mock.On("UpdatePassword", ctx, actorID, id, passwordHash)}
+}
+
+func (_c *mockuserRepository_UpdatePassword_Call) Run(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, passwordHash string)) *mockuserRepository_UpdatePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserRepository_UpdatePassword_Call) Return(user *domain.User, err error) *mockuserRepository_UpdatePassword_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserRepository_UpdatePassword_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, passwordHash string) (*domain.User, error)) *mockuserRepository_UpdatePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockrefreshTokenRepository creates a new instance of mockrefreshTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockrefreshTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockrefreshTokenRepository {
+	mock := &mockrefreshTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockrefreshTokenRepository is an autogenerated mock type for the refreshTokenRepository type
+type mockrefreshTokenRepository struct {
+	mock.Mock
+}
+
+type mockrefreshTokenRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockrefreshTokenRepository) EXPECT() *mockrefreshTokenRepository_Expecter {
+	return &mockrefreshTokenRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateRefreshToken provides a mock function for the type mockrefreshTokenRepository
+func (_mock *mockrefreshTokenRepository) CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error {
+	ret := _mock.Called(ctx, t)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.RefreshToken) error); ok {
+		r0 = returnFunc(ctx, t)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockrefreshTokenRepository_CreateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefreshToken'
+type mockrefreshTokenRepository_CreateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// CreateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - t *domain.RefreshToken
+func (_e *mockrefreshTokenRepository_Expecter) CreateRefreshToken(ctx interface{}, t interface{}) *mockrefreshTokenRepository_CreateRefreshToken_Call {
+	return &mockrefreshTokenRepository_CreateRefreshToken_Call{Call: _e.mock.On("CreateRefreshToken", ctx, t)}
+}
+
+func (_c *mockrefreshTokenRepository_CreateRefreshToken_Call) Run(run func(ctx context.Context, t *domain.RefreshToken)) *mockrefreshTokenRepository_CreateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.RefreshToken
+		if args[1] != nil {
+			arg1 = args[1].(*domain.RefreshToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_CreateRefreshToken_Call) Return(err error) *mockrefreshTokenRepository_CreateRefreshToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_CreateRefreshToken_Call) RunAndReturn(run func(ctx context.Context, t *domain.RefreshToken) error) *mockrefreshTokenRepository_CreateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRefreshToken provides a mock function for the type mockrefreshTokenRepository
+func (_mock *mockrefreshTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	ret := _mock.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshToken")
+	}
+
+	var r0 *domain.RefreshToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.RefreshToken, error)); ok {
+		return returnFunc(ctx, tokenHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.RefreshToken); ok {
+		r0 = returnFunc(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RefreshToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockrefreshTokenRepository_GetRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRefreshToken'
+type mockrefreshTokenRepository_GetRefreshToken_Call struct {
+	*mock.Call
+}
+
+// GetRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *mockrefreshTokenRepository_Expecter) GetRefreshToken(ctx interface{}, tokenHash interface{}) *mockrefreshTokenRepository_GetRefreshToken_Call {
+	return &mockrefreshTokenRepository_GetRefreshToken_Call{Call: _e.mock.On("GetRefreshToken", ctx, tokenHash)}
+}
+
+func (_c *mockrefreshTokenRepository_GetRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string)) *mockrefreshTokenRepository_GetRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_GetRefreshToken_Call) Return(refreshToken *domain.RefreshToken, err error) *mockrefreshTokenRepository_GetRefreshToken_Call {
+	_c.Call.Return(refreshToken, err)
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_GetRefreshToken_Call) RunAndReturn(run func(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)) *mockrefreshTokenRepository_GetRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeFamily provides a mock function for the type mockrefreshTokenRepository
+func (_mock *mockrefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) ([]*domain.RevokedToken, error) {
+	ret := _mock.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeFamily")
+	}
+
+	var r0 []*domain.RevokedToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.RevokedToken, error)); ok {
+		return returnFunc(ctx, familyID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.RevokedToken); ok {
+		r0 = returnFunc(ctx, familyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.RevokedToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, familyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockrefreshTokenRepository_RevokeFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeFamily'
+type mockrefreshTokenRepository_RevokeFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID uuid.UUID
+func (_e *mockrefreshTokenRepository_Expecter) RevokeFamily(ctx interface{}, familyID interface{}) *mockrefreshTokenRepository_RevokeFamily_Call {
+	return &mockrefreshTokenRepository_RevokeFamily_Call{Call: _e.mock.On("RevokeFamily", ctx, familyID)}
+}
+
+func (_c *mockrefreshTokenRepository_RevokeFamily_Call) Run(run func(ctx context.Context, familyID uuid.UUID)) *mockrefreshTokenRepository_RevokeFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RevokeFamily_Call) Return(revokedTokens []*domain.RevokedToken, err error) *mockrefreshTokenRepository_RevokeFamily_Call {
+	_c.Call.Return(revokedTokens, err)
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RevokeFamily_Call) RunAndReturn(run func(ctx context.Context, familyID uuid.UUID) ([]*domain.RevokedToken, error)) *mockrefreshTokenRepository_RevokeFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function for the type mockrefreshTokenRepository
+func (_mock *mockrefreshTokenRepository) RevokeSession(ctx context.Context, jti uuid.UUID, expiresAt time.Time) ([]*domain.RevokedToken, error) {
+	ret := _mock.Called(ctx, jti, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 []*domain.RevokedToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) ([]*domain.RevokedToken, error)); ok {
+		return returnFunc(ctx, jti, expiresAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) []*domain.RevokedToken); ok {
+		r0 = returnFunc(ctx, jti, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.RevokedToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = returnFunc(ctx, jti, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockrefreshTokenRepository_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type mockrefreshTokenRepository_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti uuid.UUID
+//   - expiresAt time.Time
+func (_e *mockrefreshTokenRepository_Expecter) RevokeSession(ctx interface{}, jti interface{}, expiresAt interface{}) *mockrefreshTokenRepository_RevokeSession_Call {
+	return &mockrefreshTokenRepository_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, jti, expiresAt)}
+}
+
+func (_c *mockrefreshTokenRepository_RevokeSession_Call) Run(run func(ctx context.Context, jti uuid.UUID, expiresAt time.Time)) *mockrefreshTokenRepository_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RevokeSession_Call) Return(revokedTokens []*domain.RevokedToken, err error) *mockrefreshTokenRepository_RevokeSession_Call {
+	_c.Call.Return(revokedTokens, err)
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RevokeSession_Call) RunAndReturn(run func(ctx context.Context, jti uuid.UUID, expiresAt time.Time) ([]*domain.RevokedToken, error)) *mockrefreshTokenRepository_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSessions provides a mock function for the type mockrefreshTokenRepository
+func (_mock *mockrefreshTokenRepository) RevokeUserSessions(ctx context.Context, userID uuid.UUID) ([]*domain.RevokedToken, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 []*domain.RevokedToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.RevokedToken, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.RevokedToken); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.RevokedToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockrefreshTokenRepository_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type mockrefreshTokenRepository_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *mockrefreshTokenRepository_Expecter) RevokeUserSessions(ctx interface{}, userID interface{}) *mockrefreshTokenRepository_RevokeUserSessions_Call {
+	return &mockrefreshTokenRepository_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userID)}
+}
+
+func (_c *mockrefreshTokenRepository_RevokeUserSessions_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *mockrefreshTokenRepository_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RevokeUserSessions_Call) Return(revokedTokens []*domain.RevokedToken, err error) *mockrefreshTokenRepository_RevokeUserSessions_Call {
+	_c.Call.Return(revokedTokens, err)
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RevokeUserSessions_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID) ([]*domain.RevokedToken, error)) *mockrefreshTokenRepository_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateRefreshToken provides a mock function for the type mockrefreshTokenRepository
+func (_mock *mockrefreshTokenRepository) RotateRefreshToken(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error {
+	ret := _mock.Called(ctx, id, next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.RefreshToken) error); ok {
+		r0 = returnFunc(ctx, id, next)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockrefreshTokenRepository_RotateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateRefreshToken'
+type mockrefreshTokenRepository_RotateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RotateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - next *domain.RefreshToken
+func (_e *mockrefreshTokenRepository_Expecter) RotateRefreshToken(ctx interface{}, id interface{}, next interface{}) *mockrefreshTokenRepository_RotateRefreshToken_Call {
+	return &mockrefreshTokenRepository_RotateRefreshToken_Call{Call: _e.mock.On("RotateRefreshToken", ctx, id, next)}
+}
+
+func (_c *mockrefreshTokenRepository_RotateRefreshToken_Call) Run(run func(ctx context.Context, id uuid.UUID, next *domain.RefreshToken)) *mockrefreshTokenRepository_RotateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.RefreshToken
+		if args[2] != nil {
+			arg2 = args[2].(*domain.RefreshToken)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RotateRefreshToken_Call) Return(err error) *mockrefreshTokenRepository_RotateRefreshToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockrefreshTokenRepository_RotateRefreshToken_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error) *mockrefreshTokenRepository_RotateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMocktokenDenylist creates a new instance of mocktokenDenylist. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMocktokenDenylist(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mocktokenDenylist {
+	mock := &mocktokenDenylist{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mocktokenDenylist is an autogenerated mock type for the tokenDenylist type
+type mocktokenDenylist struct {
+	mock.Mock
+}
+
+type mocktokenDenylist_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mocktokenDenylist) EXPECT() *mocktokenDenylist_Expecter {
+	return &mocktokenDenylist_Expecter{mock: &_m.Mock}
+}
+
+// Add provides a mock function for the type mocktokenDenylist
+func (_mock *mocktokenDenylist) Add(tokens ...*domain.RevokedToken) {
+	var _ca []interface{}
+	for _, _va := range tokens {
+		_ca = append(_ca, _va)
+	}
+	_mock.Called(_ca...)
+	return
+}
+
+// mocktokenDenylist_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
+type mocktokenDenylist_Add_Call struct {
+	*mock.Call
+}
+
+// Add is a helper method to define mock.On call
+//   - tokens ...*domain.RevokedToken
+func (_e *mocktokenDenylist_Expecter) Add(tokens ...interface{}) *mocktokenDenylist_Add_Call {
+	return &mocktokenDenylist_Add_Call{Call: _e.mock.On("Add", append([]interface{}{}, tokens...)...)}
+}
+
+func (_c *mocktokenDenylist_Add_Call) Run(run func(tokens ...*domain.RevokedToken)) *mocktokenDenylist_Add_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []*domain.RevokedToken
+		variadicArgs := make([]*domain.RevokedToken, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(*domain.RevokedToken)
+			}
+		}
+		arg0 = variadicArgs
+		run(
+			arg0...,
+		)
+	})
+	return _c
+}
+
+func (_c *mocktokenDenylist_Add_Call) Return() *mocktokenDenylist_Add_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *mocktokenDenylist_Add_Call) RunAndReturn(run func(tokens ...*domain.RevokedToken)) *mocktokenDenylist_Add_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockTokenManager creates a new instance of MockTokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenManager {
+	mock := &MockTokenManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockTokenManager is an autogenerated mock type for the TokenManager type
+type MockTokenManager struct {
+	mock.Mock
+}
+
+type MockTokenManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTokenManager) EXPECT() *MockTokenManager_Expecter {
+	return &MockTokenManager_Expecter{mock: &_m.Mock}
+}
+
+// GenerateJWT provides a mock function for the type MockTokenManager
+func (_mock *MockTokenManager) GenerateJWT(user *domain.User) (*domain.AccessToken, error) {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateJWT")
+	}
+
+	var r0 *domain.AccessToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*domain.User) (*domain.AccessToken, error)); ok {
+		return returnFunc(user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*domain.User) *domain.AccessToken); ok {
+		r0 = returnFunc(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*domain.User) error); ok {
+		r1 = returnFunc(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTokenManager_GenerateJWT_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateJWT'
+type MockTokenManager_GenerateJWT_Call struct {
+	*mock.Call
+}
+
+// GenerateJWT is a helper method to define mock.On call
+//   - user *domain.User
+func (_e *MockTokenManager_Expecter) GenerateJWT(user interface{}) *MockTokenManager_GenerateJWT_Call {
+	return &MockTokenManager_GenerateJWT_Call{Call: _e.mock.On("GenerateJWT", user)}
+}
+
+func (_c *MockTokenManager_GenerateJWT_Call) Run(run func(user *domain.User)) *MockTokenManager_GenerateJWT_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *domain.User
+		if args[0] != nil {
+			arg0 = args[0].(*domain.User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenManager_GenerateJWT_Call) Return(accessToken *domain.AccessToken, err error) *MockTokenManager_GenerateJWT_Call {
+	_c.Call.Return(accessToken, err)
+	return _c
+}
+
+func (_c *MockTokenManager_GenerateJWT_Call) RunAndReturn(run func(user *domain.User) (*domain.AccessToken, error)) *MockTokenManager_GenerateJWT_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Validate provides a mock function for the type MockTokenManager
+func (_mock *MockTokenManager) Validate(tokenStr string) (*domain.AuthClaims, error) {
+	ret := _mock.Called(tokenStr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 *domain.AuthClaims
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (*domain.AuthClaims, error)); ok {
+		return returnFunc(tokenStr)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) *domain.AuthClaims); ok {
+		r0 = returnFunc(tokenStr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthClaims)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(tokenStr)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockTokenManager_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type MockTokenManager_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - tokenStr string
+func (_e *MockTokenManager_Expecter) Validate(tokenStr interface{}) *MockTokenManager_Validate_Call {
+	return &MockTokenManager_Validate_Call{Call: _e.mock.On("Validate", tokenStr)}
+}
+
+func (_c *MockTokenManager_Validate_Call) Run(run func(tokenStr string)) *MockTokenManager_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockTokenManager_Validate_Call) Return(authClaims *domain.AuthClaims, err error) *MockTokenManager_Validate_Call {
+	_c.Call.Return(authClaims, err)
+	return _c
+}
+
+func (_c *MockTokenManager_Validate_Call) RunAndReturn(run func(tokenStr string) (*domain.AuthClaims, error)) *MockTokenManager_Validate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockauditFeedRepository creates a new instance of mockauditFeedRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockauditFeedRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockauditFeedRepository {
+	mock := &mockauditFeedRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockauditFeedRepository is an autogenerated mock type for the auditFeedRepository type
+type mockauditFeedRepository struct {
+	mock.Mock
+}
+
+type mockauditFeedRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockauditFeedRepository) EXPECT() *mockauditFeedRepository_Expecter {
+	return &mockauditFeedRepository_Expecter{mock: &_m.Mock}
+}
+
+// LastID provides a mock function for the type mockauditFeedRepository
+func (_mock *mockauditFeedRepository) LastID(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastID")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditFeedRepository_LastID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LastID'
+type mockauditFeedRepository_LastID_Call struct {
+	*mock.Call
+}
+
+// LastID is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockauditFeedRepository_Expecter) LastID(ctx interface{}) *mockauditFeedRepository_LastID_Call {
+	return &mockauditFeedRepository_LastID_Call{Call: _e.mock.On("LastID", ctx)}
+}
+
+func (_c *mockauditFeedRepository_LastID_Call) Run(run func(ctx context.Context)) *mockauditFeedRepository_LastID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditFeedRepository_LastID_Call) Return(n int64, err error) *mockauditFeedRepository_LastID_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *mockauditFeedRepository_LastID_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *mockauditFeedRepository_LastID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAfter provides a mock function for the type mockauditFeedRepository
+func (_mock *mockauditFeedRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*domain.AuditEntryWithUser, error) {
+	ret := _mock.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAfter")
+	}
+
+	var r0 []*domain.AuditEntryWithUser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int) ([]*domain.AuditEntryWithUser, error)); ok {
+		return returnFunc(ctx, afterID, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int) []*domain.AuditEntryWithUser); ok {
+		r0 = returnFunc(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = returnFunc(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockauditFeedRepository_ListAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAfter'
+type mockauditFeedRepository_ListAfter_Call struct {
+	*mock.Call
+}
+
+// ListAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *mockauditFeedRepository_Expecter) ListAfter(ctx interface{}, afterID interface{}, limit interface{}) *mockauditFeedRepository_ListAfter_Call {
+	return &mockauditFeedRepository_ListAfter_Call{Call: _e.mock.On("ListAfter", ctx, afterID, limit)}
+}
+
+func (_c *mockauditFeedRepository_ListAfter_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *mockauditFeedRepository_ListAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditFeedRepository_ListAfter_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, err error) *mockauditFeedRepository_ListAfter_Call {
+	_c.Call.Return(auditEntryWithUsers, err)
+	return _c
+}
+
+func (_c *mockauditFeedRepository_ListAfter_Call) RunAndReturn(run func(ctx context.Context, afterID int64, limit int) ([]*domain.AuditEntryWithUser, error)) *mockauditFeedRepository_ListAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockauditNotifier creates a new instance of mockauditNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockauditNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockauditNotifier {
+	mock := &mockauditNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockauditNotifier is an autogenerated mock type for the auditNotifier type
+type mockauditNotifier struct {
+	mock.Mock
+}
+
+type mockauditNotifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockauditNotifier) EXPECT() *mockauditNotifier_Expecter {
+	return &mockauditNotifier_Expecter{mock: &_m.Mock}
+}
+
+// Listen provides a mock function for the type mockauditNotifier
+func (_mock *mockauditNotifier) Listen(ctx context.Context, notify chan<- struct{}) error {
+	ret := _mock.Called(ctx, notify)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Listen")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, chan<- struct{}) error); ok {
+		r0 = returnFunc(ctx, notify)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockauditNotifier_Listen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Listen'
+type mockauditNotifier_Listen_Call struct {
+	*mock.Call
+}
+
+// Listen is a helper method to define mock.On call
+//   - ctx context.Context
+//   - notify chan<- struct{}
+func (_e *mockauditNotifier_Expecter) Listen(ctx interface{}, notify interface{}) *mockauditNotifier_Listen_Call {
+	return &mockauditNotifier_Listen_Call{Call: _e.mock.On("Listen", ctx, notify)}
+}
+
+func (_c *mockauditNotifier_Listen_Call) Run(run func(ctx context.Context, notify chan<- struct{})) *mockauditNotifier_Listen_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 chan<- struct{}
+		if args[1] != nil {
+			arg1 = args[1].(chan<- struct{})
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockauditNotifier_Listen_Call) Return(err error) *mockauditNotifier_Listen_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockauditNotifier_Listen_Call) RunAndReturn(run func(ctx context.Context, notify chan<- struct{}) error) *mockauditNotifier_Listen_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockexportJobRepository creates a new instance of mockexportJobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockexportJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockexportJobRepository {
+	mock := &mockexportJobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockexportJobRepository is an autogenerated mock type for the exportJobRepository type
+type mockexportJobRepository struct {
+	mock.Mock
+}
+
+type mockexportJobRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockexportJobRepository) EXPECT() *mockexportJobRepository_Expecter {
+	return &mockexportJobRepository_Expecter{mock: &_m.Mock}
+}
+
+// ClaimNext provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) ClaimNext(ctx context.Context) (*domain.ExportJob, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimNext")
+	}
+
+	var r0 *domain.ExportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*domain.ExportJob, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *domain.ExportJob); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobRepository_ClaimNext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimNext'
+type mockexportJobRepository_ClaimNext_Call struct {
+	*mock.Call
+}
+
+// ClaimNext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockexportJobRepository_Expecter) ClaimNext(ctx interface{}) *mockexportJobRepository_ClaimNext_Call {
+	return &mockexportJobRepository_ClaimNext_Call{Call: _e.mock.On("ClaimNext", ctx)}
+}
+
+func (_c *mockexportJobRepository_ClaimNext_Call) Run(run func(ctx context.Context)) *mockexportJobRepository_ClaimNext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_ClaimNext_Call) Return(exportJob *domain.ExportJob, err error) *mockexportJobRepository_ClaimNext_Call {
+	_c.Call.Return(exportJob, err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_ClaimNext_Call) RunAndReturn(run func(ctx context.Context) (*domain.ExportJob, error)) *mockexportJobRepository_ClaimNext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Complete provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) Complete(ctx context.Context, id uuid.UUID, fileName string, fileSize int64, processed int64, expiresAt time.Time) error {
+	ret := _mock.Called(ctx, id, fileName, fileSize, processed, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, int64, int64, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, fileName, fileSize, processed, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockexportJobRepository_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type mockexportJobRepository_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - fileName string
+//   - fileSize int64
+//   - processed int64
+//   - expiresAt time.Time
+func (_e *mockexportJobRepository_Expecter) Complete(ctx interface{}, id interface{}, fileName interface{}, fileSize interface{}, processed interface{}, expiresAt interface{}) *mockexportJobRepository_Complete_Call {
+	return &mockexportJobRepository_Complete_Call{Call: _e.mock.On("Complete", ctx, id, fileName, fileSize, processed, expiresAt)}
+}
+
+func (_c *mockexportJobRepository_Complete_Call) Run(run func(ctx context.Context, id uuid.UUID, fileName string, fileSize int64, processed int64, expiresAt time.Time)) *mockexportJobRepository_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		var arg4 int64
+		if args[4] != nil {
+			arg4 = args[4].(int64)
+		}
+		var arg5 time.Time
+		if args[5] != nil {
+			arg5 = args[5].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_Complete_Call) Return(err error) *mockexportJobRepository_Complete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_Complete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, fileName string, fileSize int64, processed int64, expiresAt time.Time) error) *mockexportJobRepository_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) Create(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	ret := _mock.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.ExportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ExportJob) (*domain.ExportJob, error)); ok {
+		return returnFunc(ctx, job)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ExportJob) *domain.ExportJob); ok {
+		r0 = returnFunc(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.ExportJob) error); ok {
+		r1 = returnFunc(ctx, job)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockexportJobRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *domain.ExportJob
+func (_e *mockexportJobRepository_Expecter) Create(ctx interface{}, job interface{}) *mockexportJobRepository_Create_Call {
+	return &mockexportJobRepository_Create_Call{Call: _e.mock.On("Create", ctx, job)}
+}
+
+func (_c *mockexportJobRepository_Create_Call) Run(run func(ctx context.Context, job *domain.ExportJob)) *mockexportJobRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.ExportJob
+		if args[1] != nil {
+			arg1 = args[1].(*domain.ExportJob)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_Create_Call) Return(exportJob *domain.ExportJob, err error) *mockexportJobRepository_Create_Call {
+	_c.Call.Return(exportJob, err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_Create_Call) RunAndReturn(run func(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error)) *mockexportJobRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpired provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) DeleteExpired(ctx context.Context, now time.Time) ([]*domain.ExportJob, error) {
+	ret := _mock.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 []*domain.ExportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) ([]*domain.ExportJob, error)); ok {
+		return returnFunc(ctx, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) []*domain.ExportJob); ok {
+		r0 = returnFunc(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobRepository_DeleteExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpired'
+type mockexportJobRepository_DeleteExpired_Call struct {
+	*mock.Call
+}
+
+// DeleteExpired is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+func (_e *mockexportJobRepository_Expecter) DeleteExpired(ctx interface{}, now interface{}) *mockexportJobRepository_DeleteExpired_Call {
+	return &mockexportJobRepository_DeleteExpired_Call{Call: _e.mock.On("DeleteExpired", ctx, now)}
+}
+
+func (_c *mockexportJobRepository_DeleteExpired_Call) Run(run func(ctx context.Context, now time.Time)) *mockexportJobRepository_DeleteExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_DeleteExpired_Call) Return(exportJobs []*domain.ExportJob, err error) *mockexportJobRepository_DeleteExpired_Call {
+	_c.Call.Return(exportJobs, err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_DeleteExpired_Call) RunAndReturn(run func(ctx context.Context, now time.Time) ([]*domain.ExportJob, error)) *mockexportJobRepository_DeleteExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fail provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) Fail(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error {
+	ret := _mock.Called(ctx, id, reason, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, reason, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockexportJobRepository_Fail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fail'
+type mockexportJobRepository_Fail_Call struct {
+	*mock.Call
+}
+
+// Fail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - reason string
+//   - expiresAt time.Time
+func (_e *mockexportJobRepository_Expecter) Fail(ctx interface{}, id interface{}, reason interface{}, expiresAt interface{}) *mockexportJobRepository_Fail_Call {
+	return &mockexportJobRepository_Fail_Call{Call: _e.mock.On("Fail", ctx, id, reason, expiresAt)}
+}
+
+func (_c *mockexportJobRepository_Fail_Call) Run(run func(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time)) *mockexportJobRepository_Fail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_Fail_Call) Return(err error) *mockexportJobRepository_Fail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_Fail_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error) *mockexportJobRepository_Fail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.ExportJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.ExportJob, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.ExportJob); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ExportJob)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockexportJobRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockexportJobRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockexportJobRepository_GetByID_Call {
+	return &mockexportJobRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockexportJobRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockexportJobRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_GetByID_Call) Return(exportJob *domain.ExportJob, err error) *mockexportJobRepository_GetByID_Call {
+	_c.Call.Return(exportJob, err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)) *mockexportJobRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequeueRunning provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) RequeueRunning(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueRunning")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockexportJobRepository_RequeueRunning_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequeueRunning'
+type mockexportJobRepository_RequeueRunning_Call struct {
+	*mock.Call
+}
+
+// RequeueRunning is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockexportJobRepository_Expecter) RequeueRunning(ctx interface{}) *mockexportJobRepository_RequeueRunning_Call {
+	return &mockexportJobRepository_RequeueRunning_Call{Call: _e.mock.On("RequeueRunning", ctx)}
+}
+
+func (_c *mockexportJobRepository_RequeueRunning_Call) Run(run func(ctx context.Context)) *mockexportJobRepository_RequeueRunning_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_RequeueRunning_Call) Return(n int64, err error) *mockexportJobRepository_RequeueRunning_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_RequeueRunning_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *mockexportJobRepository_RequeueRunning_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProgress provides a mock function for the type mockexportJobRepository
+func (_mock *mockexportJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, processed int64, total int64) error {
+	ret := _mock.Called(ctx, id, processed, total)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProgress")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, int64) error); ok {
+		r0 = returnFunc(ctx, id, processed, total)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockexportJobRepository_UpdateProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProgress'
+type mockexportJobRepository_UpdateProgress_Call struct {
+	*mock.Call
+}
+
+// UpdateProgress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - processed int64
+//   - total int64
+func (_e *mockexportJobRepository_Expecter) UpdateProgress(ctx interface{}, id interface{}, processed interface{}, total interface{}) *mockexportJobRepository_UpdateProgress_Call {
+	return &mockexportJobRepository_UpdateProgress_Call{Call: _e.mock.On("UpdateProgress", ctx, id, processed, total)}
+}
+
+func (_c *mockexportJobRepository_UpdateProgress_Call) Run(run func(ctx context.Context, id uuid.UUID, processed int64, total int64)) *mockexportJobRepository_UpdateProgress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockexportJobRepository_UpdateProgress_Call) Return(err error) *mockexportJobRepository_UpdateProgress_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockexportJobRepository_UpdateProgress_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, processed int64, total int64) error) *mockexportJobRepository_UpdateProgress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockitemRepository creates a new instance of mockitemRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockitemRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockitemRepository {
+	mock := &mockitemRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockitemRepository is an autogenerated mock type for the itemRepository type
+type mockitemRepository struct {
+	mock.Mock
+}
+
+type mockitemRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockitemRepository) EXPECT() *mockitemRepository_Expecter {
+	return &mockitemRepository_Expecter{mock: &_m.Mock}
+}
+
+// AdjustQuantity provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) AdjustQuantity(ctx context.Context, userID uuid.UUID, id uuid.UUID, delta int) (*domain.Item, error) {
+	ret := _mock.Called(ctx, userID, id, delta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustQuantity")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, int) (*domain.Item, error)); ok {
+		return returnFunc(ctx, userID, id, delta)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, int) *domain.Item); ok {
+		r0 = returnFunc(ctx, userID, id, delta)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, int) error); ok {
+		r1 = returnFunc(ctx, userID, id, delta)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_AdjustQuantity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustQuantity'
+type mockitemRepository_AdjustQuantity_Call struct {
+	*mock.Call
+}
+
+// AdjustQuantity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - id uuid.UUID
+//   - delta int
+func (_e *mockitemRepository_Expecter) AdjustQuantity(ctx interface{}, userID interface{}, id interface{}, delta interface{}) *mockitemRepository_AdjustQuantity_Call {
+	return &mockitemRepository_AdjustQuantity_Call{Call: _e.mock.On("AdjustQuantity", ctx, userID, id, delta)}
+}
+
+func (_c *mockitemRepository_AdjustQuantity_Call) Run(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID, delta int)) *mockitemRepository_AdjustQuantity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_AdjustQuantity_Call) Return(item *domain.Item, err error) *mockitemRepository_AdjustQuantity_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemRepository_AdjustQuantity_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID, delta int) (*domain.Item, error)) *mockitemRepository_AdjustQuantity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Batch provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) Batch(ctx context.Context, userID uuid.UUID, ops []*domain.BatchOperation, mode domain.BatchMode) ([]*domain.BatchItemResult, error) {
+	ret := _mock.Called(ctx, userID, ops, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 []*domain.BatchItemResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []*domain.BatchOperation, domain.BatchMode) ([]*domain.BatchItemResult, error)); ok {
+		return returnFunc(ctx, userID, ops, mode)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []*domain.BatchOperation, domain.BatchMode) []*domain.BatchItemResult); ok {
+		r0 = returnFunc(ctx, userID, ops, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.BatchItemResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, []*domain.BatchOperation, domain.BatchMode) error); ok {
+		r1 = returnFunc(ctx, userID, ops, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_Batch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Batch'
+type mockitemRepository_Batch_Call struct {
+	*mock.Call
+}
+
+// Batch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - ops []*domain.BatchOperation
+//   - mode domain.BatchMode
+func (_e *mockitemRepository_Expecter) Batch(ctx interface{}, userID interface{}, ops interface{}, mode interface{}) *mockitemRepository_Batch_Call {
+	return &mockitemRepository_Batch_Call{Call: _e.mock.On("Batch", ctx, userID, ops, mode)}
+}
+
+func (_c *mockitemRepository_Batch_Call) Run(run func(ctx context.Context, userID uuid.UUID, ops []*domain.BatchOperation, mode domain.BatchMode)) *mockitemRepository_Batch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 []*domain.BatchOperation
+		if args[2] != nil {
+			arg2 = args[2].([]*domain.BatchOperation)
+		}
+		var arg3 domain.BatchMode
+		if args[3] != nil {
+			arg3 = args[3].(domain.BatchMode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_Batch_Call) Return(batchItemResults []*domain.BatchItemResult, err error) *mockitemRepository_Batch_Call {
+	_c.Call.Return(batchItemResults, err)
+	return _c
+}
+
+func (_c *mockitemRepository_Batch_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, ops []*domain.BatchOperation, mode domain.BatchMode) ([]*domain.BatchItemResult, error)) *mockitemRepository_Batch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) Create(ctx context.Context, userID uuid.UUID, input *domain.CreateItemInput) (*domain.Item, error) {
+	ret := _mock.Called(ctx, userID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.CreateItemInput) (*domain.Item, error)); ok {
+		return returnFunc(ctx, userID, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.CreateItemInput) *domain.Item); ok {
+		r0 = returnFunc(ctx, userID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.CreateItemInput) error); ok {
+		r1 = returnFunc(ctx, userID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockitemRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - input *domain.CreateItemInput
+func (_e *mockitemRepository_Expecter) Create(ctx interface{}, userID interface{}, input interface{}) *mockitemRepository_Create_Call {
+	return &mockitemRepository_Create_Call{Call: _e.mock.On("Create", ctx, userID, input)}
+}
+
+func (_c *mockitemRepository_Create_Call) Run(run func(ctx context.Context, userID uuid.UUID, input *domain.CreateItemInput)) *mockitemRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.CreateItemInput
+		if args[2] != nil {
+			arg2 = args[2].(*domain.CreateItemInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_Create_Call) Return(item *domain.Item, err error) *mockitemRepository_Create_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemRepository_Create_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, input *domain.CreateItemInput) (*domain.Item, error)) *mockitemRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ret := _mock.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockitemRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockitemRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - id uuid.UUID
+func (_e *mockitemRepository_Expecter) Delete(ctx interface{}, userID interface{}, id interface{}) *mockitemRepository_Delete_Call {
+	return &mockitemRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, userID, id)}
+}
+
+func (_c *mockitemRepository_Delete_Call) Run(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID)) *mockitemRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_Delete_Call) Return(err error) *mockitemRepository_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockitemRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID) error) *mockitemRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Item, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Item, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Item); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockitemRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockitemRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockitemRepository_GetByID_Call {
+	return &mockitemRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockitemRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockitemRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_GetByID_Call) Return(item *domain.Item, err error) *mockitemRepository_GetByID_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Item, error)) *mockitemRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBySKUs provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) GetBySKUs(ctx context.Context, skus []string) ([]*domain.Item, error) {
+	ret := _mock.Called(ctx, skus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySKUs")
+	}
+
+	var r0 []*domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]*domain.Item, error)); ok {
+		return returnFunc(ctx, skus)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []*domain.Item); ok {
+		r0 = returnFunc(ctx, skus)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, skus)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_GetBySKUs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBySKUs'
+type mockitemRepository_GetBySKUs_Call struct {
+	*mock.Call
+}
+
+// GetBySKUs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - skus []string
+func (_e *mockitemRepository_Expecter) GetBySKUs(ctx interface{}, skus interface{}) *mockitemRepository_GetBySKUs_Call {
+	return &mockitemRepository_GetBySKUs_Call{Call: _e.mock.On("GetBySKUs", ctx, skus)}
+}
+
+func (_c *mockitemRepository_GetBySKUs_Call) Run(run func(ctx context.Context, skus []string)) *mockitemRepository_GetBySKUs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_GetBySKUs_Call) Return(items []*domain.Item, err error) *mockitemRepository_GetBySKUs_Call {
+	_c.Call.Return(items, err)
+	return _c
+}
+
+func (_c *mockitemRepository_GetBySKUs_Call) RunAndReturn(run func(ctx context.Context, skus []string) ([]*domain.Item, error)) *mockitemRepository_GetBySKUs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) List(ctx context.Context, filter *domain.ItemFilter, limit int, offset int) ([]*domain.Item, int64, error) {
+	ret := _mock.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.Item
+	var r1 int64
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ItemFilter, int, int) ([]*domain.Item, int64, error)); ok {
+		return returnFunc(ctx, filter, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ItemFilter, int, int) []*domain.Item); ok {
+		r0 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.ItemFilter, int, int) int64); ok {
+		r1 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.ItemFilter, int, int) error); ok {
+		r2 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockitemRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockitemRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.ItemFilter
+//   - limit int
+//   - offset int
+func (_e *mockitemRepository_Expecter) List(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *mockitemRepository_List_Call {
+	return &mockitemRepository_List_Call{Call: _e.mock.On("List", ctx, filter, limit, offset)}
+}
+
+func (_c *mockitemRepository_List_Call) Run(run func(ctx context.Context, filter *domain.ItemFilter, limit int, offset int)) *mockitemRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.ItemFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.ItemFilter)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_List_Call) Return(items []*domain.Item, n int64, err error) *mockitemRepository_List_Call {
+	_c.Call.Return(items, n, err)
+	return _c
+}
+
+func (_c *mockitemRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter *domain.ItemFilter, limit int, offset int) ([]*domain.Item, int64, error)) *mockitemRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAfter provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) ListAfter(ctx context.Context, filter *domain.ItemFilter, after *domain.ItemCursor, limit int) ([]*domain.Item, error) {
+	ret := _mock.Called(ctx, filter, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAfter")
+	}
+
+	var r0 []*domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ItemFilter, *domain.ItemCursor, int) ([]*domain.Item, error)); ok {
+		return returnFunc(ctx, filter, after, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ItemFilter, *domain.ItemCursor, int) []*domain.Item); ok {
+		r0 = returnFunc(ctx, filter, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.ItemFilter, *domain.ItemCursor, int) error); ok {
+		r1 = returnFunc(ctx, filter, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_ListAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAfter'
+type mockitemRepository_ListAfter_Call struct {
+	*mock.Call
+}
+
+// ListAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *domain.ItemFilter
+//   - after *domain.ItemCursor
+//   - limit int
+func (_e *mockitemRepository_Expecter) ListAfter(ctx interface{}, filter interface{}, after interface{}, limit interface{}) *mockitemRepository_ListAfter_Call {
+	return &mockitemRepository_ListAfter_Call{Call: _e.mock.On("ListAfter", ctx, filter, after, limit)}
+}
+
+func (_c *mockitemRepository_ListAfter_Call) Run(run func(ctx context.Context, filter *domain.ItemFilter, after *domain.ItemCursor, limit int)) *mockitemRepository_ListAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.ItemFilter
+		if args[1] != nil {
+			arg1 = args[1].(*domain.ItemFilter)
+		}
+		var arg2 *domain.ItemCursor
+		if args[2] != nil {
+			arg2 = args[2].(*domain.ItemCursor)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_ListAfter_Call) Return(items []*domain.Item, err error) *mockitemRepository_ListAfter_Call {
+	_c.Call.Return(items, err)
+	return _c
+}
+
+func (_c *mockitemRepository_ListAfter_Call) RunAndReturn(run func(ctx context.Context, filter *domain.ItemFilter, after *domain.ItemCursor, limit int) ([]*domain.Item, error)) *mockitemRepository_ListAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type mockitemRepository
+func (_mock *mockitemRepository) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error) {
+	ret := _mock.Called(ctx, userID, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *domain.Item
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *domain.UpdateItemInput) (*domain.Item, error)); ok {
+		return returnFunc(ctx, userID, id, input)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *domain.UpdateItemInput) *domain.Item); ok {
+		r0 = returnFunc(ctx, userID, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Item)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, *domain.UpdateItemInput) error); ok {
+		r1 = returnFunc(ctx, userID, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockitemRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockitemRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - id uuid.UUID
+//   - input *domain.UpdateItemInput
+func (_e *mockitemRepository_Expecter) Update(ctx interface{}, userID interface{}, id interface{}, input interface{}) *mockitemRepository_Update_Call {
+	return &mockitemRepository_Update_Call{Call: _e.mock.On("Update", ctx, userID, id, input)}
+}
+
+func (_c *mockitemRepository_Update_Call) Run(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput)) *mockitemRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 *domain.UpdateItemInput
+		if args[3] != nil {
+			arg3 = args[3].(*domain.UpdateItemInput)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockitemRepository_Update_Call) Return(item *domain.Item, err error) *mockitemRepository_Update_Call {
+	_c.Call.Return(item, err)
+	return _c
+}
+
+func (_c *mockitemRepository_Update_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error)) *mockitemRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockoidcProvider creates a new instance of mockoidcProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockoidcProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockoidcProvider {
+	mock := &mockoidcProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockoidcProvider is an autogenerated mock type for the oidcProvider type
+type mockoidcProvider struct {
+	mock.Mock
+}
+
+type mockoidcProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockoidcProvider) EXPECT() *mockoidcProvider_Expecter {
+	return &mockoidcProvider_Expecter{mock: &_m.Mock}
+}
+
+// AuthURL provides a mock function for the type mockoidcProvider
+func (_mock *mockoidcProvider) AuthURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	ret := _mock.Called(ctx, state, nonce, verifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthURL")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return returnFunc(ctx, state, nonce, verifier)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = returnFunc(ctx, state, nonce, verifier)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, state, nonce, verifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcProvider_AuthURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthURL'
+type mockoidcProvider_AuthURL_Call struct {
+	*mock.Call
+}
+
+// AuthURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - state string
+//   - nonce string
+//   - verifier string
+func (_e *mockoidcProvider_Expecter) AuthURL(ctx interface{}, state interface{}, nonce interface{}, verifier interface{}) *mockoidcProvider_AuthURL_Call {
+	return &mockoidcProvider_AuthURL_Call{Call: _e.mock.On("AuthURL", ctx, state, nonce, verifier)}
+}
+
+func (_c *mockoidcProvider_AuthURL_Call) Run(run func(ctx context.Context, state string, nonce string, verifier string)) *mockoidcProvider_AuthURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcProvider_AuthURL_Call) Return(s string, err error) *mockoidcProvider_AuthURL_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *mockoidcProvider_AuthURL_Call) RunAndReturn(run func(ctx context.Context, state string, nonce string, verifier string) (string, error)) *mockoidcProvider_AuthURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Exchange provides a mock function for the type mockoidcProvider
+func (_mock *mockoidcProvider) Exchange(ctx context.Context, code string, verifier string, nonce string) (*domain.OIDCIdentity, error) {
+	ret := _mock.Called(ctx, code, verifier, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 *domain.OIDCIdentity
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.OIDCIdentity, error)); ok {
+		return returnFunc(ctx, code, verifier, nonce)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.OIDCIdentity); ok {
+		r0 = returnFunc(ctx, code, verifier, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCIdentity)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = returnFunc(ctx, code, verifier, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcProvider_Exchange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exchange'
+type mockoidcProvider_Exchange_Call struct {
+	*mock.Call
+}
+
+// Exchange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - verifier string
+//   - nonce string
+func (_e *mockoidcProvider_Expecter) Exchange(ctx interface{}, code interface{}, verifier interface{}, nonce interface{}) *mockoidcProvider_Exchange_Call {
+	return &mockoidcProvider_Exchange_Call{Call: _e.mock.On("Exchange", ctx, code, verifier, nonce)}
+}
+
+func (_c *mockoidcProvider_Exchange_Call) Run(run func(ctx context.Context, code string, verifier string, nonce string)) *mockoidcProvider_Exchange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcProvider_Exchange_Call) Return(oidcIdentity *domain.OIDCIdentity, err error) *mockoidcProvider_Exchange_Call {
+	_c.Call.Return(oidcIdentity, err)
+	return _c
+}
+
+func (_c *mockoidcProvider_Exchange_Call) RunAndReturn(run func(ctx context.Context, code string, verifier string, nonce string) (*domain.OIDCIdentity, error)) *mockoidcProvider_Exchange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockoidcRepository creates a new instance of mockoidcRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockoidcRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockoidcRepository {
+	mock := &mockoidcRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockoidcRepository is an autogenerated mock type for the oidcRepository type
+type mockoidcRepository struct {
+	mock.Mock
+}
+
+type mockoidcRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockoidcRepository) EXPECT() *mockoidcRepository_Expecter {
+	return &mockoidcRepository_Expecter{mock: &_m.Mock}
+}
+
+// ConsumeLoginState provides a mock function for the type mockoidcRepository
+func (_mock *mockoidcRepository) ConsumeLoginState(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	ret := _mock.Called(ctx, stateHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeLoginState")
+	}
+
+	var r0 *domain.OIDCLoginState
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.OIDCLoginState, error)); ok {
+		return returnFunc(ctx, stateHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.OIDCLoginState); ok {
+		r0 = returnFunc(ctx, stateHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCLoginState)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, stateHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcRepository_ConsumeLoginState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeLoginState'
+type mockoidcRepository_ConsumeLoginState_Call struct {
+	*mock.Call
+}
+
+// ConsumeLoginState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stateHash string
+func (_e *mockoidcRepository_Expecter) ConsumeLoginState(ctx interface{}, stateHash interface{}) *mockoidcRepository_ConsumeLoginState_Call {
+	return &mockoidcRepository_ConsumeLoginState_Call{Call: _e.mock.On("ConsumeLoginState", ctx, stateHash)}
+}
+
+func (_c *mockoidcRepository_ConsumeLoginState_Call) Run(run func(ctx context.Context, stateHash string)) *mockoidcRepository_ConsumeLoginState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcRepository_ConsumeLoginState_Call) Return(oidcLoginState *domain.OIDCLoginState, err error) *mockoidcRepository_ConsumeLoginState_Call {
+	_c.Call.Return(oidcLoginState, err)
+	return _c
+}
+
+func (_c *mockoidcRepository_ConsumeLoginState_Call) RunAndReturn(run func(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error)) *mockoidcRepository_ConsumeLoginState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateLoginState provides a mock function for the type mockoidcRepository
+func (_mock *mockoidcRepository) CreateLoginState(ctx context.Context, st *domain.OIDCLoginState) error {
+	ret := _mock.Called(ctx, st)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLoginState")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.OIDCLoginState) error); ok {
+		r0 = returnFunc(ctx, st)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockoidcRepository_CreateLoginState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateLoginState'
+type mockoidcRepository_CreateLoginState_Call struct {
+	*mock.Call
+}
+
+// CreateLoginState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - st *domain.OIDCLoginState
+func (_e *mockoidcRepository_Expecter) CreateLoginState(ctx interface{}, st interface{}) *mockoidcRepository_CreateLoginState_Call {
+	return &mockoidcRepository_CreateLoginState_Call{Call: _e.mock.On("CreateLoginState", ctx, st)}
+}
+
+func (_c *mockoidcRepository_CreateLoginState_Call) Run(run func(ctx context.Context, st *domain.OIDCLoginState)) *mockoidcRepository_CreateLoginState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.OIDCLoginState
+		if args[1] != nil {
+			arg1 = args[1].(*domain.OIDCLoginState)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcRepository_CreateLoginState_Call) Return(err error) *mockoidcRepository_CreateLoginState_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockoidcRepository_CreateLoginState_Call) RunAndReturn(run func(ctx context.Context, st *domain.OIDCLoginState) error) *mockoidcRepository_CreateLoginState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUserWithIdentity provides a mock function for the type mockoidcRepository
+func (_mock *mockoidcRepository) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.OIDCIdentity) (*domain.User, error) {
+	ret := _mock.Called(ctx, user, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUserWithIdentity")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User, *domain.OIDCIdentity) (*domain.User, error)); ok {
+		return returnFunc(ctx, user, identity)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User, *domain.OIDCIdentity) *domain.User); ok {
+		r0 = returnFunc(ctx, user, identity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.User, *domain.OIDCIdentity) error); ok {
+		r1 = returnFunc(ctx, user, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcRepository_CreateUserWithIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUserWithIdentity'
+type mockoidcRepository_CreateUserWithIdentity_Call struct {
+	*mock.Call
+}
+
+// CreateUserWithIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *domain.User
+//   - identity *domain.OIDCIdentity
+func (_e *mockoidcRepository_Expecter) CreateUserWithIdentity(ctx interface{}, user interface{}, identity interface{}) *mockoidcRepository_CreateUserWithIdentity_Call {
+	return &mockoidcRepository_CreateUserWithIdentity_Call{Call: _e.mock.On("CreateUserWithIdentity", ctx, user, identity)}
+}
+
+func (_c *mockoidcRepository_CreateUserWithIdentity_Call) Run(run func(ctx context.Context, user *domain.User, identity *domain.OIDCIdentity)) *mockoidcRepository_CreateUserWithIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.User
+		if args[1] != nil {
+			arg1 = args[1].(*domain.User)
+		}
+		var arg2 *domain.OIDCIdentity
+		if args[2] != nil {
+			arg2 = args[2].(*domain.OIDCIdentity)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcRepository_CreateUserWithIdentity_Call) Return(user *domain.User, err error) *mockoidcRepository_CreateUserWithIdentity_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockoidcRepository_CreateUserWithIdentity_Call) RunAndReturn(run func(ctx context.Context, user *domain.User, identity *domain.OIDCIdentity) (*domain.User, error)) *mockoidcRepository_CreateUserWithIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkIdentity provides a mock function for the type mockoidcRepository
+func (_mock *mockoidcRepository) LinkIdentity(ctx context.Context, userID uuid.UUID, identity *domain.OIDCIdentity) error {
+	ret := _mock.Called(ctx, userID, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkIdentity")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.OIDCIdentity) error); ok {
+		r0 = returnFunc(ctx, userID, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockoidcRepository_LinkIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkIdentity'
+type mockoidcRepository_LinkIdentity_Call struct {
+	*mock.Call
+}
+
+// LinkIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - identity *domain.OIDCIdentity
+func (_e *mockoidcRepository_Expecter) LinkIdentity(ctx interface{}, userID interface{}, identity interface{}) *mockoidcRepository_LinkIdentity_Call {
+	return &mockoidcRepository_LinkIdentity_Call{Call: _e.mock.On("LinkIdentity", ctx, userID, identity)}
+}
+
+func (_c *mockoidcRepository_LinkIdentity_Call) Run(run func(ctx context.Context, userID uuid.UUID, identity *domain.OIDCIdentity)) *mockoidcRepository_LinkIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.OIDCIdentity
+		if args[2] != nil {
+			arg2 = args[2].(*domain.OIDCIdentity)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcRepository_LinkIdentity_Call) Return(err error) *mockoidcRepository_LinkIdentity_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockoidcRepository_LinkIdentity_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, identity *domain.OIDCIdentity) error) *mockoidcRepository_LinkIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordIdentityLogin provides a mock function for the type mockoidcRepository
+func (_mock *mockoidcRepository) RecordIdentityLogin(ctx context.Context, issuer string, subject string) (*domain.User, error) {
+	ret := _mock.Called(ctx, issuer, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordIdentityLogin")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, issuer, subject)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.User); ok {
+		r0 = returnFunc(ctx, issuer, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, issuer, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcRepository_RecordIdentityLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordIdentityLogin'
+type mockoidcRepository_RecordIdentityLogin_Call struct {
+	*mock.Call
+}
+
+// RecordIdentityLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - issuer string
+//   - subject string
+func (_e *mockoidcRepository_Expecter) RecordIdentityLogin(ctx interface{}, issuer interface{}, subject interface{}) *mockoidcRepository_RecordIdentityLogin_Call {
+	return &mockoidcRepository_RecordIdentityLogin_Call{Call: _e.mock.On("RecordIdentityLogin", ctx, issuer, subject)}
+}
+
+func (_c *mockoidcRepository_RecordIdentityLogin_Call) Run(run func(ctx context.Context, issuer string, subject string)) *mockoidcRepository_RecordIdentityLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcRepository_RecordIdentityLogin_Call) Return(user *domain.User, err error) *mockoidcRepository_RecordIdentityLogin_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockoidcRepository_RecordIdentityLogin_Call) RunAndReturn(run func(ctx context.Context, issuer string, subject string) (*domain.User, error)) *mockoidcRepository_RecordIdentityLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockoidcUserRepository creates a new instance of mockoidcUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockoidcUserRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockoidcUserRepository {
+	mock := &mockoidcUserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockoidcUserRepository is an autogenerated mock type for the oidcUserRepository type
+type mockoidcUserRepository struct {
+	mock.Mock
+}
+
+type mockoidcUserRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockoidcUserRepository) EXPECT() *mockoidcUserRepository_Expecter {
+	return &mockoidcUserRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetByUsername provides a mock function for the type mockoidcUserRepository
+func (_mock *mockoidcUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ret := _mock.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUsername")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, username)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = returnFunc(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcUserRepository_GetByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByUsername'
+type mockoidcUserRepository_GetByUsername_Call struct {
+	*mock.Call
+}
+
+// GetByUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *mockoidcUserRepository_Expecter) GetByUsername(ctx interface{}, username interface{}) *mockoidcUserRepository_GetByUsername_Call {
+	return &mockoidcUserRepository_GetByUsername_Call{Call: _e.mock.On("GetByUsername", ctx, username)}
+}
+
+func (_c *mockoidcUserRepository_GetByUsername_Call) Run(run func(ctx context.Context, username string)) *mockoidcUserRepository_GetByUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcUserRepository_GetByUsername_Call) Return(user *domain.User, err error) *mockoidcUserRepository_GetByUsername_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockoidcUserRepository_GetByUsername_Call) RunAndReturn(run func(ctx context.Context, username string) (*domain.User, error)) *mockoidcUserRepository_GetByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRole provides a mock function for the type mockoidcUserRepository
+func (_mock *mockoidcUserRepository) UpdateRole(ctx context.Context, actorID uuid.UUID, id uuid.UUID, role domain.Role) (*domain.User, error) {
+	ret := _mock.Called(ctx, actorID, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, domain.Role) (*domain.User, error)); ok {
+		return returnFunc(ctx, actorID, id, role)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, domain.Role) *domain.User); ok {
+		r0 = returnFunc(ctx, actorID, id, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, domain.Role) error); ok {
+		r1 = returnFunc(ctx, actorID, id, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoidcUserRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type mockoidcUserRepository_UpdateRole_Call struct {
+	*mock.Call
+}
+
+// UpdateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - id uuid.UUID
+//   - role domain.Role
+func (_e *mockoidcUserRepository_Expecter) UpdateRole(ctx interface{}, actorID interface{}, id interface{}, role interface{}) *mockoidcUserRepository_UpdateRole_Call {
+	return &mockoidcUserRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, actorID, id, role)}
+}
+
+func (_c *mockoidcUserRepository_UpdateRole_Call) Run(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, role domain.Role)) *mockoidcUserRepository_UpdateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 domain.Role
+		if args[3] != nil {
+			arg3 = args[3].(domain.Role)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoidcUserRepository_UpdateRole_Call) Return(user *domain.User, err error) *mockoidcUserRepository_UpdateRole_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockoidcUserRepository_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, role domain.Role) (*domain.User, error)) *mockoidcUserRepository_UpdateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMocksessionStarter creates a new instance of mocksessionStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMocksessionStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mocksessionStarter {
+	mock := &mocksessionStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mocksessionStarter is an autogenerated mock type for the sessionStarter type
+type mocksessionStarter struct {
+	mock.Mock
+}
+
+type mocksessionStarter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mocksessionStarter) EXPECT() *mocksessionStarter_Expecter {
+	return &mocksessionStarter_Expecter{mock: &_m.Mock}
+}
+
+// StartSession provides a mock function for the type mocksessionStarter
+func (_mock *mocksessionStarter) StartSession(ctx context.Context, user *domain.User) (*domain.TokenPair, error) {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartSession")
+	}
+
+	var r0 *domain.TokenPair
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User) (*domain.TokenPair, error)); ok {
+		return returnFunc(ctx, user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.User) *domain.TokenPair); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TokenPair)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.User) error); ok {
+		r1 = returnFunc(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mocksessionStarter_StartSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartSession'
+type mocksessionStarter_StartSession_Call struct {
+	*mock.Call
+}
+
+// StartSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *domain.User
+func (_e *mocksessionStarter_Expecter) StartSession(ctx interface{}, user interface{}) *mocksessionStarter_StartSession_Call {
+	return &mocksessionStarter_StartSession_Call{Call: _e.mock.On("StartSession", ctx, user)}
+}
+
+func (_c *mocksessionStarter_StartSession_Call) Run(run func(ctx context.Context, user *domain.User)) *mocksessionStarter_StartSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.User
+		if args[1] != nil {
+			arg1 = args[1].(*domain.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mocksessionStarter_StartSession_Call) Return(tokenPair *domain.TokenPair, err error) *mocksessionStarter_StartSession_Call {
+	_c.Call.Return(tokenPair, err)
+	return _c
+}
+
+func (_c *mocksessionStarter_StartSession_Call) RunAndReturn(run func(ctx context.Context, user *domain.User) (*domain.TokenPair, error)) *mocksessionStarter_StartSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockoutboxRepository creates a new instance of mockoutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockoutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockoutboxRepository {
+	mock := &mockoutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockoutboxRepository is an autogenerated mock type for the outboxRepository type
+type mockoutboxRepository struct {
+	mock.Mock
+}
+
+type mockoutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockoutboxRepository) EXPECT() *mockoutboxRepository_Expecter {
+	return &mockoutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// Claim provides a mock function for the type mockoutboxRepository
+func (_mock *mockoutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	ret := _mock.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 []*domain.OutboxEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]*domain.OutboxEvent, error)); ok {
+		return returnFunc(ctx, limit, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) []*domain.OutboxEvent); ok {
+		r0 = returnFunc(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OutboxEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = returnFunc(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoutboxRepository_Claim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Claim'
+type mockoutboxRepository_Claim_Call struct {
+	*mock.Call
+}
+
+// Claim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - lease time.Duration
+func (_e *mockoutboxRepository_Expecter) Claim(ctx interface{}, limit interface{}, lease interface{}) *mockoutboxRepository_Claim_Call {
+	return &mockoutboxRepository_Claim_Call{Call: _e.mock.On("Claim", ctx, limit, lease)}
+}
+
+func (_c *mockoutboxRepository_Claim_Call) Run(run func(ctx context.Context, limit int, lease time.Duration)) *mockoutboxRepository_Claim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoutboxRepository_Claim_Call) Return(outboxEvents []*domain.OutboxEvent, err error) *mockoutboxRepository_Claim_Call {
+	_c.Call.Return(outboxEvents, err)
+	return _c
+}
+
+func (_c *mockoutboxRepository_Claim_Call) RunAndReturn(run func(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)) *mockoutboxRepository_Claim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePublished provides a mock function for the type mockoutboxRepository
+func (_mock *mockoutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	ret := _mock.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePublished")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, before)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockoutboxRepository_DeletePublished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePublished'
+type mockoutboxRepository_DeletePublished_Call struct {
+	*mock.Call
+}
+
+// DeletePublished is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *mockoutboxRepository_Expecter) DeletePublished(ctx interface{}, before interface{}) *mockoutboxRepository_DeletePublished_Call {
+	return &mockoutboxRepository_DeletePublished_Call{Call: _e.mock.On("DeletePublished", ctx, before)}
+}
+
+func (_c *mockoutboxRepository_DeletePublished_Call) Run(run func(ctx context.Context, before time.Time)) *mockoutboxRepository_DeletePublished_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoutboxRepository_DeletePublished_Call) Return(n int64, err error) *mockoutboxRepository_DeletePublished_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *mockoutboxRepository_DeletePublished_Call) RunAndReturn(run func(ctx context.Context, before time.Time) (int64, error)) *mockoutboxRepository_DeletePublished_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkFailed provides a mock function for the type mockoutboxRepository
+func (_mock *mockoutboxRepository) MarkFailed(ctx context.Context, id int64, delay time.Duration, reason string) error {
+	ret := _mock.Called(ctx, id, delay, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, time.Duration, string) error); ok {
+		r0 = returnFunc(ctx, id, delay, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockoutboxRepository_MarkFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkFailed'
+type mockoutboxRepository_MarkFailed_Call struct {
+	*mock.Call
+}
+
+// MarkFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - delay time.Duration
+//   - reason string
+func (_e *mockoutboxRepository_Expecter) MarkFailed(ctx interface{}, id interface{}, delay interface{}, reason interface{}) *mockoutboxRepository_MarkFailed_Call {
+	return &mockoutboxRepository_MarkFailed_Call{Call: _e.mock.On("MarkFailed", ctx, id, delay, reason)}
+}
+
+func (_c *mockoutboxRepository_MarkFailed_Call) Run(run func(ctx context.Context, id int64, delay time.Duration, reason string)) *mockoutboxRepository_MarkFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoutboxRepository_MarkFailed_Call) Return(err error) *mockoutboxRepository_MarkFailed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockoutboxRepository_MarkFailed_Call) RunAndReturn(run func(ctx context.Context, id int64, delay time.Duration, reason string) error) *mockoutboxRepository_MarkFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkPublished provides a mock function for the type mockoutboxRepository
+func (_mock *mockoutboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPublished")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) error); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockoutboxRepository_MarkPublished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkPublished'
+type mockoutboxRepository_MarkPublished_Call struct {
+	*mock.Call
+}
+
+// MarkPublished is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+func (_e *mockoutboxRepository_Expecter) MarkPublished(ctx interface{}, ids interface{}) *mockoutboxRepository_MarkPublished_Call {
+	return &mockoutboxRepository_MarkPublished_Call{Call: _e.mock.On("MarkPublished", ctx, ids)}
+}
+
+func (_c *mockoutboxRepository_MarkPublished_Call) Run(run func(ctx context.Context, ids []int64)) *mockoutboxRepository_MarkPublished_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoutboxRepository_MarkPublished_Call) Return(err error) *mockoutboxRepository_MarkPublished_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockoutboxRepository_MarkPublished_Call) RunAndReturn(run func(ctx context.Context, ids []int64) error) *mockoutboxRepository_MarkPublished_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function for the type mockoutboxRepository
+func (_mock *mockoutboxRepository) Release(ctx context.Context, ids []int64) error {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64) error); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockoutboxRepository_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type mockoutboxRepository_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+func (_e *mockoutboxRepository_Expecter) Release(ctx interface{}, ids interface{}) *mockoutboxRepository_Release_Call {
+	return &mockoutboxRepository_Release_Call{Call: _e.mock.On("Release", ctx, ids)}
+}
+
+func (_c *mockoutboxRepository_Release_Call) Run(run func(ctx context.Context, ids []int64)) *mockoutboxRepository_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockoutboxRepository_Release_Call) Return(err error) *mockoutboxRepository_Release_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockoutboxRepository_Release_Call) RunAndReturn(run func(ctx context.Context, ids []int64) error) *mockoutboxRepository_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockeventFeed creates a new instance of mockeventFeed. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockeventFeed(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockeventFeed {
+	mock := &mockeventFeed{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockeventFeed is an autogenerated mock type for the eventFeed type
+type mockeventFeed struct {
+	mock.Mock
+}
+
+type mockeventFeed_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockeventFeed) EXPECT() *mockeventFeed_Expecter {
+	return &mockeventFeed_Expecter{mock: &_m.Mock}
+}
+
+// Subscribe provides a mock function for the type mockeventFeed
+func (_mock *mockeventFeed) Subscribe(ctx context.Context, claims *domain.AuthClaims, lastEventID int64) (*domain.EventSubscription, error) {
+	ret := _mock.Called(ctx, claims, lastEventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 *domain.EventSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, int64) (*domain.EventSubscription, error)); ok {
+		return returnFunc(ctx, claims, lastEventID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuthClaims, int64) *domain.EventSubscription); ok {
+		r0 = returnFunc(ctx, claims, lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.EventSubscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuthClaims, int64) error); ok {
+		r1 = returnFunc(ctx, claims, lastEventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockeventFeed_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type mockeventFeed_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims *domain.AuthClaims
+//   - lastEventID int64
+func (_e *mockeventFeed_Expecter) Subscribe(ctx interface{}, claims interface{}, lastEventID interface{}) *mockeventFeed_Subscribe_Call {
+	return &mockeventFeed_Subscribe_Call{Call: _e.mock.On("Subscribe", ctx, claims, lastEventID)}
+}
+
+func (_c *mockeventFeed_Subscribe_Call) Run(run func(ctx context.Context, claims *domain.AuthClaims, lastEventID int64)) *mockeventFeed_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuthClaims
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuthClaims)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockeventFeed_Subscribe_Call) Return(eventSubscription *domain.EventSubscription, err error) *mockeventFeed_Subscribe_Call {
+	_c.Call.Return(eventSubscription, err)
+	return _c
+}
+
+func (_c *mockeventFeed_Subscribe_Call) RunAndReturn(run func(ctx context.Context, claims *domain.AuthClaims, lastEventID int64) (*domain.EventSubscription, error)) *mockeventFeed_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockuserAdminRepository creates a new instance of mockuserAdminRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockuserAdminRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockuserAdminRepository {
+	mock := &mockuserAdminRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockuserAdminRepository is an autogenerated mock type for the userAdminRepository type
+type mockuserAdminRepository struct {
+	mock.Mock
+}
+
+type mockuserAdminRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockuserAdminRepository) EXPECT() *mockuserAdminRepository_Expecter {
+	return &mockuserAdminRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) Create(ctx context.Context, actorID uuid.UUID, user *domain.User) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, actorID, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.User) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, actorID, user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.User) uuid.UUID); ok {
+		r0 = returnFunc(ctx, actorID, user)
+	} else {
+		r0 = ret.Get(0).(uuid.UUID)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.User) error); ok {
+		r1 = returnFunc(ctx, actorID, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserAdminRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockuserAdminRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - user *domain.User
+func (_e *mockuserAdminRepository_Expecter) Create(ctx interface{}, actorID interface{}, user interface{}) *mockuserAdminRepository_Create_Call {
+	return &mockuserAdminRepository_Create_Call{Call: _e.mock.On("Create", ctx, actorID, user)}
+}
+
+func (_c *mockuserAdminRepository_Create_Call) Run(run func(ctx context.Context, actorID uuid.UUID, user *domain.User)) *mockuserAdminRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.User
+		if args[2] != nil {
+			arg2 = args[2].(*domain.User)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_Create_Call) Return(uuid uuid.UUID, err error) *mockuserAdminRepository_Create_Call {
+	_c.Call.Return(uuid, err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_Create_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, user *domain.User) (uuid.UUID, error)) *mockuserAdminRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) Delete(ctx context.Context, actorID uuid.UUID, id uuid.UUID) error {
+	ret := _mock.Called(ctx, actorID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, actorID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mockuserAdminRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockuserAdminRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - id uuid.UUID
+func (_e *mockuserAdminRepository_Expecter) Delete(ctx interface{}, actorID interface{}, id interface{}) *mockuserAdminRepository_Delete_Call {
+	return &mockuserAdminRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, actorID, id)}
+}
+
+func (_c *mockuserAdminRepository_Delete_Call) Run(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID)) *mockuserAdminRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_Delete_Call) Return(err error) *mockuserAdminRepository_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID) error) *mockuserAdminRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserAdminRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockuserAdminRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockuserAdminRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockuserAdminRepository_GetByID_Call {
+	return &mockuserAdminRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *mockuserAdminRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockuserAdminRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_GetByID_Call) Return(user *domain.User, err error) *mockuserAdminRepository_GetByID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.User, error)) *mockuserAdminRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) List(ctx context.Context) ([]*domain.User, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.User, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.User); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserAdminRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockuserAdminRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockuserAdminRepository_Expecter) List(ctx interface{}) *mockuserAdminRepository_List_Call {
+	return &mockuserAdminRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *mockuserAdminRepository_List_Call) Run(run func(ctx context.Context)) *mockuserAdminRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_List_Call) Return(users []*domain.User, err error) *mockuserAdminRepository_List_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.User, error)) *mockuserAdminRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetDisabled provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) SetDisabled(ctx context.Context, actorID uuid.UUID, id uuid.UUID, disabled bool) (*domain.User, error) {
+	ret := _mock.Called(ctx, actorID, id, disabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetDisabled")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, bool) (*domain.User, error)); ok {
+		return returnFunc(ctx, actorID, id, disabled)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, bool) *domain.User); ok {
+		r0 = returnFunc(ctx, actorID, id, disabled)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, bool) error); ok {
+		r1 = returnFunc(ctx, actorID, id, disabled)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserAdminRepository_SetDisabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetDisabled'
+type mockuserAdminRepository_SetDisabled_Call struct {
+	*mock.Call
+}
+
+// SetDisabled is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - id uuid.UUID
+//   - disabled bool
+func (_e *mockuserAdminRepository_Expecter) SetDisabled(ctx interface{}, actorID interface{}, id interface{}, disabled interface{}) *mockuserAdminRepository_SetDisabled_Call {
+	return &mockuserAdminRepository_SetDisabled_Call{Call: _e.mock.On("SetDisabled", ctx, actorID, id, disabled)}
+}
+
+func (_c *mockuserAdminRepository_SetDisabled_Call) Run(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, disabled bool)) *mockuserAdminRepository_SetDisabled_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 bool
+		if args[3] != nil {
+			arg3 = args[3].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_SetDisabled_Call) Return(user *domain.User, err error) *mockuserAdminRepository_SetDisabled_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_SetDisabled_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, disabled bool) (*domain.User, error)) *mockuserAdminRepository_SetDisabled_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePassword provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) UpdatePassword(ctx context.Context, actorID uuid.UUID, id uuid.UUID, passwordHash string) (*domain.User, error) {
+	ret := _mock.Called(ctx, actorID, id, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (*domain.User, error)); ok {
+		return returnFunc(ctx, actorID, id, passwordHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) *domain.User); ok {
+		r0 = returnFunc(ctx, actorID, id, passwordHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = returnFunc(ctx, actorID, id, passwordHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserAdminRepository_UpdatePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePassword'
+type mockuserAdminRepository_UpdatePassword_Call struct {
+	*mock.Call
+}
+
+// UpdatePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - id uuid.UUID
+//   - passwordHash string
+func (_e *mockuserAdminRepository_Expecter) UpdatePassword(ctx interface{}, actorID interface{}, id interface{}, passwordHash interface{}) *mockuserAdminRepository_UpdatePassword_Call {
+	return &mockuserAdminRepository_UpdatePassword_Call{Call: _e.mock.On("UpdatePassword", ctx, actorID, id, passwordHash)}
+}
+
+func (_c *mockuserAdminRepository_UpdatePassword_Call) Run(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, passwordHash string)) *mockuserAdminRepository_UpdatePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_UpdatePassword_Call) Return(user *domain.User, err error) *mockuserAdminRepository_UpdatePassword_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_UpdatePassword_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, passwordHash string) (*domain.User, error)) *mockuserAdminRepository_UpdatePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRole provides a mock function for the type mockuserAdminRepository
+func (_mock *mockuserAdminRepository) UpdateRole(ctx context.Context, actorID uuid.UUID, id uuid.UUID, role domain.Role) (*domain.User, error) {
+	ret := _mock.Called(ctx, actorID, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, domain.Role) (*domain.User, error)); ok {
+		return returnFunc(ctx, actorID, id, role)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, domain.Role) *domain.User); ok {
+		r0 = returnFunc(ctx, actorID, id, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, domain.Role) error); ok {
+		r1 = returnFunc(ctx, actorID, id, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockuserAdminRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type mockuserAdminRepository_UpdateRole_Call struct {
+	*mock.Call
+}
+
+// UpdateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - id uuid.UUID
+//   - role domain.Role
+func (_e *mockuserAdminRepository_Expecter) UpdateRole(ctx interface{}, actorID interface{}, id interface{}, role interface{}) *mockuserAdminRepository_UpdateRole_Call {
+	return &mockuserAdminRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, actorID, id, role)}
+}
+
+func (_c *mockuserAdminRepository_UpdateRole_Call) Run(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, role domain.Role)) *mockuserAdminRepository_UpdateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 domain.Role
+		if args[3] != nil {
+			arg3 = args[3].(domain.Role)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockuserAdminRepository_UpdateRole_Call) Return(user *domain.User, err error) *mockuserAdminRepository_UpdateRole_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockuserAdminRepository_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, actorID uuid.UUID, id uuid.UUID, role domain.Role) (*domain.User, error)) *mockuserAdminRepository_UpdateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMocksessionRevoker creates a new instance of mocksessionRevoker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMocksessionRevoker(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *mockauditRepository {
-	mock := &mockauditRepository{}
+}) *mocksessionRevoker {
+	mock := &mocksessionRevoker{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -26,68 +5870,226 @@ func newMockauditRepository(t interface {
 	return mock
 }
 
-// mockauditRepository is an autogenerated mock type for the auditRepository type
-type mockauditRepository struct {
+// mocksessionRevoker is an autogenerated mock type for the sessionRevoker type
+type mocksessionRevoker struct {
 	mock.Mock
 }
 
-type mockauditRepository_Expecter struct {
+type mocksessionRevoker_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *mockauditRepository) EXPECT() *mockauditRepository_Expecter {
-	return &mockauditRepository_Expecter{mock: &_m.Mock}
+func (_m *mocksessionRevoker) EXPECT() *mocksessionRevoker_Expecter {
+	return &mocksessionRevoker_Expecter{mock: &_m.Mock}
 }
 
-// GetByItemID provides a mock function for the type mockauditRepository
-func (_mock *mockauditRepository) GetByItemID(ctx context.Context, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error) {
-	ret := _mock.Called(ctx, itemID)
+// RevokeUserSessions provides a mock function for the type mocksessionRevoker
+func (_mock *mocksessionRevoker) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	ret := _mock.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByItemID")
+		panic("no return value specified for RevokeUserSessions")
 	}
 
-	var r0 []*domain.AuditEntryWithUser
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// mocksessionRevoker_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type mocksessionRevoker_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *mocksessionRevoker_Expecter) RevokeUserSessions(ctx interface{}, userID interface{}) *mocksessionRevoker_RevokeUserSessions_Call {
+	return &mocksessionRevoker_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userID)}
+}
+
+func (_c *mocksessionRevoker_RevokeUserSessions_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *mocksessionRevoker_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mocksessionRevoker_RevokeUserSessions_Call) Return(err error) *mocksessionRevoker_RevokeUserSessions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *mocksessionRevoker_RevokeUserSessions_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID) error) *mocksessionRevoker_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockwebhookRepository creates a new instance of mockwebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockwebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockwebhookRepository {
+	mock := &mockwebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockwebhookRepository is an autogenerated mock type for the webhookRepository type
+type mockwebhookRepository struct {
+	mock.Mock
+}
+
+type mockwebhookRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockwebhookRepository) EXPECT() *mockwebhookRepository_Expecter {
+	return &mockwebhookRepository_Expecter{mock: &_m.Mock}
+}
+
+// Claim provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	ret := _mock.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 []*domain.WebhookDelivery
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.AuditEntryWithUser, error)); ok {
-		return returnFunc(ctx, itemID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]*domain.WebhookDelivery, error)); ok {
+		return returnFunc(ctx, limit, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) []*domain.WebhookDelivery); ok {
+		r0 = returnFunc(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = returnFunc(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockwebhookRepository_Claim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Claim'
+type mockwebhookRepository_Claim_Call struct {
+	*mock.Call
+}
+
+// Claim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - lease time.Duration
+func (_e *mockwebhookRepository_Expecter) Claim(ctx interface{}, limit interface{}, lease interface{}) *mockwebhookRepository_Claim_Call {
+	return &mockwebhookRepository_Claim_Call{Call: _e.mock.On("Claim", ctx, limit, lease)}
+}
+
+func (_c *mockwebhookRepository_Claim_Call) Run(run func(ctx context.Context, limit int, lease time.Duration)) *mockwebhookRepository_Claim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockwebhookRepository_Claim_Call) Return(webhookDeliverys []*domain.WebhookDelivery, err error) *mockwebhookRepository_Claim_Call {
+	_c.Call.Return(webhookDeliverys, err)
+	return _c
+}
+
+func (_c *mockwebhookRepository_Claim_Call) RunAndReturn(run func(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)) *mockwebhookRepository_Claim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) Create(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error) {
+	ret := _mock.Called(ctx, hook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.Webhook
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Webhook) (*domain.Webhook, error)); ok {
+		return returnFunc(ctx, hook)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.AuditEntryWithUser); ok {
-		r0 = returnFunc(ctx, itemID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Webhook) *domain.Webhook); ok {
+		r0 = returnFunc(ctx, hook)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
+			r0 = ret.Get(0).(*domain.Webhook)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, itemID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.Webhook) error); ok {
+		r1 = returnFunc(ctx, hook)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockauditRepository_GetByItemID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByItemID'
-type mockauditRepository_GetByItemID_Call struct {
+// mockwebhookRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockwebhookRepository_Create_Call struct {
 	*mock.Call
 }
 
-// GetByItemID is a helper method to define mock.On call
+// Create is a helper method to define mock.On call
 //   - ctx context.Context
-//   - itemID uuid.UUID
-func (_e *mockauditRepository_Expecter) GetByItemID(ctx interface{}, itemID interface{}) *mockauditRepository_GetByItemID_Call {
-	return &mockauditRepository_GetByItemID_Call{Call: _e.mock.On("GetByItemID", ctx, itemID)}
+//   - hook *domain.Webhook
+func (_e *mockwebhookRepository_Expecter) Create(ctx interface{}, hook interface{}) *mockwebhookRepository_Create_Call {
+	return &mockwebhookRepository_Create_Call{Call: _e.mock.On("Create", ctx, hook)}
 }
 
-func (_c *mockauditRepository_GetByItemID_Call) Run(run func(ctx context.Context, itemID uuid.UUID)) *mockauditRepository_GetByItemID_Call {
+func (_c *mockwebhookRepository_Create_Call) Run(run func(ctx context.Context, hook *domain.Webhook)) *mockwebhookRepository_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 *domain.Webhook
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(*domain.Webhook)
 		}
 		run(
 			arg0,
@@ -97,178 +6099,120 @@ func (_c *mockauditRepository_GetByItemID_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *mockauditRepository_GetByItemID_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, err error) *mockauditRepository_GetByItemID_Call {
-	_c.Call.Return(auditEntryWithUsers, err)
+func (_c *mockwebhookRepository_Create_Call) Return(webhook *domain.Webhook, err error) *mockwebhookRepository_Create_Call {
+	_c.Call.Return(webhook, err)
 	return _c
 }
 
-func (_c *mockauditRepository_GetByItemID_Call) RunAndReturn(run func(ctx context.Context, itemID uuid.UUID) ([]*domain.AuditEntryWithUser, error)) *mockauditRepository_GetByItemID_Call {
+func (_c *mockwebhookRepository_Create_Call) RunAndReturn(run func(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error)) *mockwebhookRepository_Create_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function for the type mockauditRepository
-func (_mock *mockauditRepository) List(ctx context.Context, filter *domain.AuditFilter, limit int, offset int) ([]*domain.AuditEntryWithUser, int64, error) {
-	ret := _mock.Called(ctx, filter, limit, offset)
+// Delete provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for List")
+		panic("no return value specified for Delete")
 	}
 
-	var r0 []*domain.AuditEntryWithUser
-	var r1 int64
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter, int, int) ([]*domain.AuditEntryWithUser, int64, error)); ok {
-		return returnFunc(ctx, filter, limit, offset)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter, int, int) []*domain.AuditEntryWithUser); ok {
-		r0 = returnFunc(ctx, filter, limit, offset)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.AuditEntryWithUser)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.AuditFilter, int, int) int64); ok {
-		r1 = returnFunc(ctx, filter, limit, offset)
-	} else {
-		r1 = ret.Get(1).(int64)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.AuditFilter, int, int) error); ok {
-		r2 = returnFunc(ctx, filter, limit, offset)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Error(0)
 	}
-	return r0, r1, r2
+	return r0
 }
 
-// mockauditRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type mockauditRepository_List_Call struct {
+// mockwebhookRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockwebhookRepository_Delete_Call struct {
 	*mock.Call
 }
 
-// List is a helper method to define mock.On call
+// Delete is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filter *domain.AuditFilter
-//   - limit int
-//   - offset int
-func (_e *mockauditRepository_Expecter) List(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *mockauditRepository_List_Call {
-	return &mockauditRepository_List_Call{Call: _e.mock.On("List", ctx, filter, limit, offset)}
+//   - id uuid.UUID
+func (_e *mockwebhookRepository_Expecter) Delete(ctx interface{}, id interface{}) *mockwebhookRepository_Delete_Call {
+	return &mockwebhookRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
 }
 
-func (_c *mockauditRepository_List_Call) Run(run func(ctx context.Context, filter *domain.AuditFilter, limit int, offset int)) *mockauditRepository_List_Call {
+func (_c *mockwebhookRepository_Delete_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockwebhookRepository_Delete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *domain.AuditFilter
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(*domain.AuditFilter)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
+			arg1 = args[1].(uuid.UUID)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockauditRepository_List_Call) Return(auditEntryWithUsers []*domain.AuditEntryWithUser, n int64, err error) *mockauditRepository_List_Call {
-	_c.Call.Return(auditEntryWithUsers, n, err)
+func (_c *mockwebhookRepository_Delete_Call) Return(err error) *mockwebhookRepository_Delete_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *mockauditRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter *domain.AuditFilter, limit int, offset int) ([]*domain.AuditEntryWithUser, int64, error)) *mockauditRepository_List_Call {
+func (_c *mockwebhookRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *mockwebhookRepository_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// newMockuserRepository creates a new instance of mockuserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func newMockuserRepository(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *mockuserRepository {
-	mock := &mockuserRepository{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// mockuserRepository is an autogenerated mock type for the userRepository type
-type mockuserRepository struct {
-	mock.Mock
-}
-
-type mockuserRepository_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *mockuserRepository) EXPECT() *mockuserRepository_Expecter {
-	return &mockuserRepository_Expecter{mock: &_m.Mock}
-}
-
-// GetByUsername provides a mock function for the type mockuserRepository
-func (_mock *mockuserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
-	ret := _mock.Called(ctx, username)
+// DeleteFinished provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	ret := _mock.Called(ctx, before)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByUsername")
+		panic("no return value specified for DeleteFinished")
 	}
 
-	var r0 *domain.User
+	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
-		return returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, before)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
-		r0 = returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, before)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.User)
-		}
+		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, username)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, before)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockuserRepository_GetByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByUsername'
-type mockuserRepository_GetByUsername_Call struct {
+// mockwebhookRepository_DeleteFinished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteFinished'
+type mockwebhookRepository_DeleteFinished_Call struct {
 	*mock.Call
 }
 
-// GetByUsername is a helper method to define mock.On call
+// DeleteFinished is a helper method to define mock.On call
 //   - ctx context.Context
-//   - username string
-func (_e *mockuserRepository_Expecter) GetByUsername(ctx interface{}, username interface{}) *mockuserRepository_GetByUsername_Call {
-	return &mockuserRepository_GetByUsername_Call{Call: _e.mock.On("GetByUsername", ctx, username)}
+//   - before time.Time
+func (_e *mockwebhookRepository_Expecter) DeleteFinished(ctx interface{}, before interface{}) *mockwebhookRepository_DeleteFinished_Call {
+	return &mockwebhookRepository_DeleteFinished_Call{Call: _e.mock.On("DeleteFinished", ctx, before)}
 }
 
-func (_c *mockuserRepository_GetByUsername_Call) Run(run func(ctx context.Context, username string)) *mockuserRepository_GetByUsername_Call {
+func (_c *mockwebhookRepository_DeleteFinished_Call) Run(run func(ctx context.Context, before time.Time)) *mockwebhookRepository_DeleteFinished_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(time.Time)
 		}
 		run(
 			arg0,
@@ -278,209 +6222,200 @@ func (_c *mockuserRepository_GetByUsername_Call) Run(run func(ctx context.Contex
 	return _c
 }
 
-func (_c *mockuserRepository_GetByUsername_Call) Return(user *domain.User, err error) *mockuserRepository_GetByUsername_Call {
-	_c.Call.Return(user, err)
+func (_c *mockwebhookRepository_DeleteFinished_Call) Return(n int64, err error) *mockwebhookRepository_DeleteFinished_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *mockuserRepository_GetByUsername_Call) RunAndReturn(run func(ctx context.Context, username string) (*domain.User, error)) *mockuserRepository_GetByUsername_Call {
+func (_c *mockwebhookRepository_DeleteFinished_Call) RunAndReturn(run func(ctx context.Context, before time.Time) (int64, error)) *mockwebhookRepository_DeleteFinished_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function for the type mockuserRepository
-func (_mock *mockuserRepository) List(ctx context.Context) ([]*domain.User, error) {
-	ret := _mock.Called(ctx)
+// Enqueue provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) Enqueue(ctx context.Context, ev *domain.OutboxEvent, payload []byte) (int64, error) {
+	ret := _mock.Called(ctx, ev, payload)
 
 	if len(ret) == 0 {
-		panic("no return value specified for List")
+		panic("no return value specified for Enqueue")
 	}
 
-	var r0 []*domain.User
+	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.User, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.OutboxEvent, []byte) (int64, error)); ok {
+		return returnFunc(ctx, ev, payload)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.User); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.OutboxEvent, []byte) int64); ok {
+		r0 = returnFunc(ctx, ev, payload)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.User)
-		}
+		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.OutboxEvent, []byte) error); ok {
+		r1 = returnFunc(ctx, ev, payload)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// mockuserRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type mockuserRepository_List_Call struct {
+// mockwebhookRepository_Enqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enqueue'
+type mockwebhookRepository_Enqueue_Call struct {
 	*mock.Call
 }
 
-// List is a helper method to define mock.On call
+// Enqueue is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *mockuserRepository_Expecter) List(ctx interface{}) *mockuserRepository_List_Call {
-	return &mockuserRepository_List_Call{Call: _e.mock.On("List", ctx)}
+//   - ev *domain.OutboxEvent
+//   - payload []byte
+func (_e *mockwebhookRepository_Expecter) Enqueue(ctx interface{}, ev interface{}, payload interface{}) *mockwebhookRepository_Enqueue_Call {
+	return &mockwebhookRepository_Enqueue_Call{Call: _e.mock.On("Enqueue", ctx, ev, payload)}
 }
 
-func (_c *mockuserRepository_List_Call) Run(run func(ctx context.Context)) *mockuserRepository_List_Call {
+func (_c *mockwebhookRepository_Enqueue_Call) Run(run func(ctx context.Context, ev *domain.OutboxEvent, payload []byte)) *mockwebhookRepository_Enqueue_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 *domain.OutboxEvent
+		if args[1] != nil {
+			arg1 = args[1].(*domain.OutboxEvent)
+		}
+		var arg2 []byte
+		if args[2] != nil {
+			arg2 = args[2].([]byte)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *mockuserRepository_List_Call) Return(users []*domain.User, err error) *mockuserRepository_List_Call {
-	_c.Call.Return(users, err)
+func (_c *mockwebhookRepository_Enqueue_Call) Return(n int64, err error) *mockwebhookRepository_Enqueue_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *mockuserRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.User, error)) *mockuserRepository_List_Call {
+func (_c *mockwebhookRepository_Enqueue_Call) RunAndReturn(run func(ctx context.Context, ev *domain.OutboxEvent, payload []byte) (int64, error)) *mockwebhookRepository_Enqueue_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockTokenManager creates a new instance of MockTokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockTokenManager(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockTokenManager {
-	mock := &MockTokenManager{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// MockTokenManager is an autogenerated mock type for the TokenManager type
-type MockTokenManager struct {
-	mock.Mock
-}
-
-type MockTokenManager_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *MockTokenManager) EXPECT() *MockTokenManager_Expecter {
-	return &MockTokenManager_Expecter{mock: &_m.Mock}
-}
-
-// GenerateJWT provides a mock function for the type MockTokenManager
-func (_mock *MockTokenManager) GenerateJWT(user *domain.User) (string, error) {
-	ret := _mock.Called(user)
+// GetByID provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GenerateJWT")
+		panic("no return value specified for GetByID")
 	}
 
-	var r0 string
+	var r0 *domain.Webhook
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*domain.User) (string, error)); ok {
-		return returnFunc(user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Webhook, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(*domain.User) string); ok {
-		r0 = returnFunc(user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Webhook); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Webhook)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(*domain.User) error); ok {
-		r1 = returnFunc(user)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockTokenManager_GenerateJWT_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateJWT'
-type MockTokenManager_GenerateJWT_Call struct {
+// mockwebhookRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type mockwebhookRepository_GetByID_Call struct {
 	*mock.Call
 }
 
-// GenerateJWT is a helper method to define mock.On call
-//   - user *domain.User
-func (_e *MockTokenManager_Expecter) GenerateJWT(user interface{}) *MockTokenManager_GenerateJWT_Call {
-	return &MockTokenManager_GenerateJWT_Call{Call: _e.mock.On("GenerateJWT", user)}
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *mockwebhookRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockwebhookRepository_GetByID_Call {
+	return &mockwebhookRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
 }
 
-func (_c *MockTokenManager_GenerateJWT_Call) Run(run func(user *domain.User)) *MockTokenManager_GenerateJWT_Call {
+func (_c *mockwebhookRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockwebhookRepository_GetByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *domain.User
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(*domain.User)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockTokenManager_GenerateJWT_Call) Return(s string, err error) *MockTokenManager_GenerateJWT_Call {
-	_c.Call.Return(s, err)
+func (_c *mockwebhookRepository_GetByID_Call) Return(webhook *domain.Webhook, err error) *mockwebhookRepository_GetByID_Call {
+	_c.Call.Return(webhook, err)
 	return _c
 }
 
-func (_c *MockTokenManager_GenerateJWT_Call) RunAndReturn(run func(user *domain.User) (string, error)) *MockTokenManager_GenerateJWT_Call {
+func (_c *mockwebhookRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)) *mockwebhookRepository_GetByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Validate provides a mock function for the type MockTokenManager
-func (_mock *MockTokenManager) Validate(tokenStr string) (*domain.AuthClaims, error) {
-	ret := _mock.Called(tokenStr)
+// List provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) List(ctx context.Context) ([]*domain.Webhook, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Validate")
+		panic("no return value specified for List")
 	}
 
-	var r0 *domain.AuthClaims
+	var r0 []*domain.Webhook
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*domain.AuthClaims, error)); ok {
-		return returnFunc(tokenStr)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*domain.Webhook, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *domain.AuthClaims); ok {
-		r0 = returnFunc(tokenStr)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*domain.Webhook); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.AuthClaims)
+			r0 = ret.Get(0).([]*domain.Webhook)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(tokenStr)
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockTokenManager_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
-type MockTokenManager_Validate_Call struct {
+// mockwebhookRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockwebhookRepository_List_Call struct {
 	*mock.Call
 }
 
-// Validate is a helper method to define mock.On call
-//   - tokenStr string
-func (_e *MockTokenManager_Expecter) Validate(tokenStr interface{}) *MockTokenManager_Validate_Call {
-	return &MockTokenManager_Validate_Call{Call: _e.mock.On("Validate", tokenStr)}
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockwebhookRepository_Expecter) List(ctx interface{}) *mockwebhookRepository_List_Call {
+	return &mockwebhookRepository_List_Call{Call: _e.mock.On("List", ctx)}
 }
 
-func (_c *MockTokenManager_Validate_Call) Run(run func(tokenStr string)) *MockTokenManager_Validate_Call {
+func (_c *mockwebhookRepository_List_Call) Run(run func(ctx context.Context)) *mockwebhookRepository_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(context.Context)
 		}
 		run(
 			arg0,
@@ -489,148 +6424,144 @@ func (_c *MockTokenManager_Validate_Call) Run(run func(tokenStr string)) *MockTo
 	return _c
 }
 
-func (_c *MockTokenManager_Validate_Call) Return(authClaims *domain.AuthClaims, err error) *MockTokenManager_Validate_Call {
-	_c.Call.Return(authClaims, err)
+func (_c *mockwebhookRepository_List_Call) Return(webhooks []*domain.Webhook, err error) *mockwebhookRepository_List_Call {
+	_c.Call.Return(webhooks, err)
 	return _c
 }
 
-func (_c *MockTokenManager_Validate_Call) RunAndReturn(run func(tokenStr string) (*domain.AuthClaims, error)) *MockTokenManager_Validate_Call {
+func (_c *mockwebhookRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]*domain.Webhook, error)) *mockwebhookRepository_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// newMockitemRepository creates a new instance of mockitemRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func newMockitemRepository(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *mockitemRepository {
-	mock := &mockitemRepository{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// mockitemRepository is an autogenerated mock type for the itemRepository type
-type mockitemRepository struct {
-	mock.Mock
-}
-
-type mockitemRepository_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *mockitemRepository) EXPECT() *mockitemRepository_Expecter {
-	return &mockitemRepository_Expecter{mock: &_m.Mock}
-}
-
-// Create provides a mock function for the type mockitemRepository
-func (_mock *mockitemRepository) Create(ctx context.Context, userID uuid.UUID, input *domain.CreateItemInput) (*domain.Item, error) {
-	ret := _mock.Called(ctx, userID, input)
+// ListDeliveries provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) ListDeliveries(ctx context.Context, filter *domain.WebhookDeliveryFilter, limit int, offset int) ([]*domain.WebhookDelivery, int64, error) {
+	ret := _mock.Called(ctx, filter, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for ListDeliveries")
 	}
 
-	var r0 *domain.Item
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.CreateItemInput) (*domain.Item, error)); ok {
-		return returnFunc(ctx, userID, input)
+	var r0 []*domain.WebhookDelivery
+	var r1 int64
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.WebhookDeliveryFilter, int, int) ([]*domain.WebhookDelivery, int64, error)); ok {
+		return returnFunc(ctx, filter, limit, offset)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.CreateItemInput) *domain.Item); ok {
-		r0 = returnFunc(ctx, userID, input)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.WebhookDeliveryFilter, int, int) []*domain.WebhookDelivery); ok {
+		r0 = returnFunc(ctx, filter, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Item)
+			r0 = ret.Get(0).([]*domain.WebhookDelivery)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.CreateItemInput) error); ok {
-		r1 = returnFunc(ctx, userID, input)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.WebhookDeliveryFilter, int, int) int64); ok {
+		r1 = returnFunc(ctx, filter, limit, offset)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.WebhookDeliveryFilter, int, int) error); ok {
+		r2 = returnFunc(ctx, filter, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// mockitemRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
-type mockitemRepository_Create_Call struct {
+// mockwebhookRepository_ListDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeliveries'
+type mockwebhookRepository_ListDeliveries_Call struct {
 	*mock.Call
 }
 
-// Create is a helper method to define mock.On call
+// ListDeliveries is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-//   - input *domain.CreateItemInput
-func (_e *mockitemRepository_Expecter) Create(ctx interface{}, userID interface{}, input interface{}) *mockitemRepository_Create_Call {
-	return &mockitemRepository_Create_Call{Call: _e.mock.On("Create", ctx, userID, input)}
+//   - filter *domain.WebhookDeliveryFilter
+//   - limit int
+//   - offset int
+func (_e *mockwebhookRepository_Expecter) ListDeliveries(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *mockwebhookRepository_ListDeliveries_Call {
+	return &mockwebhookRepository_ListDeliveries_Call{Call: _e.mock.On("ListDeliveries", ctx, filter, limit, offset)}
 }
 
-func (_c *mockitemRepository_Create_Call) Run(run func(ctx context.Context, userID uuid.UUID, input *domain.CreateItemInput)) *mockitemRepository_Create_Call {
+func (_c *mockwebhookRepository_ListDeliveries_Call) Run(run func(ctx context.Context, filter *domain.WebhookDeliveryFilter, limit int, offset int)) *mockwebhookRepository_ListDeliveries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 *domain.WebhookDeliveryFilter
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(*domain.WebhookDeliveryFilter)
 		}
-		var arg2 *domain.CreateItemInput
+		var arg2 int
 		if args[2] != nil {
-			arg2 = args[2].(*domain.CreateItemInput)
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockitemRepository_Create_Call) Return(item *domain.Item, err error) *mockitemRepository_Create_Call {
-	_c.Call.Return(item, err)
+func (_c *mockwebhookRepository_ListDeliveries_Call) Return(webhookDeliverys []*domain.WebhookDelivery, n int64, err error) *mockwebhookRepository_ListDeliveries_Call {
+	_c.Call.Return(webhookDeliverys, n, err)
 	return _c
 }
 
-func (_c *mockitemRepository_Create_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, input *domain.CreateItemInput) (*domain.Item, error)) *mockitemRepository_Create_Call {
+func (_c *mockwebhookRepository_ListDeliveries_Call) RunAndReturn(run func(ctx context.Context, filter *domain.WebhookDeliveryFilter, limit int, offset int) ([]*domain.WebhookDelivery, int64, error)) *mockwebhookRepository_ListDeliveries_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Delete provides a mock function for the type mockitemRepository
-func (_mock *mockitemRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
-	ret := _mock.Called(ctx, userID, id)
+// Redeliver provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) Redeliver(ctx context.Context, webhookID uuid.UUID, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	ret := _mock.Called(ctx, webhookID, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Delete")
+		panic("no return value specified for Redeliver")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
-		r0 = returnFunc(ctx, userID, id)
+	var r0 *domain.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*domain.WebhookDelivery, error)); ok {
+		return returnFunc(ctx, webhookID, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *domain.WebhookDelivery); ok {
+		r0 = returnFunc(ctx, webhookID, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookDelivery)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, webhookID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// mockitemRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
-type mockitemRepository_Delete_Call struct {
+// mockwebhookRepository_Redeliver_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Redeliver'
+type mockwebhookRepository_Redeliver_Call struct {
 	*mock.Call
 }
 
-// Delete is a helper method to define mock.On call
+// Redeliver is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
+//   - webhookID uuid.UUID
 //   - id uuid.UUID
-func (_e *mockitemRepository_Expecter) Delete(ctx interface{}, userID interface{}, id interface{}) *mockitemRepository_Delete_Call {
-	return &mockitemRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, userID, id)}
+func (_e *mockwebhookRepository_Expecter) Redeliver(ctx interface{}, webhookID interface{}, id interface{}) *mockwebhookRepository_Redeliver_Call {
+	return &mockwebhookRepository_Redeliver_Call{Call: _e.mock.On("Redeliver", ctx, webhookID, id)}
 }
 
-func (_c *mockitemRepository_Delete_Call) Run(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID)) *mockitemRepository_Delete_Call {
+func (_c *mockwebhookRepository_Redeliver_Call) Run(run func(ctx context.Context, webhookID uuid.UUID, id uuid.UUID)) *mockwebhookRepository_Redeliver_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -653,57 +6584,49 @@ func (_c *mockitemRepository_Delete_Call) Run(run func(ctx context.Context, user
 	return _c
 }
 
-func (_c *mockitemRepository_Delete_Call) Return(err error) *mockitemRepository_Delete_Call {
-	_c.Call.Return(err)
+func (_c *mockwebhookRepository_Redeliver_Call) Return(webhookDelivery *domain.WebhookDelivery, err error) *mockwebhookRepository_Redeliver_Call {
+	_c.Call.Return(webhookDelivery, err)
 	return _c
 }
 
-func (_c *mockitemRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID) error) *mockitemRepository_Delete_Call {
+func (_c *mockwebhookRepository_Redeliver_Call) RunAndReturn(run func(ctx context.Context, webhookID uuid.UUID, id uuid.UUID) (*domain.WebhookDelivery, error)) *mockwebhookRepository_Redeliver_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByID provides a mock function for the type mockitemRepository
-func (_mock *mockitemRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Item, error) {
-	ret := _mock.Called(ctx, id)
+// SaveAttempt provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) SaveAttempt(ctx context.Context, id uuid.UUID, attempt *domain.WebhookAttempt, status domain.WebhookDeliveryStatus, retryIn time.Duration) error {
+	ret := _mock.Called(ctx, id, attempt, status, retryIn)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByID")
+		panic("no return value specified for SaveAttempt")
 	}
 
-	var r0 *domain.Item
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Item, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Item); ok {
-		r0 = returnFunc(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Item)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, id)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.WebhookAttempt, domain.WebhookDeliveryStatus, time.Duration) error); ok {
+		r0 = returnFunc(ctx, id, attempt, status, retryIn)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// mockitemRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
-type mockitemRepository_GetByID_Call struct {
+// mockwebhookRepository_SaveAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAttempt'
+type mockwebhookRepository_SaveAttempt_Call struct {
 	*mock.Call
 }
 
-// GetByID is a helper method to define mock.On call
+// SaveAttempt is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-func (_e *mockitemRepository_Expecter) GetByID(ctx interface{}, id interface{}) *mockitemRepository_GetByID_Call {
-	return &mockitemRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+//   - attempt *domain.WebhookAttempt
+//   - status domain.WebhookDeliveryStatus
+//   - retryIn time.Duration
+func (_e *mockwebhookRepository_Expecter) SaveAttempt(ctx interface{}, id interface{}, attempt interface{}, status interface{}, retryIn interface{}) *mockwebhookRepository_SaveAttempt_Call {
+	return &mockwebhookRepository_SaveAttempt_Call{Call: _e.mock.On("SaveAttempt", ctx, id, attempt, status, retryIn)}
 }
 
-func (_c *mockitemRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *mockitemRepository_GetByID_Call {
+func (_c *mockwebhookRepository_SaveAttempt_Call) Run(run func(ctx context.Context, id uuid.UUID, attempt *domain.WebhookAttempt, status domain.WebhookDeliveryStatus, retryIn time.Duration)) *mockwebhookRepository_SaveAttempt_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -713,186 +6636,201 @@ func (_c *mockitemRepository_GetByID_Call) Run(run func(ctx context.Context, id
 		if args[1] != nil {
 			arg1 = args[1].(uuid.UUID)
 		}
+		var arg2 *domain.WebhookAttempt
+		if args[2] != nil {
+			arg2 = args[2].(*domain.WebhookAttempt)
+		}
+		var arg3 domain.WebhookDeliveryStatus
+		if args[3] != nil {
+			arg3 = args[3].(domain.WebhookDeliveryStatus)
+		}
+		var arg4 time.Duration
+		if args[4] != nil {
+			arg4 = args[4].(time.Duration)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
+			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *mockitemRepository_GetByID_Call) Return(item *domain.Item, err error) *mockitemRepository_GetByID_Call {
-	_c.Call.Return(item, err)
+func (_c *mockwebhookRepository_SaveAttempt_Call) Return(err error) *mockwebhookRepository_SaveAttempt_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *mockitemRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Item, error)) *mockitemRepository_GetByID_Call {
+func (_c *mockwebhookRepository_SaveAttempt_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, attempt *domain.WebhookAttempt, status domain.WebhookDeliveryStatus, retryIn time.Duration) error) *mockwebhookRepository_SaveAttempt_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function for the type mockitemRepository
-func (_mock *mockitemRepository) List(ctx context.Context, filter *domain.ItemFilter, limit int, offset int) ([]*domain.Item, int64, error) {
-	ret := _mock.Called(ctx, filter, limit, offset)
+// Update provides a mock function for the type mockwebhookRepository
+func (_mock *mockwebhookRepository) Update(ctx context.Context, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error) {
+	ret := _mock.Called(ctx, id, input)
 
 	if len(ret) == 0 {
-		panic("no return value specified for List")
+		panic("no return value specified for Update")
 	}
 
-	var r0 []*domain.Item
-	var r1 int64
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ItemFilter, int, int) ([]*domain.Item, int64, error)); ok {
-		return returnFunc(ctx, filter, limit, offset)
+	var r0 *domain.Webhook
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.UpdateWebhookInput) (*domain.Webhook, error)); ok {
+		return returnFunc(ctx, id, input)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.ItemFilter, int, int) []*domain.Item); ok {
-		r0 = returnFunc(ctx, filter, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.UpdateWebhookInput) *domain.Webhook); ok {
+		r0 = returnFunc(ctx, id, input)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Item)
+			r0 = ret.Get(0).(*domain.Webhook)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.ItemFilter, int, int) int64); ok {
-		r1 = returnFunc(ctx, filter, limit, offset)
-	} else {
-		r1 = ret.Get(1).(int64)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, *domain.ItemFilter, int, int) error); ok {
-		r2 = returnFunc(ctx, filter, limit, offset)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *domain.UpdateWebhookInput) error); ok {
+		r1 = returnFunc(ctx, id, input)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// mockitemRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type mockitemRepository_List_Call struct {
+// mockwebhookRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockwebhookRepository_Update_Call struct {
 	*mock.Call
 }
 
-// List is a helper method to define mock.On call
+// Update is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filter *domain.ItemFilter
-//   - limit int
-//   - offset int
-func (_e *mockitemRepository_Expecter) List(ctx interface{}, filter interface{}, limit interface{}, offset interface{}) *mockitemRepository_List_Call {
-	return &mockitemRepository_List_Call{Call: _e.mock.On("List", ctx, filter, limit, offset)}
+//   - id uuid.UUID
+//   - input *domain.UpdateWebhookInput
+func (_e *mockwebhookRepository_Expecter) Update(ctx interface{}, id interface{}, input interface{}) *mockwebhookRepository_Update_Call {
+	return &mockwebhookRepository_Update_Call{Call: _e.mock.On("Update", ctx, id, input)}
 }
 
-func (_c *mockitemRepository_List_Call) Run(run func(ctx context.Context, filter *domain.ItemFilter, limit int, offset int)) *mockitemRepository_List_Call {
+func (_c *mockwebhookRepository_Update_Call) Run(run func(ctx context.Context, id uuid.UUID, input *domain.UpdateWebhookInput)) *mockwebhookRepository_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *domain.ItemFilter
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(*domain.ItemFilter)
+			arg1 = args[1].(uuid.UUID)
 		}
-		var arg2 int
+		var arg2 *domain.UpdateWebhookInput
 		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
+			arg2 = args[2].(*domain.UpdateWebhookInput)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockitemRepository_List_Call) Return(items []*domain.Item, n int64, err error) *mockitemRepository_List_Call {
-	_c.Call.Return(items, n, err)
+func (_c *mockwebhookRepository_Update_Call) Return(webhook *domain.Webhook, err error) *mockwebhookRepository_Update_Call {
+	_c.Call.Return(webhook, err)
 	return _c
 }
 
-func (_c *mockitemRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter *domain.ItemFilter, limit int, offset int) ([]*domain.Item, int64, error)) *mockitemRepository_List_Call {
+func (_c *mockwebhookRepository_Update_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error)) *mockwebhookRepository_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Update provides a mock function for the type mockitemRepository
-func (_mock *mockitemRepository) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error) {
-	ret := _mock.Called(ctx, userID, id, input)
+// newMockwebhookSender creates a new instance of mockwebhookSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockwebhookSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockwebhookSender {
+	mock := &mockwebhookSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// mockwebhookSender is an autogenerated mock type for the webhookSender type
+type mockwebhookSender struct {
+	mock.Mock
+}
+
+type mockwebhookSender_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockwebhookSender) EXPECT() *mockwebhookSender_Expecter {
+	return &mockwebhookSender_Expecter{mock: &_m.Mock}
+}
+
+// Send provides a mock function for the type mockwebhookSender
+func (_mock *mockwebhookSender) Send(ctx context.Context, hook *domain.Webhook, d *domain.WebhookDelivery) *domain.WebhookAttempt {
+	ret := _mock.Called(ctx, hook, d)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for Send")
 	}
 
-	var r0 *domain.Item
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *domain.UpdateItemInput) (*domain.Item, error)); ok {
-		return returnFunc(ctx, userID, id, input)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *domain.UpdateItemInput) *domain.Item); ok {
-		r0 = returnFunc(ctx, userID, id, input)
+	var r0 *domain.WebhookAttempt
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Webhook, *domain.WebhookDelivery) *domain.WebhookAttempt); ok {
+		r0 = returnFunc(ctx, hook, d)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Item)
+			r0 = ret.Get(0).(*domain.WebhookAttempt)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, *domain.UpdateItemInput) error); ok {
-		r1 = returnFunc(ctx, userID, id, input)
-	} else {
-		r1 = ret.Error(1)
-	}
-	return r0, r1
+	return r0
 }
 
-// mockitemRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
-type mockitemRepository_Update_Call struct {
+// mockwebhookSender_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type mockwebhookSender_Send_Call struct {
 	*mock.Call
 }
 
-// Update is a helper method to define mock.On call
+// Send is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-//   - id uuid.UUID
-//   - input *domain.UpdateItemInput
-func (_e *mockitemRepository_Expecter) Update(ctx interface{}, userID interface{}, id interface{}, input interface{}) *mockitemRepository_Update_Call {
-	return &mockitemRepository_Update_Call{Call: _e.mock.On("Update", ctx, userID, id, input)}
+//   - hook *domain.Webhook
+//   - d *domain.WebhookDelivery
+func (_e *mockwebhookSender_Expecter) Send(ctx interface{}, hook interface{}, d interface{}) *mockwebhookSender_Send_Call {
+	return &mockwebhookSender_Send_Call{Call: _e.mock.On("Send", ctx, hook, d)}
 }
 
-func (_c *mockitemRepository_Update_Call) Run(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput)) *mockitemRepository_Update_Call {
+func (_c *mockwebhookSender_Send_Call) Run(run func(ctx context.Context, hook *domain.Webhook, d *domain.WebhookDelivery)) *mockwebhookSender_Send_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 *domain.Webhook
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(*domain.Webhook)
 		}
-		var arg2 uuid.UUID
+		var arg2 *domain.WebhookDelivery
 		if args[2] != nil {
-			arg2 = args[2].(uuid.UUID)
-		}
-		var arg3 *domain.UpdateItemInput
-		if args[3] != nil {
-			arg3 = args[3].(*domain.UpdateItemInput)
+			arg2 = args[2].(*domain.WebhookDelivery)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *mockitemRepository_Update_Call) Return(item *domain.Item, err error) *mockitemRepository_Update_Call {
-	_c.Call.Return(item, err)
+func (_c *mockwebhookSender_Send_Call) Return(webhookAttempt *domain.WebhookAttempt) *mockwebhookSender_Send_Call {
+	_c.Call.Return(webhookAttempt)
 	return _c
 }
 
-func (_c *mockitemRepository_Update_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, id uuid.UUID, input *domain.UpdateItemInput) (*domain.Item, error)) *mockitemRepository_Update_Call {
+func (_c *mockwebhookSender_Send_Call) RunAndReturn(run func(ctx context.Context, hook *domain.Webhook, d *domain.WebhookDelivery) *domain.WebhookAttempt) *mockwebhookSender_Send_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+// oidcSecretBytes - случайная часть state, nonce и code_verifier PKCE
+const oidcSecretBytes = 32
+
+type oidcProvider interface {
+	AuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*domain.OIDCIdentity, error)
+}
+
+type oidcRepository interface {
+	CreateLoginState(ctx context.Context, st *domain.OIDCLoginState) error
+	ConsumeLoginState(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error)
+	RecordIdentityLogin(ctx context.Context, issuer, subject string) (*domain.User, error)
+	CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.OIDCIdentity) (*domain.User, error)
+	LinkIdentity(ctx context.Context, userID uuid.UUID, identity *domain.OIDCIdentity) error
+}
+
+type oidcUserRepository interface {
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	UpdateRole(ctx context.Context, actorID, id uuid.UUID, role domain.Role) (*domain.User, error)
+}
+
+// sessionStarter открывает сессию опознанного пользователя - AuthService.StartSession
+type sessionStarter interface {
+	StartSession(ctx context.Context, user *domain.User) (*domain.TokenPair, error)
+}
+
+// OIDCOptions - единый вход через провайдера OpenID Connect
+type OIDCOptions struct {
+	Name string // название провайдера на кнопке входа
+	// RoleMapping - роль по группе провайдера; из нескольких групп берётся роль с большими правами
+	RoleMapping map[string]domain.Role
+	// DefaultRole - роль пользователя без сопоставленных групп; пустая - такому пользователю вход запрещён
+	DefaultRole domain.Role
+	// LinkByUsername - при первом входе привязать существующего пользователя с тем же логином.
+	// Включать, только если логины у провайдера нельзя выбрать произвольно.
+	LinkByUsername bool
+	StateTTL       time.Duration // сколько ждать возврата пользователя со страницы провайдера
+}
+
+// OIDCService - вход через провайдера OpenID Connect. При первом входе пользователь
+// создаётся без пароля, роль определяется группами провайдера и обновляется при каждом входе.
+// После входа выдаются собственные токены приложения - как при входе по паролю.
+// Без провайдера (единый вход не настроен) все методы возвращают domain.ErrNotFound.
+type OIDCService struct {
+	provider oidcProvider
+	repo     oidcRepository
+	users    oidcUserRepository
+	sessions sessionStarter
+	opts     OIDCOptions
+	log      logger.Logger
+	now      func() time.Time
+}
+
+func NewOIDCService(
+	provider oidcProvider,
+	repo oidcRepository,
+	users oidcUserRepository,
+	sessions sessionStarter,
+	opts OIDCOptions,
+	log logger.Logger,
+) *OIDCService {
+	if opts.StateTTL <= 0 {
+		opts.StateTTL = 10 * time.Minute
+	}
+
+	return &OIDCService{
+		provider: provider,
+		repo:     repo,
+		users:    users,
+		sessions: sessions,
+		opts:     opts,
+		log:      log.With("component", "OIDCService"),
+		now:      time.Now,
+	}
+}
+
+// Provider - название провайдера для кнопки входа
+func (s *OIDCService) Provider() (string, error) {
+	if s.provider == nil {
+		return "", domain.ErrNotFound
+	}
+	return s.opts.Name, nil
+}
+
+// Start начинает вход: возвращает адрес страницы провайдера и state, с которым пользователь вернётся
+func (s *OIDCService) Start(ctx context.Context) (authURL, state string, err error) {
+	const op = "OIDCService.Start"
+
+	if s.provider == nil {
+		return "", "", domain.ErrNotFound
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		if secrets[i], err = newOIDCSecret(); err != nil {
+			return "", "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err = s.provider.AuthURL(ctx, state, nonce, verifier)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to build identity provider login url",
+			"error", err,
+		)
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.CreateLoginState(ctx, &domain.OIDCLoginState{
+		StateHash:    hashOIDCState(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.now().Add(s.opts.StateTTL),
+	})
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to save login state",
+			"error", err,
+		)
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authURL, state, nil
+}
+
+// Complete завершает вход по code и state, с которыми провайдер вернул пользователя
+func (s *OIDCService) Complete(ctx context.Context, state, code string) (*domain.TokenPair, *domain.User, error) {
+	const op = "OIDCService.Complete"
+
+	if s.provider == nil {
+		return nil, nil, domain.ErrNotFound
+	}
+
+	st, err := s.repo.ConsumeLoginState(ctx, hashOIDCState(state))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, domain.ErrTokenInvalid
+		}
+		s.log.Ctx(ctx).Error("failed to get login state",
+			"error", err,
+		)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !st.ExpiresAt.After(s.now()) {
+		return nil, nil, domain.ErrTokenInvalid
+	}
+
+	identity, err := s.provider.Exchange(ctx, code, st.CodeVerifier, st.Nonce)
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenInvalid) {
+			s.log.Ctx(ctx).Warn("identity provider login rejected",
+				"error", err,
+			)
+		} else {
+			s.log.Ctx(ctx).Error("failed to exchange authorization code",
+				"error", err,
+			)
+		}
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	role, ok := s.mapRole(identity.Groups)
+	if !ok {
+		s.log.Ctx(ctx).Warn("identity provider user has no mapped role",
+			"subject", identity.Subject,
+			"username", identity.Username,
+			"groups", identity.Groups,
+		)
+		return nil, nil, domain.ErrForbidden
+	}
+
+	user, err := s.user(ctx, identity, role)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.Disabled() {
+		return nil, nil, domain.ErrUserDisabled
+	}
+
+	// роль определяет провайдер: изменения групп вступают в силу при следующем входе
+	if user.Role != role {
+		updated, err := s.users.UpdateRole(ctx, user.ID, user.ID, role)
+		if err != nil {
+			s.log.Ctx(ctx).Error("failed to sync user role",
+				"error", err,
+				"user_id", user.ID,
+			)
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s.log.Ctx(ctx).Info("user role synced from identity provider",
+			"user_id", user.ID,
+			"from", user.Role,
+			"to", role,
+		)
+		user = updated
+	}
+
+	pair, err := s.sessions.StartSession(ctx, user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return pair, user, nil
+}
+
+// user - пользователь привязки identity; при первом входе он создаётся или привязывается по логину
+func (s *OIDCService) user(ctx context.Context, identity *domain.OIDCIdentity, role domain.Role) (*domain.User, error) {
+	user, err := s.repo.RecordIdentityLogin(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		s.log.Ctx(ctx).Error("failed to get user by identity",
+			"error", err,
+			"subject", identity.Subject,
+		)
+		return nil, err
+	}
+
+	username := oidcUsername(identity)
+
+	if s.opts.LinkByUsername {
+		user, err = s.users.GetByUsername(ctx, username)
+		switch {
+		case err == nil:
+			if err = s.repo.LinkIdentity(ctx, user.ID, identity); err != nil {
+				s.log.Ctx(ctx).Error("failed to link identity",
+					"error", err,
+					"user_id", user.ID,
+				)
+				return nil, err
+			}
+			s.log.Ctx(ctx).Info("identity linked to existing user",
+				"user_id", user.ID,
+				"subject", identity.Subject,
+			)
+			return user, nil
+		case !errors.Is(err, domain.ErrNotFound):
+			s.log.Ctx(ctx).Error("failed to get user",
+				"error", err,
+				"username", username,
+			)
+			return nil, err
+		}
+	}
+
+	user, err = s.repo.CreateUserWithIdentity(ctx, &domain.User{Username: username, Role: role}, identity)
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			// логин занят пользователем, входящим по паролю, - привязать его может только LinkByUsername
+			s.log.Ctx(ctx).Warn("cannot provision identity provider user: username is taken",
+				"username", username,
+				"subject", identity.Subject,
+			)
+			return nil, err
+		}
+		s.log.Ctx(ctx).Error("failed to provision user",
+			"error", err,
+			"username", username,
+		)
+		return nil, err
+	}
+
+	s.log.Ctx(ctx).Info("user provisioned from identity provider",
+		"user_id", user.ID,
+		"username", user.Username,
+		"role", user.Role,
+	)
+	return user, nil
+}
+
+// mapRole - роль с наибольшими правами среди групп пользователя; без групп из RoleMapping - DefaultRole
+func (s *OIDCService) mapRole(groups []string) (domain.Role, bool) {
+	var role domain.Role
+	for _, g := range groups {
+		if mapped, ok := s.opts.RoleMapping[g]; ok {
+			role = role.Max(mapped)
+		}
+	}
+	if role == "" {
+		role = s.opts.DefaultRole
+	}
+	return role, role.IsValid()
+}
+
+// oidcUsername - логин нового пользователя: логин у провайдера, иначе начало email,
+// иначе производный от subject. Недопустимые в логине символы заменяются на "_".
+func oidcUsername(identity *domain.OIDCIdentity) string {
+	email, _, _ := strings.Cut(identity.Email, "@")
+	for _, candidate := range []string{identity.Username, email} {
+		if u := sanitizeUsername(candidate); len(u) >= usernameMinLen {
+			return u
+		}
+	}
+	sum := sha256.Sum256([]byte(identity.Issuer + "\x00" + identity.Subject))
+	return "sso-" + hex.EncodeToString(sum[:6])
+}
+
+func sanitizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if b.Len() >= usernameMaxLen {
+			break
+		}
+		if r < 0x80 && usernamePattern.MatchString(string(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func newOIDCSecret() (string, error) {
+	b := make([]byte, oidcSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate login secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashOIDCState - в БД хранится SHA-256 от state, как и от refresh-токена
+func hashOIDCState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://idp.example.com"
+
+type oidcMocks struct {
+	provider *mockoidcProvider
+	repo     *mockoidcRepository
+	users    *mockoidcUserRepository
+	sessions *mocksessionStarter
+}
+
+func newOIDCService(t *testing.T, opts OIDCOptions) (*OIDCService, *oidcMocks) {
+	m := &oidcMocks{
+		provider: newMockoidcProvider(t),
+		repo:     newMockoidcRepository(t),
+		users:    newMockoidcUserRepository(t),
+		sessions: newMocksessionStarter(t),
+	}
+	if opts.RoleMapping == nil {
+		opts.RoleMapping = map[string]domain.Role{
+			"wh-admins":   domain.RoleAdmin,
+			"wh-managers": domain.RoleManager,
+		}
+	}
+	return NewOIDCService(m.provider, m.repo, m.users, m.sessions, opts, newTestLogger()), m
+}
+
+// expectLogin - возврат от провайдера со state "state" и пользователем identity
+func (m *oidcMocks) expectLogin(identity *domain.OIDCIdentity) {
+	m.repo.EXPECT().ConsumeLoginState(mock.Anything, hashOIDCState("state")).Return(&domain.OIDCLoginState{
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}, nil)
+	m.provider.EXPECT().Exchange(mock.Anything, "code", "verifier", "nonce").Return(identity, nil)
+}
+
+func testIdentity(groups ...string) *domain.OIDCIdentity {
+	return &domain.OIDCIdentity{Issuer: testIssuer, Subject: "u-1", Username: "alice", Groups: groups}
+}
+
+func TestOIDCService_Start(t *testing.T) {
+	svc, m := newOIDCService(t, OIDCOptions{StateTTL: 5 * time.Minute})
+
+	var nonce, verifier string
+	m.provider.EXPECT().AuthURL(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, n, v string) (string, error) {
+			nonce, verifier = n, v
+			return "https://idp.example.com/authorize?x=1", nil
+		})
+
+	var saved *domain.OIDCLoginState
+	m.repo.EXPECT().CreateLoginState(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, st *domain.OIDCLoginState) error {
+			saved = st
+			return nil
+		})
+
+	authURL, state, err := svc.Start(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/authorize?x=1", authURL)
+
+	// state хранится только хешем; nonce и verifier - разные случайные строки
+	assert.Equal(t, hashOIDCState(state), saved.StateHash)
+	assert.NotEqual(t, state, saved.StateHash)
+	assert.Equal(t, nonce, saved.Nonce)
+	assert.Equal(t, verifier, saved.CodeVerifier)
+	assert.NotEqual(t, nonce, verifier)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), saved.ExpiresAt, time.Second)
+}
+
+func TestOIDCService_Complete_Provision(t *testing.T) {
+	svc, m := newOIDCService(t, OIDCOptions{})
+	m.expectLogin(testIdentity("staff", "wh-managers", "wh-admins"))
+
+	m.repo.EXPECT().RecordIdentityLogin(mock.Anything, testIssuer, "u-1").Return(nil, domain.ErrNotFound)
+
+	created := &domain.User{ID: uuid.New(), Username: "alice", Role: domain.RoleAdmin}
+	m.repo.EXPECT().CreateUserWithIdentity(mock.Anything,
+		&domain.User{Username: "alice", Role: domain.RoleAdmin}, testIdentity("staff", "wh-managers", "wh-admins"),
+	).Return(created, nil)
+
+	pair := &domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+	m.sessions.EXPECT().StartSession(mock.Anything, created).Return(pair, nil)
+
+	gotPair, user, err := svc.Complete(context.Background(), "state", "code")
+	require.NoError(t, err)
+	assert.Equal(t, pair, gotPair)
+	assert.Equal(t, created, user)
+}
+
+func TestOIDCService_Complete_SyncRole(t *testing.T) {
+	svc, m := newOIDCService(t, OIDCOptions{})
+	m.expectLogin(testIdentity("wh-managers"))
+
+	existing := &domain.User{ID: uuid.New(), Username: "alice", Role: domain.RoleAdmin}
+	m.repo.EXPECT().RecordIdentityLogin(mock.Anything, testIssuer, "u-1").Return(existing, nil)
+
+	// группу администраторов у провайдера отобрали - роль понижается при входе, от имени самого пользователя
+	updated := &domain.User{ID: existing.ID, Username: "alice", Role: domain.RoleManager}
+	m.users.EXPECT().UpdateRole(mock.Anything, existing.ID, existing.ID, domain.RoleManager).Return(updated, nil)
+	m.sessions.EXPECT().StartSession(mock.Anything, updated).Return(&domain.TokenPair{}, nil)
+
+	_, user, err := svc.Complete(context.Background(), "state", "code")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleManager, user.Role)
+}
+
+func TestOIDCService_Complete_LinkByUsername(t *testing.T) {
+	svc, m := newOIDCService(t, OIDCOptions{LinkByUsername: true})
+	identity := testIdentity("wh-admins")
+	m.expectLogin(identity)
+
+	m.repo.EXPECT().RecordIdentityLogin(mock.Anything, testIssuer, "u-1").Return(nil, domain.ErrNotFound)
+	existing := &domain.User{ID: uuid.New(), Username: "alice", Role: domain.RoleAdmin, PasswordHash: "$2a$10$hash"}
+	m.users.EXPECT().GetByUsername(mock.Anything, "alice").Return(existing, nil)
+	m.repo.EXPECT().LinkIdentity(mock.Anything, existing.ID, identity).Return(nil)
+	m.sessions.EXPECT().StartSession(mock.Anything, existing).Return(&domain.TokenPair{}, nil)
+
+	_, user, err := svc.Complete(context.Background(), "state", "code")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, user.ID)
+}
+
+func TestOIDCService_Complete_UsernameTaken(t *testing.T) {
+	svc, m := newOIDCService(t, OIDCOptions{})
+	m.expectLogin(testIdentity("wh-admins"))
+
+	m.repo.EXPECT().RecordIdentityLogin(mock.Anything, testIssuer, "u-1").Return(nil, domain.ErrNotFound)
+	m.repo.EXPECT().CreateUserWithIdentity(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, domain.ErrAlreadyExists)
+
+	_, _, err := svc.Complete(context.Background(), "state", "code")
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+}
+
+func TestOIDCService_Complete_Rejected(t *testing.T) {
+	disabledAt := time.Now()
+
+	t.Run("unknown state", func(t *testing.T) {
+		svc, m := newOIDCService(t, OIDCOptions{})
+		m.repo.EXPECT().ConsumeLoginState(mock.Anything, hashOIDCState("state")).Return(nil, domain.ErrNotFound)
+
+		_, _, err := svc.Complete(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("expired state", func(t *testing.T) {
+		svc, m := newOIDCService(t, OIDCOptions{})
+		m.repo.EXPECT().ConsumeLoginState(mock.Anything, hashOIDCState("state")).Return(&domain.OIDCLoginState{
+			ExpiresAt: time.Now().Add(-time.Second),
+		}, nil)
+
+		_, _, err := svc.Complete(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("id token rejected", func(t *testing.T) {
+		svc, m := newOIDCService(t, OIDCOptions{})
+		m.repo.EXPECT().ConsumeLoginState(mock.Anything, hashOIDCState("state")).Return(&domain.OIDCLoginState{
+			Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(time.Minute),
+		}, nil)
+		m.provider.EXPECT().Exchange(mock.Anything, "code", "verifier", "nonce").Return(nil, domain.ErrTokenInvalid)
+
+		_, _, err := svc.Complete(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	})
+
+	t.Run("no mapped group", func(t *testing.T) {
+		svc, m := newOIDCService(t, OIDCOptions{})
+		m.expectLogin(testIdentity("staff"))
+
+		_, _, err := svc.Complete(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("disabled user", func(t *testing.T) {
+		svc, m := newOIDCService(t, OIDCOptions{})
+		m.expectLogin(testIdentity("wh-admins"))
+		m.repo.EXPECT().RecordIdentityLogin(mock.Anything, testIssuer, "u-1").Return(&domain.User{
+			ID: uuid.New(), Username: "alice", Role: domain.RoleAdmin, DisabledAt: &disabledAt,
+		}, nil)
+
+		_, _, err := svc.Complete(context.Background(), "state", "code")
+		assert.ErrorIs(t, err, domain.ErrUserDisabled)
+	})
+}
+
+func TestOIDCService_Complete_DefaultRole(t *testing.T) {
+	svc, m := newOIDCService(t, OIDCOptions{DefaultRole: domain.RoleViewer})
+	m.expectLogin(testIdentity("staff"))
+
+	m.repo.EXPECT().RecordIdentityLogin(mock.Anything, testIssuer, "u-1").Return(nil, domain.ErrNotFound)
+	created := &domain.User{ID: uuid.New(), Username: "alice", Role: domain.RoleViewer}
+	m.repo.EXPECT().CreateUserWithIdentity(mock.Anything,
+		&domain.User{Username: "alice", Role: domain.RoleViewer}, mock.Anything,
+	).Return(created, nil)
+	m.sessions.EXPECT().StartSession(mock.Anything, created).Return(&domain.TokenPair{}, nil)
+
+	_, _, err := svc.Complete(context.Background(), "state", "code")
+	require.NoError(t, err)
+}
+
+func TestOIDCService_Disabled(t *testing.T) {
+	svc := NewOIDCService(nil, nil, nil, nil, OIDCOptions{}, newTestLogger())
+
+	_, err := svc.Provider()
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, _, err = svc.Start(context.Background())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, _, err = svc.Complete(context.Background(), "state", "code")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestOIDCUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *domain.OIDCIdentity
+		want     string
+	}{
+		{"username claim", &domain.OIDCIdentity{Username: "alice", Email: "a@example.com"}, "alice"},
+		{"invalid characters replaced", &domain.OIDCIdentity{Username: "Иван Petrov"}, "_____Petrov"},
+		{"email local part", &domain.OIDCIdentity{Email: "bob.smith@example.com"}, "bob.smith"},
+		{"too short", &domain.OIDCIdentity{Username: "ab", Email: "cd@example.com", Issuer: testIssuer, Subject: "u-1"},
+			oidcUsername(&domain.OIDCIdentity{Issuer: testIssuer, Subject: "u-1"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, oidcUsername(tt.identity))
+		})
+	}
+
+	derived := oidcUsername(&domain.OIDCIdentity{Issuer: testIssuer, Subject: "u-1"})
+	assert.Regexp(t, `^sso-[0-9a-f]{12}$`, derived)
+	assert.True(t, usernamePattern.MatchString(derived))
+}
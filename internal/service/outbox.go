@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+	"github.com/wb-go/wbf/retry"
+)
+
+type outboxRepository interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, id int64, delay time.Duration, reason string) error
+	Release(ctx context.Context, ids []int64) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventPublisher доставляет события outbox во внешнюю систему (лог, файл, HTTP, Kafka).
+// nil - событие принято получателем; при ошибке оно будет отправлено повторно.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// OutboxOptions - релей outbox
+type OutboxOptions struct {
+	BatchSize       int            // сколько событий забирать за раз
+	PollInterval    time.Duration  // как часто проверять очередь
+	Lease           time.Duration  // сколько выданная пачка закреплена за релеем
+	MaxBackoff      time.Duration  // предел откладывания события после неудачных отправок
+	Retention       time.Duration  // сколько хранить доставленные события
+	CleanupInterval time.Duration  // как часто удалять доставленные события
+	Retry           retry.Strategy // повторы отправки одного события, прежде чем отложить его
+}
+
+// OutboxRelay доставляет события из outbox получателю EventPublisher не менее одного раза.
+// События одного агрегата уходят по порядку: пока предыдущее не доставлено, следующие ждут,
+// а события других агрегатов продолжают уходить.
+type OutboxRelay struct {
+	repo      outboxRepository
+	publisher EventPublisher
+	opts      OutboxOptions
+	log       logger.Logger
+	now       func() time.Time
+}
+
+func NewOutboxRelay(repo outboxRepository, publisher EventPublisher, opts OutboxOptions, log logger.Logger) *OutboxRelay {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = time.Minute
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 10 * time.Minute
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 7 * 24 * time.Hour
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Hour
+	}
+	if opts.Retry.Attempts <= 0 {
+		opts.Retry.Attempts = 1
+	}
+
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		opts:      opts,
+		log:       log.With("component", "OutboxRelay"),
+		now:       time.Now,
+	}
+}
+
+// Run доставляет события и удаляет старые доставленные; блокируется до отмены ctx
+func (s *OutboxRelay) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.cleanupLoop(ctx)
+	}()
+
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// полная пачка - в очереди, скорее всего, есть ещё
+		for ctx.Err() == nil {
+			n, err := s.relay(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.log.Ctx(ctx).Error("failed to claim outbox events",
+						"error", err,
+					)
+				}
+				break
+			}
+			if n < s.opts.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay отправляет одну пачку и возвращает её размер
+func (s *OutboxRelay) relay(ctx context.Context) (int, error) {
+	events, err := s.repo.Claim(ctx, s.opts.BatchSize, s.opts.Lease)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	var (
+		published []int64
+		deferred  []int64
+		blocked   = make(map[uuid.UUID]bool)
+	)
+	for _, ev := range events {
+		// за неотправленным событием агрегата следующие не идут, иначе получатель увидит их не по порядку
+		if blocked[ev.AggregateID] || ctx.Err() != nil {
+			deferred = append(deferred, ev.ID)
+			continue
+		}
+
+		err = retry.DoContext(ctx, s.opts.Retry, func() error {
+			return s.publisher.Publish(ctx, ev)
+		})
+		if err == nil {
+			published = append(published, ev.ID)
+			continue
+		}
+
+		blocked[ev.AggregateID] = true
+		if ctx.Err() != nil {
+			deferred = append(deferred, ev.ID)
+			continue
+		}
+		s.fail(ctx, ev, err)
+	}
+
+	// отметки переживают остановку: доставленное не должно уйти повторно без нужды
+	markCtx := context.WithoutCancel(ctx)
+	if len(published) > 0 {
+		if err = s.repo.MarkPublished(markCtx, published); err != nil {
+			// события уйдут ещё раз после истечения Lease - получатель отбросит повтор по event_id
+			s.log.Ctx(ctx).Error("failed to mark outbox events published",
+				"error", err,
+				"count", len(published),
+			)
+		}
+	}
+	if len(deferred) > 0 {
+		if err = s.repo.Release(markCtx, deferred); err != nil {
+			s.log.Ctx(ctx).Error("failed to release outbox events",
+				"error", err,
+				"count", len(deferred),
+			)
+		}
+	}
+
+	return len(events), nil
+}
+
+func (s *OutboxRelay) fail(ctx context.Context, ev *domain.OutboxEvent, cause error) {
+	delay := s.backoff(ev.Attempts + 1)
+	s.log.Ctx(ctx).Warn("failed to publish outbox event",
+		"error", cause,
+		"event_id", ev.EventID,
+		"event_type", ev.Type,
+		"aggregate_id", ev.AggregateID,
+		"attempts", ev.Attempts+1,
+		"retry_in", delay,
+	)
+
+	if err := s.repo.MarkFailed(ctx, ev.ID, delay, cause.Error()); err != nil {
+		s.log.Ctx(ctx).Error("failed to reschedule outbox event",
+			"error", err,
+			"event_id", ev.EventID,
+		)
+	}
+}
+
+// backoff - PollInterval, удваивающийся с каждой неудачей, не больше MaxBackoff
+func (s *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := s.opts.PollInterval
+	for i := 1; i < attempts && delay < s.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.opts.MaxBackoff)
+}
+
+func (s *OutboxRelay) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OutboxRelay) cleanup(ctx context.Context) {
+	n, err := s.repo.DeletePublished(ctx, s.now().Add(-s.opts.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Ctx(ctx).Error("failed to delete published outbox events",
+				"error", err,
+			)
+		}
+		return
+	}
+	if n > 0 {
+		s.log.Ctx(ctx).Info("published outbox events deleted",
+			"count", n,
+		)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/publisher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/retry"
+)
+
+func newOutboxRelay(t *testing.T, opts OutboxOptions) (*OutboxRelay, *mockoutboxRepository, *publisher.Memory) {
+	repo := newMockoutboxRepository(t)
+	pub := publisher.NewMemory()
+	return NewOutboxRelay(repo, pub, opts, newTestLogger()), repo, pub
+}
+
+func outboxEvent(id int64, aggregateID uuid.UUID) *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:            id,
+		EventID:       uuid.New(),
+		AggregateType: domain.OutboxAggregateItem,
+		AggregateID:   aggregateID,
+		Type:          domain.OutboxItemUpdated,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func publishedIDs(events []*domain.OutboxEvent) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestOutboxRelay_Relay_PublishesInOrder(t *testing.T) {
+	relay, repo, pub := newOutboxRelay(t, OutboxOptions{BatchSize: 10})
+	item := uuid.New()
+
+	repo.EXPECT().Claim(mock.Anything, 10, time.Minute).Return([]*domain.OutboxEvent{
+		outboxEvent(1, item),
+		outboxEvent(2, uuid.New()),
+		outboxEvent(3, item),
+	}, nil)
+	repo.EXPECT().MarkPublished(mock.Anything, []int64{1, 2, 3}).Return(nil)
+
+	n, err := relay.relay(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []int64{1, 2, 3}, publishedIDs(pub.Events()))
+}
+
+func TestOutboxRelay_Relay_Empty(t *testing.T) {
+	relay, repo, pub := newOutboxRelay(t, OutboxOptions{})
+	repo.EXPECT().Claim(mock.Anything, 100, time.Minute).Return(nil, nil)
+
+	n, err := relay.relay(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, pub.Events())
+}
+
+func TestOutboxRelay_Relay_FailureBlocksAggregate(t *testing.T) {
+	relay, repo, pub := newOutboxRelay(t, OutboxOptions{PollInterval: time.Second})
+	failing, other := uuid.New(), uuid.New()
+
+	first := outboxEvent(1, failing)
+	first.Attempts = 2
+	repo.EXPECT().Claim(mock.Anything, mock.Anything, mock.Anything).Return([]*domain.OutboxEvent{
+		first,
+		outboxEvent(2, other),
+		outboxEvent(3, failing),
+	}, nil)
+	pub.FailNext(errors.New("receiver is down"))
+
+	// третья неудача подряд - откладывается на PollInterval * 2^2
+	repo.EXPECT().MarkFailed(mock.Anything, int64(1), 4*time.Second, "receiver is down").Return(nil)
+	repo.EXPECT().MarkPublished(mock.Anything, []int64{2}).Return(nil)
+	// событие 3 не должно обогнать событие 1 того же товара
+	repo.EXPECT().Release(mock.Anything, []int64{3}).Return(nil)
+
+	_, err := relay.relay(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, publishedIDs(pub.Events()))
+}
+
+func TestOutboxRelay_Relay_RetryStrategy(t *testing.T) {
+	relay, repo, pub := newOutboxRelay(t, OutboxOptions{
+		Retry: retry.Strategy{Attempts: 3, Delay: time.Millisecond, Backoff: 1},
+	})
+
+	repo.EXPECT().Claim(mock.Anything, mock.Anything, mock.Anything).
+		Return([]*domain.OutboxEvent{outboxEvent(7, uuid.New())}, nil)
+	pub.FailNext(errors.New("timeout"), errors.New("timeout"))
+	repo.EXPECT().MarkPublished(mock.Anything, []int64{7}).Return(nil)
+
+	_, err := relay.relay(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, pub.Events(), 1, "third attempt must succeed")
+}
+
+func TestOutboxRelay_Relay_ClaimError(t *testing.T) {
+	relay, repo, _ := newOutboxRelay(t, OutboxOptions{})
+	repo.EXPECT().Claim(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := relay.relay(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestOutboxRelay_Relay_Canceled(t *testing.T) {
+	relay, repo, pub := newOutboxRelay(t, OutboxOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repo.EXPECT().Claim(mock.Anything, mock.Anything, mock.Anything).
+		Return([]*domain.OutboxEvent{outboxEvent(1, uuid.New()), outboxEvent(2, uuid.New())}, nil)
+	// при остановке невыданное возвращается в очередь сразу, не дожидаясь Lease
+	repo.EXPECT().Release(mock.Anything, []int64{1, 2}).Return(nil)
+
+	_, err := relay.relay(ctx)
+
+	require.NoError(t, err)
+	assert.Empty(t, pub.Events())
+}
+
+func TestOutboxRelay_Backoff(t *testing.T) {
+	relay, _, _ := newOutboxRelay(t, OutboxOptions{PollInterval: time.Second, MaxBackoff: 10 * time.Second})
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 2*time.Second, relay.backoff(2))
+	assert.Equal(t, 8*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(5))
+	assert.Equal(t, 10*time.Second, relay.backoff(1000))
+}
+
+func TestOutboxRelay_Cleanup(t *testing.T) {
+	relay, repo, _ := newOutboxRelay(t, OutboxOptions{Retention: 24 * time.Hour})
+	now := time.Date(2026, 3, 26, 12, 0, 0, 0, time.UTC)
+	relay.now = func() time.Time { return now }
+
+	repo.EXPECT().DeletePublished(mock.Anything, now.Add(-24*time.Hour)).Return(5, nil)
+
+	relay.cleanup(context.Background())
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	relay, repo, pub := newOutboxRelay(t, OutboxOptions{BatchSize: 2, PollInterval: time.Hour})
+
+	repo.EXPECT().DeletePublished(mock.Anything, mock.Anything).Return(0, nil)
+	// полная пачка - сразу забирается следующая
+	repo.EXPECT().Claim(mock.Anything, 2, time.Minute).
+		Return([]*domain.OutboxEvent{outboxEvent(1, uuid.New()), outboxEvent(2, uuid.New())}, nil).Once()
+	repo.EXPECT().Claim(mock.Anything, 2, time.Minute).
+		Return([]*domain.OutboxEvent{outboxEvent(3, uuid.New())}, nil).Once()
+	repo.EXPECT().MarkPublished(mock.Anything, mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- relay.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(pub.Events()) == 3 }, time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
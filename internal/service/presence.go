@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+)
+
+type eventFeed interface {
+	Subscribe(ctx context.Context, claims *domain.AuthClaims, lastEventID int64) (*domain.EventSubscription, error)
+}
+
+// PresenceOptions - канал присутствия
+type PresenceOptions struct {
+	Buffer   int // очередь сообщений сессии; переполнилась - сессия закрывается
+	MaxItems int // на сколько товаров может подписаться одна сессия
+}
+
+// PresenceService хранит, у кого какой товар открыт на просмотр или редактирование, и раздаёт это
+// подписанным сессиям вместе с изменениями самих товаров из ленты EventService.
+// Состояние живёт в памяти процесса: сессии разных экземпляров приложения друг друга не видят.
+type PresenceService struct {
+	feed eventFeed
+	opts PresenceOptions
+	log  logger.Logger
+	now  func() time.Time
+
+	mu       sync.Mutex
+	items    map[uuid.UUID]map[*presenceSession]struct{}
+	sessions map[*presenceSession]struct{}
+	closed   bool
+}
+
+func NewPresenceService(feed eventFeed, opts PresenceOptions, log logger.Logger) *PresenceService {
+	if opts.Buffer <= 0 {
+		opts.Buffer = 64
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = 50
+	}
+
+	return &PresenceService{
+		feed:     feed,
+		opts:     opts,
+		log:      log.With("component", "PresenceService"),
+		now:      time.Now,
+		items:    make(map[uuid.UUID]map[*presenceSession]struct{}),
+		sessions: make(map[*presenceSession]struct{}),
+	}
+}
+
+// presenceItem - товар, открытый в сессии
+type presenceItem struct {
+	editing bool
+	since   time.Time
+}
+
+// presenceSession - одно соединение. Поля items, closed и канал out защищены PresenceService.mu
+type presenceSession struct {
+	svc    *PresenceService
+	claims *domain.AuthClaims
+	out    chan *domain.PresenceMessage
+	cancel context.CancelFunc
+
+	items  map[uuid.UUID]*presenceItem
+	closed bool
+}
+
+// Join открывает сессию присутствия. Изменения товаров приходят из ленты с фильтрацией по роли claims.
+func (s *PresenceService) Join(ctx context.Context, claims *domain.AuthClaims) (domain.PresenceSession, error) {
+	if !claims.Role.CanView() {
+		return nil, domain.ErrForbidden
+	}
+
+	sub, err := s.feed.Subscribe(ctx, claims, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// сессия переживает контекст запроса на подключение
+	sessCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	sess := &presenceSession{
+		svc:    s,
+		claims: claims,
+		out:    make(chan *domain.PresenceMessage, s.opts.Buffer),
+		cancel: cancel,
+		items:  make(map[uuid.UUID]*presenceItem),
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		cancel()
+		sub.Close()
+		return nil, domain.ErrEventsUnavailable
+	}
+	s.sessions[sess] = struct{}{}
+	s.mu.Unlock()
+
+	go sess.pump(sessCtx, sub)
+	return sess, nil
+}
+
+// Close завершает все сессии; вызывается при остановке HTTP-сервера
+func (s *PresenceService) Close() {
+	s.mu.Lock()
+	s.closed = true
+	sessions := make([]*presenceSession, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+
+	for _, sess := range sessions {
+		sess.Close()
+	}
+}
+
+// pump пересылает в сессию изменения товаров, на которые она подписана.
+// Если лента закрыла подписку (сессия не успевала читать), подписка продолжается с последнего события.
+func (sess *presenceSession) pump(ctx context.Context, sub *domain.EventSubscription) {
+	var lastID int64
+	for {
+		for _, ev := range sub.Replay {
+			sess.change(ev)
+			lastID = ev.Entry.ID
+		}
+		if !sess.drain(ctx, sub, &lastID) {
+			return
+		}
+
+		var err error
+		sub, err = sess.svc.feed.Subscribe(ctx, sess.claims, lastID)
+		if err != nil {
+			if ctx.Err() == nil {
+				sess.svc.log.Ctx(ctx).Warn("presence session lost event feed",
+					"error", err,
+					"user_id", sess.claims.UserID,
+				)
+			}
+			// клиент переподключится и заново откроет товары
+			sess.Close()
+			return
+		}
+	}
+}
+
+// drain читает подписку до её закрытия; false - сессия завершена
+func (sess *presenceSession) drain(ctx context.Context, sub *domain.EventSubscription, lastID *int64) bool {
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case ev, ok := <-sub.Events:
+			if !ok {
+				return ctx.Err() == nil
+			}
+			sess.change(ev)
+			*lastID = ev.Entry.ID
+		}
+	}
+}
+
+func (sess *presenceSession) change(ev *domain.ChangeEvent) {
+	if ev.Entry.EntityType != domain.AuditEntityItem {
+		return
+	}
+
+	s := sess.svc
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := sess.items[ev.Entry.EntityID]; ok {
+		if !s.send(sess, &domain.PresenceMessage{Change: ev}) {
+			s.drop(sess)
+		}
+	}
+}
+
+func (sess *presenceSession) Messages() <-chan *domain.PresenceMessage {
+	return sess.out
+}
+
+func (sess *presenceSession) Subscribe(itemID uuid.UUID) error {
+	s := sess.svc
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added, err := s.subscribe(sess, itemID)
+	if added {
+		s.broadcast(itemID)
+	}
+	return err
+}
+
+func (sess *presenceSession) Unsubscribe(itemID uuid.UUID) {
+	s := sess.svc
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := sess.items[itemID]; !ok {
+		return
+	}
+	s.leave(sess, itemID)
+	s.broadcast(itemID)
+}
+
+func (sess *presenceSession) SetEditing(itemID uuid.UUID, editing bool) error {
+	if editing && !sess.claims.Role.CanUpdate() {
+		return domain.ErrForbidden
+	}
+
+	s := sess.svc
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added, err := s.subscribe(sess, itemID)
+	if err != nil {
+		return err
+	}
+	it := sess.items[itemID]
+	changed := it.editing != editing
+	it.editing = editing
+	if added || changed {
+		s.broadcast(itemID)
+	}
+	return nil
+}
+
+// Close выходит из всех товаров и закрывает Messages; повторный вызов ничего не делает
+func (sess *presenceSession) Close() {
+	s := sess.svc
+	s.mu.Lock()
+	if sess.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closeSession(sess)
+	s.mu.Unlock()
+
+	sess.cancel()
+}
+
+// subscribe добавляет товар в сессию без рассылки; true - товара в сессии ещё не было. Вызывается под s.mu
+func (s *PresenceService) subscribe(sess *presenceSession, itemID uuid.UUID) (bool, error) {
+	if sess.closed {
+		return false, domain.ErrEventsUnavailable
+	}
+	if _, ok := sess.items[itemID]; ok {
+		return false, nil
+	}
+	if len(sess.items) >= s.opts.MaxItems {
+		return false, &domain.ValidationError{Field: "item_id", Reason: "too many subscribed items"}
+	}
+
+	sess.items[itemID] = &presenceItem{since: s.now()}
+	subs := s.items[itemID]
+	if subs == nil {
+		subs = make(map[*presenceSession]struct{})
+		s.items[itemID] = subs
+	}
+	subs[sess] = struct{}{}
+	return true, nil
+}
+
+// leave убирает товар из сессии без рассылки; вызывается под s.mu
+func (s *PresenceService) leave(sess *presenceSession, itemID uuid.UUID) {
+	delete(sess.items, itemID)
+	if subs := s.items[itemID]; subs != nil {
+		delete(subs, sess)
+		if len(subs) == 0 {
+			delete(s.items, itemID)
+		}
+	}
+}
+
+// closeSession вызывается под s.mu
+func (s *PresenceService) closeSession(sess *presenceSession) {
+	if sess.closed {
+		return
+	}
+	sess.closed = true
+	delete(s.sessions, sess)
+	close(sess.out)
+
+	left := make([]uuid.UUID, 0, len(sess.items))
+	for itemID := range sess.items {
+		left = append(left, itemID)
+	}
+	for _, itemID := range left {
+		s.leave(sess, itemID)
+		s.broadcast(itemID)
+	}
+}
+
+// send кладёт сообщение в очередь сессии; false - очередь переполнена. Вызывается под s.mu
+func (s *PresenceService) send(sess *presenceSession, msg *domain.PresenceMessage) bool {
+	if sess.closed {
+		return true
+	}
+	select {
+	case sess.out <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// drop отключает медленного клиента - он переподключится и заново откроет товары. Вызывается под s.mu
+func (s *PresenceService) drop(sess *presenceSession) {
+	s.closeSession(sess)
+	sess.cancel()
+}
+
+// broadcast рассылает состав открывших товар всем подписанным на него сессиям; вызывается под s.mu
+func (s *PresenceService) broadcast(itemID uuid.UUID) {
+	subs := s.items[itemID]
+	if len(subs) == 0 {
+		return
+	}
+
+	update := &domain.PresenceUpdate{ItemID: itemID, Users: s.presenceUsers(subs, itemID)}
+	var slow []*presenceSession
+	for sess := range subs {
+		if !s.send(sess, &domain.PresenceMessage{Presence: update}) {
+			slow = append(slow, sess)
+		}
+	}
+	// отключение рассылает новый состав, поэтому оно идёт после текущей рассылки, чтобы не обогнать её
+	for _, sess := range slow {
+		s.drop(sess)
+	}
+}
+
+// presenceUsers - по одной записи на пользователя, сколько бы вкладок у него ни было открыто
+func (s *PresenceService) presenceUsers(subs map[*presenceSession]struct{}, itemID uuid.UUID) []domain.PresenceUser {
+	byUser := make(map[uuid.UUID]*domain.PresenceUser, len(subs))
+	for sess := range subs {
+		it := sess.items[itemID]
+		u := byUser[sess.claims.UserID]
+		if u == nil {
+			u = &domain.PresenceUser{
+				UserID:   sess.claims.UserID,
+				Username: sess.claims.Username,
+				Role:     sess.claims.Role,
+				Since:    it.since,
+			}
+			byUser[sess.claims.UserID] = u
+		}
+		u.Editing = u.Editing || it.editing
+		if it.since.Before(u.Since) {
+			u.Since = it.since
+		}
+	}
+
+	users := make([]domain.PresenceUser, 0, len(byUser))
+	for _, u := range byUser {
+		users = append(users, *u)
+	}
+	slices.SortFunc(users, func(a, b domain.PresenceUser) int {
+		if c := a.Since.Compare(b.Since); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return users
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPresenceService(t *testing.T, opts PresenceOptions) (*PresenceService, *mockeventFeed) {
+	feed := newMockeventFeed(t)
+	svc := NewPresenceService(feed, opts, newTestLogger())
+
+	// каждое обращение к часам на секунду позже предыдущего - порядок участников предсказуем
+	clock := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return svc, feed
+}
+
+// joinPresence открывает сессию; события ленты для неё отправляются в возвращаемый канал
+func joinPresence(
+	t *testing.T,
+	svc *PresenceService,
+	feed *mockeventFeed,
+	claims *domain.AuthClaims,
+) (domain.PresenceSession, chan *domain.ChangeEvent) {
+	events := make(chan *domain.ChangeEvent, 8)
+	feed.EXPECT().Subscribe(mock.Anything, claims, int64(0)).
+		Return(domain.NewEventSubscription(events, func() {}), nil).Once()
+
+	sess, err := svc.Join(context.Background(), claims)
+	require.NoError(t, err)
+	t.Cleanup(sess.Close)
+	return sess, events
+}
+
+func nextPresenceMessage(t *testing.T, sess domain.PresenceSession) *domain.PresenceMessage {
+	t.Helper()
+	select {
+	case msg, ok := <-sess.Messages():
+		require.True(t, ok, "session must be open")
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered")
+		return nil
+	}
+}
+
+func itemChangeEvent(id int64, itemID uuid.UUID) *domain.ChangeEvent {
+	e := auditEvent(id, domain.AuditEntityItem)
+	e.EntityID = itemID
+	return &domain.ChangeEvent{Entry: e}
+}
+
+func TestPresenceService_Join_FeedUnavailable(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	feed.EXPECT().Subscribe(mock.Anything, viewerClaims, int64(0)).Return(nil, domain.ErrEventsUnavailable)
+
+	_, err := svc.Join(context.Background(), viewerClaims)
+
+	assert.ErrorIs(t, err, domain.ErrEventsUnavailable)
+}
+
+func TestPresenceService_SubscribeAndEditing(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	itemID := uuid.New()
+
+	manager, _ := joinPresence(t, svc, feed, managerClaims)
+	viewer, _ := joinPresence(t, svc, feed, viewerClaims)
+
+	require.NoError(t, manager.SetEditing(itemID, true))
+	msg := nextPresenceMessage(t, manager)
+	require.NotNil(t, msg.Presence)
+	assert.Equal(t, itemID, msg.Presence.ItemID)
+	require.Len(t, msg.Presence.Users, 1)
+	assert.True(t, msg.Presence.Users[0].Editing)
+
+	require.NoError(t, viewer.Subscribe(itemID))
+	for _, sess := range []domain.PresenceSession{manager, viewer} {
+		msg = nextPresenceMessage(t, sess)
+		require.Len(t, msg.Presence.Users, 2)
+		assert.Equal(t, "manager", msg.Presence.Users[0].Username)
+		assert.True(t, msg.Presence.Users[0].Editing)
+		assert.Equal(t, "viewer", msg.Presence.Users[1].Username)
+		assert.False(t, msg.Presence.Users[1].Editing)
+	}
+
+	// повторная подписка ничего не рассылает
+	require.NoError(t, viewer.Subscribe(itemID))
+	assert.Empty(t, viewer.Messages())
+
+	viewer.Unsubscribe(itemID)
+	msg = nextPresenceMessage(t, manager)
+	require.Len(t, msg.Presence.Users, 1)
+	assert.Equal(t, "manager", msg.Presence.Users[0].Username)
+}
+
+func TestPresenceService_SetEditing_Forbidden(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	viewer, _ := joinPresence(t, svc, feed, viewerClaims)
+
+	err := viewer.SetEditing(uuid.New(), true)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Empty(t, viewer.Messages())
+}
+
+func TestPresenceService_SameUserAggregated(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	itemID := uuid.New()
+
+	// две вкладки одного пользователя: одна просматривает, другая редактирует
+	first, _ := joinPresence(t, svc, feed, adminClaims)
+	second, _ := joinPresence(t, svc, feed, adminClaims)
+
+	require.NoError(t, first.Subscribe(itemID))
+	nextPresenceMessage(t, first)
+	require.NoError(t, second.SetEditing(itemID, true))
+
+	msg := nextPresenceMessage(t, first)
+	require.Len(t, msg.Presence.Users, 1)
+	assert.Equal(t, adminClaims.UserID, msg.Presence.Users[0].UserID)
+	assert.True(t, msg.Presence.Users[0].Editing)
+	assert.Equal(t, time.Date(2026, 3, 10, 9, 0, 1, 0, time.UTC), msg.Presence.Users[0].Since,
+		"since must be the earliest of the user's sessions")
+}
+
+func TestPresenceService_MaxItems(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{MaxItems: 1})
+	sess, _ := joinPresence(t, svc, feed, viewerClaims)
+
+	require.NoError(t, sess.Subscribe(uuid.New()))
+	err := sess.Subscribe(uuid.New())
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestPresenceService_ChangeOnlyForSubscribedItems(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	itemID := uuid.New()
+
+	sess, events := joinPresence(t, svc, feed, managerClaims)
+	require.NoError(t, sess.SetEditing(itemID, true))
+	nextPresenceMessage(t, sess)
+
+	events <- itemChangeEvent(1, uuid.New())
+	events <- &domain.ChangeEvent{Entry: auditEvent(2, domain.AuditEntityUser)}
+	events <- itemChangeEvent(3, itemID)
+
+	msg := nextPresenceMessage(t, sess)
+	require.NotNil(t, msg.Change)
+	assert.Equal(t, int64(3), msg.Change.Entry.ID)
+}
+
+func TestPresenceService_ResubscribeAfterFeedClosed(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	itemID := uuid.New()
+
+	sess, events := joinPresence(t, svc, feed, managerClaims)
+	require.NoError(t, sess.Subscribe(itemID))
+	nextPresenceMessage(t, sess)
+
+	next := make(chan *domain.ChangeEvent, 1)
+	resumed := domain.NewEventSubscription(next, func() {})
+	resumed.Replay = []*domain.ChangeEvent{itemChangeEvent(8, itemID)}
+	feed.EXPECT().Subscribe(mock.Anything, managerClaims, int64(7)).Return(resumed, nil).Once()
+
+	events <- itemChangeEvent(7, itemID)
+	close(events)
+
+	assert.Equal(t, int64(7), nextPresenceMessage(t, sess).Change.Entry.ID)
+	assert.Equal(t, int64(8), nextPresenceMessage(t, sess).Change.Entry.ID)
+
+	next <- itemChangeEvent(9, itemID)
+	assert.Equal(t, int64(9), nextPresenceMessage(t, sess).Change.Entry.ID)
+}
+
+func TestPresenceService_SlowSessionClosed(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{Buffer: 2})
+	itemID := uuid.New()
+
+	slow, _ := joinPresence(t, svc, feed, viewerClaims)
+	other, _ := joinPresence(t, svc, feed, managerClaims)
+
+	require.NoError(t, slow.Subscribe(itemID))
+	require.NoError(t, other.Subscribe(itemID))
+	nextPresenceMessage(t, other)
+	// очередь slow уже заполнена, третье сообщение её переполняет
+	require.NoError(t, other.SetEditing(itemID, true))
+
+	<-slow.Messages()
+	<-slow.Messages()
+	_, ok := <-slow.Messages()
+	assert.False(t, ok, "session must be closed on overflow")
+
+	// other получает рассылку, при которой slow отключился, и только после неё - состав без slow
+	msg := nextPresenceMessage(t, other)
+	require.Len(t, msg.Presence.Users, 2)
+	msg = nextPresenceMessage(t, other)
+	require.Len(t, msg.Presence.Users, 1)
+	assert.Equal(t, "manager", msg.Presence.Users[0].Username)
+
+	assert.ErrorIs(t, slow.Subscribe(itemID), domain.ErrEventsUnavailable)
+}
+
+func TestPresenceService_SessionClose(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	itemID := uuid.New()
+
+	first, _ := joinPresence(t, svc, feed, managerClaims)
+	second, _ := joinPresence(t, svc, feed, viewerClaims)
+	require.NoError(t, first.SetEditing(itemID, true))
+	require.NoError(t, second.Subscribe(itemID))
+	nextPresenceMessage(t, second)
+
+	first.Close()
+	first.Close()
+
+	msg := nextPresenceMessage(t, second)
+	require.Len(t, msg.Presence.Users, 1)
+	assert.Equal(t, "viewer", msg.Presence.Users[0].Username)
+}
+
+func TestPresenceService_Close(t *testing.T) {
+	svc, feed := newPresenceService(t, PresenceOptions{})
+	sess, _ := joinPresence(t, svc, feed, viewerClaims)
+
+	svc.Close()
+
+	_, ok := <-sess.Messages()
+	assert.False(t, ok)
+
+	feed.EXPECT().Subscribe(mock.Anything, viewerClaims, int64(0)).
+		Return(domain.NewEventSubscription(make(chan *domain.ChangeEvent), func() {}), nil).Once()
+	_, err := svc.Join(context.Background(), viewerClaims)
+	assert.ErrorIs(t, err, domain.ErrEventsUnavailable)
+}
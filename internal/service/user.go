@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/wb-go/wbf/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	usernameMinLen = 3
+	usernameMaxLen = 64
+	passwordMinLen = 8
+	// bcrypt учитывает только первые 72 байта пароля - длиннее не принимаем, чтобы хвост не игнорировался молча
+	passwordMaxBytes = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+type userAdminRepository interface {
+	Create(ctx context.Context, actorID uuid.UUID, user *domain.User) (uuid.UUID, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	List(ctx context.Context) ([]*domain.User, error)
+	UpdateRole(ctx context.Context, actorID, id uuid.UUID, role domain.Role) (*domain.User, error)
+	UpdatePassword(ctx context.Context, actorID, id uuid.UUID, passwordHash string) (*domain.User, error)
+	SetDisabled(ctx context.Context, actorID, id uuid.UUID, disabled bool) (*domain.User, error)
+	Delete(ctx context.Context, actorID, id uuid.UUID) error
+}
+
+// sessionRevoker завершает все сессии пользователя - AuthService.RevokeUserSessions
+type sessionRevoker interface {
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) error
+}
+
+// UserService - управление учётными записями администратором.
+// Все изменения пишутся в журнал аудита от имени администратора.
+// Свою учётную запись через него менять нельзя: так администратор
+// не может случайно отключить или понизить себя и остаться без доступа.
+// Смена роли, сброс пароля, отключение и удаление завершают сессии пользователя:
+// роль записана в токене доступа, и без отзыва прежние права действовали бы до его истечения.
+type UserService struct {
+	repo       userAdminRepository
+	sessions   sessionRevoker
+	bcryptCost int
+	log        logger.Logger
+}
+
+func NewUserService(repo userAdminRepository, sessions sessionRevoker, bcryptCost int, log logger.Logger) *UserService {
+	return &UserService{
+		repo:       repo,
+		sessions:   sessions,
+		bcryptCost: bcryptCost,
+		log:        log.With("component", "UserService"),
+	}
+}
+
+func (s *UserService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.User, error) {
+	const op = "UserService.List"
+
+	if !claims.Role.CanManageUsers() {
+		return nil, domain.ErrForbidden
+	}
+
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to list users",
+			"error", err,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return users, nil
+}
+
+func (s *UserService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.User, error) {
+	const op = "UserService.Get"
+
+	if !claims.Role.CanManageUsers() {
+		return nil, domain.ErrForbidden
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to get user",
+			"error", err,
+			"target_user_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+func (s *UserService) Create(ctx context.Context, claims *domain.AuthClaims, input *domain.CreateUserInput) (*domain.User, error) {
+	const op = "UserService.Create"
+
+	if !claims.Role.CanManageUsers() {
+		return nil, domain.ErrForbidden
+	}
+
+	if err := validateUsername(input.Username); err != nil {
+		return nil, err
+	}
+	if err := validatePassword("password", input.Password); err != nil {
+		return nil, err
+	}
+	if !input.Role.IsValid() {
+		return nil, &domain.ValidationError{Field: "role", Reason: "must be admin, manager or viewer"}
+	}
+
+	hash, err := generatePasswordHash(input.Password, s.bcryptCost)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.repo.Create(ctx, claims.UserID, &domain.User{
+		Username:     input.Username,
+		PasswordHash: hash,
+		Role:         input.Role,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, &domain.ValidationError{Field: "username", Reason: "is already taken"}
+		}
+		s.log.Ctx(ctx).Error("failed to create user",
+			"error", err,
+			"user_id", claims.UserID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("user created",
+		"user_id", claims.UserID,
+		"target_user_id", id,
+		"role", input.Role,
+	)
+	return s.Get(ctx, claims, id)
+}
+
+func (s *UserService) UpdateRole(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, role domain.Role) (*domain.User, error) {
+	const op = "UserService.UpdateRole"
+
+	if err := s.checkTarget(claims, id); err != nil {
+		return nil, err
+	}
+	if !role.IsValid() {
+		return nil, &domain.ValidationError{Field: "role", Reason: "must be admin, manager or viewer"}
+	}
+
+	user, err := s.repo.UpdateRole(ctx, claims.UserID, id, role)
+	if err != nil {
+		return nil, s.wrapUpdateErr(ctx, op, "failed to update user role", id, err)
+	}
+	if err = s.sessions.RevokeUserSessions(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("user role changed",
+		"user_id", claims.UserID,
+		"target_user_id", id,
+		"role", role,
+	)
+	return user, nil
+}
+
+// ResetPassword задаёт пользователю новый пароль без проверки старого
+func (s *UserService) ResetPassword(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, password string) (*domain.User, error) {
+	const op = "UserService.ResetPassword"
+
+	if err := s.checkTarget(claims, id); err != nil {
+		return nil, err
+	}
+	if err := validatePassword("password", password); err != nil {
+		return nil, err
+	}
+
+	hash, err := generatePasswordHash(password, s.bcryptCost)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.repo.UpdatePassword(ctx, claims.UserID, id, hash)
+	if err != nil {
+		return nil, s.wrapUpdateErr(ctx, op, "failed to reset user password", id, err)
+	}
+	if err = s.sessions.RevokeUserSessions(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Ctx(ctx).Info("user password reset",
+		"user_id", claims.UserID,
+		"target_user_id", id,
+	)
+	return user, nil
+}
+
+// SetDisabled отключает или включает учётную запись; отключённый пользователь не может войти
+func (s *UserService) SetDisabled(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID, disabled bool) (*domain.User, error) {
+	const op = "UserService.SetDisabled"
+
+	if err := s.checkTarget(claims, id); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.SetDisabled(ctx, claims.UserID, id, disabled)
+	if err != nil {
+		return nil, s.wrapUpdateErr(ctx, op, "failed to change user status", id, err)
+	}
+	// без отзыва выданные токены доступа действовали бы до истечения
+	if disabled {
+		if err = s.sessions.RevokeUserSessions(ctx, id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	s.log.Ctx(ctx).Info("user status changed",
+		"user_id", claims.UserID,
+		"target_user_id", id,
+		"disabled", disabled,
+	)
+	return user, nil
+}
+
+func (s *UserService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+	const op = "UserService.Delete"
+
+	if err := s.checkTarget(claims, id); err != nil {
+		return err
+	}
+
+	// до удаления: refresh-токены удаляются вместе с пользователем, и по ним уже не найти токены доступа
+	if err := s.sessions.RevokeUserSessions(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.repo.Delete(ctx, claims.UserID, id); err != nil {
+		return s.wrapUpdateErr(ctx, op, "failed to delete user", id, err)
+	}
+
+	s.log.Ctx(ctx).Info("user deleted",
+		"user_id", claims.UserID,
+		"target_user_id", id,
+	)
+	return nil
+}
+
+// checkTarget - право на управление пользователями и запрет менять собственную учётную запись
+func (s *UserService) checkTarget(claims *domain.AuthClaims, id uuid.UUID) error {
+	if !claims.Role.CanManageUsers() {
+		return domain.ErrForbidden
+	}
+	if id == claims.UserID {
+		return domain.ErrOwnAccount
+	}
+	return nil
+}
+
+func (s *UserService) wrapUpdateErr(ctx context.Context, op, msg string, id uuid.UUID, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrNotFound
+	}
+	s.log.Ctx(ctx).Error(msg,
+		"error", err,
+		"target_user_id", id,
+	)
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func validateUsername(username string) error {
+	if n := utf8.RuneCountInString(username); n < usernameMinLen || n > usernameMaxLen {
+		return &domain.ValidationError{
+			Field:  "username",
+			Reason: fmt.Sprintf("must be %d to %d characters", usernameMinLen, usernameMaxLen),
+		}
+	}
+	if !usernamePattern.MatchString(username) {
+		return &domain.ValidationError{Field: "username", Reason: "may contain only latin letters, digits, '.', '_' and '-'"}
+	}
+	return nil
+}
+
+// validatePassword проверяет длину пароля; field - имя поля в ошибке
+func validatePassword(field, password string) error {
+	if utf8.RuneCountInString(password) < passwordMinLen {
+		return &domain.ValidationError{Field: field, Reason: fmt.Sprintf("must be at least %d characters", passwordMinLen)}
+	}
+	if len(password) > passwordMaxBytes {
+		return &domain.ValidationError{Field: field, Reason: fmt.Sprintf("must be at most %d bytes", passwordMaxBytes)}
+	}
+	return nil
+}
+
+func generatePasswordHash(password string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(hash), nil
+}
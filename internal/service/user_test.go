@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestUserService - UserService с моками хранилища и отзыва сессий
+func newTestUserService(t *testing.T) (*UserService, *mockuserAdminRepository, *mocksessionRevoker) {
+	repo := newMockuserAdminRepository(t)
+	sessions := newMocksessionRevoker(t)
+	return NewUserService(repo, sessions, bcrypt.MinCost, newTestLogger()), repo, sessions
+}
+
+func TestUserService_Create(t *testing.T) {
+	svc, repo, _ := newTestUserService(t)
+	id := uuid.New()
+
+	repo.EXPECT().Create(mock.Anything, adminClaims.UserID, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Username == "storekeeper" && u.Role == domain.RoleManager &&
+			bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("s3cret-pass")) == nil
+	})).Return(id, nil)
+	repo.EXPECT().GetByID(mock.Anything, id).Return(&domain.User{ID: id, Username: "storekeeper", Role: domain.RoleManager}, nil)
+
+	user, err := svc.Create(context.Background(), adminClaims, &domain.CreateUserInput{
+		Username: "storekeeper",
+		Password: "s3cret-pass",
+		Role:     domain.RoleManager,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+}
+
+func TestUserService_Create_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  *domain.AuthClaims
+		input   *domain.CreateUserInput
+		field   string
+		wantErr error
+	}{
+		{
+			name:    "manager forbidden",
+			claims:  managerClaims,
+			input:   &domain.CreateUserInput{Username: "bob", Password: "password1", Role: domain.RoleViewer},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name:   "short username",
+			claims: adminClaims,
+			input:  &domain.CreateUserInput{Username: "bo", Password: "password1", Role: domain.RoleViewer},
+			field:  "username",
+		},
+		{
+			name:   "username with spaces",
+			claims: adminClaims,
+			input:  &domain.CreateUserInput{Username: "bob smith", Password: "password1", Role: domain.RoleViewer},
+			field:  "username",
+		},
+		{
+			name:   "short password",
+			claims: adminClaims,
+			input:  &domain.CreateUserInput{Username: "bob", Password: "pass", Role: domain.RoleViewer},
+			field:  "password",
+		},
+		{
+			name:   "password over bcrypt limit",
+			claims: adminClaims,
+			input:  &domain.CreateUserInput{Username: "bob", Password: strings.Repeat("п", 37), Role: domain.RoleViewer},
+			field:  "password",
+		},
+		{
+			name:   "unknown role",
+			claims: adminClaims,
+			input:  &domain.CreateUserInput{Username: "bob", Password: "password1", Role: "root"},
+			field:  "role",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newTestUserService(t)
+
+			_, err := svc.Create(context.Background(), tt.claims, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			var verr *domain.ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.field, verr.Field)
+		})
+	}
+}
+
+func TestUserService_Create_DuplicateUsername(t *testing.T) {
+	svc, repo, _ := newTestUserService(t)
+
+	repo.EXPECT().Create(mock.Anything, adminClaims.UserID, mock.Anything).
+		Return(uuid.Nil, fmt.Errorf("UserRepository.Create: %w", domain.ErrAlreadyExists))
+
+	_, err := svc.Create(context.Background(), adminClaims, &domain.CreateUserInput{
+		Username: "admin", Password: "password1", Role: domain.RoleAdmin,
+	})
+
+	var verr *domain.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "username", verr.Field)
+}
+
+func TestUserService_OwnAccount(t *testing.T) {
+	svc, _, _ := newTestUserService(t)
+	ctx := context.Background()
+	self := adminClaims.UserID
+
+	_, err := svc.UpdateRole(ctx, adminClaims, self, domain.RoleViewer)
+	assert.ErrorIs(t, err, domain.ErrOwnAccount)
+	_, err = svc.ResetPassword(ctx, adminClaims, self, "password1")
+	assert.ErrorIs(t, err, domain.ErrOwnAccount)
+	_, err = svc.SetDisabled(ctx, adminClaims, self, true)
+	assert.ErrorIs(t, err, domain.ErrOwnAccount)
+	assert.ErrorIs(t, svc.Delete(ctx, adminClaims, self), domain.ErrOwnAccount)
+
+	// права проверяются раньше: не-админ не узнаёт, что речь о его учётной записи
+	_, err = svc.SetDisabled(ctx, managerClaims, managerClaims.UserID, true)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestUserService_UpdateRole(t *testing.T) {
+	svc, repo, sessions := newTestUserService(t)
+	id := uuid.New()
+
+	_, err := svc.UpdateRole(context.Background(), adminClaims, id, "superuser")
+	var verr *domain.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "role", verr.Field)
+
+	repo.EXPECT().UpdateRole(mock.Anything, adminClaims.UserID, id, domain.RoleAdmin).
+		Return(&domain.User{ID: id, Role: domain.RoleAdmin}, nil)
+	// роль записана в токене: выданные с прежней ролью токены отзываются
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(nil).Once()
+	user, err := svc.UpdateRole(context.Background(), adminClaims, id, domain.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, user.Role)
+
+	demoted := uuid.New()
+	repo.EXPECT().UpdateRole(mock.Anything, adminClaims.UserID, demoted, domain.RoleViewer).
+		Return(&domain.User{ID: demoted, Role: domain.RoleViewer}, nil)
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, demoted).Return(errors.New("db down")).Once()
+	_, err = svc.UpdateRole(context.Background(), adminClaims, demoted, domain.RoleViewer)
+	assert.Error(t, err, "role change must fail when sessions are not revoked")
+
+	missing := uuid.New()
+	repo.EXPECT().UpdateRole(mock.Anything, adminClaims.UserID, missing, domain.RoleViewer).
+		Return(nil, domain.ErrNotFound)
+	_, err = svc.UpdateRole(context.Background(), adminClaims, missing, domain.RoleViewer)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	svc, repo, sessions := newTestUserService(t)
+	id := uuid.New()
+
+	_, err := svc.ResetPassword(context.Background(), adminClaims, id, "short")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	changedAt := time.Now()
+	repo.EXPECT().UpdatePassword(mock.Anything, adminClaims.UserID, id, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+	})).Return(&domain.User{ID: id, PasswordChangedAt: &changedAt}, nil)
+	// украденный refresh-токен не должен пережить сброс пароля
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(nil).Once()
+
+	user, err := svc.ResetPassword(context.Background(), adminClaims, id, "new-password")
+	require.NoError(t, err)
+	assert.NotNil(t, user.PasswordChangedAt)
+}
+
+func TestUserService_SetDisabled(t *testing.T) {
+	svc, repo, sessions := newTestUserService(t)
+	id := uuid.New()
+	disabledAt := time.Now()
+
+	// отключение завершает сессии, включение - нет
+	repo.EXPECT().SetDisabled(mock.Anything, adminClaims.UserID, id, true).
+		Return(&domain.User{ID: id, DisabledAt: &disabledAt}, nil)
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(nil).Once()
+	user, err := svc.SetDisabled(context.Background(), adminClaims, id, true)
+	require.NoError(t, err)
+	assert.True(t, user.Disabled())
+
+	repo.EXPECT().SetDisabled(mock.Anything, adminClaims.UserID, id, false).
+		Return(&domain.User{ID: id}, nil)
+	user, err = svc.SetDisabled(context.Background(), adminClaims, id, false)
+	require.NoError(t, err)
+	assert.False(t, user.Disabled())
+
+	_, err = svc.SetDisabled(context.Background(), viewerClaims, id, true)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestUserService_Delete(t *testing.T) {
+	svc, repo, sessions := newTestUserService(t)
+	id := uuid.New()
+
+	// сессии отзываются до удаления - вместе с пользователем удаляются и его refresh-токены
+	revoke := sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(nil).Once()
+	repo.EXPECT().Delete(mock.Anything, adminClaims.UserID, id).Return(nil).Once().NotBefore(revoke)
+	assert.NoError(t, svc.Delete(context.Background(), adminClaims, id))
+
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(nil).Once()
+	repo.EXPECT().Delete(mock.Anything, adminClaims.UserID, id).Return(domain.ErrNotFound).Once()
+	assert.ErrorIs(t, svc.Delete(context.Background(), adminClaims, id), domain.ErrNotFound)
+
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(nil).Once()
+	repo.EXPECT().Delete(mock.Anything, adminClaims.UserID, id).Return(errors.New("db down")).Once()
+	err := svc.Delete(context.Background(), adminClaims, id)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+
+	// не удалось отозвать сессии - пользователь не удаляется
+	sessions.EXPECT().RevokeUserSessions(mock.Anything, id).Return(errors.New("db down")).Once()
+	assert.Error(t, svc.Delete(context.Background(), adminClaims, id))
+}
@@ -0,0 +1,570 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/publisher"
+	"github.com/wb-go/wbf/logger"
+)
+
+const (
+	webhookURLMaxLen       = 2048
+	webhookSecretMinLen    = 16
+	webhookSecretMaxLen    = 256
+	webhookSecretPrefix    = "whsec_"
+	webhookSecretRandBytes = 32
+)
+
+type webhookRepository interface {
+	Create(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	List(ctx context.Context) ([]*domain.Webhook, error)
+	Update(ctx context.Context, id uuid.UUID, input *domain.UpdateWebhookInput) (*domain.Webhook, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Enqueue(ctx context.Context, ev *domain.OutboxEvent, payload []byte) (int64, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	SaveAttempt(
+		ctx context.Context,
+		id uuid.UUID,
+		attempt *domain.WebhookAttempt,
+		status domain.WebhookDeliveryStatus,
+		retryIn time.Duration,
+	) error
+	ListDeliveries(ctx context.Context, filter *domain.WebhookDeliveryFilter, limit, offset int) ([]*domain.WebhookDelivery, int64, error)
+	Redeliver(ctx context.Context, webhookID, id uuid.UUID) (*domain.WebhookDelivery, error)
+	DeleteFinished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type webhookSender interface {
+	Send(ctx context.Context, hook *domain.Webhook, d *domain.WebhookDelivery) *domain.WebhookAttempt
+}
+
+// WebhookOptions - доставка исходящих webhook
+type WebhookOptions struct {
+	Workers         int           // число параллельных отправителей
+	BatchSize       int           // сколько доставок отправитель забирает за раз
+	PollInterval    time.Duration // как часто отправитель проверяет очередь без сигнала
+	Lease           time.Duration // сколько выданная пачка закреплена за отправителем; больше BatchSize таймаутов запроса
+	MaxAttempts     int           // после стольких неудач доставка помечается failed
+	RetryDelay      time.Duration // пауза после первой неудачи, дальше удваивается
+	MaxBackoff      time.Duration // предел паузы между попытками
+	Retention       time.Duration // сколько хранить завершённые доставки
+	CleanupInterval time.Duration // как часто удалять старые доставки
+}
+
+// WebhookService ведёт подписки на исходящие webhook и доставляет их.
+// Как EventPublisher релея outbox он раскладывает событие по подпискам на его тип,
+// отправители забирают доставки из webhook_deliveries и повторяют неудачные с нарастающей паузой.
+type WebhookService struct {
+	repo   webhookRepository
+	sender webhookSender
+	opts   WebhookOptions
+	log    logger.Logger
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+func NewWebhookService(repo webhookRepository, sender webhookSender, opts WebhookOptions, log logger.Logger) *WebhookService {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 10
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = 5 * time.Minute
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 8
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = 30 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 30 * 24 * time.Hour
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Hour
+	}
+
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		opts:   opts,
+		log:    log.With("component", "WebhookService"),
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+}
+
+// Create регистрирует подписку; без Secret ключ подписи генерируется
+func (s *WebhookService) Create(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	input *domain.CreateWebhookInput,
+) (*domain.Webhook, error) {
+	const op = "WebhookService.Create"
+
+	if !claims.Role.CanManageWebhooks() {
+		return nil, domain.ErrForbidden
+	}
+
+	if err := validateWebhookURL(input.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	} else if err = validateWebhookSecret(secret); err != nil {
+		return nil, err
+	}
+
+	hook := &domain.Webhook{
+		URL:        input.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     input.Active == nil || *input.Active,
+		CreatedBy:  claims.UserID,
+	}
+	hook, err = s.repo.Create(ctx, hook)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to create webhook",
+			"error", err,
+			"user_id", claims.UserID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hook, nil
+}
+
+func (s *WebhookService) List(ctx context.Context, claims *domain.AuthClaims) ([]*domain.Webhook, error) {
+	const op = "WebhookService.List"
+
+	if !claims.Role.CanManageWebhooks() {
+		return nil, domain.ErrForbidden
+	}
+
+	hooks, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to list webhooks",
+			"error", err,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return hooks, nil
+}
+
+func (s *WebhookService) Get(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) (*domain.Webhook, error) {
+	const op = "WebhookService.Get"
+
+	if !claims.Role.CanManageWebhooks() {
+		return nil, domain.ErrForbidden
+	}
+
+	hook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to get webhook",
+			"error", err,
+			"webhook_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return hook, nil
+}
+
+func (s *WebhookService) Update(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	id uuid.UUID,
+	input *domain.UpdateWebhookInput,
+) (*domain.Webhook, error) {
+	const op = "WebhookService.Update"
+
+	if !claims.Role.CanManageWebhooks() {
+		return nil, domain.ErrForbidden
+	}
+
+	if input.URL == nil && input.EventTypes == nil && input.Secret == nil && input.Active == nil {
+		return nil, domain.ErrNoChanges
+	}
+	if input.URL != nil {
+		if err := validateWebhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+	}
+	if input.EventTypes != nil {
+		eventTypes, err := normalizeWebhookEventTypes(input.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		input.EventTypes = eventTypes
+	}
+	if input.Secret != nil {
+		if err := validateWebhookSecret(*input.Secret); err != nil {
+			return nil, err
+		}
+	}
+
+	hook, err := s.repo.Update(ctx, id, input)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to update webhook",
+			"error", err,
+			"webhook_id", id,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// включённая подписка могла накопить доставки
+	if hook.Active {
+		s.notify()
+	}
+	return hook, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, claims *domain.AuthClaims, id uuid.UUID) error {
+	const op = "WebhookService.Delete"
+
+	if !claims.Role.CanManageWebhooks() {
+		return domain.ErrForbidden
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to delete webhook",
+			"error", err,
+			"webhook_id", id,
+		)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ListDeliveries - журнал доставок подписки, новые первыми
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	filter *domain.WebhookDeliveryFilter,
+	page, pageSize int,
+) (*domain.WebhookDeliveryList, error) {
+	const op = "WebhookService.ListDeliveries"
+
+	if !claims.Role.CanManageWebhooks() {
+		return nil, domain.ErrForbidden
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, &domain.ValidationError{Field: "status", Reason: "must be pending, succeeded or failed"}
+	}
+	// несуществующая подписка - 404, а не пустой журнал
+	if _, err := s.Get(ctx, claims, filter.WebhookID); err != nil {
+		return nil, err
+	}
+
+	page, pageSize = normalizePagination(page, pageSize)
+	offset := (page - 1) * pageSize
+
+	deliveries, total, err := s.repo.ListDeliveries(ctx, filter, pageSize, offset)
+	if err != nil {
+		s.log.Ctx(ctx).Error("failed to list webhook deliveries",
+			"error", err,
+			"webhook_id", filter.WebhookID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &domain.WebhookDeliveryList{
+		Deliveries: deliveries,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: calcTotalPages(total, pageSize),
+	}, nil
+}
+
+// Redeliver ставит в очередь повторную отправку события доставки; возвращает новую доставку
+func (s *WebhookService) Redeliver(
+	ctx context.Context,
+	claims *domain.AuthClaims,
+	webhookID, deliveryID uuid.UUID,
+) (*domain.WebhookDelivery, error) {
+	const op = "WebhookService.Redeliver"
+
+	if !claims.Role.CanManageWebhooks() {
+		return nil, domain.ErrForbidden
+	}
+
+	d, err := s.repo.Redeliver(ctx, webhookID, deliveryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		s.log.Ctx(ctx).Error("failed to redeliver webhook",
+			"error", err,
+			"webhook_id", webhookID,
+			"delivery_id", deliveryID,
+		)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.notify()
+	return d, nil
+}
+
+// Publish раскладывает событие outbox по подпискам на его тип. Вызывается релеем outbox:
+// ошибка - событие будет предложено снова, уже созданные доставки не задваиваются.
+func (s *WebhookService) Publish(ctx context.Context, ev *domain.OutboxEvent) error {
+	const op = "WebhookService.Publish"
+
+	payload, err := publisher.Encode(ev)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := s.repo.Enqueue(ctx, ev, payload)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n > 0 {
+		s.notify()
+	}
+	return nil
+}
+
+// Run запускает отправителей и очистку журнала; блокируется до отмены ctx
+func (s *WebhookService) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < s.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.worker(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.cleanupLoop(ctx)
+	}()
+
+	wg.Wait()
+	return nil
+}
+
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *WebhookService) worker(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// полная пачка - в очереди, скорее всего, есть ещё
+		for ctx.Err() == nil {
+			n, err := s.dispatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.log.Ctx(ctx).Error("failed to claim webhook deliveries",
+						"error", err,
+					)
+				}
+				break
+			}
+			if n < s.opts.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// dispatch отправляет одну пачку доставок и возвращает её размер
+func (s *WebhookService) dispatch(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.Claim(ctx, s.opts.BatchSize, s.opts.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			// невыполненные доставки выдадутся снова после Lease
+			break
+		}
+		s.deliver(ctx, d)
+	}
+	return len(deliveries), nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, d *domain.WebhookDelivery) {
+	attempt := s.sender.Send(ctx, d.Webhook, d)
+	if ctx.Err() != nil && !attempt.Succeeded() {
+		// попытку оборвала остановка приложения - она не считается
+		return
+	}
+
+	attempts := d.Attempts + 1
+	var (
+		status  domain.WebhookDeliveryStatus
+		retryIn time.Duration
+	)
+	switch {
+	case attempt.Succeeded():
+		status = domain.WebhookDeliverySucceeded
+	case attempts >= s.opts.MaxAttempts:
+		status = domain.WebhookDeliveryFailed
+	default:
+		status = domain.WebhookDeliveryPending
+		retryIn = s.backoff(attempts)
+	}
+
+	if status != domain.WebhookDeliverySucceeded {
+		s.log.Ctx(ctx).Warn("failed to deliver webhook",
+			"error", attempt.Error,
+			"webhook_id", d.WebhookID,
+			"delivery_id", d.ID,
+			"event_type", d.EventType,
+			"attempts", attempts,
+			"status", status,
+			"retry_in", retryIn,
+		)
+	}
+
+	// результат сохраняется и при остановке: успешная доставка не должна уйти повторно без нужды
+	if err := s.repo.SaveAttempt(context.WithoutCancel(ctx), d.ID, attempt, status, retryIn); err != nil {
+		s.log.Ctx(ctx).Error("failed to save webhook attempt",
+			"error", err,
+			"delivery_id", d.ID,
+		)
+	}
+}
+
+// backoff - RetryDelay, удваивающийся с каждой неудачей, не больше MaxBackoff
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	delay := s.opts.RetryDelay
+	for i := 1; i < attempts && delay < s.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.opts.MaxBackoff)
+}
+
+func (s *WebhookService) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WebhookService) cleanup(ctx context.Context) {
+	n, err := s.repo.DeleteFinished(ctx, s.now().Add(-s.opts.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Ctx(ctx).Error("failed to delete old webhook deliveries",
+				"error", err,
+			)
+		}
+		return
+	}
+	if n > 0 {
+		s.log.Ctx(ctx).Info("old webhook deliveries deleted",
+			"count", n,
+		)
+	}
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &domain.ValidationError{Field: "url", Reason: "must be an absolute http or https URL"}
+	}
+	if len(raw) > webhookURLMaxLen {
+		return &domain.ValidationError{Field: "url", Reason: fmt.Sprintf("must be at most %d characters", webhookURLMaxLen)}
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes проверяет типы событий и убирает повторы, сохраняя порядок
+func normalizeWebhookEventTypes(types []domain.OutboxEventType) ([]domain.OutboxEventType, error) {
+	if len(types) == 0 {
+		return nil, &domain.ValidationError{Field: "event_types", Reason: "must not be empty"}
+	}
+
+	res := make([]domain.OutboxEventType, 0, len(types))
+	seen := make(map[domain.OutboxEventType]bool, len(types))
+	for _, t := range types {
+		if !t.IsValid() {
+			return nil, &domain.ValidationError{Field: "event_types", Reason: fmt.Sprintf("unknown event type %q", t)}
+		}
+		if !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func validateWebhookSecret(secret string) error {
+	if len(secret) < webhookSecretMinLen || len(secret) > webhookSecretMaxLen {
+		return &domain.ValidationError{
+			Field:  "secret",
+			Reason: fmt.Sprintf("must be %d to %d characters", webhookSecretMinLen, webhookSecretMaxLen),
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretRandBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stpnv0/WarehouseControl/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookService(t *testing.T, opts WebhookOptions) (*WebhookService, *mockwebhookRepository, *mockwebhookSender) {
+	repo := newMockwebhookRepository(t)
+	sender := newMockwebhookSender(t)
+	return NewWebhookService(repo, sender, opts, newTestLogger()), repo, sender
+}
+
+func webhookDelivery(attempts int) *domain.WebhookDelivery {
+	hook := &domain.Webhook{ID: uuid.New(), URL: "https://example.com/hook", Secret: "receiver-signing-key"}
+	return &domain.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: hook.ID,
+		EventID:   uuid.New(),
+		EventType: domain.OutboxItemUpdated,
+		Payload:   []byte(`{}`),
+		Status:    domain.WebhookDeliveryPending,
+		Attempts:  attempts,
+		Webhook:   hook,
+	}
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	svc, repo, _ := newWebhookService(t, WebhookOptions{})
+
+	repo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(h *domain.Webhook) bool {
+		return h.URL == "https://example.com/hook" &&
+			assert.ObjectsAreEqual([]domain.OutboxEventType{domain.OutboxItemLowStock, domain.OutboxItemDeleted}, h.EventTypes) &&
+			strings.HasPrefix(h.Secret, webhookSecretPrefix) && len(h.Secret) == len(webhookSecretPrefix)+2*webhookSecretRandBytes &&
+			h.Active && h.CreatedBy == adminClaims.UserID
+	})).RunAndReturn(func(_ context.Context, h *domain.Webhook) (*domain.Webhook, error) {
+		h.ID = uuid.New()
+		return h, nil
+	})
+
+	hook, err := svc.Create(context.Background(), adminClaims, &domain.CreateWebhookInput{
+		URL:        "https://example.com/hook",
+		EventTypes: []domain.OutboxEventType{domain.OutboxItemLowStock, domain.OutboxItemDeleted, domain.OutboxItemLowStock},
+	})
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, hook.ID)
+}
+
+func TestWebhookService_Create_Validation(t *testing.T) {
+	inactive := false
+	tests := []struct {
+		name    string
+		claims  *domain.AuthClaims
+		input   *domain.CreateWebhookInput
+		field   string
+		wantErr error
+	}{
+		{
+			name:    "manager forbidden",
+			claims:  managerClaims,
+			input:   &domain.CreateWebhookInput{URL: "https://example.com", EventTypes: []domain.OutboxEventType{domain.OutboxItemCreated}},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name:   "relative url",
+			claims: adminClaims,
+			input:  &domain.CreateWebhookInput{URL: "/hook", EventTypes: []domain.OutboxEventType{domain.OutboxItemCreated}},
+			field:  "url",
+		},
+		{
+			name:   "ftp url",
+			claims: adminClaims,
+			input:  &domain.CreateWebhookInput{URL: "ftp://example.com/hook", EventTypes: []domain.OutboxEventType{domain.OutboxItemCreated}},
+			field:  "url",
+		},
+		{
+			name:   "no event types",
+			claims: adminClaims,
+			input:  &domain.CreateWebhookInput{URL: "https://example.com"},
+			field:  "event_types",
+		},
+		{
+			name:   "unknown event type",
+			claims: adminClaims,
+			input:  &domain.CreateWebhookInput{URL: "https://example.com", EventTypes: []domain.OutboxEventType{"item.moved"}},
+			field:  "event_types",
+		},
+		{
+			name:   "short secret",
+			claims: adminClaims,
+			input: &domain.CreateWebhookInput{
+				URL:        "https://example.com",
+				EventTypes: []domain.OutboxEventType{domain.OutboxItemCreated},
+				Secret:     "short",
+				Active:     &inactive,
+			},
+			field: "secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newWebhookService(t, WebhookOptions{})
+
+			_, err := svc.Create(context.Background(), tt.claims, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			var verr *domain.ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.field, verr.Field)
+		})
+	}
+}
+
+func TestWebhookService_Update(t *testing.T) {
+	svc, repo, _ := newWebhookService(t, WebhookOptions{})
+	id := uuid.New()
+
+	_, err := svc.Update(context.Background(), adminClaims, id, &domain.UpdateWebhookInput{})
+	assert.ErrorIs(t, err, domain.ErrNoChanges)
+
+	active := true
+	repo.EXPECT().Update(mock.Anything, id, mock.Anything).Return(&domain.Webhook{ID: id, Active: true}, nil)
+
+	_, err = svc.Update(context.Background(), adminClaims, id, &domain.UpdateWebhookInput{Active: &active})
+	require.NoError(t, err)
+	assert.Len(t, svc.wake, 1, "re-enabled webhook must wake the workers")
+
+	repo.EXPECT().Update(mock.Anything, uuid.Nil, mock.Anything).Return(nil, domain.ErrNotFound)
+	_, err = svc.Update(context.Background(), adminClaims, uuid.Nil, &domain.UpdateWebhookInput{Active: &active})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestWebhookService_ListDeliveries(t *testing.T) {
+	svc, repo, _ := newWebhookService(t, WebhookOptions{})
+	id := uuid.New()
+
+	_, err := svc.ListDeliveries(context.Background(), viewerClaims, &domain.WebhookDeliveryFilter{WebhookID: id}, 1, 20)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	var verr *domain.ValidationError
+	_, err = svc.ListDeliveries(context.Background(), adminClaims,
+		&domain.WebhookDeliveryFilter{WebhookID: id, Status: "lost"}, 1, 20)
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "status", verr.Field)
+
+	repo.EXPECT().GetByID(mock.Anything, uuid.Nil).Return(nil, domain.ErrNotFound)
+	_, err = svc.ListDeliveries(context.Background(), adminClaims, &domain.WebhookDeliveryFilter{WebhookID: uuid.Nil}, 1, 20)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	filter := &domain.WebhookDeliveryFilter{WebhookID: id, Status: domain.WebhookDeliveryFailed}
+	repo.EXPECT().GetByID(mock.Anything, id).Return(&domain.Webhook{ID: id}, nil)
+	repo.EXPECT().ListDeliveries(mock.Anything, filter, 20, 20).Return([]*domain.WebhookDelivery{webhookDelivery(8)}, 21, nil)
+
+	list, err := svc.ListDeliveries(context.Background(), adminClaims, filter, 2, 20)
+
+	require.NoError(t, err)
+	assert.Len(t, list.Deliveries, 1)
+	assert.EqualValues(t, 21, list.Total)
+	assert.Equal(t, 2, list.TotalPages)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	svc, repo, _ := newWebhookService(t, WebhookOptions{})
+	hookID, deliveryID := uuid.New(), uuid.New()
+
+	repo.EXPECT().Redeliver(mock.Anything, hookID, deliveryID).RunAndReturn(
+		func(_ context.Context, hookID, id uuid.UUID) (*domain.WebhookDelivery, error) {
+			d := webhookDelivery(0)
+			d.WebhookID = hookID
+			d.RedeliveryOf = &id
+			return d, nil
+		})
+
+	d, err := svc.Redeliver(context.Background(), adminClaims, hookID, deliveryID)
+
+	require.NoError(t, err)
+	assert.Equal(t, deliveryID, *d.RedeliveryOf)
+	assert.Len(t, svc.wake, 1)
+
+	repo.EXPECT().Redeliver(mock.Anything, hookID, uuid.Nil).Return(nil, domain.ErrNotFound)
+	_, err = svc.Redeliver(context.Background(), adminClaims, hookID, uuid.Nil)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	svc, repo, _ := newWebhookService(t, WebhookOptions{})
+	ev := outboxEvent(1, uuid.New())
+	ev.Payload = []byte(`{"item":{"quantity":3}}`)
+
+	repo.EXPECT().Enqueue(mock.Anything, ev, mock.MatchedBy(func(payload []byte) bool {
+		return strings.Contains(string(payload), ev.EventID.String())
+	})).Return(0, nil).Once()
+	require.NoError(t, svc.Publish(context.Background(), ev))
+	assert.Empty(t, svc.wake, "no subscribers - nothing to wake")
+
+	repo.EXPECT().Enqueue(mock.Anything, ev, mock.Anything).Return(2, nil).Once()
+	require.NoError(t, svc.Publish(context.Background(), ev))
+	assert.Len(t, svc.wake, 1)
+
+	repo.EXPECT().Enqueue(mock.Anything, ev, mock.Anything).Return(0, errors.New("db down")).Once()
+	assert.Error(t, svc.Publish(context.Background(), ev))
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int
+		attempt    *domain.WebhookAttempt
+		wantStatus domain.WebhookDeliveryStatus
+		wantRetry  time.Duration
+	}{
+		{
+			name:       "succeeded",
+			attempt:    &domain.WebhookAttempt{StatusCode: http.StatusNoContent},
+			wantStatus: domain.WebhookDeliverySucceeded,
+		},
+		{
+			name:       "first failure",
+			attempt:    &domain.WebhookAttempt{StatusCode: http.StatusBadGateway, Error: "receiver responded 502 Bad Gateway"},
+			wantStatus: domain.WebhookDeliveryPending,
+			wantRetry:  30 * time.Second,
+		},
+		{
+			name:       "backoff doubles",
+			attempts:   3,
+			attempt:    &domain.WebhookAttempt{Error: "connection refused"},
+			wantStatus: domain.WebhookDeliveryPending,
+			wantRetry:  4 * time.Minute, // четвёртая неудача: 30s * 2^3
+		},
+		{
+			name:       "backoff capped",
+			attempts:   6,
+			attempt:    &domain.WebhookAttempt{Error: "connection refused"},
+			wantStatus: domain.WebhookDeliveryPending,
+			wantRetry:  10 * time.Minute,
+		},
+		{
+			name:       "attempts exhausted",
+			attempts:   7,
+			attempt:    &domain.WebhookAttempt{Error: "connection refused"},
+			wantStatus: domain.WebhookDeliveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, sender := newWebhookService(t, WebhookOptions{MaxAttempts: 8, MaxBackoff: 10 * time.Minute})
+			d := webhookDelivery(tt.attempts)
+
+			sender.EXPECT().Send(mock.Anything, d.Webhook, d).Return(tt.attempt)
+			repo.EXPECT().SaveAttempt(mock.Anything, d.ID, tt.attempt, tt.wantStatus, tt.wantRetry).Return(nil)
+
+			svc.deliver(context.Background(), d)
+		})
+	}
+}
+
+func TestWebhookService_Deliver_Shutdown(t *testing.T) {
+	svc, _, sender := newWebhookService(t, WebhookOptions{})
+	d := webhookDelivery(0)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sender.EXPECT().Send(mock.Anything, d.Webhook, d).RunAndReturn(
+		func(context.Context, *domain.Webhook, *domain.WebhookDelivery) *domain.WebhookAttempt {
+			cancel()
+			return &domain.WebhookAttempt{Error: "context canceled"}
+		})
+
+	// оборванная остановкой попытка не сохраняется и не расходует MaxAttempts
+	svc.deliver(ctx, d)
+}
+
+// доставка от очереди до получателя: настоящий Sender, подпись проверяется как у получателя
+func TestWebhookService_Run(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify("receiver-signing-key", r.Header.Get(webhook.SignatureHeader), body, time.Now(), time.Minute); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		received = append(received, r.Header.Get("X-Webhook-Delivery"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	repo := newMockwebhookRepository(t)
+	svc := NewWebhookService(repo, webhook.NewSender(time.Second, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}), WebhookOptions{
+		BatchSize:    2,
+		PollInterval: time.Hour,
+	}, newTestLogger())
+
+	d := webhookDelivery(0)
+	d.Webhook.URL = srv.URL
+	saved := make(chan domain.WebhookDeliveryStatus, 1)
+
+	repo.EXPECT().DeleteFinished(mock.Anything, mock.Anything).Return(0, nil).Maybe()
+	repo.EXPECT().Claim(mock.Anything, 2, 5*time.Minute).Return([]*domain.WebhookDelivery{d}, nil).Once()
+	repo.EXPECT().Claim(mock.Anything, 2, 5*time.Minute).Return(nil, nil).Maybe()
+	repo.EXPECT().SaveAttempt(mock.Anything, d.ID, mock.MatchedBy(func(a *domain.WebhookAttempt) bool {
+		return a.StatusCode == http.StatusOK && a.Error == ""
+	}), domain.WebhookDeliverySucceeded, time.Duration(0)).
+		RunAndReturn(func(_ context.Context, _ uuid.UUID, _ *domain.WebhookAttempt, status domain.WebhookDeliveryStatus, _ time.Duration) error {
+			saved <- status
+			return nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = svc.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case status := <-saved:
+		assert.Equal(t, domain.WebhookDeliverySucceeded, status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not attempted")
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{d.ID.String()}, received)
+}
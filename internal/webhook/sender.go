@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+)
+
+// maxResponseBody - сколько начала ответа получателя сохраняется в журнале доставок
+const maxResponseBody = 1024
+
+// ErrDestinationForbidden - адрес получателя внутренний (loopback, link-local, частная сеть) и не разрешён явно
+var ErrDestinationForbidden = errors.New("webhook destination is an internal address")
+
+// sharedAddressSpace - 100.64.0.0/10 (RFC 6598), адреса за NAT провайдера; netip.Addr.IsPrivate его не включает
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Sender отправляет доставку POST-запросом на адрес подписки.
+// Перенаправления не выполняются: ответ 3xx - неудачная попытка, адрес нужно исправить в подписке.
+// Адрес подписки задаёт пользователь, поэтому соединения с внутренними адресами запрещены:
+// проверяется IP, к которому идёт подключение после разрешения имени, так что имя,
+// указывающее на 127.0.0.1 или адрес из внутренней сети, не поможет.
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender - allowed: внутренние сети, куда доставка всё же разрешена (получатели внутри периметра)
+func NewSender(timeout time.Duration, allowed []netip.Prefix) *Sender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: destinationControl(allowed),
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// через прокси проверялся бы адрес прокси, а не получателя
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// ParseAllowedNetworks разбирает список сетей (CIDR) и отдельных адресов из конфигурации
+func ParseAllowedNetworks(list []string) ([]netip.Prefix, error) {
+	res := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("allowed network %q: %w", s, err)
+			}
+			res = append(res, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("allowed network %q: %w", s, err)
+		}
+		res = append(res, prefix.Masked())
+	}
+	return res, nil
+}
+
+// destinationControl вызывается для каждого подключения с уже разрешённым адресом
+func destinationControl(allowed []netip.Prefix) func(network, address string, _ syscall.RawConn) error {
+	return func(_, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip, err := netip.ParseAddr(host)
+		if err != nil {
+			return err
+		}
+		ip = ip.Unmap().WithZone("")
+		if isInternal(ip) && !containsAddr(allowed, ip) {
+			return fmt.Errorf("%w: %s", ErrDestinationForbidden, ip)
+		}
+		return nil
+	}
+}
+
+func isInternal(ip netip.Addr) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+func containsAddr(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Send выполняет одну попытку; ошибка сети или ответ не 2xx - в результате, не в error
+func (s *Sender) Send(ctx context.Context, hook *domain.Webhook, d *domain.WebhookDelivery) *domain.WebhookAttempt {
+	start := s.now()
+	attempt := &domain.WebhookAttempt{}
+	defer func() { attempt.Duration = s.now().Sub(start) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WarehouseControl-Webhook/1")
+	req.Header.Set("X-Webhook-ID", hook.ID.String())
+	req.Header.Set("X-Webhook-Delivery", d.ID.String())
+	req.Header.Set("X-Event-ID", d.EventID.String())
+	req.Header.Set("X-Event-Type", string(d.EventType))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, start, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt.Body = printable(body)
+	// остаток дочитывается, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if !attempt.Succeeded() {
+		attempt.Error = "receiver responded " + resp.Status
+	}
+	return attempt
+}
+
+// printable - тело ответа, пригодное для TEXT в PostgreSQL: без NUL и неверных UTF-8 последовательностей
+// (в том числе обрезанной на границе maxResponseBody)
+func printable(b []byte) string {
+	if !utf8.Valid(b) {
+		b = bytes.ToValidUTF8(b, []byte("�"))
+	}
+	return strings.ReplaceAll(string(b), "\x00", "")
+}
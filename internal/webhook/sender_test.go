@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stpnv0/WarehouseControl/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDelivery(hookURL string) (*domain.Webhook, *domain.WebhookDelivery) {
+	hook := &domain.Webhook{ID: uuid.New(), URL: hookURL, Secret: "receiver-signing-key"}
+	return hook, &domain.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: hook.ID,
+		EventID:   uuid.New(),
+		EventType: domain.OutboxItemLowStock,
+		Payload:   []byte(`{"type":"item.low_stock","data":{"item":{"quantity":2}}}`),
+	}
+}
+
+// loopback - тестовые получатели httptest.Server; в работе внутренние адреса разрешаются явно так же
+var testAllowed = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+
+func TestSender_Send(t *testing.T) {
+	var (
+		hook     *domain.Webhook
+		delivery *domain.WebhookDelivery
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, hook.ID.String(), r.Header.Get("X-Webhook-ID"))
+		assert.Equal(t, delivery.ID.String(), r.Header.Get("X-Webhook-Delivery"))
+		assert.Equal(t, delivery.EventID.String(), r.Header.Get("X-Event-ID"))
+		assert.Equal(t, "item.low_stock", r.Header.Get("X-Event-Type"))
+		assert.JSONEq(t, string(delivery.Payload), string(body))
+		assert.NoError(t, Verify(hook.Secret, r.Header.Get(SignatureHeader), body, time.Now(), time.Minute))
+
+		_, _ = io.WriteString(w, "accepted")
+	}))
+	defer srv.Close()
+
+	hook, delivery = testDelivery(srv.URL)
+	attempt := NewSender(time.Second, testAllowed).Send(context.Background(), hook, delivery)
+
+	assert.True(t, attempt.Succeeded(), attempt.Error)
+	assert.Equal(t, http.StatusOK, attempt.StatusCode)
+	assert.Equal(t, "accepted", attempt.Body)
+	assert.Positive(t, attempt.Duration)
+}
+
+func TestSender_Send_Failures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/error", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, strings.Repeat("x", 2*maxResponseBody)+"\x00")
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/error", http.StatusFound)
+	})
+	mux.HandleFunc("/binary", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte{'o', 'k', 0, 0xff, 0xfe})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	sender := NewSender(time.Second, testAllowed)
+
+	hook, d := testDelivery(srv.URL + "/error")
+	attempt := sender.Send(context.Background(), hook, d)
+	assert.False(t, attempt.Succeeded())
+	assert.Equal(t, http.StatusInternalServerError, attempt.StatusCode)
+	assert.Equal(t, "receiver responded 500 Internal Server Error", attempt.Error)
+	assert.Len(t, attempt.Body, maxResponseBody)
+
+	// перенаправление не выполняется
+	hook, d = testDelivery(srv.URL + "/redirect")
+	attempt = sender.Send(context.Background(), hook, d)
+	assert.False(t, attempt.Succeeded())
+	assert.Equal(t, http.StatusFound, attempt.StatusCode)
+
+	hook, d = testDelivery(srv.URL + "/binary")
+	attempt = sender.Send(context.Background(), hook, d)
+	assert.Equal(t, "ok�", attempt.Body, "body must be storable as PostgreSQL TEXT")
+
+	url := srv.URL
+	srv.Close()
+	hook, d = testDelivery(url)
+	attempt = sender.Send(context.Background(), hook, d)
+	require.False(t, attempt.Succeeded())
+	assert.Zero(t, attempt.StatusCode)
+	assert.NotEmpty(t, attempt.Error)
+}
+
+func TestSender_Send_InternalDestination(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	// имя разрешается в 127.0.0.1: проверяется адрес подключения, а не адрес из URL
+	hook, d := testDelivery(strings.Replace(srv.URL, "127.0.0.1", "localhost", 1))
+	attempt := NewSender(time.Second, nil).Send(context.Background(), hook, d)
+
+	assert.False(t, attempt.Succeeded())
+	assert.Zero(t, attempt.StatusCode)
+	assert.Contains(t, attempt.Error, ErrDestinationForbidden.Error())
+	assert.False(t, called, "request must not reach an internal receiver")
+
+	// разрешена другая внутренняя сеть - loopback по-прежнему запрещён
+	other := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	attempt = NewSender(time.Second, other).Send(context.Background(), hook, d)
+	assert.Contains(t, attempt.Error, ErrDestinationForbidden.Error())
+	assert.False(t, called)
+}
+
+func TestDestinationControl(t *testing.T) {
+	allowed, err := ParseAllowedNetworks([]string{"10.0.5.0/24", " 192.168.1.10 ", ""})
+	require.NoError(t, err)
+	check := destinationControl(allowed)
+
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1::1]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"0.0.0.0:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.11:80", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1%eth0]:80", false},
+		{"[fd00::1]:80", false},
+		{"100.64.0.1:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"10.0.5.7:8080", true},
+		{"192.168.1.10:80", true},
+		{"[::ffff:10.0.5.7]:80", true},
+	}
+	for _, tt := range tests {
+		err := check("tcp", tt.address, nil)
+		if tt.allowed {
+			assert.NoError(t, err, tt.address)
+		} else {
+			assert.ErrorIs(t, err, ErrDestinationForbidden, tt.address)
+		}
+	}
+
+	_, err = ParseAllowedNetworks([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseAllowedNetworks([]string{"intranet"})
+	assert.Error(t, err)
+}
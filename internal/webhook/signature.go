@@ -0,0 +1,83 @@
+// Package webhook - подпись и отправка исходящих webhook.
+//
+// Тело запроса - событие outbox в формате publisher.Envelope. Заголовок X-Webhook-Signature
+// имеет вид "t=<unix-время>,v1=<hex HMAC-SHA256>", где HMAC считается ключом подписки
+// от строки "<t>.<тело>": время в подписи не даёт переиграть старый запрос.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SignatureHeader = "X-Webhook-Signature"
+
+var (
+	ErrSignatureMissing  = errors.New("webhook signature is missing or malformed")
+	ErrSignatureMismatch = errors.New("webhook signature does not match")
+	ErrSignatureExpired  = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// Sign - значение заголовка X-Webhook-Signature для тела body, отправленного в момент ts
+func Sign(secret string, ts time.Time, body []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, body))
+}
+
+// Verify проверяет заголовок X-Webhook-Signature на стороне получателя.
+// tolerance - насколько время подписи может отличаться от now; 0 - не проверять.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var (
+		t    string
+		sigs []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+
+	ts, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrSignatureMissing
+	}
+
+	want := mac(secret, t, body)
+	matched := false
+	// подписей v1 может быть несколько - например, во время смены ключа
+	for _, sig := range sigs {
+		if decoded, err := hex.DecodeString(sig); err == nil && hmac.Equal(decoded, want) {
+			matched = true
+		}
+	}
+	if !matched {
+		return ErrSignatureMismatch
+	}
+	if tolerance > 0 {
+		if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+			return fmt.Errorf("%w: %s", ErrSignatureExpired, d.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+func mac(secret, t string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(t))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event_id":"e1","type":"item.created"}`)
+	now := time.Unix(1774512000, 0)
+	header := Sign("topsecret-signing-key", now, body)
+
+	assert.Regexp(t, `^t=1774512000,v1=[0-9a-f]{64}$`, header)
+	assert.NoError(t, Verify("topsecret-signing-key", header, body, now.Add(time.Minute), 5*time.Minute))
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr error
+	}{
+		{"other secret", "another-signing-key", header, body, now, ErrSignatureMismatch},
+		{"modified body", "topsecret-signing-key", header, []byte(`{"event_id":"e2"}`), now, ErrSignatureMismatch},
+		{"expired", "topsecret-signing-key", header, body, now.Add(10 * time.Minute), ErrSignatureExpired},
+		{"from future", "topsecret-signing-key", header, body, now.Add(-10 * time.Minute), ErrSignatureExpired},
+		{"no timestamp", "topsecret-signing-key", "v1=abcdef", body, now, ErrSignatureMissing},
+		{"no signature", "topsecret-signing-key", "t=1774512000", body, now, ErrSignatureMissing},
+		{"empty", "topsecret-signing-key", "", body, now, ErrSignatureMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, Verify(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute), tt.wantErr)
+		})
+	}
+}
+
+func TestVerify_KeyRotation(t *testing.T) {
+	body := []byte(`{}`)
+	now := time.Unix(1774512000, 0)
+	old := Sign("old-signing-key-0001", now, body)
+	current := Sign("new-signing-key-0002", now, body)
+
+	// получатель принимает заголовок, если совпала любая из подписей v1 - порядок частей не важен
+	header := current[len("t=1774512000,"):] + "," + old
+	assert.NoError(t, Verify("old-signing-key-0001", header, body, now, 0))
+	assert.NoError(t, Verify("new-signing-key-0002", header, body, now, 0))
+}
@@ -0,0 +1,6 @@
+-- +goose Up
+-- keyset-пагинация выгрузки каталога: ORDER BY created_at DESC, id DESC
+CREATE INDEX idx_items_created_at_id ON items (created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_created_at_id;
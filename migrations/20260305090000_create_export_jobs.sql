@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- ============================================================
+-- Фоновые выгрузки (POST /api/exports)
+-- ============================================================
+CREATE TABLE export_jobs (
+                             id          UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+                             type        VARCHAR(16)  NOT NULL CHECK (type IN ('audit', 'items')),
+                             format      VARCHAR(16)  NOT NULL,
+                             filter      JSONB        NOT NULL DEFAULT '{}',
+                             columns     TEXT[],
+                             status      VARCHAR(16)  NOT NULL DEFAULT 'pending'
+                                 CHECK (status IN ('pending', 'running', 'done', 'failed')),
+                             total_rows  BIGINT       NOT NULL DEFAULT 0,
+                             processed   BIGINT       NOT NULL DEFAULT 0,
+                             file_name   TEXT,
+                             file_size   BIGINT       NOT NULL DEFAULT 0,
+                             error       TEXT,
+                             created_by  UUID         NOT NULL,
+                             created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+                             started_at  TIMESTAMPTZ,
+                             finished_at TIMESTAMPTZ,
+                             expires_at  TIMESTAMPTZ
+);
+
+-- выборка очереди воркерами и поиск просроченных
+CREATE INDEX idx_export_jobs_pending ON export_jobs (created_at) WHERE status = 'pending';
+CREATE INDEX idx_export_jobs_expires ON export_jobs (expires_at) WHERE expires_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS export_jobs;
@@ -0,0 +1,111 @@
+-- +goose Up
+
+-- ============================================================
+-- Цепочка хешей аудита: каждая запись хранит хеш своего содержимого
+-- вместе с хешем предыдущей записи. Правка или удаление любой строки
+-- рвёт цепочку, что видно в GET /api/audit/verify и `warehouse audit-verify`.
+-- ============================================================
+ALTER TABLE item_audit_log
+    ADD COLUMN prev_hash CHAR(64),
+    ADD COLUMN hash      CHAR(64);
+
+-- Голова цепочки: последняя запись и её хеш. Единственная строка,
+-- блокировка которой упорядочивает вставки в item_audit_log
+CREATE TABLE audit_chain_head (
+                                  id        BOOLEAN   PRIMARY KEY DEFAULT TRUE CHECK (id),
+                                  last_id   BIGINT    NOT NULL DEFAULT 0,
+                                  last_hash CHAR(64)  NOT NULL
+);
+
+-- Хеш записи. Формат должен совпадать с auditchain.Hash в Go:
+-- поля через \n, changed_at - в микросекундах Unix, пустой JSONB - пустая строка
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_hash(
+    p_prev_hash  TEXT,
+    p_id         BIGINT,
+    p_item_id    UUID,
+    p_action     TEXT,
+    p_changed_by UUID,
+    p_changed_at TIMESTAMPTZ,
+    p_old_data   JSONB,
+    p_new_data   JSONB,
+    p_diff       JSONB
+) RETURNS TEXT AS $$
+SELECT encode(sha256(convert_to(concat_ws(E'\n',
+    p_prev_hash,
+    p_id::TEXT,
+    p_item_id::TEXT,
+    p_action,
+    p_changed_by::TEXT,
+    (extract(EPOCH FROM p_changed_at) * 1000000)::BIGINT::TEXT,
+    coalesce(p_old_data::TEXT, ''),
+    coalesce(p_new_data::TEXT, ''),
+    coalesce(p_diff::TEXT, '')
+), 'UTF8')), 'hex');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- Проставляем хеши уже существующим записям в порядке id
+-- +goose StatementBegin
+DO $$
+DECLARE
+    v_prev TEXT := repeat('0', 64);
+    v_last BIGINT := 0;
+    r      RECORD;
+BEGIN
+    FOR r IN SELECT * FROM item_audit_log ORDER BY id LOOP
+        UPDATE item_audit_log
+        SET prev_hash = v_prev,
+            hash      = fn_audit_hash(v_prev, r.id, r.item_id, r.action, r.changed_by,
+                                      r.changed_at, r.old_data, r.new_data, r.diff)
+        WHERE id = r.id
+        RETURNING hash INTO v_prev;
+        v_last := r.id;
+    END LOOP;
+
+    INSERT INTO audit_chain_head (last_id, last_hash) VALUES (v_last, v_prev);
+END;
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE item_audit_log
+    ALTER COLUMN prev_hash SET NOT NULL,
+    ALTER COLUMN hash      SET NOT NULL;
+
+-- Дописывает запись в цепочку. id выдаётся только под блокировкой головы,
+-- чтобы порядок id совпадал с порядком цепочки при параллельных транзакциях
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_chain() RETURNS TRIGGER AS $$
+DECLARE
+    v_prev TEXT;
+BEGIN
+    SELECT last_hash INTO v_prev FROM audit_chain_head FOR UPDATE;
+
+    NEW.id        := nextval('item_audit_log_id_seq');
+    NEW.prev_hash := v_prev;
+    NEW.hash      := fn_audit_hash(v_prev, NEW.id, NEW.item_id, NEW.action, NEW.changed_by,
+                                   NEW.changed_at, NEW.old_data, NEW.new_data, NEW.diff);
+
+    UPDATE audit_chain_head SET last_id = NEW.id, last_hash = NEW.hash;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE item_audit_log ALTER COLUMN id DROP DEFAULT;
+
+CREATE TRIGGER trg_audit_chain
+    BEFORE INSERT ON item_audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION fn_audit_chain();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_audit_chain ON item_audit_log;
+DROP FUNCTION IF EXISTS fn_audit_chain();
+ALTER TABLE item_audit_log
+    ALTER COLUMN id SET DEFAULT nextval('item_audit_log_id_seq');
+DROP FUNCTION IF EXISTS fn_audit_hash(TEXT, BIGINT, UUID, TEXT, UUID, TIMESTAMPTZ, JSONB, JSONB, JSONB);
+DROP TABLE IF EXISTS audit_chain_head;
+ALTER TABLE item_audit_log
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash;
@@ -0,0 +1,146 @@
+-- +goose Up
+-- Режим audit.mode: app - журнал пишет приложение в той же транзакции,
+-- триггер пропускает транзакции с app.audit_mode = 'app'
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_item_audit() RETURNS TRIGGER AS $$
+DECLARE
+    v_user_text TEXT;
+    v_user UUID;
+    v_old  JSONB;
+    v_new  JSONB;
+    v_diff JSONB;
+    k      TEXT;
+BEGIN
+    -- Журнал пишет приложение (audit.mode: app) - триггер не дублирует записи
+    IF current_setting('app.audit_mode', true) = 'app' THEN
+        RETURN NULL;
+    END IF;
+
+    -- Получаем ID пользователя
+    v_user_text := current_setting('app.current_user_id', true);
+
+    IF v_user_text IS NULL OR v_user_text = '' THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Session variable app.current_user_id is not set';
+    END IF;
+
+    BEGIN
+        v_user := v_user_text::UUID;
+    EXCEPTION WHEN invalid_text_representation THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Invalid UUID format in app.current_user_id: %', v_user_text;
+    END;
+
+    IF TG_OP = 'INSERT' THEN
+        v_new := to_jsonb(NEW);
+        INSERT INTO item_audit_log (item_id, action, changed_by, new_data)
+        VALUES (NEW.id, 'INSERT', v_user, v_new);
+        RETURN NEW;
+
+    ELSIF TG_OP = 'UPDATE' THEN
+        v_old  := to_jsonb(OLD);
+        v_new  := to_jsonb(NEW);
+        v_diff := '{}'::JSONB;
+
+        FOR k IN SELECT jsonb_object_keys(v_new)
+            LOOP
+                -- Пропускаем служебные поля
+                IF k IN ('id','updated_at', 'created_at') THEN
+                    CONTINUE;
+                END IF;
+
+                IF (v_old -> k) IS DISTINCT FROM (v_new -> k) THEN
+                    v_diff := v_diff || jsonb_build_object(
+                            k, jsonb_build_object('old', v_old -> k, 'new', v_new -> k)
+                                        );
+                END IF;
+            END LOOP;
+
+        -- Если ничего не изменилось — не пишем
+        IF v_diff = '{}'::JSONB THEN RETURN NEW;
+        END IF;
+
+        INSERT INTO item_audit_log (item_id, action, changed_by, old_data, new_data, diff)
+        VALUES (NEW.id, 'UPDATE', v_user, v_old, v_new, v_diff);
+
+        RETURN NEW;
+
+    ELSIF TG_OP = 'DELETE' THEN
+        v_old := to_jsonb(OLD);
+        INSERT INTO item_audit_log (item_id, action, changed_by, old_data)
+        VALUES (OLD.id, 'DELETE', v_user, v_old);
+        RETURN OLD;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_item_audit() RETURNS TRIGGER AS $$
+DECLARE
+    v_user_text TEXT;
+    v_user UUID;
+    v_old  JSONB;
+    v_new  JSONB;
+    v_diff JSONB;
+    k      TEXT;
+BEGIN
+    -- Получаем ID пользователя
+    v_user_text := current_setting('app.current_user_id', true);
+
+    IF v_user_text IS NULL OR v_user_text = '' THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Session variable app.current_user_id is not set';
+    END IF;
+
+    BEGIN
+        v_user := v_user_text::UUID;
+    EXCEPTION WHEN invalid_text_representation THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Invalid UUID format in app.current_user_id: %', v_user_text;
+    END;
+
+    IF TG_OP = 'INSERT' THEN
+        v_new := to_jsonb(NEW);
+        INSERT INTO item_audit_log (item_id, action, changed_by, new_data)
+        VALUES (NEW.id, 'INSERT', v_user, v_new);
+        RETURN NEW;
+
+    ELSIF TG_OP = 'UPDATE' THEN
+        v_old  := to_jsonb(OLD);
+        v_new  := to_jsonb(NEW);
+        v_diff := '{}'::JSONB;
+
+        FOR k IN SELECT jsonb_object_keys(v_new)
+            LOOP
+                -- Пропускаем служебные поля
+                IF k IN ('id','updated_at', 'created_at') THEN
+                    CONTINUE;
+                END IF;
+
+                IF (v_old -> k) IS DISTINCT FROM (v_new -> k) THEN
+                    v_diff := v_diff || jsonb_build_object(
+                            k, jsonb_build_object('old', v_old -> k, 'new', v_new -> k)
+                                        );
+                END IF;
+            END LOOP;
+
+        -- Если ничего не изменилось — не пишем
+        IF v_diff = '{}'::JSONB THEN RETURN NEW;
+        END IF;
+
+        INSERT INTO item_audit_log (item_id, action, changed_by, old_data, new_data, diff)
+        VALUES (NEW.id, 'UPDATE', v_user, v_old, v_new, v_diff);
+
+        RETURN NEW;
+
+    ELSIF TG_OP = 'DELETE' THEN
+        v_old := to_jsonb(OLD);
+        INSERT INTO item_audit_log (item_id, action, changed_by, old_data)
+        VALUES (OLD.id, 'DELETE', v_user, v_old);
+        RETURN OLD;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
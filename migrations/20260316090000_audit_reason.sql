@@ -0,0 +1,328 @@
+-- +goose Up
+
+-- ============================================================
+-- Причина изменения и ссылка на документ в журнале аудита.
+-- Приложение передаёт их в app.audit_reason / app.audit_reference.
+-- ============================================================
+ALTER TABLE audit_log ADD COLUMN reason TEXT;
+ALTER TABLE audit_log ADD COLUMN reference VARCHAR(128);
+
+CREATE INDEX idx_audit_reference ON audit_log (reference) WHERE reference IS NOT NULL;
+
+CREATE OR REPLACE VIEW item_audit_log AS
+SELECT id, entity_id AS item_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+       reason, reference
+FROM audit_log
+WHERE entity_type = 'item';
+
+DROP TRIGGER IF EXISTS trg_audit_chain ON audit_log;
+DROP FUNCTION IF EXISTS fn_audit_chain();
+DROP FUNCTION IF EXISTS fn_audit_hash(TEXT, BIGINT, TEXT, UUID, TEXT, UUID, TIMESTAMPTZ, JSONB, JSONB, JSONB);
+
+-- Хеш записи с причиной и ссылкой. Формат должен совпадать с auditchain.Hash в Go.
+-- Пустые reason/reference concat_ws пропускает, поэтому хеши старых записей не меняются,
+-- а префиксы не дают выдать причину за ссылку и наоборот
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_hash(
+    p_prev_hash   TEXT,
+    p_id          BIGINT,
+    p_entity_type TEXT,
+    p_entity_id   UUID,
+    p_action      TEXT,
+    p_changed_by  UUID,
+    p_changed_at  TIMESTAMPTZ,
+    p_old_data    JSONB,
+    p_new_data    JSONB,
+    p_diff        JSONB,
+    p_reason      TEXT,
+    p_reference   TEXT
+) RETURNS TEXT AS $$
+SELECT encode(sha256(convert_to(concat_ws(E'\n',
+    p_prev_hash,
+    p_id::TEXT,
+    p_entity_type,
+    p_entity_id::TEXT,
+    p_action,
+    p_changed_by::TEXT,
+    (extract(EPOCH FROM p_changed_at) * 1000000)::BIGINT::TEXT,
+    coalesce(p_old_data::TEXT, ''),
+    coalesce(p_new_data::TEXT, ''),
+    coalesce(p_diff::TEXT, ''),
+    'reason:' || p_reason,
+    'reference:' || p_reference
+), 'UTF8')), 'hex');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_chain() RETURNS TRIGGER AS $$
+DECLARE
+    v_prev TEXT;
+BEGIN
+    SELECT last_hash INTO v_prev FROM audit_chain_head FOR UPDATE;
+
+    NEW.id        := nextval('audit_log_id_seq');
+    NEW.prev_hash := v_prev;
+    NEW.hash      := fn_audit_hash(v_prev, NEW.id, NEW.entity_type, NEW.entity_id, NEW.action, NEW.changed_by,
+                                   NEW.changed_at, NEW.old_data, NEW.new_data, NEW.diff,
+                                   NEW.reason, NEW.reference);
+
+    UPDATE audit_chain_head SET last_id = NEW.id, last_hash = NEW.hash;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_audit_chain
+    BEFORE INSERT ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION fn_audit_chain();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_row() RETURNS TRIGGER AS $$
+DECLARE
+    v_user_text TEXT;
+    v_user UUID;
+    v_old  JSONB;
+    v_new  JSONB;
+    v_diff JSONB;
+    k      TEXT;
+    i      INT;
+BEGIN
+    -- Журнал пишет приложение (audit.mode: app) - триггер не дублирует записи
+    IF current_setting('app.audit_mode', true) = 'app' THEN
+        RETURN NULL;
+    END IF;
+
+    -- Получаем ID пользователя
+    v_user_text := current_setting('app.current_user_id', true);
+
+    IF v_user_text IS NULL OR v_user_text = '' THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Session variable app.current_user_id is not set';
+    END IF;
+
+    BEGIN
+        v_user := v_user_text::UUID;
+    EXCEPTION WHEN invalid_text_representation THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Invalid UUID format in app.current_user_id: %', v_user_text;
+    END;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        v_old := to_jsonb(OLD);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        v_new := to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        v_diff := '{}'::JSONB;
+
+        FOR k IN SELECT jsonb_object_keys(v_new)
+            LOOP
+                -- Пропускаем служебные поля
+                IF k IN ('id','updated_at', 'created_at') THEN
+                    CONTINUE;
+                END IF;
+
+                IF (v_old -> k) IS DISTINCT FROM (v_new -> k) THEN
+                    v_diff := v_diff || jsonb_build_object(
+                            k, jsonb_build_object('old', v_old -> k, 'new', v_new -> k)
+                                        );
+                END IF;
+            END LOOP;
+
+        -- Если ничего не изменилось — не пишем
+        IF v_diff = '{}'::JSONB THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    FOR i IN 1 .. TG_NARGS - 1
+        LOOP
+            k := TG_ARGV[i];
+            IF v_old ? k THEN
+                v_old := v_old || jsonb_build_object(k, '***');
+            END IF;
+            IF v_new ? k THEN
+                v_new := v_new || jsonb_build_object(k, '***');
+            END IF;
+            IF v_diff ? k THEN
+                v_diff := v_diff || jsonb_build_object(k, jsonb_build_object('old', '***', 'new', '***'));
+            END IF;
+        END LOOP;
+
+    INSERT INTO audit_log (entity_type, entity_id, action, changed_by, old_data, new_data, diff, reason, reference)
+    VALUES (TG_ARGV[0], COALESCE(v_new ->> 'id', v_old ->> 'id')::UUID, TG_OP, v_user, v_old, v_new, v_diff,
+            NULLIF(current_setting('app.audit_reason', true), ''),
+            NULLIF(current_setting('app.audit_reference', true), ''));
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_audit_chain ON audit_log;
+DROP FUNCTION IF EXISTS fn_audit_chain();
+DROP FUNCTION IF EXISTS fn_audit_hash(TEXT, BIGINT, TEXT, UUID, TEXT, UUID, TIMESTAMPTZ, JSONB, JSONB, JSONB, TEXT, TEXT);
+
+DROP VIEW IF EXISTS item_audit_log;
+DROP INDEX IF EXISTS idx_audit_reference;
+ALTER TABLE audit_log DROP COLUMN reference;
+ALTER TABLE audit_log DROP COLUMN reason;
+
+CREATE VIEW item_audit_log AS
+SELECT id, entity_id AS item_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash
+FROM audit_log
+WHERE entity_type = 'item';
+
+-- Записи с причиной хешировались с ней - без неё цепочка пересчитывается
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_hash(
+    p_prev_hash   TEXT,
+    p_id          BIGINT,
+    p_entity_type TEXT,
+    p_entity_id   UUID,
+    p_action      TEXT,
+    p_changed_by  UUID,
+    p_changed_at  TIMESTAMPTZ,
+    p_old_data    JSONB,
+    p_new_data    JSONB,
+    p_diff        JSONB
+) RETURNS TEXT AS $$
+SELECT encode(sha256(convert_to(concat_ws(E'\n',
+    p_prev_hash,
+    p_id::TEXT,
+    p_entity_type,
+    p_entity_id::TEXT,
+    p_action,
+    p_changed_by::TEXT,
+    (extract(EPOCH FROM p_changed_at) * 1000000)::BIGINT::TEXT,
+    coalesce(p_old_data::TEXT, ''),
+    coalesce(p_new_data::TEXT, ''),
+    coalesce(p_diff::TEXT, '')
+), 'UTF8')), 'hex');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+    v_prev TEXT := repeat('0', 64);
+    v_last BIGINT := 0;
+    r      RECORD;
+BEGIN
+    FOR r IN SELECT * FROM audit_log ORDER BY id LOOP
+        UPDATE audit_log
+        SET prev_hash = v_prev,
+            hash      = fn_audit_hash(v_prev, r.id, r.entity_type, r.entity_id, r.action, r.changed_by,
+                                      r.changed_at, r.old_data, r.new_data, r.diff)
+        WHERE id = r.id
+        RETURNING hash INTO v_prev;
+        v_last := r.id;
+    END LOOP;
+
+    UPDATE audit_chain_head SET last_id = v_last, last_hash = v_prev;
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_chain() RETURNS TRIGGER AS $$
+DECLARE
+    v_prev TEXT;
+BEGIN
+    SELECT last_hash INTO v_prev FROM audit_chain_head FOR UPDATE;
+
+    NEW.id        := nextval('audit_log_id_seq');
+    NEW.prev_hash := v_prev;
+    NEW.hash      := fn_audit_hash(v_prev, NEW.id, NEW.entity_type, NEW.entity_id, NEW.action, NEW.changed_by,
+                                   NEW.changed_at, NEW.old_data, NEW.new_data, NEW.diff);
+
+    UPDATE audit_chain_head SET last_id = NEW.id, last_hash = NEW.hash;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_audit_chain
+    BEFORE INSERT ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION fn_audit_chain();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_row() RETURNS TRIGGER AS $$
+DECLARE
+    v_user_text TEXT;
+    v_user UUID;
+    v_old  JSONB;
+    v_new  JSONB;
+    v_diff JSONB;
+    k      TEXT;
+    i      INT;
+BEGIN
+    IF current_setting('app.audit_mode', true) = 'app' THEN
+        RETURN NULL;
+    END IF;
+
+    v_user_text := current_setting('app.current_user_id', true);
+
+    IF v_user_text IS NULL OR v_user_text = '' THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Session variable app.current_user_id is not set';
+    END IF;
+
+    BEGIN
+        v_user := v_user_text::UUID;
+    EXCEPTION WHEN invalid_text_representation THEN
+        RAISE EXCEPTION 'Audit Trigger Error: Invalid UUID format in app.current_user_id: %', v_user_text;
+    END;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        v_old := to_jsonb(OLD);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        v_new := to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        v_diff := '{}'::JSONB;
+
+        FOR k IN SELECT jsonb_object_keys(v_new)
+            LOOP
+                IF k IN ('id','updated_at', 'created_at') THEN
+                    CONTINUE;
+                END IF;
+
+                IF (v_old -> k) IS DISTINCT FROM (v_new -> k) THEN
+                    v_diff := v_diff || jsonb_build_object(
+                            k, jsonb_build_object('old', v_old -> k, 'new', v_new -> k)
+                                        );
+                END IF;
+            END LOOP;
+
+        IF v_diff = '{}'::JSONB THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    FOR i IN 1 .. TG_NARGS - 1
+        LOOP
+            k := TG_ARGV[i];
+            IF v_old ? k THEN
+                v_old := v_old || jsonb_build_object(k, '***');
+            END IF;
+            IF v_new ? k THEN
+                v_new := v_new || jsonb_build_object(k, '***');
+            END IF;
+            IF v_diff ? k THEN
+                v_diff := v_diff || jsonb_build_object(k, jsonb_build_object('old', '***', 'new', '***'));
+            END IF;
+        END LOOP;
+
+    INSERT INTO audit_log (entity_type, entity_id, action, changed_by, old_data, new_data, diff)
+    VALUES (TG_ARGV[0], COALESCE(v_new ->> 'id', v_old ->> 'id')::UUID, TG_OP, v_user, v_old, v_new, v_diff);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
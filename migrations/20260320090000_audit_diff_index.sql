@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Поиск по полям diff: ?field= и условия на старое/новое значение (оператор ?)
+CREATE INDEX idx_audit_diff ON audit_log USING GIN (diff);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_audit_diff;
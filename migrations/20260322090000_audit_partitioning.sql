@@ -0,0 +1,177 @@
+-- +goose Up
+
+-- ============================================================
+-- Секционирование audit_log по месяцам changed_at и архивы секций.
+-- Старые секции приложение выгружает в файлы (JSON Lines + gzip)
+-- и отсоединяет; audit_archives хранит, что и куда выгружено,
+-- и отрезки цепочки хешей, ушедшие вместе с секцией.
+-- ============================================================
+DROP VIEW IF EXISTS item_audit_log;
+DROP TRIGGER IF EXISTS trg_audit_chain ON audit_log;
+
+ALTER TABLE audit_log RENAME TO audit_log_unpartitioned;
+-- id выдаёт fn_audit_chain(), последовательность переживает старую таблицу
+ALTER SEQUENCE audit_log_id_seq OWNED BY NONE;
+
+-- порядок колонок - как у прежней таблицы
+CREATE TABLE audit_log (
+                           id          BIGINT       NOT NULL,
+                           entity_id   UUID         NOT NULL,
+                           action      VARCHAR(10)  NOT NULL CHECK (action IN ('INSERT', 'UPDATE', 'DELETE')),
+                           changed_by  UUID         NOT NULL, -- без FK
+                           old_data    JSONB,
+                           new_data    JSONB,
+                           diff        JSONB,
+                           changed_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+                           prev_hash   CHAR(64)     NOT NULL,
+                           hash        CHAR(64)     NOT NULL,
+                           entity_type VARCHAR(32)  NOT NULL,
+                           reason      TEXT,
+                           reference   VARCHAR(128),
+                           request_id  VARCHAR(128),
+                           client_ip   VARCHAR(64),
+                           user_agent  VARCHAR(512),
+                           PRIMARY KEY (id, changed_at)
+) PARTITION BY RANGE (changed_at);
+
+-- Страховка: запись вне созданных секций не должна ронять изменение товара
+CREATE TABLE audit_log_default PARTITION OF audit_log DEFAULT;
+
+-- Секция audit_log_YYYY_MM за месяц p_month (границы - по UTC), если её ещё нет.
+-- Приложение создаёт секции на текущий и следующий месяц заранее
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_partition(p_month DATE) RETURNS TEXT AS $$
+DECLARE
+    v_from TIMESTAMP := date_trunc('month', p_month::TIMESTAMP);
+    v_name TEXT      := 'audit_log_' || to_char(v_from, 'YYYY_MM');
+BEGIN
+    IF to_regclass(v_name) IS NULL THEN
+        EXECUTE format('CREATE TABLE %I PARTITION OF audit_log FOR VALUES FROM (%L) TO (%L)',
+                       v_name, v_from AT TIME ZONE 'UTC', (v_from + INTERVAL '1 month') AT TIME ZONE 'UTC');
+    END IF;
+    RETURN v_name;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DO $$
+DECLARE
+    v_month DATE;
+BEGIN
+    FOR v_month IN
+        SELECT generate_series(
+                       date_trunc('month', COALESCE((SELECT min(changed_at) FROM audit_log_unpartitioned), now())
+                           AT TIME ZONE 'UTC'),
+                       date_trunc('month', now() AT TIME ZONE 'UTC') + INTERVAL '1 month',
+                       INTERVAL '1 month')::DATE
+        LOOP
+            PERFORM fn_audit_partition(v_month);
+        END LOOP;
+END;
+$$;
+-- +goose StatementEnd
+
+-- триггера цепочки на новой таблице ещё нет - id и хеши переносятся как есть
+INSERT INTO audit_log (id, entity_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+                       entity_type, reason, reference, request_id, client_ip, user_agent)
+SELECT id, entity_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+       entity_type, reason, reference, request_id, client_ip, user_agent
+FROM audit_log_unpartitioned;
+
+DROP TABLE audit_log_unpartitioned;
+
+CREATE INDEX idx_audit_changed_at ON audit_log (changed_at DESC);
+CREATE INDEX idx_audit_changed_by ON audit_log (changed_by);
+CREATE INDEX idx_audit_entity_changed ON audit_log (entity_type, entity_id, changed_at DESC);
+CREATE INDEX idx_audit_reference ON audit_log (reference) WHERE reference IS NOT NULL;
+CREATE INDEX idx_audit_request_id ON audit_log (request_id) WHERE request_id IS NOT NULL;
+CREATE INDEX idx_audit_diff ON audit_log USING GIN (diff);
+
+-- BEFORE-триггер секционированной таблицы копируется на каждую секцию, в том числе присоединённую позже
+CREATE TRIGGER trg_audit_chain
+    BEFORE INSERT ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION fn_audit_chain();
+
+CREATE VIEW item_audit_log AS
+SELECT id, entity_id AS item_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+       reason, reference, request_id, client_ip, user_agent
+FROM audit_log
+WHERE entity_type = 'item';
+
+-- Архивы секций. segments - непрерывные отрезки цепочки хешей из секции
+-- ([{first_id, last_id, rows, prev_hash, hash}]): по ним проверка цепочки
+-- переходит через записи, которых больше нет в БД
+CREATE TABLE audit_archives (
+                                id             BIGSERIAL    PRIMARY KEY,
+                                partition_name VARCHAR(63)  NOT NULL UNIQUE,
+                                range_from     TIMESTAMPTZ  NOT NULL,
+                                range_to       TIMESTAMPTZ  NOT NULL,
+                                file_name      TEXT         NOT NULL,
+                                file_size      BIGINT       NOT NULL,
+                                file_sha256    CHAR(64)     NOT NULL,
+                                row_count      BIGINT       NOT NULL,
+                                segments       JSONB        NOT NULL,
+                                status         VARCHAR(16)  NOT NULL CHECK (status IN ('archived', 'attached')),
+                                archived_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+                                attached_at    TIMESTAMPTZ,
+                                attached_by    UUID
+);
+
+-- +goose Down
+-- Записи из архивов со статусом archived в БД не возвращаются: перед откатом их нужно присоединить
+DROP VIEW IF EXISTS item_audit_log;
+DROP TRIGGER IF EXISTS trg_audit_chain ON audit_log;
+DROP TABLE IF EXISTS audit_archives;
+
+ALTER TABLE audit_log RENAME TO audit_log_partitioned;
+
+CREATE TABLE audit_log (
+                           id          BIGINT       NOT NULL,
+                           entity_id   UUID         NOT NULL,
+                           action      VARCHAR(10)  NOT NULL CHECK (action IN ('INSERT', 'UPDATE', 'DELETE')),
+                           changed_by  UUID         NOT NULL, -- без FK
+                           old_data    JSONB,
+                           new_data    JSONB,
+                           diff        JSONB,
+                           changed_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+                           prev_hash   CHAR(64)     NOT NULL,
+                           hash        CHAR(64)     NOT NULL,
+                           entity_type VARCHAR(32)  NOT NULL,
+                           reason      TEXT,
+                           reference   VARCHAR(128),
+                           request_id  VARCHAR(128),
+                           client_ip   VARCHAR(64),
+                           user_agent  VARCHAR(512)
+);
+
+INSERT INTO audit_log (id, entity_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+                       entity_type, reason, reference, request_id, client_ip, user_agent)
+SELECT id, entity_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+       entity_type, reason, reference, request_id, client_ip, user_agent
+FROM audit_log_partitioned;
+
+DROP TABLE audit_log_partitioned;
+DROP FUNCTION IF EXISTS fn_audit_partition(DATE);
+
+ALTER TABLE audit_log ADD PRIMARY KEY (id);
+ALTER SEQUENCE audit_log_id_seq OWNED BY audit_log.id;
+
+CREATE INDEX idx_audit_changed_at ON audit_log (changed_at DESC);
+CREATE INDEX idx_audit_changed_by ON audit_log (changed_by);
+CREATE INDEX idx_audit_entity_changed ON audit_log (entity_type, entity_id, changed_at DESC);
+CREATE INDEX idx_audit_reference ON audit_log (reference) WHERE reference IS NOT NULL;
+CREATE INDEX idx_audit_request_id ON audit_log (request_id) WHERE request_id IS NOT NULL;
+CREATE INDEX idx_audit_diff ON audit_log USING GIN (diff);
+
+CREATE TRIGGER trg_audit_chain
+    BEFORE INSERT ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION fn_audit_chain();
+
+CREATE VIEW item_audit_log AS
+SELECT id, entity_id AS item_id, action, changed_by, old_data, new_data, diff, changed_at, prev_hash, hash,
+       reason, reference, request_id, client_ip, user_agent
+FROM audit_log
+WHERE entity_type = 'item';
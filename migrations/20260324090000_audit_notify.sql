@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- ============================================================
+-- Уведомление о новых записях аудита для ленты /api/events.
+-- Триггер висит на audit_log, а не на items/users: запись журнала
+-- из fn_audit_row() и из приложения (audit.mode: app) уведомляет одинаково.
+-- Полезной нагрузки нет - слушатель сам дочитывает записи после
+-- последнего известного id; одинаковые NOTIFY одной транзакции
+-- PostgreSQL сливает в одно, пакетные изменения дают одно уведомление.
+-- Уведомление уходит при COMMIT, когда записи уже видны.
+-- ============================================================
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_notify() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('audit_log_changes', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_audit_notify
+    AFTER INSERT ON audit_log
+    FOR EACH STATEMENT
+EXECUTE FUNCTION fn_audit_notify();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_audit_notify ON audit_log;
+DROP FUNCTION IF EXISTS fn_audit_notify();
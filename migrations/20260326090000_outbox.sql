@@ -0,0 +1,33 @@
+-- +goose Up
+
+-- ============================================================
+-- Outbox событий для внешних систем (ERP, BI).
+-- Событие пишется в той же транзакции, что и изменение товара,
+-- поэтому уходит наружу тогда и только тогда, когда изменение
+-- зафиксировано. Релей приложения забирает события по порядку id,
+-- отправляет и отмечает published_at; неудачная отправка
+-- откладывается до next_attempt_at. Следующие события того же
+-- товара ждут, пока не уйдёт предыдущее.
+-- ============================================================
+CREATE TABLE outbox (
+    id              BIGSERIAL    PRIMARY KEY,
+    event_id        UUID         NOT NULL DEFAULT uuid_generate_v4() UNIQUE,
+    aggregate_type  VARCHAR(32)  NOT NULL,
+    aggregate_id    UUID         NOT NULL,
+    event_type      VARCHAR(64)  NOT NULL,
+    payload         JSONB        NOT NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    attempts        INT          NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    last_error      TEXT,
+    published_at    TIMESTAMPTZ
+);
+
+-- очередь релея и проверка «нет ли неотправленного события того же товара раньше»
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_pending_aggregate ON outbox (aggregate_id, id) WHERE published_at IS NULL;
+-- очистка отправленных
+CREATE INDEX idx_outbox_published ON outbox (published_at) WHERE published_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,53 @@
+-- +goose Up
+
+-- ============================================================
+-- Исходящие webhook. Релей outbox раскладывает каждое событие
+-- по активным подпискам на его тип (строка в webhook_deliveries
+-- на пару подписка-событие), воркеры приложения отправляют
+-- доставки с подписью HMAC-SHA256 и повторяют неудачные
+-- с нарастающим интервалом. webhook_deliveries - журнал доставок:
+-- результат последней попытки хранится в самой строке.
+-- ============================================================
+CREATE TABLE webhooks (
+    id          UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url         TEXT         NOT NULL,
+    event_types TEXT[]       NOT NULL,
+    secret      TEXT         NOT NULL,
+    active      BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_by  UUID         NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_deliveries (
+    id              UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    webhook_id      UUID         NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id        UUID         NOT NULL,
+    event_type      VARCHAR(64)  NOT NULL,
+    payload         JSONB        NOT NULL,
+    status          VARCHAR(16)  NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts        INT          NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ  DEFAULT now(),
+    response_status INT,
+    response_body   TEXT,
+    error           TEXT,
+    duration_ms     INT,
+    redelivery_of   UUID         REFERENCES webhook_deliveries (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ
+);
+
+-- релей outbox может отправить событие повторно - доставка для подписки создаётся один раз;
+-- повторные отправки вручную - отдельные строки
+CREATE UNIQUE INDEX uq_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id)
+    WHERE redelivery_of IS NULL;
+-- очередь воркеров
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+-- журнал доставок подписки и очистка старых
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_created ON webhook_deliveries (created_at) WHERE status <> 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
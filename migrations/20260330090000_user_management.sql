@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- ============================================================
+-- Управление пользователями. disabled_at - время отключения
+-- учётной записи (NULL - активна, вход запрещён у отключённых),
+-- password_changed_at - время последней смены пароля (NULL -
+-- пароль не менялся с создания). Колонки входят в to_jsonb(users)
+-- и попадают в журнал аудита как обычные поля.
+-- ============================================================
+ALTER TABLE users
+    ADD COLUMN disabled_at         TIMESTAMPTZ,
+    ADD COLUMN password_changed_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS password_changed_at,
+    DROP COLUMN IF EXISTS disabled_at;
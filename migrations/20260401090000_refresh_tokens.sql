@@ -0,0 +1,45 @@
+-- +goose Up
+
+-- ============================================================
+-- Refresh-токены. Сам токен не хранится - только SHA-256 от него.
+-- Токены одного входа образуют семейство (family_id): при обновлении
+-- старый помечается rotated_at и выдаётся следующий. Повторное
+-- предъявление уже обменянного токена означает утечку - отзывается
+-- всё семейство вместе с выданными с ним токенами доступа.
+-- access_jti / access_expires_at - токен доступа, выданный вместе
+-- с refresh-токеном: по нему находится сессия при выходе.
+-- ============================================================
+CREATE TABLE refresh_tokens (
+    id                UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id           UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id         UUID        NOT NULL,
+    token_hash        TEXT        NOT NULL UNIQUE,
+    access_jti        UUID        NOT NULL UNIQUE,
+    access_expires_at TIMESTAMPTZ NOT NULL,
+    expires_at        TIMESTAMPTZ NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rotated_at        TIMESTAMPTZ,
+    revoked_at        TIMESTAMPTZ
+);
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens (family_id);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens (user_id);
+CREATE INDEX idx_refresh_tokens_expires ON refresh_tokens (expires_at);
+
+-- ============================================================
+-- Отозванные токены доступа (denylist) по jti. Запись нужна, пока
+-- токен не истёк, - затем удаляется. Экземпляры приложения держат
+-- список в памяти и подтягивают новые записи по revoked_at.
+-- ============================================================
+CREATE TABLE revoked_tokens (
+    jti        UUID        PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_revoked_tokens_revoked_at ON revoked_tokens (revoked_at);
+CREATE INDEX idx_revoked_tokens_expires ON revoked_tokens (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,23 @@
+-- +goose Up
+
+-- ============================================================
+-- Ключи подписи токенов доступа (auth.signing.algorithm RS256/EdDSA).
+-- Ключи общие для всех экземпляров: private_key - PKCS #8 (DER),
+-- открытая часть публикуется в /.well-known/jwks.json.
+-- Ключ подписывает токены с activates_at до появления следующего;
+-- новый ключ создаётся заранее, чтобы экземпляры и потребители JWKS
+-- успели его получить. Заменённый ключ удаляется, когда истекли
+-- все подписанные им токены.
+-- ============================================================
+CREATE TABLE signing_keys (
+    kid          TEXT        PRIMARY KEY,
+    algorithm    TEXT        NOT NULL CHECK (algorithm IN ('RS256', 'EdDSA')),
+    private_key  BYTEA       NOT NULL,
+    activates_at TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_signing_keys_activates_at ON signing_keys (activates_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS signing_keys;
@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- ============================================================
+-- API-ключи для интеграций (заголовок X-API-Key). Ключ действует
+-- от имени владельца user_id: изменения попадают в журнал аудита
+-- как его. Сам ключ не хранится - только SHA-256 (key_hash);
+-- prefix - открытое начало ключа, по которому его узнают в списке.
+-- Права ключа - role, но не больше текущей роли владельца;
+-- read_only - только чтение.
+-- ============================================================
+CREATE TABLE api_keys (
+    id           UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name         VARCHAR(128) NOT NULL,
+    prefix       VARCHAR(32)  NOT NULL UNIQUE,
+    key_hash     CHAR(64)     NOT NULL UNIQUE,
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role         VARCHAR(16)  NOT NULL CHECK (role IN ('admin', 'manager', 'viewer')),
+    read_only    BOOLEAN      NOT NULL DEFAULT FALSE,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_by   UUID         NOT NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_keys;
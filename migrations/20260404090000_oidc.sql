@@ -0,0 +1,37 @@
+-- +goose Up
+
+-- ============================================================
+-- Единый вход через провайдера OpenID Connect. user_identities -
+-- привязка пользователя к учётной записи у провайдера (issuer, sub);
+-- создаётся при первом входе. У созданных так пользователей пустой
+-- password_hash: войти по паролю они не могут.
+-- ============================================================
+CREATE TABLE user_identities (
+    issuer        TEXT        NOT NULL,
+    subject       TEXT        NOT NULL,
+    user_id       UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities (user_id);
+
+-- ============================================================
+-- Начатые входы через провайдера: от перехода на его страницу до
+-- возврата с code. Запись одноразовая - удаляется при возврате;
+-- state не хранится, только SHA-256 от него.
+-- ============================================================
+CREATE TABLE oidc_login_states (
+    state_hash    CHAR(64)    PRIMARY KEY,
+    nonce         TEXT        NOT NULL,
+    code_verifier TEXT        NOT NULL,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_oidc_login_states_expires ON oidc_login_states (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,51 @@
+-- +goose Up
+
+-- ============================================================
+-- Записи, попавшие в audit_log_default, больше не мешают создать секцию.
+-- Раньше CREATE TABLE ... PARTITION OF падал навсегда, если в секции
+-- по умолчанию уже были строки за этот месяц. Теперь в одной транзакции
+-- секция создаётся отдельной таблицей, строки месяца переносятся в неё
+-- из audit_log_default как есть (id и хеши не меняются, триггер цепочки
+-- на отдельной таблице не срабатывает), и таблица присоединяется.
+-- ============================================================
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_partition(p_month DATE) RETURNS TEXT AS $$
+DECLARE
+    v_from TIMESTAMP   := date_trunc('month', p_month::TIMESTAMP);
+    v_name TEXT        := 'audit_log_' || to_char(v_from, 'YYYY_MM');
+    v_lo   TIMESTAMPTZ := v_from AT TIME ZONE 'UTC';
+    v_hi   TIMESTAMPTZ := (v_from + INTERVAL '1 month') AT TIME ZONE 'UTC';
+BEGIN
+    IF to_regclass(v_name) IS NOT NULL THEN
+        RETURN v_name;
+    END IF;
+
+    -- новые записи не должны попасть в секцию по умолчанию между переносом и присоединением
+    LOCK TABLE audit_log_default IN EXCLUSIVE MODE;
+
+    EXECUTE format('CREATE TABLE %I (LIKE audit_log INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', v_name);
+    EXECUTE format('WITH moved AS (DELETE FROM audit_log_default WHERE changed_at >= $1 AND changed_at < $2 RETURNING *)
+                    INSERT INTO %I SELECT * FROM moved', v_name)
+        USING v_lo, v_hi;
+    EXECUTE format('ALTER TABLE audit_log ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+                   v_name, v_lo, v_hi);
+    RETURN v_name;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION fn_audit_partition(p_month DATE) RETURNS TEXT AS $$
+DECLARE
+    v_from TIMESTAMP := date_trunc('month', p_month::TIMESTAMP);
+    v_name TEXT      := 'audit_log_' || to_char(v_from, 'YYYY_MM');
+BEGIN
+    IF to_regclass(v_name) IS NULL THEN
+        EXECUTE format('CREATE TABLE %I PARTITION OF audit_log FOR VALUES FROM (%L) TO (%L)',
+                       v_name, v_from AT TIME ZONE 'UTC', (v_from + INTERVAL '1 month') AT TIME ZONE 'UTC');
+    END IF;
+    RETURN v_name;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
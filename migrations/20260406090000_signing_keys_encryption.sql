@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- ============================================================
+-- Закрытые ключи подписи шифруются ключом auth.signing.encryption_key
+-- (AES-256-GCM): копия БД или её резервная копия больше не позволяет
+-- подписывать токены. Ключи, созданные раньше, остаются с encrypted = false
+-- и шифруются приложением при первой загрузке.
+-- ============================================================
+ALTER TABLE signing_keys ADD COLUMN encrypted BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+-- прежняя версия не расшифрует ключи - она создаст новый при старте
+DELETE FROM signing_keys WHERE encrypted;
+ALTER TABLE signing_keys DROP COLUMN IF EXISTS encrypted;
@@ -5,6 +5,7 @@
    ═══════════════════════════════════════════════════════════════════════ */
 const state = {
     token: localStorage.getItem('wc_token') || '',
+    refreshToken: localStorage.getItem('wc_refresh_token') || '',
     user: JSON.parse(localStorage.getItem('wc_user') || 'null'),
     items: [],
     selectedItemId: null,
@@ -13,6 +14,24 @@ const state = {
     pageSize: 20,
 };
 
+// Лента изменений /api/events
+const live = {
+    ctrl: null,      // AbortController текущего потока
+    lastId: 0,       // id последнего события - Last-Event-ID при переподключении
+    retry: 0,
+    timers: {},
+};
+
+// Канал присутствия /api/ws/presence: кто ещё открыл редактируемый товар
+const presence = {
+    ws: null,
+    retry: 0,
+    timer: null,
+    active: false,
+    editingId: null, // товар в форме редактирования
+    renew: false,    // сервер закрыл канал из-за токена - перед переподключением продлить сессию
+};
+
 /* ═══════════════════════════════════════════════════════════════════════
    Utility Helpers
    ═══════════════════════════════════════════════════════════════════════ */
@@ -58,9 +77,10 @@ function actionBadge(action) {
 /* ═══════════════════════════════════════════════════════════════════════
    API Helper
    ═══════════════════════════════════════════════════════════════════════ */
-async function api(method, path, body) {
+async function api(method, path, body, retry = true) {
     const opts = { method, headers: {} };
-    if (state.token) opts.headers['Authorization'] = 'Bearer ' + state.token;
+    const authorized = Boolean(state.token);
+    if (authorized) opts.headers['Authorization'] = 'Bearer ' + state.token;
     if (body !== undefined) {
         opts.headers['Content-Type'] = 'application/json';
         opts.body = JSON.stringify(body);
@@ -69,7 +89,9 @@ async function api(method, path, body) {
     const res = await fetch(path, opts);
 
     if (res.status === 204) return null;
-    if (res.status === 401) {
+    if (res.status === 401 && authorized) {
+        // токен доступа короткий - пробуем продлить сессию и повторить запрос один раз
+        if (retry && await refreshSession()) return api(method, path, body, false);
         logout();
         throw new Error('Session expired');
     }
@@ -84,45 +106,109 @@ async function api(method, path, body) {
 /* ═══════════════════════════════════════════════════════════════════════
    Auth
    ═══════════════════════════════════════════════════════════════════════ */
-async function loadUsers() {
+function saveSession(data) {
+    state.token = data.token;
+    state.refreshToken = data.refresh_token;
+    state.user = data.user;
+    localStorage.setItem('wc_token', data.token);
+    localStorage.setItem('wc_refresh_token', data.refresh_token);
+    localStorage.setItem('wc_user', JSON.stringify(data.user));
+}
+
+async function login(username, password) {
     try {
-        const users = await api('GET', '/api/auth/users');
-        const sel = $('#userSelect');
-        sel.innerHTML = '<option value="">— Select a user —</option>';
-        (users || []).forEach(u => {
-            const o = document.createElement('option');
-            o.value = u.username;
-            o.textContent = `${u.username} (${u.role})`;
-            sel.appendChild(o);
-        });
+        saveSession(await api('POST', '/api/auth/login', { username, password }));
+        enterApp();
     } catch (e) {
-        showToast('Failed to load users: ' + e.message, 'error');
+        showToast('Login failed: ' + e.message, 'error');
     }
 }
 
-async function login(username, password) {
+// Единый вход: кнопка показывается, только если на сервере настроен провайдер
+async function initSSO() {
+    const res = await fetch('/api/auth/oidc').catch(() => null);
+    if (!res || !res.ok) return;
+    const { name } = await res.json();
+    const btn = $('#ssoBtn');
+    btn.textContent = 'Sign in with ' + name;
+    btn.style.display = '';
+}
+
+async function startSSO() {
     try {
-        const data = await api('POST', '/api/auth/login', { username, password });
-        state.token = data.token;
-        state.user = data.user;
-        localStorage.setItem('wc_token', data.token);
-        localStorage.setItem('wc_user', JSON.stringify(data.user));
+        const { auth_url } = await api('POST', '/api/auth/oidc/login');
+        window.location.assign(auth_url);
+    } catch (e) {
+        showToast('SSO login failed: ' + e.message, 'error');
+    }
+}
+
+// Возврат со страницы провайдера: /?code=...&state=... или /?error=...
+async function completeSSO(params) {
+    // code одноразовый - убираем его из адресной строки и истории
+    history.replaceState(null, '', window.location.pathname);
+    if (params.get('error')) {
+        showToast('SSO login failed: ' + (params.get('error_description') || params.get('error')), 'error');
+        return;
+    }
+    try {
+        saveSession(await api('POST', '/api/auth/oidc/callback', {
+            code: params.get('code'),
+            state: params.get('state'),
+        }));
         enterApp();
     } catch (e) {
-        showToast('Login failed: ' + e.message, 'error');
+        showToast('SSO login failed: ' + e.message, 'error');
+    }
+}
+
+// Обмен refresh-токена на новую пару. Refresh-токен одноразовый, поэтому
+// одновременные запросы с 401 ждут один общий обмен
+let refreshing = null;
+function refreshSession() {
+    if (!state.refreshToken) return Promise.resolve(false);
+    if (!refreshing) {
+        refreshing = fetch('/api/auth/refresh', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ refresh_token: state.refreshToken }),
+        })
+            .then(async res => {
+                if (!res.ok) return false;
+                saveSession(await res.json());
+                return true;
+            })
+            .catch(() => false)
+            .finally(() => { refreshing = null; });
+    }
+    return refreshing;
+}
+
+// Выход по кнопке: сессия отзывается и на сервере
+async function signOut() {
+    if (state.token) {
+        await fetch('/api/auth/logout', {
+            method: 'POST',
+            headers: { 'Authorization': 'Bearer ' + state.token },
+        }).catch(() => {});
     }
+    logout();
 }
 
 function logout() {
+    stopEvents();
+    stopPresence();
     state.token = '';
+    state.refreshToken = '';
     state.user = null;
     state.items = [];
     state.selectedItemId = null;
     localStorage.removeItem('wc_token');
+    localStorage.removeItem('wc_refresh_token');
     localStorage.removeItem('wc_user');
+    $('#passwordInput').value = '';
     $('#loginScreen').style.display = '';
     $('#appShell').classList.remove('active');
-    loadUsers();
 }
 
 function enterApp() {
@@ -146,6 +232,8 @@ function enterApp() {
     $('#historyTab').style.display = canAudit ? '' : 'none';
 
     loadItems();
+    startEvents();
+    startPresence();
 }
 
 /* ═══════════════════════════════════════════════════════════════════════
@@ -231,6 +319,8 @@ function openAddModal() {
     $('#fieldQuantity').value = '';
     $('#fieldPrice').value = '';
     $('#fieldLocation').value = '';
+    $('#fieldReason').value = '';
+    $('#fieldReference').value = '';
     $('#itemModal').style.display = '';
 }
 
@@ -244,7 +334,10 @@ async function openEditModal(id) {
         $('#fieldQuantity').value = item.quantity;
         $('#fieldPrice').value = item.price;
         $('#fieldLocation').value = item.location || '';
+        $('#fieldReason').value = '';
+        $('#fieldReference').value = '';
         $('#itemModal').style.display = '';
+        presenceEdit(item.id);
     } catch (e) {
         showToast('Failed to load item: ' + e.message, 'error');
     }
@@ -252,6 +345,24 @@ async function openEditModal(id) {
 
 function closeItemModal() {
     $('#itemModal').style.display = 'none';
+    presenceLeave();
+}
+
+// подтягивает в форму текущие значения товара, изменённого другим пользователем; причина остаётся
+async function reloadEditedItem() {
+    const id = $('#itemEditId').value;
+    if (!id) return;
+    try {
+        const item = await api('GET', `/api/items/${id}`);
+        $('#fieldName').value = item.name;
+        $('#fieldSku').value = item.sku;
+        $('#fieldQuantity').value = item.quantity;
+        $('#fieldPrice').value = item.price;
+        $('#fieldLocation').value = item.location || '';
+        $('#itemConflict').style.display = 'none';
+    } catch (e) {
+        showToast('Failed to load item: ' + e.message, 'error');
+    }
 }
 
 async function saveItem() {
@@ -262,6 +373,8 @@ async function saveItem() {
         quantity: parseInt($('#fieldQuantity').value, 10) || 0,
         price: $('#fieldPrice').value,
         location: $('#fieldLocation').value.trim() || null,
+        reason: $('#fieldReason').value.trim(),
+        reference: $('#fieldReference').value.trim(),
     };
 
     if (!payload.name || !payload.sku) {
@@ -293,6 +406,7 @@ function openDeleteConfirm(id) {
     pendingDeleteId = id;
     $('#confirmMessage').textContent =
         `Are you sure you want to delete "${item ? item.name : 'this item'}"? This action cannot be undone.`;
+    $('#deleteReason').value = '';
     $('#confirmModal').style.display = '';
 }
 
@@ -304,7 +418,9 @@ function closeConfirmModal() {
 async function confirmDelete() {
     if (!pendingDeleteId) return;
     try {
-        await api('DELETE', `/api/items/${pendingDeleteId}`);
+        const reason = $('#deleteReason').value.trim();
+        const query = reason ? `?reason=${encodeURIComponent(reason)}` : '';
+        await api('DELETE', `/api/items/${pendingDeleteId}${query}`);
         showToast('Item deleted', 'success');
         if (state.selectedItemId === pendingDeleteId) {
             state.selectedItemId = null;
@@ -320,7 +436,7 @@ async function confirmDelete() {
 /* ═══════════════════════════════════════════════════════════════════════
    Item History (inline panel)
    ═══════════════════════════════════════════════════════════════════════ */
-async function loadItemHistory(id) {
+async function loadItemHistory(id, scroll = true) {
     const item = state.items.find(i => i.id === id);
     $('#itemHistoryTitle').textContent = 'History — ' + (item ? item.name : id);
     $('#itemHistoryPanel').style.display = '';
@@ -347,13 +463,25 @@ async function loadItemHistory(id) {
     }
 
     // Scroll into view
-    $('#itemHistoryPanel').scrollIntoView({ behavior: 'smooth', block: 'nearest' });
+    if (scroll) $('#itemHistoryPanel').scrollIntoView({ behavior: 'smooth', block: 'nearest' });
 }
 
 /* ═══════════════════════════════════════════════════════════════════════
    Diff Rendering
    ═══════════════════════════════════════════════════════════════════════ */
 function renderDiff(entry) {
+    return renderChanges(entry) + renderReason(entry);
+}
+
+function renderReason(entry) {
+    if (!entry.reason && !entry.reference) return '';
+    const parts = [];
+    if (entry.reason) parts.push(escHtml(entry.reason));
+    if (entry.reference) parts.push(`<code>${escHtml(entry.reference)}</code>`);
+    return `<div class="text-muted">${parts.join(' · ')}</div>`;
+}
+
+function renderChanges(entry) {
     if (entry.action === 'INSERT') {
         return '<div class="diff-block"><span class="diff-added">Created</span></div>';
     }
@@ -385,12 +513,16 @@ function renderDiff(entry) {
 async function loadAudit(page = 1) {
     state.auditPage = page;
 
+    const entity = $('#filterEntity').value;
     const action = $('#filterAction').value;
+    const field = $('#filterField').value.trim();
     const dateFrom = $('#filterDateFrom').value;
     const dateTo = $('#filterDateTo').value;
 
     let url = `/api/audit?page=${page}&page_size=${state.pageSize}`;
+    if (entity) url += `&entity_type=${entity}`;
     if (action) url += `&action=${action}`;
+    if (field) url += `&field=${encodeURIComponent(field)}`;
     if (dateFrom) url += `&date_from=${dateFrom}T00:00:00Z`;
     if (dateTo) url += `&date_to=${dateTo}T23:59:59Z`;
 
@@ -410,7 +542,7 @@ async function loadAudit(page = 1) {
         tbody.innerHTML = entries.map(e => `
             <tr>
                 <td>${actionBadge(e.action)}</td>
-                <td><code>${escHtml((e.item_id || '').substring(0, 8))}…</code></td>
+                <td>${escHtml(e.entity_type)} <code>${escHtml((e.entity_id || '').substring(0, 8))}…</code></td>
                 <td>${escHtml(e.username || '—')}</td>
                 <td>${formatDate(e.changed_at)}</td>
                 <td>${renderDiff(e)}</td>
@@ -424,14 +556,67 @@ async function loadAudit(page = 1) {
     }
 }
 
+/* ─── Audit Stats ──────────────────────────────────────────────────── */
+function statsTable(title, head, rows) {
+    const body = rows.length
+        ? rows.join('')
+        : `<tr><td colspan="${head.length}" class="empty-state">No data</td></tr>`;
+    return `
+        <div>
+            <h3>${title}</h3>
+            <table>
+                <thead><tr>${head.map(h => `<th>${h}</th>`).join('')}</tr></thead>
+                <tbody>${body}</tbody>
+            </table>
+        </div>`;
+}
+
+async function loadAuditStats() {
+    const dateFrom = $('#filterDateFrom').value;
+    const dateTo = $('#filterDateTo').value;
+
+    const params = [];
+    if (dateFrom) params.push(`date_from=${dateFrom}T00:00:00Z`);
+    if (dateTo) params.push(`date_to=${dateTo}T23:59:59Z`);
+
+    const box = $('#auditStats');
+    box.innerHTML = '<div class="empty-state"><div class="spinner spinner-dark"></div></div>';
+
+    try {
+        const s = await api('GET', `/api/audit/stats?${params.join('&')}`);
+
+        $('#statsPeriod').textContent = `${formatDate(s.date_from)} — ${formatDate(s.date_to)}`;
+        box.innerHTML = [
+            statsTable('Changes per day', ['Day', 'Insert', 'Update', 'Delete'], s.daily.map(d => `
+                <tr><td>${escHtml(d.day)}</td><td class="num">${d.inserts}</td>
+                <td class="num">${d.updates}</td><td class="num">${d.deletes}</td></tr>`)),
+            statsTable('Most active users', ['User', 'Changes'], s.top_users.map(u => `
+                <tr><td>${escHtml(u.username)}</td><td class="num">${u.changes}</td></tr>`)),
+            statsTable('Most changed items', ['Item', 'Changes'], s.top_items.map(i => `
+                <tr><td>${escHtml(i.name)}</td><td class="num">${i.changes}</td></tr>`)),
+            statsTable('Most changed fields', ['Field', 'Changes'], s.top_fields.map(f => `
+                <tr><td>${escHtml(f.field)}</td><td class="num">${f.changes}</td></tr>`)),
+            statsTable('Net quantity change', ['Item', 'Net'], s.quantity_changes.map(q => `
+                <tr><td>${escHtml(q.name)}</td><td class="num">${q.net > 0 ? '+' : ''}${q.net}</td></tr>`)),
+        ].join('');
+    } catch (e) {
+        $('#statsPeriod').textContent = '';
+        box.innerHTML = '<div class="empty-state">Failed to load statistics</div>';
+    }
+}
+
 /* ─── CSV Export ───────────────────────────────────────────────────── */
 function exportCsv() {
+    const entity = $('#filterEntity').value;
     const action = $('#filterAction').value;
+    const field = $('#filterField').value.trim();
     const dateFrom = $('#filterDateFrom').value;
     const dateTo = $('#filterDateTo').value;
 
     const params = [];
+    if (entity) params.push(`entity_type=${entity}`);
     if (action) params.push(`action=${action}`);
+    if (field) params.push(`field=${encodeURIComponent(field)}`);
     if (dateFrom) params.push(`date_from=${dateFrom}T00:00:00Z`);
     if (dateTo) params.push(`date_to=${dateTo}T23:59:59Z`);
 
@@ -496,6 +681,275 @@ function renderPagination(containerId, data, loadFn) {
     });
 }
 
+/* ═══════════════════════════════════════════════════════════════════════
+   Live Updates (Server-Sent Events)
+   ═══════════════════════════════════════════════════════════════════════ */
+// EventSource не умеет заголовок Authorization, поэтому поток читается через fetch
+function startEvents() {
+    stopEvents();
+    live.retry = 0;
+    live.ctrl = new AbortController();
+    readEvents(live.ctrl.signal);
+}
+
+function stopEvents() {
+    if (live.ctrl) live.ctrl.abort();
+    live.ctrl = null;
+    live.lastId = 0;
+    Object.values(live.timers).forEach(clearTimeout);
+    live.timers = {};
+    setLiveStatus(false);
+}
+
+async function readEvents(signal) {
+    while (!signal.aborted) {
+        try {
+            const headers = { 'Authorization': 'Bearer ' + state.token };
+            if (live.lastId) headers['Last-Event-ID'] = String(live.lastId);
+
+            const res = await fetch('/api/events', { headers, signal });
+            if (res.status === 401) {
+                if (await refreshSession()) continue;
+                logout();
+                return;
+            }
+            if (!res.ok || !res.body) throw new Error(`Request failed (${res.status})`);
+
+            live.retry = 0;
+            setLiveStatus(true);
+
+            const reader = res.body.pipeThrough(new TextDecoderStream()).getReader();
+            let buf = '';
+            for (;;) {
+                const { value, done } = await reader.read();
+                if (done) break;
+                buf += value.replace(/\r\n?/g, '\n');
+                let end;
+                while ((end = buf.indexOf('\n\n')) >= 0) {
+                    handleEventFrame(buf.slice(0, end));
+                    buf = buf.slice(end + 2);
+                }
+            }
+        } catch (e) {
+            if (signal.aborted) return;
+        }
+
+        // поток оборвался: переподключение с Last-Event-ID досылает пропущенное
+        setLiveStatus(false);
+        const delay = Math.min(30000, 1000 * 2 ** live.retry++);
+        await new Promise(r => setTimeout(r, delay));
+    }
+}
+
+function handleEventFrame(frame) {
+    let id = '', event = 'message', data = '';
+    for (const line of frame.split('\n')) {
+        if (!line || line.startsWith(':')) continue;
+        const i = line.indexOf(':');
+        const field = i < 0 ? line : line.slice(0, i);
+        let value = i < 0 ? '' : line.slice(i + 1);
+        if (value.startsWith(' ')) value = value.slice(1);
+
+        if (field === 'id') id = value;
+        else if (field === 'event') event = value;
+        else if (field === 'data') data += (data ? '\n' : '') + value;
+    }
+    if (id) live.lastId = Number(id);
+
+    if (event === 'reset') {
+        refreshVisible();
+        return;
+    }
+    if (!data) return;
+
+    let ev;
+    try {
+        ev = JSON.parse(data);
+    } catch (e) {
+        return;
+    }
+
+    if (event === 'item') applyItemEvent(ev);
+    if ($('#panelHistory').classList.contains('active') && state.auditPage === 1) {
+        scheduleRefresh('audit', () => loadAudit(1));
+    }
+}
+
+// UPDATE применяется к строке на месте, создание и удаление перечитывают страницу:
+// меняется состав страницы и пагинация
+function applyItemEvent(ev) {
+    const idx = state.items.findIndex(i => i.id === ev.entity_id);
+
+    if (ev.action === 'UPDATE' && idx >= 0 && ev.new_data) {
+        const snap = ev.new_data;
+        const item = state.items[idx];
+        ['name', 'sku', 'quantity', 'price', 'location', 'updated_at'].forEach(k => {
+            if (k in snap) item[k] = snap[k];
+        });
+        renderItems();
+        const row = $(`#itemsBody tr[data-id="${ev.entity_id}"]`);
+        if (row) row.classList.add('row-flash');
+    } else if (ev.action !== 'UPDATE') {
+        scheduleRefresh('items', () => loadItems(state.currentPage));
+    }
+
+    if (state.selectedItemId === ev.entity_id) {
+        if (ev.action === 'DELETE') {
+            state.selectedItemId = null;
+            $('#itemHistoryPanel').style.display = 'none';
+        } else {
+            scheduleRefresh('itemHistory', () => loadItemHistory(ev.entity_id, false));
+        }
+    }
+}
+
+// пачка событий (импорт, пакетное изменение) даёт одно обновление
+function scheduleRefresh(key, fn) {
+    clearTimeout(live.timers[key]);
+    live.timers[key] = setTimeout(() => {
+        delete live.timers[key];
+        fn();
+    }, 300);
+}
+
+function refreshVisible() {
+    scheduleRefresh('items', () => loadItems(state.currentPage));
+    if ($('#panelHistory').classList.contains('active')) {
+        scheduleRefresh('audit', () => loadAudit(state.auditPage));
+    }
+}
+
+function setLiveStatus(on) {
+    const el = $('#liveStatus');
+    if (!el) return;
+    el.classList.toggle('live-on', on);
+    el.title = on ? 'Live updates connected' : 'Live updates disconnected';
+}
+
+/* ═══════════════════════════════════════════════════════════════════════
+   Presence (WebSocket)
+   ═══════════════════════════════════════════════════════════════════════ */
+// браузер не задаёт заголовки WebSocket, поэтому токен передаётся подпротоколом "bearer"
+function startPresence() {
+    stopPresence();
+    presence.active = true;
+    presence.retry = 0;
+    connectPresence();
+}
+
+function stopPresence() {
+    presence.active = false;
+    presence.editingId = null;
+    clearTimeout(presence.timer);
+    if (presence.ws) presence.ws.close();
+    presence.ws = null;
+}
+
+function connectPresence() {
+    const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const ws = new WebSocket(`${proto}//${location.host}/api/ws/presence`, ['bearer', state.token]);
+    presence.ws = ws;
+
+    ws.onopen = () => {
+        presence.retry = 0;
+        // после переподключения сервер ничего не помнит - форма открывается заново
+        if (presence.editingId) presenceSend({ type: 'editing', item_id: presence.editingId, editing: true });
+    };
+    ws.onmessage = e => {
+        try {
+            handlePresenceMessage(JSON.parse(e.data));
+        } catch (err) {
+            // битое сообщение пропускается
+        }
+    };
+    ws.onclose = async () => {
+        if (presence.ws !== ws || !presence.active) return;
+        presence.ws = null;
+        if (presence.renew) {
+            presence.renew = false;
+            if (!(await refreshSession())) {
+                logout();
+                return;
+            }
+            if (!presence.active) return;
+        }
+        const delay = Math.min(30000, 1000 * 2 ** presence.retry++);
+        presence.timer = setTimeout(connectPresence, delay);
+    };
+}
+
+function presenceSend(msg) {
+    if (presence.ws && presence.ws.readyState === WebSocket.OPEN) {
+        presence.ws.send(JSON.stringify(msg));
+    }
+}
+
+function presenceEdit(id) {
+    presenceLeave();
+    presence.editingId = id;
+    presenceSend({ type: 'editing', item_id: id, editing: true });
+}
+
+function presenceLeave() {
+    if (presence.editingId) presenceSend({ type: 'unsubscribe', item_id: presence.editingId });
+    presence.editingId = null;
+    $('#itemPresence').style.display = 'none';
+    $('#itemConflict').style.display = 'none';
+    $('#itemModalSave').disabled = false;
+}
+
+function handlePresenceMessage(msg) {
+    switch (msg.type) {
+        case 'ping':
+            presenceSend({ type: 'pong' });
+            break;
+        case 'presence':
+            if (msg.item_id === presence.editingId) renderPresence(msg.users || []);
+            break;
+        case 'item_changed':
+            if (msg.item_id === presence.editingId) showItemConflict(msg.event);
+            break;
+        case 'error':
+            // канал закрывается сервером: токен истёк или отозван
+            if (msg.error === 'token expired' || msg.error === 'token revoked') {
+                presence.renew = true;
+                break;
+            }
+            showToast('Presence: ' + msg.error, 'error');
+            break;
+    }
+}
+
+function renderPresence(users) {
+    const others = users.filter(u => u.user_id !== state.user.id);
+    const el = $('#itemPresence');
+    if (!others.length) {
+        el.style.display = 'none';
+        return;
+    }
+    el.innerHTML = 'Also open by ' + others.map(u =>
+        `<strong>${escHtml(u.username)}</strong>${u.editing ? ' (editing)' : ''}`
+    ).join(', ');
+    el.style.display = '';
+}
+
+// своё сохранение предупреждения не вызывает: форма к этому времени уже закрывается
+function showItemConflict(ev) {
+    if (ev.changed_by && ev.changed_by === state.user.id) return;
+
+    const who = ev.username ? ` by <strong>${escHtml(ev.username)}</strong>` : '';
+    const el = $('#itemConflict');
+    if (ev.action === 'DELETE') {
+        el.innerHTML = `This item was deleted${who}.`;
+        $('#itemModalSave').disabled = true;
+    } else {
+        el.innerHTML = `This item was changed${who} while you were editing it. ` +
+            '<button class="btn btn-outline btn-sm" id="itemConflictReload">Load latest</button>';
+        $('#itemConflictReload').addEventListener('click', reloadEditedItem);
+    }
+    el.style.display = '';
+}
+
 /* ═══════════════════════════════════════════════════════════════════════
    Tab Navigation
    ═══════════════════════════════════════════════════════════════════════ */
@@ -509,7 +963,10 @@ $$('.tab-btn').forEach(btn => {
         const panelId = 'panel' + tab.charAt(0).toUpperCase() + tab.slice(1);
         $(`#${panelId}`).classList.add('active');
 
-        if (tab === 'history') loadAudit(1);
+        if (tab === 'history') {
+            loadAudit(1);
+            loadAuditStats();
+        }
         if (tab === 'items') loadItems(state.currentPage);
     });
 });
@@ -521,13 +978,15 @@ $$('.tab-btn').forEach(btn => {
 // Login
 $('#loginForm').addEventListener('submit', e => {
     e.preventDefault();
-    const username = $('#userSelect').value;
+    const username = $('#usernameInput').value.trim();
     const password = $('#passwordInput').value;
     if (username) login(username, password);
 });
 
+$('#ssoBtn').addEventListener('click', startSSO);
+
 // Logout
-$('#logoutBtn').addEventListener('click', logout);
+$('#logoutBtn').addEventListener('click', signOut);
 
 // Add Item
 $('#addItemBtn').addEventListener('click', openAddModal);
@@ -562,12 +1021,18 @@ $('#searchInput').addEventListener('keydown', e => {
 });
 
 // Audit filters
-$('#applyFiltersBtn').addEventListener('click', () => loadAudit(1));
+$('#applyFiltersBtn').addEventListener('click', () => {
+    loadAudit(1);
+    loadAuditStats();
+});
 $('#clearFiltersBtn').addEventListener('click', () => {
+    $('#filterEntity').value = '';
     $('#filterAction').value = '';
+    $('#filterField').value = '';
     $('#filterDateFrom').value = '';
     $('#filterDateTo').value = '';
     loadAudit(1);
+    loadAuditStats();
 });
 $('#exportCsvBtn').addEventListener('click', exportCsv);
 
@@ -583,9 +1048,11 @@ document.addEventListener('keydown', e => {
    Init
    ═══════════════════════════════════════════════════════════════════════ */
 (function init() {
-    if (state.token && state.user) {
+    const params = new URLSearchParams(window.location.search);
+    if (params.has('state')) {
+        completeSSO(params);
+    } else if (state.token && state.user) {
         enterApp();
-    } else {
-        loadUsers();
     }
+    initSSO();
 })();
\ No newline at end of file